@@ -0,0 +1,178 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+	"time"
+
+	apimw "github.com/wepala/weos/v3/api/middleware"
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+	"github.com/labstack/echo/v4"
+)
+
+// auditExportPage is how many entries a CSV export reads per query.
+const auditExportPage = 500
+
+// AuditHandler lists the audit log of the caller's active account. It is
+// admin-only.
+type AuditHandler struct {
+	audit       repositories.AuditRepository
+	accountRepo authrepos.AccountRepository
+	logger      entities.Logger
+}
+
+func NewAuditHandler(
+	audit repositories.AuditRepository, accountRepo authrepos.AccountRepository, logger entities.Logger,
+) *AuditHandler {
+	return &AuditHandler{audit: audit, accountRepo: accountRepo, logger: logger}
+}
+
+// AuditEntryResponse is one audit log entry.
+type AuditEntryResponse struct {
+	EventID     string `json:"event_id"`
+	EventType   string `json:"event_type"`
+	ResourceID  string `json:"resource_id"`
+	TypeSlug    string `json:"type_slug,omitempty"`
+	AgentID     string `json:"agent_id,omitempty"`
+	RealAgentID string `json:"real_agent_id,omitempty"`
+	AccountID   string `json:"account_id,omitempty"`
+	AuthMethod  string `json:"auth_method,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
+	OccurredAt  string `json:"occurred_at"`
+}
+
+var auditCSVHeader = []string{
+	"occurred_at", "event_id", "event_type", "resource_id", "type_slug",
+	"agent_id", "real_agent_id", "account_id", "auth_method", "request_id",
+}
+
+// List returns the audit log, newest first, filtered by the agent,
+// resource, type, event, from and to query parameters (from and to are
+// RFC 3339 times). format=csv downloads every matching entry as CSV.
+func (h *AuditHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	isAdmin, err := apimw.IsAdmin(ctx, h.accountRepo)
+	if err != nil {
+		h.logger.Error(ctx, "failed to check admin status", "error", err)
+		return respondError(c, http.StatusInternalServerError, "authorization check failed")
+	}
+	if !isAdmin {
+		return respondError(c, http.StatusForbidden, "admin role required")
+	}
+	ident := auth.AgentFromCtx(ctx)
+	if ident == nil || ident.ActiveAccountID == "" {
+		return respondError(c, http.StatusForbidden, "the audit log requires an active account")
+	}
+
+	filter := entities.AuditFilter{
+		AccountID:  ident.ActiveAccountID,
+		AgentID:    c.QueryParam("agent"),
+		ResourceID: c.QueryParam("resource"),
+		TypeSlug:   c.QueryParam("type"),
+		EventType:  c.QueryParam("event"),
+	}
+	for param, t := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.QueryParam(param); v != "" {
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return respondError(c, http.StatusBadRequest, param+" must be an RFC 3339 time")
+			}
+		}
+	}
+
+	if c.QueryParam("format") == "csv" {
+		return h.exportCSV(c, filter)
+	}
+	cursor, limit := pageParams(c)
+	page, err := h.audit.List(ctx, filter, cursor, limit)
+	if err != nil {
+		h.logger.Error(ctx, "failed to list audit log", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to list audit log")
+	}
+	items := make([]AuditEntryResponse, 0, len(page.Data))
+	for _, e := range page.Data {
+		items = append(items, auditEntryResponse(e))
+	}
+	return respondPaginated(c, http.StatusOK, items, page.Cursor, page.HasMore)
+}
+
+// exportCSV streams every entry matching filter. Once the header is sent a
+// failure can only end the download early, so it is logged.
+func (h *AuditHandler) exportCSV(c echo.Context, filter entities.AuditFilter) error {
+	ctx := c.Request().Context()
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="audit.csv"`)
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+	_ = w.Write(auditCSVHeader)
+	for cursor := ""; ; {
+		page, err := h.audit.List(ctx, filter, cursor, auditExportPage)
+		if err != nil {
+			h.logger.Error(ctx, "audit export failed", "error", err)
+			break
+		}
+		for _, e := range page.Data {
+			r := auditEntryResponse(e)
+			record := []string{
+				r.OccurredAt, r.EventID, r.EventType, r.ResourceID, r.TypeSlug,
+				r.AgentID, r.RealAgentID, r.AccountID, r.AuthMethod, r.RequestID,
+			}
+			for i, cell := range record {
+				record[i] = csvCell(cell)
+			}
+			_ = w.Write(record)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil || !page.HasMore {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return nil
+}
+
+// csvCell keeps a spreadsheet from running a cell as a formula: a value
+// starting with '=', '+', '-', '@', a tab or a carriage return gets a
+// leading "'".
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func auditEntryResponse(e *entities.AuditEntry) AuditEntryResponse {
+	return AuditEntryResponse{
+		EventID:     e.EventID,
+		EventType:   e.EventType,
+		ResourceID:  e.ResourceID,
+		TypeSlug:    e.TypeSlug,
+		AgentID:     e.AgentID,
+		RealAgentID: e.RealAgentID,
+		AccountID:   e.AccountID,
+		AuthMethod:  e.AuthMethod,
+		RequestID:   e.RequestID,
+		OccurredAt:  e.OccurredAt.UTC().Format(time.RFC3339Nano),
+	}
+}
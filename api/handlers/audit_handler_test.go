@@ -0,0 +1,26 @@
+package handlers_test
+
+import (
+	"testing"
+
+	"github.com/wepala/weos/v3/api/handlers"
+)
+
+func TestCSVCell_DefusesFormulas(t *testing.T) {
+	t.Parallel()
+	for value, want := range map[string]string{
+		`=HYPERLINK("https://evil.example","x")`: `'=HYPERLINK("https://evil.example","x")`,
+		"+1":                                     "'+1",
+		"-1":                                     "'-1",
+		"@SUM(A1)":                               "'@SUM(A1)",
+		"\tx":                                    "'\tx",
+		"\rx":                                    "'\rx",
+		"Resource.Created":                       "Resource.Created",
+		"urn:project:a=b":                        "urn:project:a=b",
+		"":                                       "",
+	} {
+		if got := handlers.ExportCSVCell(value); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuthzHandler explains authorization decisions without taking the
+// action checked.
+type AuthzHandler struct {
+	service application.AuthorizationCheckService
+	logger  entities.Logger
+}
+
+func NewAuthzHandler(service application.AuthorizationCheckService, logger entities.Logger) *AuthzHandler {
+	return &AuthzHandler{service: service, logger: logger}
+}
+
+type authzCheckRequest struct {
+	AgentID    string `json:"agent_id"`
+	Action     string `json:"action"`
+	TypeSlug   string `json:"type_slug"`
+	ResourceID string `json:"resource_id"`
+}
+
+// AuthzCheckResponse is the decision and the checks that led to it.
+type AuthzCheckResponse struct {
+	Allowed   bool                            `json:"allowed"`
+	DecidedBy string                          `json:"decided_by,omitempty"`
+	Trace     []application.AuthorizationStep `json:"trace"`
+}
+
+// Check decides whether an agent, the caller when agent_id is omitted,
+// may take an action on a resource type or one resource of it.
+func (h *AuthzHandler) Check(c echo.Context) error {
+	var req authzCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return respondError(c, http.StatusBadRequest, "invalid request body")
+	}
+	explanation, err := h.service.Check(c.Request().Context(), application.CheckAuthorizationCommand{
+		AgentID:    req.AgentID,
+		Action:     req.Action,
+		TypeSlug:   req.TypeSlug,
+		ResourceID: req.ResourceID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrAccessDenied):
+			return respondError(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, repositories.ErrNotFound):
+			return respondError(c, http.StatusNotFound, "resource not found")
+		case errors.Is(err, application.ErrValidation):
+			return respondValidationError(c, err)
+		}
+		h.logger.Error(c.Request().Context(), "authorization check failed", "error", err)
+		return respondError(c, http.StatusInternalServerError, err.Error())
+	}
+	trace := explanation.Trace
+	if trace == nil {
+		trace = []application.AuthorizationStep{}
+	}
+	return respond(c, http.StatusOK, AuthzCheckResponse{
+		Allowed:   explanation.Allowed,
+		DecidedBy: explanation.DecidedBy,
+		Trace:     trace,
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EditorialHandler serves publish/unpublish for x-editorial resource types
+// and the unauthenticated read API over their published revisions.
+type EditorialHandler struct {
+	editorialService    application.EditorialService
+	resourceTypeService application.ResourceTypeService
+}
+
+func NewEditorialHandler(
+	editorialSvc application.EditorialService, typeSvc application.ResourceTypeService,
+) *EditorialHandler {
+	return &EditorialHandler{editorialService: editorialSvc, resourceTypeService: typeSvc}
+}
+
+// PublishedResponse is one published revision. Data is the simplified
+// resource data as it was when published.
+type PublishedResponse struct {
+	ID          string          `json:"id"`
+	TypeSlug    string          `json:"type_slug"`
+	Data        json.RawMessage `json:"data"`
+	PublishedAt string          `json:"published_at"`
+}
+
+func (h *EditorialHandler) Publish(c echo.Context) error {
+	return h.move(c, h.editorialService.Publish)
+}
+
+func (h *EditorialHandler) Unpublish(c echo.Context) error {
+	return h.move(c, h.editorialService.Unpublish)
+}
+
+func (h *EditorialHandler) move(
+	c echo.Context, fn func(ctx context.Context, id string) (*entities.Resource, error),
+) error {
+	typeSlug := c.Param("typeSlug")
+	if _, err := h.resourceTypeService.GetBySlug(c.Request().Context(), typeSlug); err != nil {
+		return respondError(c, http.StatusNotFound, "resource type not found")
+	}
+
+	entity, err := fn(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrAccessDenied):
+			return respondError(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, repositories.ErrNotFound):
+			return respondError(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, application.ErrValidation):
+			return respondValidationError(c, err)
+		case errors.Is(err, application.ErrConflict):
+			return respondError(c, http.StatusConflict, err.Error())
+		}
+		return respondError(c, http.StatusInternalServerError, err.Error())
+	}
+	return respondWithResource(c, http.StatusOK, entity)
+}
+
+func (h *EditorialHandler) GetPublished(c echo.Context) error {
+	typeSlug := c.Param("typeSlug")
+	rt, err := h.resourceTypeService.GetBySlug(c.Request().Context(), typeSlug)
+	if err != nil {
+		return respondError(c, http.StatusNotFound, "resource type not found")
+	}
+
+	rev, err := h.editorialService.GetPublished(
+		c.Request().Context(), c.Param("accountId"), typeSlug, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			return respondError(c, http.StatusNotFound, "resource not found")
+		case errors.Is(err, application.ErrValidation):
+			return respondValidationError(c, err)
+		}
+		return respondError(c, http.StatusInternalServerError, err.Error())
+	}
+	return respond(c, http.StatusOK, publishedResponse(rev, rt.Context()))
+}
+
+func (h *EditorialHandler) ListPublished(c echo.Context) error {
+	typeSlug := c.Param("typeSlug")
+	rt, err := h.resourceTypeService.GetBySlug(c.Request().Context(), typeSlug)
+	if err != nil {
+		return respondError(c, http.StatusNotFound, "resource type not found")
+	}
+
+	cursor := c.QueryParam("cursor")
+	limit, _ := strconv.Atoi(c.QueryParam("limit")) //nolint:errcheck // defaults to 0, handled below
+	if limit <= 0 {
+		limit = 20
+	}
+	result, err := h.editorialService.ListPublished(
+		c.Request().Context(), c.Param("accountId"), typeSlug, cursor, limit)
+	if err != nil {
+		if errors.Is(err, application.ErrValidation) {
+			return respondValidationError(c, err)
+		}
+		return respondError(c, http.StatusInternalServerError, err.Error())
+	}
+	items := make([]PublishedResponse, 0, len(result.Data))
+	for _, rev := range result.Data {
+		items = append(items, publishedResponse(rev, rt.Context()))
+	}
+	return respondPaginated(c, http.StatusOK, items, result.Cursor, result.HasMore)
+}
+
+func publishedResponse(rev *entities.PublishedRevision, ldCtx json.RawMessage) PublishedResponse {
+	data := rev.Data
+	if simplified, err := entities.SimplifyJSONLD(rev.Data, ldCtx); err == nil {
+		data = simplified
+	}
+	return PublishedResponse{
+		ID:          rev.ResourceID,
+		TypeSlug:    rev.TypeSlug,
+		Data:        data,
+		PublishedAt: rev.PublishedAt.Format(time.RFC3339),
+	}
+}
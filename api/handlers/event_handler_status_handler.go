@@ -0,0 +1,94 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	apimw "github.com/wepala/weos/v3/api/middleware"
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+	"github.com/labstack/echo/v4"
+)
+
+// EventHandlerStatusHandler reports how far each event handler group has got
+// through the event log. It is admin-only.
+type EventHandlerStatusHandler struct {
+	relay       *application.EventRelay
+	accountRepo authrepos.AccountRepository
+	logger      entities.Logger
+}
+
+func NewEventHandlerStatusHandler(
+	relay *application.EventRelay, accountRepo authrepos.AccountRepository, logger entities.Logger,
+) *EventHandlerStatusHandler {
+	return &EventHandlerStatusHandler{relay: relay, accountRepo: accountRepo, logger: logger}
+}
+
+// EventHandlerStatusResponse is one event handler group.
+type EventHandlerStatusResponse struct {
+	Group     string `json:"group"`
+	Async     bool   `json:"async"`
+	Position  int64  `json:"position"`
+	Lag       int64  `json:"lag"`
+	Attempts  int    `json:"attempts"`
+	RetryAt   string `json:"retry_at,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+	Skipped   int    `json:"skipped"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+func (h *EventHandlerStatusHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	isAdmin, err := apimw.IsAdmin(ctx, h.accountRepo)
+	if err != nil {
+		h.logger.Error(ctx, "failed to check admin status", "error", err)
+		return respondError(c, http.StatusInternalServerError, "authorization check failed")
+	}
+	if !isAdmin {
+		return respondError(c, http.StatusForbidden, "admin role required")
+	}
+
+	statuses, err := h.relay.Status(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "failed to read event handler status", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to read event handler status")
+	}
+	resp := make([]EventHandlerStatusResponse, len(statuses))
+	for i, s := range statuses {
+		resp[i] = EventHandlerStatusResponse{
+			Group:     s.Group,
+			Async:     s.Async,
+			Position:  s.Position,
+			Lag:       s.Lag,
+			Attempts:  s.Attempts,
+			LastError: s.LastError,
+			Skipped:   s.Skipped,
+			RetryAt:   formatOptionalTime(s.RetryAt),
+			UpdatedAt: formatOptionalTime(s.UpdatedAt),
+		}
+	}
+	return respond(c, http.StatusOK, resp)
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
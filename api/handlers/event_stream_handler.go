@@ -0,0 +1,161 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+
+	"github.com/labstack/echo/v4"
+)
+
+const eventStreamBatch = 200
+
+// EventStreamHandler serves resource changes as Server-Sent Events. Each
+// event's SSE id is its event log position, so a client that reconnects with
+// Last-Event-ID picks up where it left off.
+type EventStreamHandler struct {
+	service application.EventStreamService
+	logger  entities.Logger
+	// PollInterval is how often the event log is checked for new events.
+	PollInterval time.Duration
+	// HeartbeatInterval is how long the stream may stay silent before a
+	// comment is sent, so proxies keep the connection open.
+	HeartbeatInterval time.Duration
+}
+
+func NewEventStreamHandler(service application.EventStreamService, logger entities.Logger) *EventStreamHandler {
+	return &EventStreamHandler{
+		service:           service,
+		logger:            logger,
+		PollInterval:      time.Second,
+		HeartbeatInterval: 15 * time.Second,
+	}
+}
+
+// Stream handles GET /api/events/stream. The type and resource query
+// parameters, repeated or comma-separated, narrow the stream to resources of
+// those type slugs or with those IDs. Without Last-Event-ID (or the
+// lastEventId query parameter) the stream starts with the next event.
+func (h *EventStreamHandler) Stream(c echo.Context) error {
+	ctx := c.Request().Context()
+	filter := application.EventStreamFilter{
+		TypeSlugs:   listParam(c, "type"),
+		ResourceIDs: listParam(c, "resource"),
+	}
+	position, err := h.startPosition(c)
+	if errors.Is(err, errBadLastEventID) {
+		return respondError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		h.logger.Error(ctx, "failed to read event log head", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to open event stream")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(res, "retry: 3000\n\n"); err != nil {
+		return nil
+	}
+	res.Flush()
+
+	poll := time.NewTicker(h.PollInterval)
+	defer poll.Stop()
+	lastWrite := time.Now()
+	for {
+		for {
+			events, next, err := h.service.After(ctx, position, filter, eventStreamBatch)
+			if err != nil {
+				if ctx.Err() == nil {
+					h.logger.Error(ctx, "event stream failed", "position", position, "error", err)
+				}
+				// The client reconnects and resumes with Last-Event-ID.
+				return nil
+			}
+			for _, e := range events {
+				data, err := json.Marshal(e)
+				if err != nil {
+					return nil
+				}
+				if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", e.Position, e.Event, data); err != nil {
+					return nil
+				}
+			}
+			if len(events) > 0 {
+				res.Flush()
+				lastWrite = time.Now()
+			}
+			if next == position {
+				break
+			}
+			position = next
+		}
+		if time.Since(lastWrite) >= h.HeartbeatInterval {
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+			lastWrite = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-poll.C:
+		}
+	}
+}
+
+var errBadLastEventID = errors.New("invalid Last-Event-ID: want an event log position")
+
+// startPosition returns the event log position the stream resumes after.
+func (h *EventStreamHandler) startPosition(c echo.Context) (int64, error) {
+	last := c.Request().Header.Get("Last-Event-ID")
+	if last == "" {
+		last = c.QueryParam("lastEventId")
+	}
+	if last == "" {
+		return h.service.Head(c.Request().Context())
+	}
+	position, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || position < 0 {
+		return 0, errBadLastEventID
+	}
+	return position, nil
+}
+
+// listParam collects a query parameter given repeatedly or comma-separated.
+func listParam(c echo.Context, name string) []string {
+	var out []string
+	for _, v := range c.QueryParams()[name] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
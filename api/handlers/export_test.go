@@ -29,3 +29,7 @@ func ExportRespondError(c echo.Context, status int, msg string) error {
 func ExportRespondForbidden(c echo.Context) error {
 	return respondForbidden(c)
 }
+
+func ExportCSVCell(value string) string {
+	return csvCell(value)
+}
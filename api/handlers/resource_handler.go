@@ -77,7 +77,10 @@ func (h *ResourceHandler) Create(c echo.Context) error {
 			return respondForbidden(c)
 		}
 		if errors.Is(err, application.ErrValidation) {
-			return respondError(c, http.StatusBadRequest, err.Error())
+			return respondValidationError(c, err)
+		}
+		if errors.Is(err, application.ErrConflict) {
+			return respondError(c, http.StatusConflict, err.Error())
 		}
 		return respondError(c, http.StatusInternalServerError, err.Error())
 	}
@@ -183,6 +186,9 @@ func (h *ResourceHandler) List(c echo.Context) error {
 		result, err = h.resourceService.List(c.Request().Context(), typeSlug, cursor, limit, sort)
 	}
 	if err != nil {
+		if errors.Is(err, application.ErrValidation) {
+			return respondValidationError(c, err)
+		}
 		return respondError(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -207,6 +213,9 @@ func (h *ResourceHandler) listFlat(
 		result, err = h.resourceService.ListFlat(
 			c.Request().Context(), typeSlug, cursor, limit, sort)
 	}
+	if errors.Is(err, application.ErrValidation) {
+		return respondValidationError(c, err)
+	}
 	if err != nil {
 		// Fall back to entity-based list if no projection table exists.
 		return h.listEntities(c, typeSlug, filters, cursor, limit, sort)
@@ -229,6 +238,9 @@ func (h *ResourceHandler) listEntities(
 		result, err = h.resourceService.List(c.Request().Context(), typeSlug, cursor, limit, sort)
 	}
 	if err != nil {
+		if errors.Is(err, application.ErrValidation) {
+			return respondValidationError(c, err)
+		}
 		return respondError(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -248,6 +260,21 @@ func (h *ResourceHandler) listEntities(
 	return respondPaginated(c, http.StatusOK, items, result.Cursor, result.HasMore)
 }
 
+// respondValidationError sends a 400 for a validation failure. x-rules
+// violations are also added to the messages array, one per violation with
+// its field, so forms can show each next to the input it concerns.
+func respondValidationError(c echo.Context, err error) error {
+	var rules *application.RuleViolationError
+	if errors.As(err, &rules) {
+		for _, v := range rules.Violations {
+			entities.AddMessage(c.Request().Context(), entities.Message{
+				Type: "error", Text: v.Message, Field: v.Path, Code: "rule_violation",
+			})
+		}
+	}
+	return respondError(c, http.StatusBadRequest, err.Error())
+}
+
 // parseFilters extracts _filter[field][operator]=value query params.
 func parseFilters(c echo.Context) []repositories.FilterCondition {
 	var filters []repositories.FilterCondition
@@ -292,7 +319,39 @@ func (h *ResourceHandler) Update(c echo.Context) error {
 			return respondForbidden(c)
 		}
 		if errors.Is(err, application.ErrValidation) {
-			return respondError(c, http.StatusBadRequest, err.Error())
+			return respondValidationError(c, err)
+		}
+		if errors.Is(err, application.ErrConflict) {
+			return respondError(c, http.StatusConflict, err.Error())
+		}
+		return respondError(c, http.StatusInternalServerError, err.Error())
+	}
+	return respondWithResource(c, http.StatusOK, entity)
+}
+
+// Transition triggers the named x-workflow transition on a resource. Illegal
+// transitions and failed guards are 400s; a caller whose role the transition
+// doesn't allow gets a 403 naming the roles it needs.
+func (h *ResourceHandler) Transition(c echo.Context) error {
+	typeSlug := c.Param("typeSlug")
+	if _, err := h.resourceTypeService.GetBySlug(c.Request().Context(), typeSlug); err != nil {
+		return respondError(c, http.StatusNotFound, "resource type not found")
+	}
+
+	entity, err := h.resourceService.Transition(
+		c.Request().Context(),
+		application.TransitionResourceCommand{ID: c.Param("id"), Name: c.Param("name")},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrAccessDenied):
+			return respondError(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, repositories.ErrNotFound):
+			return respondError(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, application.ErrValidation):
+			return respondValidationError(c, err)
+		case errors.Is(err, application.ErrConflict):
+			return respondError(c, http.StatusConflict, err.Error())
 		}
 		return respondError(c, http.StatusInternalServerError, err.Error())
 	}
@@ -310,6 +369,9 @@ func (h *ResourceHandler) Delete(c echo.Context) error {
 		if errors.Is(err, entities.ErrAccessDenied) {
 			return respondForbidden(c)
 		}
+		if errors.Is(err, application.ErrConflict) {
+			return respondError(c, http.StatusConflict, err.Error())
+		}
 		return respondError(c, http.StatusInternalServerError, err.Error())
 	}
 	// 204 No Content intentionally has no body; any accumulated messages are not sent.
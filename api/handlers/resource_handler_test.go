@@ -54,6 +54,8 @@ type stubResourceSvc struct {
 
 	updateEntity *entities.Resource
 	updateErr    error
+
+	transitionErr error
 }
 
 func (s *stubResourceSvc) GetFlat(_ context.Context, _, _ string) (map[string]any, error) {
@@ -74,6 +76,12 @@ func (s *stubResourceSvc) Update(_ context.Context, _ application.UpdateResource
 	return s.updateEntity, s.updateErr
 }
 
+func (s *stubResourceSvc) Transition(
+	_ context.Context, _ application.TransitionResourceCommand,
+) (*entities.Resource, error) {
+	return s.updateEntity, s.transitionErr
+}
+
 // stubTypeSvc returns a fixed resource type from GetBySlug and panics on
 // anything else. Only the type-existence check is exercised by Get.
 type stubTypeSvc struct {
@@ -477,3 +485,98 @@ func TestResourceHandler_Update_ValidationErrorReturns400(t *testing.T) {
 		t.Fatalf("body = %q, want underlying validation message preserved", rec.Body.String())
 	}
 }
+
+// TestResourceHandler_Create_ConflictReturns409 verifies that an x-unique
+// violation surfaces as 409 with the service's message intact.
+func TestResourceHandler_Create_ConflictReturns409(t *testing.T) {
+	svc := &stubResourceSvc{
+		createErr: fmt.Errorf("sku %q is already in use: %w", "ABC-1", application.ErrConflict),
+	}
+	h := newHandler(t, svc)
+
+	c, rec := newPostRequest(t, `{"sku":"ABC-1"}`)
+	if err := h.Create(c); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("code = %d, want 409", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "sku") {
+		t.Fatalf("body = %q, want conflict message preserved", rec.Body.String())
+	}
+}
+
+func TestResourceHandler_Update_ConflictReturns409(t *testing.T) {
+	svc := &stubResourceSvc{
+		updateErr: fmt.Errorf("sku %q is already in use: %w", "ABC-1", application.ErrConflict),
+	}
+	h := newHandler(t, svc)
+
+	c, rec := newPutRequest(t, `{"sku":"ABC-1"}`)
+	if err := h.Update(c); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("code = %d, want 409", rec.Code)
+	}
+}
+
+// TestResourceHandler_Create_RuleViolationsReturn400WithFields verifies that
+// every x-rules violation is reported, each as a message tied to its field.
+func TestResourceHandler_Create_RuleViolationsReturn400WithFields(t *testing.T) {
+	svc := &stubResourceSvc{
+		createErr: &application.RuleViolationError{Violations: []application.RuleViolation{
+			{Path: "endDate", Message: "must be after startDate"},
+			{Path: "price", Message: "is required when in stock"},
+		}},
+	}
+	h := newHandler(t, svc)
+
+	c, rec := newPostRequest(t, `{}`)
+	c.SetRequest(c.Request().WithContext(entities.ContextWithMessages(c.Request().Context())))
+	if err := h.Create(c); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("code = %d, want 400", rec.Code)
+	}
+	var body handlers.ErrorEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if len(body.Messages) != 2 {
+		t.Fatalf("messages = %+v, want one per violation", body.Messages)
+	}
+	if body.Messages[0].Field != "endDate" || body.Messages[1].Field != "price" {
+		t.Errorf("message fields = %q, %q; want endDate, price", body.Messages[0].Field, body.Messages[1].Field)
+	}
+	if !strings.Contains(body.Error, "endDate: must be after startDate") {
+		t.Errorf("error = %q, want every violation listed", body.Error)
+	}
+}
+
+func TestResourceHandler_Transition_ErrorStatusCodes(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"role not allowed", fmt.Errorf("transition %q requires role admin: %w", "finish", entities.ErrAccessDenied), http.StatusForbidden},
+		{"unknown transition", fmt.Errorf("no transition: %w", repositories.ErrNotFound), http.StatusNotFound},
+		{"illegal from current state", fmt.Errorf("not allowed: %w", application.ErrValidation), http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHandler(t, &stubResourceSvc{transitionErr: tc.err})
+			c, rec := newPostRequest(t, "")
+			c.SetParamNames("typeSlug", "id", "name")
+			c.SetParamValues("course", "urn:course:1", "finish")
+			if err := h.Transition(c); err != nil {
+				t.Fatalf("Transition returned error: %v", err)
+			}
+			if rec.Code != tc.want {
+				t.Errorf("code = %d, want %d", rec.Code, tc.want)
+			}
+		})
+	}
+}
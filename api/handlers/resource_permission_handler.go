@@ -23,12 +23,14 @@ func NewResourcePermissionHandler(svc application.ResourcePermissionService) *Re
 
 type grantRequest struct {
 	AgentID string   `json:"agent_id"`
+	TeamID  string   `json:"team_id"`
 	Actions []string `json:"actions"`
 }
 
 type permissionResponse struct {
 	ResourceID string   `json:"resource_id"`
 	AgentID    string   `json:"agent_id"`
+	TeamID     string   `json:"team_id,omitempty"`
 	Actions    []string `json:"actions"`
 	GrantedBy  string   `json:"granted_by"`
 	GrantedAt  string   `json:"granted_at"`
@@ -46,8 +48,8 @@ func (h *ResourcePermissionHandler) Grant(c echo.Context) error {
 	if err := json.Unmarshal(body, &req); err != nil {
 		return respondError(c, http.StatusBadRequest, "invalid JSON")
 	}
-	if req.AgentID == "" || len(req.Actions) == 0 {
-		return respondError(c, http.StatusBadRequest, "agent_id and actions are required")
+	if (req.AgentID == "") == (req.TeamID == "") || len(req.Actions) == 0 {
+		return respondError(c, http.StatusBadRequest, "actions and exactly one of agent_id and team_id are required")
 	}
 
 	actionsJSON, err := json.Marshal(req.Actions)
@@ -57,6 +59,7 @@ func (h *ResourcePermissionHandler) Grant(c echo.Context) error {
 	cmd := application.GrantPermissionCommand{
 		ResourceID: resourceID,
 		AgentID:    req.AgentID,
+		TeamID:     req.TeamID,
 		Actions:    actionsJSON,
 	}
 
@@ -64,6 +67,9 @@ func (h *ResourcePermissionHandler) Grant(c echo.Context) error {
 		if errors.Is(err, entities.ErrAccessDenied) {
 			return respondForbidden(c)
 		}
+		if errors.Is(err, application.ErrValidation) {
+			return respondValidationError(c, err)
+		}
 		return respondError(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -89,6 +95,23 @@ func (h *ResourcePermissionHandler) Revoke(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// RevokeTeam revokes the grant made to a team.
+func (h *ResourcePermissionHandler) RevokeTeam(c echo.Context) error {
+	cmd := application.RevokePermissionCommand{
+		ResourceID: c.Param("id"),
+		TeamID:     c.Param("teamId"),
+	}
+
+	if err := h.permService.Revoke(c.Request().Context(), cmd); err != nil {
+		if errors.Is(err, entities.ErrAccessDenied) {
+			return respondForbidden(c)
+		}
+		return respondError(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (h *ResourcePermissionHandler) List(c echo.Context) error {
 	resourceID := c.Param("id")
 
@@ -105,6 +128,7 @@ func (h *ResourcePermissionHandler) List(c echo.Context) error {
 		items = append(items, permissionResponse{
 			ResourceID: p.ResourceID,
 			AgentID:    p.AgentID,
+			TeamID:     p.TeamID,
 			Actions:    p.Actions,
 			GrantedBy:  p.GrantedBy,
 			GrantedAt:  p.GrantedAt.Format(time.RFC3339),
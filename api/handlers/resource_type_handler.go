@@ -30,24 +30,23 @@ import (
 	"github.com/akeemphilbert/pericarp/pkg/auth"
 	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
 	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
-	authcasbin "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/casbin"
 	"github.com/labstack/echo/v4"
 )
 
 type ResourceTypeHandler struct {
 	service     application.ResourceTypeService
-	checker     *authcasbin.CasbinAuthorizationChecker
+	roles       application.RoleAuthorizer
 	accountRepo authrepos.AccountRepository
 	logger      entities.Logger
 }
 
 func NewResourceTypeHandler(
 	service application.ResourceTypeService,
-	checker *authcasbin.CasbinAuthorizationChecker,
+	roles application.RoleAuthorizer,
 	accountRepo authrepos.AccountRepository,
 	logger entities.Logger,
 ) *ResourceTypeHandler {
-	return &ResourceTypeHandler{service: service, checker: checker, accountRepo: accountRepo, logger: logger}
+	return &ResourceTypeHandler{service: service, roles: roles, accountRepo: accountRepo, logger: logger}
 }
 
 type CreateResourceTypeRequest struct {
@@ -118,39 +117,27 @@ func (h *ResourceTypeHandler) List(c echo.Context) error {
 	includeAll := c.QueryParam("includeAll") == "true"
 	items := make([]ResourceTypeResponse, 0, len(result.Data))
 
-	// Filter resource types by read permission when Casbin is configured.
-	identity := auth.AgentFromCtx(c.Request().Context())
-	role, roleErr := apimw.GetUserRole(c.Request().Context(), h.accountRepo)
+	// Filter resource types by read permission in the active account. Roles
+	// with no policies configured there see every type.
+	ctx := c.Request().Context()
+	identity := auth.AgentFromCtx(ctx)
+	role, roleErr := apimw.GetUserRole(ctx, h.accountRepo)
 	if roleErr != nil {
-		h.logger.Warn(c.Request().Context(), "failed to get user role for filtering, showing all types", "error", roleErr)
-	}
-	skipFilter := role == authentities.RoleAdmin || role == authentities.RoleOwner || role == ""
-
-	var filterByPerms bool
-	if !skipFilter && identity != nil {
-		perms, _ := h.checker.GetPermissions(c.Request().Context(), role)
-		filterByPerms = len(perms) > 0
+		h.logger.Warn(ctx, "failed to get user role for filtering, showing all types", "error", roleErr)
 	}
+	filter := identity != nil && h.roles != nil &&
+		role != authentities.RoleAdmin && role != authentities.RoleOwner && role != ""
 
 	for _, e := range result.Data {
 		if !includeAll && (jsonld.IsValueObject(e.Context()) || jsonld.IsAbstract(e.Context())) {
 			continue
 		}
-		if filterByPerms {
-			var allowed bool
-			if identity.ActiveAccountID != "" {
-				allowed, _ = h.checker.IsAuthorizedInAccount(
-					c.Request().Context(),
-					identity.AgentID, identity.ActiveAccountID,
-					authentities.ActionRead, e.Slug(),
-				)
-			} else {
-				allowed, _ = h.checker.IsAuthorized(
-					c.Request().Context(),
-					identity.AgentID, authentities.ActionRead, e.Slug(),
-				)
-			}
-			if !allowed {
+		if filter {
+			decision, err := h.roles.Authorize(ctx, application.AuthorizationRequest{
+				AgentID: identity.AgentID, AccountID: identity.ActiveAccountID, MemberRole: role,
+				Action: authentities.ActionRead, TypeSlug: e.Slug(),
+			})
+			if err != nil || !(decision.Allowed || decision.Unconfigured) {
 				continue
 			}
 		}
@@ -35,6 +35,7 @@ type RoleAccessHandler struct {
 	repo        repositories.RoleResourceAccessRepository
 	checker     *authcasbin.CasbinAuthorizationChecker
 	accountRepo authrepos.AccountRepository
+	assignments repositories.RoleAssignmentRepository
 	logger      entities.Logger
 }
 
@@ -42,6 +43,9 @@ type RoleAccessHandlerConfig struct {
 	Repo        repositories.RoleResourceAccessRepository
 	Checker     *authcasbin.CasbinAuthorizationChecker
 	AccountRepo authrepos.AccountRepository
+	// Assignments identifies super admins, who alone may edit the global
+	// default. Without it the default cannot be edited over the API.
+	Assignments repositories.RoleAssignmentRepository
 	Logger      entities.Logger
 }
 
@@ -50,85 +54,247 @@ func NewRoleAccessHandler(cfg RoleAccessHandlerConfig) *RoleAccessHandler {
 		repo:        cfg.Repo,
 		checker:     cfg.Checker,
 		accountRepo: cfg.AccountRepo,
+		assignments: cfg.Assignments,
 		logger:      cfg.Logger,
 	}
 }
 
+// roleAccessScopeDefault is the ?scope= value that selects the global
+// default instead of the active account's configuration.
+const roleAccessScopeDefault = "default"
+
 type roleAccessResponse struct {
-	Roles entities.AccessMap `json:"roles"`
+	Roles    entities.AccessMap      `json:"roles"`
+	Fields   entities.FieldAccessMap `json:"fields"`
+	Deny     entities.AccessMap      `json:"deny"`
+	Inherits entities.RoleHierarchy  `json:"inherits"`
+	// Default reports that the account has no configuration of its own and
+	// uses the global default shown.
+	Default bool `json:"default"`
 }
 
+// roleAccessRequest replaces the active account's role access configuration.
+// Leaving Fields, Deny, or Inherits out keeps the current rules of that kind,
+// taken from the global default when the account has no configuration yet.
 type roleAccessRequest struct {
-	Roles entities.AccessMap `json:"roles"`
+	Roles    entities.AccessMap      `json:"roles"`
+	Fields   entities.FieldAccessMap `json:"fields"`
+	Deny     entities.AccessMap      `json:"deny"`
+	Inherits entities.RoleHierarchy  `json:"inherits"`
 }
 
-// Get returns the current role-resource access configuration. Admin-only.
-func (h *RoleAccessHandler) Get(c echo.Context) error {
+// targetAccount resolves the account whose configuration the request reads
+// or writes: the active account, which requires an admin of it, or with
+// ?scope=default the global default (the empty account ID), which requires a
+// super admin. When ok is false, it has already written the error response.
+func (h *RoleAccessHandler) targetAccount(c echo.Context) (accountID string, ok bool, err error) {
 	ctx := c.Request().Context()
 
+	switch c.QueryParam("scope") {
+	case "":
+	case roleAccessScopeDefault:
+		if h.assignments == nil {
+			return "", false, respondError(c, http.StatusForbidden, "super admin role required")
+		}
+		isSuperAdmin, err := apimw.IsSuperAdmin(ctx, h.assignments)
+		if err != nil {
+			h.logger.Error(ctx, "failed to check super admin status", "error", err)
+			return "", false, respondError(c, http.StatusInternalServerError, "authorization check failed")
+		}
+		if !isSuperAdmin {
+			return "", false, respondError(c, http.StatusForbidden, "super admin role required")
+		}
+		return "", true, nil
+	default:
+		return "", false, respondError(c, http.StatusBadRequest, `scope must be empty or "default"`)
+	}
+
 	isAdmin, err := apimw.IsAdmin(ctx, h.accountRepo)
 	if err != nil {
 		h.logger.Error(ctx, "failed to check admin status", "error", err)
-		return respondError(c, http.StatusInternalServerError, "authorization check failed")
+		return "", false, respondError(c, http.StatusInternalServerError, "authorization check failed")
 	}
 	if !isAdmin {
-		return respondError(c, http.StatusForbidden, "admin role required")
+		return "", false, respondError(c, http.StatusForbidden, "admin role required")
 	}
+	accountID, err = apimw.GetActiveAccountID(ctx, h.accountRepo)
+	if err != nil {
+		h.logger.Error(ctx, "failed to resolve active account", "error", err)
+		return "", false, respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
+	return accountID, true, nil
+}
 
-	accessMap, err := h.repo.GetAccessMap(ctx)
+// Get returns the active account's role-resource access configuration, or the
+// global default when it has none. Admin-only. With ?scope=default it returns
+// the global default itself, for super admins only.
+func (h *RoleAccessHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	accountID, ok, err := h.targetAccount(c)
+	if !ok {
+		return err
+	}
+	settings, err := h.repo.Get(ctx, accountID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load role access", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
+	accessMap, err := h.repo.GetAccessMap(ctx, accountID)
 	if err != nil {
 		h.logger.Error(ctx, "failed to load role access", "error", err)
 		return respondError(c, http.StatusInternalServerError, "failed to load role access")
 	}
-	return respond(c, http.StatusOK, roleAccessResponse{Roles: accessMap})
+	fieldMap, err := h.repo.GetFieldAccessMap(ctx, accountID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load field access", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
+	denyMap, err := h.repo.GetDenyMap(ctx, accountID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load deny rules", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
+	inherits, err := h.repo.GetRoleHierarchy(ctx, accountID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load role hierarchy", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
+	return respond(c, http.StatusOK, roleAccessResponse{
+		Roles: accessMap, Fields: fieldMap, Deny: denyMap, Inherits: inherits,
+		Default: settings.AccountID != accountID || accountID == "",
+	})
 }
 
-// Save updates the role-resource access configuration and syncs policies to Casbin.
+// Save updates the active account's role-resource access configuration and syncs
+// its policies to Casbin. With ?scope=default it updates the global default,
+// used by every account without a configuration of its own, for super admins
+// only.
 func (h *RoleAccessHandler) Save(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	isAdmin, err := apimw.IsAdmin(ctx, h.accountRepo)
-	if err != nil {
-		h.logger.Error(ctx, "failed to check admin status", "error", err)
-		return respondError(c, http.StatusInternalServerError, "authorization check failed")
-	}
-	if !isAdmin {
-		return respondError(c, http.StatusForbidden, "admin role required")
+	accountID, ok, err := h.targetAccount(c)
+	if !ok {
+		return err
 	}
-
 	var req roleAccessRequest
 	if err := c.Bind(&req); err != nil {
 		return respondError(c, http.StatusBadRequest, "invalid request body")
 	}
+	current, err := h.repo.Get(ctx, accountID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load role access", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
 	if req.Roles == nil {
 		req.Roles = entities.AccessMap{}
 	}
 
-	// Remove admin/owner from the map — they always have wildcard access.
+	if req.Fields == nil {
+		fields, err := h.repo.GetFieldAccessMap(ctx, accountID)
+		if err != nil {
+			h.logger.Error(ctx, "failed to load field access", "error", err)
+			return respondError(c, http.StatusInternalServerError, "failed to load role access")
+		}
+		req.Fields = fields
+	}
+	if req.Fields == nil {
+		req.Fields = entities.FieldAccessMap{}
+	}
+
+	// Load the old deny rules and inheritance, to keep when left out and to
+	// know which policies to clear.
+	oldDeny, err := h.repo.GetDenyMap(ctx, accountID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load deny rules", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
+	oldInherits, err := h.repo.GetRoleHierarchy(ctx, accountID)
+	if err != nil {
+		h.logger.Error(ctx, "failed to load role hierarchy", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load role access")
+	}
+	if req.Deny == nil {
+		req.Deny = oldDeny
+	}
+	if req.Deny == nil {
+		req.Deny = entities.AccessMap{}
+	}
+	if req.Inherits == nil {
+		req.Inherits = oldInherits
+	}
+	if req.Inherits == nil {
+		req.Inherits = entities.RoleHierarchy{}
+	}
+
+	// Remove admin/owner from the maps — they always have wildcard access.
 	delete(req.Roles, authentities.RoleAdmin)
 	delete(req.Roles, authentities.RoleOwner)
+	delete(req.Fields, authentities.RoleAdmin)
+	delete(req.Fields, authentities.RoleOwner)
+	delete(req.Deny, authentities.RoleAdmin)
+	delete(req.Deny, authentities.RoleOwner)
+	delete(req.Inherits, authentities.RoleAdmin)
+	delete(req.Inherits, authentities.RoleOwner)
+
+	if err := application.ValidateRoleDefinitions(req.Roles, req.Deny, req.Fields, req.Inherits); err != nil {
+		return respondValidationError(c, err)
+	}
 
 	// Load old access map before saving to know which roles to clear.
-	oldMap, oldMapErr := h.repo.GetAccessMap(ctx)
+	oldMap, oldMapErr := h.repo.GetAccessMap(ctx, accountID)
 	if oldMapErr != nil {
 		h.logger.Warn(ctx, "failed to load previous access map, stale policies may not be cleared", "error", oldMapErr)
 	}
+	// An account saving its first configuration leaves the global default's
+	// policies in place for the accounts still using it.
+	if current.AccountID != accountID {
+		oldMap, oldDeny, oldInherits = nil, nil, nil
+	}
 
 	accessJSON, err := json.Marshal(req.Roles)
 	if err != nil {
 		return respondError(c, http.StatusInternalServerError, "failed to encode access")
 	}
 
-	settings := &models.RoleResourceAccess{Access: string(accessJSON)}
-	if err := h.repo.Save(ctx, settings); err != nil {
+	fieldsJSON, err := json.Marshal(req.Fields)
+	if err != nil {
+		return respondError(c, http.StatusInternalServerError, "failed to encode access")
+	}
+
+	denyJSON, err := json.Marshal(req.Deny)
+	if err != nil {
+		return respondError(c, http.StatusInternalServerError, "failed to encode access")
+	}
+
+	inheritsJSON, err := json.Marshal(req.Inherits)
+	if err != nil {
+		return respondError(c, http.StatusInternalServerError, "failed to encode access")
+	}
+
+	settings := &models.RoleResourceAccess{
+		Access:   string(accessJSON),
+		Fields:   string(fieldsJSON),
+		Deny:     string(denyJSON),
+		Inherits: string(inheritsJSON),
+	}
+	if err := h.repo.Save(ctx, accountID, settings); err != nil {
 		h.logger.Error(ctx, "failed to save role access", "error", err)
 		return respondError(c, http.StatusInternalServerError, "failed to save role access")
 	}
 
 	// Sync policies to Casbin enforcer, clearing stale role policies.
-	if syncErr := application.SyncAccessMapToCasbin(h.checker, req.Roles, oldMap); syncErr != nil {
+	if syncErr := application.SyncAccessMapToCasbin(h.checker, accountID, req.Roles, oldMap); syncErr != nil {
 		h.logger.Warn(ctx, "casbin policy sync partially failed", "error", syncErr)
 	}
+	if syncErr := application.SyncDenyMapToCasbin(h.checker, accountID, req.Deny, oldDeny); syncErr != nil {
+		h.logger.Warn(ctx, "casbin deny policy sync partially failed", "error", syncErr)
+	}
+	if syncErr := application.SyncRoleHierarchyToCasbin(h.checker, accountID, req.Inherits, oldInherits); syncErr != nil {
+		h.logger.Warn(ctx, "casbin role hierarchy sync partially failed", "error", syncErr)
+	}
 
-	return respond(c, http.StatusOK, roleAccessResponse(req))
+	return respond(c, http.StatusOK, roleAccessResponse{
+		Roles: req.Roles, Fields: req.Fields, Deny: req.Deny, Inherits: req.Inherits,
+	})
 }
@@ -0,0 +1,128 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RoleAssignmentHandler manages scoped role assignments. The service
+// decides who may manage them.
+type RoleAssignmentHandler struct {
+	service application.RoleAssignmentService
+	logger  entities.Logger
+}
+
+func NewRoleAssignmentHandler(
+	service application.RoleAssignmentService, logger entities.Logger,
+) *RoleAssignmentHandler {
+	return &RoleAssignmentHandler{service: service, logger: logger}
+}
+
+type roleAssignmentRequest struct {
+	AgentID    string `json:"agent_id"`
+	Role       string `json:"role"`
+	Scope      string `json:"scope"`
+	TypeSlug   string `json:"type_slug"`
+	ResourceID string `json:"resource_id"`
+}
+
+// RoleAssignmentResponse is one role assignment.
+type RoleAssignmentResponse struct {
+	ID         string `json:"id"`
+	AgentID    string `json:"agent_id"`
+	Role       string `json:"role"`
+	Scope      string `json:"scope"`
+	AccountID  string `json:"account_id,omitempty"`
+	TypeSlug   string `json:"type_slug,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
+	GrantedBy  string `json:"granted_by"`
+	GrantedAt  string `json:"granted_at"`
+}
+
+func (h *RoleAssignmentHandler) Create(c echo.Context) error {
+	var req roleAssignmentRequest
+	if err := c.Bind(&req); err != nil {
+		return respondError(c, http.StatusBadRequest, "invalid request body")
+	}
+	a, err := h.service.Assign(c.Request().Context(), application.AssignRoleCommand{
+		AgentID:    req.AgentID,
+		Role:       req.Role,
+		Scope:      req.Scope,
+		TypeSlug:   req.TypeSlug,
+		ResourceID: req.ResourceID,
+	})
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusCreated, roleAssignmentResponse(a))
+}
+
+// List returns the active account's assignments, or one agent's with
+// ?agent_id=.
+func (h *RoleAssignmentHandler) List(c echo.Context) error {
+	assignments, err := h.service.List(c.Request().Context(), c.QueryParam("agent_id"))
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	items := make([]RoleAssignmentResponse, 0, len(assignments))
+	for _, a := range assignments {
+		items = append(items, roleAssignmentResponse(a))
+	}
+	return respond(c, http.StatusOK, items)
+}
+
+func (h *RoleAssignmentHandler) Delete(c echo.Context) error {
+	if err := h.service.Revoke(c.Request().Context(), c.Param("id")); err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *RoleAssignmentHandler) respondServiceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, entities.ErrAccessDenied):
+		return respondError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, repositories.ErrNotFound):
+		return respondError(c, http.StatusNotFound, "role assignment not found")
+	case errors.Is(err, application.ErrValidation):
+		return respondValidationError(c, err)
+	}
+	h.logger.Error(c.Request().Context(), "role assignment request failed", "error", err)
+	return respondError(c, http.StatusInternalServerError, err.Error())
+}
+
+func roleAssignmentResponse(a *entities.RoleAssignment) RoleAssignmentResponse {
+	return RoleAssignmentResponse{
+		ID:         a.ID,
+		AgentID:    a.AgentID,
+		Role:       a.Role,
+		Scope:      a.Scope,
+		AccountID:  a.AccountID,
+		TypeSlug:   a.TypeSlug,
+		ResourceID: a.ResourceID,
+		GrantedBy:  a.GrantedBy,
+		GrantedAt:  a.GrantedAt.Format(time.RFC3339),
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	apimw "github.com/wepala/weos/v3/api/middleware"
+	"github.com/wepala/weos/v3/application"
 	"github.com/wepala/weos/v3/domain/entities"
 	"github.com/wepala/weos/v3/domain/repositories"
 	"github.com/wepala/weos/v3/infrastructure/models"
@@ -56,6 +57,8 @@ type roleSettingsRequest struct {
 	Roles []string `json:"roles"`
 }
 
+// Get returns the active account's role list, or the global default when it
+// has none. Admin-only.
 func (h *RoleSettingsHandler) Get(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -68,7 +71,12 @@ func (h *RoleSettingsHandler) Get(c echo.Context) error {
 		return respondError(c, http.StatusForbidden, "admin role required")
 	}
 
-	roles, err := h.repo.GetRoleNames(ctx)
+	accountID, err := apimw.GetActiveAccountID(ctx, h.accountRepo)
+	if err != nil {
+		h.logger.Error(ctx, "failed to resolve active account", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to load roles")
+	}
+	roles, err := h.repo.GetRoleNames(ctx, accountID)
 	if err != nil {
 		h.logger.Error(ctx, "failed to load role settings", "error", err)
 		return respondError(c, http.StatusInternalServerError, "failed to load roles")
@@ -76,7 +84,7 @@ func (h *RoleSettingsHandler) Get(c echo.Context) error {
 	return respond(c, http.StatusOK, roleSettingsResponse{Roles: roles})
 }
 
-// Save updates the role list. Admin-only.
+// Save updates the active account's role list. Admin-only.
 func (h *RoleSettingsHandler) Save(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -98,7 +106,10 @@ func (h *RoleSettingsHandler) Save(c echo.Context) error {
 	for _, r := range req.Roles {
 		if r == "admin" {
 			hasAdmin = true
-			break
+			continue
+		}
+		if err := application.ValidateRoleName(r); err != nil {
+			return respondValidationError(c, err)
 		}
 	}
 	if !hasAdmin {
@@ -110,10 +121,15 @@ func (h *RoleSettingsHandler) Save(c echo.Context) error {
 		return respondError(c, http.StatusInternalServerError, "failed to encode roles")
 	}
 
+	accountID, err := apimw.GetActiveAccountID(ctx, h.accountRepo)
+	if err != nil {
+		h.logger.Error(ctx, "failed to resolve active account", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to save roles")
+	}
 	settings := &models.RoleSettings{
 		Roles: string(rolesJSON),
 	}
-	if err := h.repo.Save(ctx, settings); err != nil {
+	if err := h.repo.Save(ctx, accountID, settings); err != nil {
 		h.logger.Error(ctx, "failed to save role settings", "error", err)
 		return respondError(c, http.StatusInternalServerError, "failed to save roles")
 	}
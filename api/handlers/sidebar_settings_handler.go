@@ -52,11 +52,18 @@ type sidebarSettingsRequest struct {
 	MenuGroups  map[string]string `json:"menu_groups"`
 }
 
-// Get returns sidebar settings. If ?role= is provided (admin-only), returns
-// that role's settings. Otherwise returns settings for the current user's role.
+// Get returns the active account's sidebar settings, falling back to the global
+// defaults. If ?role= is provided (admin-only), returns that role's settings.
+// Otherwise returns settings for the current user's role.
 func (h *SidebarSettingsHandler) Get(c echo.Context) error {
 	ctx := c.Request().Context()
 
+	accountID, err := apimw.GetActiveAccountID(ctx, h.accountRepo)
+	if err != nil {
+		h.logger.Warn(ctx, "failed to resolve active account for sidebar, using global defaults", "error", err)
+		accountID = ""
+	}
+
 	role := c.QueryParam("role")
 	if role == "" {
 		var roleErr error
@@ -67,7 +74,7 @@ func (h *SidebarSettingsHandler) Get(c echo.Context) error {
 		}
 	}
 
-	settings, err := h.repo.GetByRole(ctx, role)
+	settings, err := h.repo.GetByRole(ctx, accountID, role)
 	if err != nil {
 		h.logger.Error(ctx, "failed to load sidebar settings", "error", err, "role", role)
 		return respondError(c, http.StatusInternalServerError, "failed to load settings")
@@ -76,7 +83,7 @@ func (h *SidebarSettingsHandler) Get(c echo.Context) error {
 	return respond(c, http.StatusOK, h.toResponse(settings))
 }
 
-// Save updates sidebar settings. Admin-only.
+// Save updates the active account's sidebar settings. Admin-only.
 func (h *SidebarSettingsHandler) Save(c echo.Context) error {
 	isAdmin, err := apimw.IsAdmin(c.Request().Context(), h.accountRepo)
 	if err != nil {
@@ -113,13 +120,18 @@ func (h *SidebarSettingsHandler) Save(c echo.Context) error {
 	if role == "" {
 		role = "default"
 	}
+	accountID, err := apimw.GetActiveAccountID(c.Request().Context(), h.accountRepo)
+	if err != nil {
+		h.logger.Error(c.Request().Context(), "failed to resolve active account", "error", err)
+		return respondError(c, http.StatusInternalServerError, "failed to save settings")
+	}
 
 	settings := &models.SidebarSettings{
 		HiddenSlugs: string(hiddenJSON),
 		MenuGroups:  string(groupsJSON),
 	}
 
-	if err := h.repo.SaveByRole(c.Request().Context(), role, settings); err != nil {
+	if err := h.repo.SaveByRole(c.Request().Context(), accountID, role, settings); err != nil {
 		h.logger.Error(c.Request().Context(), "failed to save sidebar settings", "error", err, "role", role)
 		return respondError(c, http.StatusInternalServerError, "failed to save settings")
 	}
@@ -0,0 +1,188 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TeamHandler manages the active account's teams and their members. The
+// service requires an admin or owner for every change.
+type TeamHandler struct {
+	service application.TeamService
+	logger  entities.Logger
+}
+
+func NewTeamHandler(service application.TeamService, logger entities.Logger) *TeamHandler {
+	return &TeamHandler{service: service, logger: logger}
+}
+
+type teamRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type teamMemberRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+// TeamResponse is one team.
+type TeamResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedBy   string `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// TeamMemberResponse is one member of a team.
+type TeamMemberResponse struct {
+	AgentID string `json:"agent_id"`
+	AddedBy string `json:"added_by"`
+	AddedAt string `json:"added_at"`
+}
+
+func (h *TeamHandler) Create(c echo.Context) error {
+	var req teamRequest
+	if err := c.Bind(&req); err != nil {
+		return respondError(c, http.StatusBadRequest, "invalid request body")
+	}
+	team, err := h.service.Create(c.Request().Context(), application.CreateTeamCommand{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusCreated, teamResponse(team))
+}
+
+func (h *TeamHandler) List(c echo.Context) error {
+	cursor, limit := pageParams(c)
+	result, err := h.service.List(c.Request().Context(), cursor, limit)
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	items := make([]TeamResponse, 0, len(result.Data))
+	for _, team := range result.Data {
+		items = append(items, teamResponse(team))
+	}
+	return respondPaginated(c, http.StatusOK, items, result.Cursor, result.HasMore)
+}
+
+func (h *TeamHandler) Get(c echo.Context) error {
+	team, err := h.service.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusOK, teamResponse(team))
+}
+
+func (h *TeamHandler) Update(c echo.Context) error {
+	var req teamRequest
+	if err := c.Bind(&req); err != nil {
+		return respondError(c, http.StatusBadRequest, "invalid request body")
+	}
+	team, err := h.service.Update(c.Request().Context(), application.UpdateTeamCommand{
+		ID:          c.Param("id"),
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusOK, teamResponse(team))
+}
+
+func (h *TeamHandler) Delete(c echo.Context) error {
+	if err := h.service.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TeamHandler) ListMembers(c echo.Context) error {
+	members, err := h.service.ListMembers(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	items := make([]TeamMemberResponse, 0, len(members))
+	for _, m := range members {
+		items = append(items, teamMemberResponse(m))
+	}
+	return respond(c, http.StatusOK, items)
+}
+
+func (h *TeamHandler) AddMember(c echo.Context) error {
+	var req teamMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return respondError(c, http.StatusBadRequest, "invalid request body")
+	}
+	member, err := h.service.AddMember(c.Request().Context(), c.Param("id"), req.AgentID)
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusCreated, teamMemberResponse(member))
+}
+
+func (h *TeamHandler) RemoveMember(c echo.Context) error {
+	err := h.service.RemoveMember(c.Request().Context(), c.Param("id"), c.Param("agentId"))
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TeamHandler) respondServiceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, entities.ErrAccessDenied):
+		return respondError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, repositories.ErrNotFound):
+		return respondError(c, http.StatusNotFound, "team or member not found")
+	case errors.Is(err, application.ErrValidation):
+		return respondValidationError(c, err)
+	}
+	h.logger.Error(c.Request().Context(), "team request failed", "error", err)
+	return respondError(c, http.StatusInternalServerError, err.Error())
+}
+
+func teamResponse(team *entities.Team) TeamResponse {
+	return TeamResponse{
+		ID:          team.ID,
+		Name:        team.Name,
+		Description: team.Description,
+		CreatedBy:   team.CreatedBy,
+		CreatedAt:   team.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   team.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func teamMemberResponse(m *entities.TeamMember) TeamMemberResponse {
+	return TeamMemberResponse{
+		AgentID: m.AgentID,
+		AddedBy: m.AddedBy,
+		AddedAt: m.AddedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	apimw "github.com/wepala/weos/v3/api/middleware"
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookHandler manages the active account's outbound webhook
+// subscriptions. Every route is admin-only.
+type WebhookHandler struct {
+	service     application.WebhookService
+	accountRepo authrepos.AccountRepository
+	logger      entities.Logger
+}
+
+type WebhookHandlerConfig struct {
+	Service     application.WebhookService
+	AccountRepo authrepos.AccountRepository
+	Logger      entities.Logger
+}
+
+func NewWebhookHandler(cfg WebhookHandlerConfig) *WebhookHandler {
+	return &WebhookHandler{
+		service:     cfg.Service,
+		accountRepo: cfg.AccountRepo,
+		logger:      cfg.Logger,
+	}
+}
+
+type webhookRequest struct {
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	TypeSlugs []string `json:"type_slugs"`
+	Secret    string   `json:"secret"`
+	Active    *bool    `json:"active"`
+}
+
+// WebhookResponse is one subscription. Secret is only returned when the
+// subscription is created.
+type WebhookResponse struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	TypeSlugs []string `json:"type_slugs"`
+	Active    bool     `json:"active"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse is one entry in a subscription's delivery log.
+type WebhookDeliveryResponse struct {
+	ID             string `json:"id"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	ResourceID     string `json:"resource_id"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ResponseStatus int    `json:"response_status,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	ReplayOf       string `json:"replay_of,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	DeliveredAt    string `json:"delivered_at,omitempty"`
+}
+
+func (h *WebhookHandler) Create(c echo.Context) error {
+	if ok, err := h.requireAdmin(c); !ok {
+		return err
+	}
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return respondError(c, http.StatusBadRequest, "invalid request body")
+	}
+	sub, err := h.service.Create(c.Request().Context(), application.CreateWebhookCommand{
+		URL:       req.URL,
+		Events:    req.Events,
+		TypeSlugs: req.TypeSlugs,
+		Secret:    req.Secret,
+	})
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	resp := webhookResponse(sub)
+	resp.Secret = sub.Secret
+	return respond(c, http.StatusCreated, resp)
+}
+
+func (h *WebhookHandler) List(c echo.Context) error {
+	if ok, err := h.requireAdmin(c); !ok {
+		return err
+	}
+	cursor, limit := pageParams(c)
+	result, err := h.service.List(c.Request().Context(), cursor, limit)
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	items := make([]WebhookResponse, 0, len(result.Data))
+	for _, sub := range result.Data {
+		items = append(items, webhookResponse(sub))
+	}
+	return respondPaginated(c, http.StatusOK, items, result.Cursor, result.HasMore)
+}
+
+func (h *WebhookHandler) Get(c echo.Context) error {
+	if ok, err := h.requireAdmin(c); !ok {
+		return err
+	}
+	sub, err := h.service.GetByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusOK, webhookResponse(sub))
+}
+
+func (h *WebhookHandler) Update(c echo.Context) error {
+	if ok, err := h.requireAdmin(c); !ok {
+		return err
+	}
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return respondError(c, http.StatusBadRequest, "invalid request body")
+	}
+	sub, err := h.service.Update(c.Request().Context(), application.UpdateWebhookCommand{
+		ID:        c.Param("id"),
+		URL:       req.URL,
+		Events:    req.Events,
+		TypeSlugs: req.TypeSlugs,
+		Secret:    req.Secret,
+		Active:    req.Active,
+	})
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusOK, webhookResponse(sub))
+}
+
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	if ok, err := h.requireAdmin(c); !ok {
+		return err
+	}
+	if err := h.service.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	if ok, err := h.requireAdmin(c); !ok {
+		return err
+	}
+	cursor, limit := pageParams(c)
+	result, err := h.service.ListDeliveries(c.Request().Context(), c.Param("id"), cursor, limit)
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	items := make([]WebhookDeliveryResponse, 0, len(result.Data))
+	for _, d := range result.Data {
+		items = append(items, webhookDeliveryResponse(d))
+	}
+	return respondPaginated(c, http.StatusOK, items, result.Cursor, result.HasMore)
+}
+
+func (h *WebhookHandler) Replay(c echo.Context) error {
+	if ok, err := h.requireAdmin(c); !ok {
+		return err
+	}
+	delivery, err := h.service.Replay(c.Request().Context(), c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		return h.respondServiceError(c, err)
+	}
+	return respond(c, http.StatusAccepted, webhookDeliveryResponse(delivery))
+}
+
+// requireAdmin reports whether the caller is an admin of their active
+// account. When they are not, it has already written the error response.
+func (h *WebhookHandler) requireAdmin(c echo.Context) (bool, error) {
+	ctx := c.Request().Context()
+	isAdmin, err := apimw.IsAdmin(ctx, h.accountRepo)
+	if err != nil {
+		h.logger.Error(ctx, "failed to check admin status", "error", err)
+		return false, respondError(c, http.StatusInternalServerError, "authorization check failed")
+	}
+	if !isAdmin {
+		return false, respondError(c, http.StatusForbidden, "admin role required")
+	}
+	return true, nil
+}
+
+func (h *WebhookHandler) respondServiceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, entities.ErrAccessDenied):
+		return respondError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, repositories.ErrNotFound):
+		return respondError(c, http.StatusNotFound, "webhook not found")
+	case errors.Is(err, application.ErrValidation):
+		return respondValidationError(c, err)
+	}
+	h.logger.Error(c.Request().Context(), "webhook request failed", "error", err)
+	return respondError(c, http.StatusInternalServerError, err.Error())
+}
+
+func pageParams(c echo.Context) (string, int) {
+	limit, _ := strconv.Atoi(c.QueryParam("limit")) //nolint:errcheck // defaults to 0, handled below
+	if limit <= 0 {
+		limit = 20
+	}
+	return c.QueryParam("cursor"), limit
+}
+
+func webhookResponse(sub *entities.WebhookSubscription) WebhookResponse {
+	typeSlugs := sub.TypeSlugs
+	if typeSlugs == nil {
+		typeSlugs = []string{}
+	}
+	return WebhookResponse{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    sub.Events,
+		TypeSlugs: typeSlugs,
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: sub.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func webhookDeliveryResponse(d *entities.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		ResourceID:     d.ResourceID,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		ReplayOf:       d.ReplayOf,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.DeliveredAt != nil {
+		resp.DeliveredAt = d.DeliveredAt.Format(time.RFC3339)
+	}
+	return resp
+}
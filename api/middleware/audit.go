@@ -0,0 +1,75 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package middleware
+
+import (
+	"github.com/wepala/weos/v3/domain/entities"
+
+	"github.com/labstack/echo/v4"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	// maxRequestIDLength bounds a caller-supplied request ID, which is
+	// stored on every event the request causes.
+	maxRequestIDLength = 128
+)
+
+// RequestID returns middleware that gives every request an ID, recorded on
+// the events it causes and echoed in the X-Request-ID response header. A
+// caller's own X-Request-ID is kept if it is short and made of letters,
+// digits, '.', '_' and '-'.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(requestIDHeader)
+			if !validRequestID(id) {
+				id = ksuid.New().String()
+			}
+			c.Response().Header().Set(requestIDHeader, id)
+			ctx := entities.ContextWithRequestID(c.Request().Context(), id)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AuthMethod returns middleware that records how the request's agent was
+// authenticated, for auth middleware that cannot record it itself.
+func AuthMethod(method string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := entities.ContextWithAuthMethod(c.Request().Context(), method)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -19,12 +19,12 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/wepala/weos/v3/application"
 	"github.com/wepala/weos/v3/domain/entities"
 
 	"github.com/akeemphilbert/pericarp/pkg/auth"
 	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
 	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
-	authcasbin "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/casbin"
 	"github.com/labstack/echo/v4"
 )
 
@@ -41,18 +41,21 @@ var methodToAction = map[string]string{
 	"DELETE": authentities.ActionDelete,
 }
 
-// AuthorizeResource returns Echo middleware that checks Casbin policies for
-// dynamic resource routes (/:typeSlug and /:typeSlug/:id). Requests without a
-// :typeSlug parameter are passed through (they are static routes).
+// AuthorizeResource returns Echo middleware that checks the caller's roles
+// for dynamic resource routes (/:typeSlug and /:typeSlug/:id). Requests
+// without a :typeSlug parameter are passed through (they are static routes).
+// The role authorizer decides from the caller's scoped role assignments,
+// falling back to their role in the active account; see
+// application.RoleAuthorizer.
 //
-// Fail-closed: nil identity returns 401, missing role returns 403, and any
-// Casbin error returns 500. The only exception is unconfigured roles (zero
-// policies) which are allowed through with a logged warning.
+// Fail-closed: nil identity returns 401, no role returns 403, and any
+// Casbin error returns 500. The only exception is unconfigured account
+// roles (zero policies) which are allowed to read with a logged warning.
 //
 // Middleware order is security-critical: RequireAuth must run first to establish
 // identity, then Impersonation to swap identity if active, then this middleware.
 func AuthorizeResource(
-	checker *authcasbin.CasbinAuthorizationChecker,
+	authorizer application.RoleAuthorizer,
 	accountRepo authrepos.AccountRepository,
 	logger entities.Logger,
 ) echo.MiddlewareFunc {
@@ -75,57 +78,40 @@ func AuthorizeResource(
 				logger.Error(ctx, "authorization: failed to resolve role", "error", err)
 				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "authorization check failed"})
 			}
-			if role == "" {
-				return c.JSON(http.StatusForbidden, map[string]string{"error": "no role assigned"})
-			}
-
-			// Ensure the user's role is assigned as a Casbin grouping policy
-			// so role-based policies apply. This is idempotent.
-			if identity.ActiveAccountID != "" {
-				_ = checker.AssignAccountRole(identity.AgentID, role, identity.ActiveAccountID)
-			} else {
-				_ = checker.AssignRole(identity.AgentID, role)
-			}
 
 			action, ok := methodToAction[strings.ToUpper(c.Request().Method)]
 			if !ok {
 				return c.JSON(http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
 			}
 
-			var allowed bool
-			if identity.ActiveAccountID != "" {
-				allowed, err = checker.IsAuthorizedInAccount(
-					ctx, identity.AgentID, identity.ActiveAccountID,
-					action, typeSlug,
-				)
-			} else {
-				allowed, err = checker.IsAuthorized(
-					ctx, identity.AgentID, action, typeSlug,
-				)
-			}
-
+			decision, err := authorizer.Authorize(ctx, application.AuthorizationRequest{
+				AgentID:    identity.AgentID,
+				AccountID:  identity.ActiveAccountID,
+				MemberRole: role,
+				Action:     action,
+				TypeSlug:   typeSlug,
+				ResourceID: c.Param("id"),
+			})
 			if err != nil {
-				logger.Error(ctx, "authorization: casbin check failed",
+				logger.Error(ctx, "authorization: role check failed",
 					"error", err, "role", role, "action", action, "resource", typeSlug)
 				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "authorization check failed"})
 			}
+			if decision.Role == "" {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "no role assigned"})
+			}
 
-			if !allowed {
-				// Allow read-only access when the role has zero configured policies
-				// (unconfigured role — admin has not yet set up access).
+			if !decision.Allowed {
+				// Allow read-only access when the account role has zero configured
+				// policies (unconfigured role — admin has not yet set up access).
 				// Write operations are always denied to prevent unauthorized mutations.
-				perms, permErr := checker.GetPermissions(ctx, role)
-				if permErr != nil {
-					logger.Error(ctx, "authorization: failed to check permissions",
-						"error", permErr, "role", role)
-					return c.JSON(http.StatusInternalServerError, map[string]string{"error": "authorization check failed"})
-				}
-				isRead := action == authentities.ActionRead
-				if len(perms) == 0 && isRead {
+				if decision.Unconfigured && action == authentities.ActionRead {
 					logger.Warn(ctx, "authorization: allowing unconfigured role read access",
 						"role", role, "action", action, "resource", typeSlug)
 					return next(c)
 				}
+				logger.Info(ctx, "authorization: denied", "reason", decision.Reason,
+					"action", action, "resource", typeSlug)
 				return c.JSON(http.StatusForbidden, map[string]string{
 					"error": "you do not have access to this resource type",
 				})
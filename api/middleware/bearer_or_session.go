@@ -19,6 +19,8 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/wepala/weos/v3/domain/entities"
+
 	"github.com/akeemphilbert/pericarp/pkg/auth"
 	authapp "github.com/akeemphilbert/pericarp/pkg/auth/application"
 	"github.com/labstack/echo/v4"
@@ -50,6 +52,8 @@ func BearerOrSession(
 			token := extractBearer(c.Request())
 			if token == "" {
 				// No Bearer token — fall through to session auth.
+				ctx := entities.ContextWithAuthMethod(c.Request().Context(), entities.AuthMethodSession)
+				c.SetRequest(c.Request().WithContext(ctx))
 				return sessionAuthEcho(sessionAuth, next, wwwAuth)(c)
 			}
 
@@ -66,6 +70,7 @@ func BearerOrSession(
 				ActiveAccountID: claims.ActiveAccountID,
 			}
 			ctx := auth.ContextWithAgent(c.Request().Context(), identity)
+			ctx = entities.ContextWithAuthMethod(ctx, entities.AuthMethodOAuthBearer)
 			c.SetRequest(c.Request().WithContext(ctx))
 			return next(c)
 		}
@@ -73,6 +73,7 @@ func Impersonation(store sessions.Store, accountRepo authrepos.AccountRepository
 				ActiveAccountID: activeAccountID,
 			}
 			ctx := auth.ContextWithAgent(c.Request().Context(), impersonatedIdentity)
+			ctx = entities.ContextWithRealAgent(ctx, currentIdentity.AgentID)
 			c.SetRequest(c.Request().WithContext(ctx))
 
 			c.Response().Header().Set("X-Impersonating", impersonatedAgentID)
@@ -3,6 +3,8 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/wepala/weos/v3/domain/entities"
+
 	"github.com/akeemphilbert/pericarp/pkg/auth"
 	authapp "github.com/akeemphilbert/pericarp/pkg/auth/application"
 	"github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/session"
@@ -37,6 +39,7 @@ func OptionalAuth(
 				ActiveAccountID: sessionInfo.AccountID,
 			}
 			ctx := auth.ContextWithAgent(r.Context(), id)
+			ctx = entities.ContextWithAuthMethod(ctx, entities.AuthMethodSession)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
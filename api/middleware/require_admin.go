@@ -18,36 +18,51 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"slices"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
 
 	"github.com/akeemphilbert/pericarp/pkg/auth"
 	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
 	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
 )
 
-// GetUserRole returns the authenticated user's role in their active account.
-// If no active account is set (legacy sessions), falls back to the first account
-// the user belongs to. Returns ("", nil) when no identity is present or the user
-// has no accounts. Returns a non-nil error only on database/infrastructure failures.
-func GetUserRole(ctx context.Context, accountRepo authrepos.AccountRepository) (string, error) {
+// GetActiveAccountID returns the authenticated user's active account. If none
+// is set (legacy sessions), falls back to the first account the user belongs
+// to, as GetUserRole does. Returns ("", nil) when no identity is present or
+// the user has no accounts.
+func GetActiveAccountID(ctx context.Context, accountRepo authrepos.AccountRepository) (string, error) {
 	identity := auth.AgentFromCtx(ctx)
 	if identity == nil {
 		return "", nil
 	}
+	if identity.ActiveAccountID != "" {
+		return identity.ActiveAccountID, nil
+	}
 
-	accountID := identity.ActiveAccountID
-	if accountID == "" {
-		// Fallback: find the first account this agent belongs to.
-		accounts, err := accountRepo.FindByMember(ctx, identity.AgentID)
-		if err != nil {
-			return "", fmt.Errorf("failed to find member accounts: %w", err)
-		}
-		if len(accounts) == 0 {
-			return "", nil
-		}
-		accountID = accounts[0].GetID()
+	// Fallback: find the first account this agent belongs to.
+	accounts, err := accountRepo.FindByMember(ctx, identity.AgentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find member accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return "", nil
 	}
+	return accounts[0].GetID(), nil
+}
 
-	role, err := accountRepo.FindMemberRole(ctx, accountID, identity.AgentID)
+// GetUserRole returns the authenticated user's role in their active account.
+// If no active account is set (legacy sessions), falls back to the first account
+// the user belongs to. Returns ("", nil) when no identity is present or the user
+// has no accounts. Returns a non-nil error only on database/infrastructure failures.
+func GetUserRole(ctx context.Context, accountRepo authrepos.AccountRepository) (string, error) {
+	accountID, err := GetActiveAccountID(ctx, accountRepo)
+	if err != nil || accountID == "" {
+		return "", err
+	}
+
+	role, err := accountRepo.FindMemberRole(ctx, accountID, auth.AgentFromCtx(ctx).AgentID)
 	if err != nil {
 		return "", fmt.Errorf("failed to find member role: %w", err)
 	}
@@ -64,3 +79,20 @@ func IsAdmin(ctx context.Context, accountRepo authrepos.AccountRepository) (bool
 	}
 	return role == authentities.RoleOwner || role == authentities.RoleAdmin, nil
 }
+
+// IsSuperAdmin checks whether the authenticated user holds the global
+// super-admin role assignment. Returns (false, nil) when no identity is
+// present.
+func IsSuperAdmin(ctx context.Context, assignments repositories.RoleAssignmentRepository) (bool, error) {
+	identity := auth.AgentFromCtx(ctx)
+	if identity == nil {
+		return false, nil
+	}
+	held, err := assignments.FindByAgent(ctx, identity.AgentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find role assignments: %w", err)
+	}
+	return slices.ContainsFunc(held, func(a *entities.RoleAssignment) bool {
+		return a.Scope == entities.RoleScopeGlobal && a.Role == entities.RoleSuperAdmin
+	}), nil
+}
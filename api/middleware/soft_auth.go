@@ -58,6 +58,7 @@ func SoftAuth(
 				ActiveAccountID: activeAccountID,
 			}
 			authCtx := auth.ContextWithAgent(ctx, identity)
+			authCtx = entities.ContextWithAuthMethod(authCtx, entities.AuthMethodDev)
 			c.SetRequest(c.Request().WithContext(authCtx))
 
 			return next(c)
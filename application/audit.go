@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// auditRecorder keeps the audit log: one entry per stored event, with the
+// audit metadata the event store stamped on it. Internal bookkeeping events
+// are left out, as they are from webhooks.
+type auditRecorder struct {
+	audit repositories.AuditRepository
+}
+
+func (r *auditRecorder) subscribe(d *domain.EventDispatcher) error {
+	return d.SubscribeWildcard(r.record)
+}
+
+func (r *auditRecorder) record(ctx context.Context, env domain.EventEnvelope[any]) error {
+	if internalEvents[env.EventType] {
+		return nil
+	}
+	// Events stored before they carried audit metadata fall back to what
+	// their payload says.
+	var fields struct {
+		TypeSlug  string
+		Slug      string
+		AccountID string
+		CreatedBy string
+	}
+	if raw, err := json.Marshal(env.Payload); err == nil {
+		_ = json.Unmarshal(raw, &fields)
+	}
+	entry := &entities.AuditEntry{
+		EventID:     env.ID,
+		EventType:   env.EventType,
+		ResourceID:  env.AggregateID,
+		TypeSlug:    fields.TypeSlug,
+		AgentID:     metadataString(env.Metadata, entities.AuditActorKey, fields.CreatedBy),
+		RealAgentID: metadataString(env.Metadata, entities.AuditRealActorKey, ""),
+		AccountID:   metadataString(env.Metadata, entities.AuditAccountKey, fields.AccountID),
+		AuthMethod:  metadataString(env.Metadata, entities.AuditMethodKey, ""),
+		RequestID:   metadataString(env.Metadata, entities.AuditRequestKey, ""),
+		OccurredAt:  env.Created.UTC(),
+	}
+	if entry.TypeSlug == "" {
+		entry.TypeSlug = fields.Slug
+	}
+	if entry.TypeSlug == "" {
+		// Only the creating event names the type; later events of the
+		// resource take it from the entry that did.
+		slug, err := r.audit.TypeSlugOf(ctx, env.AggregateID)
+		if err != nil {
+			return err
+		}
+		entry.TypeSlug = slug
+	}
+	return r.audit.Record(ctx, entry)
+}
+
+func metadataString(metadata map[string]any, key, fallback string) string {
+	if v, ok := metadata[key].(string); ok && v != "" {
+		return v
+	}
+	return fallback
+}
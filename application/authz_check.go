@@ -0,0 +1,239 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+	"go.uber.org/fx"
+)
+
+// Checks recorded in an authorization trace, in the order they run.
+const (
+	// CheckRole is the type-level check AuthorizeResource makes from the
+	// agent's role assignments and account role.
+	CheckRole = "role"
+	// CheckUnconfiguredRead is AuthorizeResource's fallback letting account
+	// roles with no policies at all read.
+	CheckUnconfiguredRead   = "unconfigured-role-read"
+	CheckAccountAdmin       = "account-admin"
+	CheckOwnership          = "ownership"
+	CheckResourcePermission = "resource-permission"
+	CheckRoleScope          = "role-scope"
+	CheckInherited          = "inherited-permission"
+	CheckUnowned            = "unowned-resource"
+)
+
+const (
+	OutcomeAllow = "allow"
+	OutcomeDeny  = "deny"
+)
+
+// AuthorizationStep is one check made while deciding a request.
+type AuthorizationStep struct {
+	Check   string `json:"check"`
+	Outcome string `json:"outcome"`
+	Detail  string `json:"detail"`
+	// Policy is the Casbin policy the check matched, as "role, action, target".
+	Policy string `json:"policy,omitempty"`
+}
+
+// authorizationTrace collects the steps of a decision. A nil trace records
+// nothing, so the request path pays nothing for it.
+type authorizationTrace struct {
+	steps []AuthorizationStep
+}
+
+func (t *authorizationTrace) add(check, outcome, detail, policy string) {
+	if t == nil {
+		return
+	}
+	t.steps = append(t.steps, AuthorizationStep{Check: check, Outcome: outcome, Detail: detail, Policy: policy})
+}
+
+// CheckAuthorizationCommand asks whether an agent may take an action on a
+// resource type, or on one resource when ResourceID is set.
+type CheckAuthorizationCommand struct {
+	// AgentID is the agent to check; empty checks the caller.
+	AgentID string
+	// AccountID is the account to check in. It is the caller's active
+	// account; system callers, which have none, name it.
+	AccountID string
+	// Action is "read", "modify" or "delete".
+	Action string
+	// TypeSlug may be omitted when ResourceID is set.
+	TypeSlug   string
+	ResourceID string
+}
+
+// AuthorizationExplanation is the decision for a CheckAuthorizationCommand
+// and the checks that led to it. DecidedBy is the check that allowed the
+// request, or the last one that denied it.
+type AuthorizationExplanation struct {
+	Allowed   bool
+	DecidedBy string
+	Trace     []AuthorizationStep
+}
+
+// AuthorizationCheckService explains authorization decisions. It runs the
+// same checks as the request path, AuthorizeResource followed by the
+// per-resource checks, without taking the action. Agents may check
+// themselves; checking another agent takes an admin or owner of the
+// account.
+type AuthorizationCheckService interface {
+	Check(ctx context.Context, cmd CheckAuthorizationCommand) (*AuthorizationExplanation, error)
+}
+
+type authorizationCheckService struct {
+	roles     RoleAuthorizer
+	accounts  authrepos.AccountRepository
+	types     repositories.ResourceTypeRepository
+	resources repositories.ResourceRepository
+	access    instanceAccess
+}
+
+func ProvideAuthorizationCheckService(params struct {
+	fx.In
+	Roles       RoleAuthorizer
+	AccountRepo authrepos.AccountRepository
+	PermRepo    repositories.ResourcePermissionRepository
+	TypeRepo    repositories.ResourceTypeRepository
+	TripleRepo  repositories.TripleRepository
+	Resources   repositories.ResourceRepository
+}) AuthorizationCheckService {
+	return &authorizationCheckService{
+		roles:     params.Roles,
+		accounts:  params.AccountRepo,
+		types:     params.TypeRepo,
+		resources: params.Resources,
+		access: instanceAccess{
+			accounts: params.AccountRepo,
+			perms:    params.PermRepo,
+			inherit: &permissionInheritance{
+				types:     params.TypeRepo,
+				triples:   params.TripleRepo,
+				resources: params.Resources,
+			},
+			roles: params.Roles,
+		},
+	}
+}
+
+func (s *authorizationCheckService) Check(
+	ctx context.Context, cmd CheckAuthorizationCommand,
+) (*AuthorizationExplanation, error) {
+	agentID, accountID, err := s.subject(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	action, ok := ODRLActions[cmd.Action]
+	if !ok {
+		return nil, fmt.Errorf("action must be read, modify or delete: %w", ErrValidation)
+	}
+
+	var resource *entities.Resource
+	typeSlug := strings.TrimSpace(cmd.TypeSlug)
+	if cmd.ResourceID != "" {
+		resource, err = s.resources.FindByID(ctx, cmd.ResourceID)
+		if err != nil || (accountID != "" && resource.AccountID() != accountID) {
+			return nil, fmt.Errorf("resource %q: %w", cmd.ResourceID, repositories.ErrNotFound)
+		}
+		if typeSlug == "" {
+			typeSlug = resource.TypeSlug()
+		} else if typeSlug != resource.TypeSlug() {
+			return nil, fmt.Errorf("resource %q is not a %s: %w", cmd.ResourceID, typeSlug, ErrValidation)
+		}
+	}
+	if typeSlug == "" {
+		return nil, fmt.Errorf("type_slug is required: %w", ErrValidation)
+	}
+	if _, err := s.types.FindBySlug(ctx, typeSlug); err != nil {
+		return nil, fmt.Errorf("resource type %q not found: %w", typeSlug, ErrValidation)
+	}
+
+	trace := &authorizationTrace{}
+	allowed, err := s.typeAccess(ctx, agentID, accountID, action, typeSlug, cmd.ResourceID, trace)
+	if err != nil {
+		return nil, err
+	}
+	if allowed && resource != nil {
+		allowed = s.access.allows(ctx, agentID, resource.GetID(), typeSlug, resource.AccountID(),
+			resource.CreatedBy(), cmd.Action, trace)
+	}
+
+	explanation := &AuthorizationExplanation{Allowed: allowed, Trace: trace.steps}
+	for i := len(trace.steps) - 1; i >= 0; i-- {
+		if (trace.steps[i].Outcome == OutcomeAllow) == allowed {
+			explanation.DecidedBy = trace.steps[i].Check
+			break
+		}
+	}
+	return explanation, nil
+}
+
+// subject returns the agent to check and the account to check them in,
+// enforcing that only admins and owners check agents other than
+// themselves.
+func (s *authorizationCheckService) subject(
+	ctx context.Context, cmd CheckAuthorizationCommand,
+) (string, string, error) {
+	agentID := strings.TrimSpace(cmd.AgentID)
+	ident := auth.AgentFromCtx(ctx)
+	if ident == nil {
+		if agentID == "" {
+			return "", "", fmt.Errorf("agent_id is required: %w", ErrValidation)
+		}
+		return agentID, cmd.AccountID, nil
+	}
+	if agentID == "" || agentID == ident.AgentID {
+		return ident.AgentID, ident.ActiveAccountID, nil
+	}
+	if ident.ActiveAccountID == "" {
+		return "", "", fmt.Errorf("checking another agent requires an active account: %w", entities.ErrAccessDenied)
+	}
+	role, err := s.accounts.FindMemberRole(ctx, ident.ActiveAccountID, ident.AgentID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find member role: %w", err)
+	}
+	if role != authentities.RoleOwner && role != authentities.RoleAdmin {
+		return "", "", fmt.Errorf("only account admins can check other agents: %w", entities.ErrAccessDenied)
+	}
+	return agentID, ident.ActiveAccountID, nil
+}
+
+// typeAccess makes AuthorizeResource's check of the resource type.
+func (s *authorizationCheckService) typeAccess(
+	ctx context.Context, agentID, accountID, action, typeSlug, resourceID string, trace *authorizationTrace,
+) (bool, error) {
+	var memberRole string
+	if accountID != "" {
+		var err error
+		if memberRole, err = s.accounts.FindMemberRole(ctx, accountID, agentID); err != nil {
+			return false, fmt.Errorf("failed to find member role: %w", err)
+		}
+	}
+	decision, err := s.roles.Authorize(ctx, AuthorizationRequest{
+		AgentID: agentID, AccountID: accountID, MemberRole: memberRole,
+		Action: action, TypeSlug: typeSlug, ResourceID: resourceID,
+	})
+	if err != nil {
+		return false, err
+	}
+	if decision.Allowed {
+		trace.add(CheckRole, OutcomeAllow, decision.Reason, decision.Policy)
+		return true, nil
+	}
+	trace.add(CheckRole, OutcomeDeny, decision.Reason, decision.Policy)
+	if !decision.Unconfigured || action != authentities.ActionRead {
+		return false, nil
+	}
+	trace.add(CheckUnconfiguredRead, OutcomeAllow,
+		fmt.Sprintf("account role %q has no policies configured, so it may read", decision.Role), "")
+	return true, nil
+}
@@ -16,10 +16,14 @@
 package application
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
 
 	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
 	authcasbin "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/casbin"
@@ -32,10 +36,86 @@ var ODRLActions = map[string]string{
 	"delete": authentities.ActionDelete,
 }
 
+// roleSubjectSeparator joins an account and a role in AccountRoleSubject.
+// Role names may not contain it, so no two account and role pairs share a
+// subject.
+const roleSubjectSeparator = "#"
+
+// AccountRoleSubject returns the Casbin subject holding role's policies in
+// the role-access configuration of accountID. An account's policies for a
+// custom role are kept under a subject qualified by the account rather
+// than in a Casbin domain: the checker's model applies the policies of the
+// "*" domain in every domain, so the global default would leak into
+// accounts that replace it. The global default (empty accountID), admin,
+// owner, and the built-in roles, which accounts cannot configure, use the
+// role itself.
+func AccountRoleSubject(accountID, role string) string {
+	if accountID == "" || role == authentities.RoleAdmin || role == authentities.RoleOwner || IsBuiltinRole(role) {
+		return role
+	}
+	return accountID + roleSubjectSeparator + role
+}
+
+// ValidateRoleName checks the name of a custom role: it may not be empty,
+// contain the "#" that qualifies a role by its account, or take the weos:
+// prefix of the built-in roles. Errors wrap ErrValidation.
+func ValidateRoleName(role string) error {
+	switch {
+	case role == "":
+		return fmt.Errorf("role names cannot be empty: %w", ErrValidation)
+	case strings.Contains(role, roleSubjectSeparator):
+		return fmt.Errorf("role %q contains %q, which role names cannot: %w", role, roleSubjectSeparator, ErrValidation)
+	case strings.HasPrefix(role, entities.BuiltinRolePrefix):
+		return fmt.Errorf("role %q uses the %q prefix reserved for built-in roles: %w",
+			role, entities.BuiltinRolePrefix, ErrValidation)
+	}
+	return nil
+}
+
+// LoadRoleAccessIntoCasbin syncs the global default role-access
+// configuration and that of every account with its own into Casbin. Run it
+// on startup; errors are joined so one bad configuration does not stop the
+// others.
+func LoadRoleAccessIntoCasbin(
+	ctx context.Context,
+	checker *authcasbin.CasbinAuthorizationChecker,
+	repo repositories.RoleResourceAccessRepository,
+) error {
+	accounts, err := repo.Accounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list accounts with role access: %w", err)
+	}
+	var errs []error
+	for _, accountID := range append([]string{""}, accounts...) {
+		accessMap, err := repo.GetAccessMap(ctx, accountID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("access map of %q: %w", accountID, err))
+		} else if err := SyncAccessMapToCasbin(checker, accountID, accessMap, nil); err != nil {
+			errs = append(errs, err)
+		}
+		denyMap, err := repo.GetDenyMap(ctx, accountID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("deny rules of %q: %w", accountID, err))
+		} else if err := SyncDenyMapToCasbin(checker, accountID, denyMap, nil); err != nil {
+			errs = append(errs, err)
+		}
+		inherits, err := repo.GetRoleHierarchy(ctx, accountID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("role hierarchy of %q: %w", accountID, err))
+		} else if err := SyncRoleHierarchyToCasbin(checker, accountID, inherits, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // SyncAccessMapToCasbin clears stale role policies and re-adds from the new access map.
-// oldAccessMap may be nil (on startup). It preserves admin and owner wildcard policies.
+// The maps are the configuration of accountID, or the global default when it is empty;
+// see AccountRoleSubject. oldAccessMap may be nil (on startup). It preserves admin and
+// owner wildcard policies.
 func SyncAccessMapToCasbin(
 	checker *authcasbin.CasbinAuthorizationChecker,
+	accountID string,
 	newMap, oldMap entities.AccessMap,
 ) error {
 	var errs []error
@@ -55,19 +135,20 @@ func SyncAccessMapToCasbin(
 
 	// Remove all per-slug policies for roles being updated/removed.
 	for role := range rolesToClear {
+		subject := AccountRoleSubject(accountID, role)
 		if oldResources, ok := oldMap[role]; ok {
 			for slug := range oldResources {
 				for _, odrl := range ODRLActions {
-					if err := checker.RemovePermission(role, odrl, slug); err != nil {
-						errs = append(errs, fmt.Errorf("remove %s/%s/%s: %w", role, odrl, slug, err))
+					if err := checker.RemovePermission(subject, odrl, slug); err != nil {
+						errs = append(errs, fmt.Errorf("remove %s/%s/%s: %w", subject, odrl, slug, err))
 					}
 				}
 			}
 		}
 		// Also remove wildcard in case it was set.
 		for _, odrl := range ODRLActions {
-			if err := checker.RemovePermission(role, odrl, "*"); err != nil {
-				errs = append(errs, fmt.Errorf("remove %s/%s/*: %w", role, odrl, err))
+			if err := checker.RemovePermission(subject, odrl, "*"); err != nil {
+				errs = append(errs, fmt.Errorf("remove %s/%s/*: %w", subject, odrl, err))
 			}
 		}
 	}
@@ -77,11 +158,12 @@ func SyncAccessMapToCasbin(
 		if role == authentities.RoleAdmin || role == authentities.RoleOwner {
 			continue
 		}
+		subject := AccountRoleSubject(accountID, role)
 		for slug, actions := range resources {
 			for _, action := range actions {
 				if odrl, ok := ODRLActions[action]; ok {
-					if err := checker.AddPermission(role, odrl, slug); err != nil {
-						errs = append(errs, fmt.Errorf("add %s/%s/%s: %w", role, odrl, slug, err))
+					if err := checker.AddPermission(subject, odrl, slug); err != nil {
+						errs = append(errs, fmt.Errorf("add %s/%s/%s: %w", subject, odrl, slug, err))
 					}
 				}
 			}
@@ -94,7 +176,8 @@ func SyncAccessMapToCasbin(
 	return errors.Join(errs...)
 }
 
-// SeedAdminPolicies ensures admin and owner roles have wildcard access.
+// SeedAdminPolicies ensures admin and owner roles have wildcard access and
+// seeds the built-in roles with their policies and inheritance.
 // Idempotent — safe to call on every startup.
 func SeedAdminPolicies(checker *authcasbin.CasbinAuthorizationChecker) error {
 	var errs []error
@@ -105,5 +188,219 @@ func SeedAdminPolicies(checker *authcasbin.CasbinAuthorizationChecker) error {
 			}
 		}
 	}
+	for role, actions := range builtinRolePolicies {
+		for _, action := range actions {
+			if err := checker.AddPermission(role, ODRLActions[action], "*"); err != nil {
+				errs = append(errs, fmt.Errorf("seed %s/%s/*: %w", role, action, err))
+			}
+		}
+	}
+	for role, included := range builtinRoleHierarchy {
+		for _, inc := range included {
+			if err := checker.AssignRole(role, inc); err != nil {
+				errs = append(errs, fmt.Errorf("seed %s > %s: %w", role, inc, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// builtinRoleHierarchy is the fixed inheritance between the built-in roles.
+var builtinRoleHierarchy = entities.RoleHierarchy{
+	entities.RoleSuperAdmin: {entities.RoleSiteAdmin},
+	entities.RoleSiteAdmin:  {entities.RoleEditor},
+	entities.RoleEditor:     {entities.RoleAuthor},
+	entities.RoleAuthor:     {entities.RoleViewer},
+}
+
+// builtinRolePolicies are the actions each built-in role adds, on every
+// resource type, to those of the roles it inherits. Whose resources a role
+// may act on is decided per resource: editors and above act on any, and
+// authors on their own (see RoleAuthorizer).
+var builtinRolePolicies = map[string][]string{
+	entities.RoleViewer: {"read"},
+	entities.RoleAuthor: {"modify", "delete"},
+}
+
+// IsBuiltinRole reports whether role is one of the built-in roles.
+func IsBuiltinRole(role string) bool {
+	_, ok := builtinRoleHierarchy[role]
+	return ok || role == entities.RoleViewer
+}
+
+// roleHierarchy returns the built-in inheritance merged with custom.
+func roleHierarchy(custom entities.RoleHierarchy) entities.RoleHierarchy {
+	merged := entities.RoleHierarchy{}
+	for role, included := range builtinRoleHierarchy {
+		merged[role] = included
+	}
+	for role, included := range custom {
+		if !IsBuiltinRole(role) {
+			merged[role] = included
+		}
+	}
+	return merged
+}
+
+// roleIncludes reports whether role is target or inherits from it in h.
+func roleIncludes(h entities.RoleHierarchy, role, target string) bool {
+	seen := map[string]bool{}
+	queue := []string{role}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == target {
+			return true
+		}
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		queue = append(queue, h[next]...)
+	}
+	return false
+}
+
+// ValidateRoleDefinitions checks role-access settings before they are saved:
+// the built-in roles cannot be given access, deny, field, or inheritance
+// rules, every other role must pass ValidateRoleName, and inheritance may
+// not loop back to a role it started from.
+func ValidateRoleDefinitions(
+	access, deny entities.AccessMap, fields entities.FieldAccessMap, inherits entities.RoleHierarchy,
+) error {
+	var roles []string
+	for role := range access {
+		roles = append(roles, role)
+	}
+	for role := range deny {
+		roles = append(roles, role)
+	}
+	for role := range fields {
+		roles = append(roles, role)
+	}
+	for role := range inherits {
+		roles = append(roles, role)
+	}
+	slices.Sort(roles)
+	for _, role := range slices.Compact(roles) {
+		if IsBuiltinRole(role) {
+			return fmt.Errorf("built-in role %q cannot be changed: %w", role, ErrValidation)
+		}
+		if err := ValidateRoleName(role); err != nil {
+			return err
+		}
+	}
+	return validateRoleHierarchy(inherits)
+}
+
+// validateRoleHierarchy rejects custom inheritance that forms a cycle,
+// naming the roles on it.
+func validateRoleHierarchy(custom entities.RoleHierarchy) error {
+	h := roleHierarchy(custom)
+	roles := make([]string, 0, len(h))
+	for role := range h {
+		roles = append(roles, role)
+	}
+	slices.Sort(roles)
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(role string) error
+	visit = func(role string) error {
+		switch state[role] {
+		case done:
+			return nil
+		case visiting:
+			start := slices.Index(path, role)
+			cycle := append(slices.Clone(path[start:]), role)
+			return fmt.Errorf("role inheritance cycle %s: %w", strings.Join(cycle, " > "), ErrValidation)
+		}
+		state[role] = visiting
+		path = append(path, role)
+		for _, included := range h[role] {
+			if included == "" {
+				return fmt.Errorf("role %q inherits an empty role name: %w", role, ErrValidation)
+			}
+			if strings.Contains(included, roleSubjectSeparator) {
+				return fmt.Errorf("role %q inherits %q, which contains %q: %w",
+					role, included, roleSubjectSeparator, ErrValidation)
+			}
+			if err := visit(included); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[role] = done
+		return nil
+	}
+	for _, role := range roles {
+		if err := visit(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncRoleHierarchyToCasbin replaces the inheritance between custom roles in
+// the configuration of accountID (the global default when empty), removing
+// the links of oldH and adding those of newH. oldH may be nil (on startup).
+// Built-in inheritance is seeded by SeedAdminPolicies.
+func SyncRoleHierarchyToCasbin(
+	checker *authcasbin.CasbinAuthorizationChecker,
+	accountID string,
+	newH, oldH entities.RoleHierarchy,
+) error {
+	var errs []error
+	each := func(h entities.RoleHierarchy, op string, fn func(role, included string) error) {
+		for role, included := range h {
+			if IsBuiltinRole(role) {
+				continue
+			}
+			subject := AccountRoleSubject(accountID, role)
+			for _, inc := range included {
+				if err := fn(subject, AccountRoleSubject(accountID, inc)); err != nil {
+					errs = append(errs, fmt.Errorf("%s %s > %s: %w", op, subject, inc, err))
+				}
+			}
+		}
+	}
+	each(oldH, "remove", checker.RevokeRole)
+	each(newH, "add", checker.AssignRole)
+	return errors.Join(errs...)
+}
+
+// SyncDenyMapToCasbin replaces explicit deny policies in the configuration of
+// accountID (the global default when empty), removing those of oldMap and
+// adding those of newMap. oldMap may be nil (on startup). A deny wins over
+// any grant to the same role or to a role inheriting it. Admin, owner, and
+// built-in roles are skipped.
+func SyncDenyMapToCasbin(
+	checker *authcasbin.CasbinAuthorizationChecker,
+	accountID string,
+	newMap, oldMap entities.AccessMap,
+) error {
+	var errs []error
+	each := func(m entities.AccessMap, fn func(role, odrl, slug string) error) {
+		for role, resources := range m {
+			if role == authentities.RoleAdmin || role == authentities.RoleOwner || IsBuiltinRole(role) {
+				continue
+			}
+			for slug, actions := range resources {
+				for _, action := range actions {
+					if odrl, ok := ODRLActions[action]; ok {
+						subject := AccountRoleSubject(accountID, role)
+						if err := fn(subject, odrl, slug); err != nil {
+							errs = append(errs, fmt.Errorf("deny %s/%s/%s: %w", subject, odrl, slug, err))
+						}
+					}
+				}
+			}
+		}
+	}
+	each(oldMap, checker.RemoveProhibition)
+	each(newMap, checker.AddProhibition)
 	return errors.Join(errs...)
 }
@@ -0,0 +1,202 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// CEL variables available to every schema expression in addition to the
+// resource's own properties.
+const (
+	// celNowVar is the evaluation time as a timestamp.
+	celNowVar = "now"
+	// celRefsVar maps each single-valued reference property to the flat data
+	// of the resource it points at: refs.customer.name.
+	celRefsVar = "refs"
+)
+
+// celReserved are CEL keywords; a property with one of these names can't be
+// referenced as an identifier and is left out of the environment.
+var celReserved = map[string]bool{
+	"as": true, "break": true, "const": true, "continue": true, "else": true,
+	"false": true, "for": true, "function": true, "if": true, "import": true,
+	"in": true, "let": true, "loop": true, "package": true, "namespace": true,
+	"null": true, "return": true, "true": true, "var": true, "void": true, "while": true,
+}
+
+// celProperty is one schema property exposed to CEL expressions as a
+// top-level variable.
+type celProperty struct {
+	Name     string
+	JSONType string
+}
+
+// schemaCELEnv builds the CEL environment that schema expressions (x-computed,
+// x-rules) are compiled against. Every property whose name is a valid CEL
+// identifier becomes a variable typed after its JSON Schema type, so
+// `givenName + " " + familyName` type-checks as a string. Properties named
+// like the built-in variables (now, refs) are shadowed by them.
+func schemaCELEnv(schema json.RawMessage) (*cel.Env, []celProperty, error) {
+	var s struct {
+		Properties map[string]struct {
+			Type string `json:"type"`
+		} `json:"properties"`
+	}
+	if len(schema) > 0 {
+		if err := json.Unmarshal(schema, &s); err != nil {
+			return nil, nil, fmt.Errorf("invalid schema JSON: %w", err)
+		}
+	}
+	props := make([]celProperty, 0, len(s.Properties))
+	for name, p := range s.Properties {
+		if !isCELIdentifier(name) || name == celNowVar || name == celRefsVar {
+			continue
+		}
+		props = append(props, celProperty{Name: name, JSONType: p.Type})
+	}
+	sort.Slice(props, func(i, j int) bool { return props[i].Name < props[j].Name })
+
+	opts := []cel.EnvOption{
+		cel.Variable(celNowVar, cel.TimestampType),
+		cel.Variable(celRefsVar, cel.MapType(cel.StringType, cel.MapType(cel.StringType, cel.DynType))),
+	}
+	for _, p := range props {
+		opts = append(opts, cel.Variable(p.Name, celTypeFor(p.JSONType)))
+	}
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build expression environment: %w", err)
+	}
+	return env, props, nil
+}
+
+func celTypeFor(jsonType string) *cel.Type {
+	switch jsonType {
+	case "string":
+		return cel.StringType
+	case "number":
+		return cel.DoubleType
+	case "integer":
+		return cel.IntType
+	case "boolean":
+		return cel.BoolType
+	case "array":
+		return cel.ListType(cel.DynType)
+	case "object":
+		return cel.MapType(cel.StringType, cel.DynType)
+	default:
+		return cel.DynType
+	}
+}
+
+// celTypeMatches reports whether an expression's output type can be stored
+// in a property of the given JSON Schema type. Integers are accepted for
+// number properties, and dyn for anything (checked again at validation).
+func celTypeMatches(out *cel.Type, jsonType string) bool {
+	if jsonType == "" || out.IsExactType(cel.DynType) {
+		return true
+	}
+	if jsonType == "number" && out.IsExactType(cel.IntType) {
+		return true
+	}
+	want := celTypeFor(jsonType)
+	return out.Kind() == want.Kind()
+}
+
+func isCELIdentifier(name string) bool {
+	if name == "" || celReserved[name] {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// usesCELVariable reports whether a checked expression refers to the named
+// top-level variable, so callers can skip work (like loading referenced
+// resources) that no expression needs.
+func usesCELVariable(ast *cel.Ast, name string) bool {
+	for _, ref := range ast.NativeRep().ReferenceMap() {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// celActivation binds the resource data to the environment's variables. A
+// property missing from data is bound to the zero value of its type, so
+// expressions over optional fields don't fail with "no such attribute".
+// JSON numbers arrive as float64 and are converted for integer properties.
+func celActivation(
+	props []celProperty, data map[string]any, refs map[string]any, now time.Time,
+) map[string]any {
+	vars := make(map[string]any, len(props)+2)
+	vars[celNowVar] = now
+	if refs == nil {
+		refs = map[string]any{}
+	}
+	vars[celRefsVar] = refs
+	for _, p := range props {
+		v, ok := data[p.Name]
+		if !ok || v == nil {
+			vars[p.Name] = celZeroValue(p.JSONType)
+			continue
+		}
+		if f, isFloat := v.(float64); isFloat && p.JSONType == "integer" && f == float64(int64(f)) {
+			v = int64(f)
+		}
+		vars[p.Name] = v
+	}
+	return vars
+}
+
+func celZeroValue(jsonType string) any {
+	switch jsonType {
+	case "string":
+		return ""
+	case "number":
+		return 0.0
+	case "integer":
+		return int64(0)
+	case "boolean":
+		return false
+	case "array":
+		return []any{}
+	case "object":
+		return map[string]any{}
+	default:
+		return nil
+	}
+}
+
+// celValueToJSON converts an evaluation result into a plain Go value that
+// encodes to JSON the way the rest of the resource data does. Timestamps
+// become RFC 3339 strings.
+func celValueToJSON(val ref.Val) (any, error) {
+	if t, ok := val.Value().(time.Time); ok {
+		return t.UTC().Format(time.RFC3339), nil
+	}
+	native, err := val.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return nil, err
+	}
+	pb, ok := native.(*structpb.Value)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %T", native)
+	}
+	return pb.AsInterface(), nil
+}
@@ -0,0 +1,190 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+
+	"github.com/google/cel-go/cel"
+)
+
+// computedProperty is a schema property whose value is derived from the CEL
+// expression declared in its x-computed annotation.
+type computedProperty struct {
+	name     string
+	program  cel.Program
+	usesRefs bool
+}
+
+// computedSet is the compiled x-computed annotations of one schema together
+// with the property variables the expressions are evaluated against.
+type computedSet struct {
+	props  []celProperty
+	fields []computedProperty
+}
+
+// computedCache holds each type's compiled x-computed set. Programs are safe
+// for concurrent use.
+var computedCache schemaCache[*computedSet]
+
+// computedPropertiesOf returns the compiled x-computed set of rt.
+func computedPropertiesOf(rt *entities.ResourceType) (*computedSet, error) {
+	return computedCache.get(rt, func(rt *entities.ResourceType) (*computedSet, error) {
+		return compileComputedProperties(rt.Schema())
+	})
+}
+
+// compileComputedProperties compiles and type-checks every x-computed
+// expression in schema. An expression must compile against the schema's
+// properties and produce a value of the property's declared type, so
+// "x-computed": "givenName + ' ' + familyName" is accepted on a string
+// property and rejected on an integer one. Errors wrap ErrValidation.
+func compileComputedProperties(schema json.RawMessage) (*computedSet, error) {
+	if len(schema) == 0 {
+		return &computedSet{}, nil
+	}
+	var s struct {
+		Properties map[string]struct {
+			Type      string  `json:"type"`
+			XComputed *string `json:"x-computed"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return &computedSet{}, nil // the jsonschema compiler reports malformed schemas
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name, p := range s.Properties {
+		if p.XComputed != nil {
+			names = append(names, name)
+		}
+	}
+	set := &computedSet{}
+	if len(names) == 0 {
+		return set, nil
+	}
+	sort.Strings(names)
+
+	env, props, err := schemaCELEnv(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	set.props = props
+	for _, name := range names {
+		prop := s.Properties[name]
+		expr := *prop.XComputed
+		if expr == "" {
+			return nil, fmt.Errorf("property %q: x-computed must not be empty: %w", name, ErrValidation)
+		}
+		ast, iss := env.Compile(expr)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("property %q: invalid x-computed expression: %v: %w",
+				name, iss.Err(), ErrValidation)
+		}
+		if !celTypeMatches(ast.OutputType(), prop.Type) {
+			return nil, fmt.Errorf("property %q: x-computed expression returns %s, property is %q: %w",
+				name, ast.OutputType(), prop.Type, ErrValidation)
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: invalid x-computed expression: %v: %w",
+				name, err, ErrValidation)
+		}
+		set.fields = append(set.fields, computedProperty{
+			name:     name,
+			program:  program,
+			usesRefs: usesCELVariable(ast, celRefsVar),
+		})
+	}
+	return set, nil
+}
+
+// validateComputedProperties rejects x-computed expressions that don't compile
+// or don't type-check. Called when a ResourceType is created or updated.
+func validateComputedProperties(schema json.RawMessage) error {
+	_, err := compileComputedProperties(schema)
+	return err
+}
+
+// applyComputedProperties evaluates the type's x-computed expressions over the
+// incoming data and writes the results into it, overriding any value the
+// caller supplied. Every expression sees the data as submitted — computed
+// values don't feed into each other. Runs before schema validation so the
+// computed values are validated like any other field.
+func (s *resourceService) applyComputedProperties(
+	ctx context.Context, rt *entities.ResourceType, data json.RawMessage,
+) (json.RawMessage, error) {
+	set, err := computedPropertiesOf(rt)
+	if err != nil {
+		return nil, err
+	}
+	if len(set.fields) == 0 {
+		return data, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil || m == nil {
+		return data, nil // schema validation reports malformed data
+	}
+
+	var refs map[string]any
+	for _, f := range set.fields {
+		if f.usesRefs {
+			refs = s.loadReferencedData(ctx, rt, m)
+			break
+		}
+	}
+	vars := celActivation(set.props, m, refs, time.Now())
+
+	results := make(map[string]any, len(set.fields))
+	for _, f := range set.fields {
+		out, _, err := f.program.Eval(vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s: could not compute value: %v: %w", f.name, err, ErrValidation)
+		}
+		v, err := celValueToJSON(out)
+		if err != nil {
+			return nil, fmt.Errorf("%s: could not compute value: %v: %w", f.name, err, ErrValidation)
+		}
+		results[f.name] = v
+	}
+	for name, v := range results {
+		m[name] = v
+	}
+	return json.Marshal(m)
+}
+
+// loadReferencedData returns the flat entity data of every resource that a
+// single-valued reference in data points at, keyed by property name, for the
+// refs variable. Targets that are missing, archived or not readable by the
+// caller are left out; verifyReferences rejects those later with a proper
+// error.
+func (s *resourceService) loadReferencedData(
+	ctx context.Context, rt *entities.ResourceType, data map[string]any,
+) map[string]any {
+	refs := make(map[string]any)
+	for _, rp := range s.referencePropsFor(rt) {
+		id, ok := data[rp.PropertyName].(string)
+		if !ok || id == "" {
+			continue
+		}
+		target, err := s.repo.FindByID(ctx, id)
+		if err != nil || target.Status() == "archived" {
+			continue
+		}
+		if s.checkInstanceAccess(ctx, target, "read") != nil {
+			continue
+		}
+		var node map[string]any
+		if json.Unmarshal(ExtractEntityNode(target.Data()), &node) != nil {
+			continue
+		}
+		delete(node, "@id")
+		delete(node, "@type")
+		delete(node, "@context")
+		refs[rp.PropertyName] = node
+	}
+	return refs
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+func makeSchemaRT(t *testing.T, slug, schema string) *entities.ResourceType {
+	t.Helper()
+	rt := &entities.ResourceType{}
+	if err := rt.Restore("id-"+slug, slug, slug, "", "active",
+		nil, json.RawMessage(schema), time.Now(), 1); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	return rt
+}
+
+func TestValidateComputedProperties(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{
+			name: "string concatenation",
+			schema: `{"properties":{"givenName":{"type":"string"},"familyName":{"type":"string"},
+				"name":{"type":"string","x-computed":"givenName + ' ' + familyName"}}}`,
+		},
+		{
+			name: "integer arithmetic stored in a number",
+			schema: `{"properties":{"quantity":{"type":"integer"},
+				"total":{"type":"number","x-computed":"quantity * 2"}}}`,
+		},
+		{
+			name:    "syntax error",
+			schema:  `{"properties":{"name":{"type":"string","x-computed":"givenName +"}}}`,
+			wantErr: "invalid x-computed expression",
+		},
+		{
+			name:    "unknown property",
+			schema:  `{"properties":{"name":{"type":"string","x-computed":"nickname"}}}`,
+			wantErr: "invalid x-computed expression",
+		},
+		{
+			name: "wrong result type",
+			schema: `{"properties":{"givenName":{"type":"string"},
+				"age":{"type":"integer","x-computed":"givenName"}}}`,
+			wantErr: "returns string",
+		},
+		{
+			name:    "empty expression",
+			schema:  `{"properties":{"name":{"type":"string","x-computed":""}}}`,
+			wantErr: "must not be empty",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateComputedProperties(json.RawMessage(tc.schema))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want ErrValidation containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyComputedProperties(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "order", `{"properties":{
+		"quantity":{"type":"integer"},
+		"unitPrice":{"type":"number"},
+		"discount":{"type":"number"},
+		"total":{"type":"number","x-computed":"double(quantity) * unitPrice - discount"},
+		"label":{"type":"string","x-computed":"string(quantity) + ' items'"}
+	}}`)
+	svc := &resourceService{logger: noopLogger{}}
+
+	// discount is absent and binds to 0; the client's total is overridden.
+	out, err := svc.applyComputedProperties(context.Background(), rt,
+		json.RawMessage(`{"quantity":3,"unitPrice":2.5,"total":999}`))
+	if err != nil {
+		t.Fatalf("applyComputedProperties: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got["total"] != 7.5 {
+		t.Errorf("total = %v, want 7.5", got["total"])
+	}
+	if got["label"] != "3 items" {
+		t.Errorf("label = %v, want \"3 items\"", got["label"])
+	}
+}
+
+func TestApplyComputedProperties_EvaluationErrorIsValidation(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "ratio", `{"properties":{
+		"a":{"type":"integer"},"b":{"type":"integer"},
+		"ratio":{"type":"integer","x-computed":"a / b"}
+	}}`)
+	svc := &resourceService{logger: noopLogger{}}
+
+	_, err := svc.applyComputedProperties(context.Background(), rt, json.RawMessage(`{"a":1,"b":0}`))
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation for division by zero", err)
+	}
+}
+
+// refLookupRepo serves FindByID from a fixed set of resources.
+type refLookupRepo struct {
+	repositories.ResourceRepository
+	byID map[string]*entities.Resource
+}
+
+func (r *refLookupRepo) FindByID(_ context.Context, id string) (*entities.Resource, error) {
+	if e, ok := r.byID[id]; ok {
+		return e, nil
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func TestApplyComputedProperties_ReadsReferencedResources(t *testing.T) {
+	t.Parallel()
+	customer, err := new(entities.Resource).With("urn:customer:1", "customer",
+		json.RawMessage(`{"@graph":[{"@id":"urn:customer:1","name":"Ada"}]}`), "", "")
+	if err != nil {
+		t.Fatalf("With: %v", err)
+	}
+	rt := makeSchemaRT(t, "invoice", `{"properties":{
+		"customer":{"type":"string","x-resource-type":"customer"},
+		"title":{"type":"string","x-computed":"'Invoice for ' + refs.customer.name"}
+	}}`)
+	svc := &resourceService{
+		repo:   &refLookupRepo{byID: map[string]*entities.Resource{"urn:customer:1": customer}},
+		logger: noopLogger{},
+	}
+
+	out, err := svc.applyComputedProperties(context.Background(), rt,
+		json.RawMessage(`{"customer":"urn:customer:1"}`))
+	if err != nil {
+		t.Fatalf("applyComputedProperties: %v", err)
+	}
+	var got map[string]any
+	_ = json.Unmarshal(out, &got)
+	if got["title"] != "Invoice for Ada" {
+		t.Errorf("title = %v, want \"Invoice for Ada\"", got["title"])
+	}
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Errors(t *testing.T) {
+	t.Parallel()
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@fortnightly",
+	} {
+		if _, err := parseCron(expr); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	t.Parallel()
+	// Friday 2026-05-01 10:07:30 UTC.
+	from := time.Date(2026, 5, 1, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 5, 1, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 5, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 5, 2, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 5, 1, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2026, 5, 4, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"10,40 10 * * *", time.Date(2026, 5, 1, 10, 10, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matching is enough.
+		{"0 0 15 * 0", time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCronSchedule_NextIsStrictlyAfter(t *testing.T) {
+	t.Parallel()
+	s, _ := parseCron("0 3 * * *")
+	at := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	if got := s.Next(at); !got.Equal(at.AddDate(0, 0, 1)) {
+		t.Errorf("Next at a firing time = %v, want the following day", got)
+	}
+}
@@ -0,0 +1,158 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// Editorial states with a fixed meaning in an x-editorial workflow. Any
+// other states (draft, in-review, ...) are ordinary workflow states.
+const (
+	editorialPublished   = "published"
+	editorialUnpublished = "unpublished"
+	editorialScheduled   = "scheduled"
+)
+
+// editorial is the compiled x-editorial annotation of one schema.
+type editorial struct {
+	// publishAt names the date-time property a scheduled resource is
+	// published at.
+	publishAt string
+	workflow  *workflow
+}
+
+func (e *editorial) schedules() bool {
+	return slices.Contains(e.workflow.states, editorialScheduled)
+}
+
+// editorialCache holds each type's compiled annotation, nil for types
+// without x-editorial.
+var editorialCache schemaCache[*editorial]
+
+// editorialOf returns the compiled x-editorial of rt, or nil when it has none.
+func editorialOf(rt *entities.ResourceType) (*editorial, error) {
+	return editorialCache.get(rt, func(rt *entities.ResourceType) (*editorial, error) {
+		return compileEditorial(rt.Schema())
+	})
+}
+
+// compileEditorial reads the root-level x-editorial annotation, which marks a
+// type's x-workflow as an editorial one:
+//
+//	"x-editorial": {"publishAt": "datePublished"}
+//
+// "x-editorial": true uses the defaults. The workflow must have published
+// and unpublished states; entering published makes the resource's current
+// data its public revision, and leaving it withdraws that revision. With a
+// scheduled state, the scheduler publishes resources once their publishAt
+// time (default datePublished) has passed. It returns nil when the schema
+// has no x-editorial. Errors wrap ErrValidation.
+func compileEditorial(schema json.RawMessage) (*editorial, error) {
+	if len(schema) == 0 {
+		return nil, nil
+	}
+	var s struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		XEditorial json.RawMessage            `json:"x-editorial"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, nil // the jsonschema compiler reports malformed schemas
+	}
+	raw := bytes.TrimSpace(s.XEditorial)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("false")) || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	ed := &editorial{publishAt: "datePublished"}
+	if !bytes.Equal(raw, []byte("true")) {
+		var opts struct {
+			PublishAt string `json:"publishAt"`
+		}
+		if err := json.Unmarshal(raw, &opts); err != nil {
+			return nil, fmt.Errorf("x-editorial must be true or an object: %w", ErrValidation)
+		}
+		if opts.PublishAt != "" {
+			ed.publishAt = opts.PublishAt
+		}
+	}
+	wf, err := compileWorkflow(schema)
+	if err != nil {
+		return nil, err
+	}
+	if wf == nil {
+		return nil, fmt.Errorf("x-editorial requires an x-workflow: %w", ErrValidation)
+	}
+	for _, state := range []string{editorialPublished, editorialUnpublished} {
+		if !slices.Contains(wf.states, state) {
+			return nil, fmt.Errorf("x-editorial: the workflow needs a %q state: %w", state, ErrValidation)
+		}
+	}
+	ed.workflow = wf
+	if ed.schedules() {
+		if _, ok := s.Properties[ed.publishAt]; !ok {
+			return nil, fmt.Errorf("x-editorial: publishAt property %q is not in the schema: %w",
+				ed.publishAt, ErrValidation)
+		}
+		// The scheduler finds scheduled resources through the projection,
+		// where "status" is the resource's own lifecycle column.
+		if wf.property == "status" {
+			return nil, fmt.Errorf(
+				"x-editorial: a workflow with a scheduled state can't use the status property: %w",
+				ErrValidation)
+		}
+	}
+	return ed, nil
+}
+
+// validateEditorial rejects an x-editorial annotation whose workflow lacks
+// the editorial states. Called when a ResourceType is created or updated.
+func validateEditorial(schema json.RawMessage) error {
+	_, err := compileEditorial(schema)
+	return err
+}
+
+// recordEditorialEvents records the public revision changes a workflow
+// transition implies for an x-editorial type: entering published (again)
+// publishes the resource's current data, and leaving published withdraws it.
+func recordEditorialEvents(
+	entity *entities.Resource, rt *entities.ResourceType, t *workflowTransition, from string,
+) error {
+	ed, err := editorialOf(rt)
+	if err != nil || ed == nil {
+		return err
+	}
+	switch {
+	case t.to == editorialPublished:
+		ev := entities.ResourceRevisionPublished{}.With(entity.TypeSlug(), entity.AccountID(), entity.Data())
+		if err := entity.RecordEvent(ev, ev.EventType()); err != nil {
+			return fmt.Errorf("failed to record revision published event: %w", err)
+		}
+	case from == editorialPublished:
+		ev := entities.ResourceRevisionWithdrawn{}.With(entity.TypeSlug())
+		if err := entity.RecordEvent(ev, ev.EventType()); err != nil {
+			return fmt.Errorf("failed to record revision withdrawn event: %w", err)
+		}
+	}
+	return nil
+}
+
+// publishAtTime parses the publishAt property of flat resource data. Both
+// RFC 3339 date-times and plain dates are accepted.
+func (e *editorial) publishAtTime(data map[string]any) (time.Time, bool) {
+	v, _ := data[e.publishAt].(string)
+	if v == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,247 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"go.uber.org/fx"
+)
+
+// EditorialService publishes and unpublishes resources of x-editorial types
+// and serves their published revisions. A published revision is a copy of
+// the resource's data taken when it entered the published state; later edits
+// change only the working draft until the resource is published again.
+type EditorialService interface {
+	// Publish moves the resource to published, or to scheduled when the type
+	// has a scheduled state and the resource's publishAt time is in the future.
+	Publish(ctx context.Context, id string) (*entities.Resource, error)
+	// Unpublish moves the resource to unpublished, withdrawing its public revision.
+	Unpublish(ctx context.Context, id string) (*entities.Resource, error)
+	// PublishDue publishes every scheduled resource whose publishAt time is at
+	// or before now and returns how many were published.
+	PublishDue(ctx context.Context, now time.Time) (int, error)
+	// GetPublished and ListPublished serve the published revisions of one
+	// account, the site they belong to. They take no identity: published
+	// revisions are public.
+	GetPublished(ctx context.Context, accountID, typeSlug, id string) (*entities.PublishedRevision, error)
+	ListPublished(ctx context.Context, accountID, typeSlug, cursor string, limit int) (
+		repositories.PaginatedResponse[*entities.PublishedRevision], error)
+}
+
+type editorialService struct {
+	resources ResourceService
+	repo      repositories.ResourceRepository
+	typeRepo  repositories.ResourceTypeRepository
+	published repositories.PublishedRevisionRepository
+	logger    entities.Logger
+}
+
+func ProvideEditorialService(params struct {
+	fx.In
+	Resources ResourceService
+	Repo      repositories.ResourceRepository
+	TypeRepo  repositories.ResourceTypeRepository
+	Published repositories.PublishedRevisionRepository
+	Logger    entities.Logger
+}) EditorialService {
+	return &editorialService{
+		resources: params.Resources,
+		repo:      params.Repo,
+		typeRepo:  params.TypeRepo,
+		published: params.Published,
+		logger:    params.Logger,
+	}
+}
+
+// editorialFor loads the resource with the caller's access check, and the
+// editorial config of its type.
+func (s *editorialService) editorialFor(
+	ctx context.Context, id string,
+) (*entities.Resource, *entities.ResourceType, *editorial, error) {
+	entity, err := s.resources.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rt, err := s.typeRepo.FindBySlug(ctx, entity.TypeSlug())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("resource type %q not found: %w", entity.TypeSlug(), err)
+	}
+	ed, err := editorialOf(rt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if ed == nil {
+		return nil, nil, nil, fmt.Errorf("resource type %q has no editorial workflow: %w",
+			rt.Slug(), ErrValidation)
+	}
+	return entity, rt, ed, nil
+}
+
+// moveTo triggers the first declared transition from the resource's current
+// state to target.
+func (s *editorialService) moveTo(
+	ctx context.Context, entity *entities.Resource, ed *editorial, current, target string,
+) (*entities.Resource, error) {
+	for _, t := range ed.workflow.transitions {
+		if t.to == target && t.leavesFrom(current) {
+			return s.resources.Transition(ctx, TransitionResourceCommand{ID: entity.GetID(), Name: t.name})
+		}
+	}
+	return nil, fmt.Errorf("no transition from %q to %q: %w", current, target, ErrValidation)
+}
+
+func (s *editorialService) Publish(ctx context.Context, id string) (*entities.Resource, error) {
+	entity, rt, ed, err := s.editorialFor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(FlattenGraph(entity.Data(), rt.Context()), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode resource data: %w", err)
+	}
+	target := editorialPublished
+	if ed.schedules() {
+		if at, ok := ed.publishAtTime(data); ok && at.After(time.Now()) {
+			target = editorialScheduled
+		}
+	}
+	return s.moveTo(ctx, entity, ed, ed.workflow.workflowState(data), target)
+}
+
+func (s *editorialService) Unpublish(ctx context.Context, id string) (*entities.Resource, error) {
+	entity, rt, ed, err := s.editorialFor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(FlattenGraph(entity.Data(), rt.Context()), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode resource data: %w", err)
+	}
+	return s.moveTo(ctx, entity, ed, ed.workflow.workflowState(data), editorialUnpublished)
+}
+
+// PublishDue runs in the system context. A resource that fails to publish
+// (a guard no longer passes, say) is logged and left scheduled.
+func (s *editorialService) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	published := 0
+	cursor := ""
+	for {
+		page, err := s.typeRepo.FindAll(ctx, cursor, 100)
+		if err != nil {
+			return published, fmt.Errorf("failed to list resource types: %w", err)
+		}
+		for _, rt := range page.Data {
+			ed, err := editorialOf(rt)
+			if err != nil || ed == nil || !ed.schedules() {
+				continue
+			}
+			n, err := s.publishDueOfType(ctx, rt, ed, now)
+			published += n
+			if err != nil {
+				return published, err
+			}
+		}
+		if !page.HasMore || page.Cursor == "" {
+			return published, nil
+		}
+		cursor = page.Cursor
+	}
+}
+
+func (s *editorialService) publishDueOfType(
+	ctx context.Context, rt *entities.ResourceType, ed *editorial, now time.Time,
+) (int, error) {
+	filters := []repositories.FilterCondition{
+		{Field: ed.workflow.property, Operator: "eq", Value: editorialScheduled},
+	}
+	var due []*entities.Resource
+	cursor := ""
+	for {
+		page, err := s.repo.FindAllByTypeWithFilters(ctx, rt.Slug(), filters, cursor, 100,
+			repositories.SortOptions{}, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list scheduled %s resources: %w", rt.Slug(), err)
+		}
+		for _, entity := range page.Data {
+			var data map[string]any
+			if json.Unmarshal(FlattenGraph(entity.Data(), rt.Context()), &data) != nil {
+				continue
+			}
+			if at, ok := ed.publishAtTime(data); ok && !at.After(now) {
+				due = append(due, entity)
+			}
+		}
+		if !page.HasMore || page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	published := 0
+	for _, entity := range due {
+		if _, err := s.moveTo(ctx, entity, ed, editorialScheduled, editorialPublished); err != nil {
+			s.logger.Error(ctx, "failed to publish scheduled resource",
+				"resourceID", entity.GetID(), "typeSlug", rt.Slug(), "error", err)
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+func (s *editorialService) GetPublished(
+	ctx context.Context, accountID, typeSlug, id string,
+) (*entities.PublishedRevision, error) {
+	if accountID == "" {
+		return nil, fmt.Errorf("an account is required: %w", ErrValidation)
+	}
+	rev, err := s.published.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rev.TypeSlug != typeSlug || rev.AccountID != accountID {
+		return nil, fmt.Errorf("published %s %q: %w", typeSlug, id, repositories.ErrNotFound)
+	}
+	fields, err := s.encryptedFields(ctx, typeSlug)
+	if err != nil {
+		return nil, err
+	}
+	rev.Data = withoutEncrypted(fields, rev.Data)
+	return rev, nil
+}
+
+func (s *editorialService) ListPublished(
+	ctx context.Context, accountID, typeSlug, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.PublishedRevision], error) {
+	if accountID == "" {
+		return repositories.PaginatedResponse[*entities.PublishedRevision]{},
+			fmt.Errorf("an account is required: %w", ErrValidation)
+	}
+	page, err := s.published.FindAllByType(ctx, accountID, typeSlug, cursor, limit)
+	if err != nil {
+		return page, err
+	}
+	fields, err := s.encryptedFields(ctx, typeSlug)
+	if err != nil {
+		return page, err
+	}
+	for _, rev := range page.Data {
+		rev.Data = withoutEncrypted(fields, rev.Data)
+	}
+	return page, nil
+}
+
+// encryptedFields returns the x-encrypted properties of typeSlug. Published
+// revisions are public, so they are served without them.
+func (s *editorialService) encryptedFields(ctx context.Context, typeSlug string) (encryptedFields, error) {
+	rt, err := s.typeRepo.FindBySlug(ctx, typeSlug)
+	if err != nil {
+		return encryptedFields{}, fmt.Errorf("resource type %q not found: %w", typeSlug, err)
+	}
+	return encryptedFieldsOf(rt.Schema()), nil
+}
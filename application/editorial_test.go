@@ -0,0 +1,133 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const editorialTestSchema = `{
+	"type": "object",
+	"properties": {
+		"headline": {"type": "string"},
+		"datePublished": {"type": "string"},
+		"state": {"type": "string"}
+	},
+	"x-workflow": {
+		"property": "state",
+		"initial": "draft",
+		"states": ["draft", "scheduled", "published", "unpublished"],
+		"transitions": [
+			{"name": "publish", "from": ["draft", "scheduled", "published", "unpublished"], "to": "published"},
+			{"name": "schedule", "from": ["draft"], "to": "scheduled"},
+			{"name": "unpublish", "from": ["published"], "to": "unpublished"}
+		]
+	},
+	"x-editorial": true
+}`
+
+func TestValidateEditorial(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "valid editorial workflow", schema: editorialTestSchema},
+		{name: "no annotation", schema: `{"properties":{"title":{"type":"string"}}}`},
+		{
+			name:    "no workflow",
+			schema:  `{"properties":{"status":{"type":"string"}},"x-editorial":true}`,
+			wantErr: "requires an x-workflow",
+		},
+		{
+			name: "missing unpublished state",
+			schema: `{"properties":{"status":{"type":"string"}},"x-editorial":true,
+				"x-workflow":{"states":["draft","published"]}}`,
+			wantErr: `needs a "unpublished" state`,
+		},
+		{
+			name: "unknown publishAt property",
+			schema: `{"properties":{"state":{"type":"string"}},"x-editorial":{"publishAt":"goLive"},
+				"x-workflow":{"property":"state","states":["draft","scheduled","published","unpublished"]}}`,
+			wantErr: `publishAt property "goLive"`,
+		},
+		{
+			name: "scheduling on the status property",
+			schema: `{"properties":{"status":{"type":"string"},"datePublished":{"type":"string"}},"x-editorial":true,
+				"x-workflow":{"states":["draft","scheduled","published","unpublished"]}}`,
+			wantErr: "can't use the status property",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateEditorial(json.RawMessage(tc.schema))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want ErrValidation containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecordEditorialEvents(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "article", editorialTestSchema)
+	ed, err := compileEditorial(rt.Schema())
+	if err != nil || ed == nil {
+		t.Fatalf("compileEditorial: %v, %v", ed, err)
+	}
+	byName := map[string]*workflowTransition{}
+	for i := range ed.workflow.transitions {
+		byName[ed.workflow.transitions[i].name] = &ed.workflow.transitions[i]
+	}
+
+	cases := []struct {
+		name       string
+		transition string
+		from       string
+		wantEvent  string
+	}{
+		{name: "publishing a draft", transition: "publish", from: "draft", wantEvent: "Resource.RevisionPublished"},
+		{name: "republishing", transition: "publish", from: "published", wantEvent: "Resource.RevisionPublished"},
+		{name: "unpublishing", transition: "unpublish", from: "published", wantEvent: "Resource.RevisionWithdrawn"},
+		{name: "scheduling", transition: "schedule", from: "draft"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			entity := workflowResource(t, tc.from)
+			if err := recordEditorialEvents(entity, rt, byName[tc.transition], tc.from); err != nil {
+				t.Fatalf("recordEditorialEvents: %v", err)
+			}
+			events := entity.GetUncommittedEvents()
+			got := ""
+			if len(events) > 0 {
+				got = events[len(events)-1].EventType
+			}
+			if got != tc.wantEvent {
+				t.Errorf("recorded %q, want %q", got, tc.wantEvent)
+			}
+		})
+	}
+}
+
+func TestEditorialPublishAtTime(t *testing.T) {
+	t.Parallel()
+	ed := &editorial{publishAt: "datePublished"}
+	for _, v := range []string{"2026-05-01T09:00:00Z", "2026-05-01"} {
+		if _, ok := ed.publishAtTime(map[string]any{"datePublished": v}); !ok {
+			t.Errorf("publishAtTime(%q) did not parse", v)
+		}
+	}
+	if _, ok := ed.publishAtTime(map[string]any{"datePublished": "next week"}); ok {
+		t.Error("publishAtTime parsed an invalid date")
+	}
+}
@@ -0,0 +1,480 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"go.uber.org/fx"
+)
+
+// EventHandlerGroup is a named set of event handlers with its own checkpoint
+// in the event log. Inline groups run in Commit, before the request returns;
+// async groups are run by the EventRelay after the commit. Either way the
+// relay catches a group up on events it missed (the process died, or a
+// handler failed), so handlers must be idempotent.
+type EventHandlerGroup struct {
+	Name string
+	// Async moves the group out of Commit and onto the event relay.
+	Async bool
+	// MaxAttempts bounds how often the relay tries an event the group fails
+	// on before it dead-letters the event and moves on. Default 10.
+	MaxAttempts int
+	// Subscribe registers the group's handlers with the group's own
+	// dispatcher. Handlers replayed by the relay get the payload type they
+	// subscribed with; handlers of EventEnvelope[any] get the payload as
+	// stored (map[string]any).
+	Subscribe func(d *domain.EventDispatcher) error
+
+	dispatcher *domain.EventDispatcher
+}
+
+// handle runs one stored event through the group's handlers.
+func (g *EventHandlerGroup) handle(ctx context.Context, env domain.EventEnvelope[any]) error {
+	return g.dispatcher.Dispatch(ctx, typedStoredEvent(ctx, g.dispatcher, env))
+}
+
+// maxAttempts is MaxAttempts, defaulted.
+func (g *EventHandlerGroup) maxAttempts() int {
+	if g.MaxAttempts > 0 {
+		return g.MaxAttempts
+	}
+	return defaultEventMaxAttempts
+}
+
+// typedStoredEvent decodes a stored event's payload into the type the
+// dispatcher's handlers for that event type subscribed with. Events without
+// a typed subscription keep their map payload.
+func typedStoredEvent(
+	ctx context.Context, d *domain.EventDispatcher, env domain.EventEnvelope[any],
+) domain.EventEnvelope[any] {
+	payload, err := json.Marshal(env.Payload)
+	if err != nil {
+		return env
+	}
+	data, err := json.Marshal(map[string]any{
+		"payload":   json.RawMessage(payload),
+		"timestamp": env.Created.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return env
+	}
+	typed, err := d.UnmarshalEvent(ctx, data, env.EventType)
+	if err != nil {
+		return env
+	}
+	// The dispatcher decodes into a new(T); handlers assert T.
+	if v := reflect.ValueOf(typed.Payload); v.Kind() == reflect.Pointer && !v.IsNil() {
+		env.Payload = v.Elem().Interface()
+	}
+	return env
+}
+
+// PresetEventHandler declares an event handler group contributed by a
+// preset. Factory is invoked once at startup with the same services
+// behaviors receive; the handler it returns is subscribed to each of Events.
+type PresetEventHandler struct {
+	Name string
+	// Events are dispatcher patterns, such as "Resource.Updated" or "Resource.*".
+	Events      []string
+	Async       bool
+	MaxAttempts int
+	// Factory returns the handler. Inline it sees the event's typed payload;
+	// replayed by the relay it sees the stored map[string]any. Decoding
+	// through JSON handles both.
+	Factory func(BehaviorServices) domain.EventHandler[any]
+}
+
+// validateEventHandlers enforces the per-preset invariants on
+// PresetDefinition.EventHandlers: a name, events, a factory, and no
+// duplicate names.
+func validateEventHandlers(def PresetDefinition) error {
+	seen := make(map[string]struct{}, len(def.EventHandlers))
+	for i, h := range def.EventHandlers {
+		if h.Name == "" {
+			return fmt.Errorf("preset %q: eventHandlers[%d] name is empty", def.Name, i)
+		}
+		if len(h.Events) == 0 {
+			return fmt.Errorf("preset %q: event handler %q has no events", def.Name, h.Name)
+		}
+		if h.Factory == nil {
+			return fmt.Errorf("preset %q: event handler %q factory is nil", def.Name, h.Name)
+		}
+		if _, dup := seen[h.Name]; dup {
+			return fmt.Errorf("preset %q: event handler %q is declared twice", def.Name, h.Name)
+		}
+		seen[h.Name] = struct{}{}
+	}
+	return nil
+}
+
+// EventHandlerRegistry maps group names to their groups.
+type EventHandlerRegistry map[string]*EventHandlerGroup
+
+// Add subscribes the group's handlers to a dispatcher of its own and
+// registers it, failing on an empty name, a missing Subscribe, or a name
+// that is already taken.
+func (r EventHandlerRegistry) Add(group EventHandlerGroup) error {
+	if group.Name == "" {
+		return fmt.Errorf("event handler group name must not be empty")
+	}
+	if group.Subscribe == nil {
+		return fmt.Errorf("event handler group %q: Subscribe is nil", group.Name)
+	}
+	if _, dup := r[group.Name]; dup {
+		return fmt.Errorf("event handler group %q is registered twice", group.Name)
+	}
+	group.dispatcher = domain.NewEventDispatcher()
+	if err := group.Subscribe(group.dispatcher); err != nil {
+		return fmt.Errorf("event handler group %q: %w", group.Name, err)
+	}
+	r[group.Name] = &group
+	return nil
+}
+
+// sorted returns the groups in name order.
+func (r EventHandlerRegistry) sorted() []*EventHandlerGroup {
+	groups := make([]*EventHandlerGroup, 0, len(r))
+	for _, g := range r {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// ProvideEventHandlerRegistry builds the registry from the built-in
+// projection, webhook and audit groups and every preset's EventHandlers.
+func ProvideEventHandlerRegistry(params struct {
+	fx.In
+	Registry      *PresetRegistry
+	EventStore    domain.EventStore
+	RTRepo        repositories.ResourceTypeRepository
+	ResourceRepo  repositories.ResourceRepository
+	TripleRepo    repositories.TripleRepository
+	ProjMgr       repositories.ProjectionManager
+	Published     repositories.PublishedRevisionRepository
+	Subscriptions repositories.WebhookSubscriptionRepository
+	Deliveries    repositories.WebhookDeliveryRepository
+	Audit         repositories.AuditRepository
+	Assignments   repositories.RoleAssignmentRepository
+	Jobs          JobService
+	Writer        *lazyResourceWriter
+	Replayer      *ResourceReplayer
+	Logger        entities.Logger
+}) (EventHandlerRegistry, error) {
+	notifier := &webhookNotifier{
+		eventStore: params.EventStore,
+		subs:       params.Subscriptions,
+		deliveries: params.Deliveries,
+		jobs:       params.Jobs,
+		logger:     params.Logger,
+	}
+	registry := EventHandlerRegistry{}
+	for _, group := range []EventHandlerGroup{
+		{
+			Name: "projections.resource-types",
+			Subscribe: func(d *domain.EventDispatcher) error {
+				return subscribeResourceTypeHandlers(d, params.RTRepo, params.ProjMgr, params.Logger)
+			},
+		},
+		{
+			Name: "projections.resources",
+			Subscribe: func(d *domain.EventDispatcher) error {
+				return subscribeResourceHandlers(d, params.EventStore, params.ResourceRepo,
+					params.ProjMgr, params.Logger)
+			},
+		},
+		{
+			Name: "projections.triples",
+			Subscribe: func(d *domain.EventDispatcher) error {
+				return subscribeTripleHandlers(d, params.TripleRepo, params.Logger)
+			},
+		},
+		{
+			Name: "projections.editorial",
+			Subscribe: func(d *domain.EventDispatcher) error {
+				return subscribeEditorialHandlers(d, params.Published, params.Logger)
+			},
+		},
+		{
+			Name: "role-assignments",
+			Subscribe: func(d *domain.EventDispatcher) error {
+				return subscribeRoleAssignmentHandlers(d, params.Assignments, params.Logger)
+			},
+		},
+		{
+			Name:        "webhooks",
+			Async:       true,
+			MaxAttempts: webhookMaxAttempts,
+			Subscribe:   notifier.subscribe,
+		},
+		{
+			Name:      "audit",
+			Async:     true,
+			Subscribe: (&auditRecorder{audit: params.Audit}).subscribe,
+		},
+	} {
+		if err := registry.Add(group); err != nil {
+			return nil, err
+		}
+	}
+	if params.Replayer.every > 0 {
+		if err := registry.Add(EventHandlerGroup{
+			Name:      "snapshots",
+			Async:     true,
+			Subscribe: params.Replayer.subscribe,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	services := BehaviorServices{
+		Resources:     params.ResourceRepo,
+		Triples:       params.TripleRepo,
+		ResourceTypes: params.RTRepo,
+		Logger:        params.Logger,
+		Writer:        params.Writer,
+	}
+	for _, preset := range params.Registry.List() {
+		for _, ph := range preset.EventHandlers {
+			handler := ph.Factory(services)
+			if handler == nil {
+				return nil, fmt.Errorf("preset %q: event handler %q factory returned nil", preset.Name, ph.Name)
+			}
+			events := ph.Events
+			err := registry.Add(EventHandlerGroup{
+				Name:        ph.Name,
+				Async:       ph.Async,
+				MaxAttempts: ph.MaxAttempts,
+				Subscribe: func(d *domain.EventDispatcher) error {
+					for _, pattern := range events {
+						if err := domain.Subscribe(d, pattern, handler); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("preset %q: %w", preset.Name, err)
+			}
+		}
+	}
+	return registry, nil
+}
+
+// startEventHandlerGroups gives every group a checkpoint and routes committed
+// events to the inline groups. A group seen for the first time starts at
+// the head of the event log; it is not replayed history it never handled.
+func startEventHandlerGroups(params struct {
+	fx.In
+	Lifecycle   fx.Lifecycle
+	Dispatcher  *domain.EventDispatcher
+	Registry    EventHandlerRegistry
+	Log         repositories.EventLogRepository
+	Checkpoints repositories.EventCheckpointRepository
+	Logger      entities.Logger
+}) error {
+	ctx := context.Background()
+	head, err := params.Log.Head(ctx)
+	if err != nil {
+		return err
+	}
+	router := &inlineEventRouter{
+		log:         params.Log,
+		checkpoints: params.Checkpoints,
+		logger:      params.Logger,
+	}
+	for _, g := range params.Registry.sorted() {
+		if _, err := params.Checkpoints.Ensure(ctx, g.Name, head); err != nil {
+			return err
+		}
+		if !g.Async {
+			router.groups = append(router.groups, g)
+		}
+	}
+	params.Lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			router.flush(ctx)
+			return nil
+		},
+	})
+	return params.Dispatcher.SubscribeWildcard(router.dispatch)
+}
+
+// inlineEventRouter runs committed events through the inline groups, one
+// group after another in registry order, and moves each group's checkpoint
+// past the events it handled once every event before them is handled too.
+// Checkpoints are advanced in batches, not per event; anything a batch
+// can't account for is left to the relay.
+type inlineEventRouter struct {
+	groups      []*EventHandlerGroup
+	log         repositories.EventLogRepository
+	checkpoints repositories.EventCheckpointRepository
+	logger      entities.Logger
+
+	mu    sync.Mutex
+	batch []inlineOutcome
+	timer *time.Timer
+
+	// flushMu serialises flushes. A commit spanning several aggregates may
+	// dispatch its events in a different order than it logged them, so
+	// positions handled ahead of the checkpoint wait in handled until the
+	// ones before them are in.
+	flushMu sync.Mutex
+	handled map[string]map[int64]bool
+}
+
+// inlineOutcome is one dispatched event and the groups that failed on it.
+type inlineOutcome struct {
+	eventID string
+	failed  map[string]bool
+}
+
+const (
+	// maxInlineHandled bounds the positions a group may hold ahead of its
+	// checkpoint. Past it they are dropped and the relay catches the group
+	// up.
+	maxInlineHandled = 1000
+	// inlineFlushDelay is how long handled events wait before their
+	// checkpoints are advanced, and inlineFlushSize how many may wait
+	// before a flush is started early.
+	inlineFlushDelay = time.Second
+	inlineFlushSize  = 500
+)
+
+func (r *inlineEventRouter) dispatch(ctx context.Context, env domain.EventEnvelope[any]) error {
+	if len(r.groups) == 0 {
+		return nil
+	}
+	var errs []error
+	outcome := inlineOutcome{eventID: env.ID}
+	for _, g := range r.groups {
+		if err := g.dispatcher.Dispatch(ctx, env); err != nil {
+			r.logger.Error(ctx, "event handler group failed; the event relay will retry",
+				"group", g.Name, "eventID", env.ID, "eventType", env.EventType, "error", err)
+			if outcome.failed == nil {
+				outcome.failed = map[string]bool{}
+			}
+			outcome.failed[g.Name] = true
+			errs = append(errs, err)
+		}
+	}
+	r.record(outcome)
+	return errors.Join(errs...)
+}
+
+// record queues an outcome for the next flush, scheduling one when the
+// queue was empty and starting one now when it is full.
+func (r *inlineEventRouter) record(outcome inlineOutcome) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batch = append(r.batch, outcome)
+	switch {
+	case len(r.batch) >= inlineFlushSize:
+		if r.timer != nil {
+			r.timer.Stop()
+			r.timer = nil
+		}
+		go r.flush(context.Background())
+	case r.timer == nil:
+		r.timer = time.AfterFunc(inlineFlushDelay, func() { r.flush(context.Background()) })
+	}
+}
+
+// flush looks up the positions of the queued events in one query and moves
+// each inline group's checkpoint over the contiguous positions it handled,
+// with one write per group.
+func (r *inlineEventRouter) flush(ctx context.Context) {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.mu.Lock()
+	batch := r.batch
+	r.batch = nil
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	r.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+
+	ids := make([]string, len(batch))
+	for i, o := range batch {
+		ids[i] = o.eventID
+	}
+	positions, err := r.log.PositionsOf(ctx, ids)
+	if err != nil {
+		r.logger.Error(ctx, "failed to look up event log positions; leaving them to the event relay",
+			"events", len(ids), "error", err)
+		return
+	}
+	if r.handled == nil {
+		r.handled = map[string]map[int64]bool{}
+	}
+	for _, o := range batch {
+		position, ok := positions[o.eventID]
+		if !ok {
+			r.logger.Warn(ctx, "event is not in the event log; leaving it to the event relay",
+				"eventID", o.eventID)
+			continue
+		}
+		for _, g := range r.groups {
+			if o.failed[g.Name] {
+				continue
+			}
+			pending := r.handled[g.Name]
+			if pending == nil {
+				pending = map[int64]bool{}
+				r.handled[g.Name] = pending
+			}
+			pending[position] = true
+		}
+	}
+
+	checkpoints, err := r.checkpoints.FindAll(ctx)
+	if err != nil {
+		r.logger.Error(ctx, "failed to load event checkpoints", "error", err)
+		return
+	}
+	at := make(map[string]int64, len(checkpoints))
+	for _, cp := range checkpoints {
+		at[cp.Group] = cp.Position
+	}
+	for _, g := range r.groups {
+		pending := r.handled[g.Name]
+		from, ok := at[g.Name]
+		if len(pending) == 0 || !ok {
+			continue
+		}
+		to := from
+		for pending[to+1] {
+			to++
+		}
+		if to > from {
+			if _, err := r.checkpoints.Advance(ctx, g.Name, from, to, 0, ""); err != nil {
+				r.logger.Error(ctx, "failed to advance event checkpoint", "group", g.Name, "error", err)
+				continue
+			}
+		}
+		for p := range pending {
+			if p <= to {
+				delete(pending, p)
+			}
+		}
+		if len(pending) > maxInlineHandled {
+			clear(pending)
+		}
+	}
+}
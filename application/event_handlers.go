@@ -5,45 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/wepala/weos/v3/domain/entities"
 	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/identity"
 	"github.com/wepala/weos/v3/pkg/jsonld"
 
 	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
-	"go.uber.org/fx"
 )
 
-// subscribeEventHandlers registers all projection event handlers with the dispatcher.
-func subscribeEventHandlers(params struct {
-	fx.In
-	Dispatcher   *domain.EventDispatcher
-	EventStore   domain.EventStore
-	RTRepo       repositories.ResourceTypeRepository
-	ResourceRepo repositories.ResourceRepository
-	TripleRepo   repositories.TripleRepository
-	ProjMgr      repositories.ProjectionManager
-	Logger       entities.Logger
-}) error {
-	if err := subscribeResourceTypeHandlers(
-		params.Dispatcher, params.RTRepo, params.ProjMgr, params.Logger,
-	); err != nil {
-		return fmt.Errorf("resource type handlers: %w", err)
-	}
-	if err := subscribeResourceHandlers(
-		params.Dispatcher, params.EventStore, params.ResourceRepo, params.ProjMgr, params.Logger,
-	); err != nil {
-		return fmt.Errorf("resource handlers: %w", err)
-	}
-	if err := subscribeTripleHandlers(
-		params.Dispatcher, params.TripleRepo, params.Logger,
-	); err != nil {
-		return fmt.Errorf("triple handlers: %w", err)
-	}
-	return nil
-}
-
 // --- ResourceType projection handlers ---
 
 func subscribeResourceTypeHandlers(
@@ -101,6 +73,7 @@ func subscribeResourceTypeHandlers(
 					"slug", p.Slug, "error", err)
 				return err
 			}
+			inheritedAncestors.clear()
 			logger.Info(ctx, "projecting ResourceType.Updated", "id", env.AggregateID)
 			return nil
 		},
@@ -173,6 +146,83 @@ func subscribeResourceHandlers(
 	)
 }
 
+// --- Editorial read model handlers ---
+
+// subscribeEditorialHandlers maintains the published_revisions read model
+// that public reads are served from.
+func subscribeEditorialHandlers(
+	d *domain.EventDispatcher,
+	repo repositories.PublishedRevisionRepository,
+	logger entities.Logger,
+) error {
+	if err := domain.Subscribe(d, "Resource.RevisionPublished",
+		func(ctx context.Context, env domain.EventEnvelope[entities.ResourceRevisionPublished]) error {
+			logger.Info(ctx, "projecting Resource.RevisionPublished", "id", env.AggregateID)
+			p := env.Payload
+			return repo.Save(ctx, &entities.PublishedRevision{
+				ResourceID:  env.AggregateID,
+				TypeSlug:    p.TypeSlug,
+				AccountID:   p.AccountID,
+				Data:        p.Data,
+				PublishedAt: p.PublishedAt,
+			})
+		},
+	); err != nil {
+		return err
+	}
+	if err := domain.Subscribe(d, "Resource.RevisionWithdrawn",
+		func(ctx context.Context, env domain.EventEnvelope[entities.ResourceRevisionWithdrawn]) error {
+			logger.Info(ctx, "projecting Resource.RevisionWithdrawn", "id", env.AggregateID)
+			return repo.Delete(ctx, env.AggregateID)
+		},
+	); err != nil {
+		return err
+	}
+	// A deleted resource takes its public revision with it.
+	return domain.Subscribe(d, "Resource.Deleted",
+		func(ctx context.Context, env domain.EventEnvelope[entities.ResourceDeleted]) error {
+			return repo.Delete(ctx, env.AggregateID)
+		},
+	)
+}
+
+// --- Role assignment handlers ---
+
+// subscribeRoleAssignmentHandlers revokes the role assignments bound to a
+// scope that is deleted: a type scope with its resource type, and a subtree
+// scope with its root resource (SCOPE-04). Account- and global-scoped
+// assignments are untouched.
+func subscribeRoleAssignmentHandlers(
+	d *domain.EventDispatcher,
+	repo repositories.RoleAssignmentRepository,
+	logger entities.Logger,
+) error {
+	if err := domain.Subscribe(d, "ResourceType.Deleted",
+		func(ctx context.Context, env domain.EventEnvelope[entities.ResourceTypeDeleted]) error {
+			slug, ok := strings.CutPrefix(env.AggregateID, identity.NewResourceType(""))
+			if !ok {
+				return nil
+			}
+			n, err := repo.DeleteByType(ctx, slug)
+			if n > 0 {
+				logger.Info(ctx, "revoked role assignments of deleted type", "type", slug, "count", n)
+			}
+			return err
+		},
+	); err != nil {
+		return err
+	}
+	return domain.Subscribe(d, "Resource.Deleted",
+		func(ctx context.Context, env domain.EventEnvelope[entities.ResourceDeleted]) error {
+			n, err := repo.DeleteByResource(ctx, env.AggregateID)
+			if n > 0 {
+				logger.Info(ctx, "revoked role assignments of deleted subtree", "id", env.AggregateID, "count", n)
+			}
+			return err
+		},
+	)
+}
+
 // txResourceState holds the resource state built from transaction events.
 type txResourceState struct {
 	Data      json.RawMessage
@@ -195,62 +245,67 @@ func buildStateFromTransaction(
 ) txResourceState {
 	state := txResourceState{MaxSeq: baseSeq}
 	for _, e := range txEvents {
-		if e.AggregateID != aggregateID {
-			continue
-		}
-		if e.SequenceNo > state.MaxSeq {
-			state.MaxSeq = e.SequenceNo
+		if e.AggregateID == aggregateID {
+			state.apply(ctx, e, logger)
 		}
-		// Payloads from the event store are deserialized as map[string]any.
-		// Map keys use Go field names (PascalCase) for untagged fields,
-		// or json tag names (lowercase) for tagged fields like BasicTripleEvent.
-		m, ok := e.Payload.(map[string]any)
-		if !ok {
-			logger.Error(ctx, "unexpected event payload type",
-				"eventType", e.EventType, "payloadType", fmt.Sprintf("%T", e.Payload))
-			continue
+	}
+	return state
+}
+
+// apply folds one of the resource's stored events into the state.
+func (state *txResourceState) apply(ctx context.Context, e domain.EventEnvelope[any], logger entities.Logger) {
+	if e.SequenceNo > state.MaxSeq {
+		state.MaxSeq = e.SequenceNo
+	}
+	// Payloads from the event store are deserialized as map[string]any.
+	// Map keys use Go field names (PascalCase) for untagged fields,
+	// or json tag names (lowercase) for tagged fields like BasicTripleEvent.
+	m, ok := e.Payload.(map[string]any)
+	if !ok {
+		logger.Error(ctx, "unexpected event payload type",
+			"eventType", e.EventType, "payloadType", fmt.Sprintf("%T", e.Payload))
+		return
+	}
+	switch e.EventType {
+	case "Resource.Created":
+		state.IsCreate = true
+		state.TypeSlug, _ = m["TypeSlug"].(string)
+		state.Data = marshalField(m["Data"])
+		state.CreatedBy, _ = m["CreatedBy"].(string)
+		state.AccountID, _ = m["AccountID"].(string)
+		if ts, ok := m["Timestamp"].(string); ok {
+			state.CreatedAt, _ = time.Parse(time.RFC3339Nano, ts)
 		}
-		switch e.EventType {
-		case "Resource.Created":
-			state.IsCreate = true
-			state.TypeSlug, _ = m["TypeSlug"].(string)
-			state.Data = marshalField(m["Data"])
-			state.CreatedBy, _ = m["CreatedBy"].(string)
-			state.AccountID, _ = m["AccountID"].(string)
-			if ts, ok := m["Timestamp"].(string); ok {
-				state.CreatedAt, _ = time.Parse(time.RFC3339Nano, ts)
-			}
-		case "Resource.Updated":
-			state.Data = marshalField(m["Data"])
-		case "Resource.Deleted":
-			state.IsDelete = true
-		case "Triple.Created":
-			predicate, _ := m["predicate"].(string)
-			object, _ := m["object"].(string)
-			if predicate != "" && state.Data != nil {
-				updated, err := AddEdgeToGraph(state.Data, predicate, object, aggregateID)
-				if err != nil {
-					logger.Error(ctx, "failed to add edge to graph",
-						"subject", aggregateID, "predicate", predicate, "error", err)
-					continue
-				}
-				state.Data = updated
+	case "Resource.Updated":
+		state.Data = marshalField(m["Data"])
+	case "Resource.Deleted":
+		state.IsDelete = true
+	case "Triple.Created":
+		predicate, _ := m["predicate"].(string)
+		object, _ := m["object"].(string)
+		// An erased x-pii reference has an empty object and no edge.
+		if predicate != "" && object != "" && state.Data != nil {
+			updated, err := AddEdgeToGraph(state.Data, predicate, object, e.AggregateID)
+			if err != nil {
+				logger.Error(ctx, "failed to add edge to graph",
+					"subject", e.AggregateID, "predicate", predicate, "error", err)
+				return
 			}
-		case "Triple.Deleted":
-			predicate, _ := m["predicate"].(string)
-			object, _ := m["object"].(string)
-			if predicate != "" && state.Data != nil {
-				updated, err := RemoveEdgeFromGraph(state.Data, predicate, object)
-				if err != nil {
-					logger.Error(ctx, "failed to remove edge from graph",
-						"subject", aggregateID, "predicate", predicate, "error", err)
-					continue
-				}
-				state.Data = updated
+			state.Data = updated
+		}
+	case "Triple.Deleted":
+		predicate, _ := m["predicate"].(string)
+		object, _ := m["object"].(string)
+		if predicate != "" && object != "" && state.Data != nil {
+			updated, err := RemoveEdgeFromGraph(state.Data, predicate, object)
+			if err != nil {
+				logger.Error(ctx, "failed to remove edge from graph",
+					"subject", e.AggregateID, "predicate", predicate, "error", err)
+				return
 			}
+			state.Data = updated
 		}
 	}
-	return state
 }
 
 func handleResourcePublished(
@@ -61,6 +61,14 @@ func (s *stubProjMgr) RegisterLink(_ context.Context, _ repositories.LinkReferen
 	return nil
 }
 
+func (s *stubProjMgr) JoinReferences(_ string) []repositories.JoinReference { return nil }
+
+func (s *stubProjMgr) ReverseJoinReferences(_ string) []repositories.JoinReference { return nil }
+
+func (s *stubProjMgr) UpdateJoinDisplay(context.Context, repositories.JoinReference, string, any) error {
+	return nil
+}
+
 func makeRT(slug, ctxJSON string) *entities.ResourceType {
 	rt := &entities.ResourceType{}
 	_ = rt.Restore("id-"+slug, slug, slug, "desc", "active",
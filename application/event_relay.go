@@ -0,0 +1,261 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/segmentio/ksuid"
+	"go.uber.org/fx"
+)
+
+const (
+	defaultEventRelayBatch = 100
+	// eventGapTimeout is how long the relay waits at a missing position. A
+	// young gap may be a transaction that is still committing; an old one is
+	// a transaction that rolled back.
+	eventGapTimeout = 10 * time.Second
+	// eventInlineSettle is how old an event must be before the relay handles
+	// it for an inline group. Newer events are the inline path's to handle.
+	eventInlineSettle = 30 * time.Second
+	eventRelayLease   = time.Minute
+	// defaultEventMaxAttempts is how often the relay tries an event for a
+	// group that doesn't set MaxAttempts before dead-lettering it.
+	defaultEventMaxAttempts = 10
+)
+
+// EventRelay works the event log for every event handler group: it runs the
+// async groups, and catches every group up on events it missed. Each group
+// is worked by one relay at a time (a lease on its checkpoint), so any
+// number of relays can share a database.
+type EventRelay struct {
+	registry    EventHandlerRegistry
+	log         repositories.EventLogRepository
+	checkpoints repositories.EventCheckpointRepository
+	deadLetters repositories.EventDeadLetterRepository
+	logger      entities.Logger
+	owner       string
+
+	// PollInterval is how often the relay looks for new events. Default 1s.
+	PollInterval time.Duration
+	// BatchSize caps the events one group handles per tick. Default 100.
+	BatchSize int
+}
+
+func ProvideEventRelay(params struct {
+	fx.In
+	Registry    EventHandlerRegistry
+	Log         repositories.EventLogRepository
+	Checkpoints repositories.EventCheckpointRepository
+	DeadLetters repositories.EventDeadLetterRepository
+	Logger      entities.Logger
+}) *EventRelay {
+	return &EventRelay{
+		registry:     params.Registry,
+		log:          params.Log,
+		checkpoints:  params.Checkpoints,
+		deadLetters:  params.DeadLetters,
+		logger:       params.Logger,
+		owner:        ksuid.New().String(),
+		PollInterval: time.Second,
+		BatchSize:    defaultEventRelayBatch,
+	}
+}
+
+// Run catches up straight away, then polls until ctx is done. While a tick
+// handles events it ticks again without waiting.
+func (r *EventRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+	r.logger.Info(ctx, "event relay started", "groups", len(r.registry))
+	for {
+		handled, err := r.Tick(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error(ctx, "event relay poll failed", "error", err)
+		}
+		if handled > 0 && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			r.logger.Info(context.Background(), "event relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick gives each group one batch of the events after its checkpoint and
+// returns how many events were handled.
+func (r *EventRelay) Tick(ctx context.Context, now time.Time) (int, error) {
+	handled := 0
+	var errs []error
+	for _, g := range r.registry.sorted() {
+		n, err := r.work(ctx, g, now)
+		handled += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("event handler group %q: %w", g.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return handled, fmt.Errorf("event relay: %w", errs[0])
+	}
+	return handled, nil
+}
+
+func (r *EventRelay) work(ctx context.Context, g *EventHandlerGroup, now time.Time) (int, error) {
+	cp, err := r.checkpoints.Claim(ctx, g.Name, r.owner, now, now.Add(eventRelayLease))
+	if err != nil || cp == nil {
+		return 0, err
+	}
+	defer func() {
+		if err := r.checkpoints.Release(context.WithoutCancel(ctx), g.Name, r.owner); err != nil {
+			r.logger.Error(ctx, "failed to release event checkpoint", "group", g.Name, "error", err)
+		}
+	}()
+	if now.Before(cp.RetryAt) {
+		return 0, nil
+	}
+	events, err := r.log.After(ctx, cp.Position, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	position, attempts := cp.Position, cp.Attempts
+	handled, skipped, lastError := 0, 0, ""
+	for _, e := range events {
+		age := now.Sub(e.LoggedAt)
+		if e.Position != position+1 && age < eventGapTimeout {
+			break
+		}
+		if !g.Async && age < eventInlineSettle {
+			break
+		}
+		err := g.handle(ctx, e.Event)
+		if err == nil {
+			position, attempts = e.Position, 0
+			handled++
+			continue
+		}
+		attempts++
+		if attempts >= g.maxAttempts() {
+			r.logger.Error(ctx, "event handler group gave up on an event; dead-lettering it",
+				"group", g.Name, "eventID", e.Event.ID, "eventType", e.Event.EventType,
+				"position", e.Position, "attempts", attempts, "error", err)
+			dead := &entities.DeadLetteredEvent{
+				Group:     g.Name,
+				Position:  e.Position,
+				EventID:   e.Event.ID,
+				EventType: e.Event.EventType,
+				Attempts:  attempts,
+				LastError: err.Error(),
+			}
+			if err := r.deadLetters.Add(ctx, dead); err != nil {
+				if aerr := r.advance(ctx, g, cp.Position, position, skipped, lastError); aerr != nil {
+					return handled, aerr
+				}
+				return handled, err
+			}
+			lastError = fmt.Sprintf("skipped event %s at position %d: %v", e.Event.ID, e.Position, err)
+			position, attempts = e.Position, 0
+			skipped++
+			continue
+		}
+		if err := r.advance(ctx, g, cp.Position, position, skipped, lastError); err != nil {
+			return handled, err
+		}
+		r.logger.Warn(ctx, "event handler group failed; retrying",
+			"group", g.Name, "eventID", e.Event.ID, "eventType", e.Event.EventType,
+			"position", e.Position, "attempts", attempts, "error", err)
+		return handled, r.checkpoints.RecordFailure(ctx, g.Name, attempts, err.Error(),
+			now.Add(jobBackoff(attempts)))
+	}
+	return handled, r.advance(ctx, g, cp.Position, position, skipped, lastError)
+}
+
+// advance moves the group's checkpoint from to to. The inline path may have
+// moved it in the meantime, which is fine: it only moves forward.
+func (r *EventRelay) advance(
+	ctx context.Context, g *EventHandlerGroup, from, to int64, skipped int, lastError string,
+) error {
+	if to == from {
+		return nil
+	}
+	_, err := r.checkpoints.Advance(ctx, g.Name, from, to, skipped, lastError)
+	return err
+}
+
+// DeadLetters lists the events groups gave up on, of group or of every
+// group when group is empty.
+func (r *EventRelay) DeadLetters(ctx context.Context, group string) ([]*entities.DeadLetteredEvent, error) {
+	return r.deadLetters.FindAll(ctx, group)
+}
+
+// RetryDeadLetter runs a dead-lettered event through its group again and
+// drops it from the dead letters when the group handles it.
+func (r *EventRelay) RetryDeadLetter(ctx context.Context, group string, position int64) error {
+	g, ok := r.registry[group]
+	if !ok {
+		return fmt.Errorf("event handler group %q: %w", group, repositories.ErrNotFound)
+	}
+	events, err := r.log.After(ctx, position-1, 1)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 || events[0].Position != position {
+		return fmt.Errorf("event at position %d: %w", position, repositories.ErrNotFound)
+	}
+	if err := g.handle(ctx, events[0].Event); err != nil {
+		return fmt.Errorf("event handler group %q: %w", group, err)
+	}
+	return r.deadLetters.Delete(ctx, group, position)
+}
+
+// EventHandlerStatus reports where an event handler group is in the event log.
+type EventHandlerStatus struct {
+	Group string
+	Async bool
+	// Position is the last event position the group has handled.
+	Position int64
+	// Lag is how many positions the group is behind the head of the log.
+	Lag       int64
+	Attempts  int
+	RetryAt   time.Time
+	LastError string
+	Skipped   int
+	UpdatedAt time.Time
+}
+
+// Status reports every registered group, in name order.
+func (r *EventRelay) Status(ctx context.Context) ([]EventHandlerStatus, error) {
+	head, err := r.log.Head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints, err := r.checkpoints.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byGroup := make(map[string]*entities.EventCheckpoint, len(checkpoints))
+	for _, cp := range checkpoints {
+		byGroup[cp.Group] = cp
+	}
+	statuses := make([]EventHandlerStatus, 0, len(r.registry))
+	for _, g := range r.registry.sorted() {
+		status := EventHandlerStatus{Group: g.Name, Async: g.Async}
+		if cp := byGroup[g.Name]; cp != nil {
+			status.Position = cp.Position
+			status.Attempts = cp.Attempts
+			status.RetryAt = cp.RetryAt
+			status.LastError = cp.LastError
+			status.Skipped = cp.Skipped
+			status.UpdatedAt = cp.UpdatedAt
+		}
+		status.Lag = max(head-status.Position, 0)
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,605 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// memEventLog is an in-memory event log. Entries are kept in position order.
+type memEventLog struct {
+	mu      sync.Mutex
+	entries []entities.LoggedEvent
+}
+
+func (l *memEventLog) add(position int64, loggedAt time.Time, env domain.EventEnvelope[any]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entities.LoggedEvent{Position: position, LoggedAt: loggedAt, Event: env})
+	sort.Slice(l.entries, func(i, j int) bool { return l.entries[i].Position < l.entries[j].Position })
+}
+
+func (l *memEventLog) Head(context.Context) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.entries) == 0 {
+		return 0, nil
+	}
+	return l.entries[len(l.entries)-1].Position, nil
+}
+
+func (l *memEventLog) After(_ context.Context, position int64, limit int) ([]entities.LoggedEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []entities.LoggedEvent
+	for _, e := range l.entries {
+		if e.Position > position && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (l *memEventLog) PositionsOf(_ context.Context, eventIDs []string) (map[string]int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	positions := map[string]int64{}
+	for _, e := range l.entries {
+		if slices.Contains(eventIDs, e.Event.ID) {
+			positions[e.Event.ID] = e.Position
+		}
+	}
+	return positions, nil
+}
+
+func (l *memEventLog) Creations(
+	_ context.Context, aggregateIDs []string,
+) (map[string]domain.EventEnvelope[any], error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	creations := map[string]domain.EventEnvelope[any]{}
+	for _, e := range l.entries {
+		if e.Event.SequenceNo == 1 && slices.Contains(aggregateIDs, e.Event.AggregateID) {
+			creations[e.Event.AggregateID] = e.Event
+		}
+	}
+	return creations, nil
+}
+
+// memCheckpoints is an in-memory EventCheckpointRepository.
+type memCheckpoints struct {
+	mu   sync.Mutex
+	rows map[string]entities.EventCheckpoint
+}
+
+func (m *memCheckpoints) get(group string) entities.EventCheckpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rows[group]
+}
+
+func (m *memCheckpoints) Ensure(_ context.Context, group string, position int64) (*entities.EventCheckpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.rows == nil {
+		m.rows = map[string]entities.EventCheckpoint{}
+	}
+	cp, ok := m.rows[group]
+	if !ok {
+		cp = entities.EventCheckpoint{Group: group, Position: position}
+		m.rows[group] = cp
+	}
+	return &cp, nil
+}
+
+func (m *memCheckpoints) FindAll(context.Context) ([]*entities.EventCheckpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*entities.EventCheckpoint
+	for _, cp := range m.rows {
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func (m *memCheckpoints) Claim(
+	_ context.Context, group, owner string, now, leaseUntil time.Time,
+) (*entities.EventCheckpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp, ok := m.rows[group]
+	if !ok || (cp.LockedBy != "" && cp.LockedBy != owner && !cp.LockedUntil.Before(now)) {
+		return nil, nil
+	}
+	cp.LockedBy, cp.LockedUntil = owner, leaseUntil
+	m.rows[group] = cp
+	return &cp, nil
+}
+
+func (m *memCheckpoints) Release(_ context.Context, group, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cp := m.rows[group]; cp.LockedBy == owner {
+		cp.LockedBy, cp.LockedUntil = "", time.Time{}
+		m.rows[group] = cp
+	}
+	return nil
+}
+
+func (m *memCheckpoints) Advance(
+	_ context.Context, group string, from, to int64, skipped int, lastError string,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := m.rows[group]
+	if cp.Position != from {
+		return false, nil
+	}
+	cp.Position, cp.Attempts, cp.RetryAt = to, 0, time.Time{}
+	cp.Skipped += skipped
+	cp.LastError = lastError
+	m.rows[group] = cp
+	return true, nil
+}
+
+func (m *memCheckpoints) RecordFailure(
+	_ context.Context, group string, attempts int, lastError string, retryAt time.Time,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := m.rows[group]
+	cp.Attempts, cp.LastError, cp.RetryAt = attempts, lastError, retryAt
+	m.rows[group] = cp
+	return nil
+}
+
+func (m *memCheckpoints) Delete(_ context.Context, group string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.rows, group)
+	return nil
+}
+
+// memDeadLetters is an in-memory EventDeadLetterRepository.
+type memDeadLetters struct {
+	mu     sync.Mutex
+	events []*entities.DeadLetteredEvent
+}
+
+func (m *memDeadLetters) Add(_ context.Context, event *entities.DeadLetteredEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memDeadLetters) FindAll(_ context.Context, group string) ([]*entities.DeadLetteredEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*entities.DeadLetteredEvent
+	for _, e := range m.events {
+		if group == "" || e.Group == group {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (m *memDeadLetters) Delete(_ context.Context, group string, position int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.events {
+		if e.Group == group && e.Position == position {
+			m.events = slices.Delete(m.events, i, i+1)
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+// recordingGroup is a group that records the events it handles and fails on
+// the events named in failOn.
+type recordingGroup struct {
+	mu      sync.Mutex
+	handled []string
+	failOn  map[string]bool
+}
+
+func (g *recordingGroup) subscribe(d *domain.EventDispatcher) error {
+	return domain.Subscribe(d, "Resource.*", func(_ context.Context, env domain.EventEnvelope[any]) error {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.failOn[env.ID] {
+			return errors.New("receiver down")
+		}
+		g.handled = append(g.handled, env.ID)
+		return nil
+	})
+}
+
+func (g *recordingGroup) seen() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.handled...)
+}
+
+func newTestRelay(t *testing.T, groups ...EventHandlerGroup) (*EventRelay, *memEventLog, *memCheckpoints) {
+	t.Helper()
+	registry := EventHandlerRegistry{}
+	checkpoints := &memCheckpoints{}
+	for _, g := range groups {
+		if err := registry.Add(g); err != nil {
+			t.Fatalf("Add(%s): %v", g.Name, err)
+		}
+		if _, err := checkpoints.Ensure(context.Background(), g.Name, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log := &memEventLog{}
+	return &EventRelay{
+		registry:    registry,
+		log:         log,
+		checkpoints: checkpoints,
+		deadLetters: &memDeadLetters{},
+		logger:      noopLogger{},
+		owner:       "test-relay",
+		BatchSize:   10,
+	}, log, checkpoints
+}
+
+func logEvents(log *memEventLog, loggedAt time.Time, ids ...string) {
+	for i, id := range ids {
+		log.add(int64(i+1), loggedAt, domain.EventEnvelope[any]{
+			ID: id, AggregateID: "urn:task:1", EventType: "Resource.Updated", SequenceNo: i + 1,
+			Payload: map[string]any{},
+		})
+	}
+}
+
+func TestEventRelay_CatchesUpAsyncGroup(t *testing.T) {
+	t.Parallel()
+	rec := &recordingGroup{}
+	relay, log, checkpoints := newTestRelay(t, EventHandlerGroup{Name: "hooks", Async: true, Subscribe: rec.subscribe})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	logEvents(log, now, "e1", "e2", "e3")
+
+	handled, err := relay.Tick(context.Background(), now)
+	if err != nil || handled != 3 {
+		t.Fatalf("Tick = %d, %v; want 3 handled", handled, err)
+	}
+	if got := rec.seen(); fmt.Sprint(got) != "[e1 e2 e3]" {
+		t.Errorf("handled %v, want e1 e2 e3 in order", got)
+	}
+	if cp := checkpoints.get("hooks"); cp.Position != 3 || cp.LockedBy != "" {
+		t.Errorf("checkpoint = %+v, want position 3 and released", cp)
+	}
+	statuses, err := relay.Status(context.Background())
+	if err != nil || len(statuses) != 1 || statuses[0].Lag != 0 || !statuses[0].Async {
+		t.Errorf("Status = %+v, %v; want one async group with no lag", statuses, err)
+	}
+}
+
+func TestEventRelay_RetriesFailuresWithBackoff(t *testing.T) {
+	t.Parallel()
+	rec := &recordingGroup{failOn: map[string]bool{"e2": true}}
+	relay, log, checkpoints := newTestRelay(t, EventHandlerGroup{Name: "hooks", Async: true, Subscribe: rec.subscribe})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	logEvents(log, now, "e1", "e2", "e3")
+
+	if _, err := relay.Tick(context.Background(), now); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	cp := checkpoints.get("hooks")
+	if cp.Position != 1 || cp.Attempts != 1 || cp.LastError == "" || !cp.RetryAt.Equal(now.Add(jobBackoff(1))) {
+		t.Fatalf("checkpoint after a failure = %+v, want held at 1 with a retry scheduled", cp)
+	}
+	statuses, _ := relay.Status(context.Background())
+	if statuses[0].Lag != 2 {
+		t.Errorf("lag = %d, want 2", statuses[0].Lag)
+	}
+
+	// Nothing happens before the retry is due.
+	if handled, _ := relay.Tick(context.Background(), now.Add(time.Second)); handled != 0 {
+		t.Errorf("Tick before the retry handled %d events", handled)
+	}
+
+	rec.mu.Lock()
+	rec.failOn = nil
+	rec.mu.Unlock()
+	if _, err := relay.Tick(context.Background(), cp.RetryAt); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	if got := rec.seen(); fmt.Sprint(got) != "[e1 e2 e3]" {
+		t.Errorf("handled %v, want e1 e2 e3", got)
+	}
+	if cp := checkpoints.get("hooks"); cp.Position != 3 || cp.Attempts != 0 || cp.LastError != "" {
+		t.Errorf("checkpoint after recovery = %+v", cp)
+	}
+}
+
+func TestEventRelay_SkipsAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+	rec := &recordingGroup{failOn: map[string]bool{"e2": true}}
+	relay, log, checkpoints := newTestRelay(t, EventHandlerGroup{
+		Name: "hooks", Async: true, MaxAttempts: 2, Subscribe: rec.subscribe,
+	})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	logEvents(log, now, "e1", "e2", "e3")
+
+	if _, err := relay.Tick(context.Background(), now); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	if _, err := relay.Tick(context.Background(), now.Add(time.Hour)); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	cp := checkpoints.get("hooks")
+	if cp.Position != 3 || cp.Skipped != 1 || cp.LastError == "" {
+		t.Fatalf("checkpoint = %+v, want e2 skipped and the group at 3", cp)
+	}
+	if got := rec.seen(); fmt.Sprint(got) != "[e1 e3]" {
+		t.Errorf("handled %v, want e1 e3", got)
+	}
+
+	dead, err := relay.DeadLetters(context.Background(), "hooks")
+	if err != nil || len(dead) != 1 || dead[0].EventID != "e2" || dead[0].Position != 2 || dead[0].Attempts != 2 {
+		t.Fatalf("DeadLetters = %+v, %v; want e2 at 2 after 2 attempts", dead, err)
+	}
+	if err := relay.RetryDeadLetter(context.Background(), "hooks", 2); err == nil {
+		t.Fatal("RetryDeadLetter succeeded while the group still fails on e2")
+	}
+	rec.mu.Lock()
+	rec.failOn = nil
+	rec.mu.Unlock()
+	if err := relay.RetryDeadLetter(context.Background(), "hooks", 2); err != nil {
+		t.Fatalf("RetryDeadLetter: %v", err)
+	}
+	if got := rec.seen(); fmt.Sprint(got) != "[e1 e3 e2]" {
+		t.Errorf("handled %v, want e2 handled on retry", got)
+	}
+	if dead, _ := relay.DeadLetters(context.Background(), ""); len(dead) != 0 {
+		t.Errorf("DeadLetters after the retry = %+v, want none", dead)
+	}
+}
+
+func TestEventRelay_DeadLettersInlineGroupsByDefault(t *testing.T) {
+	t.Parallel()
+	rec := &recordingGroup{failOn: map[string]bool{"e1": true}}
+	relay, log, checkpoints := newTestRelay(t, EventHandlerGroup{Name: "projections", Subscribe: rec.subscribe})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	logEvents(log, now, "e1", "e2")
+
+	at := now.Add(eventInlineSettle)
+	for range defaultEventMaxAttempts {
+		if _, err := relay.Tick(context.Background(), at); err != nil {
+			t.Fatalf("Tick: %v", err)
+		}
+		at = at.Add(jobBackoffMax)
+	}
+	if cp := checkpoints.get("projections"); cp.Position != 2 || cp.Skipped != 1 {
+		t.Fatalf("checkpoint = %+v, want e1 dead-lettered and the group at 2", cp)
+	}
+	if dead, _ := relay.DeadLetters(context.Background(), ""); len(dead) != 1 || dead[0].EventID != "e1" {
+		t.Errorf("DeadLetters = %+v, want e1", dead)
+	}
+}
+
+func TestEventRelay_LeavesFreshEventsToInlineGroups(t *testing.T) {
+	t.Parallel()
+	rec := &recordingGroup{}
+	relay, log, checkpoints := newTestRelay(t, EventHandlerGroup{Name: "projections", Subscribe: rec.subscribe})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	logEvents(log, now, "e1")
+	log.add(2, now.Add(eventInlineSettle), domain.EventEnvelope[any]{ID: "e2", EventType: "Resource.Updated", Payload: map[string]any{}})
+
+	if _, err := relay.Tick(context.Background(), now.Add(eventInlineSettle)); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	if got := rec.seen(); fmt.Sprint(got) != "[e1]" {
+		t.Errorf("handled %v, want only the settled e1", got)
+	}
+	if cp := checkpoints.get("projections"); cp.Position != 1 {
+		t.Errorf("position = %d, want 1", cp.Position)
+	}
+}
+
+func TestEventRelay_WaitsAtYoungGaps(t *testing.T) {
+	t.Parallel()
+	rec := &recordingGroup{}
+	relay, log, checkpoints := newTestRelay(t, EventHandlerGroup{Name: "hooks", Async: true, Subscribe: rec.subscribe})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	logEvents(log, now, "e1")
+	// Position 2 may still be committing.
+	log.add(3, now, domain.EventEnvelope[any]{ID: "e3", EventType: "Resource.Updated", Payload: map[string]any{}})
+
+	if _, err := relay.Tick(context.Background(), now); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	if cp := checkpoints.get("hooks"); cp.Position != 1 {
+		t.Fatalf("position = %d, want 1 (waiting at the gap)", cp.Position)
+	}
+	if _, err := relay.Tick(context.Background(), now.Add(eventGapTimeout)); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	if cp := checkpoints.get("hooks"); cp.Position != 3 {
+		t.Errorf("position = %d, want 3 once the gap is old", cp.Position)
+	}
+}
+
+func TestEventRelay_ClaimsGroupsExclusively(t *testing.T) {
+	t.Parallel()
+	rec := &recordingGroup{}
+	relay, log, checkpoints := newTestRelay(t, EventHandlerGroup{Name: "hooks", Async: true, Subscribe: rec.subscribe})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	logEvents(log, now, "e1")
+	if _, err := checkpoints.Claim(context.Background(), "hooks", "other-relay", now, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if handled, _ := relay.Tick(context.Background(), now); handled != 0 {
+		t.Errorf("handled %d events of a group another relay holds", handled)
+	}
+}
+
+func TestEventHandlerGroup_DecodesStoredPayloads(t *testing.T) {
+	t.Parallel()
+	var got entities.TripleCreated
+	registry := EventHandlerRegistry{}
+	err := registry.Add(EventHandlerGroup{
+		Name: "triples",
+		Subscribe: func(d *domain.EventDispatcher) error {
+			return domain.Subscribe(d, "Triple.Created",
+				func(_ context.Context, env domain.EventEnvelope[entities.TripleCreated]) error {
+					got = env.Payload
+					return nil
+				})
+		},
+	})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	stored := domain.EventEnvelope[any]{
+		ID: "e1", EventType: "Triple.Created", Created: time.Now(),
+		Payload: map[string]any{"subject": "urn:task:1", "predicate": "belongsTo", "object": "urn:project:1"},
+	}
+	if err := registry["triples"].handle(context.Background(), stored); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if got.Subject != "urn:task:1" || got.Object != "urn:project:1" {
+		t.Errorf("decoded payload = %+v", got)
+	}
+}
+
+func TestInlineEventRouter_AdvancesContiguously(t *testing.T) {
+	t.Parallel()
+	ok := &recordingGroup{}
+	failing := &recordingGroup{failOn: map[string]bool{"e2": true}}
+	registry := EventHandlerRegistry{}
+	for _, g := range []EventHandlerGroup{
+		{Name: "ok", Subscribe: ok.subscribe},
+		{Name: "failing", Subscribe: failing.subscribe},
+	} {
+		if err := registry.Add(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log := &memEventLog{}
+	checkpoints := &memCheckpoints{}
+	router := &inlineEventRouter{
+		groups: registry.sorted(), log: log, checkpoints: checkpoints, logger: noopLogger{},
+	}
+	for _, name := range []string{"ok", "failing"} {
+		_, _ = checkpoints.Ensure(context.Background(), name, 0)
+	}
+	now := time.Now()
+	logEvents(log, now, "e1", "e2", "e3")
+
+	ctx := context.Background()
+	for _, id := range []string{"e1", "e2", "e3"} {
+		_ = router.dispatch(ctx, domain.EventEnvelope[any]{
+			ID: id, EventType: "Resource.Updated", Payload: map[string]any{},
+		})
+	}
+	if cp := checkpoints.get("ok"); cp.Position != 0 {
+		t.Errorf("ok group at %d before the batch is flushed, want 0", cp.Position)
+	}
+	router.flush(ctx)
+	if cp := checkpoints.get("ok"); cp.Position != 3 {
+		t.Errorf("ok group at %d, want 3", cp.Position)
+	}
+	// The failing group stops before e2 and stays there; e3 is left to the relay.
+	if cp := checkpoints.get("failing"); cp.Position != 1 {
+		t.Errorf("failing group at %d, want 1", cp.Position)
+	}
+}
+
+func TestInlineEventRouter_AdvancesOverEventsDispatchedOutOfOrder(t *testing.T) {
+	t.Parallel()
+	group := &recordingGroup{}
+	registry := EventHandlerRegistry{}
+	if err := registry.Add(EventHandlerGroup{Name: "ok", Subscribe: group.subscribe}); err != nil {
+		t.Fatal(err)
+	}
+	log := &memEventLog{}
+	checkpoints := &memCheckpoints{}
+	router := &inlineEventRouter{
+		groups: registry.sorted(), log: log, checkpoints: checkpoints, logger: noopLogger{},
+	}
+	_, _ = checkpoints.Ensure(context.Background(), "ok", 0)
+	logEvents(log, time.Now(), "e1", "e2", "e3")
+
+	// A commit over several aggregates logs e1..e3 but may dispatch e3 first.
+	ctx := context.Background()
+	for _, id := range []string{"e3", "e1"} {
+		_ = router.dispatch(ctx, domain.EventEnvelope[any]{
+			ID: id, EventType: "Resource.Updated", Payload: map[string]any{},
+		})
+	}
+	router.flush(ctx)
+	if cp := checkpoints.get("ok"); cp.Position != 1 {
+		t.Fatalf("ok group at %d before e2, want 1", cp.Position)
+	}
+	_ = router.dispatch(ctx, domain.EventEnvelope[any]{
+		ID: "e2", EventType: "Resource.Updated", Payload: map[string]any{},
+	})
+	router.flush(ctx)
+	if cp := checkpoints.get("ok"); cp.Position != 3 {
+		t.Errorf("ok group at %d after e2, want 3", cp.Position)
+	}
+}
+
+func TestInlineEventRouter_RunsGroupsInOrder(t *testing.T) {
+	t.Parallel()
+	var order []string
+	registry := EventHandlerRegistry{}
+	for _, name := range []string{"b", "a", "c"} {
+		err := registry.Add(EventHandlerGroup{
+			Name: name,
+			Subscribe: func(d *domain.EventDispatcher) error {
+				return domain.Subscribe(d, "Resource.*", func(context.Context, domain.EventEnvelope[any]) error {
+					order = append(order, name)
+					return nil
+				})
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	router := &inlineEventRouter{
+		groups: registry.sorted(), log: &memEventLog{}, checkpoints: &memCheckpoints{}, logger: noopLogger{},
+	}
+	_ = router.dispatch(context.Background(), domain.EventEnvelope[any]{
+		ID: "e1", EventType: "Resource.Updated", Payload: map[string]any{},
+	})
+	router.flush(context.Background())
+	if fmt.Sprint(order) != "[a b c]" {
+		t.Errorf("groups ran as %v, want a b c", order)
+	}
+}
+
+func TestEventHandlerRegistry_Add(t *testing.T) {
+	t.Parallel()
+	registry := EventHandlerRegistry{}
+	subscribe := func(*domain.EventDispatcher) error { return nil }
+	if err := registry.Add(EventHandlerGroup{Subscribe: subscribe}); err == nil {
+		t.Error("a group without a name was accepted")
+	}
+	if err := registry.Add(EventHandlerGroup{Name: "a"}); err == nil {
+		t.Error("a group without Subscribe was accepted")
+	}
+	if err := registry.Add(EventHandlerGroup{Name: "a", Subscribe: subscribe}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := registry.Add(EventHandlerGroup{Name: "a", Subscribe: subscribe}); err == nil {
+		t.Error("a duplicate group name was accepted")
+	}
+}
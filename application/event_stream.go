@@ -0,0 +1,355 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/jsonld"
+
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"go.uber.org/fx"
+)
+
+// streamedEventFamilies are the event families an event stream carries.
+var streamedEventFamilies = []string{"Resource.", "Triple.", "ResourceType."}
+
+// EventStreamFilter narrows an event stream. Empty fields match every event.
+type EventStreamFilter struct {
+	TypeSlugs   []string
+	ResourceIDs []string
+}
+
+// StreamedEvent is one event sent to an event stream. Position is its event
+// log position, which a reconnecting client resumes after.
+type StreamedEvent struct {
+	Position   int64           `json:"position"`
+	Event      string          `json:"event"`
+	EventID    string          `json:"eventId"`
+	ResourceID string          `json:"resourceId"`
+	TypeSlug   string          `json:"typeSlug,omitempty"`
+	SequenceNo int             `json:"sequenceNo"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// EventStreamService reads the event log for live event streams. Resource
+// and triple events are only returned to callers who may read the resource,
+// and show its data as GetByID would: without the properties the caller's
+// role may not read, with x-encrypted values revealed only to the roles
+// allowed them. x-pii values are never streamed. Resource type events are
+// shared by every account.
+type EventStreamService interface {
+	// Head returns the latest event log position. A new stream starts after it.
+	Head(ctx context.Context) (int64, error)
+	// After returns the events after position that match filter and that the
+	// caller may read, looking at up to limit log entries. It also returns
+	// the position to continue after: the last entry it looked at, or the
+	// entry before a missing position that may still be committing.
+	After(ctx context.Context, position int64, filter EventStreamFilter, limit int) (
+		[]StreamedEvent, int64, error)
+}
+
+type eventStreamService struct {
+	log        repositories.EventLogRepository
+	types      repositories.ResourceTypeRepository
+	access     instanceAccess
+	fields     fieldPermissions
+	encryption fieldEncryption
+}
+
+func ProvideEventStreamService(params struct {
+	fx.In
+	Log         repositories.EventLogRepository
+	AccountRepo authrepos.AccountRepository
+	PermRepo    repositories.ResourcePermissionRepository
+	TypeRepo    repositories.ResourceTypeRepository
+	TripleRepo  repositories.TripleRepository
+	Resources   repositories.ResourceRepository
+	Logger      entities.Logger
+	Roles       RoleAuthorizer                            `optional:"true"`
+	FieldKeys   entities.FieldKeyProvider                 `optional:"true"`
+	RoleAccess  repositories.RoleResourceAccessRepository `optional:"true"`
+}) EventStreamService {
+	return &eventStreamService{
+		log:   params.Log,
+		types: params.TypeRepo,
+		access: instanceAccess{
+			accounts: params.AccountRepo,
+			perms:    params.PermRepo,
+			inherit: &permissionInheritance{
+				types:     params.TypeRepo,
+				triples:   params.TripleRepo,
+				resources: params.Resources,
+			},
+			roles: params.Roles,
+		},
+		fields: fieldPermissions{access: params.RoleAccess, accounts: params.AccountRepo},
+		encryption: fieldEncryption{
+			keys:     params.FieldKeys,
+			accounts: params.AccountRepo,
+			logger:   params.Logger,
+		},
+	}
+}
+
+func (s *eventStreamService) Head(ctx context.Context) (int64, error) {
+	return s.log.Head(ctx)
+}
+
+// eventOrigin is what the creating event of an aggregate says about it.
+// Found is false when that event could not be read.
+type eventOrigin struct {
+	TypeSlug  string
+	AccountID string
+	CreatedBy string
+	Found     bool
+}
+
+func (s *eventStreamService) After(
+	ctx context.Context, position int64, filter EventStreamFilter, limit int,
+) ([]StreamedEvent, int64, error) {
+	start := position
+	logged, err := s.log.After(ctx, position, limit)
+	if err != nil {
+		return nil, start, err
+	}
+	logged = heldAtGap(logged, position, time.Now())
+	var candidates []entities.LoggedEvent
+	for _, l := range logged {
+		env := l.Event
+		if internalEvents[env.EventType] || !slices.ContainsFunc(streamedEventFamilies, func(family string) bool {
+			return strings.HasPrefix(env.EventType, family)
+		}) {
+			continue
+		}
+		if len(filter.ResourceIDs) > 0 && !slices.Contains(filter.ResourceIDs, env.AggregateID) {
+			continue
+		}
+		candidates = append(candidates, l)
+	}
+	if len(logged) > 0 {
+		position = logged[len(logged)-1].Position
+	}
+	origins, err := s.origins(ctx, candidates)
+	if err != nil {
+		return nil, start, err
+	}
+
+	readable := map[string]bool{}
+	views := map[string]*streamView{}
+	var out []StreamedEvent
+	for _, l := range candidates {
+		env := l.Event
+		origin := origins[env.AggregateID]
+		if len(filter.TypeSlugs) > 0 && !slices.Contains(filter.TypeSlugs, origin.TypeSlug) {
+			continue
+		}
+		payload := env.Payload
+		if webhookAccountScoped(env.EventType) {
+			allowed, ok := readable[env.AggregateID]
+			if !ok {
+				allowed = origin.Found &&
+					s.access.check(ctx, env.AggregateID, origin.TypeSlug, origin.AccountID, origin.CreatedBy, "read") == nil
+				readable[env.AggregateID] = allowed
+			}
+			if !allowed {
+				continue
+			}
+			view, ok := views[origin.TypeSlug]
+			if !ok {
+				if view, err = s.viewOf(ctx, origin.TypeSlug); err != nil {
+					return nil, start, err
+				}
+				views[origin.TypeSlug] = view
+			}
+			if payload, ok = view.payload(ctx, env, origin.AccountID); !ok {
+				continue
+			}
+		}
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, start, fmt.Errorf("failed to encode %s payload: %w", env.EventType, err)
+		}
+		out = append(out, StreamedEvent{
+			Position:   l.Position,
+			Event:      env.EventType,
+			EventID:    env.ID,
+			ResourceID: env.AggregateID,
+			TypeSlug:   origin.TypeSlug,
+			SequenceNo: env.SequenceNo,
+			OccurredAt: env.Created,
+			Payload:    raw,
+		})
+	}
+	return out, position, nil
+}
+
+// heldAtGap returns the entries up to the first missing position younger
+// than eventGapTimeout. Like the relay, the stream waits there for the
+// transaction that took it to commit rather than skip its event for good.
+func heldAtGap(logged []entities.LoggedEvent, position int64, now time.Time) []entities.LoggedEvent {
+	for i, l := range logged {
+		if l.Position != position+1 && now.Sub(l.LoggedAt) < eventGapTimeout {
+			return logged[:i]
+		}
+		position = l.Position
+	}
+	return logged
+}
+
+// origins returns the type, account, and creator of the aggregates of the
+// events. Only the creating event carries them; those not among the events
+// are loaded in one query, which still works once a resource has been
+// deleted from the read models.
+func (s *eventStreamService) origins(
+	ctx context.Context, logged []entities.LoggedEvent,
+) (map[string]eventOrigin, error) {
+	origins := map[string]eventOrigin{}
+	for _, l := range logged {
+		if l.Event.SequenceNo == 1 {
+			origins[l.Event.AggregateID] = originOf(l.Event)
+		}
+	}
+	var missing []string
+	for _, l := range logged {
+		id := l.Event.AggregateID
+		if _, ok := origins[id]; !ok && !slices.Contains(missing, id) {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return origins, nil
+	}
+	creations, err := s.log.Creations(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for id, env := range creations {
+		origins[id] = originOf(env)
+	}
+	return origins, nil
+}
+
+func originOf(env domain.EventEnvelope[any]) eventOrigin {
+	var fields struct {
+		TypeSlug  string
+		Slug      string
+		AccountID string
+		CreatedBy string
+	}
+	if raw, err := json.Marshal(env.Payload); err == nil {
+		_ = json.Unmarshal(raw, &fields)
+	}
+	if fields.TypeSlug == "" {
+		fields.TypeSlug = fields.Slug
+	}
+	return eventOrigin{
+		TypeSlug: fields.TypeSlug, AccountID: fields.AccountID, CreatedBy: fields.CreatedBy, Found: true,
+	}
+}
+
+// streamView is what the caller may see of the events of one type's
+// resources.
+type streamView struct {
+	resourceView
+	pii        map[string]bool
+	secret     map[string]bool // x-encrypted properties
+	reverse    map[string]string
+	predicates map[string]string // reference property by triple predicate
+}
+
+// viewOf returns the caller's view of typeSlug's events, or nil for a type
+// that no longer exists, whose resource data is left out.
+func (s *eventStreamService) viewOf(ctx context.Context, typeSlug string) (*streamView, error) {
+	rt, err := s.types.FindBySlug(ctx, typeSlug)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && rt == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	view, err := resourceViewOf(ctx, s.fields, s.encryption, rt)
+	if err != nil {
+		return nil, err
+	}
+	v := &streamView{
+		resourceView: view,
+		pii:          piiProperties(rt.Schema()),
+		secret:       encryptedFieldsOf(rt.Schema()).properties,
+		reverse:      jsonld.BuildReverseMap(rt.Context()),
+		predicates:   map[string]string{},
+	}
+	for _, ref := range ExtractReferenceProperties(rt.Schema(), rt.Context()) {
+		v.predicates[ref.PredicateIRI] = ref.PropertyName
+	}
+	return v, nil
+}
+
+// payload returns the payload of a resource or triple event as the caller
+// may see it, and false when the caller may not see the event at all: a
+// triple of a reference property they may not read.
+func (v *streamView) payload(ctx context.Context, env domain.EventEnvelope[any], accountID string) (any, bool) {
+	payload, ok := env.Payload.(map[string]any)
+	if !ok {
+		return env.Payload, true
+	}
+	if v == nil {
+		if _, ok := payload["Data"]; ok {
+			payload = maps.Clone(payload)
+			delete(payload, "Data")
+		}
+		return payload, true
+	}
+	if strings.HasPrefix(env.EventType, "Triple.") {
+		predicate, _ := payload["predicate"].(string)
+		if name, ok := v.predicates[predicate]; ok && !v.mayRead(ctx, accountID, name) {
+			return nil, false
+		}
+		return payload, true
+	}
+	data, ok := payload["Data"].(map[string]any)
+	if !ok {
+		return payload, true
+	}
+	data = v.data(ctx, env.AggregateID, accountID, data)
+	if len(v.pii) > 0 {
+		data = withDataNodes(data, func(node map[string]any) {
+			for key := range node {
+				name := key
+				if prop, ok := v.reverse[key]; ok {
+					name = prop
+				}
+				if v.pii[name] {
+					delete(node, key)
+				}
+			}
+		})
+	}
+	payload = maps.Clone(payload)
+	payload["Data"] = data
+	return payload, true
+}
+
+// mayRead reports whether the caller may see the value of property name of
+// a resource in accountID.
+func (v *streamView) mayRead(ctx context.Context, accountID, name string) bool {
+	if v.pii[name] {
+		return false
+	}
+	if f := v.fields; f != nil && f.properties[name] && !f.mayRead(name) {
+		return false
+	}
+	if v.secret[name] {
+		return v.encrypted != nil && v.encrypted.mayRead(ctx, accountID)
+	}
+	return true
+}
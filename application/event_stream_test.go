@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// countingEventLog counts the Creations lookups made of a memEventLog.
+type countingEventLog struct {
+	*memEventLog
+	creations int
+}
+
+func (l *countingEventLog) Creations(
+	ctx context.Context, aggregateIDs []string,
+) (map[string]domain.EventEnvelope[any], error) {
+	l.creations++
+	return l.memEventLog.Creations(ctx, aggregateIDs)
+}
+
+func TestEventStream_LooksUpOriginsInOneQueryAndLeavesOutPersonalData(t *testing.T) {
+	t.Parallel()
+	types := newInstallTestTypeRepo()
+	types.types["patient"] = makeSchemaRT(t, "patient",
+		`{"type":"object","properties":{"name":{"type":"string","x-pii":true},"ward":{"type":"string"}}}`)
+	log := &countingEventLog{memEventLog: &memEventLog{}}
+	now := time.Now()
+	for i, id := range []string{"urn:patient:a", "urn:patient:b"} {
+		log.add(int64(i+1), now, domain.EventEnvelope[any]{
+			ID: "created-" + id, AggregateID: id, EventType: "Resource.Created", SequenceNo: 1,
+			Payload: map[string]any{"TypeSlug": "patient", "AccountID": "acct-1", "CreatedBy": "agent-1"},
+		})
+	}
+	for i, id := range []string{"urn:patient:a", "urn:patient:b", "urn:patient:a"} {
+		log.add(int64(i+3), now, domain.EventEnvelope[any]{
+			ID: fmt.Sprintf("updated-%d", i), AggregateID: id, EventType: "Resource.Updated",
+			SequenceNo: 2 + i/2,
+			Payload: map[string]any{"Data": map[string]any{"@graph": []any{
+				map[string]any{"@id": id, "name": "Ada Lovelace", "ward": "B"},
+			}}},
+		})
+	}
+	service := &eventStreamService{log: log, types: types}
+
+	events, position, err := service.After(context.Background(), 2, EventStreamFilter{}, 10)
+	if err != nil {
+		t.Fatalf("After: %v", err)
+	}
+	if len(events) != 3 || position != 5 {
+		t.Fatalf("After = %d events up to %d, want 3 up to 5", len(events), position)
+	}
+	if log.creations != 1 {
+		t.Errorf("origins were looked up in %d queries, want 1", log.creations)
+	}
+	for _, e := range events {
+		if e.TypeSlug != "patient" {
+			t.Errorf("event %s has type %q, want patient", e.EventID, e.TypeSlug)
+		}
+		var payload map[string]json.RawMessage
+		_ = json.Unmarshal(e.Payload, &payload)
+		if data := string(payload["Data"]); strings.Contains(data, "Ada") || !strings.Contains(data, `"ward":"B"`) {
+			t.Errorf("event %s data = %s, want the ward without the name", e.EventID, data)
+		}
+	}
+}
+
+func TestEventStream_HoldsBelowYoungGaps(t *testing.T) {
+	t.Parallel()
+	log := &memEventLog{}
+	now := time.Now()
+	typeEvent := func(position int64, loggedAt time.Time) {
+		log.add(position, loggedAt, domain.EventEnvelope[any]{
+			ID: fmt.Sprintf("event-%d", position), AggregateID: fmt.Sprintf("urn:type:t%d", position),
+			EventType: "ResourceType.Created", SequenceNo: 1, Payload: map[string]any{},
+		})
+	}
+	typeEvent(1, now)
+	typeEvent(3, now)
+	service := &eventStreamService{log: log, types: newInstallTestTypeRepo()}
+
+	events, position, err := service.After(context.Background(), 0, EventStreamFilter{}, 10)
+	if err != nil {
+		t.Fatalf("After: %v", err)
+	}
+	if len(events) != 1 || position != 1 {
+		t.Fatalf("After(0) = %d events up to %d, want 1 up to 1 while position 2 commits", len(events), position)
+	}
+
+	typeEvent(2, now)
+	events, position, err = service.After(context.Background(), position, EventStreamFilter{}, 10)
+	if err != nil {
+		t.Fatalf("After: %v", err)
+	}
+	if len(events) != 2 || events[0].Position != 2 || position != 3 {
+		t.Fatalf("After(1) = %d events up to %d, want 2 and 3 once the gap fills", len(events), position)
+	}
+
+	typeEvent(5, now.Add(-eventGapTimeout))
+	events, position, err = service.After(context.Background(), position, EventStreamFilter{}, 10)
+	if err != nil {
+		t.Fatalf("After: %v", err)
+	}
+	if len(events) != 1 || position != 5 {
+		t.Fatalf("After(3) = %d events up to %d, want to pass the old gap at 4", len(events), position)
+	}
+}
@@ -0,0 +1,447 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/utils"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+)
+
+// encryptedTokenPrefix starts a stored x-encrypted value: "$enc:<key
+// ID>:<nonce and ciphertext, base64url>".
+const encryptedTokenPrefix = "$enc:"
+
+// ErrNoFieldKey is returned when a resource with x-encrypted properties is
+// written without a field encryption key configured.
+var ErrNoFieldKey = errors.New(
+	"x-encrypted properties need a field encryption key: set FIELD_ENCRYPTION_KEYFILE")
+
+// encryptedFields is a type's x-encrypted properties and the account roles
+// that may read them.
+type encryptedFields struct {
+	properties map[string]bool
+	roles      map[string]bool
+}
+
+type encryptedSchema struct {
+	Properties map[string]struct {
+		Encrypted bool `json:"x-encrypted"`
+	} `json:"properties"`
+	DecryptRoles []string `json:"x-decrypt-roles"`
+}
+
+// encryptedFieldsOf returns the schema's x-encrypted properties. Owners and
+// admins may always read them, along with the roles in x-decrypt-roles.
+func encryptedFieldsOf(schema json.RawMessage) encryptedFields {
+	var s encryptedSchema
+	if len(schema) == 0 || json.Unmarshal(schema, &s) != nil {
+		return encryptedFields{}
+	}
+	fields := encryptedFields{}
+	for name, p := range s.Properties {
+		if p.Encrypted {
+			if fields.properties == nil {
+				fields.properties = make(map[string]bool)
+			}
+			fields.properties[name] = true
+		}
+	}
+	if fields.properties == nil {
+		return fields
+	}
+	fields.roles = map[string]bool{authentities.RoleOwner: true, authentities.RoleAdmin: true}
+	for _, role := range s.DecryptRoles {
+		fields.roles[role] = true
+	}
+	return fields
+}
+
+// validateEncryptedProperties checks x-encrypted and x-decrypt-roles.
+// x-encrypted applies to string properties that are neither references,
+// unique, nor computed: their values must be readable to the server. Each
+// encryption of a value differs, so unique x-indexes cannot include them
+// either.
+func validateEncryptedProperties(schema json.RawMessage) error {
+	var s struct {
+		Properties   map[string]map[string]json.RawMessage `json:"properties"`
+		DecryptRoles json.RawMessage                       `json:"x-decrypt-roles"`
+	}
+	if len(schema) == 0 || json.Unmarshal(schema, &s) != nil {
+		return nil
+	}
+	var errs []error
+	for name, p := range s.Properties {
+		raw, ok := p[entities.EncryptedAnnotation]
+		if !ok {
+			continue
+		}
+		var encrypted bool
+		if json.Unmarshal(raw, &encrypted) != nil {
+			errs = append(errs, fmt.Errorf("property %q: x-encrypted must be true or false: %w", name, ErrValidation))
+			continue
+		}
+		if !encrypted {
+			continue
+		}
+		if !isStringProperty(p["type"]) {
+			errs = append(errs, fmt.Errorf("property %q: x-encrypted applies to string properties only: %w",
+				name, ErrValidation))
+		}
+		for _, other := range []string{"x-resource-type", "x-unique", "x-computed"} {
+			if _, ok := p[other]; ok {
+				errs = append(errs, fmt.Errorf("property %q: x-encrypted cannot be combined with %s: %w",
+					name, other, ErrValidation))
+			}
+		}
+	}
+	encrypted := encryptedFieldsOf(schema)
+	for _, uc := range ExtractUniqueConstraints(schema) {
+		if len(uc.Properties) == 1 {
+			continue // x-unique, reported above
+		}
+		for _, name := range uc.Properties {
+			if encrypted.properties[name] {
+				errs = append(errs, fmt.Errorf("x-indexes: unique index on %s cannot include x-encrypted property %q: %w",
+					strings.Join(uc.Properties, ", "), name, ErrValidation))
+			}
+		}
+	}
+	if len(s.DecryptRoles) > 0 {
+		var roles []string
+		if json.Unmarshal(s.DecryptRoles, &roles) != nil || slices.ContainsFunc(roles, func(role string) bool {
+			return strings.TrimSpace(role) == ""
+		}) {
+			errs = append(errs, fmt.Errorf("x-decrypt-roles must be a list of role names: %w", ErrValidation))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isStringProperty reports whether a JSON Schema type is "string", alone
+// or with "null".
+func isStringProperty(raw json.RawMessage) bool {
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return single == "string"
+	}
+	var types []string
+	if json.Unmarshal(raw, &types) != nil {
+		return false
+	}
+	hasString := false
+	for _, t := range types {
+		switch t {
+		case "string":
+			hasString = true
+		case "null":
+		default:
+			return false
+		}
+	}
+	return hasString
+}
+
+// checkEncryptedQuery rejects filtering and sorting on x-encrypted
+// properties: their stored values are ciphertext, so the database cannot
+// compare them.
+func checkEncryptedQuery(
+	fields encryptedFields, filters []repositories.FilterCondition, sort repositories.SortOptions,
+) error {
+	for _, f := range filters {
+		if name, ok := fields.match(f.Field); ok {
+			return fmt.Errorf("cannot filter on %q: the property is encrypted: %w", name, ErrValidation)
+		}
+	}
+	if name, ok := fields.match(sort.SortBy); ok {
+		return fmt.Errorf("cannot sort on %q: the property is encrypted: %w", name, ErrValidation)
+	}
+	return nil
+}
+
+// match returns the x-encrypted property a field or column name refers to.
+func (f encryptedFields) match(field string) (string, bool) {
+	if field == "" {
+		return "", false
+	}
+	column := utils.CamelToSnake(field)
+	for name := range f.properties {
+		if name == field || utils.CamelToSnake(name) == column {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// fieldEncryption encrypts x-encrypted values before a resource is
+// committed, and decrypts them for callers whose role may read them.
+type fieldEncryption struct {
+	keys     entities.FieldKeyProvider
+	accounts authrepos.AccountRepository
+	logger   entities.Logger
+}
+
+// encryptedAAD binds a value to the resource and property it belongs to, so
+// it cannot be copied into a resource its reader has access to.
+func encryptedAAD(resourceID, property string) string {
+	return resourceID + "#" + property
+}
+
+// encrypt returns data, a resource's flat JSON object, with its x-encrypted
+// values encrypted.
+func (f fieldEncryption) encrypt(
+	ctx context.Context, fields encryptedFields, resourceID string, data json.RawMessage,
+) (json.RawMessage, error) {
+	if len(fields.properties) == 0 {
+		return data, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse resource data: %w", err)
+	}
+	changed := false
+	for name := range fields.properties {
+		value, ok := m[name]
+		if !ok || value == nil {
+			continue
+		}
+		if f.keys == nil {
+			return nil, ErrNoFieldKey
+		}
+		keyID, key, err := f.keys.CurrentKey(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the field encryption key: %w", err)
+		}
+		aad := encryptedAAD(resourceID, name)
+		if m[name], err = sealToken(encryptedTokenPrefix, keyID, key, aad, value); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(m)
+}
+
+// prepareUpdate readies the data of an update before it is validated. Values
+// the resource already holds encrypted, as a Transition passes them back,
+// are decrypted so they are validated and encrypted as plaintext. A caller
+// who cannot read the encrypted values cannot see them to send them back
+// either, so the values they leave out are kept.
+func (f fieldEncryption) prepareUpdate(
+	ctx context.Context, fields encryptedFields, entity *entities.Resource, data json.RawMessage,
+) (json.RawMessage, error) {
+	if len(fields.properties) == 0 {
+		return data, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		// Left for schema validation to report.
+		return data, nil
+	}
+	var stored map[string]any
+	if !f.reader(fields).mayRead(ctx, entity.AccountID()) {
+		var doc map[string]any
+		if err := json.Unmarshal(entity.Data(), &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode resource data: %w", err)
+		}
+		stored = make(map[string]any)
+		for _, node := range dataNodes(doc) {
+			for name := range fields.properties {
+				if v, ok := node[name]; ok {
+					stored[name] = v
+				}
+			}
+		}
+	}
+	for name := range fields.properties {
+		value, ok := m[name]
+		if !ok {
+			value, ok = stored[name]
+		}
+		if !ok {
+			continue
+		}
+		if token, isToken := value.(string); isToken && strings.HasPrefix(token, encryptedTokenPrefix) {
+			plain, ok := f.decryptValue(ctx, entity.GetID(), name, token)
+			if !ok {
+				return nil, fmt.Errorf("failed to decrypt the stored value of %q", name)
+			}
+			value = plain
+		}
+		m[name] = value
+	}
+	return json.Marshal(m)
+}
+
+// decryptValue decrypts one stored value. It returns false when the value
+// cannot be decrypted, which is logged.
+func (f fieldEncryption) decryptValue(ctx context.Context, resourceID, property string, value any) (any, bool) {
+	token, ok := value.(string)
+	if !ok || !strings.HasPrefix(token, encryptedTokenPrefix) {
+		// Stored before the property was encrypted.
+		return value, true
+	}
+	if f.keys == nil {
+		f.logger.Warn(ctx, "cannot decrypt an x-encrypted value without a field encryption key",
+			"id", resourceID, "property", property)
+		return nil, false
+	}
+	key, err := f.keys.Key(ctx, tokenKeyID(encryptedTokenPrefix, token))
+	if err == nil {
+		var plain any
+		aad := encryptedAAD(resourceID, property)
+		if plain, err = openToken(encryptedTokenPrefix, key, aad, token); err == nil {
+			return plain, true
+		}
+	}
+	f.logger.Warn(ctx, "failed to decrypt an x-encrypted value", "id", resourceID, "property", property,
+		"error", err)
+	return nil, false
+}
+
+// reader returns an encryptedReader for one call's results, or nil when
+// there are no x-encrypted properties.
+func (f fieldEncryption) reader(fields encryptedFields) *encryptedReader {
+	if len(fields.properties) == 0 {
+		return nil
+	}
+	return &encryptedReader{f: f, fields: fields, allowed: make(map[string]bool)}
+}
+
+// encryptedReader reveals the x-encrypted values of the resources one call
+// returns: decrypted for callers who may read them, left out otherwise.
+type encryptedReader struct {
+	f       fieldEncryption
+	fields  encryptedFields
+	allowed map[string]bool // by account ID
+}
+
+// mayRead reports whether the caller may read encrypted values of
+// resources in accountID. System callers (CLI, MCP over stdio) may.
+func (r *encryptedReader) mayRead(ctx context.Context, accountID string) bool {
+	ident := auth.AgentFromCtx(ctx)
+	if ident == nil {
+		return true
+	}
+	if accountID == "" || r.f.accounts == nil {
+		return false
+	}
+	allowed, ok := r.allowed[accountID]
+	if !ok {
+		role, err := r.f.accounts.FindMemberRole(ctx, accountID, ident.AgentID)
+		allowed = err == nil && r.fields.roles[role]
+		r.allowed[accountID] = allowed
+	}
+	return allowed
+}
+
+// reveal decrypts or removes the x-encrypted values of one node of resource
+// data, in place.
+func (r *encryptedReader) reveal(ctx context.Context, resourceID string, readable bool, node map[string]any) {
+	for name := range r.fields.properties {
+		value, ok := node[name]
+		if !ok {
+			continue
+		}
+		if !readable {
+			delete(node, name)
+			continue
+		}
+		if plain, ok := r.f.decryptValue(ctx, resourceID, name, value); ok {
+			node[name] = plain
+		} else {
+			delete(node, name)
+		}
+	}
+}
+
+// resource returns the entity with its encrypted values revealed.
+func (r *encryptedReader) resource(ctx context.Context, entity *entities.Resource) *entities.Resource {
+	if r == nil || entity == nil {
+		return entity
+	}
+	var data map[string]any
+	if json.Unmarshal(entity.Data(), &data) != nil {
+		return entity
+	}
+	raw, err := json.Marshal(r.data(ctx, entity.GetID(), entity.AccountID(), data))
+	if err != nil {
+		return entity
+	}
+	revealed := &entities.Resource{}
+	if err := revealed.Restore(entity.GetID(), entity.TypeSlug(), entity.Status(), raw,
+		entity.CreatedBy(), entity.AccountID(), entity.CreatedAt(), entity.GetSequenceNo()); err != nil {
+		return entity
+	}
+	return revealed
+}
+
+// data returns the data of the resource resourceID, in accountID, with its
+// encrypted values revealed.
+func (r *encryptedReader) data(ctx context.Context, resourceID, accountID string, data map[string]any) map[string]any {
+	if r == nil {
+		return data
+	}
+	readable := r.mayRead(ctx, accountID)
+	return withDataNodes(data, func(node map[string]any) {
+		r.reveal(ctx, resourceID, readable, node)
+	})
+}
+
+// row reveals the encrypted values of a flat projection row, in place.
+// Row keys are the camelCase column names.
+func (r *encryptedReader) row(ctx context.Context, row map[string]any) {
+	if r == nil || row == nil {
+		return
+	}
+	id, _ := row["id"].(string)
+	accountID, _ := row["accountId"].(string)
+	readable := r.mayRead(ctx, accountID)
+	for key, value := range row {
+		name, ok := r.fields.match(key)
+		if !ok {
+			continue
+		}
+		if !readable {
+			delete(row, key)
+			continue
+		}
+		if plain, ok := r.f.decryptValue(ctx, id, name, value); ok {
+			row[key] = plain
+		} else {
+			delete(row, key)
+		}
+	}
+}
+
+// withoutEncrypted returns resource data with its x-encrypted values left out.
+func withoutEncrypted(fields encryptedFields, data json.RawMessage) json.RawMessage {
+	if len(fields.properties) == 0 {
+		return data
+	}
+	var doc map[string]any
+	if json.Unmarshal(data, &doc) != nil {
+		return data
+	}
+	doc = withDataNodes(doc, func(node map[string]any) {
+		for name := range fields.properties {
+			delete(node, name)
+		}
+	})
+	stripped, err := json.Marshal(doc)
+	if err != nil {
+		return data
+	}
+	return stripped
+}
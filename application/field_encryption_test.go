@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+type staticFieldKey []byte
+
+func (k staticFieldKey) CurrentKey(context.Context) (string, []byte, error) { return "k1", k, nil }
+
+func (k staticFieldKey) Key(_ context.Context, id string) ([]byte, error) {
+	if id != "k1" {
+		return nil, errors.New("unknown key")
+	}
+	return k, nil
+}
+
+func TestFieldEncryption_EncryptAndReveal(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	fields := encryptedFieldsOf(json.RawMessage(`{"properties":{
+		"name":{"type":"string"},"ssn":{"type":"string","x-encrypted":true}}}`))
+	f := fieldEncryption{keys: staticFieldKey(make([]byte, 32)), logger: noopLogger{}}
+
+	data, err := f.encrypt(ctx, fields, "urn:employee:1", json.RawMessage(`{"name":"Grace","ssn":"078-05-1120"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	token, _ := m["ssn"].(string)
+	if !strings.HasPrefix(token, encryptedTokenPrefix+"k1:") || m["name"] != "Grace" {
+		t.Fatalf("encrypted data = %s", data)
+	}
+
+	// A system caller reads the value; a token copied to another resource
+	// does not decrypt.
+	row := map[string]any{"id": "urn:employee:1", "name": "Grace", "ssn": token}
+	f.reader(fields).row(ctx, row)
+	if row["ssn"] != "078-05-1120" {
+		t.Errorf("revealed ssn = %v", row["ssn"])
+	}
+	moved := map[string]any{"id": "urn:employee:2", "ssn": token}
+	f.reader(fields).row(ctx, moved)
+	if _, ok := moved["ssn"]; ok {
+		t.Errorf("a token moved to another resource decrypted to %v", moved["ssn"])
+	}
+
+	if _, err := (fieldEncryption{}).encrypt(ctx, fields, "urn:employee:1", data); !errors.Is(err, ErrNoFieldKey) {
+		t.Errorf("encrypt without a key = %v, want ErrNoFieldKey", err)
+	}
+}
+
+func TestCheckEncryptedQuery(t *testing.T) {
+	t.Parallel()
+	fields := encryptedFieldsOf(json.RawMessage(`{"properties":{"taxId":{"type":"string","x-encrypted":true}}}`))
+	for _, tc := range []struct {
+		filters []repositories.FilterCondition
+		sort    repositories.SortOptions
+		ok      bool
+	}{
+		{filters: []repositories.FilterCondition{{Field: "name", Operator: "eq", Value: "x"}}, ok: true},
+		{filters: []repositories.FilterCondition{{Field: "taxId", Operator: "eq", Value: "x"}}},
+		{sort: repositories.SortOptions{SortBy: "tax_id"}},
+	} {
+		err := checkEncryptedQuery(fields, tc.filters, tc.sort)
+		if (err == nil) != tc.ok || (err != nil && !errors.Is(err, ErrValidation)) {
+			t.Errorf("checkEncryptedQuery(%v, %v) = %v", tc.filters, tc.sort, err)
+		}
+	}
+}
+
+func TestValidateEncryptedProperties(t *testing.T) {
+	t.Parallel()
+	for schema, ok := range map[string]bool{
+		`{"properties":{"ssn":{"type":"string","x-encrypted":true}}}`:                          true,
+		`{"properties":{"ssn":{"type":["string","null"],"x-encrypted":true}}}`:                 true,
+		`{"properties":{"ssn":{"type":"string","x-encrypted":true}},"x-decrypt-roles":["hr"]}`: true,
+		`{"properties":{"age":{"type":"integer","x-encrypted":true}}}`:                         false,
+		`{"properties":{"ssn":{"type":"string","x-encrypted":"yes"}}}`:                         false,
+		`{"properties":{"ssn":{"type":"string","x-encrypted":true,"x-unique":true}}}`:          false,
+		`{"properties":{"ssn":{"type":"string"}},"x-decrypt-roles":[""]}`:                      false,
+		`{"properties":{"ssn":{"type":"string","x-encrypted":true},"name":{"type":"string"}},
+			"x-indexes":[{"properties":["name","ssn"],"unique":true}]}`: false,
+		`{"properties":{"ssn":{"type":"string","x-encrypted":true},"name":{"type":"string"}},
+			"x-indexes":[{"properties":["name","ssn"]}]}`: true,
+	} {
+		if err := validateEncryptedProperties(json.RawMessage(schema)); (err == nil) != ok {
+			t.Errorf("validateEncryptedProperties(%s) = %v", schema, err)
+		}
+	}
+}
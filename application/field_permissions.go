@@ -0,0 +1,286 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/jsonld"
+	"github.com/wepala/weos/v3/pkg/utils"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+)
+
+// fieldPermissions applies the field rules of the role-access settings: the
+// properties of a resource type each role may read and write. Owners, admins
+// and system callers (CLI, MCP over stdio) are not restricted.
+type fieldPermissions struct {
+	access   repositories.RoleResourceAccessRepository
+	accounts authrepos.AccountRepository
+}
+
+// fieldRule is the caller's field rule for one resource type.
+type fieldRule struct {
+	role       string
+	readable   map[string]bool // nil: every property
+	writable   map[string]bool // nil: every property
+	properties map[string]bool // the type's schema properties
+	// serverSet are the computed and workflow properties, which the server
+	// sets: x-computed overwrites them and transitions carry their own roles.
+	serverSet map[string]bool
+	ldContext json.RawMessage
+}
+
+// rule returns the caller's field rule for rt, or nil when the caller's
+// role has none or the call carries out x-on-delete policies.
+func (p fieldPermissions) rule(ctx context.Context, rt *entities.ResourceType) (*fieldRule, error) {
+	if p.access == nil || p.accounts == nil || rt == nil {
+		return nil, nil
+	}
+	ident := auth.AgentFromCtx(ctx)
+	if ident == nil || isPolicyWrite(ctx) {
+		return nil, nil
+	}
+	accountID, role, err := callerRole(ctx, p.accounts, ident)
+	if err != nil {
+		return nil, err
+	}
+	if role == authentities.RoleOwner || role == authentities.RoleAdmin {
+		return nil, nil
+	}
+	rules, err := p.access.GetFieldAccessMap(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load field access rules: %w", err)
+	}
+	access, ok := rules[role][rt.Slug()]
+	if !ok || (access.Read == nil && access.Write == nil) {
+		return nil, nil
+	}
+	r := &fieldRule{
+		role:      role,
+		readable:  nameSet(access.Read),
+		writable:  nameSet(access.Write),
+		serverSet: make(map[string]bool),
+		ldContext: rt.Context(),
+	}
+	var s struct {
+		Properties map[string]map[string]json.RawMessage `json:"properties"`
+	}
+	if json.Unmarshal(rt.Schema(), &s) == nil {
+		r.properties = make(map[string]bool, len(s.Properties))
+		for name, prop := range s.Properties {
+			r.properties[name] = true
+			if _, computed := prop["x-computed"]; computed {
+				r.serverSet[name] = true
+			}
+		}
+	}
+	if wf, err := workflowOf(rt); err == nil && wf != nil {
+		r.serverSet[wf.property] = true
+	}
+	return r, nil
+}
+
+// callerRole returns the caller's account and role in it: their active
+// account, or the first account they belong to when none is active, as the
+// resource route authorization does.
+func callerRole(
+	ctx context.Context, accounts authrepos.AccountRepository, ident *auth.Identity,
+) (accountID, role string, err error) {
+	accountID = ident.ActiveAccountID
+	if accountID == "" {
+		members, err := accounts.FindByMember(ctx, ident.AgentID)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to find member accounts: %w", err)
+		}
+		if len(members) == 0 {
+			return "", "", nil
+		}
+		accountID = members[0].GetID()
+	}
+	role, err = accounts.FindMemberRole(ctx, accountID, ident.AgentID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find member role: %w", err)
+	}
+	return accountID, role, nil
+}
+
+func nameSet(names []string) map[string]bool {
+	if names == nil {
+		return nil
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+func (r *fieldRule) mayRead(name string) bool {
+	return r.readable == nil || r.readable[name]
+}
+
+func (r *fieldRule) mayWrite(name string) bool {
+	return r.writable == nil || r.writable[name] || r.serverSet[name]
+}
+
+// property returns the schema property a field, column or <field>Display
+// column name refers to.
+func (r *fieldRule) property(key string) (string, bool) {
+	if r.properties[key] {
+		return key, true
+	}
+	column := utils.CamelToSnake(key)
+	for name := range r.properties {
+		snake := utils.CamelToSnake(name)
+		if snake == column || snake+"_display" == column {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// checkQuery rejects filtering and sorting on properties the caller may not
+// read, which would otherwise reveal their values.
+func (r *fieldRule) checkQuery(filters []repositories.FilterCondition, sort repositories.SortOptions) error {
+	if r == nil {
+		return nil
+	}
+	for _, f := range filters {
+		if name, ok := r.property(f.Field); ok && !r.mayRead(name) {
+			return fmt.Errorf("cannot filter on %q: the property is not readable by role %q: %w",
+				name, r.role, ErrValidation)
+		}
+	}
+	if sort.SortBy == "" {
+		return nil
+	}
+	if name, ok := r.property(sort.SortBy); ok && !r.mayRead(name) {
+		return fmt.Errorf("cannot sort on %q: the property is not readable by role %q: %w",
+			name, r.role, ErrValidation)
+	}
+	return nil
+}
+
+// checkCreate rejects a create that sets properties the caller may not write.
+func (r *fieldRule) checkCreate(data json.RawMessage) error {
+	if r == nil {
+		return nil
+	}
+	var m map[string]any
+	if json.Unmarshal(data, &m) != nil {
+		return nil // schema validation reports malformed data
+	}
+	var denied []string
+	for name, value := range m {
+		if value != nil && r.properties[name] && !r.mayWrite(name) {
+			denied = append(denied, name)
+		}
+	}
+	return r.writeDenied(denied)
+}
+
+// prepareUpdate checks an update against the caller's rule and fills in what
+// the caller could not send. A property the caller may not write may only be
+// sent back unchanged, and keeps its stored value when left out; so does a
+// property the caller may not read. stored is the resource's current flat
+// data.
+func (r *fieldRule) prepareUpdate(stored map[string]any, data json.RawMessage) (json.RawMessage, error) {
+	if r == nil {
+		return data, nil
+	}
+	var m map[string]any
+	if json.Unmarshal(data, &m) != nil || m == nil {
+		return data, nil // schema validation reports malformed data
+	}
+	var denied []string
+	for name := range r.properties {
+		sent, isSent := m[name]
+		current, isStored := stored[name]
+		switch {
+		case !r.mayWrite(name):
+			if isSent && !reflect.DeepEqual(sent, current) {
+				denied = append(denied, name)
+			} else if isStored {
+				m[name] = current
+			}
+		case !isSent && isStored && !r.mayRead(name):
+			m[name] = current
+		}
+	}
+	if err := r.writeDenied(denied); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+func (r *fieldRule) writeDenied(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return fmt.Errorf("role %q may not write %s: %w",
+		r.role, strings.Join(names, ", "), entities.ErrAccessDenied)
+}
+
+// resource returns the entity without the properties the caller may not
+// read, from both the entity node and the edges node of its graph.
+func (r *fieldRule) resource(entity *entities.Resource) *entities.Resource {
+	if r == nil || entity == nil {
+		return entity
+	}
+	var data map[string]any
+	if json.Unmarshal(entity.Data(), &data) != nil {
+		return entity
+	}
+	raw, err := json.Marshal(r.data(data))
+	if err != nil {
+		return entity
+	}
+	restricted := &entities.Resource{}
+	if err := restricted.Restore(entity.GetID(), entity.TypeSlug(), entity.Status(), raw,
+		entity.CreatedBy(), entity.AccountID(), entity.CreatedAt(), entity.GetSequenceNo()); err != nil {
+		return entity
+	}
+	return restricted
+}
+
+// data returns resource data without the properties the caller may not
+// read, from every node of its graph.
+func (r *fieldRule) data(data map[string]any) map[string]any {
+	if r == nil {
+		return data
+	}
+	reverse := jsonld.BuildReverseMap(r.ldContext)
+	return withDataNodes(data, func(node map[string]any) {
+		for key := range node {
+			name := key
+			if prop, ok := reverse[key]; ok {
+				name = prop
+			}
+			if r.properties[name] && !r.mayRead(name) {
+				delete(node, key)
+			}
+		}
+	})
+}
+
+// row removes the columns the caller may not read from a flat projection
+// row, in place, including the <field>Display columns of references.
+func (r *fieldRule) row(row map[string]any) {
+	if r == nil {
+		return
+	}
+	for key := range row {
+		if name, ok := r.property(key); ok && !r.mayRead(name) {
+			delete(row, key)
+		}
+	}
+}
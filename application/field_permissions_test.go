@@ -0,0 +1,84 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+func testFieldRule() *fieldRule {
+	return &fieldRule{
+		role:       "member",
+		readable:   nameSet([]string{"name", "manager", "status"}),
+		writable:   nameSet([]string{"name"}),
+		properties: nameSet([]string{"name", "salary", "manager", "status"}),
+		serverSet:  nameSet([]string{"status"}),
+		ldContext:  json.RawMessage(`{"@vocab":"https://schema.org/","manager":"https://schema.org/employee"}`),
+	}
+}
+
+func TestFieldRule_PrepareUpdate(t *testing.T) {
+	t.Parallel()
+	r := testFieldRule()
+	stored := map[string]any{"name": "Ada", "salary": "90000", "manager": "urn:staff:1", "status": "draft"}
+
+	data, err := r.prepareUpdate(stored, json.RawMessage(`{"name":"Ada L","manager":"urn:staff:1","status":"review"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["name"] != "Ada L" || got["salary"] != "90000" || got["status"] != "review" {
+		t.Errorf("prepared update = %v", got)
+	}
+
+	_, err = r.prepareUpdate(stored, json.RawMessage(`{"name":"Ada","manager":"urn:staff:2"}`))
+	if !errors.Is(err, entities.ErrAccessDenied) {
+		t.Errorf("changing a read-only reference: err = %v, want ErrAccessDenied", err)
+	}
+	if err := r.checkCreate(json.RawMessage(`{"name":"Ada","salary":"1"}`)); !errors.Is(err, entities.ErrAccessDenied) {
+		t.Errorf("creating with a salary: err = %v, want ErrAccessDenied", err)
+	}
+	if err := r.checkCreate(json.RawMessage(`{"name":"Ada","salary":null}`)); err != nil {
+		t.Errorf("creating without a salary: err = %v", err)
+	}
+}
+
+func TestFieldRule_Reads(t *testing.T) {
+	t.Parallel()
+	r := testFieldRule()
+	r.readable = nameSet([]string{"name"})
+
+	entity := &entities.Resource{}
+	if err := entity.Restore("urn:staff:2", "staff", "active", json.RawMessage(`{"@graph":[
+		{"@id":"urn:staff:2","name":"Ada","salary":"90000"},
+		{"@id":"urn:staff:2","https://schema.org/employee":{"@id":"urn:staff:1"}}
+	]}`), "", "", time.Now(), 1); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(r.resource(entity).Data(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	nodes := dataNodes(doc)
+	if nodes[0]["name"] != "Ada" || nodes[0]["salary"] != nil || nodes[1]["https://schema.org/employee"] != nil {
+		t.Errorf("restricted graph = %v", nodes)
+	}
+
+	row := map[string]any{"id": "urn:staff:2", "name": "Ada", "salary": "90000", "managerDisplay": "Grace"}
+	r.row(row)
+	if len(row) != 2 || row["name"] != "Ada" {
+		t.Errorf("restricted row = %v", row)
+	}
+
+	err := r.checkQuery(nil, repositories.SortOptions{SortBy: "salary"})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("sorting on the salary: err = %v, want ErrValidation", err)
+	}
+}
@@ -155,6 +155,12 @@ func (f *fakeResourceSvc) Update(context.Context, UpdateResourceCommand) (*entit
 	return nil, nil //nolint:nilnil
 }
 func (f *fakeResourceSvc) Delete(context.Context, DeleteResourceCommand) error { return nil }
+func (f *fakeResourceSvc) Transition(context.Context, TransitionResourceCommand) (*entities.Resource, error) {
+	return nil, nil //nolint:nilnil
+}
+func (f *fakeResourceSvc) AvailableTransitions(context.Context, string) ([]AvailableTransition, error) {
+	return nil, nil
+}
 
 // --- helpers ---
 
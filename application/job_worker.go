@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"go.uber.org/fx"
+)
+
+// Job retry backoff: the first retry waits jobBackoffBase, and each later one
+// doubles the wait, up to jobBackoffMax.
+const (
+	jobBackoffBase = 10 * time.Second
+	jobBackoffMax  = time.Hour
+)
+
+// jobBackoff returns how long to wait before retrying a job that has failed
+// attempts times.
+func jobBackoff(attempts int) time.Duration {
+	d := jobBackoffBase
+	for i := 1; i < attempts && d < jobBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, jobBackoffMax)
+}
+
+// JobWorker runs queued jobs and enqueues recurring ones when their cron
+// schedule fires. Any number of workers can share a database: claims and
+// schedule advances are conditional updates, so each job and each scheduled
+// run is taken by one worker.
+type JobWorker struct {
+	jobs      repositories.JobRepository
+	schedules repositories.JobScheduleRepository
+	service   JobService
+	registry  JobRegistry
+	logger    entities.Logger
+
+	// Concurrency caps how many jobs this worker runs at once. Default 4.
+	Concurrency int
+	// PollInterval is how often the worker looks for due work. Default 1s.
+	PollInterval time.Duration
+
+	running atomic.Int64
+	wg      sync.WaitGroup
+}
+
+func ProvideJobWorker(params struct {
+	fx.In
+	Jobs      repositories.JobRepository
+	Schedules repositories.JobScheduleRepository
+	Service   JobService
+	Registry  JobRegistry
+	Logger    entities.Logger
+}) *JobWorker {
+	return &JobWorker{
+		jobs:         params.Jobs,
+		schedules:    params.Schedules,
+		service:      params.Service,
+		registry:     params.Registry,
+		logger:       params.Logger,
+		Concurrency:  4,
+		PollInterval: time.Second,
+	}
+}
+
+// Run polls for work until ctx is done, then waits for running jobs to finish.
+func (w *JobWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+	w.logger.Info(ctx, "job worker started",
+		"concurrency", w.Concurrency, "jobs", len(w.registry))
+	for {
+		if _, err := w.Tick(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			w.logger.Error(ctx, "job worker poll failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			w.Wait()
+			w.logger.Info(context.Background(), "job worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick enqueues scheduled runs that are due at now and starts as many due
+// jobs as the concurrency limits allow. It returns the number of jobs
+// started; they run in the background (see Wait).
+func (w *JobWorker) Tick(ctx context.Context, now time.Time) (int, error) {
+	names := make([]string, 0, len(w.registry))
+	for name := range w.registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := w.enqueueScheduled(ctx, w.registry[name], now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	started := 0
+	for _, name := range names {
+		def := w.registry[name]
+		free := w.Concurrency - int(w.running.Load())
+		if free <= 0 {
+			break
+		}
+		claimed, err := w.jobs.Claim(ctx, name, now, now.Add(def.timeout()+w.PollInterval),
+			free, def.concurrency())
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, job := range claimed {
+			w.running.Add(1)
+			w.wg.Add(1)
+			go w.execute(ctx, def, job)
+			started++
+		}
+	}
+	if len(errs) > 0 {
+		return started, fmt.Errorf("job worker: %w", errs[0])
+	}
+	return started, nil
+}
+
+// Wait blocks until every job started by Tick has finished.
+func (w *JobWorker) Wait() {
+	w.wg.Wait()
+}
+
+// enqueueScheduled enqueues one run of a recurring job if its schedule is
+// due. Runs missed while no worker was up collapse into one.
+func (w *JobWorker) enqueueScheduled(ctx context.Context, def JobDefinition, now time.Time) error {
+	if def.Schedule == "" {
+		return nil
+	}
+	sched, err := parseCron(def.Schedule)
+	if err != nil {
+		return err // rejected at registration; kept for safety
+	}
+	next := sched.Next(now)
+	due, err := w.schedules.EnsureSchedule(ctx, def.Name, next)
+	if err != nil {
+		return err
+	}
+	if due.After(now) {
+		return nil
+	}
+	won, err := w.schedules.AdvanceSchedule(ctx, def.Name, due, next)
+	if err != nil || !won {
+		return err
+	}
+	_, err = w.service.Enqueue(ctx, EnqueueJobCommand{Name: def.Name, RunAt: due})
+	return err
+}
+
+func (w *JobWorker) execute(ctx context.Context, def JobDefinition, job *entities.Job) {
+	defer w.wg.Done()
+	defer w.running.Add(-1)
+
+	jobCtx, cancel := context.WithTimeout(ctx, def.timeout())
+	err := runJobHandler(jobCtx, def.Handler, job)
+	cancel()
+
+	// Record the outcome even when the worker is shutting down.
+	ctx = context.WithoutCancel(ctx)
+	var recErr error
+	switch {
+	case err == nil:
+		recErr = w.jobs.Complete(ctx, job.ID, job.Attempts)
+	case job.Attempts >= job.MaxAttempts:
+		w.logger.Error(ctx, "job dead-lettered",
+			"job", job.ID, "name", job.Name, "attempts", job.Attempts, "error", err)
+		recErr = w.jobs.Bury(ctx, job.ID, job.Attempts, err.Error())
+	default:
+		retryAt := time.Now().Add(jobBackoff(job.Attempts))
+		w.logger.Warn(ctx, "job failed, will retry",
+			"job", job.ID, "name", job.Name, "attempt", job.Attempts, "retryAt", retryAt, "error", err)
+		recErr = w.jobs.Retry(ctx, job.ID, job.Attempts, err.Error(), retryAt)
+	}
+	switch {
+	case errors.Is(recErr, repositories.ErrJobLeaseLost):
+		// The attempt outlived its lease and the job was claimed again; the
+		// newer attempt records the outcome.
+		w.logger.Warn(ctx, "job outcome discarded, lease lost",
+			"job", job.ID, "name", job.Name, "attempt", job.Attempts)
+	case recErr != nil:
+		w.logger.Error(ctx, "failed to record job outcome", "job", job.ID, "error", recErr)
+	}
+}
+
+// runJobHandler calls the handler, turning a panic into a failed attempt.
+func runJobHandler(ctx context.Context, handler JobHandler, job *entities.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job.Payload)
+}
@@ -0,0 +1,376 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+// memJobStore is an in-memory JobRepository and JobScheduleRepository.
+type memJobStore struct {
+	mu        sync.Mutex
+	jobs      map[string]*entities.Job
+	schedules map[string]time.Time
+}
+
+func newMemJobStore() *memJobStore {
+	return &memJobStore{jobs: map[string]*entities.Job{}, schedules: map[string]time.Time{}}
+}
+
+func (m *memJobStore) Enqueue(_ context.Context, job *entities.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *job
+	m.jobs[job.ID] = &cp
+	return nil
+}
+
+func (m *memJobStore) Claim(
+	_ context.Context, name string, now, leaseUntil time.Time, limit, concurrency int,
+) ([]*entities.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, j := range m.jobs {
+		if j.Name == name && j.Status == entities.JobRunning && !j.LockedUntil.Before(now) {
+			concurrency--
+		}
+	}
+	limit = min(limit, concurrency)
+	var claimed []*entities.Job
+	for _, j := range m.jobs {
+		if len(claimed) >= limit {
+			break
+		}
+		if len(claimed) == limit {
+			break
+		}
+		due := j.Status == entities.JobPending && !j.RunAt.After(now)
+		expired := j.Status == entities.JobRunning && j.LockedUntil.Before(now)
+		if j.Name != name || !(due || expired) {
+			continue
+		}
+		j.Status, j.LockedUntil = entities.JobRunning, leaseUntil
+		j.Attempts++
+		cp := *j
+		claimed = append(claimed, &cp)
+	}
+	return claimed, nil
+}
+
+func (m *memJobStore) set(id string, fn func(*entities.Job)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	fn(j)
+	return nil
+}
+
+// finish applies fn while the job is running attempt, like the gorm store.
+func (m *memJobStore) finish(id string, attempt int, fn func(*entities.Job)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok || j.Status != entities.JobRunning || j.Attempts != attempt {
+		return repositories.ErrJobLeaseLost
+	}
+	fn(j)
+	return nil
+}
+
+func (m *memJobStore) Complete(_ context.Context, id string, attempt int) error {
+	return m.finish(id, attempt, func(j *entities.Job) { j.Status = entities.JobSucceeded })
+}
+
+func (m *memJobStore) Retry(_ context.Context, id string, attempt int, lastError string, runAt time.Time) error {
+	return m.finish(id, attempt, func(j *entities.Job) {
+		j.Status, j.LastError, j.RunAt = entities.JobPending, lastError, runAt
+	})
+}
+
+func (m *memJobStore) Bury(_ context.Context, id string, attempt int, lastError string) error {
+	return m.finish(id, attempt, func(j *entities.Job) { j.Status, j.LastError = entities.JobDead, lastError })
+}
+
+func (m *memJobStore) DeleteSucceeded(_ context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for id, j := range m.jobs {
+		if j.Status == entities.JobSucceeded && j.RunAt.Before(before) {
+			delete(m.jobs, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memJobStore) Requeue(_ context.Context, id string, runAt time.Time) error {
+	return m.set(id, func(j *entities.Job) {
+		j.Status, j.Attempts, j.RunAt = entities.JobPending, 0, runAt
+	})
+}
+
+func (m *memJobStore) FindByID(_ context.Context, id string) (*entities.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	cp := *j
+	return &cp, nil
+}
+
+func (m *memJobStore) FindAllByStatus(
+	_ context.Context, status, _ string, _ int,
+) (repositories.PaginatedResponse[*entities.Job], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*entities.Job
+	for _, j := range m.jobs {
+		if j.Status == status {
+			cp := *j
+			out = append(out, &cp)
+		}
+	}
+	return repositories.PaginatedResponse[*entities.Job]{Data: out}, nil
+}
+
+func (m *memJobStore) EnsureSchedule(_ context.Context, name string, firstRun time.Time) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if due, ok := m.schedules[name]; ok {
+		return due, nil
+	}
+	m.schedules[name] = firstRun
+	return firstRun, nil
+}
+
+func (m *memJobStore) AdvanceSchedule(_ context.Context, name string, due, next time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.schedules[name].Equal(due) {
+		return false, nil
+	}
+	m.schedules[name] = next
+	return true, nil
+}
+
+func (m *memJobStore) only(t *testing.T) *entities.Job {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.jobs) != 1 {
+		t.Fatalf("store has %d jobs, want 1", len(m.jobs))
+	}
+	for _, j := range m.jobs {
+		cp := *j
+		return &cp
+	}
+	return nil
+}
+
+func newTestJobWorker(store *memJobStore, defs ...JobDefinition) *JobWorker {
+	registry := JobRegistry{}
+	for _, def := range defs {
+		if err := registry.Add(def); err != nil {
+			panic(err)
+		}
+	}
+	return &JobWorker{
+		jobs:         store,
+		schedules:    store,
+		service:      &jobService{jobs: store, registry: registry},
+		registry:     registry,
+		logger:       noopLogger{},
+		Concurrency:  4,
+		PollInterval: time.Second,
+	}
+}
+
+// runOnce ticks the worker at now and waits for the jobs it started to finish.
+func runOnce(t *testing.T, w *JobWorker, now time.Time) {
+	t.Helper()
+	if _, err := w.Tick(context.Background(), now); err != nil {
+		t.Fatalf("Tick: %v", err)
+	}
+	w.Wait()
+}
+
+func TestJobWorker_Success(t *testing.T) {
+	t.Parallel()
+	store := newMemJobStore()
+	var got string
+	w := newTestJobWorker(store, JobDefinition{
+		Name: "greet",
+		Handler: func(_ context.Context, payload json.RawMessage) error {
+			return json.Unmarshal(payload, &got)
+		},
+	})
+	if _, err := w.service.Enqueue(context.Background(),
+		EnqueueJobCommand{Name: "greet", Payload: json.RawMessage(`"hello"`)}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	runOnce(t, w, time.Now())
+	if got != "hello" {
+		t.Errorf("handler received %q, want hello", got)
+	}
+	if job := store.only(t); job.Status != entities.JobSucceeded {
+		t.Errorf("status = %s, want succeeded", job.Status)
+	}
+}
+
+func TestJobWorker_RetriesThenDeadLetters(t *testing.T) {
+	t.Parallel()
+	store := newMemJobStore()
+	w := newTestJobWorker(store, JobDefinition{
+		Name:        "flaky",
+		MaxAttempts: 2,
+		Handler:     func(context.Context, json.RawMessage) error { return errors.New("boom") },
+	})
+	if _, err := w.service.Enqueue(context.Background(), EnqueueJobCommand{Name: "flaky"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	before := time.Now()
+	runOnce(t, w, before)
+	job := store.only(t)
+	if job.Status != entities.JobPending || job.Attempts != 1 || job.LastError != "boom" {
+		t.Fatalf("after first failure: %+v, want pending with 1 attempt", job)
+	}
+	if wait := job.RunAt.Sub(before); wait < jobBackoffBase {
+		t.Errorf("retry scheduled %v out, want at least %v", wait, jobBackoffBase)
+	}
+
+	runOnce(t, w, job.RunAt)
+	if job = store.only(t); job.Status != entities.JobDead || job.Attempts != 2 {
+		t.Fatalf("after last attempt: %+v, want dead", job)
+	}
+
+	if err := w.service.Requeue(context.Background(), job.ID); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	if job = store.only(t); job.Status != entities.JobPending || job.Attempts != 0 {
+		t.Errorf("after Requeue: %+v, want pending with no attempts", job)
+	}
+}
+
+func TestJobWorker_PanicFailsAttempt(t *testing.T) {
+	t.Parallel()
+	store := newMemJobStore()
+	w := newTestJobWorker(store, JobDefinition{
+		Name:        "explode",
+		MaxAttempts: 1,
+		Handler:     func(context.Context, json.RawMessage) error { panic("kaboom") },
+	})
+	if _, err := w.service.Enqueue(context.Background(), EnqueueJobCommand{Name: "explode"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	runOnce(t, w, time.Now())
+	if job := store.only(t); job.Status != entities.JobDead || job.LastError != "job panicked: kaboom" {
+		t.Errorf("after panic: %+v, want dead with the panic recorded", job)
+	}
+}
+
+func TestJobWorker_ConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+	store := newMemJobStore()
+	release := make(chan struct{})
+	w := newTestJobWorker(store, JobDefinition{
+		Name:    "serial",
+		Handler: func(context.Context, json.RawMessage) error { <-release; return nil },
+	})
+	ctx := context.Background()
+	for range 3 {
+		if _, err := w.service.Enqueue(ctx, EnqueueJobCommand{Name: "serial"}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	now := time.Now()
+	if n, _ := w.Tick(ctx, now); n != 1 {
+		t.Errorf("first Tick started %d jobs, want 1 (concurrency 1)", n)
+	}
+	if n, _ := w.Tick(ctx, now); n != 0 {
+		t.Errorf("Tick while a job runs started %d, want 0", n)
+	}
+	close(release)
+	w.Wait()
+}
+
+func TestJobWorker_ScheduledRunEnqueuedOnce(t *testing.T) {
+	t.Parallel()
+	store := newMemJobStore()
+	var mu sync.Mutex
+	runs := 0
+	def := JobDefinition{
+		Name:     "every-five",
+		Schedule: "*/5 * * * *",
+		Handler: func(context.Context, json.RawMessage) error {
+			mu.Lock()
+			runs++
+			mu.Unlock()
+			return nil
+		},
+	}
+	a, b := newTestJobWorker(store, def), newTestJobWorker(store, def)
+
+	start := time.Date(2026, 5, 1, 10, 2, 0, 0, time.UTC)
+	runOnce(t, a, start)
+	if len(store.jobs) != 0 {
+		t.Fatalf("a run was enqueued before the schedule fired")
+	}
+
+	fire := start.Add(3 * time.Minute)
+	runOnce(t, a, fire)
+	runOnce(t, b, fire)
+	if runs != 1 {
+		t.Errorf("scheduled job ran %d times, want 1", runs)
+	}
+	if next := store.schedules["every-five"]; !next.Equal(fire.Add(5 * time.Minute)) {
+		t.Errorf("next run = %v, want %v", next, fire.Add(5*time.Minute))
+	}
+}
+
+func TestJobService_EnqueueValidation(t *testing.T) {
+	t.Parallel()
+	svc := &jobService{jobs: newMemJobStore(), registry: JobRegistry{
+		"known": {Name: "known", Handler: func(context.Context, json.RawMessage) error { return nil }},
+	}}
+	ctx := context.Background()
+	if _, err := svc.Enqueue(ctx, EnqueueJobCommand{Name: "unknown"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown job: err = %v, want ErrValidation", err)
+	}
+	if _, err := svc.Enqueue(ctx, EnqueueJobCommand{Name: "known", Payload: json.RawMessage("{")}); !errors.Is(err, ErrValidation) {
+		t.Errorf("invalid payload: err = %v, want ErrValidation", err)
+	}
+	job, err := svc.Enqueue(ctx, EnqueueJobCommand{Name: "known"})
+	if err != nil || job.MaxAttempts != defaultJobMaxAttempts || string(job.Payload) != "null" {
+		t.Errorf("defaults: %+v, %v", job, err)
+	}
+	if err := svc.Requeue(ctx, job.ID); !errors.Is(err, ErrValidation) {
+		t.Errorf("requeue of a pending job: err = %v, want ErrValidation", err)
+	}
+}
+
+func TestJobBackoff(t *testing.T) {
+	t.Parallel()
+	for attempts, want := range map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		4:  80 * time.Second,
+		20: time.Hour,
+	} {
+		if got := jobBackoff(attempts); got != want {
+			t.Errorf("jobBackoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/events"
+	"github.com/wepala/weos/v3/pkg/identity"
+
+	"go.uber.org/fx"
+)
+
+// Job defaults, used when a definition or enqueue leaves them unset.
+const (
+	defaultJobMaxAttempts = 5
+	defaultJobConcurrency = 1
+	defaultJobTimeout     = 5 * time.Minute
+)
+
+// Succeeded jobs are kept for jobRetention, then removed by the
+// jobPruneJobName job. Scheduled jobs that run every minute would otherwise
+// add over a thousand rows a day each. Dead jobs are kept until requeued.
+const (
+	jobPruneJobName = "jobs.prune"
+	jobRetention    = 7 * 24 * time.Hour
+)
+
+// JobHandler runs one job. Handlers run in the system context (no caller
+// identity). Returning an error fails the attempt: the job is retried with
+// exponential backoff, and dead-lettered once it runs out of attempts.
+// Handlers should be idempotent — a worker that dies mid-job leaves it to be
+// run again once its lease expires.
+type JobHandler func(ctx context.Context, payload json.RawMessage) error
+
+// JobDefinition registers a handler with the job worker.
+type JobDefinition struct {
+	Name    string
+	Handler JobHandler
+	// Schedule is an optional cron expression (UTC). Each time it fires the
+	// worker enqueues one run with an empty payload.
+	Schedule string
+	// MaxAttempts before the job is dead-lettered. Default 5.
+	MaxAttempts int
+	// Concurrency caps how many jobs of this name run at once across all
+	// workers sharing the database. Default 1.
+	Concurrency int
+	// Timeout bounds one attempt. Default 5 minutes.
+	Timeout time.Duration
+}
+
+func (d JobDefinition) maxAttempts() int {
+	if d.MaxAttempts > 0 {
+		return d.MaxAttempts
+	}
+	return defaultJobMaxAttempts
+}
+
+func (d JobDefinition) concurrency() int {
+	if d.Concurrency > 0 {
+		return d.Concurrency
+	}
+	return defaultJobConcurrency
+}
+
+func (d JobDefinition) timeout() time.Duration {
+	if d.Timeout > 0 {
+		return d.Timeout
+	}
+	return defaultJobTimeout
+}
+
+// PresetJob declares a background job contributed by a preset. Factory is
+// invoked once at startup with the same services behaviors receive.
+type PresetJob struct {
+	Name        string
+	Schedule    string
+	MaxAttempts int
+	Concurrency int
+	Timeout     time.Duration
+	Factory     func(BehaviorServices) JobHandler
+}
+
+// validateJobs enforces the per-preset invariants on PresetDefinition.Jobs:
+// a name, a factory, a valid schedule, and no duplicate names.
+func validateJobs(def PresetDefinition) error {
+	seen := make(map[string]struct{}, len(def.Jobs))
+	for i, j := range def.Jobs {
+		if j.Name == "" {
+			return fmt.Errorf("preset %q: jobs[%d] name is empty", def.Name, i)
+		}
+		if j.Factory == nil {
+			return fmt.Errorf("preset %q: job %q factory is nil", def.Name, j.Name)
+		}
+		if j.Schedule != "" {
+			if _, err := parseCron(j.Schedule); err != nil {
+				return fmt.Errorf("preset %q: job %q: %w", def.Name, j.Name, err)
+			}
+		}
+		if _, dup := seen[j.Name]; dup {
+			return fmt.Errorf("preset %q: job %q is declared twice", def.Name, j.Name)
+		}
+		seen[j.Name] = struct{}{}
+	}
+	return nil
+}
+
+// JobRegistry maps job names to their definitions.
+type JobRegistry map[string]JobDefinition
+
+// Add registers a definition, failing on an empty name, a nil handler, an
+// invalid schedule, or a name that is already taken.
+func (r JobRegistry) Add(def JobDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("job name must not be empty")
+	}
+	if def.Handler == nil {
+		return fmt.Errorf("job %q: handler is nil", def.Name)
+	}
+	if def.Schedule != "" {
+		if _, err := parseCron(def.Schedule); err != nil {
+			return fmt.Errorf("job %q: %w", def.Name, err)
+		}
+	}
+	if _, dup := r[def.Name]; dup {
+		return fmt.Errorf("job %q is registered twice", def.Name)
+	}
+	r[def.Name] = def
+	return nil
+}
+
+// ProvideJobRegistry builds the job registry from the built-in jobs and every
+// preset's Jobs. Startup fails on a duplicate name or a factory that returns nil.
+func ProvideJobRegistry(params struct {
+	fx.In
+	Registry      *PresetRegistry
+	Jobs          repositories.JobRepository
+	Resources     repositories.ResourceRepository
+	Triples       repositories.TripleRepository
+	ResourceTypes repositories.ResourceTypeRepository
+	Logger        entities.Logger
+	Writer        *lazyResourceWriter
+	Editorial     EditorialService
+	Webhooks      *WebhookDeliverer
+	Secondary     *events.SecondaryWriter
+}) (JobRegistry, error) {
+	jobs := JobRegistry{}
+	builtins := []JobDefinition{
+		{
+			Name:     "editorial.publish-due",
+			Schedule: "* * * * *",
+			Handler: func(ctx context.Context, _ json.RawMessage) error {
+				_, err := params.Editorial.PublishDue(ctx, time.Now())
+				return err
+			},
+		},
+		{
+			Name:     jobPruneJobName,
+			Schedule: "15 3 * * *",
+			Handler: func(ctx context.Context, _ json.RawMessage) error {
+				n, err := params.Jobs.DeleteSucceeded(ctx, time.Now().Add(-jobRetention))
+				if n > 0 {
+					params.Logger.Info(ctx, "pruned succeeded jobs", "count", n)
+				}
+				return err
+			},
+		},
+		{
+			Name:        webhookDeliverJobName,
+			Handler:     params.Webhooks.Deliver,
+			MaxAttempts: webhookMaxAttempts,
+			Concurrency: 8,
+			Timeout:     2 * webhookTimeout,
+		},
+	}
+	if params.Secondary != nil {
+		builtins = append(builtins, JobDefinition{
+			Name:     secondaryResendJobName,
+			Schedule: "* * * * *",
+			Handler:  resendSecondaryEvents(params.Secondary, params.Logger),
+		})
+	}
+	for _, def := range builtins {
+		if err := jobs.Add(def); err != nil {
+			return nil, err
+		}
+	}
+
+	services := BehaviorServices{
+		Resources:     params.Resources,
+		Triples:       params.Triples,
+		ResourceTypes: params.ResourceTypes,
+		Logger:        params.Logger,
+		Writer:        params.Writer,
+	}
+	for _, preset := range params.Registry.List() {
+		for _, pj := range preset.Jobs {
+			handler := pj.Factory(services)
+			if handler == nil {
+				return nil, fmt.Errorf("preset %q: job %q factory returned nil", preset.Name, pj.Name)
+			}
+			if err := jobs.Add(JobDefinition{
+				Name:        pj.Name,
+				Handler:     handler,
+				Schedule:    pj.Schedule,
+				MaxAttempts: pj.MaxAttempts,
+				Concurrency: pj.Concurrency,
+				Timeout:     pj.Timeout,
+			}); err != nil {
+				return nil, fmt.Errorf("preset %q: %w", preset.Name, err)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(jobs))
+	for name := range jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params.Logger.Info(context.Background(), "background jobs registered",
+		"count", len(names), "names", names)
+	return jobs, nil
+}
+
+// EnqueueJobCommand queues one run of a registered job.
+type EnqueueJobCommand struct {
+	Name    string
+	Payload json.RawMessage
+	// RunAt delays the job; the zero value runs it as soon as a worker is free.
+	RunAt time.Time
+	// MaxAttempts overrides the definition's limit when positive.
+	MaxAttempts int
+}
+
+// JobService enqueues background jobs and manages the dead-letter queue.
+// Jobs are run by a JobWorker.
+type JobService interface {
+	Enqueue(ctx context.Context, cmd EnqueueJobCommand) (*entities.Job, error)
+	GetByID(ctx context.Context, id string) (*entities.Job, error)
+	List(ctx context.Context, status, cursor string, limit int) (
+		repositories.PaginatedResponse[*entities.Job], error)
+	// Requeue gives a dead-lettered job a fresh set of attempts.
+	Requeue(ctx context.Context, id string) error
+}
+
+type jobService struct {
+	jobs     repositories.JobRepository
+	registry JobRegistry
+}
+
+func ProvideJobService(params struct {
+	fx.In
+	Jobs     repositories.JobRepository
+	Registry JobRegistry
+}) JobService {
+	return &jobService{jobs: params.Jobs, registry: params.Registry}
+}
+
+func (s *jobService) Enqueue(ctx context.Context, cmd EnqueueJobCommand) (*entities.Job, error) {
+	def, ok := s.registry[cmd.Name]
+	if !ok {
+		return nil, fmt.Errorf("no job named %q is registered: %w", cmd.Name, ErrValidation)
+	}
+	payload := cmd.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage("null")
+	}
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("job payload must be valid JSON: %w", ErrValidation)
+	}
+	maxAttempts := def.maxAttempts()
+	if cmd.MaxAttempts > 0 {
+		maxAttempts = cmd.MaxAttempts
+	}
+	now := time.Now().UTC()
+	runAt := cmd.RunAt
+	if runAt.IsZero() {
+		runAt = now
+	}
+	job := &entities.Job{
+		ID:          identity.NewJob(),
+		Name:        cmd.Name,
+		Payload:     payload,
+		Status:      entities.JobPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt.UTC(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.jobs.Enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *jobService) GetByID(ctx context.Context, id string) (*entities.Job, error) {
+	return s.jobs.FindByID(ctx, id)
+}
+
+func (s *jobService) List(
+	ctx context.Context, status, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.Job], error) {
+	return s.jobs.FindAllByStatus(ctx, status, cursor, limit)
+}
+
+func (s *jobService) Requeue(ctx context.Context, id string) error {
+	job, err := s.jobs.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status != entities.JobDead {
+		return fmt.Errorf("job %s is %s, not dead: %w", id, job.Status, ErrValidation)
+	}
+	return s.jobs.Requeue(ctx, id, time.Now())
+}
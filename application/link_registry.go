@@ -47,6 +47,9 @@ import (
 //     validate it.
 //   - DisplayProperty defaults to "name" when empty; it names the property on
 //     the target whose value is denormalized into <prop>_display.
+//   - OnDelete is the link's equivalent of x-on-delete ("restrict", "cascade"
+//     or "set-null"); empty leaves referrers untouched when the target is
+//     deleted.
 type PresetLinkDefinition struct {
 	Name            string
 	SourceType      string
@@ -54,6 +57,7 @@ type PresetLinkDefinition struct {
 	PropertyName    string
 	PredicateIRI    string
 	DisplayProperty string
+	OnDelete        string
 }
 
 // LinkRegistry holds cross-type link definitions contributed by presets and by
@@ -9,6 +9,7 @@ import (
 	"github.com/wepala/weos/v3/infrastructure/database/gorm"
 	"github.com/wepala/weos/v3/infrastructure/email"
 	"github.com/wepala/weos/v3/infrastructure/events"
+	"github.com/wepala/weos/v3/infrastructure/keys"
 	"github.com/wepala/weos/v3/infrastructure/logging"
 	storageprovider "github.com/wepala/weos/v3/infrastructure/storage/provider"
 	"github.com/wepala/weos/v3/internal/config"
@@ -47,22 +48,27 @@ func Module(cfg config.Config, registry *PresetRegistry) fx.Option {
 		// Database providers
 		fx.Provide(gorm.ProvideGormDB),
 
-		// Event store provider (with optional BigQuery dual-write)
+		// Event store provider (with optional BigQuery dual-write). Every
+		// store is wrapped to stamp payload schema versions and audit
+		// metadata and encrypt personal data on write, and to decrypt and
+		// upcast old payloads on read.
+		fx.Provide(entities.DefaultEventSchemas),
+		fx.Provide(ProvidePIICipher),
 		fx.Provide(gorm.ProvideEventStore),
-		fx.Decorate(func(lc fx.Lifecycle, primary domain.EventStore, cfg config.Config, logger entities.Logger) domain.EventStore {
-			if cfg.BigQueryProjectID == "" {
-				return primary
+		fx.Provide(gorm.ProvidePendingEventRepository),
+		fx.Provide(events.ProvideSecondaryWriter),
+		fx.Decorate(func(
+			primary domain.EventStore, schemas *entities.EventSchemaRegistry, cipher entities.PIICipher,
+			secondary *events.SecondaryWriter, cfg config.Config, logger entities.Logger,
+		) domain.EventStore {
+			if secondary == nil {
+				return events.NewVersionedEventStore(
+					events.NewPIIEventStore(events.NewAuditEventStore(primary), cipher), schemas)
 			}
-			bqStore, err := events.ProvideBigQueryEventStore(cfg)
-			if err != nil || bqStore == nil {
-				logger.Error(context.Background(), "failed to create BigQuery event store, using primary only",
-					"error", err)
-				return primary
-			}
-			lc.Append(fx.Hook{OnStop: func(_ context.Context) error { return bqStore.Close() }})
 			logger.Info(context.Background(), "BigQuery dual-write enabled",
 				"project", cfg.BigQueryProjectID, "dataset", cfg.BigQueryDatasetID)
-			return events.NewDualWriteEventStore(primary, bqStore, logger)
+			return events.NewVersionedEventStore(events.NewPIIEventStore(
+				events.NewAuditEventStore(events.NewDualWriteEventStore(primary, secondary)), cipher), schemas)
 		}),
 
 		// Session store provider (for pericarp auth integration)
@@ -70,6 +76,9 @@ func Module(cfg config.Config, registry *PresetRegistry) fx.Option {
 			return sessions.NewCookieStore([]byte(cfg.SessionSecret))
 		}),
 
+		// Key for x-encrypted properties (nil without FIELD_ENCRYPTION_KEYFILE)
+		fx.Provide(keys.ProvideFieldKeyProvider),
+
 		// Repository providers
 		fx.Provide(gorm.ProvideResourceTypeRepository),
 		fx.Provide(gorm.ProvideProjectionManager),
@@ -77,8 +86,23 @@ func Module(cfg config.Config, registry *PresetRegistry) fx.Option {
 		fx.Provide(gorm.ProvideSidebarSettingsRepository),
 		fx.Provide(gorm.ProvideRoleSettingsRepository),
 		fx.Provide(gorm.ProvideRoleResourceAccessRepository),
+		fx.Provide(func(r *gorm.RoleResourceAccessRepository) repositories.RoleResourceAccessRepository { return r }),
 		fx.Provide(gorm.ProvideTripleRepository),
 		fx.Provide(gorm.ProvideResourcePermissionRepository),
+		fx.Provide(gorm.ProvideTeamRepository),
+		fx.Provide(gorm.ProvideRoleAssignmentRepository),
+		fx.Provide(gorm.ProvidePublishedRevisionRepository),
+		fx.Provide(gorm.ProvideJobRepository),
+		fx.Provide(gorm.ProvideJobScheduleRepository),
+		fx.Provide(gorm.ProvideWebhookSubscriptionRepository),
+		fx.Provide(gorm.ProvideWebhookDeliveryRepository),
+		fx.Provide(gorm.ProvideAuditRepository),
+		fx.Provide(gorm.ProvideSubjectKeyRepository),
+		fx.Provide(gorm.ProvideEventLogRepository),
+		fx.Provide(gorm.ProvideEventCheckpointRepository),
+		fx.Provide(gorm.ProvideEventDeadLetterRepository),
+		fx.Provide(gorm.ProvideReadModelTruncater),
+		fx.Provide(gorm.ProvideSnapshotRepository),
 
 		// Auth repositories (from pericarp)
 		fx.Provide(func(db *gormdb.DB) authrepos.AgentRepository { return authgorm.NewAgentRepository(db) }),
@@ -138,6 +162,22 @@ func Module(cfg config.Config, registry *PresetRegistry) fx.Option {
 		fx.Provide(ProvideResourceTypeService),
 		fx.Provide(ProvideResourceService),
 		fx.Provide(ProvideResourcePermissionService),
+		fx.Provide(ProvideTeamService),
+		fx.Provide(ProvideRoleAuthorizer),
+		fx.Provide(ProvideRoleAssignmentService),
+		fx.Provide(ProvideAuthorizationCheckService),
+		fx.Provide(ProvideEditorialService),
+		fx.Provide(ProvideWebhookDeliverer),
+		fx.Provide(ProvideJobRegistry),
+		fx.Provide(ProvideJobService),
+		fx.Provide(ProvideJobWorker),
+		fx.Provide(ProvideWebhookService),
+		fx.Provide(ProvideResourceReplayer),
+		fx.Provide(ProvideEventHandlerRegistry),
+		fx.Provide(ProvideEventRelay),
+		fx.Provide(ProvideProjectionMaintainer),
+		fx.Provide(ProvidePersonalDataEraser),
+		fx.Provide(ProvideEventStreamService),
 		fx.Provide(storageprovider.ProvideFileService),
 
 		// Install the real ResourceService into the lazy writer proxy now that
@@ -145,8 +185,9 @@ func Module(cfg config.Config, registry *PresetRegistry) fx.Option {
 		// invoke must run before any hook can be called.
 		fx.Invoke(WireResourceWriter),
 
-		// Subscribe event handlers (projections)
-		fx.Invoke(subscribeEventHandlers),
+		// Checkpoint the event handler groups (projections, webhooks) and
+		// route committed events to the inline ones.
+		fx.Invoke(startEventHandlerGroups),
 
 		// Ensure built-in resource types and projection tables at startup
 		fx.Invoke(ensureBuiltInResourceTypes),
@@ -0,0 +1,298 @@
+package application
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+// maxInheritanceDepth bounds how many x-inherit-permissions-from references
+// an access check or list scope follows, so a long or cyclic chain stays
+// cheap. Three covers the presets' deepest chain, a meal occurrence's
+// scheduled meal's meal plan; an ancestor further up grants nothing.
+const maxInheritanceDepth = 3
+
+const (
+	// ancestorCacheSize bounds inheritedAncestors; the least recently
+	// checked resource is evicted past it.
+	ancestorCacheSize = 10000
+	// ancestorCacheTTL bounds how long a resource's ancestors are reused.
+	// Triple changes projected in this process drop them at once; the TTL
+	// covers changes projected by another process.
+	ancestorCacheTTL = time.Minute
+)
+
+// inheritanceCache holds each type's compiled annotation, nil for types
+// without x-inherit-permissions-from.
+var inheritanceCache schemaCache[[]ReferencePropertyDef]
+
+// inheritanceOf returns the references rt inherits permissions through.
+func inheritanceOf(rt *entities.ResourceType) ([]ReferencePropertyDef, error) {
+	return inheritanceCache.get(rt, func(rt *entities.ResourceType) ([]ReferencePropertyDef, error) {
+		return compileInheritance(rt.Schema(), rt.Context())
+	})
+}
+
+// compileInheritance reads the root-level x-inherit-permissions-from
+// annotation, which names the reference properties a resource inherits its
+// permissions from:
+//
+//	"x-inherit-permissions-from": "project"
+//	"x-inherit-permissions-from": ["project", "mealPlan"]
+//
+// A caller may take an action on the resource when they may take it on a
+// resource it references through one of them, following the parent's own
+// annotation up to maxInheritanceDepth references. Each name must be an
+// x-resource-type property. It returns nil when the schema has no
+// annotation. Errors wrap ErrValidation.
+func compileInheritance(schema, ldContext json.RawMessage) ([]ReferencePropertyDef, error) {
+	if len(schema) == 0 {
+		return nil, nil
+	}
+	var s struct {
+		XInherit json.RawMessage `json:"x-inherit-permissions-from"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, nil // the jsonschema compiler reports malformed schemas
+	}
+	if len(s.XInherit) == 0 || string(s.XInherit) == "null" {
+		return nil, nil
+	}
+	var names []string
+	if json.Unmarshal(s.XInherit, &names) != nil {
+		var name string
+		if err := json.Unmarshal(s.XInherit, &name); err != nil {
+			return nil, fmt.Errorf("x-inherit-permissions-from must be a property name or a list of them: %w",
+				ErrValidation)
+		}
+		names = []string{name}
+	}
+
+	refs := make(map[string]ReferencePropertyDef)
+	for _, ref := range ExtractReferenceProperties(schema, ldContext) {
+		refs[ref.PropertyName] = ref
+	}
+	parents := make([]ReferencePropertyDef, 0, len(names))
+	for _, name := range names {
+		ref, ok := refs[name]
+		if !ok {
+			return nil, fmt.Errorf("x-inherit-permissions-from names %q, which is not an x-resource-type property: %w",
+				name, ErrValidation)
+		}
+		parents = append(parents, ref)
+	}
+	return parents, nil
+}
+
+func validateInheritance(schema json.RawMessage) error {
+	_, err := compileInheritance(schema, nil)
+	return err
+}
+
+// permissionInheritance follows x-inherit-permissions-from references from a
+// resource to the resources it inherits permissions from, through the
+// triples its references were projected to.
+type permissionInheritance struct {
+	types     repositories.ResourceTypeRepository
+	triples   repositories.TripleRepository
+	resources repositories.ResourceRepository
+}
+
+// parents returns the references typeSlug inherits permissions through.
+func (p *permissionInheritance) parents(ctx context.Context, typeSlug string) []ReferencePropertyDef {
+	rt, err := p.types.FindBySlug(ctx, typeSlug)
+	if err != nil {
+		return nil
+	}
+	parents, _ := inheritanceOf(rt)
+	return parents
+}
+
+// allows reports whether direct allows the caller on any resource the
+// resource id of typeSlug inherits permissions from, nearest first. Lookup
+// errors deny.
+func (p *permissionInheritance) allows(
+	ctx context.Context, id, typeSlug string, direct func(parent *entities.Resource) bool,
+) bool {
+	for _, ancestor := range p.ancestors(ctx, id, typeSlug) {
+		parent, err := p.resources.FindByID(ctx, ancestor.id)
+		if err != nil {
+			continue
+		}
+		if direct(parent) {
+			return true
+		}
+	}
+	return false
+}
+
+// ancestors returns the resources id inherits permissions from, from
+// inheritedAncestors or by walking the parents breadth first, nearest first,
+// down to maxInheritanceDepth references. Each resource is listed once.
+func (p *permissionInheritance) ancestors(ctx context.Context, id, typeSlug string) []inheritedResource {
+	if ancestors, ok := inheritedAncestors.get(id); ok {
+		return ancestors
+	}
+	seen := map[string]bool{id: true}
+	walked := []string{id}
+	complete := true
+	var ancestors []inheritedResource
+	level := []inheritedResource{{id, typeSlug}}
+	for depth := 0; depth < maxInheritanceDepth && len(level) > 0; depth++ {
+		var next []inheritedResource
+		for _, n := range level {
+			for _, ref := range p.parents(ctx, n.typeSlug) {
+				triples, err := p.triples.FindBySubjectAndPredicate(ctx, n.id, ref.PredicateIRI)
+				if err != nil {
+					return ancestors // not cached, so the next check tries again
+				}
+				for _, t := range triples {
+					if seen[t.Object] {
+						continue
+					}
+					seen[t.Object] = true
+					parent, err := p.resources.FindByID(ctx, t.Object)
+					if err != nil {
+						complete = false // not cached; the parent may not be projected yet
+						continue
+					}
+					next = append(next, inheritedResource{parent.GetID(), parent.TypeSlug()})
+				}
+			}
+			if depth > 0 {
+				walked = append(walked, n.id)
+			}
+		}
+		ancestors = append(ancestors, next...)
+		level = next
+	}
+	if complete {
+		inheritedAncestors.put(id, ancestors, walked)
+	}
+	return ancestors
+}
+
+// scope returns the list-scope inheritance steps of typeSlug, down to
+// maxInheritanceDepth references.
+func (p *permissionInheritance) scope(ctx context.Context, typeSlug string) []repositories.PermissionInheritance {
+	return p.scopeAt(ctx, typeSlug, 0)
+}
+
+func (p *permissionInheritance) scopeAt(
+	ctx context.Context, typeSlug string, depth int,
+) []repositories.PermissionInheritance {
+	if depth >= maxInheritanceDepth {
+		return nil
+	}
+	var steps []repositories.PermissionInheritance
+	for _, ref := range p.parents(ctx, typeSlug) {
+		steps = append(steps, repositories.PermissionInheritance{
+			Predicate: ref.PredicateIRI,
+			Parents:   p.scopeAt(ctx, ref.TargetType, depth+1),
+		})
+	}
+	return steps
+}
+
+// inheritedResource is a resource permissions are inherited from.
+type inheritedResource struct{ id, typeSlug string }
+
+// inheritedAncestors holds each checked resource's ancestors.
+var inheritedAncestors ancestorCache
+
+// ancestorCache holds the resources each resource inherits permissions from.
+// An entry is dropped when a triple of any resource its walk read changes,
+// when a resource type changes, or after ancestorCacheTTL.
+type ancestorCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List                 // most recently used first
+	readers map[string]map[string]bool // resource id -> entries whose walk read its triples
+}
+
+type ancestorCacheEntry struct {
+	id        string
+	ancestors []inheritedResource
+	walked    []string
+	expires   time.Time
+}
+
+func (c *ancestorCache) get(id string) ([]inheritedResource, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*ancestorCacheEntry)
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.ancestors, true
+}
+
+// put caches the ancestors of id, found by reading the triples of walked.
+func (c *ancestorCache) put(id string, ancestors []inheritedResource, walked []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.order = list.New()
+		c.readers = make(map[string]map[string]bool)
+	}
+	if el, ok := c.entries[id]; ok {
+		c.remove(el)
+	}
+	c.entries[id] = c.order.PushFront(&ancestorCacheEntry{
+		id: id, ancestors: ancestors, walked: walked, expires: time.Now().Add(ancestorCacheTTL),
+	})
+	for _, w := range walked {
+		if c.readers[w] == nil {
+			c.readers[w] = make(map[string]bool)
+		}
+		c.readers[w][id] = true
+	}
+	for c.order.Len() > ancestorCacheSize {
+		c.remove(c.order.Back())
+	}
+}
+
+// forget drops every entry whose walk read subject's triples.
+func (c *ancestorCache) forget(subject string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.readers[subject] {
+		if el, ok := c.entries[id]; ok {
+			c.remove(el)
+		}
+	}
+}
+
+// clear drops every entry, for a resource type change that may add or
+// remove an x-inherit-permissions-from reference.
+func (c *ancestorCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries, c.order, c.readers = nil, nil, nil
+}
+
+// remove drops el; the caller holds mu.
+func (c *ancestorCache) remove(el *list.Element) {
+	e := el.Value.(*ancestorCacheEntry)
+	c.order.Remove(el)
+	delete(c.entries, e.id)
+	for _, w := range e.walked {
+		delete(c.readers[w], e.id)
+		if len(c.readers[w]) == 0 {
+			delete(c.readers, w)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+func TestCompileInheritance(t *testing.T) {
+	t.Parallel()
+	schema := json.RawMessage(`{"properties":{
+		"name":{"type":"string"},
+		"project":{"type":"string","x-resource-type":"project"},
+		"owner":{"type":"string","x-resource-type":"person"}
+	},"x-inherit-permissions-from":["project","owner"]}`)
+	ldContext := json.RawMessage(`{"@vocab":"https://schema.org/","project":"https://schema.org/isPartOf"}`)
+
+	parents, err := compileInheritance(schema, ldContext)
+	if err != nil {
+		t.Fatalf("compileInheritance: %v", err)
+	}
+	predicates := map[string]string{}
+	for _, p := range parents {
+		predicates[p.PropertyName] = p.PredicateIRI
+	}
+	if predicates["project"] != "https://schema.org/isPartOf" || predicates["owner"] != "https://schema.org/owner" {
+		t.Errorf("parents = %+v", parents)
+	}
+}
+
+func TestValidateInheritance(t *testing.T) {
+	t.Parallel()
+	ref := `"project":{"type":"string","x-resource-type":"project"},"name":{"type":"string"}`
+	for schema, ok := range map[string]bool{
+		`{"properties":{` + ref + `}}`:                                                 true,
+		`{"properties":{` + ref + `},"x-inherit-permissions-from":"project"}`:          true,
+		`{"properties":{` + ref + `},"x-inherit-permissions-from":["project"]}`:        true,
+		`{"properties":{` + ref + `},"x-inherit-permissions-from":"name"}`:             false,
+		`{"properties":{` + ref + `},"x-inherit-permissions-from":"missing"}`:          false,
+		`{"properties":{` + ref + `},"x-inherit-permissions-from":{"from":"project"}}`: false,
+	} {
+		if err := validateInheritance(json.RawMessage(schema)); (err == nil) != ok {
+			t.Errorf("validateInheritance(%s) = %v", schema, err)
+		}
+	}
+}
+
+// countingTriples serves fixed triples and counts the lookups.
+type countingTriples struct {
+	repositories.TripleRepository
+	triples []repositories.Triple
+	lookups int
+}
+
+func (r *countingTriples) FindBySubjectAndPredicate(
+	_ context.Context, subject, predicate string,
+) ([]repositories.Triple, error) {
+	r.lookups++
+	var found []repositories.Triple
+	for _, t := range r.triples {
+		if t.Subject == subject && t.Predicate == predicate {
+			found = append(found, t)
+		}
+	}
+	return found, nil
+}
+
+type inheritanceResources struct {
+	repositories.ResourceRepository
+	resources map[string]*entities.Resource
+}
+
+func (r *inheritanceResources) FindByID(_ context.Context, id string) (*entities.Resource, error) {
+	if res, ok := r.resources[id]; ok {
+		return res, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestPermissionInheritance_CachesAncestorsUntilATripleChanges(t *testing.T) {
+	t.Parallel()
+	task := makeSchemaRT(t, "inherit-task", `{"properties":{
+		"project":{"type":"string","x-resource-type":"inherit-project"}
+	},"x-inherit-permissions-from":"project"}`)
+	project := makeSchemaRT(t, "inherit-project", `{"properties":{"name":{"type":"string"}}}`)
+	parents, err := inheritanceOf(task)
+	if err != nil || len(parents) != 1 {
+		t.Fatalf("inheritanceOf = %v, %v", parents, err)
+	}
+	predicate := parents[0].PredicateIRI
+
+	resources := map[string]*entities.Resource{}
+	for _, id := range []string{"urn:inherit-project:a", "urn:inherit-project:b"} {
+		res := &entities.Resource{}
+		if err := res.Restore(id, "inherit-project", "active", json.RawMessage(`{}`),
+			"", "", time.Now(), 1); err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		resources[id] = res
+	}
+	triples := &countingTriples{triples: []repositories.Triple{
+		{Subject: "urn:inherit-task:1", Predicate: predicate, Object: "urn:inherit-project:a"},
+	}}
+	p := &permissionInheritance{
+		types: &stubTypeRepo{types: map[string]*entities.ResourceType{
+			"inherit-task": task, "inherit-project": project,
+		}},
+		triples:   triples,
+		resources: &inheritanceResources{resources: resources},
+	}
+	allowsB := func(parent *entities.Resource) bool { return parent.GetID() == "urn:inherit-project:b" }
+
+	ctx := context.Background()
+	if p.allows(ctx, "urn:inherit-task:1", "inherit-task", allowsB) {
+		t.Fatal("allowed through a project the task is not part of")
+	}
+	lookups := triples.lookups
+	p.allows(ctx, "urn:inherit-task:1", "inherit-task", allowsB)
+	if triples.lookups != lookups {
+		t.Errorf("second check read triples again: %d lookups, want %d", triples.lookups, lookups)
+	}
+
+	// The task moves to project b.
+	triples.triples[0].Object = "urn:inherit-project:b"
+	inheritedAncestors.forget("urn:inherit-task:1")
+	if !p.allows(ctx, "urn:inherit-task:1", "inherit-task", allowsB) {
+		t.Error("denied after the task moved to project b")
+	}
+}
@@ -0,0 +1,526 @@
+package application
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/fx"
+)
+
+// piiTokenPrefix starts a sealed value: "$pii:<key ID>:<nonce and
+// ciphertext, base64url>". The ciphertext is the JSON encoding of the value.
+const piiTokenPrefix = "$pii:"
+
+// piiKeySize is the size of a subject key, which makes it an AES-256 key.
+const piiKeySize = 32
+
+// piiCipher seals the x-pii properties of resource events with AES-GCM,
+// under a key of the resource the event belongs to. Resource data is sealed
+// property by property, in each node of its @graph, and the objects of triples from x-pii reference
+// properties are sealed too, so the triples table can be redacted along
+// with the data.
+type piiCipher struct {
+	keys   repositories.SubjectKeyRepository
+	types  repositories.ResourceTypeRepository
+	logger entities.Logger
+}
+
+func ProvidePIICipher(params struct {
+	fx.In
+	Keys   repositories.SubjectKeyRepository
+	Types  repositories.ResourceTypeRepository
+	Logger entities.Logger
+}) entities.PIICipher {
+	return &piiCipher{keys: params.Keys, types: params.Types, logger: params.Logger}
+}
+
+// piiProperties returns the names of the schema's top-level properties
+// annotated x-pii: true.
+func piiProperties(schema json.RawMessage) map[string]bool {
+	var s struct {
+		Properties map[string]struct {
+			PII bool `json:"x-pii"`
+		} `json:"properties"`
+	}
+	if len(schema) == 0 || json.Unmarshal(schema, &s) != nil {
+		return nil
+	}
+	props := make(map[string]bool)
+	for name, p := range s.Properties {
+		if p.PII {
+			props[name] = true
+		}
+	}
+	return props
+}
+
+// validatePIIAnnotations rejects an x-pii that is not a boolean.
+func validatePIIAnnotations(schema json.RawMessage) error {
+	var s struct {
+		Properties map[string]map[string]json.RawMessage `json:"properties"`
+	}
+	if len(schema) == 0 || json.Unmarshal(schema, &s) != nil {
+		return nil
+	}
+	var errs []error
+	for name, p := range s.Properties {
+		raw, ok := p[entities.PIIAnnotation]
+		if !ok {
+			continue
+		}
+		var flag bool
+		if json.Unmarshal(raw, &flag) != nil {
+			errs = append(errs, fmt.Errorf("property %q: x-pii must be true or false: %w", name, ErrValidation))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// piiFields is what to seal in the events of one resource.
+type piiFields struct {
+	properties map[string]bool
+	predicates map[string]bool
+}
+
+// fieldsOf returns the x-pii properties of the type of the resource
+// aggregateID, and the triple predicates of those that are references.
+func (c *piiCipher) fieldsOf(ctx context.Context, aggregateID string) (piiFields, error) {
+	parts := strings.SplitN(aggregateID, ":", 3)
+	if len(parts) != 3 || parts[0] != "urn" {
+		return piiFields{}, nil
+	}
+	rt, err := c.types.FindBySlug(ctx, parts[1])
+	if errors.Is(err, repositories.ErrNotFound) {
+		return piiFields{}, nil
+	}
+	if err != nil {
+		return piiFields{}, fmt.Errorf("failed to look up the personal data of %s: %w", aggregateID, err)
+	}
+	fields := piiFields{properties: piiProperties(rt.Schema())}
+	if len(fields.properties) == 0 {
+		return fields, nil
+	}
+	fields.predicates = make(map[string]bool)
+	for _, ref := range ExtractReferenceProperties(rt.Schema(), rt.Context()) {
+		if fields.properties[ref.PropertyName] {
+			fields.predicates[ref.PredicateIRI] = true
+		}
+	}
+	return fields, nil
+}
+
+func (c *piiCipher) Seal(
+	ctx context.Context, events []domain.EventEnvelope[any],
+) ([]domain.EventEnvelope[any], error) {
+	fieldsByAggregate := make(map[string]piiFields)
+	keys := make(map[string]*entities.SubjectKey)
+	var sealed []domain.EventEnvelope[any]
+	for i, e := range events {
+		if !strings.HasPrefix(e.EventType, "Resource.") && !strings.HasPrefix(e.EventType, "Triple.") {
+			continue
+		}
+		fields, ok := fieldsByAggregate[e.AggregateID]
+		if !ok {
+			var err error
+			if fields, err = c.fieldsOf(ctx, e.AggregateID); err != nil {
+				return nil, err
+			}
+			fieldsByAggregate[e.AggregateID] = fields
+		}
+		if len(fields.properties) == 0 {
+			continue
+		}
+		payload, err := payloadMap(e.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s payload: %w", e.EventType, err)
+		}
+		key := func() (*entities.SubjectKey, error) {
+			if keys[e.AggregateID] == nil {
+				k, err := c.subjectKey(ctx, e.AggregateID)
+				if err != nil {
+					return nil, err
+				}
+				keys[e.AggregateID] = k
+			}
+			return keys[e.AggregateID], nil
+		}
+		changed := false
+		if data, ok := payload["Data"].(map[string]any); ok {
+			var sealErr error
+			payload["Data"] = withDataNodes(data, func(node map[string]any) {
+				for name := range fields.properties {
+					value, present := node[name]
+					if !present || value == nil || sealErr != nil {
+						continue
+					}
+					k, err := key()
+					if err != nil {
+						sealErr = err
+						return
+					}
+					node[name], sealErr = sealToken(piiTokenPrefix, k.ID, k.Key, e.AggregateID, value)
+					if sealErr == nil {
+						changed = true
+					}
+				}
+			})
+			if sealErr != nil {
+				return nil, sealErr
+			}
+		}
+		if predicate, _ := payload["predicate"].(string); fields.predicates[predicate] {
+			if object, _ := payload["object"].(string); object != "" {
+				k, err := key()
+				if err != nil {
+					return nil, err
+				}
+				if payload["object"], err = sealToken(piiTokenPrefix, k.ID, k.Key, e.AggregateID, object); err != nil {
+					return nil, err
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if sealed == nil {
+			sealed = slices.Clone(events)
+		}
+		e.Payload = payload
+		sealed[i] = e
+	}
+	if sealed == nil {
+		return events, nil
+	}
+	return sealed, nil
+}
+
+// subjectKey returns the subject's newest key, creating its first.
+func (c *piiCipher) subjectKey(ctx context.Context, subjectID string) (*entities.SubjectKey, error) {
+	key, err := c.keys.Latest(ctx, subjectID)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	key = &entities.SubjectKey{
+		ID:        ksuid.New().String(),
+		SubjectID: subjectID,
+		Key:       make([]byte, piiKeySize),
+		CreatedAt: time.Now().UTC(),
+	}
+	if _, err := rand.Read(key.Key); err != nil {
+		return nil, fmt.Errorf("failed to generate subject key: %w", err)
+	}
+	if err := c.keys.Save(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (c *piiCipher) Open(
+	ctx context.Context, events []domain.EventEnvelope[any],
+) ([]domain.EventEnvelope[any], error) {
+	var ids []string
+	for _, e := range events {
+		m, _ := e.Payload.(map[string]any)
+		eachToken(m, func(keyID string) { ids = append(ids, keyID) })
+	}
+	if len(ids) == 0 {
+		return events, nil
+	}
+	keys, err := c.keys.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	opened := slices.Clone(events)
+	for i, e := range opened {
+		m, ok := e.Payload.(map[string]any)
+		if !ok {
+			continue
+		}
+		payload := maps.Clone(m)
+		if data, ok := payload["Data"].(map[string]any); ok {
+			payload["Data"] = withDataNodes(data, func(node map[string]any) {
+				for name, value := range node {
+					if token, ok := value.(string); ok && strings.HasPrefix(token, piiTokenPrefix) {
+						node[name] = c.openValue(ctx, keys, e, token)
+					}
+				}
+			})
+		}
+		if token, ok := payload["object"].(string); ok && strings.HasPrefix(token, piiTokenPrefix) {
+			// An erased reference leaves no edge behind.
+			object, _ := c.openValue(ctx, keys, e, token).(string)
+			payload["object"] = object
+		}
+		e.Payload = payload
+		opened[i] = e
+	}
+	return opened, nil
+}
+
+// openValue decrypts one sealed value. A value whose key has been erased,
+// or that does not decrypt, is redacted to nil.
+func (c *piiCipher) openValue(
+	ctx context.Context, keys map[string]*entities.SubjectKey, e domain.EventEnvelope[any], token string,
+) any {
+	key := keys[tokenKeyID(piiTokenPrefix, token)]
+	if key == nil {
+		return nil
+	}
+	value, err := openToken(piiTokenPrefix, key.Key, e.AggregateID, token)
+	if err != nil {
+		c.logger.Warn(ctx, "redacting personal data that does not decrypt",
+			"eventID", e.ID, "aggregateID", e.AggregateID, "error", err)
+		return nil
+	}
+	return value
+}
+
+// SealedKeyIDs returns the IDs of the subject keys the x-pii values in a
+// stored event payload are sealed with, nil when it has none. Moving events
+// to another database needs these keys too; without them the values read
+// as erased.
+func SealedKeyIDs(payload any) []string {
+	m, err := payloadMap(payload)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	eachToken(m, func(keyID string) { ids = append(ids, keyID) })
+	return ids
+}
+
+// eachToken calls fn with the key ID of every sealed value in a stored
+// payload.
+func eachToken(payload map[string]any, fn func(keyID string)) {
+	visit := func(v any) {
+		if s, ok := v.(string); ok && strings.HasPrefix(s, piiTokenPrefix) {
+			fn(tokenKeyID(piiTokenPrefix, s))
+		}
+	}
+	if data, ok := payload["Data"].(map[string]any); ok {
+		for _, node := range dataNodes(data) {
+			for _, v := range node {
+				visit(v)
+			}
+		}
+	}
+	visit(payload["object"])
+}
+
+// dataNodes returns the nodes of resource data: the nodes of its @graph,
+// or the data itself when it has none.
+func dataNodes(data map[string]any) []map[string]any {
+	graph, ok := data["@graph"].([]any)
+	if !ok {
+		return []map[string]any{data}
+	}
+	nodes := make([]map[string]any, 0, len(graph))
+	for _, n := range graph {
+		if node, ok := n.(map[string]any); ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// withDataNodes returns a copy of resource data with fn applied to a copy
+// of each of its nodes (see dataNodes).
+func withDataNodes(data map[string]any, fn func(node map[string]any)) map[string]any {
+	data = maps.Clone(data)
+	graph, ok := data["@graph"].([]any)
+	if !ok {
+		fn(data)
+		return data
+	}
+	graph = slices.Clone(graph)
+	for i, n := range graph {
+		if node, ok := n.(map[string]any); ok {
+			node = maps.Clone(node)
+			fn(node)
+			graph[i] = node
+		}
+	}
+	data["@graph"] = graph
+	return data
+}
+
+// sealToken encrypts the JSON encoding of value with AES-GCM, bound to aad,
+// into "<prefix><key ID>:<nonce and ciphertext, base64url>".
+func sealToken(prefix, keyID string, key []byte, aad string, value any) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode value to encrypt: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, []byte(aad))
+	return prefix + keyID + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// tokenKeyID returns the ID of the key a sealed token was encrypted with.
+func tokenKeyID(prefix, token string) string {
+	keyID, _, _ := strings.Cut(strings.TrimPrefix(token, prefix), ":")
+	return keyID
+}
+
+// openToken decrypts a token made by sealToken.
+func openToken(prefix string, key []byte, aad, token string) (any, error) {
+	_, encoded, ok := strings.Cut(strings.TrimPrefix(token, prefix), ":")
+	if !ok {
+		return nil, fmt.Errorf("malformed sealed value")
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed sealed value: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("malformed sealed value")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(aad))
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// payloadMap returns a payload as the map it is stored as. Typed payloads
+// are converted through JSON; maps are copied.
+func payloadMap(payload any) (map[string]any, error) {
+	if m, ok := payload.(map[string]any); ok {
+		return maps.Clone(m), nil
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ErasureResult reports what erasing a resource's personal data did.
+type ErasureResult struct {
+	ResourceID    string
+	KeysDestroyed int64
+	// Repaired is whether the read models held personal data and were
+	// rewritten from the redacted events.
+	Repaired bool
+}
+
+// PersonalDataEraser crypto-shreds a resource: it destroys the keys its
+// x-pii values are sealed with, so every copy of the events, wherever it
+// is, reads them as null from then on. The resource itself is not deleted.
+type PersonalDataEraser struct {
+	eventStore domain.EventStore
+	keys       repositories.SubjectKeyRepository
+	snapshots  repositories.SnapshotRepository
+	maintainer *ProjectionMaintainer
+	registry   EventHandlerRegistry
+	logger     entities.Logger
+}
+
+func ProvidePersonalDataEraser(params struct {
+	fx.In
+	EventStore domain.EventStore
+	Keys       repositories.SubjectKeyRepository
+	Snapshots  repositories.SnapshotRepository
+	Maintainer *ProjectionMaintainer
+	Registry   EventHandlerRegistry
+	Logger     entities.Logger
+}) *PersonalDataEraser {
+	return &PersonalDataEraser{
+		eventStore: params.EventStore,
+		keys:       params.Keys,
+		snapshots:  params.Snapshots,
+		maintainer: params.Maintainer,
+		registry:   params.Registry,
+		logger:     params.Logger,
+	}
+}
+
+// Erase destroys the resource's subject keys, drops its snapshot, which
+// holds decrypted state, and rewrites its resources row, projection row,
+// triples and published revision from the now redacted events.
+func (e *PersonalDataEraser) Erase(ctx context.Context, resourceID string) (ErasureResult, error) {
+	result := ErasureResult{ResourceID: resourceID}
+	events, err := e.eventStore.GetEvents(ctx, resourceID)
+	if err != nil {
+		return result, err
+	}
+	if len(events) == 0 {
+		return result, fmt.Errorf("resource %s: %w", resourceID, repositories.ErrNotFound)
+	}
+	if result.KeysDestroyed, err = e.keys.DeleteBySubject(ctx, resourceID); err != nil {
+		return result, err
+	}
+	if err := e.snapshots.Delete(ctx, resourceID); err != nil {
+		return result, err
+	}
+	drift, err := e.maintainer.verifyResource(ctx, resourceID, true)
+	if err != nil {
+		return result, err
+	}
+	result.Repaired = drift != nil && drift.Repaired
+
+	editorial := e.registry["projections.editorial"]
+	if editorial == nil {
+		return result, nil
+	}
+	if events, err = e.eventStore.GetEvents(ctx, resourceID); err != nil {
+		return result, err
+	}
+	for _, event := range events {
+		switch event.EventType {
+		case "Resource.RevisionPublished", "Resource.RevisionWithdrawn", "Resource.Deleted":
+			if err := editorial.handle(ctx, event); err != nil {
+				return result, fmt.Errorf("failed to redact the published revision of %s: %w", resourceID, err)
+			}
+		}
+	}
+	e.logger.Info(ctx, "erased personal data", "resourceID", resourceID, "keysDestroyed", result.KeysDestroyed)
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+type memSubjectKeys map[string]*entities.SubjectKey
+
+func (m memSubjectKeys) Latest(_ context.Context, subjectID string) (*entities.SubjectKey, error) {
+	for _, k := range m {
+		if k.SubjectID == subjectID {
+			return k, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (m memSubjectKeys) Save(_ context.Context, k *entities.SubjectKey) error {
+	m[k.ID] = k
+	return nil
+}
+
+func (m memSubjectKeys) FindByIDs(_ context.Context, ids []string) (map[string]*entities.SubjectKey, error) {
+	found := make(map[string]*entities.SubjectKey)
+	for _, id := range ids {
+		if k, ok := m[id]; ok {
+			found[id] = k
+		}
+	}
+	return found, nil
+}
+
+func (m memSubjectKeys) DeleteBySubject(_ context.Context, subjectID string) (int64, error) {
+	var n int64
+	for id, k := range m {
+		if k.SubjectID == subjectID {
+			delete(m, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func newTestPIICipher(t *testing.T) (*piiCipher, memSubjectKeys) {
+	t.Helper()
+	rt := &entities.ResourceType{}
+	if err := rt.Restore("id-patient", "Patient", "patient", "", "active", nil, json.RawMessage(`{
+		"type":"object","properties":{
+			"name":{"type":"string","x-pii":true},
+			"age":{"type":"integer","x-pii":true},
+			"ward":{"type":"string"}
+		}}`), time.Now(), 1); err != nil {
+		t.Fatal(err)
+	}
+	keys := memSubjectKeys{}
+	return &piiCipher{
+		keys:   keys,
+		types:  &installTestTypeRepo{types: map[string]*entities.ResourceType{"patient": rt}},
+		logger: noopLogger{},
+	}, keys
+}
+
+func TestPIICipher_SealOpenAndErase(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c, keys := newTestPIICipher(t)
+	const id = "urn:patient:1"
+	events := []domain.EventEnvelope[any]{{
+		ID: "e1", AggregateID: id, EventType: "Resource.Created",
+		Payload: map[string]any{"Data": map[string]any{"@graph": []any{
+			map[string]any{"@id": id, "name": "Ada", "age": float64(36), "ward": "B"},
+		}}},
+	}}
+
+	sealed, err := c.Seal(ctx, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(sealed[0].Payload)
+	if strings.Contains(string(raw), "Ada") || strings.Contains(string(raw), "36") ||
+		!strings.Contains(string(raw), `"ward":"B"`) {
+		t.Fatalf("sealed payload = %s", raw)
+	}
+	if raw, _ := json.Marshal(events[0].Payload); !strings.Contains(string(raw), "Ada") {
+		t.Error("Seal modified the events it was given")
+	}
+
+	node := func(events []domain.EventEnvelope[any]) map[string]any {
+		data := events[0].Payload.(map[string]any)["Data"].(map[string]any)
+		return data["@graph"].([]any)[0].(map[string]any)
+	}
+	opened, err := c.Open(ctx, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := node(opened); n["name"] != "Ada" || n["age"] != float64(36) || n["ward"] != "B" {
+		t.Fatalf("opened node = %v", n)
+	}
+
+	if n, _ := keys.DeleteBySubject(ctx, id); n != 1 {
+		t.Fatalf("deleted %d keys, want 1", n)
+	}
+	erased, err := c.Open(ctx, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := node(erased); n["name"] != nil || n["age"] != nil || n["ward"] != "B" {
+		t.Fatalf("erased node = %v", n)
+	}
+}
+
+func TestPIICipher_LeavesOtherTypesAlone(t *testing.T) {
+	t.Parallel()
+	c, keys := newTestPIICipher(t)
+	events := []domain.EventEnvelope[any]{{
+		AggregateID: "urn:clinic:1", EventType: "Resource.Created",
+		Payload: map[string]any{"Data": map[string]any{"name": "Northside"}},
+	}}
+	sealed, err := c.Seal(context.Background(), events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sealed[0].Payload.(map[string]any)["Data"].(map[string]any)["name"]; got != "Northside" {
+		t.Errorf("name = %v, want it untouched", got)
+	}
+	if len(keys) != 0 {
+		t.Errorf("created %d keys for a type without x-pii", len(keys))
+	}
+}
+
+func TestValidatePIIAnnotations(t *testing.T) {
+	t.Parallel()
+	for schema, ok := range map[string]bool{
+		`{"properties":{"email":{"type":"string","x-pii":true}}}`:  true,
+		`{"properties":{"email":{"type":"string","x-pii":false}}}`: true,
+		`{"properties":{"email":{"type":"string","x-pii":"yes"}}}`: false,
+	} {
+		if err := validatePIIAnnotations(json.RawMessage(schema)); (err == nil) != ok {
+			t.Errorf("validatePIIAnnotations(%s) = %v", schema, err)
+		}
+	}
+}
@@ -78,8 +78,10 @@ type PresetDefinition struct {
 	// are installed — enabling a "finance-education" integration preset to
 	// link Invoice and Guardian without either preset depending on the other.
 	// See PresetLinkDefinition for semantics.
-	Links       []PresetLinkDefinition
-	AutoInstall bool // if true, types are auto-created at startup
+	Links         []PresetLinkDefinition
+	Jobs          []PresetJob          // optional background jobs, run by the job worker
+	EventHandlers []PresetEventHandler // optional event handler groups
+	AutoInstall   bool                 // if true, types are auto-created at startup
 }
 
 // InstallPresetResult reports which types were created, updated, unchanged, or
@@ -173,6 +175,12 @@ func (r *PresetRegistry) Add(def PresetDefinition) error {
 	if err := validatePresetLinks(def); err != nil {
 		return err
 	}
+	if err := validateJobs(def); err != nil {
+		return err
+	}
+	if err := validateEventHandlers(def); err != nil {
+		return err
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.presets[def.Name] = def
@@ -284,6 +292,16 @@ func (d PresetDefinition) clone() PresetDefinition {
 		copy(links, d.Links)
 		d.Links = links
 	}
+	if d.Jobs != nil {
+		jobs := make([]PresetJob, len(d.Jobs))
+		copy(jobs, d.Jobs)
+		d.Jobs = jobs
+	}
+	if d.EventHandlers != nil {
+		handlers := make([]PresetEventHandler, len(d.EventHandlers))
+		copy(handlers, d.EventHandlers)
+		d.EventHandlers = handlers
+	}
 	if d.Sidebar != nil {
 		s := *d.Sidebar
 		if s.HiddenSlugs != nil {
@@ -0,0 +1,119 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package mealplanning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+)
+
+// occurrenceHorizonJobName is the nightly job that keeps open-ended
+// schedules generated ahead of today.
+const occurrenceHorizonJobName = "meal-planning.roll-occurrence-horizon"
+
+// newOccurrenceHorizonJob returns the handler for the nightly horizon job.
+// Creating a scheduled meal generates at most MaxOccurrences occurrences, so
+// a daily schedule with no end date would run dry after 52 days; the job
+// tops every recurring schedule back up to MaxOccurrences dates from today.
+func newOccurrenceHorizonJob(svc application.BehaviorServices) application.JobHandler {
+	b := newScheduledMealBehavior(svc)
+	return func(ctx context.Context, _ json.RawMessage) error {
+		return b.rollHorizon(ctx, time.Now().UTC())
+	}
+}
+
+// rollHorizon creates the missing future occurrences of every recurring
+// scheduled meal. It is idempotent: dates that already have an occurrence,
+// whatever its status, are skipped.
+func (b *scheduledMealBehavior) rollHorizon(ctx context.Context, now time.Time) error {
+	if b.writer == nil || b.svc.Resources == nil {
+		return errors.New("scheduled-meal horizon: services not injected")
+	}
+	failures := 0
+	cursor := ""
+	for {
+		page, err := b.svc.Resources.FindAllByType(ctx, "scheduled-meal", cursor, 100,
+			repositories.SortOptions{}, nil)
+		if err != nil {
+			return fmt.Errorf("scheduled-meal horizon: failed to list scheduled meals: %w", err)
+		}
+		for _, resource := range page.Data {
+			if err := b.rollMealHorizon(ctx, resource, now); err != nil {
+				if b.logger != nil {
+					b.logger.Error(ctx, "scheduled-meal horizon: failed to roll schedule forward",
+						"id", resource.GetID(), "error", err)
+				}
+				failures++
+			}
+		}
+		if !page.HasMore || page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+	if failures > 0 {
+		return fmt.Errorf("scheduled-meal horizon: %d schedules failed to roll forward", failures)
+	}
+	return nil
+}
+
+func (b *scheduledMealBehavior) rollMealHorizon(
+	ctx context.Context, resource *entities.Resource, now time.Time,
+) error {
+	sm, err := extractFlatDataByID(resource, resource.GetID())
+	if err != nil {
+		return err
+	}
+	// One-off meals have nothing to roll, and a repeatCount schedule was
+	// fully generated when it was created (the walker counts only the dates
+	// it emits, so rolling it forward would overshoot the count).
+	if freq, _ := sm["repeatFrequency"].(string); freq == "" || parseInt(sm["repeatCount"]) > 0 {
+		return nil
+	}
+	dates, _, err := expandSchedule(sm, now, true)
+	if err != nil {
+		return err
+	}
+	existing, err := b.listOccurrences(ctx, resource.GetID(), resource)
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool, len(existing))
+	for _, occ := range existing {
+		if date, _ := occ["date"].(string); date != "" {
+			have[date] = true
+		}
+	}
+	// Create as the schedule's owner so the occurrences land in their account.
+	if resource.AccountID() != "" {
+		ctx = auth.ContextWithAgent(ctx, &auth.Identity{
+			AgentID:         resource.CreatedBy(),
+			AccountIDs:      []string{resource.AccountID()},
+			ActiveAccountID: resource.AccountID(),
+		})
+	}
+	b.createOccurrencesForDates(ctx, resource, sm, dates, have)
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package mealplanning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+func TestRollHorizon_CreatesMissingFutureOccurrences(t *testing.T) {
+	t.Parallel()
+	b, stub := setupScheduledMealBehavior(t)
+	daily := map[string]any{
+		"startDate":       pastDate(100),
+		"repeatFrequency": "P1D",
+		"mealType":        "dinner",
+	}
+	stub.listData["scheduled-meal"] = []*entities.Resource{
+		makeTestResource(t, "sm-daily", "scheduled-meal", daily),
+		makeTestResource(t, "sm-once", "scheduled-meal", map[string]any{
+			"startDate": futureDate(3),
+			"mealType":  "lunch",
+		}),
+		makeTestResource(t, "sm-counted", "scheduled-meal", map[string]any{
+			"startDate":       pastDate(5),
+			"repeatFrequency": "P1D",
+			"repeatCount":     float64(10),
+			"mealType":        "breakfast",
+		}),
+	}
+	stub.listFlatData["meal-occurrence"] = []map[string]any{
+		{"scheduledMeal": "sm-daily", "date": futureDate(1), "status": "planned"},
+		{"scheduledMeal": "sm-daily", "date": futureDate(2), "status": "skipped"},
+	}
+
+	now := scheduledMealTestBaseNow
+	if err := b.rollHorizon(context.Background(), now); err != nil {
+		t.Fatalf("rollHorizon: %v", err)
+	}
+
+	dates, _, err := expandSchedule(daily, now, true)
+	if err != nil {
+		t.Fatalf("expandSchedule: %v", err)
+	}
+	if want := len(dates) - 2; len(stub.creates) != want {
+		t.Fatalf("expected %d occurrence creates, got %d", want, len(stub.creates))
+	}
+	for _, c := range stub.creates {
+		var m map[string]any
+		if err := json.Unmarshal(c.Data, &m); err != nil {
+			t.Fatalf("failed to unmarshal create payload: %v", err)
+		}
+		if m["scheduledMeal"] != "sm-daily" {
+			t.Fatalf("only the open-ended schedule should roll forward, got %v", m["scheduledMeal"])
+		}
+		if m["date"] == futureDate(1) || m["date"] == futureDate(2) {
+			t.Fatalf("existing occurrence on %v was recreated", m["date"])
+		}
+		if m["date"].(string) < futureDate(0) {
+			t.Fatalf("created a past occurrence on %v", m["date"])
+		}
+	}
+
+	// A second run finds nothing left to do once the creates are visible.
+	for _, c := range stub.creates {
+		var m map[string]any
+		_ = json.Unmarshal(c.Data, &m)
+		stub.listFlatData["meal-occurrence"] = append(stub.listFlatData["meal-occurrence"], m)
+	}
+	stub.creates = nil
+	if err := b.rollHorizon(context.Background(), now); err != nil {
+		t.Fatalf("second rollHorizon: %v", err)
+	}
+	if len(stub.creates) != 0 {
+		t.Fatalf("second run created %d occurrences, want 0", len(stub.creates))
+	}
+}
@@ -30,6 +30,8 @@ import (
 // stubWriter and stubRepo wrappers that implement ResourceWriter and
 // ResourceRepository respectively.
 type stubResourceSvc struct {
+	// listData holds per-typeSlug pre-seeded results for FindAllByType.
+	listData map[string][]*entities.Resource
 	// listFlatData holds per-typeSlug pre-seeded results for FindAllByTypeFlatWithFilters.
 	listFlatData map[string][]map[string]any
 	// getByIDData holds pre-seeded resources returned by FindByID.
@@ -54,6 +56,7 @@ type stubResourceSvc struct {
 
 func newStubResourceSvc() *stubResourceSvc {
 	return &stubResourceSvc{
+		listData:     make(map[string][]*entities.Resource),
 		listFlatData: make(map[string][]map[string]any),
 		getByIDData:  make(map[string]*entities.Resource),
 		getByIDErr:   make(map[string]error),
@@ -122,11 +125,11 @@ func (r *stubRepo) FindByID(
 	return nil, nil //nolint:nilnil
 }
 
-func (*stubRepo) FindAllByType(
-	context.Context, string, string, int,
-	repositories.SortOptions, *repositories.VisibilityScope,
+func (r *stubRepo) FindAllByType(
+	_ context.Context, typeSlug string, _ string, _ int,
+	_ repositories.SortOptions, _ *repositories.VisibilityScope,
 ) (repositories.PaginatedResponse[*entities.Resource], error) {
-	return repositories.PaginatedResponse[*entities.Resource]{}, nil
+	return repositories.PaginatedResponse[*entities.Resource]{Data: r.s.listData[typeSlug]}, nil
 }
 
 func (*stubRepo) FindAllByTypeAndField(
@@ -54,6 +54,13 @@ func Register(registry *application.PresetRegistry) {
 				"food-item":          "pantry",
 			},
 		},
+		Jobs: []application.PresetJob{
+			{
+				Name:     occurrenceHorizonJobName,
+				Schedule: "0 3 * * *", // nightly, 03:00 UTC
+				Factory:  newOccurrenceHorizonJob,
+			},
+		},
 		Behaviors: map[string]application.BehaviorFactory{
 			"pantry": func(svc application.BehaviorServices) entities.ResourceBehavior {
 				return newEnforceSingleDefaultBehavior(svc)
@@ -376,7 +383,8 @@ func scheduledMealType() application.PresetResourceType {
 		"recipe":{"type":"string","x-resource-type":"recipe","x-display-property":"name"},
 		"mealPlan":{"type":"string","x-resource-type":"meal-plan","x-display-property":"name"}
 	},
-	"required":["startDate","mealType","recipe","mealPlan"]
+	"required":["startDate","mealType","recipe","mealPlan"],
+	"x-inherit-permissions-from":"mealPlan"
 }`),
 	}
 }
@@ -400,7 +408,8 @@ func mealOccurrenceType() application.PresetResourceType {
 		"notes":{"type":"string"},
 		"scheduledMeal":{"type":"string","x-resource-type":"scheduled-meal","x-display-property":"mealType"}
 	},
-	"required":["date","mealType","status","scheduledMeal"]
+	"required":["date","mealType","status","scheduledMeal"],
+	"x-inherit-permissions-from":"scheduledMeal"
 }`),
 	}
 }
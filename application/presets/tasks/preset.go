@@ -41,7 +41,7 @@ func Register(registry *application.PresetRegistry) {
 					`"priority":{"type":"string"},`+
 					`"dueDate":{"type":"string","format":"date"},`+
 					`"project":{"type":"string","x-resource-type":"project","x-display-property":"name"}`+
-					`},"required":["name"]}`,
+					`},"required":["name"],"x-inherit-permissions-from":"project"}`,
 			),
 		},
 	})
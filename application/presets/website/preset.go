@@ -1,7 +1,66 @@
 // Package website provides resource types for website structure and content.
 package website
 
-import "github.com/wepala/weos/v3/application"
+import (
+	"encoding/json"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+type transition struct {
+	Name    string   `json:"name"`
+	From    []string `json:"from"`
+	To      string   `json:"to"`
+	Guard   string   `json:"guard,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// editorialWorkflow is the draft → review → published lifecycle shared by the
+// content types.
+var editorialWorkflow = struct {
+	Property    string       `json:"property"`
+	Initial     string       `json:"initial"`
+	States      []string     `json:"states"`
+	Transitions []transition `json:"transitions"`
+}{
+	Property: "creativeWorkStatus",
+	Initial:  "draft",
+	States:   []string{"draft", "in-review", "scheduled", "published", "unpublished"},
+	Transitions: []transition{
+		{Name: "submit", From: []string{"draft"}, To: "in-review"},
+		{Name: "publish", From: []string{"draft", "in-review", "scheduled", "published", "unpublished"}, To: "published"},
+		{
+			Name: "schedule", From: []string{"draft", "in-review", "unpublished"}, To: "scheduled",
+			Guard: "datePublished != ''", Message: "datePublished is required to schedule",
+		},
+		{Name: "unpublish", From: []string{"published", "scheduled"}, To: "unpublished"},
+		{Name: "revise", From: []string{"in-review", "scheduled", "unpublished"}, To: "draft"},
+	},
+}
+
+// editorialSchema returns the schema of a content type on the editorial
+// workflow: the given properties (a JSON object) plus the creativeWorkStatus
+// state, with an x-editorial annotation that keeps the published revision
+// apart from the working draft and publishes scheduled content at its
+// datePublished time.
+func editorialSchema(properties string, required ...string) string {
+	var props map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(properties), &props); err != nil {
+		panic("website preset: invalid properties: " + err.Error())
+	}
+	props["creativeWorkStatus"] = json.RawMessage(`{"type":"string"}`)
+	schema, err := json.Marshal(map[string]any{
+		"type":        "object",
+		"properties":  props,
+		"required":    required,
+		"x-workflow":  editorialWorkflow,
+		"x-editorial": map[string]string{"publishAt": "datePublished"},
+	})
+	if err != nil {
+		panic("website preset: " + err.Error())
+	}
+	return string(schema)
+}
 
 // Register adds the website preset to the registry.
 func Register(registry *application.PresetRegistry) {
@@ -18,8 +77,9 @@ func Register(registry *application.PresetRegistry) {
 			application.NewPresetType("Web Page", "web-page",
 				"Individual page with name, slug, description, and template reference",
 				`{"@vocab":"https://schema.org/","@type":"WebPage"}`,
-				`{"type":"object","properties":{"name":{"type":"string"},"slug":{"type":"string"},`+
-					`"description":{"type":"string"},"template":{"type":"string"}},"required":["name"]}`,
+				editorialSchema(`{"name":{"type":"string"},"slug":{"type":"string"},`+
+					`"description":{"type":"string"},"template":{"type":"string"},`+
+					`"datePublished":{"type":"string","format":"date-time"}}`, "name"),
 			),
 			application.NewPresetType("Web Page Element", "web-page-element",
 				"Content section or block within a page",
@@ -42,16 +102,16 @@ func Register(registry *application.PresetRegistry) {
 			application.NewPresetType("Article", "article",
 				"Written composition such as a news or feature article",
 				`{"@vocab":"https://schema.org/","@type":"Article"}`,
-				`{"type":"object","properties":{"headline":{"type":"string"},"articleBody":{"type":"string"},`+
-					`"author":{"type":"string"},"datePublished":{"type":"string","format":"date-time"}},`+
-					`"required":["headline"]}`,
+				editorialSchema(`{"headline":{"type":"string"},"articleBody":{"type":"string"},`+
+					`"author":{"type":"string"},"datePublished":{"type":"string","format":"date-time"}}`,
+					"headline"),
 			),
 			application.NewPresetType("Blog Post", "blog-post",
 				"Blog entry, informal tone, reverse-chronological listing",
 				`{"@vocab":"https://schema.org/","@type":"BlogPosting"}`,
-				`{"type":"object","properties":{"headline":{"type":"string"},"articleBody":{"type":"string"},`+
-					`"author":{"type":"string"},"datePublished":{"type":"string","format":"date-time"}},`+
-					`"required":["headline"]}`,
+				editorialSchema(`{"headline":{"type":"string"},"articleBody":{"type":"string"},`+
+					`"author":{"type":"string"},"datePublished":{"type":"string","format":"date-time"}}`,
+					"headline"),
 			),
 			application.NewPresetType("FAQ", "faq",
 				"Frequently asked questions page with question-answer pairs",
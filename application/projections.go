@@ -0,0 +1,452 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/segmentio/ksuid"
+	"go.uber.org/fx"
+)
+
+const (
+	defaultProjectionBatch = 500
+	projectionTaskLease    = 5 * time.Minute
+)
+
+// ProjectionProgress reports how far a rebuild or verify task has got. It is
+// passed to the progress callback after every batch.
+type ProjectionProgress struct {
+	// Task names the task and its checkpoint, such as "rebuild.resources.task".
+	Task string
+	// Position is the last event log position the task has finished.
+	Position int64
+	Head     int64
+	// Resumed is set when the task picked up an interrupted run.
+	Resumed bool
+	// Handled counts the events replayed, or the resources verified, by this run.
+	Handled int
+	// Failed counts replayed events a handler failed on. They are logged.
+	Failed int
+	// Drifted counts the resources whose read models disagree with their events.
+	Drifted int
+}
+
+// RebuildOptions selects the read models Rebuild clears and replays.
+type RebuildOptions struct {
+	// Resources rebuilds the resources table and the projection tables.
+	Resources bool
+	// TypeSlug limits the resources rebuild to one type.
+	TypeSlug string
+	// Triples rebuilds the triples table.
+	Triples bool
+	// Restart discards the checkpoint of an interrupted run and starts over.
+	Restart   bool
+	BatchSize int
+	Progress  func(ProjectionProgress)
+}
+
+// VerifyOptions selects the resources Verify checks.
+type VerifyOptions struct {
+	// TypeSlug limits verification to one type.
+	TypeSlug string
+	// Repair rewrites the read models of every drifted resource.
+	Repair bool
+	// Restart discards the checkpoint of an interrupted run and starts over.
+	Restart   bool
+	BatchSize int
+	Progress  func(ProjectionProgress)
+	// OnDrift is called for every drifted resource.
+	OnDrift func(ProjectionDrift)
+}
+
+// ProjectionDrift describes a resource whose read models disagree with its
+// events.
+type ProjectionDrift struct {
+	ResourceID string
+	TypeSlug   string
+	Problems   []string
+	Repaired   bool
+}
+
+// ProjectionMaintainer rebuilds read models from the event log and checks
+// them against the events. Each task keeps a checkpoint in the event log
+// while it runs, so an interrupted task resumes where it stopped, and holds
+// a lease on it, so the same task never runs twice at once.
+type ProjectionMaintainer struct {
+	registry    EventHandlerRegistry
+	log         repositories.EventLogRepository
+	checkpoints repositories.EventCheckpointRepository
+	truncater   repositories.ReadModelTruncater
+	eventStore  domain.EventStore
+	replayer    *ResourceReplayer
+	resources   repositories.ResourceRepository
+	triples     repositories.TripleRepository
+	projMgr     repositories.ProjectionManager
+	logger      entities.Logger
+	owner       string
+}
+
+func ProvideProjectionMaintainer(params struct {
+	fx.In
+	Registry    EventHandlerRegistry
+	Log         repositories.EventLogRepository
+	Checkpoints repositories.EventCheckpointRepository
+	Truncater   repositories.ReadModelTruncater
+	EventStore  domain.EventStore
+	Replayer    *ResourceReplayer
+	Resources   repositories.ResourceRepository
+	Triples     repositories.TripleRepository
+	ProjMgr     repositories.ProjectionManager
+	Logger      entities.Logger
+}) *ProjectionMaintainer {
+	return &ProjectionMaintainer{
+		registry:    params.Registry,
+		log:         params.Log,
+		checkpoints: params.Checkpoints,
+		truncater:   params.Truncater,
+		eventStore:  params.EventStore,
+		replayer:    params.Replayer,
+		resources:   params.Resources,
+		triples:     params.Triples,
+		projMgr:     params.ProjMgr,
+		logger:      params.Logger,
+		owner:       ksuid.New().String(),
+	}
+}
+
+// ofType reports whether aggregateID is a resource of typeSlug, or of any
+// type when typeSlug is empty.
+func ofType(aggregateID, typeSlug string) bool {
+	return typeSlug == "" || strings.HasPrefix(aggregateID, "urn:"+typeSlug+":")
+}
+
+// Rebuild truncates the selected read models and replays the event log
+// through the same handler groups that maintain them. Events committed
+// while it runs are handled twice, which the handlers tolerate.
+func (m *ProjectionMaintainer) Rebuild(ctx context.Context, opts RebuildOptions) ([]ProjectionProgress, error) {
+	if !opts.Resources && !opts.Triples {
+		return nil, fmt.Errorf("nothing to rebuild: %w", ErrValidation)
+	}
+	var results []ProjectionProgress
+	if opts.Resources {
+		group := m.registry["projections.resources"]
+		if group == nil {
+			return nil, fmt.Errorf("the projections.resources event handler group is not registered")
+		}
+		task := "rebuild.resources"
+		if opts.TypeSlug != "" {
+			task += "." + opts.TypeSlug
+		}
+		p, err := m.run(ctx, task, opts.Restart, opts.BatchSize, opts.Progress,
+			func(ctx context.Context) error { return m.truncater.TruncateResources(ctx, opts.TypeSlug) },
+			func(ctx context.Context, event domain.EventEnvelope[any], p *ProjectionProgress) error {
+				if !strings.HasPrefix(event.EventType, "Resource.") || !ofType(event.AggregateID, opts.TypeSlug) {
+					return nil
+				}
+				// A resumed run may replay a creation it already projected.
+				if event.EventType == "Resource.Created" {
+					if err := m.resources.Delete(ctx, event.AggregateID); err != nil {
+						return err
+					}
+				}
+				m.replay(ctx, group, event, p)
+				return nil
+			})
+		results = append(results, p)
+		if err != nil {
+			return results, err
+		}
+	}
+	if opts.Triples {
+		group := m.registry["projections.triples"]
+		if group == nil {
+			return results, fmt.Errorf("the projections.triples event handler group is not registered")
+		}
+		p, err := m.run(ctx, "rebuild.triples", opts.Restart, opts.BatchSize, opts.Progress,
+			m.truncater.TruncateTriples,
+			func(ctx context.Context, event domain.EventEnvelope[any], p *ProjectionProgress) error {
+				if strings.HasPrefix(event.EventType, "Triple.") {
+					m.replay(ctx, group, event, p)
+				}
+				return nil
+			})
+		results = append(results, p)
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// replay runs one event through group. A failing event is logged and
+// counted rather than stopping the rebuild.
+func (m *ProjectionMaintainer) replay(
+	ctx context.Context, group *EventHandlerGroup, event domain.EventEnvelope[any], p *ProjectionProgress,
+) {
+	p.Handled++
+	if err := group.handle(ctx, event); err != nil {
+		p.Failed++
+		m.logger.Error(ctx, "failed to replay event", "task", p.Task, "group", group.Name,
+			"eventID", event.ID, "eventType", event.EventType, "aggregateID", event.AggregateID, "error", err)
+	}
+}
+
+// Verify replays each resource's events and compares the result with the
+// resources table (FindByID), its projection row (FindFlatByID), and its
+// triples. Each drifted resource is passed to OnDrift and, with Repair,
+// rewritten from its events.
+func (m *ProjectionMaintainer) Verify(ctx context.Context, opts VerifyOptions) (ProjectionProgress, error) {
+	task := "verify.resources"
+	if opts.TypeSlug != "" {
+		task += "." + opts.TypeSlug
+	}
+	return m.run(ctx, task, opts.Restart, opts.BatchSize, opts.Progress, nil,
+		func(ctx context.Context, event domain.EventEnvelope[any], p *ProjectionProgress) error {
+			if event.EventType != "Resource.Created" || !ofType(event.AggregateID, opts.TypeSlug) {
+				return nil
+			}
+			p.Handled++
+			drift, err := m.verifyResource(ctx, event.AggregateID, opts.Repair)
+			if err != nil || drift == nil {
+				return err
+			}
+			p.Drifted++
+			if opts.OnDrift != nil {
+				opts.OnDrift(*drift)
+			}
+			return nil
+		})
+}
+
+// run walks the event log from the task's checkpoint to the head, batch by
+// batch, and deletes the checkpoint once it is done. A task starting afresh
+// calls reset first. An error stops the task at the last finished batch.
+func (m *ProjectionMaintainer) run(
+	ctx context.Context, task string, restart bool, batchSize int, progress func(ProjectionProgress),
+	reset func(ctx context.Context) error,
+	handle func(ctx context.Context, event domain.EventEnvelope[any], p *ProjectionProgress) error,
+) (ProjectionProgress, error) {
+	p := ProjectionProgress{Task: task}
+	if batchSize <= 0 {
+		batchSize = defaultProjectionBatch
+	}
+	if restart {
+		if err := m.checkpoints.Delete(ctx, task); err != nil {
+			return p, err
+		}
+	}
+	cp, err := m.checkpoints.Ensure(ctx, task, 0)
+	if err != nil {
+		return p, err
+	}
+	claim := func() error {
+		now := time.Now().UTC()
+		claimed, err := m.checkpoints.Claim(ctx, task, m.owner, now, now.Add(projectionTaskLease))
+		if err != nil {
+			return err
+		}
+		if claimed == nil {
+			return fmt.Errorf("%s is already running elsewhere", task)
+		}
+		return nil
+	}
+	if err := claim(); err != nil {
+		return p, err
+	}
+	defer func() { _ = m.checkpoints.Release(context.WithoutCancel(ctx), task, m.owner) }()
+
+	p.Position, p.Resumed = cp.Position, cp.Position > 0
+	if !p.Resumed && reset != nil {
+		if err := reset(ctx); err != nil {
+			return p, err
+		}
+	}
+	for {
+		if p.Head, err = m.log.Head(ctx); err != nil {
+			return p, err
+		}
+		events, err := m.log.After(ctx, p.Position, batchSize)
+		if err != nil {
+			return p, err
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, logged := range events {
+			if err := handle(ctx, logged.Event, &p); err != nil {
+				return p, fmt.Errorf("%s stopped after position %d: %w", task, p.Position, err)
+			}
+		}
+		last := events[len(events)-1].Position
+		advanced, err := m.checkpoints.Advance(ctx, task, p.Position, last, 0, "")
+		if err != nil {
+			return p, err
+		}
+		if !advanced {
+			return p, fmt.Errorf("%s lost its checkpoint to another run", task)
+		}
+		p.Position = last
+		if progress != nil {
+			progress(p)
+		}
+		if err := claim(); err != nil {
+			return p, err
+		}
+	}
+	if err := m.checkpoints.Delete(ctx, task); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// verifyResource compares one resource's read models with its replayed
+// events and returns its drift, or nil when there is none.
+func (m *ProjectionMaintainer) verifyResource(
+	ctx context.Context, id string, repair bool,
+) (*ProjectionDrift, error) {
+	replayed, err := m.replayer.replay(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	state, edges := replayed.State, replayed.Triples
+	drift := &ProjectionDrift{ResourceID: id, TypeSlug: state.TypeSlug}
+
+	stored, err := m.resources.FindByID(ctx, id)
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		if !state.IsDelete {
+			drift.Problems = append(drift.Problems, "missing from the resources table")
+		}
+	case err != nil:
+		return nil, err
+	case state.IsDelete:
+		drift.Problems = append(drift.Problems, "deleted, but still in the resources table")
+	default:
+		drift.Problems = append(drift.Problems, compareResource(stored, state)...)
+	}
+
+	if !state.IsDelete {
+		row, err := m.resources.FindFlatByID(ctx, state.TypeSlug, id)
+		switch {
+		case errors.Is(err, repositories.ErrNoProjectionTable):
+		case errors.Is(err, repositories.ErrNotFound):
+			drift.Problems = append(drift.Problems, "missing from the "+state.TypeSlug+" projection table")
+		case err != nil:
+			return nil, err
+		default:
+			if seq := fmt.Sprint(row["sequenceNo"]); seq != fmt.Sprint(state.MaxSeq) {
+				drift.Problems = append(drift.Problems,
+					fmt.Sprintf("projection row is at sequence %s, events at %d", seq, state.MaxSeq))
+			}
+		}
+	}
+
+	stored3, err := m.triples.FindBySubject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	actual := make([]replayedTriple, 0, len(stored3))
+	for _, t := range stored3 {
+		actual = append(actual, replayedTriple{t.Predicate, t.Object})
+	}
+	if !sameTriples(actual, edges) {
+		drift.Problems = append(drift.Problems,
+			fmt.Sprintf("has %d triples, events have %d", len(actual), len(edges)))
+	}
+
+	if len(drift.Problems) == 0 {
+		return nil, nil
+	}
+	if repair {
+		if err := m.repair(ctx, id, state, edges); err != nil {
+			return drift, fmt.Errorf("failed to repair %s: %w", id, err)
+		}
+		drift.Repaired = true
+	}
+	return drift, nil
+}
+
+// compareResource lists how the stored resource differs from its replayed state.
+func compareResource(stored *entities.Resource, state txResourceState) []string {
+	var problems []string
+	if stored.TypeSlug() != state.TypeSlug {
+		problems = append(problems, fmt.Sprintf("type is %q, events say %q", stored.TypeSlug(), state.TypeSlug))
+	}
+	if stored.AccountID() != state.AccountID {
+		problems = append(problems, fmt.Sprintf("account is %q, events say %q", stored.AccountID(), state.AccountID))
+	}
+	if stored.CreatedBy() != state.CreatedBy {
+		problems = append(problems, fmt.Sprintf("creator is %q, events say %q", stored.CreatedBy(), state.CreatedBy))
+	}
+	if stored.GetSequenceNo() != state.MaxSeq {
+		problems = append(problems,
+			fmt.Sprintf("resource is at sequence %d, events at %d", stored.GetSequenceNo(), state.MaxSeq))
+	}
+	if !sameJSON(stored.Data(), state.Data) {
+		problems = append(problems, "data differs from the events")
+	}
+	return problems
+}
+
+func sameJSON(a, b json.RawMessage) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func sameTriples(a, b []replayedTriple) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, t := range a {
+		if !slices.Contains(b, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// repair rewrites one resource's read models from its replayed state.
+func (m *ProjectionMaintainer) repair(
+	ctx context.Context, id string, state txResourceState, edges []replayedTriple,
+) error {
+	if err := m.resources.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := m.triples.DeleteBySubject(ctx, id); err != nil {
+		return err
+	}
+	for _, t := range edges {
+		if err := m.triples.SaveTriple(ctx, id, t.Predicate, t.Object); err != nil {
+			return err
+		}
+	}
+	inheritedAncestors.forget(id)
+	if state.IsDelete {
+		return nil
+	}
+	if state.Data == nil {
+		return fmt.Errorf("no resource data in the events of %s", id)
+	}
+	entity := &entities.Resource{}
+	if err := entity.Restore(id, state.TypeSlug, "active", state.Data,
+		state.CreatedBy, state.AccountID, state.CreatedAt, state.MaxSeq); err != nil {
+		return err
+	}
+	if err := m.resources.Save(ctx, entity); err != nil {
+		return err
+	}
+	return propagateDisplayValues(ctx, id, state.Data, m.projMgr, m.logger)
+}
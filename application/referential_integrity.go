@@ -0,0 +1,366 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+// x-on-delete policies. A reference property declares what happens to the
+// referring resource when the resource it points at is deleted. Without a
+// policy the reference is left in place (the pre-existing behaviour).
+const (
+	// OnDeleteRestrict blocks deleting the target while any referrer exists.
+	OnDeleteRestrict = "restrict"
+	// OnDeleteCascade deletes every referrer along with the target.
+	OnDeleteCascade = "cascade"
+	// OnDeleteSetNull removes the reference from each referrer.
+	OnDeleteSetNull = "set-null"
+)
+
+var onDeletePolicies = map[string]bool{
+	OnDeleteRestrict: true,
+	OnDeleteCascade:  true,
+	OnDeleteSetNull:  true,
+}
+
+// verifyReferences checks every reference value in data before it is
+// committed: the target must exist, must not be archived, must be of the
+// declared x-resource-type (or a subtype of it), and must be readable by the
+// caller. Values whose predicate|object key is in skip are not checked —
+// Update passes the resource's existing triples so an edit to an unrelated
+// field doesn't fail because an old target has since gone away.
+//
+// Missing and unreadable targets produce the same message so the check can't
+// be used to probe for IDs the caller has no access to.
+func (s *resourceService) verifyReferences(
+	ctx context.Context, data json.RawMessage, refProps []ReferencePropertyDef, skip map[string]bool,
+) error {
+	if len(refProps) == 0 {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil // schema validation reports malformed data
+	}
+	for _, rp := range refProps {
+		for _, ref := range collectReferenceTriples(m, []ReferencePropertyDef{rp}) {
+			if skip[ref.Predicate+"|"+ref.Object] {
+				continue
+			}
+			if err := s.verifyReferenceTarget(ctx, rp, ref.Object); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *resourceService) verifyReferenceTarget(
+	ctx context.Context, rp ReferencePropertyDef, targetID string,
+) error {
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("%s: referenced resource %q does not exist: %w",
+				rp.PropertyName, targetID, ErrValidation)
+		}
+		return fmt.Errorf("failed to load referenced resource %q: %w", targetID, err)
+	}
+	if target.Status() == "archived" {
+		return fmt.Errorf("%s: referenced resource %q does not exist: %w",
+			rp.PropertyName, targetID, ErrValidation)
+	}
+	if err := s.checkInstanceAccess(ctx, target, "read"); err != nil {
+		if errors.Is(err, entities.ErrAccessDenied) {
+			return fmt.Errorf("%s: referenced resource %q does not exist: %w",
+				rp.PropertyName, targetID, ErrValidation)
+		}
+		return err
+	}
+	if !s.isTypeOrSubtype(target.TypeSlug(), rp.TargetType) {
+		return fmt.Errorf("%s: referenced resource %q is a %q, expected %q: %w",
+			rp.PropertyName, targetID, target.TypeSlug(), rp.TargetType, ErrValidation)
+	}
+	return nil
+}
+
+// isTypeOrSubtype reports whether slug is want or declares want as an
+// ancestor through rdfs:subClassOf. Without a ProjectionManager (unit tests)
+// only an exact match is accepted.
+func (s *resourceService) isTypeOrSubtype(slug, want string) bool {
+	if slug == want {
+		return true
+	}
+	if s.projMgr == nil {
+		return false
+	}
+	return slices.Contains(s.projMgr.AncestorSlugs(slug), want)
+}
+
+type policyWriteKey struct{}
+
+// withPolicyWrite marks ctx as carrying out the x-on-delete policies of a
+// delete the caller was authorized to make. The writes keep the caller as
+// their actor for the audit log but skip the caller's permission checks: the
+// policy is part of the schema rather than a choice the caller makes per
+// referrer, the same way a SQL ON DELETE CASCADE does not check privileges
+// on the child table.
+func withPolicyWrite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, policyWriteKey{}, true)
+}
+
+func isPolicyWrite(ctx context.Context) bool {
+	ok, _ := ctx.Value(policyWriteKey{}).(bool)
+	return ok
+}
+
+// referrer is one resource pointing at a resource being deleted, together
+// with the reference properties (and their policies) that point at it.
+type referrer struct {
+	entity *entities.Resource
+	rt     *entities.ResourceType
+	props  []ReferencePropertyDef
+}
+
+// setNull is a referrer that survives a delete with the references to the
+// deleted resources taken out.
+type setNull struct {
+	ref     *referrer
+	targets map[string]bool
+	data    json.RawMessage
+}
+
+// deletePlan is every write a delete leads to through x-on-delete policies,
+// worked out before any of them is made.
+type deletePlan struct {
+	deleting map[string]bool
+	// cascade holds the referrers to delete, each after the ones it cascades to.
+	cascade []*entities.Resource
+	setNull map[string]*setNull
+	order   []string // setNull keys in the order found
+}
+
+// applyDeletePolicies enforces the x-on-delete policies of every resource
+// referring to target, following cascades to the resources referring to
+// those. Every write is planned and checked first — restrict blockers
+// anywhere in the cascade, and set-null edits that would fail validation —
+// so a blocked delete changes nothing. Then the set-nulls run, the cascaded
+// deletes follow deepest first, and the caller deletes target last: if a
+// write still fails, target is in place and the delete can be retried.
+//
+// Referrers are found through TripleRepository.FindByObject and matched to
+// reference properties by predicate and declared target type.
+func (s *resourceService) applyDeletePolicies(ctx context.Context, target *entities.Resource) error {
+	if isPolicyWrite(ctx) {
+		return nil // planned with the delete that led here
+	}
+	plan := &deletePlan{deleting: make(map[string]bool), setNull: make(map[string]*setNull)}
+	if err := s.planDelete(ctx, target, plan); err != nil {
+		return err
+	}
+	for _, id := range plan.order {
+		n := plan.setNull[id]
+		if plan.deleting[id] {
+			continue
+		}
+		data, err := s.clearedData(n.ref, n.targets)
+		if err != nil {
+			return err
+		}
+		if err := validateAgainstSchema(n.ref.rt.Schema(), data); err != nil {
+			return fmt.Errorf("cannot delete %q: set-null on %q fails schema validation: %w",
+				target.GetID(), id, err)
+		}
+		if err := s.checkValidationRules(ctx, n.ref.rt, data); err != nil {
+			return fmt.Errorf("cannot delete %q: set-null on %q: %w", target.GetID(), id, err)
+		}
+		n.data = data
+	}
+
+	ctx = withPolicyWrite(ctx)
+	for _, id := range plan.order {
+		n := plan.setNull[id]
+		if n.data == nil {
+			continue
+		}
+		if _, err := s.Update(ctx, UpdateResourceCommand{ID: id, Data: n.data}); err != nil {
+			return fmt.Errorf("set-null on %q failed: %w", id, err)
+		}
+	}
+	for _, entity := range plan.cascade {
+		if err := s.Delete(ctx, DeleteResourceCommand{ID: entity.GetID()}); err != nil {
+			return fmt.Errorf("cascade delete of %q failed: %w", entity.GetID(), err)
+		}
+	}
+	return nil
+}
+
+// planDelete adds the writes deleting target leads to to plan, failing on
+// the first restrict blocker. A reference cycle (A cascades to B, B back to
+// A) stops at the resource already being deleted.
+func (s *resourceService) planDelete(ctx context.Context, target *entities.Resource, plan *deletePlan) error {
+	plan.deleting[target.GetID()] = true
+	triples, err := s.tripleRepo.FindByObject(ctx, target.GetID())
+	if err != nil {
+		return fmt.Errorf("failed to load referrers: %w", err)
+	}
+
+	var referrers []*referrer
+	bySubject := make(map[string]*referrer)
+	for _, t := range triples {
+		if plan.deleting[t.Subject] {
+			continue
+		}
+		ref, ok := bySubject[t.Subject]
+		if !ok {
+			ref, err = s.loadReferrer(ctx, t.Subject)
+			if err != nil {
+				return err
+			}
+			bySubject[t.Subject] = ref
+			if ref != nil {
+				referrers = append(referrers, ref)
+			}
+		}
+		if ref == nil {
+			continue
+		}
+		for _, rp := range s.referencePropsFor(ref.rt) {
+			if rp.PredicateIRI != t.Predicate || rp.OnDelete == "" {
+				continue
+			}
+			if !s.isTypeOrSubtype(target.TypeSlug(), rp.TargetType) {
+				continue
+			}
+			ref.props = append(ref.props, rp)
+		}
+	}
+
+	for _, ref := range referrers {
+		for _, rp := range ref.props {
+			if rp.OnDelete == OnDeleteRestrict {
+				return fmt.Errorf("cannot delete %q: still referenced by %q through %s: %w",
+					target.GetID(), ref.entity.GetID(), rp.PropertyName, ErrConflict)
+			}
+		}
+	}
+
+	for _, ref := range referrers {
+		if len(ref.props) == 0 {
+			continue
+		}
+		id := ref.entity.GetID()
+		if slices.ContainsFunc(ref.props, func(rp ReferencePropertyDef) bool {
+			return rp.OnDelete == OnDeleteCascade
+		}) {
+			if plan.deleting[id] {
+				continue
+			}
+			if err := s.planDelete(ctx, ref.entity, plan); err != nil {
+				return err
+			}
+			plan.cascade = append(plan.cascade, ref.entity)
+			continue
+		}
+		n, ok := plan.setNull[id]
+		if !ok {
+			n = &setNull{ref: ref, targets: make(map[string]bool)}
+			plan.setNull[id] = n
+			plan.order = append(plan.order, id)
+		} else {
+			n.ref.props = append(n.ref.props, ref.props...)
+		}
+		n.targets[target.GetID()] = true
+	}
+	return nil
+}
+
+// loadReferrer loads a referring resource and its type. It returns nil (no
+// error) when the subject no longer exists — a stale triple is not a reason
+// to block the delete.
+func (s *resourceService) loadReferrer(ctx context.Context, id string) (*referrer, error) {
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load referrer %q: %w", id, err)
+	}
+	rt, err := s.typeRepo.FindBySlug(ctx, entity.TypeSlug())
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load type of referrer %q: %w", id, err)
+	}
+	return &referrer{entity: entity, rt: rt}, nil
+}
+
+// clearedData returns the referrer's data with targets removed from its
+// set-null properties. It is saved through Update, so behaviors, validation
+// and triple reconciliation run exactly as for a user edit. A required
+// property can't be nulled; the resulting validation error blocks the delete.
+func (s *resourceService) clearedData(ref *referrer, targets map[string]bool) (json.RawMessage, error) {
+	var data map[string]any
+	if err := json.Unmarshal(FlattenGraph(ref.entity.Data(), ref.rt.Context()), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode referrer data: %w", err)
+	}
+	for _, rp := range ref.props {
+		switch v := data[rp.PropertyName].(type) {
+		case string:
+			if targets[v] {
+				delete(data, rp.PropertyName)
+			}
+		case []any:
+			kept := make([]any, 0, len(v))
+			for _, item := range v {
+				if id, _ := item.(string); !targets[id] {
+					kept = append(kept, item)
+				}
+			}
+			data[rp.PropertyName] = kept
+		}
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode referrer data: %w", err)
+	}
+	return raw, nil
+}
+
+// validateOnDeletePolicies rejects x-on-delete values other than the three
+// supported policies, and x-on-delete on a property that isn't a reference.
+func validateOnDeletePolicies(schema json.RawMessage) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	var s struct {
+		Properties map[string]struct {
+			XResourceType string `json:"x-resource-type"`
+			XOnDelete     string `json:"x-on-delete"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil
+	}
+	for propName, prop := range s.Properties {
+		if prop.XOnDelete == "" {
+			continue
+		}
+		if prop.XResourceType == "" {
+			return fmt.Errorf("property %q: x-on-delete requires x-resource-type: %w",
+				propName, ErrValidation)
+		}
+		if !onDeletePolicies[prop.XOnDelete] {
+			return fmt.Errorf("property %q: x-on-delete must be one of restrict, cascade, set-null, got %q: %w",
+				propName, prop.XOnDelete, ErrValidation)
+		}
+	}
+	return nil
+}
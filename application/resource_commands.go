@@ -30,3 +30,8 @@ type UpdateResourceCommand struct {
 type DeleteResourceCommand struct {
 	ID string
 }
+
+type TransitionResourceCommand struct {
+	ID   string
+	Name string
+}
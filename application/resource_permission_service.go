@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/wepala/weos/v3/domain/entities"
@@ -13,15 +14,21 @@ import (
 	"go.uber.org/fx"
 )
 
+// GrantPermissionCommand grants actions to an agent or, with TeamID, to
+// every member of a team. Exactly one of AgentID and TeamID is set.
 type GrantPermissionCommand struct {
 	ResourceID string          `json:"resource_id"`
 	AgentID    string          `json:"agent_id"`
+	TeamID     string          `json:"team_id"`
 	Actions    json.RawMessage `json:"actions"` // JSON array: ["read","modify","delete"]
 }
 
+// RevokePermissionCommand revokes the grant made to an agent or, with
+// TeamID, to a team.
 type RevokePermissionCommand struct {
 	ResourceID string `json:"resource_id"`
 	AgentID    string `json:"agent_id"`
+	TeamID     string `json:"team_id"`
 }
 
 type ResourcePermissionService interface {
@@ -33,6 +40,7 @@ type ResourcePermissionService interface {
 type resourcePermissionService struct {
 	permRepo     repositories.ResourcePermissionRepository
 	resourceRepo repositories.ResourceRepository
+	teamRepo     repositories.TeamRepository
 	accountRepo  authrepos.AccountRepository
 	logger       entities.Logger
 }
@@ -41,12 +49,14 @@ func ProvideResourcePermissionService(params struct {
 	fx.In
 	PermRepo     repositories.ResourcePermissionRepository
 	ResourceRepo repositories.ResourceRepository
+	TeamRepo     repositories.TeamRepository
 	AccountRepo  authrepos.AccountRepository
 	Logger       entities.Logger
 }) ResourcePermissionService {
 	return &resourcePermissionService{
 		permRepo:     params.PermRepo,
 		resourceRepo: params.ResourceRepo,
+		teamRepo:     params.TeamRepo,
 		accountRepo:  params.AccountRepo,
 		logger:       params.Logger,
 	}
@@ -81,6 +91,14 @@ func (s *resourcePermissionService) Grant(
 	if err := s.canManagePermissions(ctx, resource); err != nil {
 		return err
 	}
+	if (cmd.AgentID == "") == (cmd.TeamID == "") {
+		return fmt.Errorf("exactly one of agent_id and team_id is required: %w", ErrValidation)
+	}
+	if cmd.TeamID != "" {
+		if err := s.checkTeam(ctx, resource, cmd.TeamID); err != nil {
+			return err
+		}
+	}
 
 	var actions []string
 	if err := json.Unmarshal(cmd.Actions, &actions); err != nil {
@@ -96,6 +114,7 @@ func (s *resourcePermissionService) Grant(
 	perm := &entities.ResourcePermission{
 		ResourceID: cmd.ResourceID,
 		AgentID:    cmd.AgentID,
+		TeamID:     cmd.TeamID,
 		Actions:    actions,
 		GrantedBy:  grantedBy,
 	}
@@ -104,7 +123,22 @@ func (s *resourcePermissionService) Grant(
 	}
 
 	s.logger.Info(ctx, "permission granted",
-		"resource", cmd.ResourceID, "agent", cmd.AgentID, "actions", actions)
+		"resource", cmd.ResourceID, "agent", cmd.AgentID, "team", cmd.TeamID, "actions", actions)
+	return nil
+}
+
+// checkTeam requires a team grantee to belong to the resource's account, so
+// a grant never reaches agents outside it.
+func (s *resourcePermissionService) checkTeam(
+	ctx context.Context, resource *entities.Resource, teamID string,
+) error {
+	team, err := s.teamRepo.FindByID(ctx, teamID)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && team.AccountID != resource.AccountID()) {
+		return fmt.Errorf("team %q is not a team of the resource's account: %w", teamID, ErrValidation)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find team: %w", err)
+	}
 	return nil
 }
 
@@ -119,12 +153,17 @@ func (s *resourcePermissionService) Revoke(
 		return err
 	}
 
-	if err := s.permRepo.Revoke(ctx, cmd.ResourceID, cmd.AgentID); err != nil {
+	if cmd.TeamID != "" {
+		err = s.permRepo.RevokeTeam(ctx, cmd.ResourceID, cmd.TeamID)
+	} else {
+		err = s.permRepo.Revoke(ctx, cmd.ResourceID, cmd.AgentID)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to revoke permission: %w", err)
 	}
 
 	s.logger.Info(ctx, "permission revoked",
-		"resource", cmd.ResourceID, "agent", cmd.AgentID)
+		"resource", cmd.ResourceID, "agent", cmd.AgentID, "team", cmd.TeamID)
 	return nil
 }
 
@@ -42,12 +42,18 @@ type ResourceService interface {
 		repositories.PaginatedResponse[map[string]any], error)
 	Update(ctx context.Context, cmd UpdateResourceCommand) (*entities.Resource, error)
 	Delete(ctx context.Context, cmd DeleteResourceCommand) error
+	// Transition moves a resource along a named x-workflow transition.
+	Transition(ctx context.Context, cmd TransitionResourceCommand) (*entities.Resource, error)
+	// AvailableTransitions lists the x-workflow transitions the caller can
+	// trigger on a resource in its current state.
+	AvailableTransitions(ctx context.Context, id string) ([]AvailableTransition, error)
 }
 
 type resourceService struct {
 	repo             repositories.ResourceRepository
 	typeRepo         repositories.ResourceTypeRepository
 	tripleRepo       repositories.TripleRepository
+	projMgr          repositories.ProjectionManager
 	permRepo         repositories.ResourcePermissionRepository
 	accountRepo      authrepos.AccountRepository
 	eventStore       domain.EventStore
@@ -57,6 +63,10 @@ type resourceService struct {
 	behaviorMeta     BehaviorMetaRegistry
 	behaviorSettings repositories.BehaviorSettingsRepository
 	linkRegistry     *LinkRegistry
+	encryption       fieldEncryption
+	fieldAccess      fieldPermissions
+	inheritance      *permissionInheritance // nil: x-inherit-permissions-from is not followed
+	roles            RoleAuthorizer         // nil: role assignments are not consulted
 }
 
 // referencePropsFor returns the merged list of schema-declared and
@@ -200,6 +210,7 @@ func ProvideResourceService(params struct {
 	Repo             repositories.ResourceRepository
 	TypeRepo         repositories.ResourceTypeRepository
 	TripleRepo       repositories.TripleRepository
+	ProjMgr          repositories.ProjectionManager `optional:"true"`
 	PermRepo         repositories.ResourcePermissionRepository
 	AccountRepo      authrepos.AccountRepository
 	EventStore       domain.EventStore
@@ -208,12 +219,16 @@ func ProvideResourceService(params struct {
 	Behaviors        ResourceBehaviorRegistry
 	BehaviorMeta     BehaviorMetaRegistry
 	BehaviorSettings repositories.BehaviorSettingsRepository
-	LinkRegistry     *LinkRegistry `optional:"true"`
+	LinkRegistry     *LinkRegistry                             `optional:"true"`
+	FieldKeys        entities.FieldKeyProvider                 `optional:"true"`
+	RoleAccess       repositories.RoleResourceAccessRepository `optional:"true"`
+	Roles            RoleAuthorizer                            `optional:"true"`
 }) ResourceService {
 	return &resourceService{
 		repo:             params.Repo,
 		typeRepo:         params.TypeRepo,
 		tripleRepo:       params.TripleRepo,
+		projMgr:          params.ProjMgr,
 		permRepo:         params.PermRepo,
 		accountRepo:      params.AccountRepo,
 		eventStore:       params.EventStore,
@@ -223,9 +238,101 @@ func ProvideResourceService(params struct {
 		behaviorMeta:     params.BehaviorMeta,
 		behaviorSettings: params.BehaviorSettings,
 		linkRegistry:     params.LinkRegistry,
+		encryption: fieldEncryption{
+			keys:     params.FieldKeys,
+			accounts: params.AccountRepo,
+			logger:   params.Logger,
+		},
+		fieldAccess: fieldPermissions{access: params.RoleAccess, accounts: params.AccountRepo},
+		inheritance: &permissionInheritance{
+			types:     params.TypeRepo,
+			triples:   params.TripleRepo,
+			resources: params.Repo,
+		},
+		roles: params.Roles,
 	}
 }
 
+// resourceView is what one call shows of a type's resources: x-encrypted
+// values are revealed to callers who may read them, and properties the
+// caller's role may not read are left out.
+type resourceView struct {
+	encrypted *encryptedReader
+	fields    *fieldRule
+}
+
+// viewOf returns the caller's view of rt's resources.
+func (s *resourceService) viewOf(ctx context.Context, rt *entities.ResourceType) (resourceView, error) {
+	return resourceViewOf(ctx, s.fieldAccess, s.encryption, rt)
+}
+
+func resourceViewOf(
+	ctx context.Context, fields fieldPermissions, encryption fieldEncryption, rt *entities.ResourceType,
+) (resourceView, error) {
+	rule, err := fields.rule(ctx, rt)
+	if err != nil {
+		return resourceView{}, err
+	}
+	return resourceView{encrypted: encryption.reader(encryptedFieldsOf(rt.Schema())), fields: rule}, nil
+}
+
+// viewFor returns the caller's view of typeSlug's resources. An unknown type
+// has nothing to reveal or restrict.
+func (s *resourceService) viewFor(ctx context.Context, typeSlug string) (resourceView, error) {
+	if s.typeRepo == nil {
+		return resourceView{}, nil
+	}
+	rt, err := s.typeRepo.FindBySlug(ctx, typeSlug)
+	if err != nil || rt == nil {
+		return resourceView{}, nil
+	}
+	return s.viewOf(ctx, rt)
+}
+
+// checkQuery rejects filters and sorts on properties the database cannot
+// compare or the caller may not read.
+func (v resourceView) checkQuery(filters []repositories.FilterCondition, sort repositories.SortOptions) error {
+	if v.encrypted != nil {
+		if err := checkEncryptedQuery(v.encrypted.fields, filters, sort); err != nil {
+			return err
+		}
+	}
+	return v.fields.checkQuery(filters, sort)
+}
+
+func (v resourceView) resource(ctx context.Context, entity *entities.Resource) *entities.Resource {
+	return v.fields.resource(v.encrypted.resource(ctx, entity))
+}
+
+// data returns the caller's view of the data of the resource resourceID,
+// in accountID.
+func (v resourceView) data(ctx context.Context, resourceID, accountID string, data map[string]any) map[string]any {
+	return v.fields.data(v.encrypted.data(ctx, resourceID, accountID, data))
+}
+
+func (v resourceView) row(ctx context.Context, row map[string]any) {
+	v.encrypted.row(ctx, row)
+	v.fields.row(row)
+}
+
+func (v resourceView) page(
+	ctx context.Context, page repositories.PaginatedResponse[*entities.Resource],
+) repositories.PaginatedResponse[*entities.Resource] {
+	for i, e := range page.Data {
+		page.Data[i] = v.resource(ctx, e)
+	}
+	return page
+}
+
+func (v resourceView) rows(
+	ctx context.Context, page repositories.PaginatedResponse[map[string]any],
+) repositories.PaginatedResponse[map[string]any] {
+	for _, row := range page.Data {
+		v.row(ctx, row)
+	}
+	return page
+}
+
 func (s *resourceService) Create(
 	ctx context.Context, cmd CreateResourceCommand,
 ) (*entities.Resource, error) {
@@ -241,6 +348,14 @@ func (s *resourceService) Create(
 		return nil, fmt.Errorf("cannot create resource of abstract type %q: use a concrete subtype instead", cmd.TypeSlug)
 	}
 
+	view, err := s.viewOf(ctx, rt)
+	if err != nil {
+		return nil, err
+	}
+	if err := view.fields.checkCreate(cmd.Data); err != nil {
+		return nil, err
+	}
+
 	behavior := s.behaviorFor(ctx, rt)
 
 	data, err := behavior.BeforeCreate(ctx, cmd.Data, rt)
@@ -248,16 +363,35 @@ func (s *resourceService) Create(
 		return nil, fmt.Errorf("behavior BeforeCreate rejected: %w", err)
 	}
 
+	data, err = s.applyComputedProperties(ctx, rt, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = applyWorkflowState(rt, nil, data)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := validateAgainstSchema(rt.Schema(), data); err != nil {
 		return nil, fmt.Errorf("schema validation failed: %w", err)
 	}
 
+	if err := s.checkValidationRules(ctx, rt, data); err != nil {
+		return nil, err
+	}
+
 	var createdBy, accountID string
 	if ident := auth.AgentFromCtx(ctx); ident != nil {
 		createdBy = ident.AgentID
 		accountID = ident.ActiveAccountID
 	}
 
+	if err := s.checkUniqueConstraints(ctx, cmd.TypeSlug, rt.Schema(), data, accountID, ""); err != nil {
+		return nil, err
+	}
+	keys := uniqueKeys(cmd.TypeSlug, rt.Schema(), data, accountID)
+
 	entityID := identity.NewResource(cmd.TypeSlug)
 	refProps := s.referencePropsFor(rt)
 
@@ -268,6 +402,14 @@ func (s *resourceService) Create(
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse resource data: %w", err)
 	}
+	if err := s.verifyReferences(ctx, data, refProps, nil); err != nil {
+		return nil, err
+	}
+
+	data, err = s.encryption.encrypt(ctx, encryptedFieldsOf(rt.Schema()), entityID, data)
+	if err != nil {
+		return nil, err
+	}
 
 	graphData, err := BuildResourceGraph(data, refProps, entityID, rt.Name(), rt.Context())
 	if err != nil {
@@ -300,7 +442,13 @@ func (s *resourceService) Create(
 	if err := uow.Track(entity); err != nil {
 		return nil, fmt.Errorf("failed to track resource: %w", err)
 	}
+	if len(keys) > 0 {
+		ctx = entities.ContextWithUniqueKeys(ctx, entityID, keys)
+	}
 	if err := uow.Commit(ctx); err != nil {
+		if conflict := uniqueKeyConflict(err); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("failed to commit resource: %w", err)
 	}
 
@@ -309,48 +457,140 @@ func (s *resourceService) Create(
 	}
 
 	s.logger.Info(ctx, "resource created", "id", entity.GetID(), "type", cmd.TypeSlug)
-	return entity, nil
+	return view.resource(ctx, entity), nil
 }
 
-func (s *resourceService) buildVisibilityScope(ctx context.Context) *repositories.VisibilityScope {
+func (s *resourceService) buildVisibilityScope(ctx context.Context, typeSlug string) *repositories.VisibilityScope {
 	identity := auth.AgentFromCtx(ctx)
 	if identity == nil {
 		return nil
 	}
-	return &repositories.VisibilityScope{
+	scope := &repositories.VisibilityScope{
 		AgentID:   identity.AgentID,
 		AccountID: identity.ActiveAccountID,
 		IsAdmin:   false, // per-user scoping: lists always filter by creator + permissions
 	}
+	if s.inheritance != nil {
+		scope.Inherit = s.inheritance.scope(ctx, typeSlug)
+	}
+	return scope
 }
 
 func (s *resourceService) checkInstanceAccess(
 	ctx context.Context, entity *entities.Resource, action string,
 ) error {
+	access := instanceAccess{accounts: s.accountRepo, perms: s.permRepo, inherit: s.inheritance, roles: s.roles}
+	return access.check(
+		ctx, entity.GetID(), entity.TypeSlug(), entity.AccountID(), entity.CreatedBy(), action)
+}
+
+// instanceAccess decides whether the caller may act on one resource, given
+// the resource's type, account and creator.
+type instanceAccess struct {
+	accounts authrepos.AccountRepository
+	perms    repositories.ResourcePermissionRepository
+	inherit  *permissionInheritance // nil: x-inherit-permissions-from is not followed
+	roles    RoleAuthorizer         // nil: role assignments are not consulted
+}
+
+func (a instanceAccess) check(ctx context.Context, id, typeSlug, accountID, createdBy, action string) error {
 	identity := auth.AgentFromCtx(ctx)
 	if identity == nil {
 		return nil // system context (CLI/MCP) — allow
 	}
+	if isPolicyWrite(ctx) {
+		return nil // x-on-delete writes of a delete already authorized
+	}
+	if a.allows(ctx, identity.AgentID, id, typeSlug, accountID, createdBy, action, nil) {
+		return nil
+	}
+	return entities.ErrAccessDenied
+}
+
+// allows reports whether agentID may act on the resource, recording the
+// checks it makes in trace unless trace is nil.
+func (a instanceAccess) allows(
+	ctx context.Context, agentID, id, typeSlug, accountID, createdBy, action string, trace *authorizationTrace,
+) bool {
+	if a.direct(ctx, agentID, id, accountID, createdBy, action, trace) {
+		return true
+	}
+	// A role assignment covering the resource: editors and above act on any
+	// resource in its scope, and viewers and above read them
+	if a.roles != nil {
+		decision, err := a.roles.Authorize(ctx, AuthorizationRequest{
+			AgentID: agentID, AccountID: accountID,
+			Action: ODRLActions[action], TypeSlug: typeSlug, ResourceID: id,
+		})
+		switch {
+		case err != nil:
+			trace.add(CheckRoleScope, OutcomeDeny, "role check failed: "+err.Error(), "")
+		case decision.Allowed && decision.AnyResource:
+			trace.add(CheckRoleScope, OutcomeAllow, decision.Reason+", on any resource in its scope", decision.Policy)
+			return true
+		case decision.Allowed:
+			trace.add(CheckRoleScope, OutcomeDeny, decision.Reason+", but only on the agent's own resources",
+				decision.Policy)
+		default:
+			trace.add(CheckRoleScope, OutcomeDeny, decision.Reason, decision.Policy)
+		}
+	}
+	// Inherited from a resource it references through x-inherit-permissions-from
+	if a.inherit != nil {
+		var from string
+		if a.inherit.allows(ctx, id, typeSlug, func(parent *entities.Resource) bool {
+			from = parent.GetID()
+			return a.direct(ctx, agentID, parent.GetID(), parent.AccountID(), parent.CreatedBy(), action, nil)
+		}) {
+			trace.add(CheckInherited, OutcomeAllow, "allowed on "+from+", which it inherits permissions from", "")
+			return true
+		}
+		if trace != nil && len(a.inherit.parents(ctx, typeSlug)) > 0 {
+			trace.add(CheckInherited, OutcomeDeny, "no resource it inherits permissions from allows it", "")
+		}
+	}
+	// Backward compatibility: pre-migration resources with no owner
+	if createdBy == "" {
+		trace.add(CheckUnowned, OutcomeAllow, "the resource predates ownership and has no creator", "")
+		return true
+	}
+	return false
+}
+
+// direct reports whether agentID may act on the resource itself: as an
+// admin or owner of its account, as its creator, or through a grant.
+func (a instanceAccess) direct(
+	ctx context.Context, agentID, id, accountID, createdBy, action string, trace *authorizationTrace,
+) bool {
 	// Admin/owner bypass: only if the caller is admin/owner in the RESOURCE's account
-	if entity.AccountID() != "" {
-		role, _ := s.accountRepo.FindMemberRole(ctx, entity.AccountID(), identity.AgentID)
+	if accountID != "" {
+		role, _ := a.accounts.FindMemberRole(ctx, accountID, agentID)
 		if role == "admin" || role == "owner" {
-			return nil
+			trace.add(CheckAccountAdmin, OutcomeAllow, fmt.Sprintf("%s of the resource's account", role), "")
+			return true
+		}
+		if role == "" {
+			trace.add(CheckAccountAdmin, OutcomeDeny, "the agent is not a member of the resource's account", "")
+		} else {
+			trace.add(CheckAccountAdmin, OutcomeDeny, fmt.Sprintf("account role %q is not admin or owner", role), "")
 		}
 	}
 	// Creator access
-	if entity.CreatedBy() == identity.AgentID {
-		return nil
+	if createdBy == agentID {
+		trace.add(CheckOwnership, OutcomeAllow, "the agent created the resource", "")
+		return true
 	}
-	// Explicit permission grant
-	if has, _ := s.permRepo.HasPermission(ctx, entity.GetID(), identity.AgentID, action); has {
-		return nil
+	if createdBy != "" {
+		trace.add(CheckOwnership, OutcomeDeny, "the resource was created by "+createdBy, "")
 	}
-	// Backward compatibility: pre-migration resources with no owner
-	if entity.CreatedBy() == "" {
-		return nil
+	// Explicit permission grant
+	has, _ := a.perms.HasPermission(ctx, id, agentID, action)
+	if has {
+		trace.add(CheckResourcePermission, OutcomeAllow, action+" is granted to the agent or one of their teams", "")
+	} else {
+		trace.add(CheckResourcePermission, OutcomeDeny, "no "+action+" grant to the agent or their teams", "")
 	}
-	return entities.ErrAccessDenied
+	return has
 }
 
 func (s *resourceService) GetByID(
@@ -363,32 +603,66 @@ func (s *resourceService) GetByID(
 	if err := s.checkInstanceAccess(ctx, entity, "read"); err != nil {
 		return nil, err
 	}
-	return entity, nil
+	view, err := s.viewFor(ctx, entity.TypeSlug())
+	if err != nil {
+		return nil, err
+	}
+	return view.resource(ctx, entity), nil
 }
 
 func (s *resourceService) List(
 	ctx context.Context, typeSlug, cursor string, limit int, sort repositories.SortOptions,
 ) (repositories.PaginatedResponse[*entities.Resource], error) {
-	return s.repo.FindAllByType(ctx, typeSlug, cursor, limit, sort, s.buildVisibilityScope(ctx))
+	view, err := s.viewFor(ctx, typeSlug)
+	if err != nil {
+		return repositories.PaginatedResponse[*entities.Resource]{}, err
+	}
+	if err := view.checkQuery(nil, sort); err != nil {
+		return repositories.PaginatedResponse[*entities.Resource]{}, err
+	}
+	page, err := s.repo.FindAllByType(ctx, typeSlug, cursor, limit, sort, s.buildVisibilityScope(ctx, typeSlug))
+	if err != nil {
+		return page, err
+	}
+	return view.page(ctx, page), nil
 }
 
 func (s *resourceService) ListByField(
 	ctx context.Context, typeSlug, fieldName, fieldValue string,
 ) (repositories.PaginatedResponse[*entities.Resource], error) {
+	filter := []repositories.FilterCondition{{Field: fieldName, Operator: "eq", Value: fieldValue}}
+	view, err := s.viewFor(ctx, typeSlug)
+	if err != nil {
+		return repositories.PaginatedResponse[*entities.Resource]{}, err
+	}
+	if err := view.checkQuery(filter, repositories.SortOptions{}); err != nil {
+		return repositories.PaginatedResponse[*entities.Resource]{}, err
+	}
 	items, err := s.repo.FindAllByTypeAndField(ctx, typeSlug, fieldName, fieldValue)
 	if err != nil {
 		return repositories.PaginatedResponse[*entities.Resource]{}, err
 	}
-	return repositories.PaginatedResponse[*entities.Resource]{
+	return view.page(ctx, repositories.PaginatedResponse[*entities.Resource]{
 		Data:    items,
 		HasMore: false,
-	}, nil
+	}), nil
 }
 
 func (s *resourceService) ListFlat(
 	ctx context.Context, typeSlug, cursor string, limit int, sort repositories.SortOptions,
 ) (repositories.PaginatedResponse[map[string]any], error) {
-	return s.repo.FindAllByTypeFlat(ctx, typeSlug, cursor, limit, sort, s.buildVisibilityScope(ctx))
+	view, err := s.viewFor(ctx, typeSlug)
+	if err != nil {
+		return repositories.PaginatedResponse[map[string]any]{}, err
+	}
+	if err := view.checkQuery(nil, sort); err != nil {
+		return repositories.PaginatedResponse[map[string]any]{}, err
+	}
+	page, err := s.repo.FindAllByTypeFlat(ctx, typeSlug, cursor, limit, sort, s.buildVisibilityScope(ctx, typeSlug))
+	if err != nil {
+		return page, err
+	}
+	return view.rows(ctx, page), nil
 }
 
 func (s *resourceService) GetFlat(
@@ -410,23 +684,60 @@ func (s *resourceService) GetFlat(
 			id, entity.TypeSlug(), typeSlug, repositories.ErrNotFound,
 		)
 	}
-	return s.repo.FindFlatByID(ctx, typeSlug, id)
+	row, err := s.repo.FindFlatByID(ctx, typeSlug, id)
+	if err != nil {
+		return nil, err
+	}
+	view, err := s.viewFor(ctx, typeSlug)
+	if err != nil {
+		return nil, err
+	}
+	view.row(ctx, row)
+	return row, nil
 }
 
 func (s *resourceService) ListFlatWithFilters(
 	ctx context.Context, typeSlug string, filters []repositories.FilterCondition,
 	cursor string, limit int, sort repositories.SortOptions,
 ) (repositories.PaginatedResponse[map[string]any], error) {
-	scope := s.buildVisibilityScope(ctx)
-	return s.repo.FindAllByTypeFlatWithFilters(ctx, typeSlug, filters, cursor, limit, sort, scope)
+	view, err := s.viewFor(ctx, typeSlug)
+	if err != nil {
+		return repositories.PaginatedResponse[map[string]any]{}, err
+	}
+	if err := view.checkQuery(filters, sort); err != nil {
+		return repositories.PaginatedResponse[map[string]any]{}, err
+	}
+	scope := s.buildVisibilityScope(ctx, typeSlug)
+	page, err := s.repo.FindAllByTypeFlatWithFilters(ctx, typeSlug, filters, cursor, limit, sort, scope)
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		return page, fmt.Errorf("%v: %w", err, ErrValidation)
+	}
+	if err != nil {
+		return page, err
+	}
+	return view.rows(ctx, page), nil
 }
 
 func (s *resourceService) ListWithFilters(
 	ctx context.Context, typeSlug string, filters []repositories.FilterCondition,
 	cursor string, limit int, sort repositories.SortOptions,
 ) (repositories.PaginatedResponse[*entities.Resource], error) {
-	scope := s.buildVisibilityScope(ctx)
-	return s.repo.FindAllByTypeWithFilters(ctx, typeSlug, filters, cursor, limit, sort, scope)
+	view, err := s.viewFor(ctx, typeSlug)
+	if err != nil {
+		return repositories.PaginatedResponse[*entities.Resource]{}, err
+	}
+	if err := view.checkQuery(filters, sort); err != nil {
+		return repositories.PaginatedResponse[*entities.Resource]{}, err
+	}
+	scope := s.buildVisibilityScope(ctx, typeSlug)
+	page, err := s.repo.FindAllByTypeWithFilters(ctx, typeSlug, filters, cursor, limit, sort, scope)
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		return page, fmt.Errorf("%v: %w", err, ErrValidation)
+	}
+	if err != nil {
+		return page, err
+	}
+	return view.page(ctx, page), nil
 }
 
 func (s *resourceService) Update(
@@ -452,15 +763,58 @@ func (s *resourceService) Update(
 
 	behavior := s.behaviorFor(ctx, rt)
 
-	data, err := behavior.BeforeUpdate(ctx, entity, cmd.Data, rt)
+	view, err := s.viewOf(ctx, rt)
+	if err != nil {
+		return nil, err
+	}
+	data := cmd.Data
+	if view.fields != nil {
+		if data, err = view.fields.prepareUpdate(s.storedFields(ctx, rt, entity), data); err != nil {
+			return nil, err
+		}
+	}
+
+	encrypted := encryptedFieldsOf(rt.Schema())
+	data, err = s.encryption.prepareUpdate(ctx, encrypted, entity, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = behavior.BeforeUpdate(ctx, entity, data, rt)
 	if err != nil {
 		return nil, fmt.Errorf("behavior BeforeUpdate rejected: %w", err)
 	}
 
+	data, err = s.applyComputedProperties(ctx, rt, data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = applyWorkflowState(rt, entity, data)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := validateAgainstSchema(rt.Schema(), data); err != nil {
 		return nil, fmt.Errorf("schema validation failed: %w", err)
 	}
 
+	if err := s.checkValidationRules(ctx, rt, data); err != nil {
+		return nil, err
+	}
+
+	transition, fromState, err := s.checkTransition(ctx, rt, entity, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUniqueConstraints(
+		ctx, entity.TypeSlug(), rt.Schema(), data, entity.AccountID(), entity.GetID(),
+	); err != nil {
+		return nil, err
+	}
+	keys := uniqueKeys(entity.TypeSlug(), rt.Schema(), data, entity.AccountID())
+
 	refProps := s.referencePropsFor(rt)
 
 	// Extract reference triples for atomic UoW commit alongside the entity.
@@ -471,6 +825,23 @@ func (s *resourceService) Update(
 		return nil, fmt.Errorf("failed to parse resource data: %w", err)
 	}
 
+	existing, err := s.tripleRepo.FindBySubject(ctx, entity.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing triples for reconciliation: %w", err)
+	}
+	existingSet := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		existingSet[t.Predicate+"|"+t.Object] = true
+	}
+	if err := s.verifyReferences(ctx, data, refProps, existingSet); err != nil {
+		return nil, err
+	}
+
+	data, err = s.encryption.encrypt(ctx, encrypted, entity.GetID(), data)
+	if err != nil {
+		return nil, err
+	}
+
 	graphData, err := BuildResourceGraph(data, refProps, entity.GetID(), rt.Name(), rt.Context())
 	if err != nil {
 		return nil, fmt.Errorf("failed to build resource graph: %w", err)
@@ -480,10 +851,20 @@ func (s *resourceService) Update(
 		return nil, fmt.Errorf("failed to update resource: %w", err)
 	}
 
-	if err := s.reconcileTriples(ctx, entity, refProps, newRefs); err != nil {
+	if err := s.reconcileTriples(entity, refProps, existing, newRefs); err != nil {
 		return nil, err
 	}
 
+	if transition != nil {
+		ev := entities.ResourceTransitioned{}.With(entity.TypeSlug(), transition.name, fromState, transition.to)
+		if err := entity.RecordEvent(ev, ev.EventType()); err != nil {
+			return nil, fmt.Errorf("failed to record resource transitioned event: %w", err)
+		}
+		if err := recordEditorialEvents(entity, rt, transition, fromState); err != nil {
+			return nil, err
+		}
+	}
+
 	published := entities.ResourcePublished{}.With(entity.TypeSlug())
 	if err := entity.RecordEvent(published, published.EventType()); err != nil {
 		return nil, fmt.Errorf("failed to record resource published event: %w", err)
@@ -497,7 +878,13 @@ func (s *resourceService) Update(
 	if err := uow.Track(entity); err != nil {
 		return nil, fmt.Errorf("failed to track resource: %w", err)
 	}
+	// The reservation is replaced even when keys is empty, releasing the
+	// values the update cleared.
+	ctx = entities.ContextWithUniqueKeys(ctx, entity.GetID(), keys)
 	if err := uow.Commit(ctx); err != nil {
+		if conflict := uniqueKeyConflict(err); conflict != nil {
+			return nil, conflict
+		}
 		return nil, fmt.Errorf("failed to commit resource update: %w", err)
 	}
 
@@ -506,7 +893,26 @@ func (s *resourceService) Update(
 	}
 
 	s.logger.Info(ctx, "resource updated", "id", entity.GetID())
-	return entity, nil
+	return view.resource(ctx, entity), nil
+}
+
+// storedFields returns the resource's current data as flat JSON values, with
+// its x-encrypted values decrypted.
+func (s *resourceService) storedFields(
+	ctx context.Context, rt *entities.ResourceType, entity *entities.Resource,
+) map[string]any {
+	var stored map[string]any
+	if json.Unmarshal(FlattenGraph(entity.Data(), rt.Context()), &stored) != nil {
+		return nil
+	}
+	for name := range encryptedFieldsOf(rt.Schema()).properties {
+		if value, ok := stored[name]; ok {
+			if plain, ok := s.encryption.decryptValue(ctx, entity.GetID(), name, value); ok {
+				stored[name] = plain
+			}
+		}
+	}
+	return stored
 }
 
 func (s *resourceService) Delete(
@@ -536,6 +942,10 @@ func (s *resourceService) Delete(
 		return fmt.Errorf("behavior BeforeDelete rejected: %w", err)
 	}
 
+	if err := s.applyDeletePolicies(ctx, entity); err != nil {
+		return err
+	}
+
 	if err := entity.MarkDeleted(); err != nil {
 		return fmt.Errorf("failed to mark resource deleted: %w", err)
 	}
@@ -561,6 +971,8 @@ func (s *resourceService) Delete(
 	if err := uow.Track(entity); err != nil {
 		return fmt.Errorf("failed to track resource: %w", err)
 	}
+	// A deleted resource releases its unique values.
+	ctx = entities.ContextWithUniqueKeys(ctx, entity.GetID(), nil)
 	if err := uow.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit resource deletion: %w", err)
 	}
@@ -576,15 +988,11 @@ func (s *resourceService) Delete(
 // reconcileTriples diffs existing triples against new references and records
 // TripleCreated/TripleDeleted events on the entity for atomic UoW commit.
 func (s *resourceService) reconcileTriples(
-	ctx context.Context,
 	entity *entities.Resource,
 	refProps []ReferencePropertyDef,
+	existing []repositories.Triple,
 	newRefs []repositories.Triple,
 ) error {
-	existing, err := s.tripleRepo.FindBySubject(ctx, entity.GetID())
-	if err != nil {
-		return fmt.Errorf("failed to load existing triples for reconciliation: %w", err)
-	}
 	schemaPredicates := make(map[string]bool, len(refProps))
 	for _, rp := range refProps {
 		schemaPredicates[rp.PredicateIRI] = true
@@ -47,6 +47,10 @@ var ErrValidation = errors.New("validation error")
 // ErrForbidden is returned when the caller lacks required permissions.
 var ErrForbidden = errors.New("forbidden")
 
+// ErrConflict is returned when a write would violate a uniqueness constraint
+// declared on the resource type (x-unique, or x-indexes with "unique": true).
+var ErrConflict = errors.New("conflict")
+
 var reservedSlugs = map[string]bool{
 	"persons":        true,
 	"organizations":  true,
@@ -68,6 +72,8 @@ var reservedSlugs = map[string]bool{
 	"admin":          true,
 	"uploads":        true,
 	"mcp":            true,
+	"public":         true,
+	"webhooks":       true,
 }
 
 // ReservedResourceTypeSlugs returns the set of slugs that cannot be used as
@@ -157,6 +163,9 @@ func (s *resourceTypeService) Create(
 	if err := validateSlug(cmd.Slug); err != nil {
 		return nil, err
 	}
+	if err := validateSchemaAnnotations(cmd.Schema); err != nil {
+		return nil, err
+	}
 	entity, err := new(entities.ResourceType).With(
 		cmd.Name, cmd.Slug, cmd.Description, cmd.Context, cmd.Schema,
 	)
@@ -200,6 +209,9 @@ func (s *resourceTypeService) Update(
 	if err := validateSlug(cmd.Slug); err != nil {
 		return nil, err
 	}
+	if err := validateSchemaAnnotations(cmd.Schema); err != nil {
+		return nil, err
+	}
 	entity, err := s.repo.FindByID(ctx, cmd.ID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,235 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/identity"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+	"go.uber.org/fx"
+)
+
+// AssignRoleCommand gives an agent a role within one scope. Every scope
+// but global is bound to the caller's active account; system callers,
+// which have none, name the account instead.
+type AssignRoleCommand struct {
+	AgentID    string
+	Role       string
+	Scope      string
+	AccountID  string
+	TypeSlug   string
+	ResourceID string
+}
+
+// RoleAssignmentService manages scoped role assignments. Admins and owners
+// of an account, and site admins assigned to it, manage its assignments;
+// global assignments are managed by global super admins and system
+// callers only.
+type RoleAssignmentService interface {
+	Assign(ctx context.Context, cmd AssignRoleCommand) (*entities.RoleAssignment, error)
+	Revoke(ctx context.Context, id string) error
+	// List returns the assignments of the caller's active account, or, when
+	// agentID is set, that agent's assignments in it along with their
+	// global ones.
+	List(ctx context.Context, agentID string) ([]*entities.RoleAssignment, error)
+}
+
+type roleAssignmentService struct {
+	assignments repositories.RoleAssignmentRepository
+	accounts    authrepos.AccountRepository
+	types       repositories.ResourceTypeRepository
+	resources   repositories.ResourceRepository
+}
+
+func ProvideRoleAssignmentService(params struct {
+	fx.In
+	Assignments repositories.RoleAssignmentRepository
+	Accounts    authrepos.AccountRepository
+	TypeRepo    repositories.ResourceTypeRepository
+	Resources   repositories.ResourceRepository
+}) RoleAssignmentService {
+	return &roleAssignmentService{
+		assignments: params.Assignments,
+		accounts:    params.Accounts,
+		types:       params.TypeRepo,
+		resources:   params.Resources,
+	}
+}
+
+// caller returns the agent making the call ("" for system callers) and the
+// account they act in.
+func (s *roleAssignmentService) caller(ctx context.Context, accountID string) (string, string, error) {
+	ident := auth.AgentFromCtx(ctx)
+	if ident == nil {
+		return "", accountID, nil
+	}
+	if ident.ActiveAccountID == "" {
+		return "", "", fmt.Errorf("role assignments require an active account: %w", entities.ErrAccessDenied)
+	}
+	return ident.AgentID, ident.ActiveAccountID, nil
+}
+
+// canManage reports whether agentID may manage the assignments of
+// accountID, or the global ones when accountID is empty.
+func (s *roleAssignmentService) canManage(ctx context.Context, agentID, accountID string) (bool, error) {
+	if agentID == "" {
+		return true, nil
+	}
+	if accountID != "" {
+		role, err := s.accounts.FindMemberRole(ctx, accountID, agentID)
+		if err != nil {
+			return false, fmt.Errorf("failed to find member role: %w", err)
+		}
+		if role == authentities.RoleOwner || role == authentities.RoleAdmin {
+			return true, nil
+		}
+	}
+	held, err := s.assignments.FindByAgent(ctx, agentID)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(held, func(a *entities.RoleAssignment) bool {
+		if a.Scope == entities.RoleScopeGlobal && a.Role == entities.RoleSuperAdmin {
+			return true
+		}
+		return accountID != "" && a.Scope == entities.RoleScopeAccount && a.AccountID == accountID &&
+			a.Role == entities.RoleSiteAdmin
+	}), nil
+}
+
+func (s *roleAssignmentService) Assign(
+	ctx context.Context, cmd AssignRoleCommand,
+) (*entities.RoleAssignment, error) {
+	agentID, accountID, err := s.caller(ctx, cmd.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	a := &entities.RoleAssignment{
+		ID:        identity.NewRoleAssignment(),
+		AgentID:   strings.TrimSpace(cmd.AgentID),
+		Role:      strings.TrimSpace(cmd.Role),
+		Scope:     cmd.Scope,
+		AccountID: accountID,
+		GrantedBy: agentID,
+		GrantedAt: time.Now().UTC(),
+	}
+	if a.Scope == entities.RoleScopeGlobal {
+		a.AccountID = ""
+	}
+	if err := s.validate(ctx, a, cmd); err != nil {
+		return nil, err
+	}
+	ok, err := s.canManage(ctx, agentID, a.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("not allowed to manage %s role assignments: %w", a.Scope, entities.ErrAccessDenied)
+	}
+	if err := s.assignments.Save(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// validate checks a new assignment and fills in its type or resource.
+func (s *roleAssignmentService) validate(
+	ctx context.Context, a *entities.RoleAssignment, cmd AssignRoleCommand,
+) error {
+	if a.AgentID == "" {
+		return fmt.Errorf("agent_id is required: %w", ErrValidation)
+	}
+	if a.Role == "" || a.Role == authentities.RoleOwner {
+		return fmt.Errorf("a role other than %q is required: %w", authentities.RoleOwner, ErrValidation)
+	}
+	if strings.Contains(a.Role, roleSubjectSeparator) {
+		return fmt.Errorf("role %q contains %q, which role names cannot: %w", a.Role, roleSubjectSeparator, ErrValidation)
+	}
+	if _, ok := roleScopeRank[a.Scope]; !ok {
+		return fmt.Errorf("scope must be one of global, account, type, or subtree: %w", ErrValidation)
+	}
+	if a.Scope != entities.RoleScopeGlobal && a.AccountID == "" {
+		return fmt.Errorf("a %s scope requires an account: %w", a.Scope, ErrValidation)
+	}
+	switch {
+	case a.Role == entities.RoleSuperAdmin && a.Scope != entities.RoleScopeGlobal:
+		return fmt.Errorf("role %q can only be assigned globally: %w", a.Role, ErrValidation)
+	case a.Role == entities.RoleSiteAdmin && a.Scope != entities.RoleScopeAccount:
+		return fmt.Errorf("role %q can only be assigned to an account: %w", a.Role, ErrValidation)
+	}
+	switch a.Scope {
+	case entities.RoleScopeType:
+		if cmd.TypeSlug == "" {
+			return fmt.Errorf("a type scope requires type_slug: %w", ErrValidation)
+		}
+		if _, err := s.types.FindBySlug(ctx, cmd.TypeSlug); err != nil {
+			return fmt.Errorf("resource type %q not found: %w", cmd.TypeSlug, ErrValidation)
+		}
+		a.TypeSlug = cmd.TypeSlug
+	case entities.RoleScopeSubtree:
+		if cmd.ResourceID == "" {
+			return fmt.Errorf("a subtree scope requires resource_id: %w", ErrValidation)
+		}
+		root, err := s.resources.FindByID(ctx, cmd.ResourceID)
+		if err != nil || root.AccountID() != a.AccountID {
+			return fmt.Errorf("resource %q not found in the account: %w", cmd.ResourceID, ErrValidation)
+		}
+		a.ResourceID = cmd.ResourceID
+	}
+	return nil
+}
+
+// Revoke reports another account's assignment as not found.
+func (s *roleAssignmentService) Revoke(ctx context.Context, id string) error {
+	agentID, accountID, err := s.caller(ctx, "")
+	if err != nil {
+		return err
+	}
+	a, err := s.assignments.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if a.Scope != entities.RoleScopeGlobal && agentID != "" && a.AccountID != accountID {
+		return fmt.Errorf("role assignment %q: %w", id, repositories.ErrNotFound)
+	}
+	ok, err := s.canManage(ctx, agentID, a.AccountID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("not allowed to manage %s role assignments: %w", a.Scope, entities.ErrAccessDenied)
+	}
+	return s.assignments.Delete(ctx, id)
+}
+
+func (s *roleAssignmentService) List(ctx context.Context, agentID string) ([]*entities.RoleAssignment, error) {
+	callerID, accountID, err := s.caller(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.canManage(ctx, callerID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("not allowed to list role assignments: %w", entities.ErrAccessDenied)
+	}
+	if agentID == "" {
+		return s.assignments.FindByAccount(ctx, accountID)
+	}
+	held, err := s.assignments.FindByAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(held, func(a *entities.RoleAssignment) bool {
+		return a.Scope != entities.RoleScopeGlobal && callerID != "" && a.AccountID != accountID
+	}), nil
+}
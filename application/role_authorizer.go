@@ -0,0 +1,328 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	authapp "github.com/akeemphilbert/pericarp/pkg/auth/application"
+	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
+	authcasbin "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/casbin"
+	"go.uber.org/fx"
+)
+
+// roleScopeRank orders the role assignment scopes from the broadest to the
+// most specific.
+var roleScopeRank = map[string]int{
+	entities.RoleScopeGlobal:  0,
+	entities.RoleScopeAccount: 1,
+	entities.RoleScopeType:    2,
+	entities.RoleScopeSubtree: 3,
+}
+
+// AuthorizationRequest asks whether an agent may take an action on a
+// resource type, or on one resource of it when ResourceID is set.
+type AuthorizationRequest struct {
+	AgentID string
+	// AccountID is the account the request is made in: the active account,
+	// or the account of the resource acted on.
+	AccountID string
+	// MemberRole is the agent's role in AccountID. It decides when none of
+	// the agent's role assignments apply.
+	MemberRole string
+	// Action is an ODRL action, as stored in Casbin policies.
+	Action     string
+	TypeSlug   string
+	ResourceID string
+}
+
+// AuthorizationDecision is the outcome of an AuthorizationRequest.
+type AuthorizationDecision struct {
+	Allowed bool
+	// Role and Scope name the assignment that decided. Scope is empty when
+	// the agent's account role decided, and Role too when nothing did.
+	Role  string
+	Scope string
+	// AnyResource reports that the deciding roles act on resources other
+	// agents created, not only the agent's own: editors and above do, and
+	// viewers and above for reads.
+	AnyResource bool
+	Reason      string
+	// Policy is the Casbin policy that decided, as "role, action, target":
+	// the grant when allowed, or the explicit deny. It is empty when no
+	// policy grants the action.
+	Policy string
+	// Unconfigured reports, on a denial by the account role, that the role
+	// has no policies at all in the account: its access has not been set up
+	// yet.
+	Unconfigured bool
+}
+
+// RoleAuthorizer decides requests from an agent's role assignments on top
+// of the Casbin policies of their roles, as configured for the account of
+// the request (see AccountRoleSubject). Only the assignments whose scope
+// covers the request apply, and of those only the most specific scope
+// decides; every role assigned at that scope must allow the action. An
+// explicit deny on any applying role or on the agent's account role wins,
+// and with no applying assignment and no account role the request is
+// denied.
+type RoleAuthorizer interface {
+	Authorize(ctx context.Context, req AuthorizationRequest) (*AuthorizationDecision, error)
+}
+
+type roleAuthorizer struct {
+	assignments repositories.RoleAssignmentRepository
+	checker     *authcasbin.CasbinAuthorizationChecker
+	access      repositories.RoleResourceAccessRepository // nil: built-in inheritance only
+	inherit     *permissionInheritance
+}
+
+func ProvideRoleAuthorizer(params struct {
+	fx.In
+	Assignments repositories.RoleAssignmentRepository
+	Checker     *authcasbin.CasbinAuthorizationChecker
+	RoleAccess  repositories.RoleResourceAccessRepository `optional:"true"`
+	TypeRepo    repositories.ResourceTypeRepository
+	TripleRepo  repositories.TripleRepository
+	Resources   repositories.ResourceRepository
+}) RoleAuthorizer {
+	return &roleAuthorizer{
+		assignments: params.Assignments,
+		checker:     params.Checker,
+		access:      params.RoleAccess,
+		inherit: &permissionInheritance{
+			types:     params.TypeRepo,
+			triples:   params.TripleRepo,
+			resources: params.Resources,
+		},
+	}
+}
+
+func (r *roleAuthorizer) Authorize(ctx context.Context, req AuthorizationRequest) (*AuthorizationDecision, error) {
+	applying, err := r.applying(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// The account role's deny policies apply even when an assignment
+	// decides, so no assignment lifts a deny the agent's membership carries
+	// (SCOPE-02).
+	denying := applying
+	if req.MemberRole != "" {
+		denying = append(slices.Clone(applying), &entities.RoleAssignment{Role: req.MemberRole})
+	}
+	if len(applying) == 0 {
+		if req.MemberRole == "" {
+			return &AuthorizationDecision{Reason: "no role assignment applies"}, nil
+		}
+		applying = denying
+	}
+	subject, err := r.subjects(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range denying {
+		deny, err := r.matching(ctx, subject(a.Role), req.Action, req.TypeSlug, r.checker.GetProhibitions)
+		if err != nil {
+			return nil, err
+		}
+		if deny != "" {
+			return &AuthorizationDecision{
+				Role: a.Role, Scope: a.Scope, Policy: deny,
+				Reason: fmt.Sprintf("%s is denied %s on %q", describeRole(a), req.Action, req.TypeSlug),
+			}, nil
+		}
+	}
+
+	deciding := mostSpecific(applying)
+	for _, a := range deciding {
+		ok, err := r.checker.IsAuthorized(ctx, subject(a.Role), req.Action, req.TypeSlug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check role %q: %w", a.Role, err)
+		}
+		if !ok {
+			decision := &AuthorizationDecision{
+				Role: a.Role, Scope: a.Scope,
+				Reason: fmt.Sprintf("%s does not grant %s on %q", describeRole(a), req.Action, req.TypeSlug),
+			}
+			if a.Scope == "" {
+				decision.Unconfigured, err = r.unconfigured(ctx, subject(a.Role))
+			}
+			return decision, err
+		}
+	}
+	decision := &AuthorizationDecision{
+		Allowed: true,
+		Role:    deciding[0].Role,
+		Scope:   deciding[0].Scope,
+		Reason:  fmt.Sprintf("%s grants %s on %q", describeRole(deciding[0]), req.Action, req.TypeSlug),
+	}
+	decision.Policy, err = r.matching(ctx, subject(decision.Role), req.Action, req.TypeSlug, r.checker.GetPermissions)
+	if err != nil {
+		return nil, err
+	}
+	if decision.Scope != "" {
+		decision.AnyResource, err = r.anyResource(ctx, req.AccountID, deciding, req.Action)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return decision, nil
+}
+
+// applying returns the agent's assignments whose scope covers req.
+func (r *roleAuthorizer) applying(
+	ctx context.Context, req AuthorizationRequest,
+) ([]*entities.RoleAssignment, error) {
+	if req.AgentID == "" {
+		return nil, nil
+	}
+	all, err := r.assignments.FindByAgent(ctx, req.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	var roots map[string]bool
+	var out []*entities.RoleAssignment
+	for _, a := range all {
+		if a.Scope != entities.RoleScopeGlobal && (req.AccountID == "" || a.AccountID != req.AccountID) {
+			continue
+		}
+		switch a.Scope {
+		case entities.RoleScopeGlobal, entities.RoleScopeAccount:
+		case entities.RoleScopeType:
+			if a.TypeSlug != req.TypeSlug {
+				continue
+			}
+		case entities.RoleScopeSubtree:
+			if req.ResourceID == "" {
+				continue
+			}
+			if roots == nil {
+				roots = r.subtreeRoots(ctx, req.ResourceID, req.TypeSlug)
+			}
+			if !roots[a.ResourceID] {
+				continue
+			}
+		default:
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// subtreeRoots returns the resource id and the resources it inherits
+// permissions from, whose subtree it is in.
+func (r *roleAuthorizer) subtreeRoots(ctx context.Context, id, typeSlug string) map[string]bool {
+	roots := map[string]bool{id: true}
+	if r.inherit != nil {
+		r.inherit.allows(ctx, id, typeSlug, func(parent *entities.Resource) bool {
+			roots[parent.GetID()] = true
+			return false
+		})
+	}
+	return roots
+}
+
+// subjects returns the Casbin subject of a role in accountID: qualified by
+// the account when it has a role-access configuration of its own, and the
+// role itself when it uses the global default.
+func (r *roleAuthorizer) subjects(ctx context.Context, accountID string) (func(role string) string, error) {
+	if r.access == nil || accountID == "" {
+		return func(role string) string { return role }, nil
+	}
+	settings, err := r.access.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load role access of account %q: %w", accountID, err)
+	}
+	if settings.AccountID != accountID {
+		return func(role string) string { return role }, nil
+	}
+	return func(role string) string { return AccountRoleSubject(accountID, role) }, nil
+}
+
+// unconfigured reports whether the role subject has no policies at all,
+// neither grants nor denies.
+func (r *roleAuthorizer) unconfigured(ctx context.Context, subject string) (bool, error) {
+	perms, err := r.checker.GetPermissions(ctx, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to load permissions of role %q: %w", subject, err)
+	}
+	prohibitions, err := r.checker.GetProhibitions(ctx, subject)
+	if err != nil {
+		return false, fmt.Errorf("failed to load prohibitions of role %q: %w", subject, err)
+	}
+	return len(perms) == 0 && len(prohibitions) == 0, nil
+}
+
+// matching returns the first of role's policies, including those of the
+// roles it inherits, for action on typeSlug, or "" when there is none.
+// policies is the checker's GetPermissions or GetProhibitions.
+func (r *roleAuthorizer) matching(
+	ctx context.Context, role, action, typeSlug string,
+	policies func(ctx context.Context, role string) ([]authapp.Permission, error),
+) (string, error) {
+	found, err := policies(ctx, role)
+	if err != nil {
+		return "", fmt.Errorf("failed to load policies of role %q: %w", role, err)
+	}
+	for _, p := range found {
+		if p.Action == action && (p.Target == typeSlug || p.Target == "*") {
+			return fmt.Sprintf("%s, %s, %s", p.Assignee, p.Action, p.Target), nil
+		}
+	}
+	return "", nil
+}
+
+// anyResource reports whether every deciding role includes editor, or
+// viewer for reads.
+func (r *roleAuthorizer) anyResource(
+	ctx context.Context, accountID string, deciding []*entities.RoleAssignment, action string,
+) (bool, error) {
+	var custom entities.RoleHierarchy
+	if r.access != nil {
+		var err error
+		if custom, err = r.access.GetRoleHierarchy(ctx, accountID); err != nil {
+			return false, err
+		}
+	}
+	h := roleHierarchy(custom)
+	target := entities.RoleEditor
+	if action == authentities.ActionRead {
+		target = entities.RoleViewer
+	}
+	for _, a := range deciding {
+		if a.Role == authentities.RoleAdmin || a.Role == authentities.RoleOwner {
+			continue
+		}
+		if !roleIncludes(h, a.Role, target) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// mostSpecific returns the assignments at the most specific scope present.
+func mostSpecific(assignments []*entities.RoleAssignment) []*entities.RoleAssignment {
+	top := -1
+	for _, a := range assignments {
+		top = max(top, roleScopeRank[a.Scope])
+	}
+	var out []*entities.RoleAssignment
+	for _, a := range assignments {
+		if roleScopeRank[a.Scope] == top {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func describeRole(a *entities.RoleAssignment) string {
+	if a.Scope == "" {
+		return fmt.Sprintf("account role %q", a.Role)
+	}
+	return fmt.Sprintf("role %q (%s scope)", a.Role, a.Scope)
+}
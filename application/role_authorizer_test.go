@@ -0,0 +1,316 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
+	authcasbin "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/casbin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type memRoleAssignments []*entities.RoleAssignment
+
+func (m memRoleAssignments) Save(context.Context, *entities.RoleAssignment) error { return nil }
+func (m memRoleAssignments) FindByID(context.Context, string) (*entities.RoleAssignment, error) {
+	return nil, repositories.ErrNotFound
+}
+func (m memRoleAssignments) FindByAgent(_ context.Context, agentID string) ([]*entities.RoleAssignment, error) {
+	var out []*entities.RoleAssignment
+	for _, a := range m {
+		if a.AgentID == agentID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+func (m memRoleAssignments) FindByAccount(context.Context, string) ([]*entities.RoleAssignment, error) {
+	return nil, nil
+}
+func (m memRoleAssignments) Delete(context.Context, string) error { return nil }
+func (m memRoleAssignments) DeleteByType(context.Context, string) (int64, error) {
+	return 0, nil
+}
+func (m memRoleAssignments) DeleteByResource(context.Context, string) (int64, error) {
+	return 0, nil
+}
+
+// roleAccessConfig is one account's configuration in memRoleAccess.
+type roleAccessConfig struct {
+	access, deny entities.AccessMap
+	inherits     entities.RoleHierarchy
+}
+
+// memRoleAccess holds role access configurations by account, "" being the
+// global default.
+type memRoleAccess map[string]roleAccessConfig
+
+func (m memRoleAccess) config(accountID string) (string, roleAccessConfig) {
+	if c, ok := m[accountID]; ok {
+		return accountID, c
+	}
+	return "", m[""]
+}
+func (m memRoleAccess) Get(_ context.Context, accountID string) (*models.RoleResourceAccess, error) {
+	owner, _ := m.config(accountID)
+	return &models.RoleResourceAccess{AccountID: owner}, nil
+}
+func (m memRoleAccess) Save(context.Context, string, *models.RoleResourceAccess) error { return nil }
+func (m memRoleAccess) Accounts(context.Context) ([]string, error) {
+	var out []string
+	for id := range m {
+		if id != "" {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+func (m memRoleAccess) GetAccessMap(_ context.Context, accountID string) (entities.AccessMap, error) {
+	_, c := m.config(accountID)
+	return c.access, nil
+}
+func (m memRoleAccess) GetFieldAccessMap(context.Context, string) (entities.FieldAccessMap, error) {
+	return nil, nil
+}
+func (m memRoleAccess) GetDenyMap(_ context.Context, accountID string) (entities.AccessMap, error) {
+	_, c := m.config(accountID)
+	return c.deny, nil
+}
+func (m memRoleAccess) GetRoleHierarchy(_ context.Context, accountID string) (entities.RoleHierarchy, error) {
+	_, c := m.config(accountID)
+	return c.inherits, nil
+}
+
+func newTestChecker(t *testing.T) *authcasbin.CasbinAuthorizationChecker {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker, err := ProvideAuthorizationChecker(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SeedAdminPolicies(checker); err != nil {
+		t.Fatal(err)
+	}
+	return checker
+}
+
+func TestRoleAuthorizer(t *testing.T) {
+	ctx := context.Background()
+	checker := newTestChecker(t)
+	if err := SyncAccessMapToCasbin(checker, "", entities.AccessMap{
+		"member":   {"project": {"read"}},
+		"reviewer": {"task": {"read"}},
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := SyncRoleHierarchyToCasbin(checker, "", entities.RoleHierarchy{"reviewer": {entities.RoleAuthor}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := SyncDenyMapToCasbin(checker, "", entities.AccessMap{
+		"reviewer": {"invoice": {"read"}},
+		"member":   {"invoice": {"delete"}},
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	assign := func(agent, role, scope, account, typeSlug string) *entities.RoleAssignment {
+		return &entities.RoleAssignment{
+			AgentID: agent, Role: role, Scope: scope, AccountID: account, TypeSlug: typeSlug,
+		}
+	}
+	authz := &roleAuthorizer{
+		assignments: memRoleAssignments{
+			assign("super", entities.RoleSuperAdmin, entities.RoleScopeGlobal, "", ""),
+			assign("site", entities.RoleSiteAdmin, entities.RoleScopeAccount, "acct-1", ""),
+			assign("editor", entities.RoleEditor, entities.RoleScopeAccount, "acct-1", ""),
+			assign("author", entities.RoleAuthor, entities.RoleScopeAccount, "acct-1", ""),
+			assign("viewer", entities.RoleViewer, entities.RoleScopeAccount, "acct-1", ""),
+			assign("mixed", entities.RoleEditor, entities.RoleScopeGlobal, "", ""),
+			assign("mixed", entities.RoleViewer, entities.RoleScopeType, "acct-1", "project"),
+			assign("both", entities.RoleEditor, entities.RoleScopeAccount, "acct-1", ""),
+			assign("both", entities.RoleViewer, entities.RoleScopeAccount, "acct-1", ""),
+			assign("reviewer", "reviewer", entities.RoleScopeAccount, "acct-1", ""),
+			assign("reviewer", entities.RoleEditor, entities.RoleScopeGlobal, "", ""),
+			assign("invoicer", entities.RoleEditor, entities.RoleScopeType, "acct-1", "invoice"),
+			{AgentID: "section", Role: entities.RoleEditor, Scope: entities.RoleScopeSubtree,
+				AccountID: "acct-1", ResourceID: "urn:project:p1"},
+		},
+		checker: checker,
+	}
+
+	read, modify, del := authentities.ActionRead, authentities.ActionModify, authentities.ActionDelete
+	cases := []struct {
+		name                 string
+		req                  AuthorizationRequest
+		allowed, anyResource bool
+	}{
+		{"ROLE-01 super admin acts on every account",
+			AuthorizationRequest{AgentID: "super", AccountID: "acct-2", Action: del, TypeSlug: "project"}, true, true},
+		{"ROLE-02 site admin acts on their account",
+			AuthorizationRequest{AgentID: "site", AccountID: "acct-1", Action: del, TypeSlug: "project"}, true, true},
+		{"ROLE-02 site admin is limited to their account",
+			AuthorizationRequest{AgentID: "site", AccountID: "acct-2", Action: read, TypeSlug: "project"}, false, false},
+		{"ROLE-03 editor modifies any resource",
+			AuthorizationRequest{AgentID: "editor", AccountID: "acct-1", Action: modify, TypeSlug: "task"}, true, true},
+		{"ROLE-04 author modifies only their own resources",
+			AuthorizationRequest{AgentID: "author", AccountID: "acct-1", Action: modify, TypeSlug: "task"}, true, false},
+		{"ROLE-05 viewer reads any resource",
+			AuthorizationRequest{AgentID: "viewer", AccountID: "acct-1", Action: read, TypeSlug: "task"}, true, true},
+		{"ROLE-05 viewer cannot modify",
+			AuthorizationRequest{AgentID: "viewer", AccountID: "acct-1", Action: modify, TypeSlug: "task"}, false, false},
+		{"ROLE-07 author inherits viewer",
+			AuthorizationRequest{AgentID: "author", AccountID: "acct-1", Action: read, TypeSlug: "task"}, true, true},
+		{"ROLE-07 custom role inherits author",
+			AuthorizationRequest{AgentID: "reviewer", AccountID: "acct-1", Action: modify, TypeSlug: "project"},
+			true, false},
+		{"ROLE-09 ROLE-11 type-scoped role applies to its type",
+			AuthorizationRequest{AgentID: "mixed", AccountID: "acct-1", Action: read, TypeSlug: "project"}, true, true},
+		{"ROLE-11 SCOPE-01 global role applies outside the type scope",
+			AuthorizationRequest{AgentID: "mixed", AccountID: "acct-1", Action: modify, TypeSlug: "task"}, true, true},
+		{"SCOPE-01 type scope decides over the global role",
+			AuthorizationRequest{AgentID: "mixed", AccountID: "acct-1", Action: modify, TypeSlug: "project"},
+			false, false},
+		{"ROLE-12 most restrictive role at the deciding scope",
+			AuthorizationRequest{AgentID: "both", AccountID: "acct-1", Action: modify, TypeSlug: "task"}, false, false},
+		{"SCOPE-02 explicit deny wins over the global editor",
+			AuthorizationRequest{AgentID: "reviewer", AccountID: "acct-1", Action: read, TypeSlug: "invoice"},
+			false, false},
+		{"ROLE-12 SCOPE-02 account role deny wins over a type-scoped editor",
+			AuthorizationRequest{AgentID: "invoicer", AccountID: "acct-1", MemberRole: "member", Action: del,
+				TypeSlug: "invoice"}, false, false},
+		{"ROLE-12 type-scoped editor grants what the account role does not deny",
+			AuthorizationRequest{AgentID: "invoicer", AccountID: "acct-1", MemberRole: "member", Action: modify,
+				TypeSlug: "invoice"}, true, true},
+		{"ROLE-10 (a) global scope",
+			AuthorizationRequest{AgentID: "mixed", AccountID: "acct-2", Action: modify, TypeSlug: "project"}, true, true},
+		{"ROLE-10 (b) account scope",
+			AuthorizationRequest{AgentID: "editor", AccountID: "acct-1", Action: modify, TypeSlug: "project"}, true, true},
+		{"ROLE-10 (b) account scope stops at its account",
+			AuthorizationRequest{AgentID: "editor", AccountID: "acct-2", Action: read, TypeSlug: "project"}, false, false},
+		{"ROLE-10 (c) type scope",
+			AuthorizationRequest{AgentID: "invoicer", AccountID: "acct-1", Action: modify, TypeSlug: "invoice"},
+			true, true},
+		{"ROLE-10 (c) type scope stops at its type",
+			AuthorizationRequest{AgentID: "invoicer", AccountID: "acct-1", Action: read, TypeSlug: "task"}, false, false},
+		{"ROLE-10 (d) subtree scope",
+			AuthorizationRequest{AgentID: "section", AccountID: "acct-1", Action: modify, TypeSlug: "project",
+				ResourceID: "urn:project:p1"}, true, true},
+		{"ROLE-10 (d) subtree scope stops at its subtree",
+			AuthorizationRequest{AgentID: "section", AccountID: "acct-1", Action: modify, TypeSlug: "project",
+				ResourceID: "urn:project:p2"}, false, false},
+		{"SCOPE-03 no assignment and no account role denies",
+			AuthorizationRequest{AgentID: "nobody", AccountID: "acct-1", Action: read, TypeSlug: "task"}, false, false},
+		{"account role decides without assignments",
+			AuthorizationRequest{AgentID: "nobody", AccountID: "acct-1", MemberRole: "member", Action: read,
+				TypeSlug: "project"}, true, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision, err := authz.Authorize(ctx, tc.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decision.Allowed != tc.allowed || decision.AnyResource != tc.anyResource {
+				t.Errorf("allowed=%v anyResource=%v (%s), want %v %v",
+					decision.Allowed, decision.AnyResource, decision.Reason, tc.allowed, tc.anyResource)
+			}
+		})
+	}
+}
+
+func TestRoleAuthorizer_PerAccountAccess(t *testing.T) {
+	ctx := context.Background()
+	checker := newTestChecker(t)
+	access := memRoleAccess{
+		"": {access: entities.AccessMap{"member": {"project": {"read"}}}},
+		"acct-own": {
+			access:   entities.AccessMap{"member": {"task": {"read"}}},
+			deny:     entities.AccessMap{"lead": {"invoice": {"read"}}},
+			inherits: entities.RoleHierarchy{"lead": {entities.RoleEditor}},
+		},
+	}
+	if err := LoadRoleAccessIntoCasbin(ctx, checker, access); err != nil {
+		t.Fatal(err)
+	}
+	authz := &roleAuthorizer{assignments: memRoleAssignments{}, checker: checker, access: access}
+
+	read, modify := authentities.ActionRead, authentities.ActionModify
+	cases := []struct {
+		name    string
+		req     AuthorizationRequest
+		allowed bool
+	}{
+		{"account configuration grants its own rules",
+			AuthorizationRequest{AccountID: "acct-own", MemberRole: "member", Action: read, TypeSlug: "task"}, true},
+		{"account configuration replaces the global default",
+			AuthorizationRequest{AccountID: "acct-own", MemberRole: "member", Action: read, TypeSlug: "project"}, false},
+		{"account without configuration uses the global default",
+			AuthorizationRequest{AccountID: "acct-other", MemberRole: "member", Action: read, TypeSlug: "project"}, true},
+		{"other accounts' rules do not leak",
+			AuthorizationRequest{AccountID: "acct-other", MemberRole: "member", Action: read, TypeSlug: "task"}, false},
+		{"account inheritance applies in the account",
+			AuthorizationRequest{AccountID: "acct-own", MemberRole: "lead", Action: modify, TypeSlug: "task"}, true},
+		{"account deny applies in the account",
+			AuthorizationRequest{AccountID: "acct-own", MemberRole: "lead", Action: read, TypeSlug: "invoice"}, false},
+		{"account inheritance stays in the account",
+			AuthorizationRequest{AccountID: "acct-other", MemberRole: "lead", Action: modify, TypeSlug: "task"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision, err := authz.Authorize(ctx, tc.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decision.Allowed != tc.allowed {
+				t.Errorf("allowed=%v (%s), want %v", decision.Allowed, decision.Reason, tc.allowed)
+			}
+		})
+	}
+}
+
+func TestValidateRoleDefinitions(t *testing.T) {
+	cases := []struct {
+		name     string
+		access   entities.AccessMap
+		deny     entities.AccessMap
+		inherits entities.RoleHierarchy
+		wantErr  bool
+	}{
+		{"custom roles may inherit built-in ones", nil, nil,
+			entities.RoleHierarchy{"reviewer": {entities.RoleViewer}, "lead": {"reviewer", entities.RoleAuthor}}, false},
+		{"custom roles may share a built-in role's short name", entities.AccessMap{"editor": {"task": {"read"}}},
+			nil, entities.RoleHierarchy{"viewer": {entities.RoleViewer}}, false},
+		{"ROLE-06 built-in access cannot change", entities.AccessMap{entities.RoleEditor: {"task": {"read"}}},
+			nil, nil, true},
+		{"ROLE-06 built-in deny cannot change", nil, entities.AccessMap{entities.RoleViewer: {"task": {"read"}}},
+			nil, true},
+		{"ROLE-06 built-in inheritance cannot change", nil, nil,
+			entities.RoleHierarchy{entities.RoleViewer: {"reviewer"}}, true},
+		{"built-in prefix is reserved", entities.AccessMap{"weos:reviewer": {"task": {"read"}}}, nil, nil, true},
+		{"role names cannot contain #", entities.AccessMap{"acct#reviewer": {"task": {"read"}}}, nil, nil, true},
+		{"inherited role names cannot contain #", nil, nil,
+			entities.RoleHierarchy{"lead": {"acct#reviewer"}}, true},
+		{"ROLE-08 self inheritance", nil, nil, entities.RoleHierarchy{"a": {"a"}}, true},
+		{"ROLE-08 longer cycle", nil, nil, entities.RoleHierarchy{"a": {"b"}, "b": {"c"}, "c": {"a"}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRoleDefinitions(tc.access, tc.deny, nil, tc.inherits)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("err = %v, want ErrValidation", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// validateSchemaAnnotations checks the weos-specific x-* annotations of a
+// resource type schema when the type is created or updated, so a bad
+// annotation is rejected up front instead of failing on the first write.
+// Plain JSON Schema keywords are left to the jsonschema compiler.
+func validateSchemaAnnotations(schema json.RawMessage) error {
+	return errors.Join(
+		validateOnDeletePolicies(schema),
+		validateComputedProperties(schema),
+		validateRuleAnnotations(schema),
+		validateWorkflow(schema),
+		validateEditorial(schema),
+		validatePIIAnnotations(schema),
+		validateEncryptedProperties(schema),
+		validateInheritance(schema),
+	)
+}
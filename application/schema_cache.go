@@ -0,0 +1,95 @@
+package application
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// schemaCacheSize bounds each compiled-annotation cache. It holds one entry
+// per resource type, so the bound only matters for installations with more
+// types than this.
+const schemaCacheSize = 512
+
+// schemaCache holds one kind of compiled schema annotation (x-workflow,
+// x-rules, ...) per resource type. An entry is keyed by the type's slug and
+// stays valid for one version of its schema: the type's sequence number
+// together with a digest of its schema and context, so a type that hasn't
+// been saved yet can't be served another one's entry. Updating a type
+// replaces its entry, and the least recently used type is evicted once the
+// cache holds schemaCacheSize of them. Compile errors are not cached.
+type schemaCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+}
+
+type schemaCacheEntry[T any] struct {
+	slug    string
+	version int
+	digest  [sha256.Size]byte
+	value   T
+}
+
+// get returns the compiled annotation of rt, running compile on a miss.
+func (c *schemaCache[T]) get(
+	rt *entities.ResourceType, compile func(*entities.ResourceType) (T, error),
+) (T, error) {
+	slug, version, digest := rt.Slug(), rt.GetSequenceNo(), schemaDigest(rt)
+	c.mu.Lock()
+	if el, ok := c.entries[slug]; ok {
+		e := el.Value.(*schemaCacheEntry[T])
+		if e.version == version && e.digest == digest {
+			c.order.MoveToFront(el)
+			c.mu.Unlock()
+			return e.value, nil
+		}
+	}
+	c.mu.Unlock()
+
+	// Compile outside the lock; two callers racing on a miss compile the
+	// same result and the later one is kept.
+	value, err := compile(rt)
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.order = list.New()
+	}
+	entry := &schemaCacheEntry[T]{slug: slug, version: version, digest: digest, value: value}
+	if el, ok := c.entries[slug]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return value, nil
+	}
+	c.entries[slug] = c.order.PushFront(entry)
+	for c.order.Len() > schemaCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*schemaCacheEntry[T]).slug)
+	}
+	return value, nil
+}
+
+// len returns the number of cached types.
+func (c *schemaCache[T]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func schemaDigest(rt *entities.ResourceType) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(rt.Schema())
+	h.Write([]byte{0})
+	h.Write(rt.Context())
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+func TestSchemaCache_KeyedByTypeAndVersion(t *testing.T) {
+	t.Parallel()
+	var cache schemaCache[string]
+	compiles := 0
+	compile := func(rt *entities.ResourceType) (string, error) {
+		compiles++
+		return string(rt.Schema()), nil
+	}
+
+	v1 := makeSchemaRT(t, "ticket", `{"title":"v1"}`)
+	for range 3 {
+		if got, _ := cache.get(v1, compile); got != `{"title":"v1"}` {
+			t.Fatalf("get = %q", got)
+		}
+	}
+	if compiles != 1 {
+		t.Errorf("compiled %d times, want once", compiles)
+	}
+
+	// An updated schema replaces the type's entry.
+	v2 := makeSchemaRT(t, "ticket", `{"title":"v2"}`)
+	if got, _ := cache.get(v2, compile); got != `{"title":"v2"}` {
+		t.Errorf("after update: get = %q", got)
+	}
+	if compiles != 2 || cache.len() != 1 {
+		t.Errorf("compiles = %d, entries = %d, want 2 and 1", compiles, cache.len())
+	}
+
+	// Errors aren't cached.
+	broken := makeSchemaRT(t, "broken", `{}`)
+	fail := func(*entities.ResourceType) (string, error) { return "", ErrValidation }
+	if _, err := cache.get(broken, fail); !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+	if cache.len() != 1 {
+		t.Errorf("a failed compile was cached")
+	}
+}
+
+func TestSchemaCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+	var cache schemaCache[int]
+	compiles := 0
+	compile := func(*entities.ResourceType) (int, error) {
+		compiles++
+		return compiles, nil
+	}
+	first := makeSchemaRT(t, "type-0", `{}`)
+	cache.get(first, compile)
+	for i := 1; i <= schemaCacheSize; i++ {
+		cache.get(makeSchemaRT(t, fmt.Sprintf("type-%d", i), `{}`), compile)
+	}
+	if cache.len() != schemaCacheSize {
+		t.Fatalf("entries = %d, want %d", cache.len(), schemaCacheSize)
+	}
+	before := compiles
+	cache.get(first, compile)
+	if compiles != before+1 {
+		t.Errorf("the least recently used type was not evicted")
+	}
+}
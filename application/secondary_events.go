@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/infrastructure/events"
+)
+
+// secondaryResendJobName resends the events the secondary event store has
+// not acknowledged. It runs every minute while dual-write is enabled.
+const secondaryResendJobName = "events.secondary-resend"
+
+// resendSecondaryEvents sends every due pending event, then logs the
+// backlog that is left so it can be alerted on.
+func resendSecondaryEvents(w *events.SecondaryWriter, logger entities.Logger) JobHandler {
+	return func(ctx context.Context, _ json.RawMessage) error {
+		total := 0
+		for {
+			sent, err := w.Resend(ctx)
+			if err != nil {
+				return err
+			}
+			if sent == 0 {
+				break
+			}
+			total += sent
+		}
+		backlog, err := w.Backlog(ctx)
+		if err != nil {
+			return err
+		}
+		if total > 0 {
+			logger.Info(ctx, "resent events to the secondary event store", "count", total)
+		}
+		if backlog.Count > 0 {
+			logger.Warn(ctx, "secondary event store backlog",
+				"pending", backlog.Count, "oldestAge", time.Since(backlog.Oldest).Round(time.Second).String(),
+				"maxAttempts", backlog.MaxAttempts, "lastError", backlog.LastError)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/internal/config"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"go.uber.org/fx"
+)
+
+// resourceSnapshotVersion versions the replayed resource state. Bump it
+// whenever txResourceState.apply or replayedResource.apply changes, so
+// snapshots taken by the old logic are ignored and retaken.
+const resourceSnapshotVersion = 2
+
+// replayedTriple is one (predicate, object) edge of a subject.
+type replayedTriple struct {
+	Predicate string
+	Object    string
+}
+
+// replayedResource is a resource's state and triples folded from its
+// events. It is what a resource snapshot holds.
+type replayedResource struct {
+	State   txResourceState
+	Triples []replayedTriple
+}
+
+// apply folds one of the resource's stored events into the replayed state.
+func (r *replayedResource) apply(
+	ctx context.Context, id string, e domain.EventEnvelope[any], logger entities.Logger,
+) {
+	r.State.apply(ctx, e, logger)
+	m, ok := e.Payload.(map[string]any)
+	if !ok || m["subject"] != id {
+		return
+	}
+	t := replayedTriple{fmt.Sprint(m["predicate"]), fmt.Sprint(m["object"])}
+	if t.Object == "" {
+		// An erased x-pii reference.
+		return
+	}
+	switch e.EventType {
+	case "Triple.Created":
+		if !slices.Contains(r.Triples, t) {
+			r.Triples = append(r.Triples, t)
+		}
+	case "Triple.Deleted":
+		r.Triples = slices.DeleteFunc(r.Triples, func(x replayedTriple) bool { return x == t })
+	}
+}
+
+// ResourceReplayer folds a resource's events into its state. It starts from
+// the resource's latest snapshot and loads only the events after it, and
+// takes a new snapshot once a replay covers SnapshotEvery events.
+type ResourceReplayer struct {
+	eventStore domain.EventStore
+	snapshots  repositories.SnapshotRepository
+	every      int
+	logger     entities.Logger
+}
+
+func ProvideResourceReplayer(params struct {
+	fx.In
+	EventStore domain.EventStore
+	Snapshots  repositories.SnapshotRepository
+	Config     config.Config
+	Logger     entities.Logger
+}) *ResourceReplayer {
+	return &ResourceReplayer{
+		eventStore: params.EventStore,
+		snapshots:  params.Snapshots,
+		every:      params.Config.SnapshotEvery,
+		logger:     params.Logger,
+	}
+}
+
+// replay returns the resource's state after all of its events.
+func (r *ResourceReplayer) replay(ctx context.Context, id string) (replayedResource, error) {
+	var res replayedResource
+	from := 1
+	if snap := r.latest(ctx, id); snap != nil {
+		if err := json.Unmarshal(snap.State, &res); err != nil {
+			r.logger.Error(ctx, "ignoring unreadable snapshot", "id", id, "error", err)
+			res = replayedResource{}
+		} else {
+			from = snap.SequenceNo + 1
+		}
+	}
+	events, err := r.eventStore.GetEventsFromVersion(ctx, id, from)
+	if err != nil {
+		return res, fmt.Errorf("failed to load events of %s: %w", id, err)
+	}
+	for _, e := range events {
+		res.apply(ctx, id, e, r.logger)
+	}
+	if r.every > 0 && len(events) >= r.every {
+		r.save(ctx, id, res)
+	}
+	return res, nil
+}
+
+// latest returns the resource's usable snapshot, or nil when snapshots are
+// off or it has none. A failed lookup only costs a full replay.
+func (r *ResourceReplayer) latest(ctx context.Context, id string) *entities.AggregateSnapshot {
+	if r.every <= 0 {
+		return nil
+	}
+	snap, err := r.snapshots.Latest(ctx, id, resourceSnapshotVersion)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			r.logger.Error(ctx, "failed to load snapshot", "id", id, "error", err)
+		}
+		return nil
+	}
+	return snap
+}
+
+func (r *ResourceReplayer) save(ctx context.Context, id string, res replayedResource) {
+	state, err := json.Marshal(res)
+	if err == nil {
+		err = r.snapshots.Save(ctx, &entities.AggregateSnapshot{
+			AggregateID:   id,
+			SequenceNo:    res.State.MaxSeq,
+			SchemaVersion: resourceSnapshotVersion,
+			State:         state,
+			CreatedAt:     time.Now().UTC(),
+		})
+	}
+	if err != nil {
+		r.logger.Error(ctx, "failed to save snapshot", "id", id, "error", err)
+	}
+}
+
+// subscribe snapshots a resource once SnapshotEvery events have been
+// committed to it since its latest snapshot.
+func (r *ResourceReplayer) subscribe(d *domain.EventDispatcher) error {
+	return domain.Subscribe(d, "Resource.Published",
+		func(ctx context.Context, env domain.EventEnvelope[entities.ResourcePublished]) error {
+			taken := 0
+			if snap := r.latest(ctx, env.AggregateID); snap != nil {
+				taken = snap.SequenceNo
+			}
+			if env.SequenceNo-taken < r.every {
+				return nil
+			}
+			_, err := r.replay(ctx, env.AggregateID)
+			return err
+		},
+	)
+}
@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
+)
+
+type memSnapshots map[string]*entities.AggregateSnapshot
+
+func (m memSnapshots) Latest(_ context.Context, id string, version int) (*entities.AggregateSnapshot, error) {
+	s, ok := m[id]
+	if !ok || s.SchemaVersion != version {
+		return nil, repositories.ErrNotFound
+	}
+	return s, nil
+}
+
+func (m memSnapshots) Save(_ context.Context, s *entities.AggregateSnapshot) error {
+	m[s.AggregateID] = s
+	return nil
+}
+
+func (m memSnapshots) Delete(_ context.Context, id string) error {
+	delete(m, id)
+	return nil
+}
+
+// fromVersionStore records where each replay started loading events.
+type fromVersionStore struct {
+	*infrastructure.MemoryStore
+	from []int
+}
+
+func (s *fromVersionStore) GetEventsFromVersion(
+	ctx context.Context, id string, from int,
+) ([]domain.EventEnvelope[any], error) {
+	s.from = append(s.from, from)
+	return s.MemoryStore.GetEventsFromVersion(ctx, id, from)
+}
+
+// appendTaskEvents appends events from..to of a task that is created at
+// sequence 1, renamed at every later one, and linked at every fourth.
+func appendTaskEvents(t *testing.T, store domain.EventStore, id string, from, to int) {
+	t.Helper()
+	for seq := from; seq <= to; seq++ {
+		e := domain.EventEnvelope[any]{
+			ID: fmt.Sprintf("%s-%d", id, seq), AggregateID: id, SequenceNo: seq,
+			EventType: "Resource.Updated",
+			Payload:   map[string]any{"Data": map[string]any{"name": fmt.Sprintf("v%d", seq)}},
+		}
+		switch {
+		case seq == 1:
+			e.EventType = "Resource.Created"
+			e.Payload = map[string]any{
+				"TypeSlug": "task", "CreatedBy": "agent", "AccountID": "account",
+				"Timestamp": "2026-05-01T09:00:00Z", "Data": map[string]any{"name": "v1"},
+			}
+		case seq%4 == 0:
+			e.EventType = "Triple.Created"
+			e.Payload = map[string]any{
+				"subject": id, "predicate": "https://schema.org/about", "object": fmt.Sprintf("urn:thing:%d", seq),
+			}
+		}
+		if err := store.Append(context.Background(), id, -1, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResourceReplayer_ResumesFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := &fromVersionStore{MemoryStore: infrastructure.NewMemoryStore()}
+	snapshots := memSnapshots{}
+	replayer := &ResourceReplayer{eventStore: store, snapshots: snapshots, every: 5, logger: noopLogger{}}
+	const id = "urn:task:long-lived"
+
+	appendTaskEvents(t, store, id, 1, 12)
+	if _, err := replayer.replay(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if snap := snapshots[id]; snap == nil || snap.SequenceNo != 12 {
+		t.Fatalf("snapshot = %+v, want one at sequence 12", snap)
+	}
+
+	appendTaskEvents(t, store, id, 13, 15)
+	got, err := replayer.replay(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.from[1] != 13 {
+		t.Errorf("second replay loaded events from %d, want 13", store.from[1])
+	}
+	if snapshots[id].SequenceNo != 12 {
+		t.Error("snapshot retaken after 3 events, want it kept until 5")
+	}
+
+	full := &ResourceReplayer{eventStore: store, snapshots: memSnapshots{}, logger: noopLogger{}}
+	want, err := full.replay(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("replay from snapshot = %s\nfull replay = %s", gotJSON, wantJSON)
+	}
+	if got.State.MaxSeq != 15 || len(got.Triples) != 3 || !sameJSON(got.State.Data, json.RawMessage(`{"name":"v15"}`)) {
+		t.Errorf("replayed state = %+v", got)
+	}
+}
+
+func TestResourceReplayer_IgnoresUnusableSnapshots(t *testing.T) {
+	ctx := context.Background()
+	for name, snap := range map[string]*entities.AggregateSnapshot{
+		"older schema version": {SequenceNo: 6, SchemaVersion: resourceSnapshotVersion - 1, State: []byte(`{}`)},
+		"unreadable state":     {SequenceNo: 6, SchemaVersion: resourceSnapshotVersion, State: []byte(`{`)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := &fromVersionStore{MemoryStore: infrastructure.NewMemoryStore()}
+			const id = "urn:task:stale"
+			snap.AggregateID = id
+			replayer := &ResourceReplayer{
+				eventStore: store, snapshots: memSnapshots{id: snap}, every: 100, logger: noopLogger{},
+			}
+			appendTaskEvents(t, store, id, 1, 7)
+
+			got, err := replayer.replay(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if store.from[0] != 1 || got.State.MaxSeq != 7 || got.State.TypeSlug != "task" {
+				t.Errorf("replayed from %d to %+v, want a full replay", store.from[0], got.State)
+			}
+		})
+	}
+}
+
+func TestResourceReplayer_SnapshotsEveryNEvents(t *testing.T) {
+	ctx := context.Background()
+	store := infrastructure.NewMemoryStore()
+	snapshots := memSnapshots{}
+	replayer := &ResourceReplayer{eventStore: store, snapshots: snapshots, every: 10, logger: noopLogger{}}
+	d := domain.NewEventDispatcher()
+	if err := replayer.subscribe(d); err != nil {
+		t.Fatal(err)
+	}
+	const id = "urn:task:busy"
+	publish := func(seq int) {
+		t.Helper()
+		err := d.Dispatch(ctx, domain.EventEnvelope[any]{
+			AggregateID: id, EventType: "Resource.Published", SequenceNo: seq,
+			Payload: entities.ResourcePublished{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	appendTaskEvents(t, store, id, 1, 9)
+	publish(9)
+	if len(snapshots) != 0 {
+		t.Fatalf("snapshot taken after 9 events, want none before 10")
+	}
+	appendTaskEvents(t, store, id, 10, 14)
+	publish(14)
+	if snap := snapshots[id]; snap == nil || snap.SequenceNo != 14 {
+		t.Fatalf("snapshot = %+v, want one at sequence 14", snap)
+	}
+	appendTaskEvents(t, store, id, 15, 23)
+	publish(23)
+	if snapshots[id].SequenceNo != 14 {
+		t.Errorf("snapshot moved to %d after 9 more events", snapshots[id].SequenceNo)
+	}
+	appendTaskEvents(t, store, id, 24, 24)
+	publish(24)
+	if snapshots[id].SequenceNo != 24 {
+		t.Errorf("snapshot at %d, want it retaken at 24", snapshots[id].SequenceNo)
+	}
+}
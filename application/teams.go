@@ -0,0 +1,215 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/identity"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	authentities "github.com/akeemphilbert/pericarp/pkg/auth/domain/entities"
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+	"go.uber.org/fx"
+)
+
+// CreateTeamCommand creates a team in the caller's active account.
+type CreateTeamCommand struct {
+	Name        string
+	Description string
+}
+
+// UpdateTeamCommand renames a team and replaces its description.
+type UpdateTeamCommand struct {
+	ID          string
+	Name        string
+	Description string
+}
+
+// TeamService manages the active account's teams and their members. Any
+// member of the account may read them; only admins and owners may change
+// them.
+type TeamService interface {
+	Create(ctx context.Context, cmd CreateTeamCommand) (*entities.Team, error)
+	Update(ctx context.Context, cmd UpdateTeamCommand) (*entities.Team, error)
+	GetByID(ctx context.Context, id string) (*entities.Team, error)
+	List(ctx context.Context, cursor string, limit int) (repositories.PaginatedResponse[*entities.Team], error)
+	// Delete removes the team, its members, and every grant made to it.
+	Delete(ctx context.Context, id string) error
+	AddMember(ctx context.Context, teamID, agentID string) (*entities.TeamMember, error)
+	RemoveMember(ctx context.Context, teamID, agentID string) error
+	ListMembers(ctx context.Context, teamID string) ([]*entities.TeamMember, error)
+}
+
+type teamService struct {
+	teams    repositories.TeamRepository
+	accounts authrepos.AccountRepository
+}
+
+func ProvideTeamService(params struct {
+	fx.In
+	Teams    repositories.TeamRepository
+	Accounts authrepos.AccountRepository
+}) TeamService {
+	return &teamService{teams: params.Teams, accounts: params.Accounts}
+}
+
+// teamAccount returns the caller's active account. Teams always belong to
+// one account, so there must be a caller with one.
+func teamAccount(ctx context.Context) (string, *auth.Identity, error) {
+	ident := auth.AgentFromCtx(ctx)
+	if ident == nil || ident.ActiveAccountID == "" {
+		return "", nil, fmt.Errorf("teams require an active account: %w", entities.ErrAccessDenied)
+	}
+	return ident.ActiveAccountID, ident, nil
+}
+
+// canManage requires the caller to be an admin or owner of their active
+// account.
+func (s *teamService) canManage(ctx context.Context) (string, *auth.Identity, error) {
+	accountID, ident, err := teamAccount(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	role, err := s.accounts.FindMemberRole(ctx, accountID, ident.AgentID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find member role: %w", err)
+	}
+	if role != authentities.RoleOwner && role != authentities.RoleAdmin {
+		return "", nil, fmt.Errorf("only admins and owners may manage teams: %w", entities.ErrAccessDenied)
+	}
+	return accountID, ident, nil
+}
+
+func (s *teamService) Create(ctx context.Context, cmd CreateTeamCommand) (*entities.Team, error) {
+	accountID, ident, err := s.canManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		return nil, fmt.Errorf("team name is required: %w", ErrValidation)
+	}
+	now := time.Now().UTC()
+	team := &entities.Team{
+		ID:          identity.NewTeam(),
+		AccountID:   accountID,
+		Name:        name,
+		Description: cmd.Description,
+		CreatedBy:   ident.AgentID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.teams.Save(ctx, team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) Update(ctx context.Context, cmd UpdateTeamCommand) (*entities.Team, error) {
+	if _, _, err := s.canManage(ctx); err != nil {
+		return nil, err
+	}
+	team, err := s.GetByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		return nil, fmt.Errorf("team name is required: %w", ErrValidation)
+	}
+	team.Name, team.Description = name, cmd.Description
+	team.UpdatedAt = time.Now().UTC()
+	if err := s.teams.Update(ctx, team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// GetByID reports another account's team as not found.
+func (s *teamService) GetByID(ctx context.Context, id string) (*entities.Team, error) {
+	accountID, _, err := teamAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.teams.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if team.AccountID != accountID {
+		return nil, fmt.Errorf("team %q: %w", id, repositories.ErrNotFound)
+	}
+	return team, nil
+}
+
+func (s *teamService) List(
+	ctx context.Context, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.Team], error) {
+	accountID, _, err := teamAccount(ctx)
+	if err != nil {
+		return repositories.PaginatedResponse[*entities.Team]{}, err
+	}
+	return s.teams.FindAllByAccount(ctx, accountID, cursor, limit)
+}
+
+func (s *teamService) Delete(ctx context.Context, id string) error {
+	if _, _, err := s.canManage(ctx); err != nil {
+		return err
+	}
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.teams.Delete(ctx, id)
+}
+
+// AddMember adds an agent to a team. The agent must be a member of the
+// team's account; adding an existing member is a no-op.
+func (s *teamService) AddMember(ctx context.Context, teamID, agentID string) (*entities.TeamMember, error) {
+	accountID, ident, err := s.canManage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	if agentID == "" {
+		return nil, fmt.Errorf("agent_id is required: %w", ErrValidation)
+	}
+	role, err := s.accounts.FindMemberRole(ctx, accountID, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find member role: %w", err)
+	}
+	if role == "" {
+		return nil, fmt.Errorf("agent %q is not a member of the account: %w", agentID, ErrValidation)
+	}
+	member := &entities.TeamMember{
+		TeamID:  teamID,
+		AgentID: agentID,
+		AddedBy: ident.AgentID,
+		AddedAt: time.Now().UTC(),
+	}
+	if err := s.teams.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *teamService) RemoveMember(ctx context.Context, teamID, agentID string) error {
+	if _, _, err := s.canManage(ctx); err != nil {
+		return err
+	}
+	if _, err := s.GetByID(ctx, teamID); err != nil {
+		return err
+	}
+	return s.teams.RemoveMember(ctx, teamID, agentID)
+}
+
+func (s *teamService) ListMembers(ctx context.Context, teamID string) ([]*entities.TeamMember, error) {
+	if _, err := s.GetByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return s.teams.FindMembers(ctx, teamID)
+}
@@ -15,6 +15,7 @@ type ReferencePropertyDef struct {
 	PredicateIRI    string // e.g. "https://schema.org/object"
 	TargetType      string // e.g. "invoice"
 	DisplayProperty string // e.g. "name" — property on the target resource shown in lists
+	OnDelete        string // x-on-delete policy applied when the target is deleted ("" = none)
 }
 
 // ExtractReferenceProperties parses a JSON Schema and JSON-LD context to find
@@ -66,6 +67,7 @@ func ExtractReferencePropertiesWithLinks(
 			Properties map[string]struct {
 				XResourceType    string `json:"x-resource-type"`
 				XDisplayProperty string `json:"x-display-property"`
+				XOnDelete        string `json:"x-on-delete"`
 			} `json:"properties"`
 		}
 		if json.Unmarshal(schema, &s) == nil {
@@ -83,6 +85,7 @@ func ExtractReferencePropertiesWithLinks(
 					PredicateIRI:    jsonld.ResolvePredicateIRI(propName, v, cm),
 					TargetType:      prop.XResourceType,
 					DisplayProperty: displayProp,
+					OnDelete:        prop.XOnDelete,
 				})
 				seen[propName] = struct{}{}
 			}
@@ -111,6 +114,7 @@ func ExtractReferencePropertiesWithLinks(
 			PredicateIRI:    predicateIRI,
 			TargetType:      link.TargetType,
 			DisplayProperty: displayProp,
+			OnDelete:        link.OnDelete,
 		})
 		seen[link.PropertyName] = struct{}{}
 	}
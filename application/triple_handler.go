@@ -23,9 +23,17 @@ func subscribeTripleHandlers(
 	if err := domain.Subscribe(d, "Triple.Created",
 		func(ctx context.Context, env domain.EventEnvelope[entities.TripleCreated]) error {
 			p := env.Payload
+			if p.Object == "" {
+				// An erased x-pii reference leaves no triple.
+				return nil
+			}
 			logger.Info(ctx, "projecting Triple.Created",
 				"subject", p.Subject, "predicate", p.Predicate, "object", p.Object)
-			return tripleRepo.SaveTriple(ctx, p.Subject, p.Predicate, p.Object)
+			if err := tripleRepo.SaveTriple(ctx, p.Subject, p.Predicate, p.Object); err != nil {
+				return err
+			}
+			inheritedAncestors.forget(p.Subject)
+			return nil
 		},
 	); err != nil {
 		return fmt.Errorf("triple created handler: %w", err)
@@ -35,9 +43,16 @@ func subscribeTripleHandlers(
 	if err := domain.Subscribe(d, "Triple.Deleted",
 		func(ctx context.Context, env domain.EventEnvelope[entities.TripleDeleted]) error {
 			p := env.Payload
+			if p.Object == "" {
+				return nil
+			}
 			logger.Info(ctx, "projecting Triple.Deleted",
 				"subject", p.Subject, "predicate", p.Predicate, "object", p.Object)
-			return tripleRepo.DeleteTriple(ctx, p.Subject, p.Predicate, p.Object)
+			if err := tripleRepo.DeleteTriple(ctx, p.Subject, p.Predicate, p.Object); err != nil {
+				return err
+			}
+			inheritedAncestors.forget(p.Subject)
+			return nil
 		},
 	); err != nil {
 		return fmt.Errorf("triple deleted handler: %w", err)
@@ -48,7 +63,8 @@ func subscribeTripleHandlers(
 
 // propagateDisplayValues updates _display columns in all projection tables that reference
 // the updated resource. Uses the reverse-reference index to find affected types and
-// performs a bulk SQL update per referencing type.
+// performs a bulk SQL update per referencing type, plus one per join table of a
+// multi-valued reference pointing at the resource's type.
 func propagateDisplayValues(
 	ctx context.Context,
 	resourceID string,
@@ -62,7 +78,8 @@ func propagateDisplayValues(
 	}
 
 	reverseRefs := projMgr.ReverseReferences(typeSlug)
-	if len(reverseRefs) == 0 {
+	joinRefs := projMgr.ReverseJoinReferences(typeSlug)
+	if len(reverseRefs) == 0 && len(joinRefs) == 0 {
 		return nil
 	}
 
@@ -86,6 +103,16 @@ func propagateDisplayValues(
 				"referencingType", ref.ReferencingTypeSlug, "column", ref.DisplayColumn, "error", err)
 		}
 	}
+	for _, ref := range joinRefs {
+		val, ok := node[ref.DisplayProperty]
+		if !ok {
+			continue
+		}
+		if err := projMgr.UpdateJoinDisplay(ctx, ref, resourceID, fmt.Sprint(val)); err != nil {
+			logger.Error(ctx, "failed to propagate display value",
+				"joinTable", ref.TableName, "error", err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,197 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+// UniqueConstraint is a set of properties whose combined values must not be
+// shared by two resources of the same type within an account. A single-element
+// Properties slice comes from "x-unique": true on a property; multi-element
+// ones come from the root-level "x-indexes" entries marked "unique": true.
+type UniqueConstraint struct {
+	Properties []string
+}
+
+// ExtractUniqueConstraints parses a JSON Schema for x-unique properties and
+// unique x-indexes entries. The result is sorted so error messages are stable.
+func ExtractUniqueConstraints(schema json.RawMessage) []UniqueConstraint {
+	if len(schema) == 0 {
+		return nil
+	}
+	var s struct {
+		Properties map[string]struct {
+			XUnique bool `json:"x-unique"`
+		} `json:"properties"`
+		XIndexes []struct {
+			Properties []string `json:"properties"`
+			Unique     bool     `json:"unique"`
+		} `json:"x-indexes"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil
+	}
+
+	var single []string
+	for propName, prop := range s.Properties {
+		if prop.XUnique {
+			single = append(single, propName)
+		}
+	}
+	sort.Strings(single)
+
+	constraints := make([]UniqueConstraint, 0, len(single)+len(s.XIndexes))
+	for _, propName := range single {
+		constraints = append(constraints, UniqueConstraint{Properties: []string{propName}})
+	}
+	for _, idx := range s.XIndexes {
+		if idx.Unique && len(idx.Properties) > 0 {
+			constraints = append(constraints, UniqueConstraint{Properties: idx.Properties})
+		}
+	}
+	return constraints
+}
+
+// checkUniqueConstraints rejects data whose unique property values are
+// already used by another resource of typeSlug in the same account. selfID
+// is the resource being updated (empty on create) and is excluded from the
+// match. Constraints with a missing or null member are skipped — like SQL
+// UNIQUE, absent values never conflict.
+//
+// The lookup uses an admin scope on the account, not the caller's: a
+// conflicting row must be detected even when the caller cannot read it.
+// Resources outside any account are checked against the whole type. A
+// constraint the query cannot apply fails the write instead of passing it.
+// The error names the properties but never the conflicting resource. The
+// projection can lag behind a concurrent write, so this check only gives
+// the common case its error; the keys from uniqueKeys enforce it.
+func (s *resourceService) checkUniqueConstraints(
+	ctx context.Context, typeSlug string, schema, data json.RawMessage, accountID, selfID string,
+) error {
+	constraints := ExtractUniqueConstraints(schema)
+	if len(constraints) == 0 {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil // schema validation reports malformed data
+	}
+
+	for _, uc := range constraints {
+		filters := make([]repositories.FilterCondition, 0, len(uc.Properties)+1)
+		complete := true
+		for _, prop := range uc.Properties {
+			val, ok := uniqueFieldValue(fields[prop])
+			if !ok {
+				complete = false
+				break
+			}
+			filters = append(filters, repositories.FilterCondition{
+				Field: prop, Operator: "eq", Value: val,
+			})
+		}
+		if !complete {
+			continue
+		}
+
+		// Limit 2: at most one row can be selfID, so a second row is enough
+		// to prove a conflict.
+		page, err := s.repo.FindAllByTypeWithFilters(ctx, typeSlug, filters, "", 2,
+			repositories.SortOptions{}, &repositories.VisibilityScope{AccountID: accountID, IsAdmin: true})
+		if err != nil {
+			return fmt.Errorf("failed to check uniqueness of %s: %w",
+				strings.Join(uc.Properties, ", "), err)
+		}
+		for _, existing := range page.Data {
+			if existing.GetID() == selfID {
+				continue
+			}
+			if len(uc.Properties) == 1 {
+				return fmt.Errorf("%s %q is already in use: %w",
+					uc.Properties[0], filters[0].Value, ErrConflict)
+			}
+			return fmt.Errorf("the combination of %s is already in use: %w",
+				strings.Join(uc.Properties, ", "), ErrConflict)
+		}
+	}
+	return nil
+}
+
+// uniqueKeys returns the keys data holds under the type's unique
+// constraints, for the event store to reserve alongside the resource's
+// events. checkUniqueConstraints reads the projection, which can lag behind
+// a concurrent write; the reservation cannot, so it is what makes the
+// constraint hold. Constraints checkUniqueConstraints skips yield no key.
+func uniqueKeys(typeSlug string, schema, data json.RawMessage, accountID string) []entities.UniqueKey {
+	constraints := ExtractUniqueConstraints(schema)
+	if len(constraints) == 0 {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	keys := make([]entities.UniqueKey, 0, len(constraints))
+	for _, uc := range constraints {
+		parts := []string{typeSlug, accountID, strings.Join(uc.Properties, ",")}
+		complete := true
+		for _, prop := range uc.Properties {
+			val, ok := uniqueFieldValue(fields[prop])
+			if !ok {
+				complete = false
+				break
+			}
+			parts = append(parts, val)
+		}
+		if !complete {
+			continue
+		}
+		sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+		label := "the combination of " + strings.Join(uc.Properties, ", ")
+		if len(uc.Properties) == 1 {
+			label = fmt.Sprintf("%s %q", uc.Properties[0], parts[3])
+		}
+		keys = append(keys, entities.UniqueKey{Hash: hex.EncodeToString(sum[:]), Label: label})
+	}
+	return keys
+}
+
+// uniqueKeyConflict maps a commit error caused by a reservation the event
+// store refused to ErrConflict, with the message checkUniqueConstraints
+// gives. Any other error yields nil.
+func uniqueKeyConflict(err error) error {
+	var taken *entities.UniqueKeyTakenError
+	if !errors.As(err, &taken) {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", taken, ErrConflict)
+}
+
+// uniqueFieldValue renders a raw JSON value as the string form used by
+// projection filters. Reference values may arrive as a bare ID or as an
+// {"@id": ...} node. Objects, arrays, booleans and nulls are not comparable
+// and report ok=false.
+func uniqueFieldValue(v any) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, val != ""
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	case map[string]any:
+		id, ok := val["@id"].(string)
+		return id, ok && id != ""
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+)
+
+// filterRecordingRepo answers FindAllByTypeWithFilters with a fixed page and
+// records the filters and scopes it was called with.
+type filterRecordingRepo struct {
+	repositories.ResourceRepository
+	matches []*entities.Resource
+	calls   [][]repositories.FilterCondition
+	scopes  []*repositories.VisibilityScope
+}
+
+func (r *filterRecordingRepo) FindAllByTypeWithFilters(
+	_ context.Context, _ string, filters []repositories.FilterCondition,
+	_ string, limit int, _ repositories.SortOptions, scope *repositories.VisibilityScope,
+) (repositories.PaginatedResponse[*entities.Resource], error) {
+	if scope == nil || !scope.IsAdmin || scope.AgentID != "" {
+		panic("uniqueness lookups must use an admin scope on the account")
+	}
+	r.calls = append(r.calls, filters)
+	r.scopes = append(r.scopes, scope)
+	return repositories.PaginatedResponse[*entities.Resource]{Data: r.matches, Limit: limit}, nil
+}
+
+func mustResource(t *testing.T, id string) *entities.Resource {
+	t.Helper()
+	e, err := new(entities.Resource).With(id, "product", json.RawMessage(`{}`), "", "acct-1")
+	if err != nil {
+		t.Fatalf("failed to build resource: %v", err)
+	}
+	return e
+}
+
+const uniqueTestSchema = `{
+	"type": "object",
+	"properties": {
+		"sku": {"type": "string", "x-unique": true},
+		"name": {"type": "string"},
+		"vendor": {"type": "string", "x-resource-type": "vendor"}
+	},
+	"x-indexes": [
+		{"properties": ["vendor", "name"], "unique": true},
+		{"properties": ["name"]}
+	]
+}`
+
+func TestExtractUniqueConstraints(t *testing.T) {
+	t.Parallel()
+	got := ExtractUniqueConstraints(json.RawMessage(uniqueTestSchema))
+	if len(got) != 2 {
+		t.Fatalf("constraints = %v, want 2 (x-unique sku + unique composite)", got)
+	}
+	if len(got[0].Properties) != 1 || got[0].Properties[0] != "sku" {
+		t.Errorf("first constraint = %v, want [sku]", got[0].Properties)
+	}
+	if len(got[1].Properties) != 2 || got[1].Properties[0] != "vendor" || got[1].Properties[1] != "name" {
+		t.Errorf("second constraint = %v, want [vendor name]", got[1].Properties)
+	}
+}
+
+func TestCheckUniqueConstraints_ConflictOnCreate(t *testing.T) {
+	t.Parallel()
+	repo := &filterRecordingRepo{matches: []*entities.Resource{mustResource(t, "urn:product:other")}}
+	svc := &resourceService{repo: repo, logger: noopLogger{}}
+
+	err := svc.checkUniqueConstraints(context.Background(), "product",
+		json.RawMessage(uniqueTestSchema), json.RawMessage(`{"sku":"ABC-1"}`), "acct-1", "")
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("err = %v, want ErrConflict", err)
+	}
+	// The composite constraint is skipped because vendor/name are absent.
+	if len(repo.calls) != 1 {
+		t.Fatalf("lookups = %d, want 1", len(repo.calls))
+	}
+	filters := repo.calls[0]
+	if len(filters) != 1 || filters[0].Field != "sku" || filters[0].Value != "ABC-1" ||
+		repo.scopes[0].AccountID != "acct-1" {
+		t.Errorf("filters = %+v in %+v, want sku=ABC-1 scoped to acct-1", filters, repo.scopes[0])
+	}
+}
+
+func TestCheckUniqueConstraints_SelfMatchOnUpdateIsNotAConflict(t *testing.T) {
+	t.Parallel()
+	repo := &filterRecordingRepo{matches: []*entities.Resource{mustResource(t, "urn:product:self")}}
+	svc := &resourceService{repo: repo, logger: noopLogger{}}
+
+	err := svc.checkUniqueConstraints(context.Background(), "product",
+		json.RawMessage(uniqueTestSchema), json.RawMessage(`{"sku":"ABC-1"}`), "acct-1", "urn:product:self")
+	if err != nil {
+		t.Fatalf("err = %v, want nil when the only match is the resource itself", err)
+	}
+}
+
+func TestCheckUniqueConstraints_CompositeUsesReferenceID(t *testing.T) {
+	t.Parallel()
+	repo := &filterRecordingRepo{matches: []*entities.Resource{mustResource(t, "urn:product:other")}}
+	svc := &resourceService{repo: repo, logger: noopLogger{}}
+
+	data := `{"name":"Widget","vendor":{"@id":"urn:vendor:v1"}}`
+	err := svc.checkUniqueConstraints(context.Background(), "product",
+		json.RawMessage(uniqueTestSchema), json.RawMessage(data), "acct-1", "")
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("err = %v, want ErrConflict", err)
+	}
+	if len(repo.calls) != 1 || repo.calls[0][0].Value != "urn:vendor:v1" {
+		t.Errorf("filters = %+v, want vendor matched by @id", repo.calls)
+	}
+}
+
+func TestCheckUniqueConstraints_NoConstraintsSkipsLookup(t *testing.T) {
+	t.Parallel()
+	repo := &filterRecordingRepo{}
+	svc := &resourceService{repo: repo, logger: noopLogger{}}
+
+	schema := `{"type":"object","properties":{"name":{"type":"string"}}}`
+	if err := svc.checkUniqueConstraints(context.Background(), "product",
+		json.RawMessage(schema), json.RawMessage(`{"name":"x"}`), "", ""); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	if len(repo.calls) != 0 {
+		t.Errorf("lookups = %d, want 0 for a schema without unique constraints", len(repo.calls))
+	}
+}
@@ -0,0 +1,185 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+)
+
+// RuleViolation is one x-rules predicate that a resource failed. Path names
+// the field the message is about; it is empty for rules about the resource
+// as a whole.
+type RuleViolation struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// RuleViolationError reports every x-rules violation of a single write, so a
+// client (or an LLM calling through MCP) can fix them all in one pass. It
+// matches ErrValidation with errors.Is.
+type RuleViolationError struct {
+	Violations []RuleViolation
+}
+
+func (e *RuleViolationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if v.Path != "" {
+			parts = append(parts, v.Path+": "+v.Message)
+		} else {
+			parts = append(parts, v.Message)
+		}
+	}
+	return "validation rules failed: " + strings.Join(parts, "; ")
+}
+
+func (e *RuleViolationError) Unwrap() error { return ErrValidation }
+
+// validationRule is one compiled entry of a schema's x-rules array.
+type validationRule struct {
+	rule     string
+	message  string
+	path     string
+	program  cel.Program
+	usesRefs bool
+}
+
+// ruleSet is the compiled x-rules of one schema together with the property
+// variables the predicates are evaluated against.
+type ruleSet struct {
+	props []celProperty
+	rules []validationRule
+}
+
+// ruleCache holds each type's compiled rule set.
+var ruleCache schemaCache[*ruleSet]
+
+// validationRulesOf returns the compiled x-rules of rt.
+func validationRulesOf(rt *entities.ResourceType) (*ruleSet, error) {
+	return ruleCache.get(rt, func(rt *entities.ResourceType) (*ruleSet, error) {
+		return compileValidationRules(rt.Schema())
+	})
+}
+
+// compileValidationRules compiles and type-checks the root-level x-rules
+// array of schema:
+//
+//	"x-rules": [
+//	  {"rule": "endDate > startDate", "message": "must be after startDate", "path": "endDate"}
+//	]
+//
+// Each rule must be a CEL expression over the schema's properties that
+// returns a bool. Errors wrap ErrValidation.
+func compileValidationRules(schema json.RawMessage) (*ruleSet, error) {
+	if len(schema) == 0 {
+		return &ruleSet{}, nil
+	}
+	var s struct {
+		XRules []struct {
+			Rule    string `json:"rule"`
+			Message string `json:"message"`
+			Path    string `json:"path"`
+		} `json:"x-rules"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return &ruleSet{}, nil // the jsonschema compiler reports malformed schemas
+	}
+	set := &ruleSet{}
+	if len(s.XRules) == 0 {
+		return set, nil
+	}
+
+	env, props, err := schemaCELEnv(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	set.props = props
+	for i, r := range s.XRules {
+		if r.Rule == "" {
+			return nil, fmt.Errorf("x-rules[%d]: rule must not be empty: %w", i, ErrValidation)
+		}
+		ast, iss := env.Compile(r.Rule)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("x-rules[%d]: invalid rule: %v: %w", i, iss.Err(), ErrValidation)
+		}
+		if !ast.OutputType().IsExactType(cel.BoolType) && !ast.OutputType().IsExactType(cel.DynType) {
+			return nil, fmt.Errorf("x-rules[%d]: rule must return a bool, returns %s: %w",
+				i, ast.OutputType(), ErrValidation)
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("x-rules[%d]: invalid rule: %v: %w", i, err, ErrValidation)
+		}
+		message := r.Message
+		if message == "" {
+			message = "must satisfy " + r.Rule
+		}
+		set.rules = append(set.rules, validationRule{
+			rule:     r.Rule,
+			message:  message,
+			path:     r.Path,
+			program:  program,
+			usesRefs: usesCELVariable(ast, celRefsVar),
+		})
+	}
+	return set, nil
+}
+
+// validateRuleAnnotations rejects x-rules entries that don't compile or don't
+// return a bool. Called when a ResourceType is created or updated.
+func validateRuleAnnotations(schema json.RawMessage) error {
+	_, err := compileValidationRules(schema)
+	return err
+}
+
+// checkValidationRules evaluates the type's x-rules against data, which has
+// already passed schema validation, and returns a *RuleViolationError listing
+// every rule that failed. A rule that can't be evaluated (for example
+// timestamp() on an empty string) counts as failed.
+func (s *resourceService) checkValidationRules(
+	ctx context.Context, rt *entities.ResourceType, data json.RawMessage,
+) error {
+	set, err := validationRulesOf(rt)
+	if err != nil {
+		return err
+	}
+	if len(set.rules) == 0 {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("invalid JSON data: %w", ErrValidation)
+	}
+
+	var refs map[string]any
+	for _, r := range set.rules {
+		if r.usesRefs {
+			refs = s.loadReferencedData(ctx, rt, m)
+			break
+		}
+	}
+	vars := celActivation(set.props, m, refs, time.Now())
+
+	var violations []RuleViolation
+	for _, r := range set.rules {
+		out, _, err := r.program.Eval(vars)
+		if err != nil {
+			s.logger.Info(ctx, "validation rule could not be evaluated",
+				"type", rt.Slug(), "rule", r.rule, "error", err)
+		}
+		if err != nil || out != types.True {
+			violations = append(violations, RuleViolation{Path: r.path, Message: r.message})
+		}
+	}
+	if len(violations) > 0 {
+		return &RuleViolationError{Violations: violations}
+	}
+	return nil
+}
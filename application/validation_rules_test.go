@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const rulesTestSchema = `{
+	"type": "object",
+	"properties": {
+		"startDate": {"type": "string"},
+		"endDate": {"type": "string"},
+		"availability": {"type": "string"},
+		"price": {"type": "number"}
+	},
+	"x-rules": [
+		{"rule": "endDate > startDate", "message": "must be after startDate", "path": "endDate"},
+		{"rule": "availability != 'InStock' || price > 0.0", "message": "is required when in stock", "path": "price"}
+	]
+}`
+
+func TestValidateRuleAnnotations(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "valid rules", schema: rulesTestSchema},
+		{
+			name:    "not a bool",
+			schema:  `{"properties":{"name":{"type":"string"}},"x-rules":[{"rule":"name","message":"x"}]}`,
+			wantErr: "must return a bool",
+		},
+		{
+			name:    "unknown property",
+			schema:  `{"properties":{},"x-rules":[{"rule":"missing > 1"}]}`,
+			wantErr: "invalid rule",
+		},
+		{
+			name:    "empty rule",
+			schema:  `{"x-rules":[{"message":"x"}]}`,
+			wantErr: "must not be empty",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateRuleAnnotations(json.RawMessage(tc.schema))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want ErrValidation containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckValidationRules_ReportsEveryViolation(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "offer", rulesTestSchema)
+	svc := &resourceService{logger: noopLogger{}}
+
+	err := svc.checkValidationRules(context.Background(), rt, json.RawMessage(
+		`{"startDate":"2026-05-02","endDate":"2026-05-01","availability":"InStock"}`))
+	var violations *RuleViolationError
+	if !errors.As(err, &violations) {
+		t.Fatalf("err = %v, want *RuleViolationError", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("rule violations must match ErrValidation")
+	}
+	want := []RuleViolation{
+		{Path: "endDate", Message: "must be after startDate"},
+		{Path: "price", Message: "is required when in stock"},
+	}
+	if len(violations.Violations) != len(want) {
+		t.Fatalf("violations = %+v, want %+v", violations.Violations, want)
+	}
+	for i := range want {
+		if violations.Violations[i] != want[i] {
+			t.Errorf("violation[%d] = %+v, want %+v", i, violations.Violations[i], want[i])
+		}
+	}
+}
+
+func TestCheckValidationRules_PassingData(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "offer", rulesTestSchema)
+	svc := &resourceService{logger: noopLogger{}}
+
+	err := svc.checkValidationRules(context.Background(), rt, json.RawMessage(
+		`{"startDate":"2026-05-01","endDate":"2026-05-02","availability":"InStock","price":9.5}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
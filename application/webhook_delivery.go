@@ -0,0 +1,276 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/identity"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"go.uber.org/fx"
+)
+
+const (
+	webhookDeliverJobName = "webhook.deliver"
+	// webhookMaxAttempts with the job backoff retries a failing receiver for
+	// about 20 minutes before the delivery is dead-lettered.
+	webhookMaxAttempts = 8
+	webhookTimeout     = 10 * time.Second
+)
+
+// Headers sent with every delivery. The signature is an HMAC-SHA256, keyed
+// with the subscription secret, of "<timestamp>.<body>".
+const (
+	WebhookEventHeader     = "X-Weos-Event"
+	WebhookDeliveryHeader  = "X-Weos-Delivery"
+	WebhookTimestampHeader = "X-Weos-Timestamp"
+	WebhookSignatureHeader = "X-Weos-Signature"
+)
+
+type webhookJobPayload struct {
+	DeliveryID string `json:"deliveryId"`
+}
+
+// webhookBody is the JSON body POSTed to receivers. Payload is the event's
+// own payload, as recorded in the event store.
+type webhookBody struct {
+	Event      string          `json:"event"`
+	EventID    string          `json:"eventId"`
+	ResourceID string          `json:"resourceId"`
+	TypeSlug   string          `json:"typeSlug,omitempty"`
+	AccountID  string          `json:"accountId,omitempty"`
+	SequenceNo int             `json:"sequenceNo"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// SignWebhook returns the X-Weos-Signature value for a delivery body sent at
+// timestamp (Unix seconds). Receivers recompute it to verify a delivery.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type webhookNotifier struct {
+	eventStore domain.EventStore
+	subs       repositories.WebhookSubscriptionRepository
+	deliveries repositories.WebhookDeliveryRepository
+	jobs       JobService
+	logger     entities.Logger
+}
+
+// subscribe feeds resource and resource type events to matching webhook
+// subscriptions. It only logs each delivery and enqueues its job, so a slow
+// receiver never holds up the event relay.
+func (n *webhookNotifier) subscribe(d *domain.EventDispatcher) error {
+	for _, pattern := range []string{entities.ResourceEventPattern, entities.ResourceTypeEventPattern} {
+		if err := domain.Subscribe(d, pattern, n.notify); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *webhookNotifier) notify(ctx context.Context, env domain.EventEnvelope[any]) error {
+	if internalEvents[env.EventType] {
+		return nil
+	}
+	active, err := n.subs.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+	var wanted []*entities.WebhookSubscription
+	for _, sub := range active {
+		if webhookWants(sub, env.EventType) {
+			wanted = append(wanted, sub)
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(env.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s payload: %w", env.EventType, err)
+	}
+	typeSlug, accountID := n.scope(ctx, env.EventType, env.AggregateID, payload)
+	body, err := json.Marshal(webhookBody{
+		Event:      env.EventType,
+		EventID:    env.ID,
+		ResourceID: env.AggregateID,
+		TypeSlug:   typeSlug,
+		AccountID:  accountID,
+		SequenceNo: env.SequenceNo,
+		OccurredAt: env.Created,
+		Payload:    payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+
+	var errs []error
+	for _, sub := range wanted {
+		if !webhookMatches(sub, env.EventType, typeSlug, accountID) {
+			continue
+		}
+		delivery := &entities.WebhookDelivery{
+			ID:             identity.NewWebhookDelivery(),
+			SubscriptionID: sub.ID,
+			EventID:        env.ID,
+			EventType:      env.EventType,
+			ResourceID:     env.AggregateID,
+			Payload:        body,
+			Status:         entities.WebhookDeliveryPending,
+			CreatedAt:      time.Now().UTC(),
+		}
+		if err := queueWebhookDelivery(ctx, n.deliveries, n.jobs, delivery); err != nil {
+			n.logger.Error(ctx, "failed to queue webhook delivery",
+				"webhook", sub.ID, "event", env.EventType, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// scope returns the type slug and account of the aggregate an event belongs
+// to. Only the creating event carries both, so other events look it up.
+func (n *webhookNotifier) scope(
+	ctx context.Context, eventType, aggregateID string, payload []byte,
+) (string, string) {
+	typeSlug, accountID := webhookScopeFields(payload)
+	if typeSlug != "" && (accountID != "" || !webhookAccountScoped(eventType)) {
+		return typeSlug, accountID
+	}
+	first, err := n.eventStore.GetEventsRange(ctx, aggregateID, 1, 1)
+	if err != nil || len(first) == 0 {
+		return typeSlug, accountID
+	}
+	raw, err := json.Marshal(first[0].Payload)
+	if err != nil {
+		return typeSlug, accountID
+	}
+	createdSlug, createdAccount := webhookScopeFields(raw)
+	if typeSlug == "" {
+		typeSlug = createdSlug
+	}
+	if accountID == "" {
+		accountID = createdAccount
+	}
+	return typeSlug, accountID
+}
+
+// webhookScopeFields reads TypeSlug (Slug on resource type events) and
+// AccountID from an encoded event payload.
+func webhookScopeFields(payload []byte) (string, string) {
+	var fields struct {
+		TypeSlug  string
+		Slug      string
+		AccountID string
+	}
+	_ = json.Unmarshal(payload, &fields)
+	if fields.TypeSlug == "" {
+		fields.TypeSlug = fields.Slug
+	}
+	return fields.TypeSlug, fields.AccountID
+}
+
+// WebhookDeliverer sends logged deliveries. It is the handler of the
+// built-in webhook.deliver job.
+type WebhookDeliverer struct {
+	subs       repositories.WebhookSubscriptionRepository
+	deliveries repositories.WebhookDeliveryRepository
+	client     *http.Client
+	logger     entities.Logger
+}
+
+func ProvideWebhookDeliverer(params struct {
+	fx.In
+	Subscriptions repositories.WebhookSubscriptionRepository
+	Deliveries    repositories.WebhookDeliveryRepository
+	Logger        entities.Logger
+}) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		subs:       params.Subscriptions,
+		deliveries: params.Deliveries,
+		client:     &http.Client{Timeout: webhookTimeout},
+		logger:     params.Logger,
+	}
+}
+
+// Deliver POSTs one delivery and records the attempt. It returns an error
+// when the receiver fails, so the job worker retries with backoff. A
+// delivery whose subscription is gone or inactive is dropped.
+func (d *WebhookDeliverer) Deliver(ctx context.Context, payload json.RawMessage) error {
+	var p webhookJobPayload
+	if err := json.Unmarshal(payload, &p); err != nil || p.DeliveryID == "" {
+		return fmt.Errorf("webhook job payload must name a delivery")
+	}
+	delivery, err := d.deliveries.FindByID(ctx, p.DeliveryID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	sub, err := d.subs.FindByID(ctx, delivery.SubscriptionID)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && !sub.Active) {
+		return d.deliveries.RecordAttempt(ctx, delivery.ID, entities.WebhookDeliveryFailed, 0,
+			"subscription is deleted or inactive")
+	}
+	if err != nil {
+		return err
+	}
+
+	status, sendErr := d.send(ctx, sub, delivery)
+	outcome, lastError := entities.WebhookDeliverySucceeded, ""
+	if sendErr != nil {
+		outcome, lastError = entities.WebhookDeliveryFailed, sendErr.Error()
+	}
+	if err := d.deliveries.RecordAttempt(ctx, delivery.ID, outcome, status, lastError); err != nil {
+		d.logger.Error(ctx, "failed to record webhook delivery attempt",
+			"delivery", delivery.ID, "error", err)
+	}
+	return sendErr
+}
+
+func (d *WebhookDeliverer) send(
+	ctx context.Context, sub *entities.WebhookSubscription, delivery *entities.WebhookDelivery,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "weos-webhooks")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
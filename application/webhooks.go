@@ -0,0 +1,321 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/pkg/identity"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	"go.uber.org/fx"
+)
+
+// webhookEventPrefixes are the event families a subscription may ask for.
+var webhookEventPrefixes = []string{"Resource", "ResourceType"}
+
+// internalEvents are bookkeeping for the projections, not facts about a
+// resource, so they are never sent to webhooks or event streams, nor
+// audited.
+var internalEvents = map[string]bool{
+	"Resource.Published": true,
+}
+
+// validWebhookPattern accepts "*", "<Family>.*", and "<Family>.<Event>".
+func validWebhookPattern(pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	family, event, ok := strings.Cut(pattern, ".")
+	return ok && event != "" && !strings.Contains(event, ".") &&
+		slices.Contains(webhookEventPrefixes, family)
+}
+
+// webhookPatternMatches reports whether an event type matches one pattern.
+func webhookPatternMatches(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	family, ok := strings.CutSuffix(pattern, ".*")
+	return ok && strings.HasPrefix(eventType, family+".")
+}
+
+// webhookWants reports whether sub subscribes to eventType, ignoring scope.
+func webhookWants(sub *entities.WebhookSubscription, eventType string) bool {
+	for _, pattern := range sub.Events {
+		if webhookPatternMatches(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookAccountScoped reports whether events of eventType belong to an
+// account. Resource types are shared by every account, so their events are
+// not.
+func webhookAccountScoped(eventType string) bool {
+	return !strings.HasPrefix(eventType, "ResourceType.")
+}
+
+// webhookMatches reports whether an event of eventType on a resource of
+// typeSlug in accountID goes to sub. Resource events only go to the
+// resource's own account; resource type events go to every account.
+func webhookMatches(sub *entities.WebhookSubscription, eventType, typeSlug, accountID string) bool {
+	if !sub.Active || !webhookWants(sub, eventType) {
+		return false
+	}
+	if webhookAccountScoped(eventType) && sub.AccountID != accountID {
+		return false
+	}
+	return len(sub.TypeSlugs) == 0 || slices.Contains(sub.TypeSlugs, typeSlug)
+}
+
+// CreateWebhookCommand subscribes the caller's active account to events.
+type CreateWebhookCommand struct {
+	URL       string
+	Events    []string
+	TypeSlugs []string
+	// Secret signs deliveries. One is generated when it is empty.
+	Secret string
+}
+
+// UpdateWebhookCommand replaces a subscription's URL, events, and type
+// filter. Secret and Active are left alone when unset.
+type UpdateWebhookCommand struct {
+	ID        string
+	URL       string
+	Events    []string
+	TypeSlugs []string
+	Secret    string
+	Active    *bool
+}
+
+// WebhookService manages the active account's webhook subscriptions and
+// their delivery log. Deliveries themselves are made by the job worker.
+type WebhookService interface {
+	Create(ctx context.Context, cmd CreateWebhookCommand) (*entities.WebhookSubscription, error)
+	Update(ctx context.Context, cmd UpdateWebhookCommand) (*entities.WebhookSubscription, error)
+	GetByID(ctx context.Context, id string) (*entities.WebhookSubscription, error)
+	List(ctx context.Context, cursor string, limit int) (
+		repositories.PaginatedResponse[*entities.WebhookSubscription], error)
+	Delete(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, id, cursor string, limit int) (
+		repositories.PaginatedResponse[*entities.WebhookDelivery], error)
+	// Replay sends a logged delivery again, as a new delivery with the same body.
+	Replay(ctx context.Context, id, deliveryID string) (*entities.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	subs       repositories.WebhookSubscriptionRepository
+	deliveries repositories.WebhookDeliveryRepository
+	jobs       JobService
+}
+
+func ProvideWebhookService(params struct {
+	fx.In
+	Subscriptions repositories.WebhookSubscriptionRepository
+	Deliveries    repositories.WebhookDeliveryRepository
+	Jobs          JobService
+}) WebhookService {
+	return &webhookService{
+		subs:       params.Subscriptions,
+		deliveries: params.Deliveries,
+		jobs:       params.Jobs,
+	}
+}
+
+// webhookAccount returns the caller's active account. Subscriptions always
+// belong to one account, so there must be a caller with one.
+func webhookAccount(ctx context.Context) (string, *auth.Identity, error) {
+	ident := auth.AgentFromCtx(ctx)
+	if ident == nil || ident.ActiveAccountID == "" {
+		return "", nil, fmt.Errorf("webhooks require an active account: %w", entities.ErrAccessDenied)
+	}
+	return ident.ActiveAccountID, ident, nil
+}
+
+func validateWebhook(rawURL string, events []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook url must be an absolute http or https URL: %w", ErrValidation)
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("webhook must subscribe to at least one event: %w", ErrValidation)
+	}
+	for _, pattern := range events {
+		if !validWebhookPattern(pattern) {
+			return fmt.Errorf("invalid event pattern %q (want \"*\", \"Resource.*\", or \"Resource.Updated\"): %w",
+				pattern, ErrValidation)
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func (s *webhookService) Create(
+	ctx context.Context, cmd CreateWebhookCommand,
+) (*entities.WebhookSubscription, error) {
+	accountID, ident, err := webhookAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhook(cmd.URL, cmd.Events); err != nil {
+		return nil, err
+	}
+	secret := cmd.Secret
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now().UTC()
+	sub := &entities.WebhookSubscription{
+		ID:        identity.NewWebhook(),
+		AccountID: accountID,
+		URL:       cmd.URL,
+		Events:    cmd.Events,
+		TypeSlugs: cmd.TypeSlugs,
+		Secret:    secret,
+		Active:    true,
+		CreatedBy: ident.AgentID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.subs.Save(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *webhookService) Update(
+	ctx context.Context, cmd UpdateWebhookCommand,
+) (*entities.WebhookSubscription, error) {
+	sub, err := s.GetByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWebhook(cmd.URL, cmd.Events); err != nil {
+		return nil, err
+	}
+	sub.URL, sub.Events, sub.TypeSlugs = cmd.URL, cmd.Events, cmd.TypeSlugs
+	if cmd.Secret != "" {
+		sub.Secret = cmd.Secret
+	}
+	if cmd.Active != nil {
+		sub.Active = *cmd.Active
+	}
+	sub.UpdatedAt = time.Now().UTC()
+	if err := s.subs.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// GetByID reports another account's subscription as not found.
+func (s *webhookService) GetByID(ctx context.Context, id string) (*entities.WebhookSubscription, error) {
+	accountID, _, err := webhookAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.subs.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.AccountID != accountID {
+		return nil, fmt.Errorf("webhook %q: %w", id, repositories.ErrNotFound)
+	}
+	return sub, nil
+}
+
+func (s *webhookService) List(
+	ctx context.Context, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.WebhookSubscription], error) {
+	accountID, _, err := webhookAccount(ctx)
+	if err != nil {
+		return repositories.PaginatedResponse[*entities.WebhookSubscription]{}, err
+	}
+	return s.subs.FindAllByAccount(ctx, accountID, cursor, limit)
+}
+
+func (s *webhookService) Delete(ctx context.Context, id string) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return s.subs.Delete(ctx, id)
+}
+
+func (s *webhookService) ListDeliveries(
+	ctx context.Context, id, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.WebhookDelivery], error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return repositories.PaginatedResponse[*entities.WebhookDelivery]{}, err
+	}
+	return s.deliveries.FindAllBySubscription(ctx, id, cursor, limit)
+}
+
+func (s *webhookService) Replay(
+	ctx context.Context, id, deliveryID string,
+) (*entities.WebhookDelivery, error) {
+	sub, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Active {
+		return nil, fmt.Errorf("webhook %q is inactive: %w", id, ErrValidation)
+	}
+	original, err := s.deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.SubscriptionID != sub.ID {
+		return nil, fmt.Errorf("delivery %q: %w", deliveryID, repositories.ErrNotFound)
+	}
+	replay := &entities.WebhookDelivery{
+		ID:             identity.NewWebhookDelivery(),
+		SubscriptionID: sub.ID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		ResourceID:     original.ResourceID,
+		Payload:        original.Payload,
+		Status:         entities.WebhookDeliveryPending,
+		ReplayOf:       original.ID,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := queueWebhookDelivery(ctx, s.deliveries, s.jobs, replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// queueWebhookDelivery logs a delivery and enqueues the job that sends it.
+func queueWebhookDelivery(
+	ctx context.Context,
+	deliveries repositories.WebhookDeliveryRepository,
+	jobs JobService,
+	delivery *entities.WebhookDelivery,
+) error {
+	if err := deliveries.Save(ctx, delivery); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(webhookJobPayload{DeliveryID: delivery.ID})
+	if err != nil {
+		return err
+	}
+	_, err = jobs.Enqueue(ctx, EnqueueJobCommand{Name: webhookDeliverJobName, Payload: payload})
+	return err
+}
@@ -0,0 +1,276 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+)
+
+func TestValidWebhookPattern(t *testing.T) {
+	t.Parallel()
+	for pattern, want := range map[string]bool{
+		"*":                     true,
+		"Resource.*":            true,
+		"Resource.Updated":      true,
+		"ResourceType.Created":  true,
+		"Triple.Created":        false,
+		"Resource":              false,
+		"Resource.":             false,
+		"Resource.Updated.More": false,
+	} {
+		if got := validWebhookPattern(pattern); got != want {
+			t.Errorf("validWebhookPattern(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	t.Parallel()
+	sub := &entities.WebhookSubscription{
+		AccountID: "acct-1",
+		Events:    []string{"Resource.*", "ResourceType.Updated"},
+		TypeSlugs: []string{"task"},
+		Active:    true,
+	}
+	tests := []struct {
+		name                           string
+		eventType, typeSlug, accountID string
+		want                           bool
+	}{
+		{"matching resource event", "Resource.Updated", "task", "acct-1", true},
+		{"other account", "Resource.Updated", "task", "acct-2", false},
+		{"resource without account", "Resource.Updated", "task", "", false},
+		{"other type", "Resource.Updated", "project", "acct-1", false},
+		{"type event is shared", "ResourceType.Updated", "task", "", true},
+		{"unsubscribed type event", "ResourceType.Created", "task", "", false},
+	}
+	for _, tt := range tests {
+		if got := webhookMatches(sub, tt.eventType, tt.typeSlug, tt.accountID); got != tt.want {
+			t.Errorf("%s: webhookMatches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	sub.Active = false
+	if webhookMatches(sub, "Resource.Updated", "task", "acct-1") {
+		t.Error("an inactive subscription matched")
+	}
+}
+
+func TestWebhookService_AccountScoped(t *testing.T) {
+	t.Parallel()
+	store := newMemWebhookStore()
+	svc := &webhookService{subs: store.subscriptions(), deliveries: store.log()}
+	ctx := auth.ContextWithAgent(context.Background(),
+		&auth.Identity{AgentID: "agent-1", ActiveAccountID: "acct-1"})
+	other := auth.ContextWithAgent(context.Background(),
+		&auth.Identity{AgentID: "agent-2", ActiveAccountID: "acct-2"})
+
+	if _, err := svc.Create(context.Background(), CreateWebhookCommand{
+		URL: "https://example.com/hook", Events: []string{"*"},
+	}); !errors.Is(err, entities.ErrAccessDenied) {
+		t.Errorf("create without an account: err = %v, want ErrAccessDenied", err)
+	}
+	if _, err := svc.Create(ctx, CreateWebhookCommand{
+		URL: "ftp://example.com", Events: []string{"*"},
+	}); !errors.Is(err, ErrValidation) {
+		t.Errorf("non-http url: err = %v, want ErrValidation", err)
+	}
+
+	sub, err := svc.Create(ctx, CreateWebhookCommand{
+		URL: "https://example.com/hook", Events: []string{"Resource.*"},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if sub.AccountID != "acct-1" || sub.Secret == "" || !sub.Active {
+		t.Errorf("created %+v, want an active acct-1 subscription with a secret", sub)
+	}
+	if _, err := svc.GetByID(other, sub.ID); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("another account's GetByID: err = %v, want ErrNotFound", err)
+	}
+	if err := svc.Delete(other, sub.ID); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("another account's Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWebhookDeliverer_SignsAndRecords(t *testing.T) {
+	t.Parallel()
+	var (
+		mu       sync.Mutex
+		received []*http.Request
+		bodies   [][]byte
+		status   = http.StatusOK
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	store := newMemWebhookStore()
+	store.subs["hook-1"] = &entities.WebhookSubscription{
+		ID: "hook-1", URL: receiver.URL, Secret: "s3cret", Active: true,
+	}
+	store.deliveries["d-1"] = &entities.WebhookDelivery{
+		ID: "d-1", SubscriptionID: "hook-1", EventType: "Resource.Updated",
+		Payload: json.RawMessage(`{"event":"Resource.Updated"}`), Status: entities.WebhookDeliveryPending,
+	}
+	d := &WebhookDeliverer{
+		subs: store.subscriptions(), deliveries: store.log(), client: receiver.Client(), logger: noopLogger{},
+	}
+	job := json.RawMessage(`{"deliveryId":"d-1"}`)
+
+	if err := d.Deliver(context.Background(), job); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	mu.Lock()
+	req := received[0]
+	mu.Unlock()
+	ts, _ := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	if got, want := req.Header.Get(WebhookSignatureHeader), SignWebhook("s3cret", ts, bodies[0]); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if req.Header.Get(WebhookEventHeader) != "Resource.Updated" || req.Header.Get(WebhookDeliveryHeader) != "d-1" {
+		t.Errorf("headers = %v", req.Header)
+	}
+	if got := store.deliveries["d-1"]; got.Status != entities.WebhookDeliverySucceeded || got.Attempts != 1 {
+		t.Errorf("after success: %+v", got)
+	}
+
+	mu.Lock()
+	status = http.StatusInternalServerError
+	mu.Unlock()
+	if err := d.Deliver(context.Background(), job); err == nil {
+		t.Fatal("Deliver to a failing receiver returned nil, want an error so the job retries")
+	}
+	if got := store.deliveries["d-1"]; got.Status != entities.WebhookDeliveryFailed ||
+		got.ResponseStatus != http.StatusInternalServerError || got.Attempts != 2 {
+		t.Errorf("after failure: %+v", got)
+	}
+
+	// A delivery for a deleted subscription is dropped, not retried.
+	delete(store.subs, "hook-1")
+	if err := d.Deliver(context.Background(), job); err != nil {
+		t.Errorf("Deliver for a deleted subscription: err = %v, want nil", err)
+	}
+}
+
+// memWebhookStore holds webhook subscriptions and deliveries in memory. Its
+// subscriptions() and log() views implement the two repositories.
+type memWebhookStore struct {
+	subs       map[string]*entities.WebhookSubscription
+	deliveries map[string]*entities.WebhookDelivery
+}
+
+func newMemWebhookStore() *memWebhookStore {
+	return &memWebhookStore{
+		subs:       map[string]*entities.WebhookSubscription{},
+		deliveries: map[string]*entities.WebhookDelivery{},
+	}
+}
+
+func (m *memWebhookStore) subscriptions() *memWebhookSubs { return &memWebhookSubs{m} }
+
+func (m *memWebhookStore) log() *memWebhookDeliveries { return &memWebhookDeliveries{m} }
+
+type memWebhookSubs struct{ m *memWebhookStore }
+
+func (r *memWebhookSubs) Save(_ context.Context, sub *entities.WebhookSubscription) error {
+	r.m.subs[sub.ID] = sub
+	return nil
+}
+
+func (r *memWebhookSubs) Update(_ context.Context, sub *entities.WebhookSubscription) error {
+	r.m.subs[sub.ID] = sub
+	return nil
+}
+
+func (r *memWebhookSubs) FindByID(_ context.Context, id string) (*entities.WebhookSubscription, error) {
+	sub, ok := r.m.subs[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	cp := *sub
+	return &cp, nil
+}
+
+func (r *memWebhookSubs) FindAllByAccount(
+	_ context.Context, accountID, _ string, _ int,
+) (repositories.PaginatedResponse[*entities.WebhookSubscription], error) {
+	var out []*entities.WebhookSubscription
+	for _, sub := range r.m.subs {
+		if sub.AccountID == accountID {
+			out = append(out, sub)
+		}
+	}
+	return repositories.PaginatedResponse[*entities.WebhookSubscription]{Data: out}, nil
+}
+
+func (r *memWebhookSubs) FindActive(context.Context) ([]*entities.WebhookSubscription, error) {
+	var out []*entities.WebhookSubscription
+	for _, sub := range r.m.subs {
+		if sub.Active {
+			out = append(out, sub)
+		}
+	}
+	return out, nil
+}
+
+func (r *memWebhookSubs) Delete(_ context.Context, id string) error {
+	delete(r.m.subs, id)
+	return nil
+}
+
+type memWebhookDeliveries struct{ m *memWebhookStore }
+
+func (r *memWebhookDeliveries) Save(_ context.Context, d *entities.WebhookDelivery) error {
+	r.m.deliveries[d.ID] = d
+	return nil
+}
+
+func (r *memWebhookDeliveries) FindByID(_ context.Context, id string) (*entities.WebhookDelivery, error) {
+	d, ok := r.m.deliveries[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	cp := *d
+	return &cp, nil
+}
+
+func (r *memWebhookDeliveries) RecordAttempt(
+	_ context.Context, id, status string, responseStatus int, lastError string,
+) error {
+	d, ok := r.m.deliveries[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	d.Status, d.ResponseStatus, d.LastError = status, responseStatus, lastError
+	d.Attempts++
+	return nil
+}
+
+func (r *memWebhookDeliveries) FindAllBySubscription(
+	_ context.Context, subscriptionID, _ string, _ int,
+) (repositories.PaginatedResponse[*entities.WebhookDelivery], error) {
+	var out []*entities.WebhookDelivery
+	for _, d := range r.m.deliveries {
+		if d.SubscriptionID == subscriptionID {
+			out = append(out, d)
+		}
+	}
+	return repositories.PaginatedResponse[*entities.WebhookDelivery]{Data: out}, nil
+}
@@ -0,0 +1,459 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+)
+
+// workflowAnyState in a transition's from list matches every state.
+const workflowAnyState = "*"
+
+// AvailableTransition is a workflow transition the caller can trigger on a
+// resource in its current state.
+type AvailableTransition struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// workflowTransition is one compiled entry of a workflow's transitions.
+type workflowTransition struct {
+	name     string
+	from     []string
+	to       string
+	roles    []string
+	guard    string
+	message  string
+	program  cel.Program // nil when the transition has no guard
+	usesRefs bool
+}
+
+func (t *workflowTransition) leavesFrom(state string) bool {
+	return slices.Contains(t.from, state) || slices.Contains(t.from, workflowAnyState)
+}
+
+// workflow is the compiled x-workflow annotation of one schema.
+type workflow struct {
+	property    string
+	initial     string
+	states      []string
+	transitions []workflowTransition
+	props       []celProperty
+}
+
+// workflowCache holds each type's compiled workflow, nil for types without
+// one.
+var workflowCache schemaCache[*workflow]
+
+// workflowOf returns the compiled x-workflow of rt, or nil when it has none.
+func workflowOf(rt *entities.ResourceType) (*workflow, error) {
+	return workflowCache.get(rt, func(rt *entities.ResourceType) (*workflow, error) {
+		return compileWorkflow(rt.Schema())
+	})
+}
+
+// compileWorkflow compiles the root-level x-workflow annotation of schema:
+//
+//	"x-workflow": {
+//	  "property": "status",
+//	  "initial": "open",
+//	  "states": ["open", "in-progress", "done"],
+//	  "transitions": [
+//	    {"name": "start", "from": ["open"], "to": "in-progress",
+//	     "guard": "assignee != ''", "roles": ["admin", "member"]}
+//	  ]
+//	}
+//
+// property defaults to "status" and initial to the first state. A from entry
+// of "*" matches every state. Guards are CEL predicates over the resource
+// data as it will be saved. It returns nil when the schema declares no
+// workflow. Errors wrap ErrValidation.
+func compileWorkflow(schema json.RawMessage) (*workflow, error) {
+	if len(schema) == 0 {
+		return nil, nil
+	}
+	var s struct {
+		Properties map[string]struct {
+			Type string `json:"type"`
+		} `json:"properties"`
+		XWorkflow *struct {
+			Property    string   `json:"property"`
+			Initial     string   `json:"initial"`
+			States      []string `json:"states"`
+			Transitions []struct {
+				Name    string   `json:"name"`
+				From    []string `json:"from"`
+				To      string   `json:"to"`
+				Guard   string   `json:"guard"`
+				Message string   `json:"message"`
+				Roles   []string `json:"roles"`
+			} `json:"transitions"`
+		} `json:"x-workflow"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, nil // the jsonschema compiler reports malformed schemas
+	}
+	if s.XWorkflow == nil {
+		return nil, nil
+	}
+
+	x := s.XWorkflow
+	wf := &workflow{property: x.Property, initial: x.Initial, states: x.States}
+	if wf.property == "" {
+		wf.property = "status"
+	}
+	if prop, ok := s.Properties[wf.property]; !ok || (prop.Type != "" && prop.Type != "string") {
+		return nil, fmt.Errorf("x-workflow: property %q must be a string property of the schema: %w",
+			wf.property, ErrValidation)
+	}
+	if len(wf.states) == 0 {
+		return nil, fmt.Errorf("x-workflow: states must not be empty: %w", ErrValidation)
+	}
+	if wf.initial == "" {
+		wf.initial = wf.states[0]
+	}
+	if !slices.Contains(wf.states, wf.initial) {
+		return nil, fmt.Errorf("x-workflow: initial state %q is not one of the states: %w",
+			wf.initial, ErrValidation)
+	}
+
+	var env *cel.Env
+	seen := make(map[string]bool, len(x.Transitions))
+	for i, t := range x.Transitions {
+		if t.Name == "" {
+			return nil, fmt.Errorf("x-workflow: transitions[%d]: name must not be empty: %w", i, ErrValidation)
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("x-workflow: duplicate transition %q: %w", t.Name, ErrValidation)
+		}
+		seen[t.Name] = true
+		if !slices.Contains(wf.states, t.To) {
+			return nil, fmt.Errorf("x-workflow: transition %q: target state %q is not one of the states: %w",
+				t.Name, t.To, ErrValidation)
+		}
+		if len(t.From) == 0 {
+			return nil, fmt.Errorf("x-workflow: transition %q: from must not be empty: %w", t.Name, ErrValidation)
+		}
+		for _, from := range t.From {
+			if from != workflowAnyState && !slices.Contains(wf.states, from) {
+				return nil, fmt.Errorf("x-workflow: transition %q: source state %q is not one of the states: %w",
+					t.Name, from, ErrValidation)
+			}
+		}
+		compiled := workflowTransition{
+			name: t.Name, from: t.From, to: t.To, roles: t.Roles,
+			guard: t.Guard, message: t.Message,
+		}
+		if t.Guard != "" {
+			if env == nil {
+				var err error
+				env, wf.props, err = schemaCELEnv(schema)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+				}
+			}
+			ast, iss := env.Compile(t.Guard)
+			if iss.Err() != nil {
+				return nil, fmt.Errorf("x-workflow: transition %q: invalid guard: %v: %w",
+					t.Name, iss.Err(), ErrValidation)
+			}
+			if !ast.OutputType().IsExactType(cel.BoolType) && !ast.OutputType().IsExactType(cel.DynType) {
+				return nil, fmt.Errorf("x-workflow: transition %q: guard must return a bool, returns %s: %w",
+					t.Name, ast.OutputType(), ErrValidation)
+			}
+			program, err := env.Program(ast)
+			if err != nil {
+				return nil, fmt.Errorf("x-workflow: transition %q: invalid guard: %v: %w",
+					t.Name, err, ErrValidation)
+			}
+			compiled.program = program
+			compiled.usesRefs = usesCELVariable(ast, celRefsVar)
+		}
+		wf.transitions = append(wf.transitions, compiled)
+	}
+	return wf, nil
+}
+
+// validateWorkflow rejects an x-workflow whose states, transitions or guards
+// are inconsistent. Called when a ResourceType is created or updated.
+func validateWorkflow(schema json.RawMessage) error {
+	_, err := compileWorkflow(schema)
+	return err
+}
+
+// requestedTransitionKey carries the transition requested through
+// ResourceService.Transition into Update, so only that transition is
+// considered when several lead to the same state. It is bound to the
+// resource ID so writes cascading from behaviors aren't affected.
+type requestedTransitionKey struct{}
+
+type requestedTransition struct {
+	id   string
+	name string
+}
+
+func withRequestedTransition(ctx context.Context, id, name string) context.Context {
+	return context.WithValue(ctx, requestedTransitionKey{}, requestedTransition{id: id, name: name})
+}
+
+func requestedTransitionFor(ctx context.Context, id string) string {
+	if r, ok := ctx.Value(requestedTransitionKey{}).(requestedTransition); ok && r.id == id {
+		return r.name
+	}
+	return ""
+}
+
+// workflowState returns the state a resource is in. A resource saved before
+// its type declared the workflow is treated as being in the initial state.
+func (wf *workflow) workflowState(data map[string]any) string {
+	if state, _ := data[wf.property].(string); state != "" {
+		return state
+	}
+	return wf.initial
+}
+
+// applyWorkflowState fills in the workflow property before schema
+// validation: new resources start in the initial state, and an update that
+// leaves the property out keeps the current state. current is nil on create.
+// A create may only name the initial state.
+func applyWorkflowState(
+	rt *entities.ResourceType, current *entities.Resource, data json.RawMessage,
+) (json.RawMessage, error) {
+	wf, err := workflowOf(rt)
+	if err != nil || wf == nil {
+		return data, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil || m == nil {
+		return data, nil // schema validation reports malformed data
+	}
+	state, _ := m[wf.property].(string)
+	if current == nil {
+		if state != "" && state != wf.initial {
+			return nil, fmt.Errorf("%s: new resources start in state %q, not %q: %w",
+				wf.property, wf.initial, state, ErrValidation)
+		}
+		if state != "" {
+			return data, nil
+		}
+		m[wf.property] = wf.initial
+		return json.Marshal(m)
+	}
+	if state != "" {
+		return data, nil
+	}
+	var existing map[string]any
+	_ = json.Unmarshal(ExtractEntityNode(current.Data()), &existing)
+	m[wf.property] = wf.workflowState(existing)
+	return json.Marshal(m)
+}
+
+// checkTransition decides whether an update may move the resource from its
+// current state to the one in data. It returns the transition taken, or nil
+// when the state doesn't change. A state change has to match a declared
+// transition whose roles include the caller's and whose guard holds; when
+// several transitions lead to the same state the first that passes is used.
+func (s *resourceService) checkTransition(
+	ctx context.Context, rt *entities.ResourceType, entity *entities.Resource, data json.RawMessage,
+) (*workflowTransition, string, error) {
+	wf, err := workflowOf(rt)
+	if err != nil || wf == nil {
+		return nil, "", err
+	}
+	var existing, m map[string]any
+	_ = json.Unmarshal(ExtractEntityNode(entity.Data()), &existing)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, "", fmt.Errorf("invalid JSON data: %w", ErrValidation)
+	}
+	from := wf.workflowState(existing)
+	to, _ := m[wf.property].(string)
+	requested := requestedTransitionFor(ctx, entity.GetID())
+	if to == from && requested == "" {
+		return nil, from, nil
+	}
+	if !slices.Contains(wf.states, to) {
+		return nil, from, fmt.Errorf("%s: %q is not a state of the %s workflow: %w",
+			wf.property, to, rt.Slug(), ErrValidation)
+	}
+
+	var firstErr error
+	for i := range wf.transitions {
+		t := &wf.transitions[i]
+		if requested != "" && t.name != requested {
+			continue
+		}
+		if t.to != to || !t.leavesFrom(from) {
+			continue
+		}
+		if err := s.allowTransition(ctx, rt, wf, t, entity, m); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		return t, from, nil
+	}
+	if firstErr != nil {
+		return nil, from, firstErr
+	}
+	if requested != "" {
+		return nil, from, fmt.Errorf("transition %q is not allowed from state %q: %w",
+			requested, from, ErrValidation)
+	}
+	return nil, from, fmt.Errorf("%s: no transition from %q to %q: %w",
+		wf.property, from, to, ErrValidation)
+}
+
+// allowTransition checks the caller's role and the guard of t against the
+// data the resource will have after the transition.
+func (s *resourceService) allowTransition(
+	ctx context.Context, rt *entities.ResourceType, wf *workflow,
+	t *workflowTransition, entity *entities.Resource, data map[string]any,
+) error {
+	if len(t.roles) > 0 {
+		if role, system := s.callerRole(ctx, entity); !system && !slices.Contains(t.roles, role) {
+			return fmt.Errorf("transition %q requires role %s: %w",
+				t.name, strings.Join(t.roles, " or "), entities.ErrAccessDenied)
+		}
+	}
+	if t.program == nil {
+		return nil
+	}
+	var refs map[string]any
+	if t.usesRefs {
+		refs = s.loadReferencedData(ctx, rt, data)
+	}
+	out, _, err := t.program.Eval(celActivation(wf.props, data, refs, time.Now()))
+	if err != nil {
+		s.logger.Info(ctx, "workflow guard could not be evaluated",
+			"type", rt.Slug(), "transition", t.name, "error", err)
+	}
+	if err != nil || out != types.True {
+		message := t.message
+		if message == "" {
+			message = "requires " + t.guard
+		}
+		return fmt.Errorf("transition %q: %s: %w", t.name, message, ErrValidation)
+	}
+	return nil
+}
+
+// callerRole returns the caller's member role in the resource's account, or
+// in their active account for resources without one. system is true for
+// calls without an identity (CLI, scheduled work), which skip role checks.
+func (s *resourceService) callerRole(ctx context.Context, entity *entities.Resource) (role string, system bool) {
+	identity := auth.AgentFromCtx(ctx)
+	if identity == nil {
+		return "", true
+	}
+	accountID := entity.AccountID()
+	if accountID == "" {
+		accountID = identity.ActiveAccountID
+	}
+	if accountID == "" || s.accountRepo == nil {
+		return "", false
+	}
+	role, _ = s.accountRepo.FindMemberRole(ctx, accountID, identity.AgentID)
+	return role, false
+}
+
+// Transition moves a resource along the named workflow transition. It saves
+// through Update with the workflow property set to the transition's target,
+// so behaviors, validation and projections run exactly as for an edit.
+func (s *resourceService) Transition(
+	ctx context.Context, cmd TransitionResourceCommand,
+) (*entities.Resource, error) {
+	entity, err := s.repo.FindByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := s.typeRepo.FindBySlug(ctx, entity.TypeSlug())
+	if err != nil {
+		return nil, fmt.Errorf("resource type %q not found: %w", entity.TypeSlug(), err)
+	}
+	wf, err := workflowOf(rt)
+	if err != nil {
+		return nil, err
+	}
+	var t *workflowTransition
+	if wf != nil {
+		for i := range wf.transitions {
+			if wf.transitions[i].name == cmd.Name {
+				t = &wf.transitions[i]
+				break
+			}
+		}
+	}
+	if t == nil {
+		return nil, fmt.Errorf("resource type %q has no transition %q: %w",
+			rt.Slug(), cmd.Name, repositories.ErrNotFound)
+	}
+
+	data := s.storedFields(ctx, rt, entity)
+	if data == nil {
+		return nil, fmt.Errorf("failed to decode resource data of %q", entity.GetID())
+	}
+	data[wf.property] = t.to
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource data: %w", err)
+	}
+	return s.Update(withRequestedTransition(ctx, cmd.ID, cmd.Name), UpdateResourceCommand{ID: cmd.ID, Data: raw})
+}
+
+// AvailableTransitions lists the transitions the caller could trigger on the
+// resource right now: those leaving its current state whose roles and guard
+// pass. Guards see the stored data, as Transition would save it, not the
+// caller's filtered view of it. Types without a workflow, and callers who
+// can't modify the resource, get none.
+func (s *resourceService) AvailableTransitions(
+	ctx context.Context, id string,
+) ([]AvailableTransition, error) {
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkInstanceAccess(ctx, entity, "read"); err != nil {
+		return nil, err
+	}
+	rt, err := s.typeRepo.FindBySlug(ctx, entity.TypeSlug())
+	if err != nil {
+		return nil, fmt.Errorf("resource type %q not found: %w", entity.TypeSlug(), err)
+	}
+	wf, err := workflowOf(rt)
+	if err != nil || wf == nil {
+		return []AvailableTransition{}, err
+	}
+	if s.checkInstanceAccess(ctx, entity, "modify") != nil {
+		return []AvailableTransition{}, nil // readers can't trigger transitions
+	}
+	data := s.storedFields(ctx, rt, entity)
+	if data == nil {
+		return nil, fmt.Errorf("failed to decode resource data of %q", entity.GetID())
+	}
+	from := wf.workflowState(data)
+
+	available := []AvailableTransition{}
+	for i := range wf.transitions {
+		t := &wf.transitions[i]
+		if !t.leavesFrom(from) {
+			continue
+		}
+		data[wf.property] = t.to
+		if s.allowTransition(ctx, rt, wf, t, entity, data) == nil {
+			available = append(available, AvailableTransition{Name: t.name, From: from, To: t.to})
+		}
+	}
+	return available, nil
+}
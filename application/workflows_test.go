@@ -0,0 +1,208 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	authrepos "github.com/akeemphilbert/pericarp/pkg/auth/domain/repositories"
+)
+
+const workflowTestSchema = `{
+	"type": "object",
+	"properties": {
+		"title": {"type": "string"},
+		"assignee": {"type": "string"},
+		"status": {"type": "string"}
+	},
+	"x-workflow": {
+		"initial": "open",
+		"states": ["open", "in-progress", "done"],
+		"transitions": [
+			{"name": "start", "from": ["open"], "to": "in-progress",
+			 "guard": "assignee != ''", "message": "needs an assignee"},
+			{"name": "finish", "from": ["in-progress"], "to": "done", "roles": ["admin"]},
+			{"name": "reopen", "from": ["*"], "to": "open"}
+		]
+	}
+}`
+
+// roleAccountRepo answers FindMemberRole with a fixed role.
+type roleAccountRepo struct {
+	authrepos.AccountRepository
+	role string
+}
+
+func (r *roleAccountRepo) FindMemberRole(context.Context, string, string) (string, error) {
+	return r.role, nil
+}
+
+func workflowResource(t *testing.T, status string) *entities.Resource {
+	t.Helper()
+	e := &entities.Resource{}
+	graph := `{"@graph":[{"@id":"urn:ticket:1","title":"Fix it","status":"` + status + `"}]}`
+	if err := e.Restore("urn:ticket:1", "ticket", "active", json.RawMessage(graph),
+		"agent-1", "account-1", time.Now(), 1); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	return e
+}
+
+func TestValidateWorkflow(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{name: "valid workflow", schema: workflowTestSchema},
+		{
+			name:    "missing property",
+			schema:  `{"properties":{},"x-workflow":{"states":["a"]}}`,
+			wantErr: `property "status" must be a string property`,
+		},
+		{
+			name:    "unknown initial state",
+			schema:  `{"properties":{"status":{"type":"string"}},"x-workflow":{"initial":"x","states":["a"]}}`,
+			wantErr: "initial state",
+		},
+		{
+			name: "unknown target state",
+			schema: `{"properties":{"status":{"type":"string"}},"x-workflow":{"states":["a"],
+				"transitions":[{"name":"go","from":["a"],"to":"b"}]}}`,
+			wantErr: `target state "b"`,
+		},
+		{
+			name: "guard not a bool",
+			schema: `{"properties":{"status":{"type":"string"}},"x-workflow":{"states":["a","b"],
+				"transitions":[{"name":"go","from":["a"],"to":"b","guard":"status"}]}}`,
+			wantErr: "guard must return a bool",
+		},
+		{
+			name: "duplicate transition",
+			schema: `{"properties":{"status":{"type":"string"}},"x-workflow":{"states":["a","b"],
+				"transitions":[{"name":"go","from":["a"],"to":"b"},{"name":"go","from":["b"],"to":"a"}]}}`,
+			wantErr: "duplicate transition",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateWorkflow(json.RawMessage(tc.schema))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want ErrValidation containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyWorkflowState(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "ticket", workflowTestSchema)
+
+	out, err := applyWorkflowState(rt, nil, json.RawMessage(`{"title":"Fix it"}`))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.Contains(string(out), `"status":"open"`) {
+		t.Errorf("create: got %s, want the initial state filled in", out)
+	}
+
+	if _, err := applyWorkflowState(rt, nil, json.RawMessage(`{"status":"done"}`)); !errors.Is(err, ErrValidation) {
+		t.Errorf("create in a later state: err = %v, want ErrValidation", err)
+	}
+
+	out, err = applyWorkflowState(rt, workflowResource(t, "in-progress"), json.RawMessage(`{"title":"Renamed"}`))
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if !strings.Contains(string(out), `"status":"in-progress"`) {
+		t.Errorf("update: got %s, want the current state kept", out)
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "ticket", workflowTestSchema)
+	member := auth.ContextWithAgent(context.Background(), &auth.Identity{AgentID: "agent-1"})
+	svc := &resourceService{logger: noopLogger{}, accountRepo: &roleAccountRepo{role: "member"}}
+
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		from     string
+		data     string
+		wantName string
+		wantErr  error
+	}{
+		{name: "unchanged state", ctx: member, from: "open", data: `{"status":"open"}`},
+		{
+			name: "legal transition", ctx: member, from: "open",
+			data: `{"status":"in-progress","assignee":"ada"}`, wantName: "start",
+		},
+		{name: "no such transition", ctx: member, from: "open", data: `{"status":"done"}`, wantErr: ErrValidation},
+		{name: "guard fails", ctx: member, from: "open", data: `{"status":"in-progress"}`, wantErr: ErrValidation},
+		{
+			name: "role not allowed", ctx: member, from: "in-progress",
+			data: `{"status":"done"}`, wantErr: entities.ErrAccessDenied,
+		},
+		{
+			name: "system context skips roles", ctx: context.Background(), from: "in-progress",
+			data: `{"status":"done"}`, wantName: "finish",
+		},
+		{name: "wildcard source", ctx: member, from: "done", data: `{"status":"open"}`, wantName: "reopen"},
+		{name: "unknown state", ctx: member, from: "open", data: `{"status":"archived"}`, wantErr: ErrValidation},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, _, err := svc.checkTransition(tc.ctx, rt, workflowResource(t, tc.from), json.RawMessage(tc.data))
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			name := ""
+			if got != nil {
+				name = got.name
+			}
+			if name != tc.wantName {
+				t.Errorf("transition = %q, want %q", name, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestCheckTransition_RequestedNameOnlyMatchesItsResource(t *testing.T) {
+	t.Parallel()
+	rt := makeSchemaRT(t, "ticket", workflowTestSchema)
+	svc := &resourceService{logger: noopLogger{}}
+
+	ctx := withRequestedTransition(context.Background(), "urn:ticket:1", "finish")
+	_, _, err := svc.checkTransition(ctx, rt, workflowResource(t, "done"), json.RawMessage(`{"status":"open"}`))
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation: reopen must not satisfy a request for finish", err)
+	}
+
+	ctx = withRequestedTransition(context.Background(), "urn:ticket:other", "finish")
+	got, _, err := svc.checkTransition(ctx, rt, workflowResource(t, "done"), json.RawMessage(`{"status":"open"}`))
+	if err != nil || got == nil || got.name != "reopen" {
+		t.Fatalf("got %v, %v; a request for another resource must be ignored", got, err)
+	}
+}
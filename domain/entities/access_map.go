@@ -18,3 +18,14 @@ package entities
 // AccessMap maps role → (resourceTypeSlug → []action).
 // Actions are ODRL short names: "read", "modify", "delete".
 type AccessMap map[string]map[string][]string
+
+// FieldAccess lists the properties of a resource type that a role may read
+// and write. A nil list leaves that side unrestricted; an empty one allows
+// no properties.
+type FieldAccess struct {
+	Read  []string `json:"read"`
+	Write []string `json:"write"`
+}
+
+// FieldAccessMap maps role → (resourceTypeSlug → FieldAccess).
+type FieldAccessMap map[string]map[string]FieldAccess
@@ -0,0 +1,33 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AggregateSnapshot is an aggregate's replayed state as of SequenceNo, so a
+// replay can start there instead of at its first event. SchemaVersion is the
+// version of the replay logic that produced State; a snapshot of any other
+// version is ignored.
+type AggregateSnapshot struct {
+	AggregateID   string
+	SequenceNo    int
+	SchemaVersion int
+	State         json.RawMessage
+	CreatedAt     time.Time
+}
@@ -0,0 +1,112 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// Event metadata keys that record who caused an event, stamped on every
+// event as it is stored.
+const (
+	// AuditActorKey is the agent the action was taken as.
+	AuditActorKey = "actor"
+	// AuditRealActorKey is the admin who took the action while
+	// impersonating the actor; absent when nobody was impersonating.
+	AuditRealActorKey = "realActor"
+	AuditAccountKey   = "accountId"
+	AuditMethodKey    = "authMethod"
+	AuditRequestKey   = "requestId"
+)
+
+// How the actor of a request was authenticated.
+const (
+	AuthMethodSession     = "session"
+	AuthMethodOAuthBearer = "oauth_bearer"
+	AuthMethodMCPStdio    = "mcp_stdio"
+	// AuthMethodDev is the soft auth of a server with no authentication
+	// configured.
+	AuthMethodDev = "dev"
+)
+
+// AuditContext is what the request context knows about an action beyond the
+// authenticated agent, which is carried by the auth identity.
+type AuditContext struct {
+	AuthMethod  string
+	RealAgentID string
+	RequestID   string
+}
+
+type auditKeyType struct{}
+
+var auditKey = &auditKeyType{}
+
+// AuditFromContext returns the audit details carried by ctx; fields that
+// were never set are empty.
+func AuditFromContext(ctx context.Context) AuditContext {
+	a, _ := ctx.Value(auditKey).(AuditContext)
+	return a
+}
+
+func withAudit(ctx context.Context, update func(*AuditContext)) context.Context {
+	a := AuditFromContext(ctx)
+	update(&a)
+	return context.WithValue(ctx, auditKey, a)
+}
+
+// ContextWithAuthMethod records how the request's agent was authenticated.
+func ContextWithAuthMethod(ctx context.Context, method string) context.Context {
+	return withAudit(ctx, func(a *AuditContext) { a.AuthMethod = method })
+}
+
+// ContextWithRealAgent records the admin impersonating the request's agent.
+func ContextWithRealAgent(ctx context.Context, agentID string) context.Context {
+	return withAudit(ctx, func(a *AuditContext) { a.RealAgentID = agentID })
+}
+
+// ContextWithRequestID records the ID of the request an action belongs to.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return withAudit(ctx, func(a *AuditContext) { a.RequestID = requestID })
+}
+
+// AuditEntry is one stored event in the audit log, with the metadata that
+// says who caused it.
+type AuditEntry struct {
+	Seq         int64
+	EventID     string
+	EventType   string
+	ResourceID  string
+	TypeSlug    string
+	AgentID     string
+	RealAgentID string
+	AccountID   string
+	AuthMethod  string
+	RequestID   string
+	OccurredAt  time.Time
+}
+
+// AuditFilter narrows an audit log listing. Empty fields match everything;
+// From is inclusive and To exclusive.
+type AuditFilter struct {
+	AccountID  string
+	AgentID    string
+	ResourceID string
+	TypeSlug   string
+	EventType  string
+	From       time.Time
+	To         time.Time
+}
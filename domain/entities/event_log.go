@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package entities
+
+import (
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// LoggedEvent is an event with its position in the event log. Positions are
+// assigned when the event is appended and increase across all aggregates,
+// so they order every event in the store.
+type LoggedEvent struct {
+	Position int64
+	LoggedAt time.Time
+	Event    domain.EventEnvelope[any]
+}
+
+// EventCheckpoint records how far an event handler group has got through the
+// event log. Every event at or before Position has been handled.
+type EventCheckpoint struct {
+	Group    string
+	Position int64
+	// Attempts is how many times the event after Position has failed.
+	Attempts int
+	// RetryAt holds the group back after a failure.
+	RetryAt   time.Time
+	LastError string
+	// Skipped counts events the group gave up on after MaxAttempts failures.
+	// Each one is kept as a DeadLetteredEvent.
+	Skipped int
+	// LockedBy and LockedUntil are the lease of the relay working the group.
+	LockedBy    string
+	LockedUntil time.Time
+	UpdatedAt   time.Time
+}
+
+// DeadLetteredEvent is an event an event handler group gave up on. The group
+// moved past it; it stays here until it is retried.
+type DeadLetteredEvent struct {
+	Group     string
+	Position  int64
+	EventID   string
+	EventType string
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
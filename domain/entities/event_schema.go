@@ -0,0 +1,197 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// EventSchemaVersionKey is the event metadata key that records the schema
+// version of an event's payload. Events stored before payloads were
+// versioned have no version and are read as version 1.
+const EventSchemaVersionKey = "schemaVersion"
+
+// ErrUnknownEventVersion is returned for an event stored with a schema
+// version newer than this build knows, e.g. during a rolling deploy.
+var ErrUnknownEventVersion = errors.New("unknown event schema version")
+
+// Upcaster transforms a payload of one schema version of an event type into
+// the next version. It receives the payload as decoded from the event store
+// (map keys are the Go field names, or json tag names for tagged fields) and
+// may modify and return it.
+type Upcaster func(payload map[string]any) (map[string]any, error)
+
+// EventSchemaRegistry holds the upcaster chain of every event type whose
+// payload shape has changed. An event type's current version is one more
+// than the number of upcasters registered for it, so an event type with
+// none is at version 1.
+type EventSchemaRegistry struct {
+	mu        sync.RWMutex
+	upcasters map[string][]Upcaster
+}
+
+func NewEventSchemaRegistry() *EventSchemaRegistry {
+	return &EventSchemaRegistry{upcasters: make(map[string][]Upcaster)}
+}
+
+// Register adds the upcaster from version from of eventType to version
+// from+1. Upcasters must be registered in order, starting from version 1,
+// so the chain has no gaps.
+func (r *EventSchemaRegistry) Register(eventType string, from int, up Upcaster) error {
+	if eventType == "" || up == nil {
+		return fmt.Errorf("upcaster needs an event type and a function")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if want := len(r.upcasters[eventType]) + 1; from != want {
+		return fmt.Errorf("%s: next upcaster must be from version %d, got %d", eventType, want, from)
+	}
+	r.upcasters[eventType] = append(r.upcasters[eventType], up)
+	return nil
+}
+
+// Version returns the current schema version of eventType.
+func (r *EventSchemaRegistry) Version(eventType string) int {
+	if r == nil {
+		return 1
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.upcasters[eventType]) + 1
+}
+
+// Stamp returns env with the current schema version of its event type in
+// its metadata. It is applied to events as they are stored; the metadata
+// map is copied, not modified.
+func (r *EventSchemaRegistry) Stamp(env domain.EventEnvelope[any]) domain.EventEnvelope[any] {
+	metadata := maps.Clone(env.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]any, 1)
+	}
+	metadata[EventSchemaVersionKey] = r.Version(env.EventType)
+	env.Metadata = metadata
+	return env
+}
+
+// Upcast returns env with its payload transformed to the current schema
+// version of its event type. Events already at the current version are
+// returned unchanged.
+func (r *EventSchemaRegistry) Upcast(env domain.EventEnvelope[any]) (domain.EventEnvelope[any], error) {
+	if r == nil {
+		return env, nil
+	}
+	version, err := EventSchemaVersion(env)
+	if err != nil {
+		return env, err
+	}
+	r.mu.RLock()
+	chain := r.upcasters[env.EventType]
+	r.mu.RUnlock()
+	current := len(chain) + 1
+	switch {
+	case version == current:
+		return env, nil
+	case version > current:
+		return env, fmt.Errorf("%w: %s %s is at version %d, this build knows %d",
+			ErrUnknownEventVersion, env.EventType, env.ID, version, current)
+	}
+
+	payload, err := payloadMap(env.Payload)
+	if err != nil {
+		return env, fmt.Errorf("failed to read %s %s payload: %w", env.EventType, env.ID, err)
+	}
+	for v := version; v < current; v++ {
+		if payload, err = chain[v-1](payload); err != nil {
+			return env, fmt.Errorf("failed to upcast %s %s from version %d: %w", env.EventType, env.ID, v, err)
+		}
+	}
+	env.Payload = payload
+	return r.Stamp(env), nil
+}
+
+// UpcastAll upcasts every event in events.
+func (r *EventSchemaRegistry) UpcastAll(events []domain.EventEnvelope[any]) ([]domain.EventEnvelope[any], error) {
+	out := make([]domain.EventEnvelope[any], len(events))
+	for i, e := range events {
+		up, err := r.Upcast(e)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = up
+	}
+	return out, nil
+}
+
+// EventSchemaVersion returns the schema version recorded in env's metadata,
+// or 1 when it has none. Metadata read back from JSON holds numbers as
+// float64.
+func EventSchemaVersion(env domain.EventEnvelope[any]) (int, error) {
+	switch v := env.Metadata[EventSchemaVersionKey].(type) {
+	case nil:
+		return 1, nil
+	case int:
+		return v, nil
+	case float64:
+		if v >= 1 && v == float64(int(v)) {
+			return int(v), nil
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil && n >= 1 {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("%s %s has an invalid schema version %v",
+		env.EventType, env.ID, env.Metadata[EventSchemaVersionKey])
+}
+
+func payloadMap(payload any) (map[string]any, error) {
+	if m, ok := payload.(map[string]any); ok {
+		return m, nil
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// defaultEventSchemas is the process-wide registry that the event store
+// upcasts with. Register an upcaster here, in the same change that alters
+// the event's payload struct, and add a fixture for the new version to
+// testdata/events.
+var defaultEventSchemas = NewEventSchemaRegistry()
+
+// RegisterUpcaster adds an upcaster to the process-wide registry. Intended
+// for package init() of the package that owns the event type.
+func RegisterUpcaster(eventType string, from int, up Upcaster) error {
+	return defaultEventSchemas.Register(eventType, from, up)
+}
+
+// DefaultEventSchemas returns the process-wide registry seeded by
+// RegisterUpcaster calls.
+func DefaultEventSchemas() *EventSchemaRegistry {
+	return defaultEventSchemas
+}
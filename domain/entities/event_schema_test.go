@@ -0,0 +1,185 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// eventPayloads returns a new value of the current payload struct of every
+// event type.
+var eventPayloads = map[string]func() any{
+	"Resource.Created":           func() any { return &ResourceCreated{} },
+	"Resource.Updated":           func() any { return &ResourceUpdated{} },
+	"Resource.Deleted":           func() any { return &ResourceDeleted{} },
+	"Resource.Published":         func() any { return &ResourcePublished{} },
+	"Resource.Transitioned":      func() any { return &ResourceTransitioned{} },
+	"Resource.RevisionPublished": func() any { return &ResourceRevisionPublished{} },
+	"Resource.RevisionWithdrawn": func() any { return &ResourceRevisionWithdrawn{} },
+	"ResourceType.Created":       func() any { return &ResourceTypeCreated{} },
+	"ResourceType.Updated":       func() any { return &ResourceTypeUpdated{} },
+	"ResourceType.Deleted":       func() any { return &ResourceTypeDeleted{} },
+	"Triple.Created":             func() any { return &TripleCreated{} },
+	"Triple.Deleted":             func() any { return &TripleDeleted{} },
+}
+
+// loadEventFixtures reads testdata/events/<event type>/v<version>.json, each
+// a payload as the event store returns it, into envelopes of that version.
+// Version 1 fixtures carry no version, like events stored before payloads
+// were versioned.
+func loadEventFixtures(t *testing.T) []domain.EventEnvelope[any] {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "events", "*", "v*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no event fixtures found: %v", err)
+	}
+	var out []domain.EventEnvelope[any]
+	for _, path := range paths {
+		eventType := filepath.Base(filepath.Dir(path))
+		version, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "v"), ".json"))
+		if err != nil {
+			t.Fatalf("%s: fixture files are named v<version>.json", path)
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		env := domain.EventEnvelope[any]{ID: path, EventType: eventType, Payload: payload}
+		if version > 1 {
+			env.Metadata = map[string]any{EventSchemaVersionKey: float64(version)}
+		}
+		out = append(out, env)
+	}
+	return out
+}
+
+func TestEventFixtures_UpcastToCurrentPayloads(t *testing.T) {
+	t.Parallel()
+	schemas := DefaultEventSchemas()
+	for _, fixture := range loadEventFixtures(t) {
+		t.Run(fixture.ID, func(t *testing.T) {
+			newPayload, ok := eventPayloads[fixture.EventType]
+			if !ok {
+				t.Fatalf("fixture for unknown event type %s", fixture.EventType)
+			}
+			env, err := schemas.Upcast(fixture)
+			if err != nil {
+				t.Fatalf("Upcast: %v", err)
+			}
+			if v, _ := EventSchemaVersion(env); v != schemas.Version(env.EventType) {
+				t.Errorf("upcast to version %d, current is %d", v, schemas.Version(env.EventType))
+			}
+
+			// The upcast payload must decode into the current struct with no
+			// field left over, and encode back to the same payload.
+			raw, err := json.Marshal(env.Payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			payload := newPayload()
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(payload); err != nil {
+				t.Fatalf("decode into %T: %v", payload, err)
+			}
+			back, err := json.Marshal(payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want, got any
+			_ = json.Unmarshal(raw, &want)
+			_ = json.Unmarshal(back, &got)
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("%T round trip\n got %s\nwant %s", payload, back, raw)
+			}
+		})
+	}
+}
+
+func TestEventFixtures_CoverCurrentVersions(t *testing.T) {
+	t.Parallel()
+	schemas := DefaultEventSchemas()
+	for eventType := range eventPayloads {
+		path := filepath.Join("testdata", "events", eventType,
+			"v"+strconv.Itoa(schemas.Version(eventType))+".json")
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s is at version %d but has no fixture %s", eventType, schemas.Version(eventType), path)
+		}
+	}
+}
+
+func TestEventSchemaRegistry_Upcast(t *testing.T) {
+	t.Parallel()
+	schemas := NewEventSchemaRegistry()
+	// Version 2 renamed Title to Name; version 3 split Owner out of Name.
+	if err := schemas.Register("Thing.Created", 1, func(p map[string]any) (map[string]any, error) {
+		p["Name"] = p["Title"]
+		delete(p, "Title")
+		return p, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.Register("Thing.Created", 3, nil); err == nil {
+		t.Error("Register accepted a gap in the chain")
+	}
+	if err := schemas.Register("Thing.Created", 2, func(p map[string]any) (map[string]any, error) {
+		owner, name, _ := strings.Cut(p["Name"].(string), "/")
+		p["Owner"], p["Name"] = owner, name
+		return p, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if v := schemas.Version("Thing.Created"); v != 3 {
+		t.Fatalf("Version = %d, want 3", v)
+	}
+
+	want := map[string]any{"Owner": "ada", "Name": "engine"}
+	for _, env := range []domain.EventEnvelope[any]{
+		{EventType: "Thing.Created", Payload: map[string]any{"Title": "ada/engine"}},
+		{EventType: "Thing.Created", Payload: map[string]any{"Name": "ada/engine"},
+			Metadata: map[string]any{EventSchemaVersionKey: float64(2)}},
+		schemas.Stamp(domain.EventEnvelope[any]{EventType: "Thing.Created", Payload: want}),
+	} {
+		got, err := schemas.Upcast(env)
+		if err != nil {
+			t.Fatalf("Upcast: %v", err)
+		}
+		if !reflect.DeepEqual(got.Payload, want) || got.Metadata[EventSchemaVersionKey] != 3 {
+			t.Errorf("Upcast(%v) = %v %v, want %v at version 3", env.Payload, got.Payload, got.Metadata, want)
+		}
+	}
+
+	_, err := schemas.Upcast(domain.EventEnvelope[any]{
+		EventType: "Thing.Created", Metadata: map[string]any{EventSchemaVersionKey: float64(4)},
+	})
+	if !errors.Is(err, ErrUnknownEventVersion) {
+		t.Errorf("Upcast of a newer version: err = %v, want ErrUnknownEventVersion", err)
+	}
+}
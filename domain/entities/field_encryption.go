@@ -0,0 +1,37 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import "context"
+
+// EncryptedAnnotation marks a string property whose values are encrypted
+// before they are stored, in events, the resources table and projection
+// columns alike.
+const EncryptedAnnotation = "x-encrypted"
+
+// DecryptRolesAnnotation lists the account roles, besides owner and admin,
+// that may read a type's x-encrypted values.
+const DecryptRolesAnnotation = "x-decrypt-roles"
+
+// FieldKeyProvider supplies the keys x-encrypted values are encrypted with.
+// The local keyfile provider implements it; a KMS-backed one can too.
+type FieldKeyProvider interface {
+	// CurrentKey returns the key new values are encrypted with, and its ID.
+	CurrentKey(ctx context.Context) (id string, key []byte, err error)
+	// Key returns the key with the given ID, including retired keys that
+	// older values are still encrypted with.
+	Key(ctx context.Context, id string) ([]byte, error)
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package entities
+
+import (
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// PendingEvent is an event stored in the primary event store that the
+// secondary event store has not acknowledged yet.
+type PendingEvent struct {
+	EventID       string
+	AggregateID   string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	// Event is the stored event, loaded alongside the pending entry.
+	Event domain.EventEnvelope[any]
+}
+
+// PendingEventBacklog summarises the events waiting for the secondary
+// event store.
+type PendingEventBacklog struct {
+	Count       int64
+	Oldest      time.Time
+	MaxAttempts int
+	LastError   string
+}
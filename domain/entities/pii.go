@@ -0,0 +1,46 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// PIIAnnotation marks a resource type property as personal data. Its values
+// are encrypted in stored events with a key of the resource they belong to,
+// and destroying that key erases them.
+const PIIAnnotation = "x-pii"
+
+// SubjectKey is a data key of one subject, the resource whose personal data
+// it encrypts. A subject may have several keys; erasing the subject deletes
+// them all.
+type SubjectKey struct {
+	ID        string
+	SubjectID string
+	Key       []byte
+	CreatedAt time.Time
+}
+
+// PIICipher seals the personal data in event payloads before they are
+// stored, and opens it again as stored events are read. Values whose key
+// has been erased open as null.
+type PIICipher interface {
+	Seal(ctx context.Context, events []domain.EventEnvelope[any]) ([]domain.EventEnvelope[any], error)
+	Open(ctx context.Context, events []domain.EventEnvelope[any]) ([]domain.EventEnvelope[any], error)
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PublishedRevision is the public revision of an x-editorial resource: the
+// @graph data as it was when the resource last entered the published state.
+// A read model maintained from Resource.RevisionPublished and
+// Resource.RevisionWithdrawn events; the resource itself holds the draft.
+type PublishedRevision struct {
+	ResourceID  string
+	TypeSlug    string
+	AccountID   string
+	Data        json.RawMessage
+	PublishedAt time.Time
+}
@@ -134,6 +134,12 @@ func (e *Resource) ApplyEvent(
 	case TripleCreated, TripleDeleted:
 		// Triple events are recorded on the resource entity for UoW atomicity.
 		return nil
+	case ResourceTransitioned:
+		// The new state arrives with the accompanying ResourceUpdated.
+		return nil
+	case ResourceRevisionPublished, ResourceRevisionWithdrawn:
+		// The public revision lives in its own read model, not on the entity.
+		return nil
 	case ResourcePublished:
 		// Signal event — triggers consolidated projection write. No entity state change.
 		return nil
@@ -86,4 +86,75 @@ func (e ResourcePublished) EventType() string {
 	return "Resource.Published"
 }
 
-const ResourceEventPattern = "Resource.%"
+// ResourceTransitioned records a resource moving along one of its type's
+// x-workflow transitions. It is recorded alongside the Resource.Updated that
+// carries the new data.
+type ResourceTransitioned struct {
+	TypeSlug   string
+	Transition string
+	From       string
+	To         string
+	Timestamp  time.Time
+}
+
+func (e ResourceTransitioned) With(typeSlug, transition, from, to string) ResourceTransitioned {
+	return ResourceTransitioned{
+		TypeSlug:   typeSlug,
+		Transition: transition,
+		From:       from,
+		To:         to,
+		Timestamp:  time.Now(),
+	}
+}
+
+func (e ResourceTransitioned) EventType() string {
+	return "Resource.Transitioned"
+}
+
+// ResourceRevisionPublished makes the resource's current data its public
+// revision. Unlike ResourcePublished, which only signals that a write is ready
+// to project, it is an editorial event recorded when an x-editorial resource
+// enters the published state. Later edits change the working draft only.
+type ResourceRevisionPublished struct {
+	TypeSlug    string
+	AccountID   string
+	Data        json.RawMessage
+	PublishedAt time.Time
+}
+
+func (e ResourceRevisionPublished) With(
+	typeSlug, accountID string, data json.RawMessage,
+) ResourceRevisionPublished {
+	return ResourceRevisionPublished{
+		TypeSlug:    typeSlug,
+		AccountID:   accountID,
+		Data:        data,
+		PublishedAt: time.Now(),
+	}
+}
+
+func (e ResourceRevisionPublished) EventType() string {
+	return "Resource.RevisionPublished"
+}
+
+// ResourceRevisionWithdrawn removes the public revision of an x-editorial
+// resource when it leaves the published state.
+type ResourceRevisionWithdrawn struct {
+	TypeSlug  string
+	Timestamp time.Time
+}
+
+func (e ResourceRevisionWithdrawn) With(typeSlug string) ResourceRevisionWithdrawn {
+	return ResourceRevisionWithdrawn{
+		TypeSlug:  typeSlug,
+		Timestamp: time.Now(),
+	}
+}
+
+func (e ResourceRevisionWithdrawn) EventType() string {
+	return "Resource.RevisionWithdrawn"
+}
+
+// ResourceEventPattern subscribes an EventDispatcher handler to every
+// resource event.
+const ResourceEventPattern = "Resource.*"
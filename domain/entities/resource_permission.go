@@ -3,11 +3,13 @@ package entities
 import "time"
 
 // ResourcePermission represents an explicit grant of actions on a specific
-// resource to a specific agent. Not event-sourced — simple CRUD.
+// resource to a specific agent, or to every member of a team. Exactly one of
+// AgentID and TeamID is set. Not event-sourced — simple CRUD.
 type ResourcePermission struct {
 	ID         string
 	ResourceID string
 	AgentID    string
+	TeamID     string
 	Actions    []string // e.g. ["read", "modify", "delete"]
 	GrantedBy  string
 	GrantedAt  time.Time
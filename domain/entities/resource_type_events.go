@@ -87,4 +87,6 @@ func (e ResourceTypeDeleted) EventType() string {
 	return "ResourceType.Deleted"
 }
 
-const ResourceTypeEventPattern = "ResourceType.%"
+// ResourceTypeEventPattern subscribes an EventDispatcher handler to every
+// resource type event.
+const ResourceTypeEventPattern = "ResourceType.*"
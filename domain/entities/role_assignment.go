@@ -0,0 +1,62 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import "time"
+
+// BuiltinRolePrefix starts the name of every built-in role, so they cannot
+// collide with the custom roles of an access map. Custom role names may not
+// use it.
+const BuiltinRolePrefix = "weos:"
+
+// Built-in roles. Each includes every permission of the roles after it:
+// super-admin > site-admin > editor > author > viewer. Their definitions
+// cannot be changed through the role-access settings.
+const (
+	RoleSuperAdmin = BuiltinRolePrefix + "super-admin"
+	RoleSiteAdmin  = BuiltinRolePrefix + "site-admin"
+	RoleEditor     = BuiltinRolePrefix + "editor"
+	RoleAuthor     = BuiltinRolePrefix + "author"
+	RoleViewer     = BuiltinRolePrefix + "viewer"
+)
+
+// RoleHierarchy maps a role to the roles whose permissions it includes.
+type RoleHierarchy map[string][]string
+
+// Role assignment scopes, from the broadest to the most specific.
+const (
+	RoleScopeGlobal  = "global"
+	RoleScopeAccount = "account"
+	RoleScopeType    = "type"
+	RoleScopeSubtree = "subtree"
+)
+
+// RoleAssignment gives an agent a role within one scope. An agent may hold
+// several, each with its own scope. Every scope but global is bound to the
+// account the assignment was made in; a type scope further narrows it to
+// one resource type, and a subtree scope to one resource and the resources
+// that inherit permissions from it. Not event-sourced — simple CRUD.
+type RoleAssignment struct {
+	ID         string
+	AgentID    string
+	Role       string
+	Scope      string
+	AccountID  string
+	TypeSlug   string
+	ResourceID string
+	GrantedBy  string
+	GrantedAt  time.Time
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import "time"
+
+// Team is a named group of an account's members. Resource permissions can
+// be granted to a team, and then apply to whoever is a member at the time
+// of the check. Not event-sourced — simple CRUD.
+type Team struct {
+	ID          string
+	AccountID   string
+	Name        string
+	Description string
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TeamMember is one agent's membership of a team.
+type TeamMember struct {
+	TeamID  string
+	AgentID string
+	AddedBy string
+	AddedAt time.Time
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package entities
+
+import "context"
+
+// UniqueKey is the value a resource holds under one of its type's unique
+// constraints. Hash identifies the type, account, constraint and values and
+// is what the event store reserves; Label describes the constraint and its
+// value for error messages and is never stored.
+type UniqueKey struct {
+	Hash  string
+	Label string
+}
+
+// UniqueKeyTakenError is returned by the event store when a unique key is
+// already reserved by another resource.
+type UniqueKeyTakenError struct {
+	Key UniqueKey
+}
+
+func (e *UniqueKeyTakenError) Error() string {
+	return e.Key.Label + " is already in use"
+}
+
+// UniqueKeyReservation is the full set of unique keys a resource holds after
+// its events are stored. The event store replaces the resource's previous
+// keys with Keys in the same transaction as the events, so an empty set
+// releases them all.
+type UniqueKeyReservation struct {
+	ResourceID string
+	Keys       []UniqueKey
+}
+
+type uniqueKeysKeyType struct{}
+
+var uniqueKeysKey = &uniqueKeysKeyType{}
+
+// ContextWithUniqueKeys asks the event store to reserve keys for resourceID
+// when it next stores that resource's events.
+func ContextWithUniqueKeys(ctx context.Context, resourceID string, keys []UniqueKey) context.Context {
+	return context.WithValue(ctx, uniqueKeysKey, UniqueKeyReservation{ResourceID: resourceID, Keys: keys})
+}
+
+// UniqueKeysFromContext returns the reservation ctx carries for aggregateID.
+// ok is false when ctx has no reservation for it, and the aggregate's keys
+// are then left as they are.
+func UniqueKeysFromContext(ctx context.Context, aggregateID string) (UniqueKeyReservation, bool) {
+	r, ok := ctx.Value(uniqueKeysKey).(UniqueKeyReservation)
+	if !ok || r.ResourceID != aggregateID {
+		return UniqueKeyReservation{}, false
+	}
+	return r, true
+}
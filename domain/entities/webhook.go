@@ -0,0 +1,65 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription asks for an account's events to be POSTed to URL.
+// Events holds event type patterns ("Resource.Updated", "Resource.*", "*")
+// and TypeSlugs optionally narrows them to resources of the listed types.
+// Every delivery is signed with Secret.
+type WebhookSubscription struct {
+	ID        string
+	AccountID string
+	URL       string
+	Events    []string
+	TypeSlugs []string
+	Secret    string
+	Active    bool
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Webhook delivery statuses. A failed delivery is retried with backoff until
+// it succeeds or its job is dead-lettered.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent, or to be sent, to one subscription.
+// Payload is the exact request body, so a replay sends the same bytes.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	ResourceID     string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	// ReplayOf is the delivery this one re-sends, if any.
+	ReplayOf    string
+	CreatedAt   time.Time
+	DeliveredAt *time.Time
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"context"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// AuditRepository is the audit log read model.
+type AuditRepository interface {
+	// Record adds an entry; recording an event already in the log is a no-op.
+	Record(ctx context.Context, entry *entities.AuditEntry) error
+	// TypeSlugOf returns the type slug recorded for a resource, or "" if
+	// none is known.
+	TypeSlugOf(ctx context.Context, resourceID string) (string, error)
+	// List returns the entries matching filter, newest first.
+	List(ctx context.Context, filter entities.AuditFilter, cursor string, limit int) (
+		PaginatedResponse[*entities.AuditEntry], error)
+}
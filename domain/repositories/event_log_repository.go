@@ -0,0 +1,72 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// EventLogRepository reads the event log: every stored event in the order it
+// was appended.
+type EventLogRepository interface {
+	// Head returns the position of the newest event, or 0 when there is none.
+	Head(ctx context.Context) (int64, error)
+	// After returns up to limit events with a position after position, oldest first.
+	After(ctx context.Context, position int64, limit int) ([]entities.LoggedEvent, error)
+	// PositionsOf returns the positions of the given events. Events that
+	// aren't in the log are left out.
+	PositionsOf(ctx context.Context, eventIDs []string) (map[string]int64, error)
+	// Creations returns the first event of each of the aggregates, by
+	// aggregate ID. Aggregates without events are left out.
+	Creations(ctx context.Context, aggregateIDs []string) (map[string]domain.EventEnvelope[any], error)
+}
+
+// EventCheckpointRepository stores the position of each event handler group.
+type EventCheckpointRepository interface {
+	// Ensure returns the group's checkpoint, creating it at position when it
+	// doesn't exist yet.
+	Ensure(ctx context.Context, group string, position int64) (*entities.EventCheckpoint, error)
+	FindAll(ctx context.Context) ([]*entities.EventCheckpoint, error)
+	// Claim leases the group to owner until leaseUntil, unless another owner
+	// holds an unexpired lease. It returns the checkpoint, or nil when the
+	// group is taken.
+	Claim(ctx context.Context, group, owner string, now, leaseUntil time.Time) (*entities.EventCheckpoint, error)
+	// Release ends owner's lease on the group.
+	Release(ctx context.Context, group, owner string) error
+	// Advance moves the group from position from to to, clearing its failure
+	// state, adding skipped to its skip count and recording lastError. It
+	// reports false when the group is no longer at from.
+	Advance(ctx context.Context, group string, from, to int64, skipped int, lastError string) (bool, error)
+	// RecordFailure holds the group at its position until retryAt.
+	RecordFailure(ctx context.Context, group string, attempts int, lastError string, retryAt time.Time) error
+	// Delete removes the group's checkpoint. Deleting a missing one is not an error.
+	Delete(ctx context.Context, group string) error
+}
+
+// EventDeadLetterRepository keeps the events event handler groups gave up on.
+type EventDeadLetterRepository interface {
+	Add(ctx context.Context, event *entities.DeadLetteredEvent) error
+	// FindAll lists dead-lettered events, oldest first, of group or of every
+	// group when group is empty.
+	FindAll(ctx context.Context, group string) ([]*entities.DeadLetteredEvent, error)
+	// Delete removes a dead-lettered event. It returns ErrNotFound when there
+	// is none for the group at position.
+	Delete(ctx context.Context, group string, position int64) error
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// ErrJobLeaseLost is returned when a worker records the outcome of an attempt
+// it no longer holds: the job's lease expired and the job was claimed again.
+var ErrJobLeaseLost = errors.New("job lease lost")
+
+// JobRepository is the durable job queue.
+type JobRepository interface {
+	Enqueue(ctx context.Context, job *entities.Job) error
+	// Claim leases up to limit jobs named name that are due at now, marking
+	// them running until leaseUntil and counting the attempt. Running jobs
+	// whose lease has expired are claimable again, or buried when they have
+	// used MaxAttempts. No more than concurrency
+	// jobs named name hold an unexpired lease at once, counting those claimed
+	// by other workers.
+	Claim(ctx context.Context, name string, now, leaseUntil time.Time, limit, concurrency int) (
+		[]*entities.Job, error)
+	// Complete, Retry and Bury record the outcome of a claimed attempt. They
+	// return ErrJobLeaseLost when the job is no longer running that attempt.
+	Complete(ctx context.Context, id string, attempt int) error
+	// Retry returns a failed job to pending, to run again at runAt.
+	Retry(ctx context.Context, id string, attempt int, lastError string, runAt time.Time) error
+	// Bury moves a job that has run out of attempts to the dead-letter state.
+	Bury(ctx context.Context, id string, attempt int, lastError string) error
+	// Requeue returns a dead job to pending with its attempts reset.
+	Requeue(ctx context.Context, id string, runAt time.Time) error
+	// DeleteSucceeded removes succeeded jobs whose last run was due before
+	// before and returns how many were removed.
+	DeleteSucceeded(ctx context.Context, before time.Time) (int, error)
+	FindByID(ctx context.Context, id string) (*entities.Job, error)
+	FindAllByStatus(ctx context.Context, status, cursor string, limit int) (
+		PaginatedResponse[*entities.Job], error)
+}
+
+// JobScheduleRepository tracks when each recurring job next fires, so that
+// several workers sharing a database enqueue each run once.
+type JobScheduleRepository interface {
+	// EnsureSchedule returns the schedule's next run, creating it with
+	// firstRun when it doesn't exist yet.
+	EnsureSchedule(ctx context.Context, name string, firstRun time.Time) (time.Time, error)
+	// AdvanceSchedule moves the schedule from its due run to next. It reports
+	// false when another worker advanced it first.
+	AdvanceSchedule(ctx context.Context, name string, due, next time.Time) (bool, error)
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// PendingEventRepository tracks the events the secondary event store has not
+// acknowledged. Entries are added by the primary event store, in the same
+// transaction as the events.
+type PendingEventRepository interface {
+	// Due returns up to limit entries due at now, oldest first, each with
+	// its stored event.
+	Due(ctx context.Context, now time.Time, limit int) ([]*entities.PendingEvent, error)
+	// Ack removes the entries of events the secondary store has stored.
+	Ack(ctx context.Context, eventIDs ...string) error
+	// Fail records a failed attempt and when to try the event again.
+	Fail(ctx context.Context, eventID string, attempts int, nextAttemptAt time.Time, lastError string) error
+	// Reschedule makes every entry due at at and returns how many there are.
+	Reschedule(ctx context.Context, at time.Time) (int64, error)
+	// Backlog summarises the entries.
+	Backlog(ctx context.Context) (entities.PendingEventBacklog, error)
+}
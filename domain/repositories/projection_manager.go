@@ -84,6 +84,19 @@ type ProjectionManager interface {
 	// the source type doesn't exist yet (no projection table) should skip
 	// activation and retry after the source type is installed.
 	RegisterLink(ctx context.Context, ref LinkReference) error
+
+	// JoinReferences returns the multi-valued references declared on a type —
+	// every array property with x-resource-type. Each one is projected into
+	// its own join table instead of a column on the type's projection table.
+	JoinReferences(typeSlug string) []JoinReference
+
+	// ReverseJoinReferences returns the join references whose target is the
+	// given type. Used to propagate display value changes into join tables.
+	ReverseJoinReferences(targetTypeSlug string) []JoinReference
+
+	// UpdateJoinDisplay sets the display value on every row of the join
+	// table that points at targetID.
+	UpdateJoinDisplay(ctx context.Context, ref JoinReference, targetID string, display any) error
 }
 
 // LinkReference is the cross-type-link equivalent of a ForwardReference,
@@ -131,3 +144,14 @@ type ForwardReference struct {
 	TargetTypeSlug  string // e.g. "course" — the type being referenced
 	DisplayProperty string // e.g. "name" — property to read from the referenced row
 }
+
+// JoinReference describes an array-valued x-resource-type property. Its values
+// live in a join table with one row per (source_id, target_id), ordered by
+// position, with the target's display value denormalized onto each row.
+type JoinReference struct {
+	SourceTypeSlug  string // e.g. "recipe"
+	PropertyName    string // e.g. "suitableForDiet" — the schema property
+	TableName       string // e.g. "recipe_suitable_for_diet_refs"
+	TargetTypeSlug  string // e.g. "restricted-diet"
+	DisplayProperty string // e.g. "name" — property to read from the target
+}
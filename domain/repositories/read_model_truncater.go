@@ -0,0 +1,29 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import "context"
+
+// ReadModelTruncater clears read models so they can be rebuilt from the
+// event store.
+type ReadModelTruncater interface {
+	// TruncateResources removes the resources of typeSlug, or of every type
+	// when it is empty, from the resources table and from the projection and
+	// join tables they are projected into.
+	TruncateResources(ctx context.Context, typeSlug string) error
+	// TruncateTriples removes every triple.
+	TruncateTriples(ctx context.Context) error
+}
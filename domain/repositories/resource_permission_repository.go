@@ -10,6 +10,9 @@ import (
 type ResourcePermissionRepository interface {
 	Grant(ctx context.Context, perm *entities.ResourcePermission) error
 	Revoke(ctx context.Context, resourceID, agentID string) error
+	RevokeTeam(ctx context.Context, resourceID, teamID string) error
 	FindByResource(ctx context.Context, resourceID string) ([]*entities.ResourcePermission, error)
+	// HasPermission reports whether the agent holds action on the resource,
+	// through a grant to them or to a team they belong to.
 	HasPermission(ctx context.Context, resourceID, agentID, action string) (bool, error)
 }
@@ -28,6 +28,11 @@ import (
 // sentinel and fall back to the canonical entity path.
 var ErrNoProjectionTable = errors.New("no projection table for type")
 
+// ErrInvalidFilter is returned by filtered reads given a filter they cannot
+// apply: an unknown operator, or a field the type's table has no column for.
+// Such filters are refused rather than dropped, which would widen the result.
+var ErrInvalidFilter = errors.New("filter cannot be applied")
+
 // SortOptions configures sorting for list queries.
 type SortOptions struct {
 	SortBy    string // column name in camelCase (e.g. "submittedAt"), default "id"
@@ -39,7 +44,20 @@ type SortOptions struct {
 type VisibilityScope struct {
 	AgentID   string
 	AccountID string
-	IsAdmin   bool
+	// IsAdmin lifts the per-agent filter: every resource is visible, limited
+	// to AccountID when it is set.
+	IsAdmin bool
+	// Inherit also shows resources whose x-inherit-permissions-from parents
+	// are visible to the agent.
+	Inherit []PermissionInheritance
+}
+
+// PermissionInheritance is one x-inherit-permissions-from step of a list
+// scope: a resource is visible when a resource it references by Predicate is
+// visible, directly or through that resource's own Parents.
+type PermissionInheritance struct {
+	Predicate string
+	Parents   []PermissionInheritance
 }
 
 type ResourceRepository interface {
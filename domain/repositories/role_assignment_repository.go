@@ -0,0 +1,38 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"context"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// RoleAssignmentRepository stores scoped role assignments.
+type RoleAssignmentRepository interface {
+	Save(ctx context.Context, assignment *entities.RoleAssignment) error
+	FindByID(ctx context.Context, id string) (*entities.RoleAssignment, error)
+	FindByAgent(ctx context.Context, agentID string) ([]*entities.RoleAssignment, error)
+	// FindByAccount returns the assignments bound to an account; global
+	// assignments are bound to none.
+	FindByAccount(ctx context.Context, accountID string) ([]*entities.RoleAssignment, error)
+	Delete(ctx context.Context, id string) error
+	// DeleteByType revokes the type-scoped assignments to typeSlug and
+	// DeleteByResource the subtree-scoped ones rooted at resourceID, once
+	// their scope is gone. Both return the number revoked.
+	DeleteByType(ctx context.Context, typeSlug string) (int64, error)
+	DeleteByResource(ctx context.Context, resourceID string) (int64, error)
+}
@@ -22,9 +22,20 @@ import (
 	"github.com/wepala/weos/v3/infrastructure/models"
 )
 
-// RoleResourceAccessRepository manages the role-to-resource-type access configuration.
+// RoleResourceAccessRepository manages the role-to-resource-type access configuration
+// and the per-property field rules that narrow it, along with the explicit
+// deny rules and the inheritance between roles. Each account may have its
+// own configuration; accounts without one use the global default, stored
+// under the empty account ID.
 type RoleResourceAccessRepository interface {
-	Get(ctx context.Context) (*models.RoleResourceAccess, error)
-	Save(ctx context.Context, settings *models.RoleResourceAccess) error
-	GetAccessMap(ctx context.Context) (entities.AccessMap, error)
+	// Get returns the account's configuration, or the global default when it
+	// has none; the returned AccountID tells which.
+	Get(ctx context.Context, accountID string) (*models.RoleResourceAccess, error)
+	Save(ctx context.Context, accountID string, settings *models.RoleResourceAccess) error
+	// Accounts returns the IDs of the accounts with a configuration of their own.
+	Accounts(ctx context.Context) ([]string, error)
+	GetAccessMap(ctx context.Context, accountID string) (entities.AccessMap, error)
+	GetFieldAccessMap(ctx context.Context, accountID string) (entities.FieldAccessMap, error)
+	GetDenyMap(ctx context.Context, accountID string) (entities.AccessMap, error)
+	GetRoleHierarchy(ctx context.Context, accountID string) (entities.RoleHierarchy, error)
 }
@@ -21,9 +21,11 @@ import (
 	"github.com/wepala/weos/v3/infrastructure/models"
 )
 
-// RoleSettingsRepository manages the configured role names.
+// RoleSettingsRepository manages the configured role names. Each account may
+// have its own list; accounts without one use the global default, stored
+// under the empty account ID.
 type RoleSettingsRepository interface {
-	Get(ctx context.Context) (*models.RoleSettings, error)
-	Save(ctx context.Context, settings *models.RoleSettings) error
-	GetRoleNames(ctx context.Context) ([]string, error)
+	Get(ctx context.Context, accountID string) (*models.RoleSettings, error)
+	Save(ctx context.Context, accountID string, settings *models.RoleSettings) error
+	GetRoleNames(ctx context.Context, accountID string) ([]string, error)
 }
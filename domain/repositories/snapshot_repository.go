@@ -0,0 +1,33 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"context"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// SnapshotRepository stores the latest snapshot of each aggregate.
+type SnapshotRepository interface {
+	// Latest returns the aggregate's snapshot if it has schemaVersion, and
+	// ErrNotFound otherwise.
+	Latest(ctx context.Context, aggregateID string, schemaVersion int) (*entities.AggregateSnapshot, error)
+	// Save replaces the aggregate's snapshot.
+	Save(ctx context.Context, snapshot *entities.AggregateSnapshot) error
+	// Delete removes the aggregate's snapshot, if it has one.
+	Delete(ctx context.Context, aggregateID string) error
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"context"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// SubjectKeyRepository stores the keys that encrypt personal data in events.
+type SubjectKeyRepository interface {
+	// Latest returns the subject's newest key, or ErrNotFound.
+	Latest(ctx context.Context, subjectID string) (*entities.SubjectKey, error)
+	Save(ctx context.Context, key *entities.SubjectKey) error
+	// FindByIDs returns the keys that still exist among ids, by ID.
+	FindByIDs(ctx context.Context, ids []string) (map[string]*entities.SubjectKey, error)
+	// DeleteBySubject destroys every key of the subject and returns how
+	// many there were.
+	DeleteBySubject(ctx context.Context, subjectID string) (int64, error)
+}
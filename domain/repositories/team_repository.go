@@ -0,0 +1,38 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"context"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// TeamRepository stores teams and their members.
+type TeamRepository interface {
+	Save(ctx context.Context, team *entities.Team) error
+	Update(ctx context.Context, team *entities.Team) error
+	FindByID(ctx context.Context, id string) (*entities.Team, error)
+	FindAllByAccount(ctx context.Context, accountID, cursor string, limit int) (
+		PaginatedResponse[*entities.Team], error)
+	// Delete removes the team along with its members and the resource
+	// permissions granted to it.
+	Delete(ctx context.Context, id string) error
+	// AddMember adds an agent to a team; adding an existing member is a no-op.
+	AddMember(ctx context.Context, member *entities.TeamMember) error
+	RemoveMember(ctx context.Context, teamID, agentID string) error
+	FindMembers(ctx context.Context, teamID string) ([]*entities.TeamMember, error)
+}
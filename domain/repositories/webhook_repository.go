@@ -0,0 +1,46 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package repositories
+
+import (
+	"context"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// WebhookSubscriptionRepository stores outbound webhook subscriptions.
+type WebhookSubscriptionRepository interface {
+	Save(ctx context.Context, sub *entities.WebhookSubscription) error
+	Update(ctx context.Context, sub *entities.WebhookSubscription) error
+	FindByID(ctx context.Context, id string) (*entities.WebhookSubscription, error)
+	FindAllByAccount(ctx context.Context, accountID, cursor string, limit int) (
+		PaginatedResponse[*entities.WebhookSubscription], error)
+	// FindActive returns every active subscription, across accounts.
+	FindActive(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// WebhookDeliveryRepository is the webhook delivery log.
+type WebhookDeliveryRepository interface {
+	Save(ctx context.Context, delivery *entities.WebhookDelivery) error
+	FindByID(ctx context.Context, id string) (*entities.WebhookDelivery, error)
+	// RecordAttempt stores the outcome of one delivery attempt and bumps its
+	// attempt count.
+	RecordAttempt(ctx context.Context, id, status string, responseStatus int, lastError string) error
+	// FindAllBySubscription lists a subscription's deliveries, newest first.
+	FindAllBySubscription(ctx context.Context, subscriptionID, cursor string, limit int) (
+		PaginatedResponse[*entities.WebhookDelivery], error)
+}
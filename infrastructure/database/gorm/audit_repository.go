@@ -0,0 +1,127 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+type AuditRepositoryResult struct {
+	fx.Out
+	Repository repositories.AuditRepository
+}
+
+func ProvideAuditRepository(db *gorm.DB) (AuditRepositoryResult, error) {
+	return AuditRepositoryResult{
+		Repository: &AuditRepository{db: db},
+	}, nil
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry *entities.AuditEntry) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoNothing: true,
+	}).Create(models.FromAuditEntry(entry)).Error
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) TypeSlugOf(ctx context.Context, resourceID string) (string, error) {
+	var row models.AuditEntry
+	err := r.db.WithContext(ctx).Select("type_slug").
+		Where("resource_id = ? AND type_slug <> ''", resourceID).
+		Order("seq ASC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up audited resource type: %w", err)
+	}
+	return row.TypeSlug, nil
+}
+
+func (r *AuditRepository) List(
+	ctx context.Context, filter entities.AuditFilter, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.AuditEntry], error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := r.db.WithContext(ctx).Model(&models.AuditEntry{})
+	for column, value := range map[string]string{
+		"account_id":  filter.AccountID,
+		"agent_id":    filter.AgentID,
+		"resource_id": filter.ResourceID,
+		"type_slug":   filter.TypeSlug,
+		"event_type":  filter.EventType,
+	} {
+		if value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+	if cursor != "" {
+		seq, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return repositories.PaginatedResponse[*entities.AuditEntry]{},
+				fmt.Errorf("invalid audit cursor %q", cursor)
+		}
+		query = query.Where("seq < ?", seq)
+	}
+	var rows []models.AuditEntry
+	if err := query.Order("seq DESC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return repositories.PaginatedResponse[*entities.AuditEntry]{},
+			fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	page := repositories.PaginatedResponse[*entities.AuditEntry]{
+		Data:    make([]*entities.AuditEntry, 0, len(rows)),
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	for i := range rows {
+		page.Data = append(page.Data, rows[i].ToEntity())
+	}
+	if hasMore {
+		page.Cursor = strconv.FormatInt(rows[len(rows)-1].Seq, 10)
+	}
+	return page, nil
+}
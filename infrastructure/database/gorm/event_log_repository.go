@@ -0,0 +1,368 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventLogRepository struct {
+	db      *gorm.DB
+	schemas *entities.EventSchemaRegistry
+	cipher  entities.PIICipher
+}
+
+type EventLogRepositoryResult struct {
+	fx.Out
+	Repository repositories.EventLogRepository
+}
+
+func ProvideEventLogRepository(params struct {
+	fx.In
+	DB      *gorm.DB
+	Schemas *entities.EventSchemaRegistry
+	Cipher  entities.PIICipher `optional:"true"`
+}) (EventLogRepositoryResult, error) {
+	return EventLogRepositoryResult{
+		Repository: &EventLogRepository{db: params.DB, schemas: params.Schemas, cipher: params.Cipher},
+	}, nil
+}
+
+func (r *EventLogRepository) Head(ctx context.Context) (int64, error) {
+	var head *int64
+	if err := r.db.WithContext(ctx).Model(&models.EventLogEntry{}).
+		Select("MAX(position)").Scan(&head).Error; err != nil {
+		return 0, fmt.Errorf("failed to read event log head: %w", err)
+	}
+	if head == nil {
+		return 0, nil
+	}
+	return *head, nil
+}
+
+func (r *EventLogRepository) After(
+	ctx context.Context, position int64, limit int,
+) ([]entities.LoggedEvent, error) {
+	var entries []models.EventLogEntry
+	if err := r.db.WithContext(ctx).Where("position > ?", position).
+		Order("position ASC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.EventID
+	}
+	var rows []infrastructure.GormEventModel
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load logged events: %w", err)
+	}
+	byID := make(map[string]infrastructure.GormEventModel, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+	envelopes := make([]domain.EventEnvelope[any], len(entries))
+	for i, e := range entries {
+		row, ok := byID[e.EventID]
+		if !ok {
+			return nil, fmt.Errorf("event %s at position %d is missing from the event store",
+				e.EventID, e.Position)
+		}
+		envelopes[i] = eventEnvelope(row)
+	}
+	envelopes, err := r.open(ctx, envelopes)
+	if err != nil {
+		return nil, err
+	}
+	logged := make([]entities.LoggedEvent, 0, len(entries))
+	for i, e := range entries {
+		event, err := r.schemas.Upcast(envelopes[i])
+		if err != nil {
+			return nil, fmt.Errorf("event at position %d: %w", e.Position, err)
+		}
+		logged = append(logged, entities.LoggedEvent{
+			Position: e.Position,
+			LoggedAt: e.CreatedAt,
+			Event:    event,
+		})
+	}
+	return logged, nil
+}
+
+// open decrypts the personal data of stored events. It runs before
+// upcasting, as the event store decorators do, so upcasters see the values
+// they were written for.
+func (r *EventLogRepository) open(
+	ctx context.Context, envelopes []domain.EventEnvelope[any],
+) ([]domain.EventEnvelope[any], error) {
+	if r.cipher == nil {
+		return envelopes, nil
+	}
+	opened, err := r.cipher.Open(ctx, envelopes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt logged events: %w", err)
+	}
+	return opened, nil
+}
+
+func (r *EventLogRepository) Creations(
+	ctx context.Context, aggregateIDs []string,
+) (map[string]domain.EventEnvelope[any], error) {
+	creations := make(map[string]domain.EventEnvelope[any], len(aggregateIDs))
+	if len(aggregateIDs) == 0 {
+		return creations, nil
+	}
+	var rows []infrastructure.GormEventModel
+	if err := r.db.WithContext(ctx).Where("aggregate_id IN ? AND sequence_no = 1", aggregateIDs).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load creating events: %w", err)
+	}
+	envelopes := make([]domain.EventEnvelope[any], len(rows))
+	for i, row := range rows {
+		envelopes[i] = eventEnvelope(row)
+	}
+	envelopes, err := r.open(ctx, envelopes)
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range envelopes {
+		event, err := r.schemas.Upcast(env)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", env.ID, err)
+		}
+		creations[event.AggregateID] = event
+	}
+	return creations, nil
+}
+
+func (r *EventLogRepository) PositionsOf(ctx context.Context, eventIDs []string) (map[string]int64, error) {
+	positions := make(map[string]int64, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return positions, nil
+	}
+	var entries []models.EventLogEntry
+	err := r.db.WithContext(ctx).Select("event_id", "position").
+		Where("event_id IN ?", eventIDs).Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find event positions: %w", err)
+	}
+	for _, e := range entries {
+		positions[e.EventID] = e.Position
+	}
+	return positions, nil
+}
+
+type EventCheckpointRepository struct {
+	db *gorm.DB
+}
+
+type EventCheckpointRepositoryResult struct {
+	fx.Out
+	Repository repositories.EventCheckpointRepository
+}
+
+func ProvideEventCheckpointRepository(db *gorm.DB) (EventCheckpointRepositoryResult, error) {
+	return EventCheckpointRepositoryResult{
+		Repository: &EventCheckpointRepository{db: db},
+	}, nil
+}
+
+func (r *EventCheckpointRepository) Ensure(
+	ctx context.Context, group string, position int64,
+) (*entities.EventCheckpoint, error) {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.EventCheckpoint{Group: group, Position: position, UpdatedAt: time.Now().UTC()}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event checkpoint %q: %w", group, err)
+	}
+	return r.find(ctx, group)
+}
+
+func (r *EventCheckpointRepository) find(ctx context.Context, group string) (*entities.EventCheckpoint, error) {
+	var row models.EventCheckpoint
+	err := r.db.WithContext(ctx).Where("group_name = ?", group).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("event checkpoint %q: %w", group, repositories.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load event checkpoint %q: %w", group, err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *EventCheckpointRepository) FindAll(ctx context.Context) ([]*entities.EventCheckpoint, error) {
+	var rows []models.EventCheckpoint
+	if err := r.db.WithContext(ctx).Order("group_name ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list event checkpoints: %w", err)
+	}
+	checkpoints := make([]*entities.EventCheckpoint, len(rows))
+	for i := range rows {
+		checkpoints[i] = rows[i].ToEntity()
+	}
+	return checkpoints, nil
+}
+
+func (r *EventCheckpointRepository) Claim(
+	ctx context.Context, group, owner string, now, leaseUntil time.Time,
+) (*entities.EventCheckpoint, error) {
+	result := r.db.WithContext(ctx).Model(&models.EventCheckpoint{}).
+		Where("group_name = ? AND (locked_by = '' OR locked_by = ? OR locked_until < ?)",
+			group, owner, now.UTC()).
+		Updates(map[string]any{"locked_by": owner, "locked_until": leaseUntil.UTC()})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to claim event checkpoint %q: %w", group, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return r.find(ctx, group)
+}
+
+func (r *EventCheckpointRepository) Release(ctx context.Context, group, owner string) error {
+	err := r.db.WithContext(ctx).Model(&models.EventCheckpoint{}).
+		Where("group_name = ? AND locked_by = ?", group, owner).
+		Updates(map[string]any{"locked_by": "", "locked_until": time.Time{}}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release event checkpoint %q: %w", group, err)
+	}
+	return nil
+}
+
+func (r *EventCheckpointRepository) Advance(
+	ctx context.Context, group string, from, to int64, skipped int, lastError string,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.EventCheckpoint{}).
+		Where("group_name = ? AND position = ?", group, from).
+		Updates(map[string]any{
+			"position":   to,
+			"attempts":   0,
+			"retry_at":   time.Time{},
+			"last_error": lastError,
+			"skipped":    gorm.Expr("skipped + ?", skipped),
+			"updated_at": time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to advance event checkpoint %q: %w", group, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *EventCheckpointRepository) RecordFailure(
+	ctx context.Context, group string, attempts int, lastError string, retryAt time.Time,
+) error {
+	err := r.db.WithContext(ctx).Model(&models.EventCheckpoint{}).
+		Where("group_name = ?", group).
+		Updates(map[string]any{
+			"attempts":   attempts,
+			"last_error": lastError,
+			"retry_at":   retryAt.UTC(),
+			"updated_at": time.Now().UTC(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record event checkpoint failure %q: %w", group, err)
+	}
+	return nil
+}
+
+func (r *EventCheckpointRepository) Delete(ctx context.Context, group string) error {
+	err := r.db.WithContext(ctx).Where("group_name = ?", group).Delete(&models.EventCheckpoint{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete event checkpoint %q: %w", group, err)
+	}
+	return nil
+}
+
+type EventDeadLetterRepository struct {
+	db *gorm.DB
+}
+
+type EventDeadLetterRepositoryResult struct {
+	fx.Out
+	Repository repositories.EventDeadLetterRepository
+}
+
+func ProvideEventDeadLetterRepository(db *gorm.DB) (EventDeadLetterRepositoryResult, error) {
+	return EventDeadLetterRepositoryResult{
+		Repository: &EventDeadLetterRepository{db: db},
+	}, nil
+}
+
+// Add records the event, replacing an earlier record of the same group and
+// position.
+func (r *EventDeadLetterRepository) Add(ctx context.Context, event *entities.DeadLetteredEvent) error {
+	row := &models.EventDeadLetter{
+		Group:     event.Group,
+		Position:  event.Position,
+		EventID:   event.EventID,
+		EventType: event.EventType,
+		Attempts:  event.Attempts,
+		LastError: event.LastError,
+		CreatedAt: time.Now().UTC(),
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_name"}, {Name: "position"}},
+		DoUpdates: clause.AssignmentColumns([]string{"attempts", "last_error", "created_at"}),
+	}).Create(row).Error
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter event %s: %w", event.EventID, err)
+	}
+	return nil
+}
+
+func (r *EventDeadLetterRepository) FindAll(
+	ctx context.Context, group string,
+) ([]*entities.DeadLetteredEvent, error) {
+	query := r.db.WithContext(ctx)
+	if group != "" {
+		query = query.Where("group_name = ?", group)
+	}
+	var rows []models.EventDeadLetter
+	if err := query.Order("position ASC, group_name ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list dead-lettered events: %w", err)
+	}
+	events := make([]*entities.DeadLetteredEvent, len(rows))
+	for i := range rows {
+		events[i] = rows[i].ToEntity()
+	}
+	return events, nil
+}
+
+func (r *EventDeadLetterRepository) Delete(ctx context.Context, group string, position int64) error {
+	result := r.db.WithContext(ctx).
+		Where("group_name = ? AND position = ?", group, position).
+		Delete(&models.EventDeadLetter{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete dead-lettered event: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("dead-lettered event %s@%d: %w", group, position, repositories.ErrNotFound)
+	}
+	return nil
+}
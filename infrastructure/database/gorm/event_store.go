@@ -0,0 +1,249 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pendingEventGrace is how long a new pending entry waits before the
+// background resend may pick it up, leaving the first attempt to the write
+// that stored it.
+const pendingEventGrace = time.Minute
+
+// EventStore is the GORM event store with an event log. Append writes each
+// event's event_log row in the same transaction as the event, so every
+// stored event has a position for event handler groups to checkpoint
+// against. Reads are served by the embedded store.
+type EventStore struct {
+	*infrastructure.GormEventStore
+	db *gorm.DB
+
+	// RecordPending also records every appended event as pending for the
+	// secondary event store, in the same transaction, so an event is never
+	// lost to it even if the process stops before sending it.
+	RecordPending bool
+}
+
+// NewEventStore wraps the GORM event store. The first time it opens a
+// database it logs the stored events that predate the event log, oldest
+// first; every event appended since is logged by Append.
+func NewEventStore(db *gorm.DB) (*EventStore, error) {
+	store, err := infrastructure.NewGormEventStore(db)
+	if err != nil {
+		return nil, err
+	}
+	err = runMigration(db, "event_log.backfill", func(tx *gorm.DB) error {
+		return tx.Exec(`INSERT INTO event_log (event_id, aggregate_id, sequence_no, event_type, created_at)
+			SELECT e.id, e.aggregate_id, e.sequence_no, e.event_type, e.created_at FROM events e
+			WHERE NOT EXISTS (SELECT 1 FROM event_log l WHERE l.event_id = e.id)
+			ORDER BY e.created_at, e.aggregate_id, e.sequence_no`).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill event log: %w", err)
+	}
+	return &EventStore{GormEventStore: store, db: db}, nil
+}
+
+// Append stores events for one aggregate. If expectedVersion is not -1 the
+// aggregate must be at that version.
+func (s *EventStore) Append(
+	ctx context.Context, aggregateID string, expectedVersion int, events ...domain.EventEnvelope[any],
+) error {
+	if len(events) == 0 {
+		return nil
+	}
+	rows := make([]infrastructure.GormEventModel, len(events))
+	entries := make([]models.EventLogEntry, len(events))
+	var pending []models.PendingEvent
+	now := time.Now().UTC()
+	for i, event := range events {
+		if event.AggregateID != aggregateID {
+			return fmt.Errorf("%w: aggregate ID mismatch", domain.ErrInvalidEvent)
+		}
+		if event.ID == "" {
+			return fmt.Errorf("%w: event ID is required", domain.ErrInvalidEvent)
+		}
+		row, err := eventModel(event)
+		if err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidEvent, err)
+		}
+		rows[i] = row
+		entries[i] = models.EventLogEntry{
+			EventID:     event.ID,
+			AggregateID: aggregateID,
+			SequenceNo:  event.SequenceNo,
+			EventType:   event.EventType,
+			CreatedAt:   now,
+		}
+		if s.RecordPending {
+			pending = append(pending, models.PendingEvent{
+				EventID:       event.ID,
+				AggregateID:   aggregateID,
+				NextAttemptAt: now.Add(pendingEventGrace),
+				CreatedAt:     now,
+			})
+		}
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if expectedVersion != -1 {
+			var current *int
+			if err := tx.Model(&infrastructure.GormEventModel{}).
+				Where("aggregate_id = ?", aggregateID).
+				Select("MAX(sequence_no)").
+				Scan(&current).Error; err != nil {
+				return fmt.Errorf("failed to check current version: %w", err)
+			}
+			version := 0
+			if current != nil {
+				version = *current
+			}
+			if version != expectedVersion {
+				return fmt.Errorf("%w: expected version %d, got %d",
+					domain.ErrConcurrencyConflict, expectedVersion, version)
+			}
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&entries).Error; err != nil {
+			return err
+		}
+		if reservation, ok := entities.UniqueKeysFromContext(ctx, aggregateID); ok {
+			if err := reserveUniqueKeys(tx, reservation, now); err != nil {
+				return err
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		return tx.Create(&pending).Error
+	})
+}
+
+// reserveUniqueKeys replaces a resource's unique keys with the reservation's.
+// A key another resource holds fails the transaction, so the events that
+// would have given this resource the same value are never stored. On
+// PostgreSQL the insert waits for a concurrent transaction holding the same
+// key to finish; SQLite serializes writers outright.
+func reserveUniqueKeys(tx *gorm.DB, reservation entities.UniqueKeyReservation, now time.Time) error {
+	keep := make([]string, 0, len(reservation.Keys))
+	for _, key := range reservation.Keys {
+		keep = append(keep, key.Hash)
+	}
+	release := tx.Where("resource_id = ?", reservation.ResourceID)
+	if len(keep) > 0 {
+		release = release.Where("hash NOT IN ?", keep)
+	}
+	if err := release.Delete(&models.UniqueKey{}).Error; err != nil {
+		return fmt.Errorf("failed to release unique keys: %w", err)
+	}
+	for _, key := range reservation.Keys {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.UniqueKey{
+			Hash: key.Hash, ResourceID: reservation.ResourceID, CreatedAt: now,
+		})
+		if res.Error != nil {
+			return fmt.Errorf("failed to reserve unique key: %w", res.Error)
+		}
+		if res.RowsAffected > 0 {
+			continue
+		}
+		var holder models.UniqueKey
+		if err := tx.Where("hash = ?", key.Hash).First(&holder).Error; err != nil {
+			return fmt.Errorf("failed to read unique key: %w", err)
+		}
+		if holder.ResourceID != reservation.ResourceID {
+			return &entities.UniqueKeyTakenError{Key: key}
+		}
+	}
+	return nil
+}
+
+// eventModel converts an envelope to its row, storing payload and metadata
+// as JSON objects the way the embedded store does.
+func eventModel(env domain.EventEnvelope[any]) (infrastructure.GormEventModel, error) {
+	payload, err := toJSONB(env.Payload)
+	if err != nil {
+		return infrastructure.GormEventModel{}, fmt.Errorf("failed to convert payload: %w", err)
+	}
+	metadata, err := toJSONB(env.Metadata)
+	if err != nil {
+		return infrastructure.GormEventModel{}, fmt.Errorf("failed to convert metadata: %w", err)
+	}
+	return infrastructure.GormEventModel{
+		ID:            env.ID,
+		AggregateID:   env.AggregateID,
+		EventType:     env.EventType,
+		SequenceNo:    env.SequenceNo,
+		TransactionID: env.TransactionID,
+		Payload:       payload,
+		Metadata:      metadata,
+		CreatedAt:     env.Created,
+	}, nil
+}
+
+func toJSONB(v any) (infrastructure.JSONB, error) {
+	switch p := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return p, nil
+	default:
+		data, err := json.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %T: %w", p, err)
+		}
+		var m infrastructure.JSONB
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %T to map: %w", p, err)
+		}
+		return m, nil
+	}
+}
+
+// eventEnvelope converts a stored row back to an envelope. Payloads come back
+// as map[string]any, as from the embedded store.
+func eventEnvelope(m infrastructure.GormEventModel) domain.EventEnvelope[any] {
+	var payload any
+	if m.Payload != nil {
+		payload = map[string]any(m.Payload)
+	}
+	metadata := map[string]any{}
+	if m.Metadata != nil {
+		metadata = m.Metadata
+	}
+	return domain.EventEnvelope[any]{
+		ID:            m.ID,
+		AggregateID:   m.AggregateID,
+		EventType:     m.EventType,
+		Payload:       payload,
+		Created:       m.CreatedAt,
+		SequenceNo:    m.SequenceNo,
+		TransactionID: m.TransactionID,
+		Metadata:      metadata,
+	}
+}
@@ -3,8 +3,9 @@ package gorm
 import (
 	"fmt"
 
+	"github.com/wepala/weos/v3/internal/config"
+
 	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
-	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
 	"go.uber.org/fx"
 	"gorm.io/gorm"
 )
@@ -15,15 +16,18 @@ type EventStoreResult struct {
 	EventStore domain.EventStore
 }
 
-// ProvideEventStore creates a GORM-backed event store.
-// This auto-migrates the events table.
+// ProvideEventStore creates a GORM-backed event store that logs each event's
+// position. This auto-migrates the events table. When a secondary (BigQuery)
+// event store is configured, every event is also recorded as pending for it.
 func ProvideEventStore(params struct {
 	fx.In
-	DB *gorm.DB
+	DB     *gorm.DB
+	Config config.Config
 }) (EventStoreResult, error) {
-	store, err := infrastructure.NewGormEventStore(params.DB)
+	store, err := NewEventStore(params.DB)
 	if err != nil {
 		return EventStoreResult{}, fmt.Errorf("failed to create event store: %w", err)
 	}
+	store.RecordPending = params.Config.BigQueryProjectID != ""
 	return EventStoreResult{EventStore: store}, nil
 }
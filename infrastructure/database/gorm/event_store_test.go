@@ -0,0 +1,336 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/events"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
+	"gorm.io/gorm"
+)
+
+func newEventLogTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	// One connection, so every query sees the same in-memory database.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.EventLogEntry{}, &models.EventCheckpoint{}, &models.EventDeadLetter{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	return db
+}
+
+func testEvent(aggregateID, id, eventType string, seq int) domain.EventEnvelope[any] {
+	return domain.EventEnvelope[any]{
+		ID:          id,
+		AggregateID: aggregateID,
+		EventType:   eventType,
+		Payload:     map[string]any{"Name": id},
+		Created:     time.Now().UTC(),
+		SequenceNo:  seq,
+		Metadata:    map[string]any{},
+	}
+}
+
+func TestEventStore_AppendLogsPositions(t *testing.T) {
+	t.Parallel()
+	db := newEventLogTestDB(t)
+	store, err := NewEventStore(db)
+	if err != nil {
+		t.Fatalf("NewEventStore: %v", err)
+	}
+	log := &EventLogRepository{db: db}
+	ctx := context.Background()
+
+	if err := store.Append(ctx, "urn:a", 0,
+		testEvent("urn:a", "e1", "Resource.Created", 1),
+		testEvent("urn:a", "e2", "Resource.Published", 2),
+	); err != nil {
+		t.Fatalf("Append a: %v", err)
+	}
+	if err := store.Append(ctx, "urn:b", -1, testEvent("urn:b", "e3", "Resource.Created", 1)); err != nil {
+		t.Fatalf("Append b: %v", err)
+	}
+
+	head, err := log.Head(ctx)
+	if err != nil || head != 3 {
+		t.Fatalf("Head = %d, %v; want 3", head, err)
+	}
+	logged, err := log.After(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("After: %v", err)
+	}
+	if len(logged) != 2 || logged[0].Event.ID != "e2" || logged[1].Event.ID != "e3" ||
+		logged[1].Position != 3 {
+		t.Fatalf("After(1) = %+v, want e2 then e3 at position 3", logged)
+	}
+	if name, _ := logged[0].Event.Payload.(map[string]any)["Name"].(string); name != "e2" {
+		t.Errorf("logged payload = %v", logged[0].Event.Payload)
+	}
+	positions, err := log.PositionsOf(ctx, []string{"e3", "e1", "missing"})
+	if err != nil || len(positions) != 2 || positions["e3"] != 3 || positions["e1"] != 1 {
+		t.Errorf("PositionsOf = %v, %v; want e1 at 1 and e3 at 3", positions, err)
+	}
+
+	// Reads still come from the event store.
+	events, err := store.GetEvents(ctx, "urn:a")
+	if err != nil || len(events) != 2 {
+		t.Fatalf("GetEvents = %d events, %v", len(events), err)
+	}
+}
+
+func TestEventStore_ReservesUniqueKeys(t *testing.T) {
+	t.Parallel()
+	db := newEventLogTestDB(t)
+	if err := db.AutoMigrate(&models.UniqueKey{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	store, err := NewEventStore(db)
+	if err != nil {
+		t.Fatalf("NewEventStore: %v", err)
+	}
+	sku := entities.UniqueKey{Hash: "h-sku", Label: `sku "ABC-1"`}
+	reserve := func(id string, keys ...entities.UniqueKey) context.Context {
+		return entities.ContextWithUniqueKeys(context.Background(), id, keys)
+	}
+
+	if err := store.Append(reserve("urn:a", sku), "urn:a", 0,
+		testEvent("urn:a", "e1", "Resource.Created", 1)); err != nil {
+		t.Fatalf("Append a: %v", err)
+	}
+	err = store.Append(reserve("urn:b", sku), "urn:b", 0, testEvent("urn:b", "e2", "Resource.Created", 1))
+	var taken *entities.UniqueKeyTakenError
+	if !errors.As(err, &taken) || taken.Key.Label != sku.Label {
+		t.Fatalf("Append b err = %v, want UniqueKeyTakenError for the sku", err)
+	}
+	if events, _ := store.GetEvents(context.Background(), "urn:b"); len(events) != 0 {
+		t.Errorf("b has %d stored events after a refused reservation, want 0", len(events))
+	}
+
+	// Storing a again with the same key keeps it; storing it with none
+	// releases it for b.
+	if err := store.Append(reserve("urn:a", sku), "urn:a", 1,
+		testEvent("urn:a", "e3", "Resource.Updated", 2)); err != nil {
+		t.Fatalf("Append a again: %v", err)
+	}
+	if err := store.Append(reserve("urn:a"), "urn:a", 2,
+		testEvent("urn:a", "e4", "Resource.Deleted", 3)); err != nil {
+		t.Fatalf("Append a delete: %v", err)
+	}
+	if err := store.Append(reserve("urn:b", sku), "urn:b", 0,
+		testEvent("urn:b", "e5", "Resource.Created", 1)); err != nil {
+		t.Fatalf("Append b after release: %v", err)
+	}
+}
+
+func TestEventStore_ConflictLogsNothing(t *testing.T) {
+	t.Parallel()
+	db := newEventLogTestDB(t)
+	store, err := NewEventStore(db)
+	if err != nil {
+		t.Fatalf("NewEventStore: %v", err)
+	}
+	ctx := context.Background()
+	if err := store.Append(ctx, "urn:a", 0, testEvent("urn:a", "e1", "Resource.Created", 1)); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	err = store.Append(ctx, "urn:a", 0, testEvent("urn:a", "e2", "Resource.Updated", 2))
+	if !errors.Is(err, domain.ErrConcurrencyConflict) {
+		t.Fatalf("stale Append err = %v, want ErrConcurrencyConflict", err)
+	}
+	if head, _ := (&EventLogRepository{db: db}).Head(ctx); head != 1 {
+		t.Errorf("Head = %d after a rejected append, want 1", head)
+	}
+}
+
+func TestNewEventStore_BackfillsTheLog(t *testing.T) {
+	t.Parallel()
+	db := newEventLogTestDB(t)
+	ctx := context.Background()
+	legacy, err := infrastructure.NewGormEventStore(db)
+	if err != nil {
+		t.Fatalf("NewGormEventStore: %v", err)
+	}
+	first := testEvent("urn:a", "e1", "Resource.Created", 1)
+	second := testEvent("urn:b", "e2", "Resource.Created", 1)
+	second.Created = first.Created.Add(time.Second)
+	for _, e := range []domain.EventEnvelope[any]{second, first} {
+		if err := legacy.Append(ctx, e.AggregateID, -1, e); err != nil {
+			t.Fatalf("legacy Append: %v", err)
+		}
+	}
+
+	if _, err := NewEventStore(db); err != nil {
+		t.Fatalf("NewEventStore: %v", err)
+	}
+	logged, err := (&EventLogRepository{db: db}).After(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("After: %v", err)
+	}
+	if len(logged) != 2 || logged[0].Event.ID != "e1" || logged[1].Event.ID != "e2" {
+		t.Fatalf("backfilled log = %+v, want e1 then e2 (oldest first)", logged)
+	}
+
+	// The backfill is a one-time migration: a later start doesn't run it.
+	late := testEvent("urn:c", "e3", "Resource.Created", 1)
+	if err := legacy.Append(ctx, late.AggregateID, -1, late); err != nil {
+		t.Fatalf("legacy Append: %v", err)
+	}
+	if _, err := NewEventStore(db); err != nil {
+		t.Fatalf("NewEventStore: %v", err)
+	}
+	if head, _ := (&EventLogRepository{db: db}).Head(ctx); head != 2 {
+		t.Errorf("Head = %d after a second start, want 2", head)
+	}
+}
+
+func TestEventStore_UpcastsStoredPayloads(t *testing.T) {
+	t.Parallel()
+	db := newEventLogTestDB(t)
+	raw, err := NewEventStore(db)
+	if err != nil {
+		t.Fatalf("NewEventStore: %v", err)
+	}
+	ctx := context.Background()
+	// An event stored before its payload was versioned.
+	if err := raw.Append(ctx, "urn:a", -1, testEvent("urn:a", "e1", "Resource.Created", 1)); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	// Version 2 renamed Name to Title.
+	schemas := entities.NewEventSchemaRegistry()
+	if err := schemas.Register("Resource.Created", 1, func(p map[string]any) (map[string]any, error) {
+		p["Title"] = p["Name"]
+		delete(p, "Name")
+		return p, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	store := events.NewVersionedEventStore(raw, schemas)
+	current := testEvent("urn:b", "e2", "Resource.Created", 1)
+	current.Payload = map[string]any{"Title": "e2"}
+	if err := store.Append(ctx, "urn:b", -1, current); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	stored, _ := raw.GetEventByID(ctx, "e2")
+	if v, _ := entities.EventSchemaVersion(stored); v != 2 {
+		t.Errorf("appended event stored at version %d, want 2", v)
+	}
+	first, err := store.GetEvents(ctx, "urn:a")
+	if err != nil || len(first) != 1 {
+		t.Fatalf("GetEvents = %v, %v", first, err)
+	}
+	logged, err := (&EventLogRepository{db: db, schemas: schemas}).After(ctx, 0, 10)
+	if err != nil || len(logged) != 2 {
+		t.Fatalf("After = %v, %v", logged, err)
+	}
+	for _, env := range []domain.EventEnvelope[any]{first[0], logged[0].Event, logged[1].Event} {
+		if p := env.Payload.(map[string]any); p["Title"] != env.ID || p["Name"] != nil {
+			t.Errorf("%s payload = %v, want it upcast to Title", env.ID, p)
+		}
+	}
+}
+
+func TestEventCheckpointRepository_LeaseAndAdvance(t *testing.T) {
+	t.Parallel()
+	db := newEventLogTestDB(t)
+	repo := &EventCheckpointRepository{db: db}
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, err := repo.Ensure(ctx, "projections", 5); err != nil {
+		t.Fatalf("Ensure: %v", err)
+	}
+	cp, err := repo.Ensure(ctx, "projections", 9)
+	if err != nil || cp.Position != 5 {
+		t.Fatalf("Ensure on an existing group = %+v, %v; want position 5", cp, err)
+	}
+
+	if cp, err := repo.Claim(ctx, "projections", "relay-1", now, now.Add(time.Minute)); err != nil || cp == nil {
+		t.Fatalf("Claim relay-1 = %v, %v", cp, err)
+	}
+	if cp, _ := repo.Claim(ctx, "projections", "relay-2", now, now.Add(time.Minute)); cp != nil {
+		t.Fatal("relay-2 claimed a group relay-1 holds")
+	}
+	if cp, _ := repo.Claim(ctx, "projections", "relay-2", now.Add(2*time.Minute), now.Add(3*time.Minute)); cp == nil {
+		t.Fatal("relay-2 could not claim a group whose lease expired")
+	}
+	if err := repo.Release(ctx, "projections", "relay-2"); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	if err := repo.RecordFailure(ctx, "projections", 2, "boom", now.Add(time.Minute)); err != nil {
+		t.Fatalf("RecordFailure: %v", err)
+	}
+	if ok, _ := repo.Advance(ctx, "projections", 4, 6, 0, ""); ok {
+		t.Error("Advance from a stale position succeeded")
+	}
+	if ok, err := repo.Advance(ctx, "projections", 5, 7, 1, "skipped e6"); err != nil || !ok {
+		t.Fatalf("Advance = %v, %v", ok, err)
+	}
+	all, err := repo.FindAll(ctx)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("FindAll = %v, %v", all, err)
+	}
+	got := all[0]
+	if got.Position != 7 || got.Attempts != 0 || !got.RetryAt.IsZero() ||
+		got.Skipped != 1 || got.LastError != "skipped e6" || got.LockedBy != "" {
+		t.Errorf("checkpoint after advance = %+v", got)
+	}
+}
+
+func TestEventDeadLetterRepository(t *testing.T) {
+	t.Parallel()
+	repo := &EventDeadLetterRepository{db: newEventLogTestDB(t)}
+	ctx := context.Background()
+
+	for _, e := range []*entities.DeadLetteredEvent{
+		{Group: "webhooks", Position: 7, EventID: "e7", EventType: "Resource.Created", Attempts: 5, LastError: "timeout"},
+		{Group: "audit", Position: 3, EventID: "e3", EventType: "Resource.Updated", Attempts: 10, LastError: "boom"},
+		{Group: "webhooks", Position: 7, EventID: "e7", EventType: "Resource.Created", Attempts: 6, LastError: "refused"},
+	} {
+		if err := repo.Add(ctx, e); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	all, err := repo.FindAll(ctx, "")
+	if err != nil || len(all) != 2 || all[0].EventID != "e3" || all[1].EventID != "e7" {
+		t.Fatalf("FindAll = %+v, %v; want e3 then e7", all, err)
+	}
+	hooks, err := repo.FindAll(ctx, "webhooks")
+	if err != nil || len(hooks) != 1 || hooks[0].Attempts != 6 || hooks[0].LastError != "refused" {
+		t.Fatalf("FindAll(webhooks) = %+v, %v; want e7 as last recorded", hooks, err)
+	}
+	if err := repo.Delete(ctx, "webhooks", 7); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Delete(ctx, "webhooks", 7); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Delete of a missing event err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository struct {
+	db *gorm.DB
+}
+
+type JobRepositoryResult struct {
+	fx.Out
+	Repository repositories.JobRepository
+}
+
+func ProvideJobRepository(db *gorm.DB) (JobRepositoryResult, error) {
+	return JobRepositoryResult{
+		Repository: &JobRepository{db: db},
+	}, nil
+}
+
+func (r *JobRepository) Enqueue(ctx context.Context, job *entities.Job) error {
+	row := models.FromJob(job)
+	row.RunAt = row.RunAt.UTC()
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+// jobLeaseExpired is the last error of a job buried because its final
+// attempt's lease expired without an outcome.
+const jobLeaseExpired = "lease expired"
+
+// Claim runs in a transaction that first writes the name's job_locks row.
+// The write holds the row until commit (a row lock on Postgres, the write
+// lock on SQLite), so claims of one name take turns and each counts the
+// leases taken before it. Each job is then taken with a conditional update on
+// its attempt count.
+func (r *JobRepository) Claim(
+	ctx context.Context, name string, now, leaseUntil time.Time, limit, concurrency int,
+) ([]*entities.Job, error) {
+	now = now.UTC()
+	var claimed []*entities.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"claimed_at"}),
+		}).Create(&models.JobLock{Name: name, ClaimedAt: now}).Error
+		if err != nil {
+			return fmt.Errorf("failed to lock %q jobs: %w", name, err)
+		}
+		var running int64
+		err = tx.Model(&models.Job{}).
+			Where("name = ? AND status = ? AND locked_until >= ?", name, entities.JobRunning, now).
+			Count(&running).Error
+		if err != nil {
+			return fmt.Errorf("failed to count running jobs: %w", err)
+		}
+		// A job whose last attempt crashed or hung the worker never reports
+		// a failure, so one that has used all its attempts is buried here.
+		err = tx.Model(&models.Job{}).
+			Where("name = ? AND status = ? AND locked_until < ? AND attempts >= max_attempts",
+				name, entities.JobRunning, now).
+			Updates(map[string]any{
+				"status":     entities.JobDead,
+				"last_error": jobLeaseExpired,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to bury expired jobs: %w", err)
+		}
+		limit = min(limit, concurrency-int(running))
+		if limit <= 0 {
+			return nil
+		}
+
+		var rows []models.Job
+		err = tx.Where("name = ?", name).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ? AND attempts < max_attempts)",
+				entities.JobPending, now, entities.JobRunning, now).
+			Order("run_at ASC").
+			Limit(limit).
+			Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("failed to find due jobs: %w", err)
+		}
+		for i := range rows {
+			row := &rows[i]
+			result := tx.Model(&models.Job{}).
+				Where("id = ? AND status = ? AND attempts = ?", row.ID, row.Status, row.Attempts).
+				Updates(map[string]any{
+					"status":       entities.JobRunning,
+					"attempts":     row.Attempts + 1,
+					"locked_until": leaseUntil.UTC(),
+				})
+			if result.Error != nil {
+				return fmt.Errorf("failed to claim job %s: %w", row.ID, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				continue // another worker took it
+			}
+			row.Status = entities.JobRunning
+			row.Attempts++
+			row.LockedUntil = leaseUntil.UTC()
+			claimed = append(claimed, row.ToEntity())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (r *JobRepository) Complete(ctx context.Context, id string, attempt int) error {
+	return r.finish(ctx, id, attempt, map[string]any{
+		"status":     entities.JobSucceeded,
+		"last_error": "",
+	})
+}
+
+func (r *JobRepository) Retry(ctx context.Context, id string, attempt int, lastError string, runAt time.Time) error {
+	return r.finish(ctx, id, attempt, map[string]any{
+		"status":     entities.JobPending,
+		"run_at":     runAt.UTC(),
+		"last_error": lastError,
+	})
+}
+
+func (r *JobRepository) Bury(ctx context.Context, id string, attempt int, lastError string) error {
+	return r.finish(ctx, id, attempt, map[string]any{
+		"status":     entities.JobDead,
+		"last_error": lastError,
+	})
+}
+
+func (r *JobRepository) Requeue(ctx context.Context, id string, runAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, entities.JobDead).
+		Updates(map[string]any{
+			"status":   entities.JobPending,
+			"attempts": 0,
+			"run_at":   runAt.UTC(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to requeue job %s: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// finish updates a job only while it is still running the given attempt, so
+// a worker whose lease expired can't overwrite the outcome of the attempt that
+// replaced it.
+func (r *JobRepository) finish(ctx context.Context, id string, attempt int, updates map[string]any) error {
+	result := r.db.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", id, entities.JobRunning, attempt).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update job %s: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("job %s attempt %d: %w", id, attempt, repositories.ErrJobLeaseLost)
+	}
+	return nil
+}
+
+// DeleteSucceeded removes succeeded jobs whose last run was due before
+// before. RunAt is stored in UTC, unlike the gorm-managed timestamps.
+func (r *JobRepository) DeleteSucceeded(ctx context.Context, before time.Time) (int, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND run_at < ?", entities.JobSucceeded, before.UTC()).
+		Delete(&models.Job{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete succeeded jobs: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (r *JobRepository) FindByID(ctx context.Context, id string) (*entities.Job, error) {
+	var row models.Job
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find job: %w", err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *JobRepository) FindAllByStatus(
+	ctx context.Context, status, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.Job], error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := r.db.WithContext(ctx).Where("status = ?", status)
+	if cursor != "" {
+		query = query.Where("id > ?", cursor)
+	}
+	var rows []models.Job
+	if err := query.Order("id ASC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return repositories.PaginatedResponse[*entities.Job]{}, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	page := repositories.PaginatedResponse[*entities.Job]{
+		Data:    make([]*entities.Job, 0, len(rows)),
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	for i := range rows {
+		page.Data = append(page.Data, rows[i].ToEntity())
+	}
+	if hasMore {
+		page.Cursor = rows[len(rows)-1].ID
+	}
+	return page, nil
+}
@@ -0,0 +1,218 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+)
+
+func newJobTestRepos(t *testing.T) (*JobRepository, *JobScheduleRepository) {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Job{}, &models.JobSchedule{}, &models.JobLock{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	return &JobRepository{db: db}, &JobScheduleRepository{db: db}
+}
+
+func TestJobRepository_Lifecycle(t *testing.T) {
+	t.Parallel()
+	repo, _ := newJobTestRepos(t)
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, job := range []*entities.Job{
+		{ID: "urn:job:a", Name: "mail", Status: entities.JobPending, MaxAttempts: 3, RunAt: now},
+		{ID: "urn:job:b", Name: "mail", Status: entities.JobPending, MaxAttempts: 3, RunAt: now.Add(time.Hour)},
+	} {
+		if err := repo.Enqueue(ctx, job); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	lease := now.Add(time.Minute)
+	claimed, err := repo.Claim(ctx, "mail", now, lease, 5, 5)
+	if err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != "urn:job:a" || claimed[0].Attempts != 1 {
+		t.Fatalf("claimed %+v, want only job a on its first attempt", claimed)
+	}
+	if again, _ := repo.Claim(ctx, "mail", now, lease, 5, 5); len(again) != 0 {
+		t.Fatalf("a leased job was claimed twice: %+v", again)
+	}
+
+	// A worker that died leaves its lease to expire.
+	later := lease.Add(time.Second)
+	reclaimed, err := repo.Claim(ctx, "mail", later, later.Add(time.Minute), 1, 1)
+	if err != nil || len(reclaimed) != 1 || reclaimed[0].Attempts != 2 {
+		t.Fatalf("reclaim after lease expiry = %+v, %v; want job a on attempt 2", reclaimed, err)
+	}
+	// The first attempt can no longer record its outcome.
+	if err := repo.Complete(ctx, "urn:job:a", 1); !errors.Is(err, repositories.ErrJobLeaseLost) {
+		t.Fatalf("Complete of a lost attempt: err = %v, want ErrJobLeaseLost", err)
+	}
+
+	if err := repo.Retry(ctx, "urn:job:a", 2, "smtp down", later.Add(time.Minute)); err != nil {
+		t.Fatalf("Retry: %v", err)
+	}
+	if due, _ := repo.Claim(ctx, "mail", later, later.Add(time.Minute), 1, 1); len(due) != 0 {
+		t.Fatalf("a job was claimed before its retry time: %+v", due)
+	}
+	if err := repo.Bury(ctx, "urn:job:a", 2, "smtp down"); !errors.Is(err, repositories.ErrJobLeaseLost) {
+		t.Fatalf("Bury of a pending job: err = %v, want ErrJobLeaseLost", err)
+	}
+
+	retried, err := repo.Claim(ctx, "mail", later.Add(time.Minute), later.Add(2*time.Minute), 1, 1)
+	if err != nil || len(retried) != 1 {
+		t.Fatalf("claim after the retry time = %+v, %v", retried, err)
+	}
+	if err := repo.Bury(ctx, "urn:job:a", retried[0].Attempts, "smtp down"); err != nil {
+		t.Fatalf("Bury: %v", err)
+	}
+	dead, err := repo.FindAllByStatus(ctx, entities.JobDead, "", 10)
+	if err != nil || len(dead.Data) != 1 || dead.Data[0].LastError != "smtp down" {
+		t.Fatalf("dead jobs = %+v, %v", dead.Data, err)
+	}
+
+	if err := repo.Requeue(ctx, "urn:job:a", later); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	job, err := repo.FindByID(ctx, "urn:job:a")
+	if err != nil || job.Status != entities.JobPending || job.Attempts != 0 {
+		t.Fatalf("after Requeue: %+v, %v; want pending with no attempts", job, err)
+	}
+	if err := repo.Requeue(ctx, "urn:job:a", later); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Requeue of a live job: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestJobRepository_ClaimRespectsConcurrency(t *testing.T) {
+	t.Parallel()
+	repo, _ := newJobTestRepos(t)
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for _, id := range []string{"urn:job:a", "urn:job:b", "urn:job:c"} {
+		if err := repo.Enqueue(ctx, &entities.Job{
+			ID: id, Name: "mail", Status: entities.JobPending, MaxAttempts: 3, RunAt: now,
+		}); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	lease := now.Add(time.Minute)
+	first, err := repo.Claim(ctx, "mail", now, lease, 1, 2)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("first claim = %+v, %v; want one job", first, err)
+	}
+	// A second worker asking for more only gets the one slot left.
+	second, err := repo.Claim(ctx, "mail", now, lease, 5, 2)
+	if err != nil || len(second) != 1 {
+		t.Fatalf("second claim = %+v, %v; want one job", second, err)
+	}
+	if third, _ := repo.Claim(ctx, "mail", now, lease, 5, 2); len(third) != 0 {
+		t.Fatalf("claimed %d jobs over the concurrency limit", len(third))
+	}
+
+	if err := repo.Complete(ctx, first[0].ID, first[0].Attempts); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if next, _ := repo.Claim(ctx, "mail", now, lease, 5, 2); len(next) != 1 {
+		t.Fatalf("after a job finished: claimed %d, want 1", len(next))
+	}
+}
+
+func TestJobRepository_ClaimBuriesExpiredFinalAttempts(t *testing.T) {
+	t.Parallel()
+	repo, _ := newJobTestRepos(t)
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	if err := repo.Enqueue(ctx, &entities.Job{
+		ID: "urn:job:a", Name: "mail", Status: entities.JobRunning, Attempts: 2, MaxAttempts: 2,
+		RunAt: now, LockedUntil: now.Add(-time.Second),
+	}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// The worker running the last attempt died without recording an outcome.
+	claimed, err := repo.Claim(ctx, "mail", now, now.Add(time.Minute), 5, 5)
+	if err != nil || len(claimed) != 0 {
+		t.Fatalf("Claim = %+v, %v; want nothing claimed", claimed, err)
+	}
+	job, err := repo.FindByID(ctx, "urn:job:a")
+	if err != nil || job.Status != entities.JobDead || job.Attempts != 2 || job.LastError != "lease expired" {
+		t.Fatalf("job = %+v, %v; want dead after 2 attempts with lease expired", job, err)
+	}
+}
+
+func TestJobRepository_DeleteSucceeded(t *testing.T) {
+	t.Parallel()
+	repo, _ := newJobTestRepos(t)
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for _, job := range []*entities.Job{
+		{ID: "urn:job:old", Name: "tick", Status: entities.JobSucceeded, RunAt: now.AddDate(0, 0, -8)},
+		{ID: "urn:job:new", Name: "tick", Status: entities.JobSucceeded, RunAt: now.AddDate(0, 0, -1)},
+		{ID: "urn:job:dead", Name: "tick", Status: entities.JobDead, RunAt: now.AddDate(0, 0, -30)},
+	} {
+		if err := repo.Enqueue(ctx, job); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	n, err := repo.DeleteSucceeded(ctx, now.AddDate(0, 0, -7))
+	if err != nil || n != 1 {
+		t.Fatalf("DeleteSucceeded = %d, %v; want 1", n, err)
+	}
+	if _, err := repo.FindByID(ctx, "urn:job:old"); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("the old succeeded job was kept: %v", err)
+	}
+	for _, id := range []string{"urn:job:new", "urn:job:dead"} {
+		if _, err := repo.FindByID(ctx, id); err != nil {
+			t.Errorf("%s was removed: %v", id, err)
+		}
+	}
+}
+
+func TestJobScheduleRepository_AdvancesOnce(t *testing.T) {
+	t.Parallel()
+	_, schedules := newJobTestRepos(t)
+	ctx := context.Background()
+	first := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+
+	due, err := schedules.EnsureSchedule(ctx, "nightly", first)
+	if err != nil || !due.Equal(first) {
+		t.Fatalf("EnsureSchedule = %v, %v; want %v", due, err, first)
+	}
+	if due, _ = schedules.EnsureSchedule(ctx, "nightly", first.Add(time.Hour)); !due.Equal(first) {
+		t.Fatalf("EnsureSchedule replaced the existing run: got %v", due)
+	}
+
+	next := first.AddDate(0, 0, 1)
+	if won, err := schedules.AdvanceSchedule(ctx, "nightly", due, next); err != nil || !won {
+		t.Fatalf("first AdvanceSchedule = %v, %v; want true", won, err)
+	}
+	if won, _ := schedules.AdvanceSchedule(ctx, "nightly", due, next); won {
+		t.Fatal("a second worker advanced the same run")
+	}
+}
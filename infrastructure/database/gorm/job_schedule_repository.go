@@ -0,0 +1,71 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobScheduleRepository struct {
+	db *gorm.DB
+}
+
+type JobScheduleRepositoryResult struct {
+	fx.Out
+	Repository repositories.JobScheduleRepository
+}
+
+func ProvideJobScheduleRepository(db *gorm.DB) (JobScheduleRepositoryResult, error) {
+	return JobScheduleRepositoryResult{
+		Repository: &JobScheduleRepository{db: db},
+	}, nil
+}
+
+func (r *JobScheduleRepository) EnsureSchedule(
+	ctx context.Context, name string, firstRun time.Time,
+) (time.Time, error) {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.JobSchedule{Name: name, NextRunAt: firstRun.UTC()}).Error
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create job schedule %q: %w", name, err)
+	}
+	var row models.JobSchedule
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&row).Error; err != nil {
+		return time.Time{}, fmt.Errorf("failed to load job schedule %q: %w", name, err)
+	}
+	return row.NextRunAt.UTC(), nil
+}
+
+func (r *JobScheduleRepository) AdvanceSchedule(
+	ctx context.Context, name string, due, next time.Time,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.JobSchedule{}).
+		Where("name = ? AND next_run_at = ?", name, due.UTC()).
+		Update("next_run_at", next.UTC())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to advance job schedule %q: %w", name, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
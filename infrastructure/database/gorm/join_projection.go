@@ -0,0 +1,259 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+	"github.com/wepala/weos/v3/pkg/jsonld"
+	"github.com/wepala/weos/v3/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// containsOperator filters a multi-valued reference by one of its targets:
+// _filter[ingredients][contains]=<id>.
+const containsOperator = "contains"
+
+// joinRow is one row of a multi-valued reference join table.
+type joinRow struct {
+	SourceID string  `gorm:"column:source_id"`
+	TargetID string  `gorm:"column:target_id"`
+	Position int     `gorm:"column:position"`
+	Display  *string `gorm:"column:display"`
+}
+
+// syncJoinRows replaces the join-table rows of every multi-valued reference
+// declared on targetSlug with the values currently in data's edges node.
+// Resource data is always the full document, so a property absent from it
+// has no values. Display values are resolved with the same scoped lookup as
+// single-valued references (see populateDisplayColumns) and a failed lookup
+// is logged and stored as NULL rather than failing the write.
+func (r *ResourceRepository) syncJoinRows(
+	ctx context.Context, targetSlug, id string, data json.RawMessage,
+	scope *repositories.VisibilityScope,
+) error {
+	refs := r.projMgr.JoinReferences(targetSlug)
+	if len(refs) == 0 {
+		return nil
+	}
+	values := joinTargetIDs(data, r.projMgr.Context(targetSlug), refs)
+	for _, ref := range refs {
+		if err := r.db.WithContext(ctx).Table(ref.TableName).
+			Where("source_id = ?", id).Delete(map[string]any{}).Error; err != nil {
+			return fmt.Errorf("failed to clear %s rows for %s: %w", ref.TableName, id, err)
+		}
+		ids := values[ref.PropertyName]
+		if len(ids) == 0 {
+			continue
+		}
+		lookup := repositories.ForwardReference{
+			TargetTypeSlug:  ref.TargetTypeSlug,
+			DisplayProperty: ref.DisplayProperty,
+		}
+		rows := make([]map[string]any, 0, len(ids))
+		seen := make(map[string]bool, len(ids))
+		for _, targetID := range ids {
+			if seen[targetID] {
+				continue
+			}
+			seen[targetID] = true
+			row := map[string]any{
+				"source_id": id,
+				"target_id": targetID,
+				"position":  len(rows),
+				"display":   nil,
+			}
+			display, found, err := r.lookupDisplayValue(ctx, lookup, targetID, scope)
+			if err != nil {
+				r.logger.Error(ctx, "display lookup failed; persisting join row with NULL display",
+					"table", ref.TableName, "targetId", targetID, "error", err)
+			} else if found {
+				row["display"] = display
+			}
+			rows = append(rows, row)
+		}
+		if err := r.db.WithContext(ctx).Table(ref.TableName).Create(rows).Error; err != nil {
+			return fmt.Errorf("failed to write %s rows for %s: %w", ref.TableName, id, err)
+		}
+	}
+	return nil
+}
+
+// backfillJoinTable fills the new join table of ref from the stored data
+// of slug's resources, so references stored before the table existed still
+// list. Display values need the reader's scope, which a table migration has
+// none of; they are left NULL until the resource is next written or the
+// projections are rebuilt. It returns how many resources had values.
+func (pm *projectionManager) backfillJoinTable(
+	ctx context.Context, slug string, ldContext json.RawMessage, ref repositories.JoinReference,
+) (int, error) {
+	if !pm.db.Migrator().HasTable(&models.Resource{}) {
+		return 0, nil
+	}
+	refs := []repositories.JoinReference{ref}
+	var filled int
+	var resources []models.Resource
+	err := pm.db.WithContext(ctx).
+		Select("id", "data").
+		Where("type_slug = ? AND deleted_at IS NULL", slug).
+		FindInBatches(&resources, 500, func(*gorm.DB, int) error {
+			var rows []map[string]any
+			for _, res := range resources {
+				ids := joinTargetIDs(json.RawMessage(res.Data), ldContext, refs)[ref.PropertyName]
+				seen := make(map[string]bool, len(ids))
+				for _, targetID := range ids {
+					if seen[targetID] {
+						continue
+					}
+					seen[targetID] = true
+					rows = append(rows, map[string]any{
+						"source_id": res.ID,
+						"target_id": targetID,
+						"position":  len(seen) - 1,
+						"display":   nil,
+					})
+				}
+				if len(ids) > 0 {
+					filled++
+				}
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			return pm.db.WithContext(ctx).Table(ref.TableName).
+				Clauses(clause.OnConflict{DoNothing: true}).Create(rows).Error
+		}).Error
+	return filled, err
+}
+
+// deleteJoinRows removes every join-table row owned by id on targetSlug.
+func (r *ResourceRepository) deleteJoinRows(ctx context.Context, targetSlug, id string) error {
+	for _, ref := range r.projMgr.JoinReferences(targetSlug) {
+		if err := r.db.WithContext(ctx).Table(ref.TableName).
+			Where("source_id = ?", id).Delete(map[string]any{}).Error; err != nil {
+			return fmt.Errorf("failed to delete %s rows for %s: %w", ref.TableName, id, err)
+		}
+	}
+	return nil
+}
+
+// attachJoinValues adds every multi-valued reference of typeSlug to the
+// given flat rows as an ordered array of {"id", "display"} pairs, keyed by
+// the schema property name. One query per join table covers all rows.
+func (r *ResourceRepository) attachJoinValues(
+	ctx context.Context, typeSlug string, rows []map[string]any,
+) error {
+	refs := r.projMgr.JoinReferences(typeSlug)
+	if len(refs) == 0 || len(rows) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, fmt.Sprint(row["id"]))
+	}
+	for _, ref := range refs {
+		var joined []joinRow
+		if err := r.db.WithContext(ctx).Table(ref.TableName).
+			Where("source_id IN ?", ids).
+			Order("source_id, position").
+			Find(&joined).Error; err != nil {
+			return fmt.Errorf("failed to load %s: %w", ref.TableName, err)
+		}
+		bySource := make(map[string][]map[string]any, len(rows))
+		for _, j := range joined {
+			var display any
+			if j.Display != nil {
+				display = *j.Display
+			}
+			bySource[j.SourceID] = append(bySource[j.SourceID],
+				map[string]any{"id": j.TargetID, "display": display})
+		}
+		for _, row := range rows {
+			values := bySource[fmt.Sprint(row["id"])]
+			if values == nil {
+				values = []map[string]any{}
+			}
+			row[ref.PropertyName] = values
+		}
+	}
+	return nil
+}
+
+// joinReferenceFor returns the multi-valued reference on typeSlug that a
+// filter field names, accepting either the property name or its snake_case
+// column spelling.
+func (r *ResourceRepository) joinReferenceFor(typeSlug, field string) (repositories.JoinReference, bool) {
+	for _, ref := range r.projMgr.JoinReferences(typeSlug) {
+		if ref.PropertyName == field || utils.CamelToSnake(ref.PropertyName) == utils.CamelToSnake(field) {
+			return ref, true
+		}
+	}
+	return repositories.JoinReference{}, false
+}
+
+// joinContainsClause builds the WHERE fragment for a contains filter on a
+// multi-valued reference; idColumn is the (optionally qualified) id column of
+// the projection table being queried.
+func joinContainsClause(idColumn string, ref repositories.JoinReference) string {
+	return idColumn + " IN (SELECT source_id FROM " + ref.TableName + " WHERE target_id = ?)"
+}
+
+// joinTargetIDs reads the edges node of a JSON-LD @graph document and returns
+// the IDs referenced through each join reference, in document order. Edges are
+// keyed by predicate IRI, resolved from the property name the same way
+// BuildResourceGraph does (explicit @context mapping, else @vocab + name).
+func joinTargetIDs(
+	data, ldContext json.RawMessage, refs []repositories.JoinReference,
+) map[string][]string {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	graphArr, ok := doc["@graph"].([]any)
+	if !ok || len(graphArr) < 2 {
+		return nil
+	}
+	edges, ok := graphArr[1].(map[string]any)
+	if !ok {
+		return nil
+	}
+	vocab, contextMap := jsonld.ParseContext(ldContext)
+	result := make(map[string][]string, len(refs))
+	for _, ref := range refs {
+		val, ok := edges[jsonld.ResolvePredicateIRI(ref.PropertyName, vocab, contextMap)]
+		if !ok {
+			continue
+		}
+		items, ok := val.([]any)
+		if !ok {
+			items = []any{val}
+		}
+		for _, item := range items {
+			if node, ok := item.(map[string]any); ok {
+				if id, ok := node["@id"].(string); ok && id != "" {
+					result[ref.PropertyName] = append(result[ref.PropertyName], id)
+				}
+			}
+		}
+	}
+	return result
+}
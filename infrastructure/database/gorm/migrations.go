@@ -0,0 +1,50 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// runMigration applies a one-time data migration: fn runs in a transaction
+// that also records id in the migrations table, so it runs once per
+// database, even with several processes starting at the same time. A failed
+// fn rolls back and is tried again on the next start.
+func runMigration(db *gorm.DB, id string, fn func(tx *gorm.DB) error) error {
+	if err := db.AutoMigrate(&models.Migration{}); err != nil {
+		return fmt.Errorf("failed to create the migrations table: %w", err)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.Migration{ID: id, AppliedAt: time.Now().UTC()})
+		if result.Error != nil {
+			return fmt.Errorf("failed to record migration %q: %w", id, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil // already applied
+		}
+		if err := fn(tx); err != nil {
+			return fmt.Errorf("migration %q: %w", id, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+type PendingEventRepository struct {
+	db *gorm.DB
+}
+
+type PendingEventRepositoryResult struct {
+	fx.Out
+	Repository repositories.PendingEventRepository
+}
+
+func ProvidePendingEventRepository(params struct {
+	fx.In
+	DB *gorm.DB
+}) (PendingEventRepositoryResult, error) {
+	return PendingEventRepositoryResult{
+		Repository: &PendingEventRepository{db: params.DB},
+	}, nil
+}
+
+func (r *PendingEventRepository) Due(ctx context.Context, now time.Time, limit int) ([]*entities.PendingEvent, error) {
+	var rows []models.PendingEvent
+	err := r.db.WithContext(ctx).
+		Where("next_attempt_at <= ?", now.UTC()).
+		Order("created_at, event_id").
+		Limit(limit).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due pending events: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.EventID
+	}
+	var stored []infrastructure.GormEventModel
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&stored).Error; err != nil {
+		return nil, fmt.Errorf("failed to load pending events: %w", err)
+	}
+	byID := make(map[string]infrastructure.GormEventModel, len(stored))
+	for _, m := range stored {
+		byID[m.ID] = m
+	}
+	out := make([]*entities.PendingEvent, 0, len(rows))
+	for _, row := range rows {
+		m, ok := byID[row.EventID]
+		if !ok {
+			continue
+		}
+		p := row.ToEntity()
+		p.Event = eventEnvelope(m)
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (r *PendingEventRepository) Ack(ctx context.Context, eventIDs ...string) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Where("event_id IN ?", eventIDs).Delete(&models.PendingEvent{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge pending events: %w", err)
+	}
+	return nil
+}
+
+func (r *PendingEventRepository) Fail(
+	ctx context.Context, eventID string, attempts int, nextAttemptAt time.Time, lastError string,
+) error {
+	err := r.db.WithContext(ctx).Model(&models.PendingEvent{}).
+		Where("event_id = ?", eventID).
+		Updates(map[string]any{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt.UTC(),
+			"last_error":      lastError,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record pending event failure: %w", err)
+	}
+	return nil
+}
+
+func (r *PendingEventRepository) Reschedule(ctx context.Context, at time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&models.PendingEvent{}).
+		Where("1 = 1").
+		Update("next_attempt_at", at.UTC())
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to reschedule pending events: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+func (r *PendingEventRepository) Backlog(ctx context.Context) (entities.PendingEventBacklog, error) {
+	var backlog entities.PendingEventBacklog
+	var summary struct {
+		Count       int64
+		MaxAttempts int
+	}
+	db := r.db.WithContext(ctx).Model(&models.PendingEvent{})
+	if err := db.Select("COUNT(*) AS count, COALESCE(MAX(attempts), 0) AS max_attempts").
+		Scan(&summary).Error; err != nil {
+		return backlog, fmt.Errorf("failed to summarise pending events: %w", err)
+	}
+	backlog.Count, backlog.MaxAttempts = summary.Count, summary.MaxAttempts
+	if backlog.Count == 0 {
+		return backlog, nil
+	}
+	var oldest, failing models.PendingEvent
+	if err := r.db.WithContext(ctx).Order("created_at, event_id").First(&oldest).Error; err != nil {
+		return backlog, fmt.Errorf("failed to find oldest pending event: %w", err)
+	}
+	backlog.Oldest = oldest.CreatedAt
+	err := r.db.WithContext(ctx).Where("last_error <> ''").Order("attempts DESC, event_id").
+		Limit(1).Find(&failing).Error
+	if err != nil {
+		return backlog, fmt.Errorf("failed to find failing pending event: %w", err)
+	}
+	backlog.LastError = failing.LastError
+	return backlog, nil
+}
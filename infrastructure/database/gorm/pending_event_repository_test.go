@@ -0,0 +1,112 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wepala/weos/v3/infrastructure/events"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
+)
+
+type silentLogger struct{}
+
+func (silentLogger) Info(context.Context, string, ...any)  {}
+func (silentLogger) Warn(context.Context, string, ...any)  {}
+func (silentLogger) Error(context.Context, string, ...any) {}
+
+// flakySecondary is a secondary event store that can be made to fail.
+type flakySecondary struct {
+	*infrastructure.MemoryStore
+	down bool
+}
+
+func (s *flakySecondary) Append(ctx context.Context, id string, version int, events ...domain.EventEnvelope[any]) error {
+	if s.down {
+		return errors.New("secondary unavailable")
+	}
+	return s.MemoryStore.Append(ctx, id, version, events...)
+}
+
+func TestDualWrite_ResendsUnacknowledgedEvents(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := newEventLogTestDB(t)
+	if err := db.AutoMigrate(&models.PendingEvent{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	primary, err := NewEventStore(db)
+	if err != nil {
+		t.Fatalf("NewEventStore: %v", err)
+	}
+	primary.RecordPending = true
+	pending := &PendingEventRepository{db: db}
+	secondary := &flakySecondary{MemoryStore: infrastructure.NewMemoryStore(), down: true}
+	writer := events.NewSecondaryWriter(secondary, pending, silentLogger{})
+	store := events.NewDualWriteEventStore(primary, writer)
+
+	if err := store.Append(ctx, "urn:a", 0,
+		testEvent("urn:a", "e1", "Resource.Created", 1),
+		testEvent("urn:a", "e2", "Resource.Updated", 2),
+	); err != nil {
+		t.Fatalf("Append with the secondary down: %v", err)
+	}
+	backlog, err := writer.Backlog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backlog.Count != 2 || backlog.MaxAttempts != 1 || backlog.LastError != "secondary unavailable" {
+		t.Fatalf("backlog = %+v, want both events pending after one failed attempt", backlog)
+	}
+	if sent, err := writer.Resend(ctx); err != nil || sent != 0 {
+		t.Fatalf("Resend during backoff = %d, %v; want nothing sent", sent, err)
+	}
+
+	secondary.down = false
+	if err := store.Append(ctx, "urn:b", -1, testEvent("urn:b", "e3", "Resource.Created", 1)); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	// Stored, but the process stopped before sending it.
+	if err := primary.Append(ctx, "urn:c", -1, testEvent("urn:c", "e4", "Resource.Created", 1)); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if backlog, _ := writer.Backlog(ctx); backlog.Count != 3 {
+		t.Fatalf("backlog = %d, want e1, e2 and e4 pending", backlog.Count)
+	}
+
+	if n, err := writer.Reconcile(ctx); err != nil || n != 3 {
+		t.Fatalf("Reconcile = %d, %v; want 3", n, err)
+	}
+	if sent, err := writer.Resend(ctx); err != nil || sent != 3 {
+		t.Fatalf("Resend = %d, %v; want 3", sent, err)
+	}
+	if backlog, _ := writer.Backlog(ctx); backlog.Count != 0 {
+		t.Errorf("backlog = %+v after resending, want none", backlog)
+	}
+	for _, id := range []string{"urn:a", "urn:b", "urn:c"} {
+		got, err := secondary.GetEvents(ctx, id)
+		if err != nil || len(got) == 0 {
+			t.Errorf("secondary events of %s = %v, %v", id, got, err)
+		}
+	}
+	if got, _ := secondary.GetEvents(ctx, "urn:a"); len(got) != 2 || got[0].ID != "e1" || got[1].ID != "e2" {
+		t.Errorf("secondary events of urn:a = %+v, want e1 then e2", got)
+	}
+}
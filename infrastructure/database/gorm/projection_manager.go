@@ -20,6 +20,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 
@@ -39,6 +42,24 @@ type columnDef struct {
 	SQLType string
 }
 
+// indexDef describes a secondary index on a projection table. Indexes are
+// derived from the schema (x-index, x-unique, x-indexes and x-resource-type
+// FK columns) and are always non-unique at the database level — see
+// schemaToIndexes for why uniqueness is enforced by the ResourceService.
+type indexDef struct {
+	Name    string
+	Columns []string
+}
+
+// managedIndexPrefix is prepended (with the table name) to every index the
+// projection manager creates. Only indexes carrying this prefix are dropped
+// when they disappear from the schema, so hand-made indexes survive.
+const managedIndexPrefix = "idx_"
+
+// maxIndexNameLen is Postgres' identifier limit (NAMEDATALEN-1). Longer names
+// are silently truncated by Postgres, which would break the drop-stale pass.
+const maxIndexNameLen = 63
+
 // standardColumnNames lists column names already part of the projection table DDL
 // and should be skipped when extracting columns from JSON Schema.
 var standardColumnNames = map[string]bool{
@@ -72,7 +93,9 @@ type projectionManager struct {
 	tables      sync.Map   // slug → tableInfo
 	reverseRe   sync.Map   // targetTypeSlug → []repositories.ReverseReference
 	forwardRe   sync.Map   // referencingTypeSlug → []repositories.ForwardReference
-	reverseReMu sync.Mutex // guards reverseRe AND forwardRe writes (symmetric)
+	reverseReMu sync.Mutex // guards reverseRe, forwardRe and the join maps' writes
+	joinRe      sync.Map   // sourceTypeSlug → []repositories.JoinReference
+	reverseJoin sync.Map   // targetTypeSlug → []repositories.JoinReference
 	parentOf    sync.Map   // slug → parentSlug (from rdfs:subClassOf, for ancestor chain)
 	// linkSource replays link-declared refs after registerReverseReferences
 	// clears a slug's entries. Optional — nil means link refs are only set by
@@ -142,6 +165,36 @@ func (pm *projectionManager) EnsureTable(
 			}
 		}
 	}
+	indexes := schemaToIndexes(tableName, schema, colSet)
+	if pm.linkSource != nil {
+		for _, ref := range pm.linkSource.LinkReferencesForSource(slug) {
+			if colName := utils.CamelToSnake(ref.PropertyName); colSet[colName] {
+				indexes = appendIndex(indexes, tableName, []string{colName})
+			}
+		}
+	}
+	if err := pm.syncIndexes(ctx, tableName, indexes); err != nil {
+		return fmt.Errorf("failed to sync indexes on %q: %w", tableName, err)
+	}
+	joinRefs := schemaToJoinReferences(slug, schema)
+	for _, ref := range joinRefs {
+		existed := pm.db.Migrator().HasTable(ref.TableName)
+		if err := pm.ensureJoinTable(ctx, ref.TableName); err != nil {
+			return fmt.Errorf("failed to ensure join table %q: %w", ref.TableName, err)
+		}
+		if existed {
+			continue
+		}
+		n, err := pm.backfillJoinTable(ctx, slug, ldContext, ref)
+		if err != nil {
+			return fmt.Errorf("failed to backfill join table %q: %w", ref.TableName, err)
+		}
+		if n > 0 {
+			pm.logger.Warn(ctx, "backfilled a new join table from stored resources without display values; "+
+				"run `weos projections rebuild` to fill them in",
+				"table", ref.TableName, "resources", n)
+		}
+	}
 	pm.tables.Store(slug, tableInfo{name: tableName, context: ldContext, columns: colSet})
 	if parentSlug := jsonld.SubClassOf(ldContext); parentSlug != "" {
 		pm.parentOf.Store(slug, parentSlug)
@@ -273,6 +326,36 @@ func (pm *projectionManager) ForwardReferences(typeSlug string) []repositories.F
 	return nil
 }
 
+func (pm *projectionManager) JoinReferences(typeSlug string) []repositories.JoinReference {
+	if v, ok := pm.joinRe.Load(typeSlug); ok {
+		if refs, ok := v.([]repositories.JoinReference); ok {
+			cp := make([]repositories.JoinReference, len(refs))
+			copy(cp, refs)
+			return cp
+		}
+	}
+	return nil
+}
+
+func (pm *projectionManager) ReverseJoinReferences(targetTypeSlug string) []repositories.JoinReference {
+	if v, ok := pm.reverseJoin.Load(targetTypeSlug); ok {
+		if refs, ok := v.([]repositories.JoinReference); ok {
+			cp := make([]repositories.JoinReference, len(refs))
+			copy(cp, refs)
+			return cp
+		}
+	}
+	return nil
+}
+
+func (pm *projectionManager) UpdateJoinDisplay(
+	ctx context.Context, ref repositories.JoinReference, targetID string, display any,
+) error {
+	return pm.db.WithContext(ctx).Table(ref.TableName).
+		Where("target_id = ?", targetID).
+		Update("display", display).Error
+}
+
 // AncestorSlugs returns the ordered chain of ancestor type slugs by walking
 // rdfs:subClassOf relationships cached during EnsureTable.
 func (pm *projectionManager) AncestorSlugs(slug string) []string {
@@ -326,6 +409,7 @@ func (pm *projectionManager) registerReverseReferences(slug string, schema json.
 	if len(schema) > 0 {
 		var s struct {
 			Properties map[string]struct {
+				Type             string `json:"type"`
 				XResourceType    string `json:"x-resource-type"`
 				XDisplayProperty string `json:"x-display-property"`
 			} `json:"properties"`
@@ -335,6 +419,11 @@ func (pm *projectionManager) registerReverseReferences(slug string, schema json.
 				if prop.XResourceType == "" {
 					continue
 				}
+				if prop.Type == "array" {
+					// Multi-valued: registered as a join reference below.
+					schemaProps[propName] = true
+					continue
+				}
 				displayProp := prop.XDisplayProperty
 				if displayProp == "" {
 					displayProp = "name"
@@ -344,6 +433,9 @@ func (pm *projectionManager) registerReverseReferences(slug string, schema json.
 				schemaCols[utils.CamelToSnake(propName)] = true
 			}
 		}
+		for _, ref := range schemaToJoinReferences(slug, schema) {
+			pm.appendJoinRefLocked(ref)
+		}
 	}
 
 	// Replay link-declared refs unconditionally — a re-parse with empty or
@@ -403,6 +495,28 @@ func (pm *projectionManager) clearReferencesForSlugLocked(slug string) {
 	// Drop the forward bucket entirely — all forward refs for slug come from
 	// its own schema, so they're all stale by definition on re-registration.
 	pm.forwardRe.Delete(slug)
+	pm.joinRe.Delete(slug)
+	pm.reverseJoin.Range(func(key, value any) bool {
+		refs, ok := value.([]repositories.JoinReference)
+		if !ok {
+			return true
+		}
+		filtered := make([]repositories.JoinReference, 0, len(refs))
+		for _, r := range refs {
+			if r.SourceTypeSlug != slug {
+				filtered = append(filtered, r)
+			}
+		}
+		switch {
+		case len(filtered) == len(refs):
+			// Nothing named slug; leave the bucket untouched.
+		case len(filtered) == 0:
+			pm.reverseJoin.Delete(key)
+		default:
+			pm.reverseJoin.Store(key, filtered)
+		}
+		return true
+	})
 
 	// Walk reverse buckets and filter out any entries that name slug as the
 	// referencer. Buckets keyed on different target types may contain refs
@@ -483,6 +597,23 @@ func (pm *projectionManager) appendForwardRefLocked(
 	pm.forwardRe.Store(referencingSlug, updated)
 }
 
+// appendJoinRefLocked records ref under its source and target slugs. The
+// source bucket has already been cleared by clearReferencesForSlugLocked, so
+// only the reverse bucket needs copy-on-write. Caller must hold reverseReMu.
+func (pm *projectionManager) appendJoinRefLocked(ref repositories.JoinReference) {
+	var forward []repositories.JoinReference
+	if existing, ok := pm.joinRe.Load(ref.SourceTypeSlug); ok {
+		forward = existing.([]repositories.JoinReference)
+	}
+	pm.joinRe.Store(ref.SourceTypeSlug, append(slices.Clone(forward), ref))
+
+	var reverse []repositories.JoinReference
+	if existing, ok := pm.reverseJoin.Load(ref.TargetTypeSlug); ok {
+		reverse = existing.([]repositories.JoinReference)
+	}
+	pm.reverseJoin.Store(ref.TargetTypeSlug, append(slices.Clone(reverse), ref))
+}
+
 // RegisterLink activates a cross-type link declared outside the source type's
 // schema. See the ProjectionManager interface docstring for semantics.
 //
@@ -518,6 +649,12 @@ func (pm *projectionManager) RegisterLink(ctx context.Context, ref repositories.
 	if err := pm.addMissingColumns(ctx, tableName, cols); err != nil {
 		return fmt.Errorf("RegisterLink: add columns to %q: %w", tableName, err)
 	}
+	// Link FK columns are indexed like schema-declared ones. Only create here —
+	// the drop-stale pass belongs to EnsureTable, which sees the full schema.
+	fkIndex := newIndexDef(tableName, []string{colName})
+	if err := pm.createIndex(ctx, tableName, fkIndex); err != nil {
+		return fmt.Errorf("RegisterLink: index %q on %q: %w", colName, tableName, err)
+	}
 	// Refresh cached column set so HasColumn reflects the ALTER TABLE. The
 	// columns map is read without a lock by HasColumn, so we copy-on-write
 	// into a fresh map and Store the new tableInfo rather than mutating the
@@ -606,6 +743,139 @@ func (pm *projectionManager) addMissingColumns(ctx context.Context, tableName st
 	return nil
 }
 
+// syncIndexes brings the managed indexes on tableName in line with indexes:
+// missing ones are created and managed indexes that are no longer declared are
+// dropped. Indexes without the managed prefix are never touched.
+func (pm *projectionManager) syncIndexes(ctx context.Context, tableName string, indexes []indexDef) error {
+	existing, err := pm.listIndexNames(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	want := make(map[string]bool, len(indexes))
+	for _, idx := range indexes {
+		want[idx.Name] = true
+		if existing[idx.Name] {
+			continue
+		}
+		if err := pm.createIndex(ctx, tableName, idx); err != nil {
+			return err
+		}
+	}
+	prefix := managedIndexPrefix + tableName + "_"
+	for name := range existing {
+		if want[name] || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if err := pm.db.WithContext(ctx).Exec("DROP INDEX IF EXISTS " + name).Error; err != nil {
+			return fmt.Errorf("drop index %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (pm *projectionManager) createIndex(ctx context.Context, tableName string, idx indexDef) error {
+	ddl := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)",
+		idx.Name, tableName, strings.Join(idx.Columns, ", "))
+	if err := pm.db.WithContext(ctx).Exec(ddl).Error; err != nil {
+		return fmt.Errorf("create index %q: %w", idx.Name, err)
+	}
+	return nil
+}
+
+// listIndexNames returns the names of the indexes currently defined on
+// tableName. gorm's Migrator().GetIndexes would do, but the sqlite driver
+// runs it through DB.Debug() and floods the log on every EnsureTable.
+func (pm *projectionManager) listIndexNames(ctx context.Context, tableName string) (map[string]bool, error) {
+	var query string
+	switch pm.db.Name() {
+	case "postgres":
+		query = "SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND tablename = ?"
+	case "sqlite":
+		query = "SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ?"
+	default:
+		// Unknown dialect: skip the drop-stale pass; CREATE INDEX IF NOT EXISTS
+		// still keeps declared indexes in place.
+		return map[string]bool{}, nil
+	}
+	var names []string
+	if err := pm.db.WithContext(ctx).Raw(query, tableName).Scan(&names).Error; err != nil {
+		return nil, fmt.Errorf("list indexes on %q: %w", tableName, err)
+	}
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set, nil
+}
+
+// ensureJoinTable creates the join table for a multi-valued reference. One
+// row per (source, target) pair; position keeps the order the values were
+// given in, and display carries the target's display property so list views
+// don't need a second lookup. target_id is indexed for the reverse direction
+// (the contains filter and display propagation).
+func (pm *projectionManager) ensureJoinTable(ctx context.Context, tableName string) error {
+	ddl := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n"+
+		"  source_id TEXT NOT NULL,\n"+
+		"  target_id TEXT NOT NULL,\n"+
+		"  position INTEGER NOT NULL DEFAULT 0,\n"+
+		"  display VARCHAR(512),\n"+
+		"  PRIMARY KEY (source_id, target_id)\n"+
+		")", tableName)
+	if err := pm.db.WithContext(ctx).Exec(ddl).Error; err != nil {
+		return err
+	}
+	return pm.createIndex(ctx, tableName, newIndexDef(tableName, []string{"target_id"}))
+}
+
+// joinTableName names the join table of an array-valued reference property:
+// <slug>_<property>_refs, e.g. recipe_suitable_for_diet_refs.
+func joinTableName(slug, propName string) string {
+	return strings.ReplaceAll(slug, "-", "_") + "_" + utils.CamelToSnake(propName) + "_refs"
+}
+
+// isJoinReference reports whether a schema property is a multi-valued
+// reference, which is projected into a join table rather than a column.
+func isJoinReference(propType, resourceType string) bool {
+	return propType == "array" && resourceType != ""
+}
+
+// schemaToJoinReferences returns a JoinReference for every array property
+// with x-resource-type, sorted by property name.
+func schemaToJoinReferences(slug string, schema json.RawMessage) []repositories.JoinReference {
+	if len(schema) == 0 {
+		return nil
+	}
+	var s struct {
+		Properties map[string]struct {
+			Type             string `json:"type"`
+			XResourceType    string `json:"x-resource-type"`
+			XDisplayProperty string `json:"x-display-property"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil
+	}
+	var refs []repositories.JoinReference
+	for propName, prop := range s.Properties {
+		if !isJoinReference(prop.Type, prop.XResourceType) {
+			continue
+		}
+		displayProp := prop.XDisplayProperty
+		if displayProp == "" {
+			displayProp = "name"
+		}
+		refs = append(refs, repositories.JoinReference{
+			SourceTypeSlug:  slug,
+			PropertyName:    propName,
+			TableName:       joinTableName(slug, propName),
+			TargetTypeSlug:  prop.XResourceType,
+			DisplayProperty: displayProp,
+		})
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].PropertyName < refs[j].PropertyName })
+	return refs
+}
+
 // slugToTableName converts a resource type slug to a SQL table name.
 // Replaces hyphens with underscores and pluralizes.
 func slugToTableName(slug string) string {
@@ -616,6 +886,8 @@ func slugToTableName(slug string) string {
 // schemaToColumns parses a JSON Schema and returns column definitions.
 // Skips JSON-LD meta-keys and standard column names.
 // For properties with x-resource-type, an additional _display column is generated.
+// Array-valued references get no column at all — they live in a join table
+// (see schemaToJoinReferences).
 func schemaToColumns(schema json.RawMessage) []columnDef {
 	if len(schema) == 0 {
 		return nil
@@ -633,7 +905,7 @@ func schemaToColumns(schema json.RawMessage) []columnDef {
 
 	var cols []columnDef
 	for propName, propDef := range s.Properties {
-		if jsonLDKeys[propName] {
+		if jsonLDKeys[propName] || isJoinReference(propDef.Type, propDef.XResourceType) {
 			continue
 		}
 
@@ -653,6 +925,101 @@ func schemaToColumns(schema json.RawMessage) []columnDef {
 	return cols
 }
 
+// schemaToIndexes derives the projection indexes declared by a JSON Schema:
+//
+//   - a single-column index for every property with "x-index": true or
+//     "x-unique": true,
+//   - a single-column index for every x-resource-type FK column, so filtering
+//     by a reference (tasks by project) doesn't scan the table,
+//   - one composite index per entry in the root-level "x-indexes" array, e.g.
+//     {"x-indexes": [{"properties": ["project", "dueDate"], "unique": true}]}.
+//
+// Columns not present in columns (the projection's known column set) are
+// skipped, as is any composite entry that references one.
+//
+// x-unique is deliberately NOT a UNIQUE index. Projection rows are written by
+// event handlers after the command has committed, so a database-level
+// violation would reject the projection write rather than the command and
+// leave the read model behind the event store. ResourceService checks
+// uniqueness before committing instead (see checkUniqueConstraints); the
+// index here only keeps that lookup cheap.
+func schemaToIndexes(tableName string, schema json.RawMessage, columns map[string]bool) []indexDef {
+	if len(schema) == 0 {
+		return nil
+	}
+	var s struct {
+		Properties map[string]struct {
+			XIndex        bool   `json:"x-index"`
+			XUnique       bool   `json:"x-unique"`
+			XResourceType string `json:"x-resource-type"`
+		} `json:"properties"`
+		XIndexes []struct {
+			Properties []string `json:"properties"`
+		} `json:"x-indexes"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil
+	}
+
+	var indexes []indexDef
+	for propName, prop := range s.Properties {
+		if !prop.XIndex && !prop.XUnique && prop.XResourceType == "" {
+			continue
+		}
+		colName := utils.CamelToSnake(propName)
+		if !columns[colName] {
+			continue
+		}
+		indexes = appendIndex(indexes, tableName, []string{colName})
+	}
+	for _, composite := range s.XIndexes {
+		if len(composite.Properties) == 0 {
+			continue
+		}
+		cols := make([]string, 0, len(composite.Properties))
+		for _, propName := range composite.Properties {
+			colName := utils.CamelToSnake(propName)
+			if !columns[colName] {
+				cols = nil
+				break
+			}
+			cols = append(cols, colName)
+		}
+		if len(cols) > 0 {
+			indexes = appendIndex(indexes, tableName, cols)
+		}
+	}
+	// Map iteration order is random; sort so DDL order is stable.
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+	return indexes
+}
+
+// appendIndex adds an index over cols unless one with the same name (and
+// therefore the same columns) is already present.
+func appendIndex(indexes []indexDef, tableName string, cols []string) []indexDef {
+	idx := newIndexDef(tableName, cols)
+	for _, existing := range indexes {
+		if existing.Name == idx.Name {
+			return indexes
+		}
+	}
+	return append(indexes, idx)
+}
+
+// newIndexDef names an index idx_<table>_<col1>_<col2>. Names longer than
+// Postgres' identifier limit are truncated and suffixed with a hash of the
+// full name so distinct column lists never collide.
+func newIndexDef(tableName string, cols []string) indexDef {
+	name := managedIndexPrefix + tableName + "_" + strings.Join(cols, "_")
+	if len(name) > maxIndexNameLen {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(name))
+		suffix := fmt.Sprintf("_%08x", h.Sum32())
+		name = name[:maxIndexNameLen-len(suffix)] + suffix
+	}
+	return indexDef{Name: name, Columns: cols}
+}
+
 // jsonTypeToSQL maps JSON Schema types to SQL column types.
 func jsonTypeToSQL(jsonType string) string {
 	switch jsonType {
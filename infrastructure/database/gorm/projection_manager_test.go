@@ -18,6 +18,7 @@ package gorm
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -245,6 +246,131 @@ func TestEnsureTable_AddsNewColumns(t *testing.T) {
 	}
 }
 
+func TestSchemaToIndexes(t *testing.T) {
+	t.Parallel()
+
+	schema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"sku": {"type": "string", "x-unique": true},
+			"dueDate": {"type": "string", "x-index": true},
+			"project": {"type": "string", "x-resource-type": "project"},
+			"ghost": {"type": "string", "x-index": true}
+		},
+		"x-indexes": [
+			{"properties": ["project", "dueDate"]},
+			{"properties": ["project", "missing"]},
+			{"properties": ["status", "name"], "unique": true}
+		]
+	}`)
+	columns := map[string]bool{
+		"name": true, "sku": true, "due_date": true, "project": true,
+		"project_display": true, "status": true,
+	}
+
+	got := map[string][]string{}
+	for _, idx := range schemaToIndexes("tasks", schema, columns) {
+		got[idx.Name] = idx.Columns
+	}
+	want := map[string][]string{
+		"idx_tasks_sku":              {"sku"},
+		"idx_tasks_due_date":         {"due_date"},
+		"idx_tasks_project":          {"project"},
+		"idx_tasks_project_due_date": {"project", "due_date"},
+		"idx_tasks_status_name":      {"status", "name"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("indexes = %v, want %v", got, want)
+	}
+	for name, cols := range want {
+		if strings.Join(got[name], ",") != strings.Join(cols, ",") {
+			t.Errorf("index %q columns = %v, want %v", name, got[name], cols)
+		}
+	}
+}
+
+func TestNewIndexDef_TruncatesLongNames(t *testing.T) {
+	t.Parallel()
+	cols := []string{"a_very_long_column_name", "another_very_long_column_name", "third_column"}
+	a := newIndexDef("some_long_table_names", cols)
+	b := newIndexDef("some_long_table_names", append(cols[:2:2], "fourth_column"))
+	if len(a.Name) > maxIndexNameLen {
+		t.Fatalf("name %q is %d chars, want <= %d", a.Name, len(a.Name), maxIndexNameLen)
+	}
+	if !strings.HasPrefix(a.Name, "idx_some_long_table_names_") {
+		t.Errorf("name %q lost the managed prefix", a.Name)
+	}
+	if a.Name == b.Name {
+		t.Errorf("distinct column lists produced the same truncated name %q", a.Name)
+	}
+}
+
+func indexNames(t *testing.T, db *gorm.DB, table string) map[string]bool {
+	t.Helper()
+	var names []string
+	if err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = ?", table).
+		Scan(&names).Error; err != nil {
+		t.Fatalf("failed to list indexes: %v", err)
+	}
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
+
+func TestEnsureTable_CreatesAndMaintainsIndexes(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	pm := &projectionManager{db: db, logger: &testLogger{}}
+
+	schema1 := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"dueDate": {"type": "string", "x-index": true},
+			"project": {"type": "string", "x-resource-type": "project"}
+		}
+	}`)
+	if err := pm.EnsureTable(context.Background(), "task", schema1, nil); err != nil {
+		t.Fatalf("EnsureTable failed: %v", err)
+	}
+	// A hand-made index must survive the drop-stale pass.
+	if err := db.Exec("CREATE INDEX custom_tasks_name ON tasks (name)").Error; err != nil {
+		t.Fatalf("failed to create custom index: %v", err)
+	}
+	names := indexNames(t, db, "tasks")
+	for _, want := range []string{"idx_tasks_due_date", "idx_tasks_project"} {
+		if !names[want] {
+			t.Errorf("expected index %q, got %v", want, names)
+		}
+	}
+
+	// Dropping x-index from dueDate removes its index; the FK index stays.
+	schema2 := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"dueDate": {"type": "string"},
+			"project": {"type": "string", "x-resource-type": "project"}
+		},
+		"x-indexes": [{"properties": ["project", "name"]}]
+	}`)
+	if err := pm.EnsureTable(context.Background(), "task", schema2, nil); err != nil {
+		t.Fatalf("second EnsureTable failed: %v", err)
+	}
+	names = indexNames(t, db, "tasks")
+	if names["idx_tasks_due_date"] {
+		t.Error("idx_tasks_due_date should have been dropped")
+	}
+	for _, want := range []string{"idx_tasks_project", "idx_tasks_project_name", "custom_tasks_name"} {
+		if !names[want] {
+			t.Errorf("expected index %q, got %v", want, names)
+		}
+	}
+}
+
 func TestEnsureTable_NoSchema(t *testing.T) {
 	t.Parallel()
 	db := newTestDB(t)
@@ -935,3 +1061,64 @@ func TestRegisterLink_CoexistsWithSchemaReference(t *testing.T) {
 		t.Errorf("expected targets {project, user}, got %+v", targets)
 	}
 }
+
+func TestEnsureTable_RegistersJoinReferences(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	pm := &projectionManager{db: db, logger: &testLogger{}}
+	ctx := context.Background()
+
+	schema := json.RawMessage(`{"type":"object","properties":{
+		"name":{"type":"string"},
+		"suitableForDiet":{"type":"array","items":{"type":"string"},"x-resource-type":"restricted-diet"}
+	}}`)
+	if err := pm.EnsureTable(ctx, "meal-plan", schema, nil); err != nil {
+		t.Fatalf("EnsureTable: %v", err)
+	}
+
+	want := repositories.JoinReference{
+		SourceTypeSlug:  "meal-plan",
+		PropertyName:    "suitableForDiet",
+		TableName:       "meal_plan_suitable_for_diet_refs",
+		TargetTypeSlug:  "restricted-diet",
+		DisplayProperty: "name",
+	}
+	if got := pm.JoinReferences("meal-plan"); len(got) != 1 || got[0] != want {
+		t.Errorf("JoinReferences = %+v, want [%+v]", got, want)
+	}
+	if got := pm.ReverseJoinReferences("restricted-diet"); len(got) != 1 || got[0] != want {
+		t.Errorf("ReverseJoinReferences = %+v, want [%+v]", got, want)
+	}
+	if len(pm.ForwardReferences("meal-plan")) != 0 || len(pm.ReverseReferences("restricted-diet")) != 0 {
+		t.Error("multi-valued reference should not register a scalar FK reference")
+	}
+	if pm.HasColumn("meal-plan", "suitable_for_diet") {
+		t.Error("multi-valued reference should not get a projection column")
+	}
+	if !db.Migrator().HasTable(want.TableName) {
+		t.Fatalf("join table %q was not created", want.TableName)
+	}
+
+	if err := db.Table(want.TableName).Create(map[string]any{
+		"source_id": "urn:meal-plan:1", "target_id": "urn:restricted-diet:1", "position": 0,
+	}).Error; err != nil {
+		t.Fatalf("insert join row: %v", err)
+	}
+	if err := pm.UpdateJoinDisplay(ctx, want, "urn:restricted-diet:1", "Vegan"); err != nil {
+		t.Fatalf("UpdateJoinDisplay: %v", err)
+	}
+	var display string
+	db.Table(want.TableName).Select("display").Where("target_id = ?", "urn:restricted-diet:1").Scan(&display)
+	if display != "Vegan" {
+		t.Errorf("display = %q, want Vegan", display)
+	}
+
+	// Removing the property from the schema drops the registration.
+	if err := pm.EnsureTable(ctx, "meal-plan",
+		json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`), nil); err != nil {
+		t.Fatalf("EnsureTable (re-parse): %v", err)
+	}
+	if got := pm.ReverseJoinReferences("restricted-diet"); len(got) != 0 {
+		t.Errorf("ReverseJoinReferences after removal = %+v, want none", got)
+	}
+}
@@ -81,7 +81,24 @@ func ProvideGormDB(params struct {
 		&weosmodels.RoleResourceAccess{},
 		&weosmodels.Triple{},
 		&weosmodels.ResourcePermission{},
+		&weosmodels.Team{},
+		&weosmodels.TeamMember{},
+		&weosmodels.RoleAssignment{},
 		&weosmodels.BehaviorSettings{},
+		&weosmodels.PublishedRevision{},
+		&weosmodels.Job{},
+		&weosmodels.JobSchedule{},
+		&weosmodels.JobLock{},
+		&weosmodels.WebhookSubscription{},
+		&weosmodels.WebhookDelivery{},
+		&weosmodels.EventLogEntry{},
+		&weosmodels.EventCheckpoint{},
+		&weosmodels.EventDeadLetter{},
+		&weosmodels.AggregateSnapshot{},
+		&weosmodels.PendingEvent{},
+		&weosmodels.AuditEntry{},
+		&weosmodels.SubjectKey{},
+		&weosmodels.UniqueKey{},
 		&oauth.OAuthClient{},
 		&oauth.OAuthAuthorizationCode{},
 		&oauth.OAuthRefreshToken{},
@@ -89,6 +106,20 @@ func ProvideGormDB(params struct {
 	if err := db.AutoMigrate(models...); err != nil {
 		return GormDBResult{}, fmt.Errorf("failed to run auto migrate: %w", err)
 	}
+	// Team grants replaced the (resource_id, agent_id) unique index, which
+	// would allow only one team grant per resource.
+	if db.Migrator().HasIndex(&weosmodels.ResourcePermission{}, "idx_rp_resource_agent") {
+		if err := db.Migrator().DropIndex(&weosmodels.ResourcePermission{}, "idx_rp_resource_agent"); err != nil {
+			return GormDBResult{}, fmt.Errorf("failed to drop resource permission index: %w", err)
+		}
+	}
+	// Sidebar settings became per account, so the role alone is no longer
+	// unique.
+	if db.Migrator().HasIndex(&weosmodels.SidebarSettings{}, "idx_sidebar_settings_role") {
+		if err := db.Migrator().DropIndex(&weosmodels.SidebarSettings{}, "idx_sidebar_settings_role"); err != nil {
+			return GormDBResult{}, fmt.Errorf("failed to drop sidebar settings index: %w", err)
+		}
+	}
 	if err := authgorm.AutoMigrate(db); err != nil {
 		return GormDBResult{}, fmt.Errorf("failed to run auth auto migrate: %w", err)
 	}
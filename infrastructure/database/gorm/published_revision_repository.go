@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PublishedRevisionRepository struct {
+	db *gorm.DB
+}
+
+type PublishedRevisionRepositoryResult struct {
+	fx.Out
+	Repository repositories.PublishedRevisionRepository
+}
+
+func ProvidePublishedRevisionRepository(db *gorm.DB) (PublishedRevisionRepositoryResult, error) {
+	return PublishedRevisionRepositoryResult{
+		Repository: &PublishedRevisionRepository{db: db},
+	}, nil
+}
+
+// Save replaces the resource's public revision.
+func (r *PublishedRevisionRepository) Save(ctx context.Context, rev *entities.PublishedRevision) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "resource_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type_slug", "account_id", "data", "published_at"}),
+	}).Create(models.FromPublishedRevision(rev))
+	if result.Error != nil {
+		return fmt.Errorf("failed to save published revision: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *PublishedRevisionRepository) Delete(ctx context.Context, resourceID string) error {
+	result := r.db.WithContext(ctx).
+		Where("resource_id = ?", resourceID).
+		Delete(&models.PublishedRevision{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete published revision: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *PublishedRevisionRepository) FindByID(
+	ctx context.Context, resourceID string,
+) (*entities.PublishedRevision, error) {
+	var row models.PublishedRevision
+	err := r.db.WithContext(ctx).Where("resource_id = ?", resourceID).First(&row).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find published revision: %w", err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *PublishedRevisionRepository) FindAllByType(
+	ctx context.Context, accountID, typeSlug, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.PublishedRevision], error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := r.db.WithContext(ctx).Where("account_id = ? AND type_slug = ?", accountID, typeSlug)
+	if cursor != "" {
+		query = query.Where("resource_id > ?", cursor)
+	}
+	var rows []models.PublishedRevision
+	if err := query.Order("resource_id ASC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return repositories.PaginatedResponse[*entities.PublishedRevision]{},
+			fmt.Errorf("failed to list published revisions: %w", err)
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	page := repositories.PaginatedResponse[*entities.PublishedRevision]{
+		Data:    make([]*entities.PublishedRevision, 0, len(rows)),
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	for i := range rows {
+		page.Data = append(page.Data, rows[i].ToEntity())
+	}
+	if hasMore {
+		page.Cursor = rows[len(rows)-1].ResourceID
+	}
+	return page, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+type ReadModelTruncater struct {
+	db      *gorm.DB
+	projMgr repositories.ProjectionManager
+}
+
+type ReadModelTruncaterResult struct {
+	fx.Out
+	Truncater repositories.ReadModelTruncater
+}
+
+func ProvideReadModelTruncater(params struct {
+	fx.In
+	DB            *gorm.DB
+	ProjectionMgr repositories.ProjectionManager
+}) (ReadModelTruncaterResult, error) {
+	return ReadModelTruncaterResult{
+		Truncater: &ReadModelTruncater{db: params.DB, projMgr: params.ProjectionMgr},
+	}, nil
+}
+
+// TruncateResources clears one type's rows from its own projection table and
+// its ancestors' (dual-projection), or every projection table when typeSlug
+// is empty. Join rows go first, while their source rows can still be found.
+func (t *ReadModelTruncater) TruncateResources(ctx context.Context, typeSlug string) error {
+	db := t.db.WithContext(ctx)
+	if typeSlug == "" {
+		var slugs []string
+		if err := db.Model(&models.ResourceType{}).Pluck("slug", &slugs).Error; err != nil {
+			return fmt.Errorf("failed to list resource types: %w", err)
+		}
+		for _, slug := range slugs {
+			if !t.projMgr.HasProjectionTable(slug) {
+				continue
+			}
+			for _, ref := range t.projMgr.JoinReferences(slug) {
+				if err := db.Table(ref.TableName).Where("1 = 1").Delete(map[string]any{}).Error; err != nil {
+					return fmt.Errorf("failed to truncate %s: %w", ref.TableName, err)
+				}
+			}
+			table := t.projMgr.TableName(slug)
+			if err := db.Table(table).Where("1 = 1").Delete(map[string]any{}).Error; err != nil {
+				return fmt.Errorf("failed to truncate %s: %w", table, err)
+			}
+		}
+		if err := db.Where("1 = 1").Delete(&models.Resource{}).Error; err != nil {
+			return fmt.Errorf("failed to truncate resources: %w", err)
+		}
+		return nil
+	}
+
+	for _, slug := range append([]string{typeSlug}, t.projMgr.AncestorSlugs(typeSlug)...) {
+		if !t.projMgr.HasProjectionTable(slug) {
+			continue
+		}
+		table := t.projMgr.TableName(slug)
+		owned := db.Table(table).Select("id").Where("type_slug = ?", typeSlug)
+		for _, ref := range t.projMgr.JoinReferences(slug) {
+			if err := db.Table(ref.TableName).Where("source_id IN (?)", owned).
+				Delete(map[string]any{}).Error; err != nil {
+				return fmt.Errorf("failed to truncate %s rows of %s: %w", ref.TableName, typeSlug, err)
+			}
+		}
+		if err := db.Table(table).Where("type_slug = ?", typeSlug).Delete(map[string]any{}).Error; err != nil {
+			return fmt.Errorf("failed to truncate %s rows of %s: %w", table, typeSlug, err)
+		}
+	}
+	if err := db.Where("type_slug = ?", typeSlug).Delete(&models.Resource{}).Error; err != nil {
+		return fmt.Errorf("failed to truncate %s resources: %w", typeSlug, err)
+	}
+	return nil
+}
+
+func (t *ReadModelTruncater) TruncateTriples(ctx context.Context) error {
+	if err := t.db.WithContext(ctx).Where("1 = 1").Delete(&models.Triple{}).Error; err != nil {
+		return fmt.Errorf("failed to truncate triples: %w", err)
+	}
+	return nil
+}
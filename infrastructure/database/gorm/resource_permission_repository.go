@@ -46,9 +46,9 @@ func (r *ResourcePermissionRepository) Grant(
 		return fmt.Errorf("failed to convert permission: %w", err)
 	}
 
-	// Upsert: if (resource_id, agent_id) exists, update actions.
+	// Upsert: if (resource_id, agent_id, team_id) exists, update actions.
 	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "resource_id"}, {Name: "agent_id"}},
+		Columns:   []clause.Column{{Name: "resource_id"}, {Name: "agent_id"}, {Name: "team_id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"actions", "granted_by", "granted_at"}),
 	}).Create(model)
 	if result.Error != nil {
@@ -61,7 +61,7 @@ func (r *ResourcePermissionRepository) Revoke(
 	ctx context.Context, resourceID, agentID string,
 ) error {
 	result := r.db.WithContext(ctx).
-		Where("resource_id = ? AND agent_id = ?", resourceID, agentID).
+		Where("resource_id = ? AND agent_id = ? AND team_id = ''", resourceID, agentID).
 		Delete(&models.ResourcePermission{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to revoke permission: %w", result.Error)
@@ -69,6 +69,18 @@ func (r *ResourcePermissionRepository) Revoke(
 	return nil
 }
 
+func (r *ResourcePermissionRepository) RevokeTeam(
+	ctx context.Context, resourceID, teamID string,
+) error {
+	result := r.db.WithContext(ctx).
+		Where("resource_id = ? AND team_id = ?", resourceID, teamID).
+		Delete(&models.ResourcePermission{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke team permission: %w", result.Error)
+	}
+	return nil
+}
+
 func (r *ResourcePermissionRepository) FindByResource(
 	ctx context.Context, resourceID string,
 ) ([]*entities.ResourcePermission, error) {
@@ -93,25 +105,35 @@ func (r *ResourcePermissionRepository) FindByResource(
 func (r *ResourcePermissionRepository) HasPermission(
 	ctx context.Context, resourceID, agentID, action string,
 ) (bool, error) {
-	var row models.ResourcePermission
+	var rows []models.ResourcePermission
 	err := r.db.WithContext(ctx).
-		Where("resource_id = ? AND agent_id = ?", resourceID, agentID).
-		First(&row).Error
+		Where("resource_id = ?", resourceID).
+		Where(grantedToAgent, agentID, agentID).
+		Find(&rows).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return false, nil
-		}
 		return false, fmt.Errorf("failed to check permission: %w", err)
 	}
 
-	var actions []string
-	if err := json.Unmarshal([]byte(row.Actions), &actions); err != nil {
-		return false, nil
-	}
-	for _, a := range actions {
-		if a == action {
-			return true, nil
+	for _, row := range rows {
+		var actions []string
+		if err := json.Unmarshal([]byte(row.Actions), &actions); err != nil {
+			continue
+		}
+		for _, a := range actions {
+			if a == action {
+				return true, nil
+			}
 		}
 	}
 	return false, nil
 }
+
+// grantedToAgent is the condition matching resource_permissions rows that
+// apply to an agent: granted to them, or to a team they are a member of.
+// Team membership only counts while the agent is still a member of the
+// team's account, so leaving the account ends the team's access even though
+// the team_members row stays. It takes the agent ID twice.
+const grantedToAgent = "(agent_id = ? OR team_id IN (SELECT tm.team_id FROM team_members tm" +
+	" JOIN teams tt ON tt.id = tm.team_id" +
+	" JOIN account_members am ON am.account_id = tt.account_id AND am.agent_id = tm.agent_id" +
+	" WHERE tm.agent_id = ?))"
@@ -154,12 +154,12 @@ func (r *ResourceRepository) saveToProjection(
 	ldCtx := r.projMgr.Context(targetSlug)
 	ExtractFlatColumns(entity.Data(), ldCtx, row)
 	r.dropMissingColumns(targetSlug, row)
-	r.populateDisplayColumns(ctx, targetSlug, row,
-		buildLookupScope(entity.AccountID(), entity.CreatedBy()))
+	scope := buildLookupScope(entity.AccountID(), entity.CreatedBy())
+	r.populateDisplayColumns(ctx, targetSlug, row, scope)
 	if err := r.db.WithContext(ctx).Table(tableName).Create(row).Error; err != nil {
 		return fmt.Errorf("failed to save resource to projection %s: %w", tableName, err)
 	}
-	return nil
+	return r.syncJoinRows(ctx, targetSlug, entity.GetID(), entity.Data(), scope)
 }
 
 // updateProjectionBySlug upserts a resource's projection row in the table identified by targetSlug.
@@ -180,9 +180,18 @@ func (r *ResourceRepository) updateProjectionBySlug(
 	}
 	ldCtx := r.projMgr.Context(targetSlug)
 	ExtractFlatColumns(entity.Data(), ldCtx, row)
+	// Entity data is the full state, so a reference absent from it has been
+	// removed (an edit or an x-on-delete set-null). Clear the FK explicitly —
+	// the upsert only assigns columns present in row, and populateDisplayColumns
+	// then clears the matching _display column for the nil FK.
+	for _, ref := range r.projMgr.ForwardReferences(targetSlug) {
+		if _, ok := row[ref.FKColumn]; !ok {
+			row[ref.FKColumn] = nil
+		}
+	}
 	r.dropMissingColumns(targetSlug, row)
-	r.populateDisplayColumns(ctx, targetSlug, row,
-		buildLookupScope(entity.AccountID(), entity.CreatedBy()))
+	scope := buildLookupScope(entity.AccountID(), entity.CreatedBy())
+	r.populateDisplayColumns(ctx, targetSlug, row, scope)
 
 	// Build column list for ON CONFLICT UPDATE, excluding immutable fields.
 	immutable := map[string]bool{"id": true, "created_at": true, "created_by": true, "account_id": true}
@@ -201,7 +210,7 @@ func (r *ResourceRepository) updateProjectionBySlug(
 	if err != nil {
 		return fmt.Errorf("failed to upsert resource in %s: %w", tableName, err)
 	}
-	return nil
+	return r.syncJoinRows(ctx, targetSlug, entity.GetID(), entity.Data(), scope)
 }
 
 // dropMissingColumns removes keys from row that don't exist as columns in the target table.
@@ -241,7 +250,7 @@ func (r *ResourceRepository) dropMissingColumns(targetSlug string, row map[strin
 //   - the row's account_id must match the target's account_id when both sides
 //     are account-scoped (multi-tenant isolation);
 //   - the writer (scope.AgentID) must be the target's creator OR have an
-//     explicit "read" grant in resource_permissions.
+//     explicit "read" grant in resource_permissions, to them or their team.
 //
 // Passing scope explicitly (rather than reading it from the row map) is
 // important because partial-update paths like updateDataInProjection don't
@@ -568,21 +577,49 @@ func (r *ResourceRepository) FindByID(
 }
 
 // applyVisibilityScope adds ownership filtering to a query when a non-nil scope
-// is provided and the caller is not an admin.
+// is provided and the caller is not an admin. Admin scopes are limited to
+// their account instead.
 func applyVisibilityScope(query *gorm.DB, scope *repositories.VisibilityScope, tablePrefix string) *gorm.DB {
-	if scope == nil || scope.IsAdmin {
+	if scope == nil {
 		return query
 	}
 	col := "created_by"
 	idCol := "id"
+	accountCol := "account_id"
 	if tablePrefix != "" {
 		col = tablePrefix + "." + col
 		idCol = tablePrefix + "." + idCol
+		accountCol = tablePrefix + "." + accountCol
 	}
-	return query.Where(
-		col+" = ? OR "+idCol+" IN (SELECT resource_id FROM resource_permissions WHERE agent_id = ? AND actions LIKE ?)",
-		scope.AgentID, scope.AgentID, `%"read"%`,
-	)
+	if scope.IsAdmin {
+		if scope.AccountID != "" {
+			query = query.Where(accountCol+" = ?", scope.AccountID)
+		}
+		return query
+	}
+	cond, args := visibleTo(col, idCol, scope.AgentID, scope.Inherit, 0)
+	return query.Where(cond, args...)
+}
+
+// visibleTo returns the condition under which the resource in idCol is
+// visible to agentID: it created the resource, holds a "read" grant on it,
+// or can see a resource it inherits permissions from. Each inheritance step
+// is a subquery over the triples to the parent's resources row, aliased by
+// depth so nested steps don't shadow each other.
+func visibleTo(
+	createdByCol, idCol, agentID string, inherit []repositories.PermissionInheritance, depth int,
+) (string, []any) {
+	cond := createdByCol + " = ? OR " + idCol + " IN (SELECT resource_id FROM resource_permissions WHERE " +
+		grantedToAgent + " AND actions LIKE ?)"
+	args := []any{agentID, agentID, agentID, `%"read"%`}
+	for _, step := range inherit {
+		t, p := fmt.Sprintf("t%d", depth), fmt.Sprintf("p%d", depth)
+		parentCond, parentArgs := visibleTo(p+".created_by", p+".id", agentID, step.Parents, depth+1)
+		cond += fmt.Sprintf(" OR %s IN (SELECT %s.subject FROM triples %s JOIN resources %s ON %s.id = %s.object"+
+			" WHERE %s.predicate = ? AND (%s))", idCol, t, t, p, p, t, t, parentCond)
+		args = append(append(args, step.Predicate), parentArgs...)
+	}
+	return cond, args
 }
 
 func (r *ResourceRepository) FindAllByType(
@@ -908,6 +945,11 @@ var operatorMap = map[string]string{
 	"eq": "=", "ne": "!=", "gt": ">", "gte": ">=", "lt": "<", "lte": "<=",
 }
 
+// invalidFilter reports a filter a query cannot apply.
+func invalidFilter(f repositories.FilterCondition) error {
+	return fmt.Errorf("%s %s: %w", f.Field, f.Operator, repositories.ErrInvalidFilter)
+}
+
 func (r *ResourceRepository) FindAllByTypeWithFilters(
 	ctx context.Context, typeSlug string, filters []repositories.FilterCondition,
 	cursor string, limit int, sort repositories.SortOptions, scope *repositories.VisibilityScope,
@@ -950,14 +992,22 @@ func (r *ResourceRepository) findAllFromProjectionWithFilters(
 	query = applyVisibilityScope(query, scope, tbl)
 
 	for _, f := range filters {
+		if f.Operator == containsOperator {
+			ref, ok := r.joinReferenceFor(typeSlug, f.Field)
+			if !ok {
+				return repositories.PaginatedResponse[*entities.Resource]{}, invalidFilter(f)
+			}
+			query = query.Where(joinContainsClause(tbl+".id", ref), f.Value)
+			continue
+		}
 		sqlOp, ok := operatorMap[f.Operator]
 		if !ok {
-			continue
+			return repositories.PaginatedResponse[*entities.Resource]{}, invalidFilter(f)
 		}
 		fc := utils.CamelToSnake(f.Field)
 		if !standardColumnNames[fc] && fc != "id" {
 			if !r.db.Migrator().HasColumn(tableName, fc) {
-				continue
+				return repositories.PaginatedResponse[*entities.Resource]{}, invalidFilter(f)
 			}
 		}
 		query = query.Where(tbl+"."+fc+" "+sqlOp+" ?", f.Value)
@@ -1066,12 +1116,17 @@ func (r *ResourceRepository) FindFlatByID(
 	for k, v := range row {
 		camelRow[utils.SnakeToCamel(k)] = v
 	}
+	if err := r.attachJoinValues(ctx, typeSlug, []map[string]any{camelRow}); err != nil {
+		return nil, err
+	}
 	return camelRow, nil
 }
 
 // findAllFlatFromProjection queries the projection table directly and returns flat rows
 // with all columns (including _display). No JOIN with the resources table.
 // Column names are converted from snake_case to camelCase for JSON API responses.
+// Multi-valued references are read from their join tables and attached as
+// arrays of {id, display} pairs.
 func (r *ResourceRepository) findAllFlatFromProjection(
 	ctx context.Context, typeSlug string, filters []repositories.FilterCondition,
 	cursor string, limit int, sort repositories.SortOptions, scope *repositories.VisibilityScope,
@@ -1092,14 +1147,22 @@ func (r *ResourceRepository) findAllFlatFromProjection(
 	query = applyVisibilityScope(query, scope, "")
 
 	for _, f := range filters {
+		if f.Operator == containsOperator {
+			ref, ok := r.joinReferenceFor(typeSlug, f.Field)
+			if !ok {
+				return repositories.PaginatedResponse[map[string]any]{}, invalidFilter(f)
+			}
+			query = query.Where(joinContainsClause("id", ref), f.Value)
+			continue
+		}
 		sqlOp, ok := operatorMap[f.Operator]
 		if !ok {
-			continue
+			return repositories.PaginatedResponse[map[string]any]{}, invalidFilter(f)
 		}
 		fc := utils.CamelToSnake(f.Field)
 		if !standardColumnNames[fc] && fc != "id" {
 			if !r.db.Migrator().HasColumn(tableName, fc) {
-				continue
+				return repositories.PaginatedResponse[map[string]any]{}, invalidFilter(f)
 			}
 		}
 		query = query.Where(fc+" "+sqlOp+" ?", f.Value)
@@ -1146,6 +1209,9 @@ func (r *ResourceRepository) findAllFlatFromProjection(
 	if !hasMore {
 		nextCursor = ""
 	}
+	if err := r.attachJoinValues(ctx, typeSlug, result); err != nil {
+		return repositories.PaginatedResponse[map[string]any]{}, err
+	}
 
 	return repositories.PaginatedResponse[map[string]any]{
 		Data: result, Cursor: nextCursor, Limit: limit, HasMore: hasMore,
@@ -1169,7 +1235,7 @@ func (r *ResourceRepository) findAllFromGenericWithFilters(
 	for _, f := range filters {
 		sqlOp, ok := operatorMap[f.Operator]
 		if !ok {
-			continue
+			return repositories.PaginatedResponse[*entities.Resource]{}, invalidFilter(f)
 		}
 		query = query.Where("json_extract(data, ?) "+sqlOp+" ?", "$."+f.Field, f.Value)
 	}
@@ -1275,16 +1341,16 @@ func (r *ResourceRepository) updateDataInProjection(
 		return err
 	}
 	r.populateDisplayColumns(ctx, targetSlug, row, scope)
-	if len(row) == 0 {
-		return nil
-	}
-	result := r.db.WithContext(ctx).Table(tableName).
-		Where("id = ?", id).Updates(row)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update projection data in %s: %w", tableName, result.Error)
+	if len(row) > 0 {
+		result := r.db.WithContext(ctx).Table(tableName).
+			Where("id = ?", id).Updates(row)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update projection data in %s: %w", tableName, result.Error)
+		}
+		// If ancestor row doesn't exist yet, skip (UpdateData is a partial update).
 	}
-	// If ancestor row doesn't exist yet, skip (UpdateData is a partial update).
-	return nil
+	// data is the full document, so the join tables are replaced wholesale.
+	return r.syncJoinRows(ctx, targetSlug, id, data, scope)
 }
 
 func (r *ResourceRepository) Update(
@@ -1349,5 +1415,5 @@ func (r *ResourceRepository) deleteFromProjectionTable(
 	if err != nil {
 		return fmt.Errorf("failed to delete resource from %s: %w", tableName, err)
 	}
-	return nil
+	return r.deleteJoinRows(ctx, targetSlug, id)
 }
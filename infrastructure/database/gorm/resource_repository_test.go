@@ -12,6 +12,8 @@ import (
 	"github.com/wepala/weos/v3/domain/entities"
 	"github.com/wepala/weos/v3/domain/repositories"
 	"github.com/wepala/weos/v3/infrastructure/models"
+
+	authmodels "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/models"
 )
 
 func setupDualProjectionTest(t *testing.T) (
@@ -19,7 +21,8 @@ func setupDualProjectionTest(t *testing.T) (
 ) {
 	t.Helper()
 	db := newTestDB(t)
-	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}); err != nil {
+	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}, &models.TeamMember{},
+		&models.Team{}, &authmodels.AccountMemberModel{}); err != nil {
 		t.Fatalf("migrate resources: %v", err)
 	}
 	pm := &projectionManager{db: db, logger: &testLogger{}}
@@ -158,7 +161,8 @@ func TestDualProjection_DeleteRemovesFromBothTables(t *testing.T) {
 func setupReferenceProjectionTest(t *testing.T) (*ResourceRepository, context.Context) {
 	t.Helper()
 	db := newTestDB(t)
-	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}); err != nil {
+	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}, &models.TeamMember{},
+		&models.Team{}, &authmodels.AccountMemberModel{}); err != nil {
 		t.Fatalf("migrate resources: %v", err)
 	}
 	pm := &projectionManager{db: db, logger: &testLogger{}}
@@ -553,7 +557,8 @@ func (l *recordingLogger) Warn(_ context.Context, msg string, _ ...any) {
 func TestSaveToProjection_DisplayLookupError_LogsAndPersists(t *testing.T) {
 	t.Parallel()
 	db := newTestDB(t)
-	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}); err != nil {
+	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}, &models.TeamMember{},
+		&models.Team{}, &authmodels.AccountMemberModel{}); err != nil {
 		t.Fatalf("migrate resources: %v", err)
 	}
 	logger := &recordingLogger{}
@@ -649,7 +654,8 @@ func TestPopulateDisplayColumns_NonStringFK_LogsAndSkips(t *testing.T) {
 func TestSaveToProjection_DualProjectionAncestor_SkipsMissingDisplayColumn(t *testing.T) {
 	t.Parallel()
 	db := newTestDB(t)
-	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}); err != nil {
+	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}, &models.TeamMember{},
+		&models.Team{}, &authmodels.AccountMemberModel{}); err != nil {
 		t.Fatalf("migrate resources: %v", err)
 	}
 	pm := &projectionManager{db: db, logger: &testLogger{}}
@@ -876,7 +882,8 @@ func TestUpdateProjection_RebindToUnknownReference_ClearsDisplay(t *testing.T) {
 func TestUpdateProjection_RebindOnLookupError_ClearsDisplay(t *testing.T) {
 	t.Parallel()
 	db := newTestDB(t)
-	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}); err != nil {
+	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}, &models.TeamMember{},
+		&models.Team{}, &authmodels.AccountMemberModel{}); err != nil {
 		t.Fatalf("migrate: %v", err)
 	}
 	pm := &projectionManager{db: db, logger: &testLogger{}}
@@ -1316,3 +1323,201 @@ func TestLoadRowOwnerScope_DBError_FailsClosed(t *testing.T) {
 		t.Errorf("error = %v, want wrap from load row owner scope", err)
 	}
 }
+
+// setupJoinProjectionTest creates an "ingredient" type and a "recipe" type
+// whose ingredients property is a multi-valued reference to ingredient.
+func setupJoinProjectionTest(t *testing.T) (*ResourceRepository, context.Context) {
+	t.Helper()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Resource{}, &models.ResourcePermission{}, &models.TeamMember{},
+		&models.Team{}, &authmodels.AccountMemberModel{}); err != nil {
+		t.Fatalf("migrate resources: %v", err)
+	}
+	pm := &projectionManager{db: db, logger: &testLogger{}}
+	ctx := context.Background()
+
+	ldCtx := json.RawMessage(`{"@vocab":"https://schema.org/"}`)
+	ingredientSchema := json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`)
+	recipeSchema := json.RawMessage(`{"type":"object","properties":{` +
+		`"name":{"type":"string"},` +
+		`"ingredients":{"type":"array","items":{"type":"string"},` +
+		`"x-resource-type":"ingredient","x-display-property":"name"}}}`)
+	if err := pm.EnsureTable(ctx, "ingredient", ingredientSchema, ldCtx); err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.EnsureTable(ctx, "recipe", recipeSchema, ldCtx); err != nil {
+		t.Fatal(err)
+	}
+	return &ResourceRepository{db: db, projMgr: pm, logger: &testLogger{}}, ctx
+}
+
+func recipeGraph(id, name string, ingredientIDs ...string) string {
+	refs := make([]string, 0, len(ingredientIDs))
+	for _, ingredientID := range ingredientIDs {
+		refs = append(refs, fmt.Sprintf(`{"@id":%q}`, ingredientID))
+	}
+	return fmt.Sprintf(`{"@graph":[{"@id":%q,"@type":"Recipe","name":%q},`+
+		`{"@id":%q,"https://schema.org/ingredients":[%s]}]}`,
+		id, name, id, strings.Join(refs, ","))
+}
+
+func TestJoinProjection_BackfillsANewJoinTable(t *testing.T) {
+	t.Parallel()
+	repo, ctx := setupJoinProjectionTest(t)
+	bread := makeTestResource(t, "urn:recipe:bread", "recipe",
+		recipeGraph("urn:recipe:bread", "Bread", "urn:ingredient:flour", "urn:ingredient:salt"))
+	if err := repo.Save(ctx, bread); err != nil {
+		t.Fatalf("Save bread: %v", err)
+	}
+
+	// A database from before join tables has the resources but no table.
+	if err := repo.db.Migrator().DropTable("recipe_ingredients_refs"); err != nil {
+		t.Fatal(err)
+	}
+	recipeSchema := json.RawMessage(`{"type":"object","properties":{` +
+		`"name":{"type":"string"},` +
+		`"ingredients":{"type":"array","items":{"type":"string"},"x-resource-type":"ingredient"}}}`)
+	if err := repo.projMgr.EnsureTable(ctx, "recipe", recipeSchema,
+		json.RawMessage(`{"@vocab":"https://schema.org/"}`)); err != nil {
+		t.Fatalf("EnsureTable: %v", err)
+	}
+
+	row, err := repo.FindFlatByID(ctx, "recipe", "urn:recipe:bread")
+	if err != nil {
+		t.Fatalf("FindFlatByID: %v", err)
+	}
+	got, _ := json.Marshal(row["ingredients"])
+	want := `[{"display":null,"id":"urn:ingredient:flour"},{"display":null,"id":"urn:ingredient:salt"}]`
+	if string(got) != want {
+		t.Errorf("ingredients after the upgrade = %s, want %s", got, want)
+	}
+}
+
+func TestJoinProjection_WriteFilterAndDelete(t *testing.T) {
+	t.Parallel()
+	repo, ctx := setupJoinProjectionTest(t)
+
+	for id, name := range map[string]string{
+		"urn:ingredient:salt": "Salt", "urn:ingredient:flour": "Flour", "urn:ingredient:egg": "Egg",
+	} {
+		if err := repo.Save(ctx, makeTestResource(t, id, "ingredient",
+			fmt.Sprintf(`{"name":%q}`, name))); err != nil {
+			t.Fatalf("Save %s: %v", id, err)
+		}
+	}
+	bread := makeTestResource(t, "urn:recipe:bread", "recipe",
+		recipeGraph("urn:recipe:bread", "Bread", "urn:ingredient:flour", "urn:ingredient:salt"))
+	if err := repo.Save(ctx, bread); err != nil {
+		t.Fatalf("Save bread: %v", err)
+	}
+	omelette := makeTestResource(t, "urn:recipe:omelette", "recipe",
+		recipeGraph("urn:recipe:omelette", "Omelette", "urn:ingredient:egg", "urn:ingredient:salt"))
+	if err := repo.Save(ctx, omelette); err != nil {
+		t.Fatalf("Save omelette: %v", err)
+	}
+
+	if repo.db.Migrator().HasColumn("recipes", "ingredients") {
+		t.Error("multi-valued reference should not get a column on the projection table")
+	}
+
+	row, err := repo.FindFlatByID(ctx, "recipe", "urn:recipe:bread")
+	if err != nil {
+		t.Fatalf("FindFlatByID: %v", err)
+	}
+	got, _ := json.Marshal(row["ingredients"])
+	want := `[{"display":"Flour","id":"urn:ingredient:flour"},{"display":"Salt","id":"urn:ingredient:salt"}]`
+	if string(got) != want {
+		t.Errorf("ingredients = %s, want %s", got, want)
+	}
+
+	page, err := repo.FindAllByTypeFlatWithFilters(ctx, "recipe", []repositories.FilterCondition{
+		{Field: "ingredients", Operator: "contains", Value: "urn:ingredient:egg"},
+	}, "", 10, repositories.SortOptions{}, nil)
+	if err != nil {
+		t.Fatalf("contains filter: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0]["id"] != "urn:recipe:omelette" {
+		t.Fatalf("contains egg: got %v, want only the omelette", page.Data)
+	}
+
+	resources, err := repo.FindAllByTypeWithFilters(ctx, "recipe", []repositories.FilterCondition{
+		{Field: "ingredients", Operator: "contains", Value: "urn:ingredient:salt"},
+	}, "", 10, repositories.SortOptions{}, nil)
+	if err != nil {
+		t.Fatalf("contains filter (entities): %v", err)
+	}
+	if len(resources.Data) != 2 {
+		t.Errorf("contains salt: got %d recipes, want 2", len(resources.Data))
+	}
+
+	// Dropping salt from the bread replaces its join rows.
+	bread = makeTestResource(t, "urn:recipe:bread", "recipe",
+		recipeGraph("urn:recipe:bread", "Bread", "urn:ingredient:flour"))
+	if err := repo.Update(ctx, bread); err != nil {
+		t.Fatalf("Update bread: %v", err)
+	}
+	var count int64
+	repo.db.Table("recipe_ingredients_refs").Where("source_id = ?", "urn:recipe:bread").Count(&count)
+	if count != 1 {
+		t.Errorf("bread join rows after update = %d, want 1", count)
+	}
+
+	if err := repo.Delete(ctx, "urn:recipe:omelette"); err != nil {
+		t.Fatalf("Delete omelette: %v", err)
+	}
+	repo.db.Table("recipe_ingredients_refs").Where("source_id = ?", "urn:recipe:omelette").Count(&count)
+	if count != 0 {
+		t.Errorf("omelette join rows after delete = %d, want 0", count)
+	}
+}
+
+func TestFindAllByTypeWithFilters_AdminScopeLimitsToAccount(t *testing.T) {
+	t.Parallel()
+	repo, _, ctx := setupDualProjectionTest(t)
+
+	// "loan" has a projection table; "note" is only in the resources table.
+	for _, r := range []*entities.Resource{
+		makeTestResourceForAccount(t, "urn:loan:a", "loan", `{"name":"Same"}`, "acct-1"),
+		makeTestResourceForAccount(t, "urn:loan:b", "loan", `{"name":"Same"}`, "acct-2"),
+		makeTestResourceForAccount(t, "urn:note:a", "note", `{"name":"Same"}`, "acct-1"),
+		makeTestResourceForAccount(t, "urn:note:b", "note", `{"name":"Same"}`, "acct-2"),
+	} {
+		if err := repo.Save(ctx, r); err != nil {
+			t.Fatalf("Save %s: %v", r.GetID(), err)
+		}
+	}
+
+	filters := []repositories.FilterCondition{{Field: "name", Operator: "eq", Value: "Same"}}
+	scope := &repositories.VisibilityScope{AccountID: "acct-1", IsAdmin: true}
+	for _, typeSlug := range []string{"loan", "note"} {
+		page, err := repo.FindAllByTypeWithFilters(ctx, typeSlug, filters, "", 10, repositories.SortOptions{}, scope)
+		if err != nil {
+			t.Fatalf("%s: %v", typeSlug, err)
+		}
+		if len(page.Data) != 1 || page.Data[0].AccountID() != "acct-1" {
+			t.Errorf("%s: got %d rows, want only acct-1's", typeSlug, len(page.Data))
+		}
+	}
+}
+
+func TestFindAllByTypeWithFilters_RefusesFiltersItCannotApply(t *testing.T) {
+	t.Parallel()
+	repo, _, ctx := setupDualProjectionTest(t)
+
+	for _, f := range []repositories.FilterCondition{
+		{Field: "missing", Operator: "eq", Value: "x"},
+		{Field: "name", Operator: "like", Value: "x"},
+		{Field: "name", Operator: "contains", Value: "x"},
+	} {
+		_, err := repo.FindAllByTypeWithFilters(ctx, "loan", []repositories.FilterCondition{f}, "", 10,
+			repositories.SortOptions{}, nil)
+		if !errors.Is(err, repositories.ErrInvalidFilter) {
+			t.Errorf("filter %+v: err = %v, want ErrInvalidFilter", f, err)
+		}
+		_, err = repo.FindAllByTypeFlatWithFilters(ctx, "loan", []repositories.FilterCondition{f}, "", 10,
+			repositories.SortOptions{}, nil)
+		if !errors.Is(err, repositories.ErrInvalidFilter) {
+			t.Errorf("flat filter %+v: err = %v, want ErrInvalidFilter", f, err)
+		}
+	}
+}
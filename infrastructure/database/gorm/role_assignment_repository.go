@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+type RoleAssignmentRepository struct {
+	db *gorm.DB
+}
+
+type RoleAssignmentRepositoryResult struct {
+	fx.Out
+	Repository repositories.RoleAssignmentRepository
+}
+
+func ProvideRoleAssignmentRepository(db *gorm.DB) (RoleAssignmentRepositoryResult, error) {
+	return RoleAssignmentRepositoryResult{
+		Repository: &RoleAssignmentRepository{db: db},
+	}, nil
+}
+
+func (r *RoleAssignmentRepository) Save(ctx context.Context, assignment *entities.RoleAssignment) error {
+	if err := r.db.WithContext(ctx).Create(models.FromRoleAssignment(assignment)).Error; err != nil {
+		return fmt.Errorf("failed to save role assignment: %w", err)
+	}
+	return nil
+}
+
+func (r *RoleAssignmentRepository) FindByID(ctx context.Context, id string) (*entities.RoleAssignment, error) {
+	var row models.RoleAssignment
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find role assignment: %w", err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *RoleAssignmentRepository) FindByAgent(
+	ctx context.Context, agentID string,
+) ([]*entities.RoleAssignment, error) {
+	return r.find(ctx, "agent_id = ?", agentID)
+}
+
+func (r *RoleAssignmentRepository) FindByAccount(
+	ctx context.Context, accountID string,
+) ([]*entities.RoleAssignment, error) {
+	return r.find(ctx, "account_id = ?", accountID)
+}
+
+func (r *RoleAssignmentRepository) find(ctx context.Context, query string, arg string) (
+	[]*entities.RoleAssignment, error,
+) {
+	var rows []models.RoleAssignment
+	if err := r.db.WithContext(ctx).Where(query, arg).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list role assignments: %w", err)
+	}
+	out := make([]*entities.RoleAssignment, 0, len(rows))
+	for i := range rows {
+		out = append(out, rows[i].ToEntity())
+	}
+	return out, nil
+}
+
+func (r *RoleAssignmentRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.RoleAssignment{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete role assignment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *RoleAssignmentRepository) DeleteByType(ctx context.Context, typeSlug string) (int64, error) {
+	return r.deleteScoped(ctx, entities.RoleScopeType, "type_slug = ?", typeSlug)
+}
+
+func (r *RoleAssignmentRepository) DeleteByResource(ctx context.Context, resourceID string) (int64, error) {
+	return r.deleteScoped(ctx, entities.RoleScopeSubtree, "resource_id = ?", resourceID)
+}
+
+func (r *RoleAssignmentRepository) deleteScoped(ctx context.Context, scope, query, arg string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("scope = ?", scope).Where(query, arg).Delete(&models.RoleAssignment{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to revoke %s role assignments: %w", scope, result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -18,6 +18,7 @@ package gorm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/wepala/weos/v3/domain/entities"
@@ -26,11 +27,12 @@ import (
 	"gorm.io/gorm"
 )
 
-// RoleResourceAccessRepository manages the singleton RBAC access configuration.
-// This is a system-wide configuration setting, not a domain entity. It intentionally
-// bypasses event sourcing / UnitOfWork because: (1) it is a single config row (ID=1),
-// (2) changes are administrative and infrequent, and (3) Casbin policy sync provides
-// the audit trail for authorization changes.
+// RoleResourceAccessRepository manages the RBAC access configuration: one row per
+// account that has its own, and the global default (empty account ID) the others use.
+// This is configuration, not a domain entity. It intentionally bypasses event
+// sourcing / UnitOfWork because: (1) it is a single config row per account, (2) changes
+// are administrative and infrequent, and (3) Casbin policy sync provides the audit
+// trail for authorization changes.
 type RoleResourceAccessRepository struct {
 	db *gorm.DB
 }
@@ -39,25 +41,67 @@ func ProvideRoleResourceAccessRepository(db *gorm.DB) *RoleResourceAccessReposit
 	return &RoleResourceAccessRepository{db: db}
 }
 
-func (r *RoleResourceAccessRepository) Get(ctx context.Context) (*models.RoleResourceAccess, error) {
+// Get returns the account's configuration, falling back to the global default,
+// which is created on first use.
+func (r *RoleResourceAccessRepository) Get(ctx context.Context, accountID string) (*models.RoleResourceAccess, error) {
 	var settings models.RoleResourceAccess
-	result := r.db.WithContext(ctx).FirstOrCreate(&settings, models.RoleResourceAccess{ID: 1})
+	if accountID != "" {
+		err := r.db.WithContext(ctx).Where("account_id = ?", accountID).First(&settings).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			return withAccessDefaults(&settings), nil
+		}
+	}
+	settings = models.RoleResourceAccess{}
+	result := r.db.WithContext(ctx).Where("account_id = ?", "").FirstOrCreate(&settings)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	return withAccessDefaults(&settings), nil
+}
+
+func withAccessDefaults(settings *models.RoleResourceAccess) *models.RoleResourceAccess {
 	if settings.Access == "" {
 		settings.Access = "{}"
 	}
-	return &settings, nil
+	if settings.Fields == "" {
+		settings.Fields = "{}"
+	}
+	if settings.Deny == "" {
+		settings.Deny = "{}"
+	}
+	if settings.Inherits == "" {
+		settings.Inherits = "{}"
+	}
+	return settings
 }
 
-func (r *RoleResourceAccessRepository) Save(ctx context.Context, settings *models.RoleResourceAccess) error {
-	settings.ID = 1
+// Save replaces the account's configuration, or the global default when
+// accountID is empty.
+func (r *RoleResourceAccessRepository) Save(
+	ctx context.Context, accountID string, settings *models.RoleResourceAccess,
+) error {
+	var existing models.RoleResourceAccess
+	err := r.db.WithContext(ctx).Where("account_id = ?", accountID).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	settings.ID = existing.ID
+	settings.AccountID = accountID
 	return r.db.WithContext(ctx).Save(settings).Error
 }
 
-func (r *RoleResourceAccessRepository) GetAccessMap(ctx context.Context) (entities.AccessMap, error) {
-	settings, err := r.Get(ctx)
+func (r *RoleResourceAccessRepository) Accounts(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&models.RoleResourceAccess{}).
+		Where("account_id <> ?", "").Pluck("account_id", &ids).Error
+	return ids, err
+}
+
+func (r *RoleResourceAccessRepository) GetAccessMap(ctx context.Context, accountID string) (entities.AccessMap, error) {
+	settings, err := r.Get(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +111,39 @@ func (r *RoleResourceAccessRepository) GetAccessMap(ctx context.Context) (entiti
 	}
 	return m, nil
 }
+
+func (r *RoleResourceAccessRepository) GetFieldAccessMap(ctx context.Context, accountID string) (entities.FieldAccessMap, error) {
+	settings, err := r.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	var m entities.FieldAccessMap
+	if err := json.Unmarshal([]byte(settings.Fields), &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal field access map: %w", err)
+	}
+	return m, nil
+}
+
+func (r *RoleResourceAccessRepository) GetDenyMap(ctx context.Context, accountID string) (entities.AccessMap, error) {
+	settings, err := r.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	var m entities.AccessMap
+	if err := json.Unmarshal([]byte(settings.Deny), &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deny map: %w", err)
+	}
+	return m, nil
+}
+
+func (r *RoleResourceAccessRepository) GetRoleHierarchy(ctx context.Context, accountID string) (entities.RoleHierarchy, error) {
+	settings, err := r.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	var h entities.RoleHierarchy
+	if err := json.Unmarshal([]byte(settings.Inherits), &h); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal role hierarchy: %w", err)
+	}
+	return h, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wepala/weos/v3/infrastructure/models"
+)
+
+func TestRoleResourceAccessRepository_AccountsFallBackToDefault(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.RoleResourceAccess{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	repo := &RoleResourceAccessRepository{db: db}
+	ctx := context.Background()
+
+	if err := repo.Save(ctx, "", &models.RoleResourceAccess{Access: `{"member":{"task":["read"]}}`}); err != nil {
+		t.Fatalf("Save default: %v", err)
+	}
+	if err := repo.Save(ctx, "acct-1", &models.RoleResourceAccess{Access: `{"member":{"project":["read"]}}`}); err != nil {
+		t.Fatalf("Save account: %v", err)
+	}
+
+	own, err := repo.Get(ctx, "acct-1")
+	if err != nil || own.AccountID != "acct-1" {
+		t.Fatalf("Get(acct-1) = %+v, %v; want the account's own row", own, err)
+	}
+	fallback, err := repo.Get(ctx, "acct-2")
+	if err != nil || fallback.AccountID != "" {
+		t.Fatalf("Get(acct-2) = %+v, %v; want the global default", fallback, err)
+	}
+	access, err := repo.GetAccessMap(ctx, "acct-2")
+	if err != nil {
+		t.Fatalf("GetAccessMap: %v", err)
+	}
+	if _, ok := access["member"]["task"]; !ok {
+		t.Errorf("acct-2 access = %v, want the default's", access)
+	}
+	if access, _ := repo.GetAccessMap(ctx, "acct-1"); access["member"]["task"] != nil {
+		t.Errorf("acct-1 access = %v, want its own only", access)
+	}
+
+	// Saving again updates the account's row instead of adding one.
+	if err := repo.Save(ctx, "acct-1", &models.RoleResourceAccess{Access: `{}`}); err != nil {
+		t.Fatalf("Save account again: %v", err)
+	}
+	accounts, err := repo.Accounts(ctx)
+	if err != nil || len(accounts) != 1 || accounts[0] != "acct-1" {
+		t.Errorf("Accounts = %v, %v; want [acct-1]", accounts, err)
+	}
+}
@@ -18,6 +18,7 @@ package gorm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/wepala/weos/v3/infrastructure/models"
@@ -27,9 +28,10 @@ import (
 
 var defaultRoles = []string{"admin", "instructor"}
 
-// RoleSettingsRepository manages the singleton role names configuration.
-// This is a system-wide configuration setting, not a domain entity. It intentionally
-// bypasses event sourcing / UnitOfWork because it is a single config row (ID=1) with
+// RoleSettingsRepository manages the role names configuration: one row per account
+// that has its own list, and the global default (empty account ID) the others use.
+// This is configuration, not a domain entity. It intentionally bypasses event
+// sourcing / UnitOfWork because it is a single config row per account with
 // infrequent administrative changes.
 type RoleSettingsRepository struct {
 	db *gorm.DB
@@ -39,9 +41,20 @@ func ProvideRoleSettingsRepository(db *gorm.DB) *RoleSettingsRepository {
 	return &RoleSettingsRepository{db: db}
 }
 
-func (r *RoleSettingsRepository) Get(ctx context.Context) (*models.RoleSettings, error) {
+// Get returns the account's role names, falling back to the global default.
+func (r *RoleSettingsRepository) Get(ctx context.Context, accountID string) (*models.RoleSettings, error) {
 	var settings models.RoleSettings
-	result := r.db.WithContext(ctx).FirstOrCreate(&settings, models.RoleSettings{ID: 1})
+	if accountID != "" {
+		err := r.db.WithContext(ctx).Where("account_id = ?", accountID).First(&settings).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			return &settings, nil
+		}
+	}
+	settings = models.RoleSettings{}
+	result := r.db.WithContext(ctx).Where("account_id = ?", "").FirstOrCreate(&settings)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -56,13 +69,21 @@ func (r *RoleSettingsRepository) Get(ctx context.Context) (*models.RoleSettings,
 	return &settings, nil
 }
 
-func (r *RoleSettingsRepository) Save(ctx context.Context, settings *models.RoleSettings) error {
-	settings.ID = 1
+// Save replaces the account's role names, or the global default when
+// accountID is empty.
+func (r *RoleSettingsRepository) Save(ctx context.Context, accountID string, settings *models.RoleSettings) error {
+	var existing models.RoleSettings
+	err := r.db.WithContext(ctx).Where("account_id = ?", accountID).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	settings.ID = existing.ID
+	settings.AccountID = accountID
 	return r.db.WithContext(ctx).Save(settings).Error
 }
 
-func (r *RoleSettingsRepository) GetRoleNames(ctx context.Context) ([]string, error) {
-	settings, err := r.Get(ctx)
+func (r *RoleSettingsRepository) GetRoleNames(ctx context.Context, accountID string) ([]string, error) {
+	settings, err := r.Get(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
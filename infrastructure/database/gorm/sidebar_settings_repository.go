@@ -26,6 +26,8 @@ import (
 
 const defaultRole = "default"
 
+// SidebarSettingsRepository manages the sidebar layout of each role, per account.
+// Rows with an empty account ID are the global defaults accounts fall back to.
 type SidebarSettingsRepository struct {
 	db *gorm.DB
 }
@@ -34,35 +36,42 @@ func ProvideSidebarSettingsRepository(db *gorm.DB) *SidebarSettingsRepository {
 	return &SidebarSettingsRepository{db: db}
 }
 
-// Get returns the default sidebar settings (backward compat).
+// Get returns the global default sidebar settings (backward compat).
 func (r *SidebarSettingsRepository) Get(ctx context.Context) (*models.SidebarSettings, error) {
-	return r.GetByRole(ctx, defaultRole)
+	return r.GetByRole(ctx, "", defaultRole)
 }
 
-// Save saves the default sidebar settings (backward compat).
+// Save saves the global default sidebar settings (backward compat).
 func (r *SidebarSettingsRepository) Save(ctx context.Context, settings *models.SidebarSettings) error {
-	return r.SaveByRole(ctx, defaultRole, settings)
+	return r.SaveByRole(ctx, "", defaultRole, settings)
 }
 
-// GetByRole returns sidebar settings for the given role.
-// Falls back to "default" if no role-specific settings exist.
-func (r *SidebarSettingsRepository) GetByRole(ctx context.Context, role string) (*models.SidebarSettings, error) {
+// GetByRole returns the account's sidebar settings for the given role. It
+// falls back to the account's "default" settings, then to the global ones
+// for the role, and then to the global "default".
+func (r *SidebarSettingsRepository) GetByRole(
+	ctx context.Context, accountID, role string,
+) (*models.SidebarSettings, error) {
 	if role == "" {
 		role = defaultRole
 	}
 
-	var settings models.SidebarSettings
-	err := r.db.WithContext(ctx).Where("role = ?", role).First(&settings).Error
-	if err == nil {
-		return &settings, nil
+	type key struct{ accountID, role string }
+	var candidates []key
+	if accountID != "" {
+		candidates = append(candidates, key{accountID, role})
+		if role != defaultRole {
+			candidates = append(candidates, key{accountID, defaultRole})
+		}
 	}
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, err
+	candidates = append(candidates, key{"", role})
+	if role != defaultRole {
+		candidates = append(candidates, key{"", defaultRole})
 	}
 
-	// Role-specific settings not found — fall back to default.
-	if role != defaultRole {
-		err = r.db.WithContext(ctx).Where("role = ?", defaultRole).First(&settings).Error
+	var settings models.SidebarSettings
+	for _, k := range candidates {
+		err := r.db.WithContext(ctx).Where("account_id = ? AND role = ?", k.accountID, k.role).First(&settings).Error
 		if err == nil {
 			return &settings, nil
 		}
@@ -71,7 +80,7 @@ func (r *SidebarSettingsRepository) GetByRole(ctx context.Context, role string)
 		}
 	}
 
-	// No settings at all — create the default row.
+	// No settings at all — create the global default row.
 	settings = models.SidebarSettings{Role: defaultRole}
 	if createErr := r.db.WithContext(ctx).Create(&settings).Error; createErr != nil {
 		return nil, createErr
@@ -79,25 +88,28 @@ func (r *SidebarSettingsRepository) GetByRole(ctx context.Context, role string)
 	return &settings, nil
 }
 
-// SaveByRole saves sidebar settings for the given role (upsert).
-func (r *SidebarSettingsRepository) SaveByRole(ctx context.Context, role string, settings *models.SidebarSettings) error {
+// SaveByRole saves the account's sidebar settings for the given role
+// (upsert). An empty accountID saves the global settings.
+func (r *SidebarSettingsRepository) SaveByRole(
+	ctx context.Context, accountID, role string, settings *models.SidebarSettings,
+) error {
 	if role == "" {
 		role = defaultRole
 	}
 
 	var existing models.SidebarSettings
-	err := r.db.WithContext(ctx).Where("role = ?", role).First(&existing).Error
+	err := r.db.WithContext(ctx).Where("account_id = ? AND role = ?", accountID, role).First(&existing).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return err
 	}
 
+	settings.AccountID = accountID
+	settings.Role = role
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		settings.ID = 0
-		settings.Role = role
 		return r.db.WithContext(ctx).Create(settings).Error
 	}
 
 	settings.ID = existing.ID
-	settings.Role = role
 	return r.db.WithContext(ctx).Save(settings).Error
 }
@@ -0,0 +1,60 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wepala/weos/v3/infrastructure/models"
+)
+
+func TestSidebarSettingsRepository_GetByRoleFallback(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.SidebarSettings{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	repo := &SidebarSettingsRepository{db: db}
+	ctx := context.Background()
+
+	for _, s := range []struct{ accountID, role, hidden string }{
+		{"", "default", "global-default"},
+		{"", "member", "global-member"},
+		{"acct-1", "default", "acct-default"},
+	} {
+		if err := repo.SaveByRole(ctx, s.accountID, s.role, &models.SidebarSettings{HiddenSlugs: s.hidden}); err != nil {
+			t.Fatalf("SaveByRole(%q, %q): %v", s.accountID, s.role, err)
+		}
+	}
+
+	cases := []struct{ accountID, role, want string }{
+		{"acct-1", "member", "acct-default"},
+		{"acct-1", "viewer", "acct-default"},
+		{"acct-2", "member", "global-member"},
+		{"acct-2", "viewer", "global-default"},
+		{"", "member", "global-member"},
+	}
+	for _, tc := range cases {
+		got, err := repo.GetByRole(ctx, tc.accountID, tc.role)
+		if err != nil {
+			t.Fatalf("GetByRole(%q, %q): %v", tc.accountID, tc.role, err)
+		}
+		if got.HiddenSlugs != tc.want {
+			t.Errorf("GetByRole(%q, %q) = %q, want %q", tc.accountID, tc.role, got.HiddenSlugs, tc.want)
+		}
+	}
+}
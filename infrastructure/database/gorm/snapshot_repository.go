@@ -0,0 +1,83 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SnapshotRepository struct {
+	db *gorm.DB
+}
+
+type SnapshotRepositoryResult struct {
+	fx.Out
+	Repository repositories.SnapshotRepository
+}
+
+func ProvideSnapshotRepository(params struct {
+	fx.In
+	DB *gorm.DB
+}) (SnapshotRepositoryResult, error) {
+	return SnapshotRepositoryResult{
+		Repository: &SnapshotRepository{db: params.DB},
+	}, nil
+}
+
+func (r *SnapshotRepository) Latest(
+	ctx context.Context, aggregateID string, schemaVersion int,
+) (*entities.AggregateSnapshot, error) {
+	var m models.AggregateSnapshot
+	err := r.db.WithContext(ctx).
+		Where("aggregate_id = ? AND schema_version = ?", aggregateID, schemaVersion).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("snapshot of %q: %w", aggregateID, repositories.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snapshot: %w", err)
+	}
+	return m.ToEntity(), nil
+}
+
+func (r *SnapshotRepository) Save(ctx context.Context, snapshot *entities.AggregateSnapshot) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "aggregate_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sequence_no", "schema_version", "state", "created_at"}),
+	}).Create(models.FromAggregateSnapshot(snapshot)).Error
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *SnapshotRepository) Delete(ctx context.Context, aggregateID string) error {
+	err := r.db.WithContext(ctx).Where("aggregate_id = ?", aggregateID).Delete(&models.AggregateSnapshot{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	return nil
+}
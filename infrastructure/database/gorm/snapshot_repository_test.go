@@ -0,0 +1,66 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+)
+
+func TestSnapshotRepository_KeepsLatestPerAggregate(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.AggregateSnapshot{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	repo := &SnapshotRepository{db: db}
+	ctx := context.Background()
+	const id = "urn:task:a"
+
+	if _, err := repo.Latest(ctx, id, 1); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("Latest before any snapshot: err = %v, want ErrNotFound", err)
+	}
+	for _, snap := range []*entities.AggregateSnapshot{
+		{AggregateID: id, SequenceNo: 100, SchemaVersion: 1, State: []byte(`{"n":1}`), CreatedAt: time.Now()},
+		{AggregateID: id, SequenceNo: 200, SchemaVersion: 1, State: []byte(`{"n":2}`), CreatedAt: time.Now()},
+	} {
+		if err := repo.Save(ctx, snap); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	got, err := repo.Latest(ctx, id, 1)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if got.SequenceNo != 200 || string(got.State) != `{"n":2}` {
+		t.Errorf("Latest = %+v, want the sequence 200 snapshot", got)
+	}
+	if _, err := repo.Latest(ctx, id, 2); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("Latest for another schema version: err = %v, want ErrNotFound", err)
+	}
+	var rows int64
+	db.Model(&models.AggregateSnapshot{}).Count(&rows)
+	if rows != 1 {
+		t.Errorf("%d snapshot rows, want one per aggregate", rows)
+	}
+}
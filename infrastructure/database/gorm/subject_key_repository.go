@@ -0,0 +1,89 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+type SubjectKeyRepository struct {
+	db *gorm.DB
+}
+
+type SubjectKeyRepositoryResult struct {
+	fx.Out
+	Repository repositories.SubjectKeyRepository
+}
+
+func ProvideSubjectKeyRepository(db *gorm.DB) (SubjectKeyRepositoryResult, error) {
+	return SubjectKeyRepositoryResult{
+		Repository: &SubjectKeyRepository{db: db},
+	}, nil
+}
+
+func (r *SubjectKeyRepository) Latest(ctx context.Context, subjectID string) (*entities.SubjectKey, error) {
+	var row models.SubjectKey
+	err := r.db.WithContext(ctx).Where("subject_id = ?", subjectID).
+		Order("created_at DESC").Order("id DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("key of %q: %w", subjectID, repositories.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subject key: %w", err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *SubjectKeyRepository) Save(ctx context.Context, key *entities.SubjectKey) error {
+	if err := r.db.WithContext(ctx).Create(models.FromSubjectKey(key)).Error; err != nil {
+		return fmt.Errorf("failed to save subject key: %w", err)
+	}
+	return nil
+}
+
+func (r *SubjectKeyRepository) FindByIDs(
+	ctx context.Context, ids []string,
+) (map[string]*entities.SubjectKey, error) {
+	keys := make(map[string]*entities.SubjectKey, len(ids))
+	if len(ids) == 0 {
+		return keys, nil
+	}
+	var rows []models.SubjectKey
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load subject keys: %w", err)
+	}
+	for i := range rows {
+		keys[rows[i].ID] = rows[i].ToEntity()
+	}
+	return keys, nil
+}
+
+func (r *SubjectKeyRepository) DeleteBySubject(ctx context.Context, subjectID string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("subject_id = ?", subjectID).Delete(&models.SubjectKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete subject keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
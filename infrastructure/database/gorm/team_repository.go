@@ -0,0 +1,172 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TeamRepository struct {
+	db *gorm.DB
+}
+
+type TeamRepositoryResult struct {
+	fx.Out
+	Repository repositories.TeamRepository
+}
+
+func ProvideTeamRepository(db *gorm.DB) (TeamRepositoryResult, error) {
+	return TeamRepositoryResult{
+		Repository: &TeamRepository{db: db},
+	}, nil
+}
+
+func (r *TeamRepository) Save(ctx context.Context, team *entities.Team) error {
+	if err := r.db.WithContext(ctx).Create(models.FromTeam(team)).Error; err != nil {
+		return fmt.Errorf("failed to save team: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamRepository) Update(ctx context.Context, team *entities.Team) error {
+	result := r.db.WithContext(ctx).Model(&models.Team{}).
+		Where("id = ?", team.ID).
+		Select("name", "description", "updated_at").
+		Updates(models.FromTeam(team))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update team: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, id string) (*entities.Team, error) {
+	var row models.Team
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find team: %w", err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *TeamRepository) FindAllByAccount(
+	ctx context.Context, accountID, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.Team], error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := r.db.WithContext(ctx).Where("account_id = ?", accountID)
+	if cursor != "" {
+		query = query.Where("id > ?", cursor)
+	}
+	var rows []models.Team
+	if err := query.Order("id ASC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return repositories.PaginatedResponse[*entities.Team]{}, fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	page := repositories.PaginatedResponse[*entities.Team]{
+		Data:    make([]*entities.Team, 0, len(rows)),
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	for i := range rows {
+		page.Data = append(page.Data, rows[i].ToEntity())
+	}
+	if hasMore {
+		page.Cursor = rows[len(rows)-1].ID
+	}
+	return page, nil
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.Team{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete team: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrNotFound
+		}
+		if err := tx.Where("team_id = ?", id).Delete(&models.TeamMember{}).Error; err != nil {
+			return fmt.Errorf("failed to delete team members: %w", err)
+		}
+		if err := tx.Where("team_id = ?", id).Delete(&models.ResourcePermission{}).Error; err != nil {
+			return fmt.Errorf("failed to delete team permissions: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *TeamRepository) AddMember(ctx context.Context, member *entities.TeamMember) error {
+	if member.AddedAt.IsZero() {
+		member.AddedAt = time.Now()
+	}
+	row := &models.TeamMember{
+		TeamID:  member.TeamID,
+		AgentID: member.AgentID,
+		AddedBy: member.AddedBy,
+		AddedAt: member.AddedAt,
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamRepository) RemoveMember(ctx context.Context, teamID, agentID string) error {
+	result := r.db.WithContext(ctx).
+		Where("team_id = ? AND agent_id = ?", teamID, agentID).
+		Delete(&models.TeamMember{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove team member: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepository) FindMembers(ctx context.Context, teamID string) ([]*entities.TeamMember, error) {
+	var rows []models.TeamMember
+	if err := r.db.WithContext(ctx).Where("team_id = ?", teamID).Order("added_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list team members: %w", err)
+	}
+	members := make([]*entities.TeamMember, 0, len(rows))
+	for i := range rows {
+		members = append(members, rows[i].ToEntity())
+	}
+	return members, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	authmodels "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/models"
+)
+
+func TestTeamRepository_GrantsFollowMembership(t *testing.T) {
+	t.Parallel()
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&models.Team{}, &models.TeamMember{}, &models.ResourcePermission{},
+		&authmodels.AccountMemberModel{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	if err := db.Create(authmodels.AccountMemberModelFrom("acct-1", "user-1", "member")).Error; err != nil {
+		t.Fatalf("add account member: %v", err)
+	}
+	teams := &TeamRepository{db: db}
+	perms := &ResourcePermissionRepository{db: db}
+	ctx := context.Background()
+
+	if err := teams.Save(ctx, &entities.Team{ID: "urn:team:a", AccountID: "acct-1", Name: "A"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	for range 2 { // adding an existing member is a no-op
+		if err := teams.AddMember(ctx, &entities.TeamMember{TeamID: "urn:team:a", AgentID: "user-1"}); err != nil {
+			t.Fatalf("AddMember: %v", err)
+		}
+	}
+	// A direct grant and a team grant on the same resource are kept apart.
+	for _, perm := range []*entities.ResourcePermission{
+		{ResourceID: "urn:doc:1", TeamID: "urn:team:a", Actions: []string{"read"}},
+		{ResourceID: "urn:doc:1", AgentID: "user-2", Actions: []string{"modify"}},
+	} {
+		if err := perms.Grant(ctx, perm); err != nil {
+			t.Fatalf("Grant: %v", err)
+		}
+	}
+
+	if ok, _ := perms.HasPermission(ctx, "urn:doc:1", "user-1", "read"); !ok {
+		t.Error("team member lacks the team's read grant")
+	}
+	if ok, _ := perms.HasPermission(ctx, "urn:doc:1", "user-1", "modify"); ok {
+		t.Error("team member gained another agent's grant")
+	}
+
+	// Leaving the account ends the team's access, and rejoining restores it.
+	member := &authmodels.AccountMemberModel{AccountID: "acct-1", AgentID: "user-1"}
+	if err := db.Delete(member).Error; err != nil {
+		t.Fatalf("remove account member: %v", err)
+	}
+	if ok, _ := perms.HasPermission(ctx, "urn:doc:1", "user-1", "read"); ok {
+		t.Error("agent that left the account kept the team's grant")
+	}
+	if err := db.Create(authmodels.AccountMemberModelFrom("acct-1", "user-1", "member")).Error; err != nil {
+		t.Fatalf("re-add account member: %v", err)
+	}
+	if ok, _ := perms.HasPermission(ctx, "urn:doc:1", "user-1", "read"); !ok {
+		t.Error("agent that rejoined the account lacks the team's grant")
+	}
+
+	if err := teams.RemoveMember(ctx, "urn:team:a", "user-1"); err != nil {
+		t.Fatalf("RemoveMember: %v", err)
+	}
+	if ok, _ := perms.HasPermission(ctx, "urn:doc:1", "user-1", "read"); ok {
+		t.Error("removed member kept the team's grant")
+	}
+
+	if err := teams.Delete(ctx, "urn:team:a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	left, err := perms.FindByResource(ctx, "urn:doc:1")
+	if err != nil || len(left) != 1 || left[0].AgentID != "user-2" {
+		t.Errorf("grants after team delete = %+v, %v; want only user-2's", left, err)
+	}
+	if _, err := teams.FindByID(ctx, "urn:team:a"); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("FindByID after delete: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package gorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/infrastructure/models"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+type WebhookSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+type WebhookSubscriptionRepositoryResult struct {
+	fx.Out
+	Repository repositories.WebhookSubscriptionRepository
+}
+
+func ProvideWebhookSubscriptionRepository(db *gorm.DB) (WebhookSubscriptionRepositoryResult, error) {
+	return WebhookSubscriptionRepositoryResult{
+		Repository: &WebhookSubscriptionRepository{db: db},
+	}, nil
+}
+
+func (r *WebhookSubscriptionRepository) Save(ctx context.Context, sub *entities.WebhookSubscription) error {
+	if err := r.db.WithContext(ctx).Create(models.FromWebhookSubscription(sub)).Error; err != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) Update(ctx context.Context, sub *entities.WebhookSubscription) error {
+	result := r.db.WithContext(ctx).Model(&models.WebhookSubscription{}).
+		Where("id = ?", sub.ID).
+		Select("*").Omit("id", "account_id", "created_by", "created_at").
+		Updates(models.FromWebhookSubscription(sub))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) FindByID(
+	ctx context.Context, id string,
+) (*entities.WebhookSubscription, error) {
+	var row models.WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *WebhookSubscriptionRepository) FindAllByAccount(
+	ctx context.Context, accountID, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.WebhookSubscription], error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := r.db.WithContext(ctx).Where("account_id = ?", accountID)
+	if cursor != "" {
+		query = query.Where("id > ?", cursor)
+	}
+	var rows []models.WebhookSubscription
+	if err := query.Order("id ASC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return repositories.PaginatedResponse[*entities.WebhookSubscription]{},
+			fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	page := repositories.PaginatedResponse[*entities.WebhookSubscription]{
+		Data:    make([]*entities.WebhookSubscription, 0, len(rows)),
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	for i := range rows {
+		page.Data = append(page.Data, rows[i].ToEntity())
+	}
+	if hasMore {
+		page.Cursor = rows[len(rows)-1].ID
+	}
+	return page, nil
+}
+
+func (r *WebhookSubscriptionRepository) FindActive(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	var rows []models.WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list active webhook subscriptions: %w", err)
+	}
+	subs := make([]*entities.WebhookSubscription, 0, len(rows))
+	for i := range rows {
+		subs = append(subs, rows[i].ToEntity())
+	}
+	return subs, nil
+}
+
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.WebhookSubscription{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+type WebhookDeliveryRepositoryResult struct {
+	fx.Out
+	Repository repositories.WebhookDeliveryRepository
+}
+
+func ProvideWebhookDeliveryRepository(db *gorm.DB) (WebhookDeliveryRepositoryResult, error) {
+	return WebhookDeliveryRepositoryResult{
+		Repository: &WebhookDeliveryRepository{db: db},
+	}, nil
+}
+
+func (r *WebhookDeliveryRepository) Save(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	if err := r.db.WithContext(ctx).Create(models.FromWebhookDelivery(delivery)).Error; err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) FindByID(ctx context.Context, id string) (*entities.WebhookDelivery, error) {
+	var row models.WebhookDelivery
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+	return row.ToEntity(), nil
+}
+
+func (r *WebhookDeliveryRepository) RecordAttempt(
+	ctx context.Context, id, status string, responseStatus int, lastError string,
+) error {
+	updates := map[string]any{
+		"status":          status,
+		"attempts":        gorm.Expr("attempts + 1"),
+		"response_status": responseStatus,
+		"last_error":      lastError,
+	}
+	if status == entities.WebhookDeliverySucceeded {
+		updates["delivered_at"] = time.Now().UTC()
+	}
+	result := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// FindAllBySubscription pages newest first; delivery IDs are KSUIDs, so ID
+// order is creation order.
+func (r *WebhookDeliveryRepository) FindAllBySubscription(
+	ctx context.Context, subscriptionID, cursor string, limit int,
+) (repositories.PaginatedResponse[*entities.WebhookDelivery], error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID)
+	if cursor != "" {
+		query = query.Where("id < ?", cursor)
+	}
+	var rows []models.WebhookDelivery
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		return repositories.PaginatedResponse[*entities.WebhookDelivery]{},
+			fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	page := repositories.PaginatedResponse[*entities.WebhookDelivery]{
+		Data:    make([]*entities.WebhookDelivery, 0, len(rows)),
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	for i := range rows {
+		page.Data = append(page.Data, rows[i].ToEntity())
+	}
+	if hasMore {
+		page.Cursor = rows[len(rows)-1].ID
+	}
+	return page, nil
+}
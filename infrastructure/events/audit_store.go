@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+	"maps"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// AuditEventStore stamps every appended event with who caused it: the agent
+// and account of the request context, the admin impersonating the agent,
+// how the agent was authenticated, and the request ID. Metadata an event
+// already carries is kept, so imported or replayed events keep their
+// original actor.
+type AuditEventStore struct {
+	domain.EventStore
+}
+
+func NewAuditEventStore(store domain.EventStore) *AuditEventStore {
+	return &AuditEventStore{EventStore: store}
+}
+
+func (s *AuditEventStore) Append(
+	ctx context.Context, aggregateID string, expectedVersion int,
+	events ...domain.EventEnvelope[any],
+) error {
+	audit := auditMetadata(ctx)
+	if len(audit) == 0 {
+		return s.EventStore.Append(ctx, aggregateID, expectedVersion, events...)
+	}
+	stamped := make([]domain.EventEnvelope[any], len(events))
+	for i, e := range events {
+		metadata := maps.Clone(e.Metadata)
+		if metadata == nil {
+			metadata = make(map[string]any, len(audit))
+		}
+		for k, v := range audit {
+			if _, set := metadata[k]; !set {
+				metadata[k] = v
+			}
+		}
+		e.Metadata = metadata
+		stamped[i] = e
+	}
+	return s.EventStore.Append(ctx, aggregateID, expectedVersion, stamped...)
+}
+
+// auditMetadata returns the audit metadata keys ctx has values for.
+func auditMetadata(ctx context.Context) map[string]any {
+	audit := entities.AuditFromContext(ctx)
+	values := map[string]string{
+		entities.AuditRealActorKey: audit.RealAgentID,
+		entities.AuditMethodKey:    audit.AuthMethod,
+		entities.AuditRequestKey:   audit.RequestID,
+	}
+	if identity := auth.AgentFromCtx(ctx); identity != nil {
+		values[entities.AuditActorKey] = identity.AgentID
+		values[entities.AuditAccountKey] = identity.ActiveAccountID
+	}
+	metadata := make(map[string]any, len(values))
+	for k, v := range values {
+		if v != "" {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
@@ -0,0 +1,56 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+func TestAuditEventStore_StampsWhoCausedTheEvent(t *testing.T) {
+	ctx := auth.ContextWithAgent(context.Background(), &auth.Identity{
+		AgentID: "agent-user", AccountIDs: []string{"acct-1"}, ActiveAccountID: "acct-1",
+	})
+	ctx = entities.ContextWithRealAgent(ctx, "agent-admin")
+	ctx = entities.ContextWithAuthMethod(ctx, entities.AuthMethodSession)
+	ctx = entities.ContextWithRequestID(ctx, "req-1")
+
+	store := NewAuditEventStore(infrastructure.NewMemoryStore())
+	imported := map[string]any{entities.AuditActorKey: "agent-original"}
+	err := store.Append(ctx, "urn:task:1", -1,
+		domain.EventEnvelope[any]{ID: "evt-1", AggregateID: "urn:task:1", EventType: "Resource.Updated",
+			SequenceNo: 1, Payload: map[string]any{}},
+		domain.EventEnvelope[any]{ID: "evt-2", AggregateID: "urn:task:1", EventType: "Resource.Updated",
+			SequenceNo: 2, Payload: map[string]any{}, Metadata: imported},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported[entities.AuditRequestKey] != nil {
+		t.Error("Append modified the caller's metadata map")
+	}
+
+	stored, err := store.GetEvents(context.Background(), "urn:task:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		entities.AuditActorKey:     "agent-user",
+		entities.AuditRealActorKey: "agent-admin",
+		entities.AuditAccountKey:   "acct-1",
+		entities.AuditMethodKey:    entities.AuthMethodSession,
+		entities.AuditRequestKey:   "req-1",
+	}
+	for k, v := range want {
+		if stored[0].Metadata[k] != v {
+			t.Errorf("metadata[%s] = %v, want %v", k, stored[0].Metadata[k], v)
+		}
+	}
+	if got := stored[1].Metadata[entities.AuditActorKey]; got != "agent-original" {
+		t.Errorf("actor of an event that had one = %v, want it kept", got)
+	}
+}
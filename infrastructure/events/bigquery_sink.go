@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+// bigQueryMaxBatch is the most events BatchInsertEvents sends in one query.
+const bigQueryMaxBatch = 1000
+
+// BigQuerySink writes events to the BigQuery events table.
+type BigQuerySink struct {
+	client    *bigquery.Client
+	projectID string
+	datasetID string
+	tableID   string
+}
+
+func NewBigQuerySink(client *bigquery.Client, projectID, datasetID, tableID string) *BigQuerySink {
+	return &BigQuerySink{client: client, projectID: projectID, datasetID: datasetID, tableID: tableID}
+}
+
+func openBigQuerySink(ctx context.Context, cfg SinkConfig) (EventSink, error) {
+	if cfg.App.BigQueryProjectID == "" {
+		return nil, fmt.Errorf("BigQuery not configured: set BIGQUERY_PROJECT_ID environment variable")
+	}
+	datasetID := cfg.App.BigQueryDatasetID
+	if datasetID == "" {
+		datasetID = "weos_events"
+	}
+	tableID := cfg.App.BigQueryTableID
+	if tableID == "" {
+		tableID = "events"
+	}
+	client, err := bigquery.NewClient(ctx, cfg.App.BigQueryProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	return NewBigQuerySink(client, cfg.App.BigQueryProjectID, datasetID, tableID), nil
+}
+
+func (s *BigQuerySink) Write(ctx context.Context, events []domain.EventEnvelope[any]) error {
+	for len(events) > 0 {
+		n := min(len(events), bigQueryMaxBatch)
+		if err := BatchInsertEvents(ctx, s.client, s.projectID, s.datasetID, s.tableID, events[:n]); err != nil {
+			return err
+		}
+		events = events[n:]
+	}
+	return nil
+}
+
+// ExistingIDs returns the IDs of the events in the table created before
+// before. The lower bound is not applied.
+func (s *BigQuerySink) ExistingIDs(ctx context.Context, _, before time.Time) (map[string]struct{}, error) {
+	if before.IsZero() {
+		before = time.Now()
+	}
+	return GetExistingEventIDs(ctx, s.client, s.projectID, s.datasetID, s.tableID, before)
+}
+
+func (s *BigQuerySink) Close() error {
+	return s.client.Close()
+}
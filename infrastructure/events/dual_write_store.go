@@ -4,22 +4,20 @@ import (
 	"context"
 
 	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
-
-	"github.com/wepala/weos/v3/domain/entities"
 )
 
 // DualWriteEventStore writes events to both a primary and secondary event store.
 // The primary store (PostgreSQL) handles concurrency control and all reads.
 // The secondary store (BigQuery) receives a synchronous copy of writes.
-// If the secondary write fails, the error is logged but the operation succeeds.
+// If the secondary write fails the operation still succeeds; the events stay
+// pending and the SecondaryWriter sends them again later.
 type DualWriteEventStore struct {
 	primary   domain.EventStore
-	secondary domain.EventStore
-	logger    entities.Logger
+	secondary *SecondaryWriter
 }
 
-func NewDualWriteEventStore(primary, secondary domain.EventStore, logger entities.Logger) *DualWriteEventStore {
-	return &DualWriteEventStore{primary: primary, secondary: secondary, logger: logger}
+func NewDualWriteEventStore(primary domain.EventStore, secondary *SecondaryWriter) *DualWriteEventStore {
+	return &DualWriteEventStore{primary: primary, secondary: secondary}
 }
 
 func (s *DualWriteEventStore) Append(
@@ -29,11 +27,7 @@ func (s *DualWriteEventStore) Append(
 	if err := s.primary.Append(ctx, aggregateID, expectedVersion, events...); err != nil {
 		return err
 	}
-	// Write to secondary with no version check (-1) since primary is the source of truth.
-	if err := s.secondary.Append(ctx, aggregateID, -1, events...); err != nil {
-		s.logger.Warn(ctx, "BigQuery dual-write failed",
-			"aggregateID", aggregateID, "eventCount", len(events), "error", err)
-	}
+	s.secondary.Send(ctx, aggregateID, events)
 	return nil
 }
 
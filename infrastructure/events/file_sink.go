@@ -0,0 +1,260 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	eventFileExt = ".ndjson"
+	// partialFileExt marks a file that is still being written. It is renamed
+	// to its final name once complete, so anything picking files up from the
+	// directory only ever sees whole files.
+	partialFileExt = ".part"
+)
+
+// FileSink writes events as NDJSON files, one event per line. Files are
+// named after the creation time of their first event, so their names sort
+// in the order they were written.
+type FileSink struct {
+	dir         string
+	maxBytes    int64
+	rotateEvery time.Duration
+	// partitioned writes each batch to its own file, in a dt=YYYY-MM-DD
+	// subdirectory for the day its events were created.
+	partitioned bool
+	now         func() time.Time
+
+	file      *os.File
+	w         *bufio.Writer
+	path      string
+	partition string
+	written   int64
+	opened    time.Time
+}
+
+// NewNDJSONSink returns a sink that appends to an NDJSON file in dir and
+// starts a new one when the current file reaches maxBytes or has been open
+// for rotateEvery. A zero limit disables that rotation.
+func NewNDJSONSink(dir string, maxBytes int64, rotateEvery time.Duration) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event directory: %w", err)
+	}
+	return &FileSink{dir: dir, maxBytes: maxBytes, rotateEvery: rotateEvery, now: time.Now}, nil
+}
+
+// NewDirectorySink returns a sink that writes every batch as a complete
+// NDJSON file under dir/dt=YYYY-MM-DD, a layout that gsutil rsync, aws s3
+// sync, or a bucket loader can pick up as it is.
+func NewDirectorySink(dir string) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event directory: %w", err)
+	}
+	return &FileSink{dir: dir, partitioned: true, now: time.Now}, nil
+}
+
+func openNDJSONSink(_ context.Context, cfg SinkConfig) (EventSink, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("the ndjson sink needs a directory")
+	}
+	return NewNDJSONSink(cfg.Dir, cfg.MaxBytes, cfg.RotateEvery)
+}
+
+func openDirectorySink(_ context.Context, cfg SinkConfig) (EventSink, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("the dir sink needs a directory")
+	}
+	return NewDirectorySink(cfg.Dir)
+}
+
+func (s *FileSink) Write(_ context.Context, events []domain.EventEnvelope[any]) error {
+	for _, env := range events {
+		exported, err := toExportedEvent(env)
+		if err != nil {
+			return err
+		}
+		line, err := json.Marshal(exported)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", env.ID, err)
+		}
+		if s.file != nil && s.due(env) {
+			if err := s.finish(); err != nil {
+				return err
+			}
+		}
+		if s.file == nil {
+			if err := s.open(env); err != nil {
+				return err
+			}
+		}
+		n, err := s.w.Write(append(line, '\n'))
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", s.path, err)
+		}
+		s.written += int64(n)
+		if s.maxBytes > 0 && s.written >= s.maxBytes {
+			if err := s.finish(); err != nil {
+				return err
+			}
+		}
+	}
+	if s.partitioned {
+		return s.finish()
+	}
+	if s.w != nil {
+		return s.w.Flush()
+	}
+	return nil
+}
+
+// due reports whether the current file must end before env is written.
+func (s *FileSink) due(env domain.EventEnvelope[any]) bool {
+	if s.rotateEvery > 0 && s.now().Sub(s.opened) >= s.rotateEvery {
+		return true
+	}
+	return s.partitioned && partitionOf(env) != s.partition
+}
+
+func partitionOf(env domain.EventEnvelope[any]) string {
+	return "dt=" + env.Created.UTC().Format(time.DateOnly)
+}
+
+func (s *FileSink) open(first domain.EventEnvelope[any]) error {
+	dir := s.dir
+	if s.partitioned {
+		s.partition = partitionOf(first)
+		dir = filepath.Join(dir, s.partition)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create event directory: %w", err)
+		}
+	}
+	path := filepath.Join(dir, eventFileName(first, eventFileExt))
+	file, err := os.OpenFile(path+partialFileExt, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create event file: %w", err)
+	}
+	s.file, s.w, s.path = file, bufio.NewWriter(file), path
+	s.written, s.opened = 0, s.now()
+	return nil
+}
+
+// eventFileName names a file after the creation time of its first event, so
+// file names sort in the order they were written.
+func eventFileName(first domain.EventEnvelope[any], ext string) string {
+	return fmt.Sprintf("events-%s-%s%s",
+		first.Created.UTC().Format("20060102T150405.000000000Z"), ksuid.New(), ext)
+}
+
+// finish completes the current file and gives it its final name.
+func (s *FileSink) finish() error {
+	if s.file == nil {
+		return nil
+	}
+	file, w, path := s.file, s.w, s.path
+	s.file, s.w = nil, nil
+	err := w.Flush()
+	if err == nil {
+		err = file.Sync()
+	}
+	if err = errors.Join(err, file.Close()); err != nil {
+		return fmt.Errorf("failed to complete %s: %w", path, err)
+	}
+	if err := os.Rename(path+partialFileExt, path); err != nil {
+		return fmt.Errorf("failed to complete %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	return s.finish()
+}
+
+// ExistingIDs reads the IDs from every complete event file in the
+// directory. Partial files left by an interrupted export are not counted,
+// so their events are written again.
+func (s *FileSink) ExistingIDs(ctx context.Context, after, before time.Time) (map[string]struct{}, error) {
+	ids := make(map[string]struct{})
+	err := readEventFiles(ctx, s.dir, func(e exportedEvent) error {
+		if inWindow(e.CreatedAt, after, before) {
+			ids[e.ID] = struct{}{}
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// ReadEventFiles calls fn with every event in the complete NDJSON and
+// Parquet event files under dir, file by file in name order, which is the
+// order a sink wrote them in.
+func ReadEventFiles(ctx context.Context, dir string, fn func(domain.EventEnvelope[any]) error) error {
+	return readEventFiles(ctx, dir, func(e exportedEvent) error {
+		env, err := e.envelope()
+		if err != nil {
+			return err
+		}
+		return fn(env)
+	})
+}
+
+func readEventFiles(ctx context.Context, dir string, fn func(exportedEvent) error) error {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && (strings.HasSuffix(path, eventFileExt) || strings.HasSuffix(path, parquetFileExt)) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list event files: %w", err)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		read := readEventFile
+		if strings.HasSuffix(path, parquetFileExt) {
+			read = readParquetFile
+		}
+		if err := read(ctx, path, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readEventFile(_ context.Context, path string, fn func(exportedEvent) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open event file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	dec := json.NewDecoder(bufio.NewReader(file))
+	for line := 1; ; line++ {
+		var e exportedEvent
+		if err := dec.Decode(&e); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: event %d is unreadable: %w", path, line, err)
+		}
+		if err := fn(e); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+func sinkEvents(from, to int, created time.Time) []domain.EventEnvelope[any] {
+	var out []domain.EventEnvelope[any]
+	for i := from; i <= to; i++ {
+		out = append(out, domain.EventEnvelope[any]{
+			ID: fmt.Sprintf("evt-%03d", i), AggregateID: "urn:task:1", EventType: "Resource.Updated",
+			SequenceNo: i, Created: created.Add(time.Duration(i) * time.Minute),
+			Payload:  map[string]any{"Data": map[string]any{"name": fmt.Sprintf("v%d", i)}},
+			Metadata: map[string]any{"schemaVersion": 1},
+		})
+	}
+	return out
+}
+
+func eventFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func readIDs(t *testing.T, dir string) []string {
+	t.Helper()
+	var ids []string
+	err := ReadEventFiles(context.Background(), dir, func(e domain.EventEnvelope[any]) error {
+		ids = append(ids, e.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestNDJSONSink_RotatesBySizeAndAge(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	sink, err := NewNDJSONSink(dir, 500, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := created
+	sink.now = func() time.Time { return now }
+
+	if err := sink.Write(ctx, sinkEvents(1, 4, created)); err != nil {
+		t.Fatal(err)
+	}
+	files := eventFiles(t, dir)
+	if len(files) != 2 || !strings.HasSuffix(files[0], eventFileExt) || !strings.HasSuffix(files[1], partialFileExt) {
+		t.Fatalf("files after 4 events = %v, want one complete file and one being written", files)
+	}
+	if ids := readIDs(t, dir); len(ids) == 0 || len(ids) == 4 {
+		t.Errorf("readable events = %v, want only those in the complete file", ids)
+	}
+
+	now = now.Add(time.Hour)
+	if err := sink.Write(ctx, sinkEvents(5, 5, created)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files = eventFiles(t, dir)
+	if len(files) != 3 || slices.ContainsFunc(files, func(f string) bool { return strings.HasSuffix(f, partialFileExt) }) {
+		t.Fatalf("files after close = %v, want three complete files", files)
+	}
+	want := []string{"evt-001", "evt-002", "evt-003", "evt-004", "evt-005"}
+	if ids := readIDs(t, dir); !slices.Equal(ids, want) {
+		t.Errorf("events read back = %v, want %v", ids, want)
+	}
+}
+
+func TestDirectorySink_WritesABatchPerFileByDay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sink, err := NewDirectorySink(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	late := time.Date(2026, 5, 1, 23, 58, 0, 0, time.UTC)
+	if err := sink.Write(ctx, sinkEvents(1, 3, late)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(ctx, sinkEvents(4, 4, late)); err != nil {
+		t.Fatal(err)
+	}
+
+	files := eventFiles(t, dir)
+	var days []string
+	for _, f := range files {
+		days = append(days, strings.Split(f, "/")[0])
+	}
+	if want := []string{"dt=2026-05-01", "dt=2026-05-02", "dt=2026-05-02"}; !slices.Equal(days, want) {
+		t.Fatalf("files = %v, want them in partitions %v", files, want)
+	}
+
+	ids, err := sink.ExistingIDs(ctx, time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ids["evt-001"]; len(ids) != 3 || ok {
+		t.Errorf("existing IDs from May 2 = %v, want evt-002 to evt-004", ids)
+	}
+
+	var got domain.EventEnvelope[any]
+	err = ReadEventFiles(ctx, dir, func(e domain.EventEnvelope[any]) error {
+		if e.ID == "evt-004" {
+			got = e
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := got.Payload.(map[string]any)["Data"].(map[string]any)
+	if got.SequenceNo != 4 || !got.Created.Equal(late.Add(4*time.Minute)) || data["name"] != "v4" ||
+		got.Metadata["schemaVersion"] != float64(1) {
+		t.Errorf("event read back = %+v", got)
+	}
+}
+
+func TestOpenEventSink_UnknownName(t *testing.T) {
+	_, err := OpenEventSink(context.Background(), "kafka", SinkConfig{Dir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "ndjson") {
+		t.Errorf("err = %v, want the available sinks listed", err)
+	}
+}
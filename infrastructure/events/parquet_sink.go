@@ -0,0 +1,294 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"github.com/apache/arrow/go/v15/arrow"
+	"github.com/apache/arrow/go/v15/arrow/array"
+	"github.com/apache/arrow/go/v15/arrow/memory"
+	"github.com/apache/arrow/go/v15/parquet"
+	"github.com/apache/arrow/go/v15/parquet/compress"
+	"github.com/apache/arrow/go/v15/parquet/file"
+	"github.com/apache/arrow/go/v15/parquet/pqarrow"
+)
+
+const parquetFileExt = ".parquet"
+
+// parquetEventSchema holds the same columns as an NDJSON event line.
+// Payload and metadata are JSON strings.
+var parquetEventSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.BinaryTypes.String},
+	{Name: "aggregate_id", Type: arrow.BinaryTypes.String},
+	{Name: "event_type", Type: arrow.BinaryTypes.String},
+	{Name: "sequence_no", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "transaction_id", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "payload", Type: arrow.BinaryTypes.String},
+	{Name: "metadata", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "created_at", Type: &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}},
+}, nil)
+
+// ParquetSink writes events as Snappy-compressed Parquet files, one row
+// group per batch. Like the NDJSON sink it writes each file under a partial
+// name and renames it once complete.
+type ParquetSink struct {
+	dir         string
+	maxBytes    int64
+	rotateEvery time.Duration
+	now         func() time.Time
+
+	file   *os.File
+	w      *countingWriter
+	fw     *pqarrow.FileWriter
+	path   string
+	opened time.Time
+}
+
+// NewParquetSink returns a sink that adds each batch to a Parquet file in
+// dir and starts a new file, between batches, once the current one reaches
+// maxBytes or has been open for rotateEvery. A zero limit disables that
+// rotation.
+func NewParquetSink(dir string, maxBytes int64, rotateEvery time.Duration) (*ParquetSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event directory: %w", err)
+	}
+	return &ParquetSink{dir: dir, maxBytes: maxBytes, rotateEvery: rotateEvery, now: time.Now}, nil
+}
+
+func openParquetSink(_ context.Context, cfg SinkConfig) (EventSink, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("the parquet sink needs a directory")
+	}
+	return NewParquetSink(cfg.Dir, cfg.MaxBytes, cfg.RotateEvery)
+}
+
+func (s *ParquetSink) Write(_ context.Context, events []domain.EventEnvelope[any]) error {
+	if len(events) == 0 {
+		return nil
+	}
+	rec, err := parquetRecord(events)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	if s.fw != nil && s.rotateEvery > 0 && s.now().Sub(s.opened) >= s.rotateEvery {
+		if err := s.finish(); err != nil {
+			return err
+		}
+	}
+	if s.fw == nil {
+		if err := s.open(events[0]); err != nil {
+			return err
+		}
+	}
+	if err := s.fw.Write(rec); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.path, err)
+	}
+	if s.maxBytes > 0 && s.w.n >= s.maxBytes {
+		return s.finish()
+	}
+	return nil
+}
+
+// parquetRecord converts a batch of events to an Arrow record.
+func parquetRecord(events []domain.EventEnvelope[any]) (arrow.Record, error) {
+	b := array.NewRecordBuilder(memory.DefaultAllocator, parquetEventSchema)
+	defer b.Release()
+	for _, env := range events {
+		e, err := toExportedEvent(env)
+		if err != nil {
+			return nil, err
+		}
+		b.Field(0).(*array.StringBuilder).Append(e.ID)
+		b.Field(1).(*array.StringBuilder).Append(e.AggregateID)
+		b.Field(2).(*array.StringBuilder).Append(e.EventType)
+		b.Field(3).(*array.Int64Builder).Append(int64(e.SequenceNo))
+		appendOptional(b.Field(4).(*array.StringBuilder), e.TransactionID)
+		b.Field(5).(*array.StringBuilder).Append(string(e.Payload))
+		appendOptional(b.Field(6).(*array.StringBuilder), string(e.Metadata))
+		b.Field(7).(*array.TimestampBuilder).Append(arrow.Timestamp(e.CreatedAt.UnixNano()))
+	}
+	return b.NewRecord(), nil
+}
+
+func appendOptional(b *array.StringBuilder, v string) {
+	if v == "" {
+		b.AppendNull()
+		return
+	}
+	b.Append(v)
+}
+
+func (s *ParquetSink) open(first domain.EventEnvelope[any]) error {
+	path := filepath.Join(s.dir, eventFileName(first, parquetFileExt))
+	f, err := os.OpenFile(path+partialFileExt, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create event file: %w", err)
+	}
+	w := &countingWriter{w: bufio.NewWriter(f)}
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	fw, err := pqarrow.NewFileWriter(parquetEventSchema, w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to start %s: %w", path, err)
+	}
+	s.file, s.w, s.fw, s.path, s.opened = f, w, fw, path, s.now()
+	return nil
+}
+
+// finish writes the current file's footer and gives it its final name.
+func (s *ParquetSink) finish() error {
+	if s.fw == nil {
+		return nil
+	}
+	f, w, fw, path := s.file, s.w, s.fw, s.path
+	s.file, s.w, s.fw = nil, nil, nil
+	err := fw.Close()
+	if err == nil {
+		err = w.w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if err = errors.Join(err, f.Close()); err != nil {
+		return fmt.Errorf("failed to complete %s: %w", path, err)
+	}
+	if err := os.Rename(path+partialFileExt, path); err != nil {
+		return fmt.Errorf("failed to complete %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *ParquetSink) Close() error {
+	return s.finish()
+}
+
+// ExistingIDs reads the IDs from every complete event file in the
+// directory, as the NDJSON sink does.
+func (s *ParquetSink) ExistingIDs(ctx context.Context, after, before time.Time) (map[string]struct{}, error) {
+	ids := make(map[string]struct{})
+	err := readEventFiles(ctx, s.dir, func(e exportedEvent) error {
+		if inWindow(e.CreatedAt, after, before) {
+			ids[e.ID] = struct{}{}
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// countingWriter counts the bytes written through it. It is not a Closer,
+// so the Parquet writer leaves closing the file to the sink.
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func readParquetFile(ctx context.Context, path string, fn func(exportedEvent) error) error {
+	pf, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		return fmt.Errorf("failed to open event file %s: %w", path, err)
+	}
+	defer func() { _ = pf.Close() }()
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: 1024}, memory.DefaultAllocator)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	rr, err := fr.GetRecordReader(ctx, nil, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	defer rr.Release()
+	for rr.Next() {
+		if err := readParquetRecord(rr.Record(), fn); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := rr.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: events are unreadable: %w", path, err)
+	}
+	return nil
+}
+
+func readParquetRecord(rec arrow.Record, fn func(exportedEvent) error) error {
+	ids, err := parquetColumn[*array.String](rec, "id")
+	if err != nil {
+		return err
+	}
+	aggregates, err := parquetColumn[*array.String](rec, "aggregate_id")
+	if err != nil {
+		return err
+	}
+	types, err := parquetColumn[*array.String](rec, "event_type")
+	if err != nil {
+		return err
+	}
+	sequences, err := parquetColumn[*array.Int64](rec, "sequence_no")
+	if err != nil {
+		return err
+	}
+	transactions, err := parquetColumn[*array.String](rec, "transaction_id")
+	if err != nil {
+		return err
+	}
+	payloads, err := parquetColumn[*array.String](rec, "payload")
+	if err != nil {
+		return err
+	}
+	metadata, err := parquetColumn[*array.String](rec, "metadata")
+	if err != nil {
+		return err
+	}
+	created, err := parquetColumn[*array.Timestamp](rec, "created_at")
+	if err != nil {
+		return err
+	}
+	for i := range int(rec.NumRows()) {
+		e := exportedEvent{
+			ID:          ids.Value(i),
+			AggregateID: aggregates.Value(i),
+			EventType:   types.Value(i),
+			SequenceNo:  int(sequences.Value(i)),
+			Payload:     []byte(payloads.Value(i)),
+			CreatedAt:   created.Value(i).ToTime(arrow.Nanosecond).UTC(),
+		}
+		if transactions.IsValid(i) {
+			e.TransactionID = transactions.Value(i)
+		}
+		if metadata.IsValid(i) {
+			e.Metadata = []byte(metadata.Value(i))
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parquetColumn returns the record's column name as an array of type T.
+func parquetColumn[T arrow.Array](rec arrow.Record, name string) (T, error) {
+	var zero T
+	indices := rec.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		return zero, fmt.Errorf("event file has no %s column", name)
+	}
+	col, ok := rec.Column(indices[0]).(T)
+	if !ok {
+		return zero, fmt.Errorf("event file column %s is %s", name, rec.Column(indices[0]).DataType())
+	}
+	return col, nil
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+)
+
+func TestParquetSink_RotatesAndReadsBack(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2026, 5, 1, 9, 0, 0, 123456789, time.UTC)
+	dir := t.TempDir()
+	sink, err := NewParquetSink(dir, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := created
+	sink.now = func() time.Time { return now }
+
+	batch := sinkEvents(1, 3, created)
+	batch[1].TransactionID = "tx-1"
+	if err := sink.Write(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(ctx, sinkEvents(4, 4, created)); err != nil {
+		t.Fatal(err)
+	}
+	files := eventFiles(t, dir)
+	if len(files) != 1 || !strings.HasSuffix(files[0], parquetFileExt+partialFileExt) {
+		t.Fatalf("files before rotation = %v, want one file being written", files)
+	}
+	if ids := readIDs(t, dir); len(ids) != 0 {
+		t.Errorf("readable events = %v, want none until the file is complete", ids)
+	}
+
+	now = now.Add(time.Hour)
+	if err := sink.Write(ctx, sinkEvents(5, 5, created)); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files = eventFiles(t, dir)
+	if len(files) != 2 || slices.ContainsFunc(files, func(f string) bool { return strings.HasSuffix(f, partialFileExt) }) {
+		t.Fatalf("files after close = %v, want two complete files", files)
+	}
+	want := []string{"evt-001", "evt-002", "evt-003", "evt-004", "evt-005"}
+	if ids := readIDs(t, dir); !slices.Equal(ids, want) {
+		t.Errorf("events read back = %v, want %v", ids, want)
+	}
+
+	var got domain.EventEnvelope[any]
+	err = ReadEventFiles(ctx, dir, func(e domain.EventEnvelope[any]) error {
+		if e.ID == "evt-002" {
+			got = e
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := got.Payload.(map[string]any)["Data"].(map[string]any)
+	if got.SequenceNo != 2 || got.TransactionID != "tx-1" || !got.Created.Equal(created.Add(2*time.Minute)) ||
+		data["name"] != "v2" || got.Metadata["schemaVersion"] != float64(1) {
+		t.Errorf("event read back = %+v", got)
+	}
+
+	ids, err := sink.ExistingIDs(ctx, created.Add(4*time.Minute), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ids["evt-003"]; len(ids) != 2 || ok {
+		t.Errorf("existing IDs from evt-004 on = %v, want evt-004 and evt-005", ids)
+	}
+}
+
+func TestParquetSink_RotatesBySize(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sink, err := NewParquetSink(dir, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i := 1; i <= 3; i++ {
+		if err := sink.Write(ctx, sinkEvents(i, i, created)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files := eventFiles(t, dir); len(files) != 3 {
+		t.Errorf("files = %v, want one per batch past the size limit", files)
+	}
+}
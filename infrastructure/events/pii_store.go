@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// PIIEventStore encrypts the personal data of every appended event and
+// decrypts it in every event it reads. Everything below it, including the
+// secondary store, only ever sees the encrypted values.
+type PIIEventStore struct {
+	domain.EventStore
+	cipher entities.PIICipher
+}
+
+func NewPIIEventStore(store domain.EventStore, cipher entities.PIICipher) *PIIEventStore {
+	return &PIIEventStore{EventStore: store, cipher: cipher}
+}
+
+func (s *PIIEventStore) Append(
+	ctx context.Context, aggregateID string, expectedVersion int,
+	events ...domain.EventEnvelope[any],
+) error {
+	sealed, err := s.cipher.Seal(ctx, events)
+	if err != nil {
+		return err
+	}
+	return s.EventStore.Append(ctx, aggregateID, expectedVersion, sealed...)
+}
+
+func (s *PIIEventStore) GetEvents(
+	ctx context.Context, aggregateID string,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	return s.cipher.Open(ctx, events)
+}
+
+func (s *PIIEventStore) GetEventsFromVersion(
+	ctx context.Context, aggregateID string, fromVersion int,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEventsFromVersion(ctx, aggregateID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.cipher.Open(ctx, events)
+}
+
+func (s *PIIEventStore) GetEventsRange(
+	ctx context.Context, aggregateID string, fromVersion, toVersion int,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEventsRange(ctx, aggregateID, fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.cipher.Open(ctx, events)
+}
+
+func (s *PIIEventStore) GetEventByID(
+	ctx context.Context, eventID string,
+) (domain.EventEnvelope[any], error) {
+	event, err := s.EventStore.GetEventByID(ctx, eventID)
+	if err != nil {
+		return event, err
+	}
+	opened, err := s.cipher.Open(ctx, []domain.EventEnvelope[any]{event})
+	if err != nil {
+		return event, err
+	}
+	return opened[0], nil
+}
+
+func (s *PIIEventStore) GetEventsByTransactionID(
+	ctx context.Context, transactionID string,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEventsByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.cipher.Open(ctx, events)
+}
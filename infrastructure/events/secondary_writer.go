@@ -0,0 +1,162 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"go.uber.org/fx"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
+	"github.com/wepala/weos/v3/internal/config"
+)
+
+const (
+	// secondaryResendBatch is how many pending events one resend sends.
+	secondaryResendBatch = 500
+	// Failed sends back off like background jobs: 10s, doubling, up to 1h.
+	secondaryBackoffBase = 10 * time.Second
+	secondaryBackoffMax  = time.Hour
+)
+
+// SecondaryWriter copies stored events to the secondary event store. Every
+// event the primary store stores is pending until the secondary store
+// acknowledges it; sends that fail are retried by Resend with backoff.
+type SecondaryWriter struct {
+	store   domain.EventStore
+	pending repositories.PendingEventRepository
+	logger  entities.Logger
+	now     func() time.Time
+}
+
+func NewSecondaryWriter(
+	store domain.EventStore, pending repositories.PendingEventRepository, logger entities.Logger,
+) *SecondaryWriter {
+	return &SecondaryWriter{store: store, pending: pending, logger: logger, now: time.Now}
+}
+
+// ProvideSecondaryWriter creates the BigQuery secondary writer from config.
+// Returns nil if BigQuery is not configured, or if its client cannot be
+// created; events stay pending until a later start can send them. On start
+// every pending event is made due, so whatever was not acknowledged before
+// is sent again.
+func ProvideSecondaryWriter(params struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    config.Config
+	Pending   repositories.PendingEventRepository
+	Logger    entities.Logger
+}) *SecondaryWriter {
+	if params.Config.BigQueryProjectID == "" {
+		return nil
+	}
+	bqStore, err := ProvideBigQueryEventStore(params.Config)
+	if err != nil || bqStore == nil {
+		params.Logger.Error(context.Background(),
+			"failed to create BigQuery event store, events will stay pending", "error", err)
+		return nil
+	}
+	w := NewSecondaryWriter(bqStore, params.Pending, params.Logger)
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			n, err := w.Reconcile(ctx)
+			if err != nil {
+				params.Logger.Error(ctx, "failed to reconcile pending secondary events", "error", err)
+			} else if n > 0 {
+				params.Logger.Info(ctx, "resending events the secondary store has not acknowledged", "count", n)
+			}
+			return nil
+		},
+		OnStop: func(_ context.Context) error { return bqStore.Close() },
+	})
+	return w
+}
+
+// Send writes the events of one aggregate, just stored by the primary store,
+// to the secondary store. A failure is recorded for Resend to retry, and
+// only logged.
+func (w *SecondaryWriter) Send(ctx context.Context, aggregateID string, events []domain.EventEnvelope[any]) {
+	if err := w.store.Append(ctx, aggregateID, -1, events...); err != nil {
+		w.logger.Warn(ctx, "secondary event store write failed; will retry",
+			"aggregateID", aggregateID, "eventCount", len(events), "error", err)
+		for _, e := range events {
+			w.fail(ctx, e.ID, 1, err)
+		}
+		return
+	}
+	w.ack(ctx, events)
+}
+
+// Resend sends the pending events that are due, in the order they were
+// stored, and returns how many the secondary store acknowledged.
+func (w *SecondaryWriter) Resend(ctx context.Context) (int, error) {
+	due, err := w.pending.Due(ctx, w.now(), secondaryResendBatch)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for len(due) > 0 {
+		// Send consecutive events of one aggregate together.
+		n := 1
+		for n < len(due) && due[n].AggregateID == due[0].AggregateID {
+			n++
+		}
+		run := due[:n]
+		due = due[n:]
+		events := make([]domain.EventEnvelope[any], len(run))
+		for i, p := range run {
+			events[i] = p.Event
+		}
+		if err := w.store.Append(ctx, run[0].AggregateID, -1, events...); err != nil {
+			for _, p := range run {
+				w.fail(ctx, p.EventID, p.Attempts+1, err)
+			}
+			continue
+		}
+		w.ack(ctx, events)
+		sent += len(events)
+	}
+	return sent, nil
+}
+
+// Reconcile makes every pending event due now and returns how many there
+// are.
+func (w *SecondaryWriter) Reconcile(ctx context.Context) (int64, error) {
+	return w.pending.Reschedule(ctx, w.now())
+}
+
+// Backlog summarises the events waiting for the secondary store.
+func (w *SecondaryWriter) Backlog(ctx context.Context) (entities.PendingEventBacklog, error) {
+	return w.pending.Backlog(ctx)
+}
+
+func (w *SecondaryWriter) Close() error {
+	return w.store.Close()
+}
+
+func (w *SecondaryWriter) ack(ctx context.Context, events []domain.EventEnvelope[any]) {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	// An unrecorded acknowledgement only means the events are sent again.
+	if err := w.pending.Ack(ctx, ids...); err != nil {
+		w.logger.Error(ctx, "failed to acknowledge secondary events", "eventCount", len(ids), "error", err)
+	}
+}
+
+func (w *SecondaryWriter) fail(ctx context.Context, eventID string, attempts int, sendErr error) {
+	next := w.now().Add(secondaryBackoff(attempts))
+	if err := w.pending.Fail(ctx, eventID, attempts, next, sendErr.Error()); err != nil {
+		w.logger.Error(ctx, "failed to record secondary event failure", "eventID", eventID, "error", err)
+	}
+}
+
+func secondaryBackoff(attempts int) time.Duration {
+	d := secondaryBackoffBase
+	for i := 1; i < attempts && d < secondaryBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, secondaryBackoffMax)
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+
+	"github.com/wepala/weos/v3/internal/config"
+)
+
+// EventSink receives copies of stored events for analytics or archival.
+// Events are written in event log order and may belong to different
+// aggregates.
+type EventSink interface {
+	// Write stores a batch of events.
+	Write(ctx context.Context, events []domain.EventEnvelope[any]) error
+	// ExistingIDs returns the IDs of the events the sink already holds that
+	// were created in [after, before), so an export can skip them. A zero
+	// time leaves that end open. A sink may return IDs outside the window.
+	ExistingIDs(ctx context.Context, after, before time.Time) (map[string]struct{}, error)
+	// Close flushes anything buffered and releases the sink.
+	Close() error
+}
+
+// SinkConfig is what an event sink is opened with. Each sink reads the
+// fields it needs.
+type SinkConfig struct {
+	// Dir is the directory file sinks write to.
+	Dir string
+	// MaxBytes rotates an NDJSON or Parquet file once it reaches this size.
+	// 0 disables it.
+	MaxBytes int64
+	// RotateEvery rotates an NDJSON or Parquet file once it has been open
+	// this long. 0 disables it.
+	RotateEvery time.Duration
+	// App holds the BigQuery settings.
+	App config.Config
+}
+
+// SinkOpener opens a named event sink.
+type SinkOpener func(ctx context.Context, cfg SinkConfig) (EventSink, error)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   = map[string]SinkOpener{
+		"ndjson":   openNDJSONSink,
+		"dir":      openDirectorySink,
+		"parquet":  openParquetSink,
+		"bigquery": openBigQuerySink,
+	}
+)
+
+// RegisterEventSink makes a sink available to OpenEventSink under name,
+// replacing any sink registered under it before.
+func RegisterEventSink(name string, open SinkOpener) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[name] = open
+}
+
+// OpenEventSink opens the sink registered under name.
+func OpenEventSink(ctx context.Context, name string, cfg SinkConfig) (EventSink, error) {
+	sinksMu.RLock()
+	open, ok := sinks[name]
+	sinksMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown event sink %q (available: %v)", name, EventSinkNames())
+	}
+	return open(ctx, cfg)
+}
+
+// EventSinkNames returns the names of the registered sinks, sorted.
+func EventSinkNames() []string {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	return slices.Sorted(maps.Keys(sinks))
+}
+
+// exportedEvent is one line of an NDJSON event file. Its fields match the
+// columns of the BigQuery events table.
+type exportedEvent struct {
+	ID            string          `json:"id"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	SequenceNo    int             `json:"sequence_no"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+	Metadata      json.RawMessage `json:"metadata"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func toExportedEvent(env domain.EventEnvelope[any]) (exportedEvent, error) {
+	payload, metadata, err := marshalEvent(env)
+	if err != nil {
+		return exportedEvent{}, fmt.Errorf("failed to marshal event %s: %w", env.ID, err)
+	}
+	return exportedEvent{
+		ID:            env.ID,
+		AggregateID:   env.AggregateID,
+		EventType:     env.EventType,
+		SequenceNo:    env.SequenceNo,
+		TransactionID: env.TransactionID,
+		Payload:       json.RawMessage(payload),
+		Metadata:      json.RawMessage(metadata),
+		CreatedAt:     env.Created.UTC(),
+	}, nil
+}
+
+// envelope converts the line back to an envelope. Payload and metadata come
+// back as maps, as they do from the database event store.
+func (e exportedEvent) envelope() (domain.EventEnvelope[any], error) {
+	var payload, metadata map[string]any
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return domain.EventEnvelope[any]{}, fmt.Errorf("event %s has an unreadable payload: %w", e.ID, err)
+	}
+	if len(e.Metadata) > 0 {
+		if err := json.Unmarshal(e.Metadata, &metadata); err != nil {
+			return domain.EventEnvelope[any]{}, fmt.Errorf("event %s has unreadable metadata: %w", e.ID, err)
+		}
+	}
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	env := domain.EventEnvelope[any]{
+		ID:            e.ID,
+		AggregateID:   e.AggregateID,
+		EventType:     e.EventType,
+		Created:       e.CreatedAt,
+		SequenceNo:    e.SequenceNo,
+		TransactionID: e.TransactionID,
+		Metadata:      metadata,
+	}
+	if payload != nil {
+		env.Payload = payload
+	}
+	return env, nil
+}
+
+// inWindow reports whether t is in [after, before); zero times are open.
+func inWindow(t, after, before time.Time) bool {
+	return (after.IsZero() || !t.Before(after)) && (before.IsZero() || t.Before(before))
+}
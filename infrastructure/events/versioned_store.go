@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// VersionedEventStore stamps every appended event with the schema version of
+// its payload and upcasts every event it reads to the current version, so
+// readers only ever see current payload shapes.
+type VersionedEventStore struct {
+	domain.EventStore
+	schemas *entities.EventSchemaRegistry
+}
+
+func NewVersionedEventStore(store domain.EventStore, schemas *entities.EventSchemaRegistry) *VersionedEventStore {
+	return &VersionedEventStore{EventStore: store, schemas: schemas}
+}
+
+func (s *VersionedEventStore) Append(
+	ctx context.Context, aggregateID string, expectedVersion int,
+	events ...domain.EventEnvelope[any],
+) error {
+	stamped := make([]domain.EventEnvelope[any], len(events))
+	for i, e := range events {
+		stamped[i] = s.schemas.Stamp(e)
+	}
+	return s.EventStore.Append(ctx, aggregateID, expectedVersion, stamped...)
+}
+
+func (s *VersionedEventStore) GetEvents(
+	ctx context.Context, aggregateID string,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEvents(ctx, aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	return s.schemas.UpcastAll(events)
+}
+
+func (s *VersionedEventStore) GetEventsFromVersion(
+	ctx context.Context, aggregateID string, fromVersion int,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEventsFromVersion(ctx, aggregateID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.schemas.UpcastAll(events)
+}
+
+func (s *VersionedEventStore) GetEventsRange(
+	ctx context.Context, aggregateID string, fromVersion, toVersion int,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEventsRange(ctx, aggregateID, fromVersion, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return s.schemas.UpcastAll(events)
+}
+
+func (s *VersionedEventStore) GetEventByID(
+	ctx context.Context, eventID string,
+) (domain.EventEnvelope[any], error) {
+	event, err := s.EventStore.GetEventByID(ctx, eventID)
+	if err != nil {
+		return event, err
+	}
+	return s.schemas.Upcast(event)
+}
+
+func (s *VersionedEventStore) GetEventsByTransactionID(
+	ctx context.Context, transactionID string,
+) ([]domain.EventEnvelope[any], error) {
+	events, err := s.EventStore.GetEventsByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.schemas.UpcastAll(events)
+}
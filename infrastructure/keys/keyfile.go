@@ -0,0 +1,108 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package keys provides the keys x-encrypted resource properties are
+// encrypted with.
+package keys
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/internal/config"
+)
+
+// KeySize is the size of a field encryption key, an AES-256 key.
+const KeySize = 32
+
+// KeyFileProvider serves field encryption keys from a local keyfile. The
+// file holds one base64-encoded 32-byte key per line; blank lines and lines
+// starting with # are ignored. The first key encrypts new values and every
+// key decrypts, so a key is rotated by adding the new one at the top and
+// keeping the old one below it.
+type KeyFileProvider struct {
+	currentID string
+	keys      map[string][]byte
+}
+
+// ProvideFieldKeyProvider loads the keyfile named by FIELD_ENCRYPTION_KEYFILE.
+// It returns nil when none is configured, and fails on a keyfile that cannot
+// be read rather than start without the keys.
+func ProvideFieldKeyProvider(cfg config.Config) (entities.FieldKeyProvider, error) {
+	if cfg.FieldEncryptionKeyFile == "" {
+		return nil, nil
+	}
+	return LoadKeyFile(cfg.FieldEncryptionKeyFile)
+}
+
+// LoadKeyFile reads a keyfile.
+func LoadKeyFile(path string) (*KeyFileProvider, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read field encryption keyfile: %w", err)
+	}
+	p := &KeyFileProvider{keys: make(map[string][]byte)}
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(line)
+		if err != nil || len(key) != KeySize {
+			return nil, fmt.Errorf("field encryption keyfile %s, line %d: want a base64-encoded %d-byte key",
+				path, n, KeySize)
+		}
+		id := KeyID(key)
+		p.keys[id] = key
+		if p.currentID == "" {
+			p.currentID = id
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read field encryption keyfile: %w", err)
+	}
+	if p.currentID == "" {
+		return nil, fmt.Errorf("field encryption keyfile %s holds no keys", path)
+	}
+	return p, nil
+}
+
+// KeyID identifies a key without revealing it: the first 8 bytes of its
+// SHA-256, in hex.
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func (p *KeyFileProvider) CurrentKey(context.Context) (string, []byte, error) {
+	return p.currentID, p.keys[p.currentID], nil
+}
+
+func (p *KeyFileProvider) Key(_ context.Context, id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("field encryption key %q is not in the keyfile", id)
+	}
+	return key, nil
+}
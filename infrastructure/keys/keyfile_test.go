@@ -0,0 +1,60 @@
+package keys_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wepala/weos/v3/infrastructure/keys"
+)
+
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadKeyFile_FirstKeyIsCurrent(t *testing.T) {
+	current := bytes.Repeat([]byte{1}, keys.KeySize)
+	retired := bytes.Repeat([]byte{2}, keys.KeySize)
+	path := writeKeyFile(t, "# rotated 2026-10-01\n"+
+		base64.StdEncoding.EncodeToString(current)+"\n\n"+
+		base64.StdEncoding.EncodeToString(retired)+"\n")
+
+	p, err := keys.LoadKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	id, key, err := p.CurrentKey(ctx)
+	if err != nil || id != keys.KeyID(current) || !bytes.Equal(key, current) {
+		t.Fatalf("CurrentKey = %q, %v, %v; want the first key", id, key, err)
+	}
+	if key, err := p.Key(ctx, keys.KeyID(retired)); err != nil || !bytes.Equal(key, retired) {
+		t.Errorf("Key(retired) = %v, %v", key, err)
+	}
+	if _, err := p.Key(ctx, "unknown"); err == nil {
+		t.Error("Key(unknown) succeeded")
+	}
+}
+
+func TestLoadKeyFile_RejectsBadKeys(t *testing.T) {
+	for name, content := range map[string]string{
+		"empty":      "# nothing yet\n",
+		"not base64": "not a key\n",
+		"too short":  base64.StdEncoding.EncodeToString([]byte("short")) + "\n",
+	} {
+		if _, err := keys.LoadKeyFile(writeKeyFile(t, content)); err == nil {
+			t.Errorf("%s: loaded", name)
+		}
+	}
+	if _, err := keys.LoadKeyFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("missing keyfile: loaded")
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// AggregateSnapshot is the GORM model for an aggregate's latest snapshot.
+type AggregateSnapshot struct {
+	AggregateID   string `gorm:"primaryKey"`
+	SequenceNo    int
+	SchemaVersion int
+	State         string `gorm:"type:text"`
+	CreatedAt     time.Time
+}
+
+func (m AggregateSnapshot) TableName() string {
+	return "aggregate_snapshots"
+}
+
+func FromAggregateSnapshot(s *entities.AggregateSnapshot) *AggregateSnapshot {
+	return &AggregateSnapshot{
+		AggregateID:   s.AggregateID,
+		SequenceNo:    s.SequenceNo,
+		SchemaVersion: s.SchemaVersion,
+		State:         string(s.State),
+		CreatedAt:     s.CreatedAt,
+	}
+}
+
+func (m *AggregateSnapshot) ToEntity() *entities.AggregateSnapshot {
+	return &entities.AggregateSnapshot{
+		AggregateID:   m.AggregateID,
+		SequenceNo:    m.SequenceNo,
+		SchemaVersion: m.SchemaVersion,
+		State:         []byte(m.State),
+		CreatedAt:     m.CreatedAt,
+	}
+}
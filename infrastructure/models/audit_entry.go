@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// AuditEntry is the GORM model for the audit log. Seq orders entries the way
+// they were recorded, which is event log order.
+type AuditEntry struct {
+	Seq         int64  `gorm:"primaryKey;autoIncrement"`
+	EventID     string `gorm:"not null;uniqueIndex"`
+	EventType   string `gorm:"index"`
+	ResourceID  string `gorm:"index"`
+	TypeSlug    string `gorm:"index"`
+	AgentID     string `gorm:"index"`
+	RealAgentID string
+	AccountID   string `gorm:"index"`
+	AuthMethod  string
+	RequestID   string
+	OccurredAt  time.Time `gorm:"index"`
+}
+
+func (m AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+func (m *AuditEntry) ToEntity() *entities.AuditEntry {
+	return &entities.AuditEntry{
+		Seq:         m.Seq,
+		EventID:     m.EventID,
+		EventType:   m.EventType,
+		ResourceID:  m.ResourceID,
+		TypeSlug:    m.TypeSlug,
+		AgentID:     m.AgentID,
+		RealAgentID: m.RealAgentID,
+		AccountID:   m.AccountID,
+		AuthMethod:  m.AuthMethod,
+		RequestID:   m.RequestID,
+		OccurredAt:  m.OccurredAt,
+	}
+}
+
+func FromAuditEntry(e *entities.AuditEntry) *AuditEntry {
+	return &AuditEntry{
+		EventID:     e.EventID,
+		EventType:   e.EventType,
+		ResourceID:  e.ResourceID,
+		TypeSlug:    e.TypeSlug,
+		AgentID:     e.AgentID,
+		RealAgentID: e.RealAgentID,
+		AccountID:   e.AccountID,
+		AuthMethod:  e.AuthMethod,
+		RequestID:   e.RequestID,
+		OccurredAt:  e.OccurredAt,
+	}
+}
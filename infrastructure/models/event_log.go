@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// EventLogEntry gives a stored event its position. Rows are written in the
+// same transaction as the events themselves.
+type EventLogEntry struct {
+	Position    int64  `gorm:"primaryKey;autoIncrement"`
+	EventID     string `gorm:"not null;uniqueIndex"`
+	AggregateID string `gorm:"not null"`
+	SequenceNo  int
+	EventType   string
+	CreatedAt   time.Time
+}
+
+func (m EventLogEntry) TableName() string {
+	return "event_log"
+}
+
+// EventCheckpoint is the GORM model for an event handler group's position.
+type EventCheckpoint struct {
+	Group       string `gorm:"column:group_name;primaryKey"`
+	Position    int64
+	Attempts    int
+	RetryAt     time.Time
+	LastError   string `gorm:"type:text"`
+	Skipped     int
+	LockedBy    string
+	LockedUntil time.Time
+	UpdatedAt   time.Time
+}
+
+func (m EventCheckpoint) TableName() string {
+	return "event_checkpoints"
+}
+
+func (m *EventCheckpoint) ToEntity() *entities.EventCheckpoint {
+	return &entities.EventCheckpoint{
+		Group:       m.Group,
+		Position:    m.Position,
+		Attempts:    m.Attempts,
+		RetryAt:     m.RetryAt,
+		LastError:   m.LastError,
+		Skipped:     m.Skipped,
+		LockedBy:    m.LockedBy,
+		LockedUntil: m.LockedUntil,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// EventDeadLetter is the GORM model for an event a group gave up on.
+type EventDeadLetter struct {
+	Group     string `gorm:"column:group_name;primaryKey"`
+	Position  int64  `gorm:"primaryKey"`
+	EventID   string `gorm:"not null"`
+	EventType string
+	Attempts  int
+	LastError string `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+func (m EventDeadLetter) TableName() string {
+	return "event_dead_letters"
+}
+
+func (m *EventDeadLetter) ToEntity() *entities.DeadLetteredEvent {
+	return &entities.DeadLetteredEvent{
+		Group:     m.Group,
+		Position:  m.Position,
+		EventID:   m.EventID,
+		EventType: m.EventType,
+		Attempts:  m.Attempts,
+		LastError: m.LastError,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// Job is the GORM model for the durable job queue.
+type Job struct {
+	ID          string `gorm:"primaryKey"`
+	Name        string `gorm:"not null;index:idx_jobs_claim,priority:1"`
+	Payload     string `gorm:"type:text"`
+	Status      string `gorm:"not null;index:idx_jobs_claim,priority:2"`
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time `gorm:"index:idx_jobs_claim,priority:3"`
+	LockedUntil time.Time
+	LastError   string `gorm:"type:text"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (m Job) TableName() string {
+	return "jobs"
+}
+
+func (m *Job) ToEntity() *entities.Job {
+	return &entities.Job{
+		ID:          m.ID,
+		Name:        m.Name,
+		Payload:     json.RawMessage(m.Payload),
+		Status:      m.Status,
+		Attempts:    m.Attempts,
+		MaxAttempts: m.MaxAttempts,
+		RunAt:       m.RunAt,
+		LockedUntil: m.LockedUntil,
+		LastError:   m.LastError,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func FromJob(e *entities.Job) *Job {
+	return &Job{
+		ID:          e.ID,
+		Name:        e.Name,
+		Payload:     string(e.Payload),
+		Status:      e.Status,
+		Attempts:    e.Attempts,
+		MaxAttempts: e.MaxAttempts,
+		RunAt:       e.RunAt,
+		LockedUntil: e.LockedUntil,
+		LastError:   e.LastError,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+// JobLock is the row claims of one job name lock, so that workers sharing a
+// database count running jobs and claim new ones one at a time.
+type JobLock struct {
+	Name      string `gorm:"primaryKey"`
+	ClaimedAt time.Time
+}
+
+func (m JobLock) TableName() string {
+	return "job_locks"
+}
+
+// JobSchedule records when a recurring job next fires.
+type JobSchedule struct {
+	Name      string `gorm:"primaryKey"`
+	NextRunAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m JobSchedule) TableName() string {
+	return "job_schedules"
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Migration records a one-time data migration that has been applied.
+type Migration struct {
+	ID        string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+func (m Migration) TableName() string {
+	return "migrations"
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// PendingEvent is the GORM model for an event the secondary event store has
+// not acknowledged. Rows are written in the same transaction as the events.
+type PendingEvent struct {
+	EventID       string `gorm:"primaryKey"`
+	AggregateID   string `gorm:"not null"`
+	Attempts      int
+	NextAttemptAt time.Time `gorm:"index"`
+	LastError     string    `gorm:"type:text"`
+	CreatedAt     time.Time
+}
+
+func (m PendingEvent) TableName() string {
+	return "event_secondary_pending"
+}
+
+func (m *PendingEvent) ToEntity() *entities.PendingEvent {
+	return &entities.PendingEvent{
+		EventID:       m.EventID,
+		AggregateID:   m.AggregateID,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		CreatedAt:     m.CreatedAt,
+	}
+}
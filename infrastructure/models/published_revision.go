@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// PublishedRevision is the GORM model for the public revision of an
+// x-editorial resource.
+type PublishedRevision struct {
+	ResourceID  string `gorm:"primaryKey"`
+	TypeSlug    string `gorm:"not null;index:idx_pr_type_slug"`
+	AccountID   string `gorm:"index"`
+	Data        string `gorm:"type:text"`
+	PublishedAt time.Time
+}
+
+func (m PublishedRevision) TableName() string {
+	return "published_revisions"
+}
+
+func (m *PublishedRevision) ToEntity() *entities.PublishedRevision {
+	return &entities.PublishedRevision{
+		ResourceID:  m.ResourceID,
+		TypeSlug:    m.TypeSlug,
+		AccountID:   m.AccountID,
+		Data:        json.RawMessage(m.Data),
+		PublishedAt: m.PublishedAt,
+	}
+}
+
+func FromPublishedRevision(e *entities.PublishedRevision) *PublishedRevision {
+	return &PublishedRevision{
+		ResourceID:  e.ResourceID,
+		TypeSlug:    e.TypeSlug,
+		AccountID:   e.AccountID,
+		Data:        string(e.Data),
+		PublishedAt: e.PublishedAt,
+	}
+}
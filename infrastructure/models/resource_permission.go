@@ -8,10 +8,12 @@ import (
 )
 
 // ResourcePermission is the GORM model for instance-level resource permissions.
+// A grant goes to an agent or to a team; the other column is empty.
 type ResourcePermission struct {
 	ID         string `gorm:"primaryKey"`
-	ResourceID string `gorm:"not null;uniqueIndex:idx_rp_resource_agent"`
-	AgentID    string `gorm:"not null;uniqueIndex:idx_rp_resource_agent"`
+	ResourceID string `gorm:"not null;uniqueIndex:idx_rp_resource_grantee"`
+	AgentID    string `gorm:"not null;uniqueIndex:idx_rp_resource_grantee"`
+	TeamID     string `gorm:"not null;default:'';uniqueIndex:idx_rp_resource_grantee;index"`
 	Actions    string `gorm:"type:text"` // JSON array: ["read","modify"]
 	GrantedBy  string `gorm:"not null"`
 	GrantedAt  time.Time
@@ -32,6 +34,7 @@ func (m *ResourcePermission) ToEntity() (*entities.ResourcePermission, error) {
 		ID:         m.ID,
 		ResourceID: m.ResourceID,
 		AgentID:    m.AgentID,
+		TeamID:     m.TeamID,
 		Actions:    actions,
 		GrantedBy:  m.GrantedBy,
 		GrantedAt:  m.GrantedAt,
@@ -47,6 +50,7 @@ func FromResourcePermission(e *entities.ResourcePermission) (*ResourcePermission
 		ID:         e.ID,
 		ResourceID: e.ResourceID,
 		AgentID:    e.AgentID,
+		TeamID:     e.TeamID,
 		Actions:    string(actionsJSON),
 		GrantedBy:  e.GrantedBy,
 		GrantedAt:  e.GrantedAt,
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// RoleAssignment is the GORM model for scoped role assignments. The
+// agent_id index serves the lookups of authorization checks.
+type RoleAssignment struct {
+	ID         string `gorm:"primaryKey"`
+	AgentID    string `gorm:"not null;index"`
+	Role       string `gorm:"not null"`
+	Scope      string `gorm:"not null"`
+	AccountID  string `gorm:"index"`
+	TypeSlug   string
+	ResourceID string
+	GrantedBy  string
+	GrantedAt  time.Time
+}
+
+func (m RoleAssignment) TableName() string {
+	return "role_assignments"
+}
+
+func (m *RoleAssignment) ToEntity() *entities.RoleAssignment {
+	return &entities.RoleAssignment{
+		ID:         m.ID,
+		AgentID:    m.AgentID,
+		Role:       m.Role,
+		Scope:      m.Scope,
+		AccountID:  m.AccountID,
+		TypeSlug:   m.TypeSlug,
+		ResourceID: m.ResourceID,
+		GrantedBy:  m.GrantedBy,
+		GrantedAt:  m.GrantedAt,
+	}
+}
+
+func FromRoleAssignment(e *entities.RoleAssignment) *RoleAssignment {
+	return &RoleAssignment{
+		ID:         e.ID,
+		AgentID:    e.AgentID,
+		Role:       e.Role,
+		Scope:      e.Scope,
+		AccountID:  e.AccountID,
+		TypeSlug:   e.TypeSlug,
+		ResourceID: e.ResourceID,
+		GrantedBy:  e.GrantedBy,
+		GrantedAt:  e.GrantedAt,
+	}
+}
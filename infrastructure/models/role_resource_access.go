@@ -20,9 +20,22 @@ import "time"
 // RoleResourceAccess stores the role→resource-type access configuration as JSON.
 // The Access column is a JSON object mapping role names to their per-resource-type
 // allowed ODRL actions: {"instructor": {"enrollment": ["read","modify"], "invoice": ["read"]}}.
+// The Fields column narrows that to properties, mapping role names to their
+// per-resource-type readable and writable properties:
+// {"instructor": {"person": {"read": ["name","email"], "write": ["name"]}}}.
+// The Deny column has the shape of Access and lists actions a role is
+// explicitly denied, which wins over any grant. The Inherits column maps
+// role names to the roles whose permissions they include:
+// {"reviewer": ["weos:viewer"]}.
+// Each account may have its own row; the row with an empty AccountID is the
+// global default, which accounts without one use.
 type RoleResourceAccess struct {
 	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	AccountID string `gorm:"type:varchar(255);not null;default:'';uniqueIndex"`
 	Access    string `gorm:"type:text"`
+	Fields    string `gorm:"type:text"`
+	Deny      string `gorm:"type:text"`
+	Inherits  string `gorm:"type:text"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -17,8 +17,11 @@ package models
 
 import "time"
 
+// RoleSettings stores the configured role names of an account as a JSON
+// array. The row with an empty AccountID is the global default.
 type RoleSettings struct {
 	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	AccountID string `gorm:"type:varchar(255);not null;default:'';uniqueIndex"`
 	Roles     string `gorm:"type:text"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -17,9 +17,12 @@ package models
 
 import "time"
 
+// SidebarSettings stores an account's sidebar layout for one role. Rows
+// with an empty AccountID are the global defaults.
 type SidebarSettings struct {
 	ID          uint   `gorm:"primaryKey;autoIncrement"`
-	Role        string `gorm:"type:varchar(100);uniqueIndex;default:default"`
+	AccountID   string `gorm:"type:varchar(255);not null;default:'';uniqueIndex:idx_sidebar_account_role"`
+	Role        string `gorm:"type:varchar(100);default:default;uniqueIndex:idx_sidebar_account_role"`
 	HiddenSlugs string `gorm:"type:text"`
 	MenuGroups  string `gorm:"type:text"`
 	CreatedAt   time.Time
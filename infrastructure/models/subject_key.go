@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// SubjectKey is the GORM model for a key that encrypts a subject's personal
+// data in events.
+type SubjectKey struct {
+	ID        string `gorm:"primaryKey"`
+	SubjectID string `gorm:"not null;index"`
+	Key       []byte `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (m SubjectKey) TableName() string {
+	return "pii_subject_keys"
+}
+
+func (m *SubjectKey) ToEntity() *entities.SubjectKey {
+	return &entities.SubjectKey{ID: m.ID, SubjectID: m.SubjectID, Key: m.Key, CreatedAt: m.CreatedAt}
+}
+
+func FromSubjectKey(e *entities.SubjectKey) *SubjectKey {
+	return &SubjectKey{ID: e.ID, SubjectID: e.SubjectID, Key: e.Key, CreatedAt: e.CreatedAt}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// Team is the GORM model for an account's teams.
+type Team struct {
+	ID          string `gorm:"primaryKey"`
+	AccountID   string `gorm:"not null;index"`
+	Name        string `gorm:"not null"`
+	Description string `gorm:"type:text"`
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (m Team) TableName() string {
+	return "teams"
+}
+
+func (m *Team) ToEntity() *entities.Team {
+	return &entities.Team{
+		ID:          m.ID,
+		AccountID:   m.AccountID,
+		Name:        m.Name,
+		Description: m.Description,
+		CreatedBy:   m.CreatedBy,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func FromTeam(e *entities.Team) *Team {
+	return &Team{
+		ID:          e.ID,
+		AccountID:   e.AccountID,
+		Name:        e.Name,
+		Description: e.Description,
+		CreatedBy:   e.CreatedBy,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+// TeamMember is the GORM model for team membership. The agent_id index
+// serves the team lookups of permission checks and list scoping.
+type TeamMember struct {
+	TeamID  string `gorm:"primaryKey"`
+	AgentID string `gorm:"primaryKey;index"`
+	AddedBy string
+	AddedAt time.Time
+}
+
+func (m TeamMember) TableName() string {
+	return "team_members"
+}
+
+func (m *TeamMember) ToEntity() *entities.TeamMember {
+	return &entities.TeamMember{
+		TeamID:  m.TeamID,
+		AgentID: m.AgentID,
+		AddedBy: m.AddedBy,
+		AddedAt: m.AddedAt,
+	}
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UniqueKey reserves one unique constraint value for a resource. Rows are
+// written in the same transaction as the resource's events, so two writes
+// can never both commit the same value, however far the projections lag.
+type UniqueKey struct {
+	Hash       string `gorm:"primaryKey"`
+	ResourceID string `gorm:"not null;index"`
+	CreatedAt  time.Time
+}
+
+func (m UniqueKey) TableName() string {
+	return "unique_keys"
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// WebhookSubscription is the GORM model for outbound webhook subscriptions.
+// Events and TypeSlugs are stored as JSON arrays.
+type WebhookSubscription struct {
+	ID        string `gorm:"primaryKey"`
+	AccountID string `gorm:"index"`
+	URL       string `gorm:"not null"`
+	Events    string `gorm:"type:text"`
+	TypeSlugs string `gorm:"type:text"`
+	Secret    string `gorm:"not null"`
+	Active    bool
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+func (m *WebhookSubscription) ToEntity() *entities.WebhookSubscription {
+	sub := &entities.WebhookSubscription{
+		ID:        m.ID,
+		AccountID: m.AccountID,
+		URL:       m.URL,
+		Secret:    m.Secret,
+		Active:    m.Active,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	_ = json.Unmarshal([]byte(m.Events), &sub.Events)
+	_ = json.Unmarshal([]byte(m.TypeSlugs), &sub.TypeSlugs)
+	return sub
+}
+
+func FromWebhookSubscription(e *entities.WebhookSubscription) *WebhookSubscription {
+	events, _ := json.Marshal(nonNilStrings(e.Events))
+	typeSlugs, _ := json.Marshal(nonNilStrings(e.TypeSlugs))
+	return &WebhookSubscription{
+		ID:        e.ID,
+		AccountID: e.AccountID,
+		URL:       e.URL,
+		Events:    string(events),
+		TypeSlugs: string(typeSlugs),
+		Secret:    e.Secret,
+		Active:    e.Active,
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// WebhookDelivery is the GORM model for the webhook delivery log.
+type WebhookDelivery struct {
+	ID             string `gorm:"primaryKey"`
+	SubscriptionID string `gorm:"not null;index"`
+	EventID        string `gorm:"index"`
+	EventType      string
+	ResourceID     string
+	Payload        string `gorm:"type:text"`
+	Status         string `gorm:"not null"`
+	Attempts       int
+	ResponseStatus int
+	LastError      string `gorm:"type:text"`
+	ReplayOf       string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func (m WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (m *WebhookDelivery) ToEntity() *entities.WebhookDelivery {
+	return &entities.WebhookDelivery{
+		ID:             m.ID,
+		SubscriptionID: m.SubscriptionID,
+		EventID:        m.EventID,
+		EventType:      m.EventType,
+		ResourceID:     m.ResourceID,
+		Payload:        json.RawMessage(m.Payload),
+		Status:         m.Status,
+		Attempts:       m.Attempts,
+		ResponseStatus: m.ResponseStatus,
+		LastError:      m.LastError,
+		ReplayOf:       m.ReplayOf,
+		CreatedAt:      m.CreatedAt,
+		DeliveredAt:    m.DeliveredAt,
+	}
+}
+
+func FromWebhookDelivery(e *entities.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:             e.ID,
+		SubscriptionID: e.SubscriptionID,
+		EventID:        e.EventID,
+		EventType:      e.EventType,
+		ResourceID:     e.ResourceID,
+		Payload:        string(e.Payload),
+		Status:         e.Status,
+		Attempts:       e.Attempts,
+		ResponseStatus: e.ResponseStatus,
+		LastError:      e.LastError,
+		ReplayOf:       e.ReplayOf,
+		CreatedAt:      e.CreatedAt,
+		DeliveredAt:    e.DeliveredAt,
+	}
+}
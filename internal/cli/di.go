@@ -24,6 +24,7 @@ import (
 
 	"github.com/wepala/weos/v3/application"
 	"github.com/wepala/weos/v3/application/presets"
+	"github.com/wepala/weos/v3/domain/repositories"
 	"github.com/wepala/weos/v3/internal/config"
 
 	"go.uber.org/fx"
@@ -33,6 +34,12 @@ import (
 type Dependencies struct {
 	ResourceTypeService application.ResourceTypeService
 	ResourceService     application.ResourceService
+	JobService          application.JobService
+	JobWorker           *application.JobWorker
+	EventRelay          *application.EventRelay
+	Projections         *application.ProjectionMaintainer
+	Eraser              *application.PersonalDataEraser
+	PendingEvents       repositories.PendingEventRepository
 	App                 *fx.App
 }
 
@@ -65,15 +72,33 @@ func StartContainerWithDSN(dsn string) (*Dependencies, error) {
 func startContainerWithConfig(appCfg config.Config) (*Dependencies, error) {
 	var resourceTypeService application.ResourceTypeService
 	var resourceService application.ResourceService
+	var jobService application.JobService
+	var jobWorker *application.JobWorker
+	var eventRelay *application.EventRelay
+	var projections *application.ProjectionMaintainer
+	var eraser *application.PersonalDataEraser
+	var pendingEvents repositories.PendingEventRepository
 
 	app := fx.New(
 		application.Module(appCfg, presets.NewDefaultRegistry()),
 		fx.Invoke(func(
 			rts application.ResourceTypeService,
 			rs application.ResourceService,
+			js application.JobService,
+			jw *application.JobWorker,
+			er *application.EventRelay,
+			pm *application.ProjectionMaintainer,
+			pde *application.PersonalDataEraser,
+			pe repositories.PendingEventRepository,
 		) {
 			resourceTypeService = rts
 			resourceService = rs
+			jobService = js
+			jobWorker = jw
+			eventRelay = er
+			projections = pm
+			eraser = pde
+			pendingEvents = pe
 		}),
 	)
 
@@ -97,6 +122,12 @@ func startContainerWithConfig(appCfg config.Config) (*Dependencies, error) {
 	return &Dependencies{
 		ResourceTypeService: resourceTypeService,
 		ResourceService:     resourceService,
+		JobService:          jobService,
+		JobWorker:           jobWorker,
+		EventRelay:          eventRelay,
+		Projections:         projections,
+		Eraser:              eraser,
+		PendingEvents:       pendingEvents,
 		App:                 app,
 	}, nil
 }
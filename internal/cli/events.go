@@ -17,13 +17,15 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
-	"cloud.google.com/go/bigquery"
 	"github.com/spf13/cobra"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -32,7 +34,10 @@ import (
 	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
 	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/infrastructure"
 
+	"github.com/wepala/weos/v3/application"
+	gormdb "github.com/wepala/weos/v3/infrastructure/database/gorm"
 	bqevents "github.com/wepala/weos/v3/infrastructure/events"
+	"github.com/wepala/weos/v3/infrastructure/models"
 )
 
 var eventsCmd = &cobra.Command{
@@ -47,142 +52,519 @@ var syncBigQueryCmd = &cobra.Command{
 	RunE:  runSyncBigQuery,
 }
 
+var eventsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Copy events from database to an event sink",
+	Long: `Copies the events created in [--after, --before) from the database to an
+event sink, in event log order:
+
+  ndjson    NDJSON files in --dir, rotated by --max-bytes and --rotate-every
+  dir       one NDJSON file per batch under --dir/dt=YYYY-MM-DD, ready for a
+            bucket sync to pick up
+  parquet   Parquet files in --dir, one row group per batch, rotated between
+            batches by --max-bytes and --rotate-every
+  bigquery  the BigQuery events table (BIGQUERY_* settings)
+
+Idempotent — skips events already present in the sink, so an interrupted
+export can be run again.
+
+The x-pii values in events stay sealed under per-subject keys, which are
+not part of the events. Pass --keys-file to write them to a file of their
+own, and import it with the events; without the keys that personal data
+reads as erased. The key file opens every subject's personal data: keep it
+apart from the events and secret. While the database holds subject keys,
+the export refuses to run without --keys-file unless --without-keys is
+given.`,
+	RunE: runEventsExport,
+}
+
+var eventsImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Load events exported to NDJSON or Parquet files into the database",
+	Long: `Appends the events in the NDJSON and Parquet files under --dir, as written
+by the ndjson, dir or parquet sink, to the event store, in the order they were exported.
+Events keep their IDs and sequence numbers; events already stored are
+skipped. Pass the --keys-file written by the export to import the subject
+keys of their personal data too. Run "weos projections rebuild" afterwards
+to build the read models.`,
+	RunE: runEventsImport,
+}
+
+var eventsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show each event handler group's position and lag",
+	Long: `Lists the event handler groups (projections, webhooks, and any preset
+handlers) with the last event position each has handled, how far it is behind
+the head of the event log, and its failure state. With BigQuery dual-write
+enabled it also shows how many events BigQuery has not acknowledged yet.`,
+	RunE: runEventsStatus,
+}
+
+var eventsDeadLettersCmd = &cobra.Command{
+	Use:   "dead-letters",
+	Short: "List the events event handler groups gave up on",
+	Long: `Lists the events a group failed on MaxAttempts times (10 unless the group
+sets it) and moved past. Retry one with "weos events retry".`,
+	RunE: runEventsDeadLetters,
+}
+
+var eventsRetryCmd = &cobra.Command{
+	Use:   "retry <group> <position>",
+	Short: "Run a dead-lettered event through its group again",
+	Long: `Runs the event at position in the event log through group's handlers and,
+when they succeed, removes it from the dead letters.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEventsRetry,
+}
+
 func init() {
 	syncBigQueryCmd.Flags().String("before", "", "Sync events created before this time (RFC3339, e.g. 2026-03-29T00:00:00Z)")
 	_ = syncBigQueryCmd.MarkFlagRequired("before")
 	syncBigQueryCmd.Flags().Int("batch-size", 500, "Number of events per BigQuery insert")
 	syncBigQueryCmd.Flags().Bool("dry-run", false, "Count events to sync without writing")
 
-	eventsCmd.AddCommand(syncBigQueryCmd)
+	eventsExportCmd.Flags().String("sink", "", "Sink to export to: "+strings.Join(bqevents.EventSinkNames(), ", "))
+	_ = eventsExportCmd.MarkFlagRequired("sink")
+	eventsExportCmd.Flags().String("dir", "", "Directory the ndjson, dir and parquet sinks write to")
+	eventsExportCmd.Flags().String("after", "", "Export events created at or after this time (RFC3339)")
+	eventsExportCmd.Flags().String("before", "", "Export events created before this time (RFC3339, default now)")
+	eventsExportCmd.Flags().Int64("max-bytes", 64<<20, "Rotate ndjson and parquet files at this size (0 disables)")
+	eventsExportCmd.Flags().Duration("rotate-every", time.Hour,
+		"Rotate ndjson and parquet files open this long (0 disables)")
+	eventsExportCmd.Flags().Int("batch-size", 500, "Number of events per sink write")
+	eventsExportCmd.Flags().Bool("dry-run", false, "Count events to export without writing")
+	eventsExportCmd.Flags().String("keys-file", "",
+		"Also write the subject keys of x-pii values to this file (sensitive: keep it secret)")
+	eventsExportCmd.Flags().Bool("without-keys", false,
+		"Export without the subject keys, leaving x-pii values unreadable wherever the events are imported")
+
+	eventsImportCmd.Flags().String("dir", "", "Directory of exported NDJSON or Parquet event files")
+	_ = eventsImportCmd.MarkFlagRequired("dir")
+	eventsImportCmd.Flags().Int("batch-size", 500, "Number of events checked against the database at once")
+	eventsImportCmd.Flags().String("keys-file", "", "Subject key file written by \"weos events export --keys-file\"")
+
+	eventsDeadLettersCmd.Flags().String("group", "", "Only list this group's events")
+
+	eventsCmd.AddCommand(syncBigQueryCmd, eventsExportCmd, eventsImportCmd, eventsStatusCmd,
+		eventsDeadLettersCmd, eventsRetryCmd)
 	rootCmd.AddCommand(eventsCmd)
 }
 
-func runSyncBigQuery(cmd *cobra.Command, _ []string) error {
-	cliCfg := GetConfig()
-	appCfg := cliCfg.Config
+func runEventsStatus(cmd *cobra.Command, _ []string) error {
+	deps, err := StartContainer(GetConfig())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = deps.Shutdown() }()
+
+	statuses, err := deps.EventRelay.Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to read event handler status: %w", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "GROUP\tDELIVERY\tPOSITION\tLAG\tATTEMPTS\tSKIPPED\tLAST ERROR")
+	for _, s := range statuses {
+		delivery := "inline"
+		if s.Async {
+			delivery = "async"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
+			s.Group, delivery, s.Position, s.Lag, s.Attempts, s.Skipped, s.LastError)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if GetConfig().Config.BigQueryProjectID == "" {
+		return nil
+	}
 
-	if appCfg.BigQueryProjectID == "" {
-		return fmt.Errorf("BigQuery not configured: set BIGQUERY_PROJECT_ID environment variable")
+	backlog, err := deps.PendingEvents.Backlog(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to read the secondary event store backlog: %w", err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "\nSecondary event store: %d pending", backlog.Count)
+	if backlog.Count > 0 {
+		_, _ = fmt.Fprintf(os.Stdout, ", oldest %s ago, up to %d attempts",
+			time.Since(backlog.Oldest).Round(time.Second), backlog.MaxAttempts)
+		if backlog.LastError != "" {
+			_, _ = fmt.Fprintf(os.Stdout, ", last error: %s", backlog.LastError)
+		}
+	}
+	_, _ = fmt.Fprintln(os.Stdout)
+	return nil
+}
+
+func runEventsDeadLetters(cmd *cobra.Command, _ []string) error {
+	group, _ := cmd.Flags().GetString("group")
+	deps, err := StartContainer(GetConfig())
+	if err != nil {
+		return err
 	}
+	defer func() { _ = deps.Shutdown() }()
 
+	events, err := deps.EventRelay.DeadLetters(cmd.Context(), group)
+	if err != nil {
+		return fmt.Errorf("failed to list dead-lettered events: %w", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "GROUP\tPOSITION\tEVENT\tTYPE\tATTEMPTS\tDEAD-LETTERED\tLAST ERROR")
+	for _, e := range events {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%s\t%s\n", e.Group, e.Position, e.EventID,
+			e.EventType, e.Attempts, e.CreatedAt.Format(time.RFC3339), e.LastError)
+	}
+	return w.Flush()
+}
+
+func runEventsRetry(cmd *cobra.Command, args []string) error {
+	position, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil || position < 1 {
+		return fmt.Errorf("invalid position %q: expected a positive integer", args[1])
+	}
+	deps, err := StartContainer(GetConfig())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = deps.Shutdown() }()
+
+	if err := deps.EventRelay.RetryDeadLetter(cmd.Context(), args[0], position); err != nil {
+		return fmt.Errorf("failed to retry the event at position %d: %w", position, err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "Event at position %d handled by %s\n", position, args[0])
+	return nil
+}
+
+func runSyncBigQuery(cmd *cobra.Command, _ []string) error {
 	beforeStr, _ := cmd.Flags().GetString("before")
 	before, err := time.Parse(time.RFC3339, beforeStr)
 	if err != nil {
 		return fmt.Errorf("invalid --before timestamp (expected RFC3339): %w", err)
 	}
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return runExport(cmd.Context(), "bigquery", bqevents.SinkConfig{App: GetConfig().Config}, exportConfig{
+		before:    before,
+		batchSize: batchSize,
+		dryRun:    dryRun,
+	})
+}
 
+func runEventsExport(cmd *cobra.Command, _ []string) error {
+	sinkName, _ := cmd.Flags().GetString("sink")
+	dir, _ := cmd.Flags().GetString("dir")
+	maxBytes, _ := cmd.Flags().GetInt64("max-bytes")
+	rotateEvery, _ := cmd.Flags().GetDuration("rotate-every")
 	batchSize, _ := cmd.Flags().GetInt("batch-size")
-	if batchSize < 1 || batchSize > 1000 {
-		return fmt.Errorf("--batch-size must be between 1 and 1000")
-	}
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	keysFile, _ := cmd.Flags().GetString("keys-file")
+	withoutKeys, _ := cmd.Flags().GetBool("without-keys")
+	if keysFile != "" && withoutKeys {
+		return fmt.Errorf("--keys-file and --without-keys cannot be used together")
+	}
 
-	datasetID := appCfg.BigQueryDatasetID
-	if datasetID == "" {
-		datasetID = "weos_events"
+	ec := exportConfig{
+		before:      time.Now().UTC(),
+		batchSize:   batchSize,
+		dryRun:      dryRun,
+		keysFile:    keysFile,
+		requireKeys: !withoutKeys,
 	}
-	tableID := appCfg.BigQueryTableID
-	if tableID == "" {
-		tableID = "events"
+	if s, _ := cmd.Flags().GetString("before"); s != "" {
+		before, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("invalid --before timestamp (expected RFC3339): %w", err)
+		}
+		ec.before = before
+	}
+	if s, _ := cmd.Flags().GetString("after"); s != "" {
+		after, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("invalid --after timestamp (expected RFC3339): %w", err)
+		}
+		ec.after = after
 	}
+	return runExport(cmd.Context(), sinkName, bqevents.SinkConfig{
+		Dir:         dir,
+		MaxBytes:    maxBytes,
+		RotateEvery: rotateEvery,
+		App:         GetConfig().Config,
+	}, ec)
+}
 
-	ctx := cmd.Context()
+func runExport(ctx context.Context, sinkName string, sinkCfg bqevents.SinkConfig, ec exportConfig) error {
+	if ec.batchSize < 1 || ec.batchSize > 1000 {
+		return fmt.Errorf("--batch-size must be between 1 and 1000")
+	}
+	if !ec.after.IsZero() && !ec.after.Before(ec.before) {
+		return fmt.Errorf("--after must be before --before")
+	}
 
-	db, err := openDB(appCfg.DatabaseDSN)
+	db, _, err := openEventStore(sinkCfg.App.DatabaseDSN)
 	if err != nil {
 		return err
 	}
 	sqlDB, _ := db.DB()
 	defer func() { _ = sqlDB.Close() }()
 
-	bqClient, err := bigquery.NewClient(ctx, appCfg.BigQueryProjectID)
+	sink, err := bqevents.OpenEventSink(ctx, sinkName, sinkCfg)
 	if err != nil {
-		return fmt.Errorf("failed to create BigQuery client: %w", err)
+		return err
 	}
-	defer func() { _ = bqClient.Close() }()
+	return errors.Join(exportTo(ctx, db, sink, sinkName, ec), sink.Close())
+}
 
-	fmt.Fprintf(os.Stderr, "Fetching existing event IDs from BigQuery...\n")
-	existingIDs, err := bqevents.GetExistingEventIDs(ctx, bqClient, appCfg.BigQueryProjectID, datasetID, tableID, before)
+func exportTo(ctx context.Context, db *gorm.DB, sink bqevents.EventSink, sinkName string, ec exportConfig) error {
+	var err error
+	fmt.Fprintf(os.Stderr, "Fetching event IDs already in the %s sink...\n", sinkName)
+	ec.existingIDs, err = sink.ExistingIDs(ctx, ec.after, ec.before)
 	if err != nil {
-		return fmt.Errorf("failed to fetch existing BigQuery events: %w", err)
+		return fmt.Errorf("failed to fetch events already in the sink: %w", err)
 	}
-	fmt.Fprintf(os.Stderr, "Found %d events already in BigQuery\n", len(existingIDs))
+	fmt.Fprintf(os.Stderr, "Found %d events already in the sink\n", len(ec.existingIDs))
 
-	var totalCount int64
-	if err := db.Model(&infrastructure.GormEventModel{}).Where("created_at < ?", before).Count(&totalCount).Error; err != nil {
+	if err := eventWindow(db.Model(&infrastructure.GormEventModel{}), ec).Count(&ec.totalCount).Error; err != nil {
 		return fmt.Errorf("failed to count events: %w", err)
 	}
 
-	if dryRun {
-		_, _ = fmt.Fprintf(os.Stdout, "Dry run: %d events in database before %s, %d already in BigQuery, up to %d to sync\n",
-			totalCount, before.Format(time.RFC3339), len(existingIDs), totalCount-int64(len(existingIDs)))
+	if err := checkSubjectKeys(db, ec); err != nil {
+		return err
+	}
+	if ec.dryRun {
+		_, _ = fmt.Fprintf(os.Stdout,
+			"Dry run: %d events in database before %s, %d already in the sink, up to %d to export\n",
+			ec.totalCount, ec.before.Format(time.RFC3339), len(ec.existingIDs), ec.totalCount-int64(len(ec.existingIDs)))
 		return nil
 	}
 
-	fmt.Fprintf(os.Stderr, "Syncing up to %d events (batch size %d)...\n", totalCount, batchSize)
+	if ec.keysFile != "" {
+		n, err := exportSubjectKeys(ctx, db, ec.keysFile)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d subject keys to %s. It opens every subject's personal data: keep it secret.\n",
+			n, ec.keysFile)
+	}
+	fmt.Fprintf(os.Stderr, "Exporting up to %d events (batch size %d)...\n", ec.totalCount, ec.batchSize)
+	return exportLoop(ctx, os.Stderr, db, sink, ec)
+}
 
-	return syncLoop(ctx, os.Stderr, db, bqClient, syncConfig{
-		projectID:   appCfg.BigQueryProjectID,
-		datasetID:   datasetID,
-		tableID:     tableID,
-		before:      before,
-		batchSize:   batchSize,
-		totalCount:  totalCount,
-		existingIDs: existingIDs,
-	})
+// checkSubjectKeys refuses an export that requires keys but has no key
+// file while the database holds subject keys: the exported x-pii values
+// could not be opened anywhere else.
+func checkSubjectKeys(db *gorm.DB, ec exportConfig) error {
+	if !ec.requireKeys || ec.keysFile != "" {
+		return nil
+	}
+	n, err := countSubjectKeys(db)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("the events' personal data is sealed under %d subject keys that are not exported with them: "+
+			"pass --keys-file to export the keys too, or --without-keys to leave that data unreadable "+
+			"wherever the events are imported", n)
+	}
+	return nil
 }
 
-type syncConfig struct {
-	projectID   string
-	datasetID   string
-	tableID     string
+type exportConfig struct {
+	after       time.Time
 	before      time.Time
 	batchSize   int
+	dryRun      bool
 	totalCount  int64
 	existingIDs map[string]struct{}
+	// keysFile receives the subject keys; requireKeys refuses to export
+	// without it while the database holds any (see checkSubjectKeys).
+	keysFile    string
+	requireKeys bool
+}
+
+// eventWindow limits q to the events created in the export's window.
+func eventWindow(q *gorm.DB, ec exportConfig) *gorm.DB {
+	q = q.Where("events.created_at < ?", ec.before)
+	if !ec.after.IsZero() {
+		q = q.Where("events.created_at >= ?", ec.after)
+	}
+	return q
 }
 
-func syncLoop(
-	ctx context.Context, w io.Writer, db *gorm.DB, bqClient *bigquery.Client, sc syncConfig,
+// loggedEvent is a stored event with its event log position.
+type loggedEvent struct {
+	infrastructure.GormEventModel `gorm:"embedded"`
+	Position                      int64
+}
+
+// exportLoop copies the events in the window to the sink in event log
+// order, batch by batch, skipping those the sink already holds. The log
+// position is the cursor, so an import replays the events in the order they
+// were stored.
+func exportLoop(
+	ctx context.Context, w io.Writer, db *gorm.DB, sink bqevents.EventSink, ec exportConfig,
 ) error {
-	cursor := ""
-	var synced, skipped int64
+	var cursor int64
+	var exported, skipped int64
 
 	for {
-		var models []infrastructure.GormEventModel
-		q := db.Where("created_at < ? AND id > ?", sc.before, cursor).
-			Order("id ASC").
-			Limit(sc.batchSize).
-			Find(&models)
+		var rows []loggedEvent
+		q := eventWindow(db.Table("events").
+			Select("events.*, event_log.position").
+			Joins("JOIN event_log ON event_log.event_id = events.id"), ec).
+			Where("event_log.position > ?", cursor).
+			Order("event_log.position ASC").
+			Limit(ec.batchSize).
+			Find(&rows)
 		if q.Error != nil {
 			return fmt.Errorf("failed to read events: %w", q.Error)
 		}
-		if len(models) == 0 {
+		if len(rows) == 0 {
 			break
 		}
 
-		var toInsert []domain.EventEnvelope[any]
-		for _, m := range models {
-			if _, exists := sc.existingIDs[m.ID]; exists {
+		var toWrite []domain.EventEnvelope[any]
+		for _, r := range rows {
+			if _, exists := ec.existingIDs[r.ID]; exists {
 				skipped++
 				continue
 			}
-			toInsert = append(toInsert, modelToEnvelope(m))
+			toWrite = append(toWrite, modelToEnvelope(r.GormEventModel))
 		}
 
-		if len(toInsert) > 0 {
-			if err := bqevents.BatchInsertEvents(ctx, bqClient, sc.projectID, sc.datasetID, sc.tableID, toInsert); err != nil {
-				return fmt.Errorf("failed to insert batch at cursor %s: %w", cursor, err)
+		if len(toWrite) > 0 {
+			if err := sink.Write(ctx, toWrite); err != nil {
+				return fmt.Errorf("failed to write batch after position %d: %w", cursor, err)
 			}
-			synced += int64(len(toInsert))
+			exported += int64(len(toWrite))
 		}
 
-		cursor = models[len(models)-1].ID
-		_, _ = fmt.Fprintf(w, "  Progress: %d synced, %d skipped (of %d total)\n",
-			synced, skipped, sc.totalCount)
+		cursor = rows[len(rows)-1].Position
+		_, _ = fmt.Fprintf(w, "  Progress: %d exported, %d skipped (of %d total)\n",
+			exported, skipped, ec.totalCount)
+	}
+
+	_, _ = fmt.Fprintf(w, "Export complete: %d events written, %d skipped (already existed)\n", exported, skipped)
+	return nil
+}
+
+func runEventsImport(cmd *cobra.Command, _ []string) error {
+	dir, _ := cmd.Flags().GetString("dir")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	keysFile, _ := cmd.Flags().GetString("keys-file")
+	if batchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+
+	db, store, err := openEventStore(GetConfig().Config.DatabaseDSN)
+	if err != nil {
+		return err
 	}
+	sqlDB, _ := db.DB()
+	defer func() { _ = sqlDB.Close() }()
 
-	_, _ = fmt.Fprintf(w, "Sync complete: %d events inserted, %d skipped (already existed)\n", synced, skipped)
+	if err := importEvents(cmd.Context(), os.Stderr, db, store, dir, keysFile, batchSize); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(os.Stderr, "Run `weos projections rebuild` to build the read models from the imported events.")
+	return nil
+}
+
+// importEvents appends the events in the event files under dir to the
+// event store, in file order, skipping events it already holds. Events keep
+// their IDs, sequence numbers, and stored payload versions. The subject keys
+// in keysFile, when given, are stored first; it warns when imported events
+// hold personal data sealed under keys the database lacks.
+func importEvents(
+	ctx context.Context, w io.Writer, db *gorm.DB, store domain.EventStore, dir, keysFile string, batchSize int,
+) error {
+	if keysFile != "" {
+		n, err := importSubjectKeys(ctx, db, keysFile)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "Imported %d subject keys\n", n)
+	}
+
+	var imported, skipped int64
+	var batch []domain.EventEnvelope[any]
+	sealedKeys := make(map[string]struct{})
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ids := make([]string, len(batch))
+		for i, e := range batch {
+			ids[i] = e.ID
+		}
+		var existing []string
+		if err := db.Model(&infrastructure.GormEventModel{}).Where("id IN ?", ids).
+			Pluck("id", &existing).Error; err != nil {
+			return fmt.Errorf("failed to look up imported events: %w", err)
+		}
+		stored := make(map[string]struct{}, len(existing))
+		for _, id := range existing {
+			stored[id] = struct{}{}
+		}
+		// Append consecutive events of one aggregate together.
+		var run []domain.EventEnvelope[any]
+		appendRun := func() error {
+			if len(run) == 0 {
+				return nil
+			}
+			if err := store.Append(ctx, run[0].AggregateID, -1, run...); err != nil {
+				return fmt.Errorf("failed to import events of %s: %w", run[0].AggregateID, err)
+			}
+			imported += int64(len(run))
+			run = run[:0]
+			return nil
+		}
+		for _, e := range batch {
+			if _, ok := stored[e.ID]; ok {
+				skipped++
+				continue
+			}
+			if len(run) > 0 && run[0].AggregateID != e.AggregateID {
+				if err := appendRun(); err != nil {
+					return err
+				}
+			}
+			run = append(run, e)
+		}
+		if err := appendRun(); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		_, _ = fmt.Fprintf(w, "  Progress: %d imported, %d skipped\n", imported, skipped)
+		return nil
+	}
+
+	err := bqevents.ReadEventFiles(ctx, dir, func(e domain.EventEnvelope[any]) error {
+		for _, id := range application.SealedKeyIDs(e.Payload) {
+			sealedKeys[id] = struct{}{}
+		}
+		batch = append(batch, e)
+		if len(batch) < batchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "Import complete: %d events imported, %d skipped (already existed)\n", imported, skipped)
+
+	missing, err := missingSubjectKeys(ctx, db, sealedKeys)
+	if err != nil {
+		return err
+	}
+	if missing > 0 {
+		_, _ = fmt.Fprintf(w, "WARNING: the events hold personal data sealed under %d subject keys this database "+
+			"does not have, so it reads as erased. Import the key file written by "+
+			"\"weos events export --keys-file\" with --keys-file; keys erased at the source are gone for good.\n",
+			missing)
+	}
 	return nil
 }
 
@@ -217,3 +599,21 @@ func openDB(dsn string) (*gorm.DB, error) {
 	}
 	return db, nil
 }
+
+// openEventStore opens the database with its event store. Opening the store
+// creates the events and event log tables if needed, and gives any events
+// stored before the event log existed their positions.
+func openEventStore(dsn string) (*gorm.DB, *gormdb.EventStore, error) {
+	db, err := openDB(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := db.AutoMigrate(&models.EventLogEntry{}); err != nil {
+		return nil, nil, fmt.Errorf("failed to migrate the event log: %w", err)
+	}
+	store, err := gormdb.NewEventStore(db)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open the event store: %w", err)
+	}
+	return db, store, nil
+}
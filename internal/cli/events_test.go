@@ -0,0 +1,210 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akeemphilbert/pericarp/pkg/eventsourcing/domain"
+	"gorm.io/gorm"
+
+	bqevents "github.com/wepala/weos/v3/infrastructure/events"
+	"github.com/wepala/weos/v3/infrastructure/models"
+)
+
+func openTestEventStore(t *testing.T, name string) (*gorm.DB, domain.EventStore) {
+	t.Helper()
+	db, store, err := openEventStore(filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		_ = sqlDB.Close()
+	})
+	return db, store
+}
+
+// loggedOrder lists the events in event log order as "aggregate#sequence".
+func loggedOrder(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var entries []models.EventLogEntry
+	if err := db.Order("position").Find(&entries).Error; err != nil {
+		t.Fatal(err)
+	}
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = fmt.Sprintf("%s#%d", e.AggregateID, e.SequenceNo)
+	}
+	return out
+}
+
+func TestExportImport_RoundTripsTheEventLog(t *testing.T) {
+	for _, sinkName := range []string{"ndjson", "parquet"} {
+		t.Run(sinkName, func(t *testing.T) { testExportImportRoundTrip(t, sinkName) })
+	}
+}
+
+func testExportImportRoundTrip(t *testing.T, sinkName string) {
+	ctx := context.Background()
+	src, store := openTestEventStore(t, "source.db")
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	// Two aggregates, interleaved, so the log order is not the ID order.
+	for i, id := range []string{"urn:task:b", "urn:task:a", "urn:task:b", "urn:task:a", "urn:task:b"} {
+		seq := i/2 + 1
+		err := store.Append(ctx, id, -1, domain.EventEnvelope[any]{
+			ID: fmt.Sprintf("evt-%d", 9-i), AggregateID: id, SequenceNo: seq, EventType: "Resource.Updated",
+			Created:  start.Add(time.Duration(i) * time.Minute),
+			Payload:  map[string]any{"Data": map[string]any{"n": i}},
+			Metadata: map[string]any{"schemaVersion": 1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	export := func(ec exportConfig) {
+		t.Helper()
+		sink, err := bqevents.OpenEventSink(ctx, sinkName, bqevents.SinkConfig{Dir: dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ec.existingIDs, err = sink.ExistingIDs(ctx, ec.after, ec.before); err != nil {
+			t.Fatal(err)
+		}
+		if err := exportLoop(ctx, io.Discard, src, sink, ec); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	countFiles := func() int {
+		files, _ := filepath.Glob(filepath.Join(dir, "*."+sinkName))
+		return len(files)
+	}
+
+	export(exportConfig{before: start.Add(2 * time.Minute), batchSize: 2})
+	export(exportConfig{after: start.Add(2 * time.Minute), before: start.Add(time.Hour), batchSize: 2})
+	if countFiles() != 2 {
+		t.Fatalf("%d files after two exports, want 2", countFiles())
+	}
+	export(exportConfig{before: start.Add(time.Hour), batchSize: 2})
+	if countFiles() != 2 {
+		t.Errorf("re-running the export wrote %d new files, want none", countFiles()-2)
+	}
+
+	dst, dstStore := openTestEventStore(t, "destination.db")
+	for range 2 {
+		if err := importEvents(ctx, io.Discard, dst, dstStore, dir, "", 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, got := loggedOrder(t, src), loggedOrder(t, dst)
+	if !slices.Equal(got, want) {
+		t.Fatalf("imported log order = %v, want %v", got, want)
+	}
+
+	events, err := dstStore.GetEvents(ctx, "urn:task:b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 || events[2].ID != "evt-5" || !events[2].Created.Equal(start.Add(4*time.Minute)) {
+		t.Fatalf("imported events of urn:task:b = %+v", events)
+	}
+	data, _ := events[2].Payload.(map[string]any)["Data"].(map[string]any)
+	if data["n"] != float64(4) || events[2].Metadata["schemaVersion"] != float64(1) {
+		t.Errorf("imported event = %+v", events[2])
+	}
+}
+
+func TestExportImport_CarriesSubjectKeys(t *testing.T) {
+	ctx := context.Background()
+	src, store := openTestEventStore(t, "source.db")
+	if err := src.AutoMigrate(&models.SubjectKey{}); err != nil {
+		t.Fatal(err)
+	}
+	key := models.SubjectKey{ID: "key-1", SubjectID: "urn:patient:a", Key: []byte("0123456789abcdef0123456789abcdef")}
+	if err := src.Create(&key).Error; err != nil {
+		t.Fatal(err)
+	}
+	err := store.Append(ctx, "urn:patient:a", -1, domain.EventEnvelope[any]{
+		ID: "evt-1", AggregateID: "urn:patient:a", SequenceNo: 1, EventType: "Resource.Created",
+		Created:  time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+		Payload:  map[string]any{"Data": map[string]any{"name": "$pii:key-1:c2VhbGVk"}},
+		Metadata: map[string]any{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkSubjectKeys(src, exportConfig{requireKeys: true}); err == nil {
+		t.Fatal("an export without the subject keys of sealed events was not refused")
+	}
+	if err := checkSubjectKeys(src, exportConfig{}); err != nil {
+		t.Fatalf("--without-keys: %v", err)
+	}
+
+	dir := t.TempDir()
+	keysFile := filepath.Join(t.TempDir(), "keys.ndjson")
+	if n, err := exportSubjectKeys(ctx, src, keysFile); err != nil || n != 1 {
+		t.Fatalf("exportSubjectKeys = %d, %v; want 1 key", n, err)
+	}
+	if info, err := os.Stat(keysFile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("key file mode = %v, %v; want 0600", info, err)
+	}
+	sink, err := bqevents.OpenEventSink(ctx, "ndjson", bqevents.SinkConfig{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exportLoop(ctx, io.Discard, src, sink, exportConfig{before: time.Now(), batchSize: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	bare, bareStore := openTestEventStore(t, "without-keys.db")
+	if err := importEvents(ctx, &out, bare, bareStore, dir, "", 10); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "WARNING") {
+		t.Errorf("importing sealed events without their keys did not warn:\n%s", out.String())
+	}
+
+	out.Reset()
+	dst, dstStore := openTestEventStore(t, "with-keys.db")
+	if err := importEvents(ctx, &out, dst, dstStore, dir, keysFile, 10); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "WARNING") {
+		t.Errorf("importing with the key file warned:\n%s", out.String())
+	}
+	var imported models.SubjectKey
+	if err := dst.First(&imported, "id = ?", "key-1").Error; err != nil || string(imported.Key) != string(key.Key) {
+		t.Errorf("imported key = %+v, %v", imported, err)
+	}
+}
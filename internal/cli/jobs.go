@@ -0,0 +1,118 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/domain/entities"
+
+	"github.com/spf13/cobra"
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Manage background jobs",
+}
+
+var jobsEnqueueCmd = &cobra.Command{
+	Use:   "enqueue [name]",
+	Short: "Queue a run of a registered job",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deps, err := StartContainer(GetConfig())
+		if err != nil {
+			return err
+		}
+		defer func() { _ = deps.Shutdown() }()
+
+		payload, _ := cmd.Flags().GetString("payload")
+		atStr, _ := cmd.Flags().GetString("at")
+		var runAt time.Time
+		if atStr != "" {
+			if runAt, err = time.Parse(time.RFC3339, atStr); err != nil {
+				return fmt.Errorf("invalid --at timestamp (expected RFC3339): %w", err)
+			}
+		}
+		job, err := deps.JobService.Enqueue(cmd.Context(), application.EnqueueJobCommand{
+			Name:    args[0],
+			Payload: json.RawMessage(payload),
+			RunAt:   runAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enqueue job: %w", err)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "Enqueued job: %s\n", job.ID)
+		return nil
+	},
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List jobs by status",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deps, err := StartContainer(GetConfig())
+		if err != nil {
+			return err
+		}
+		defer func() { _ = deps.Shutdown() }()
+
+		status, _ := cmd.Flags().GetString("status")
+		limit, _ := cmd.Flags().GetInt("limit")
+		cursor, _ := cmd.Flags().GetString("cursor")
+		result, err := deps.JobService.List(cmd.Context(), status, cursor, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list jobs: %w", err)
+		}
+		data, _ := json.MarshalIndent(result, "", "  ")
+		_, _ = fmt.Fprintln(os.Stdout, string(data))
+		return nil
+	},
+}
+
+var jobsRequeueCmd = &cobra.Command{
+	Use:   "requeue [id]",
+	Short: "Give a dead-lettered job a fresh set of attempts",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deps, err := StartContainer(GetConfig())
+		if err != nil {
+			return err
+		}
+		defer func() { _ = deps.Shutdown() }()
+
+		if err := deps.JobService.Requeue(cmd.Context(), args[0]); err != nil {
+			return fmt.Errorf("failed to requeue job: %w", err)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "Requeued job: %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	jobsEnqueueCmd.Flags().String("payload", "", "JSON payload passed to the job handler")
+	jobsEnqueueCmd.Flags().String("at", "", "run at this time (RFC3339) instead of now")
+	jobsListCmd.Flags().String("status", entities.JobDead, "pending, running, succeeded, or dead")
+	jobsListCmd.Flags().Int("limit", 20, "Number of items per page")
+	jobsListCmd.Flags().String("cursor", "", "Pagination cursor")
+
+	jobsCmd.AddCommand(jobsEnqueueCmd, jobsListCmd, jobsRequeueCmd)
+	rootCmd.AddCommand(jobsCmd)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wepala/weos/v3/application"
+
+	"github.com/spf13/cobra"
+)
+
+var projectionsCmd = &cobra.Command{
+	Use:   "projections",
+	Short: "Rebuild and verify read models",
+}
+
+var projectionsRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Truncate read models and replay them from the event store",
+	Long: `Truncates the resources table and the projection tables, the triples
+table, or both, and replays the event log through the same handlers that
+maintain them, in batches. With --type only that type's resources are rebuilt;
+with --triples only the triples table. Without either flag, everything is.
+
+An interrupted rebuild resumes where it stopped when it is run again with the
+same flags. --restart starts it over.`,
+	RunE: runProjectionsRebuild,
+}
+
+var projectionsVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Compare read models with the events and report or repair drift",
+	Long: `Replays every resource's events and compares the result with its row in
+the resources table, its projection row, and its triples. Drifted resources are
+listed, and rewritten from their events with --repair. Without --repair the
+command fails when it finds drift.
+
+An interrupted verify resumes where it stopped when it is run again with the
+same flags. --restart starts it over.`,
+	RunE: runProjectionsVerify,
+}
+
+func init() {
+	projectionsRebuildCmd.Flags().String("type", "", "rebuild only the resources of this type")
+	projectionsRebuildCmd.Flags().Bool("triples", false, "rebuild the triples table")
+	projectionsRebuildCmd.Flags().Bool("restart", false, "discard an interrupted rebuild and start over")
+	projectionsRebuildCmd.Flags().Int("batch-size", 500, "Number of events replayed per batch")
+
+	projectionsVerifyCmd.Flags().String("type", "", "verify only the resources of this type")
+	projectionsVerifyCmd.Flags().Bool("repair", false, "rewrite drifted read models from their events")
+	projectionsVerifyCmd.Flags().Bool("restart", false, "discard an interrupted verify and start over")
+	projectionsVerifyCmd.Flags().Int("batch-size", 500, "Number of events read per batch")
+
+	projectionsCmd.AddCommand(projectionsRebuildCmd, projectionsVerifyCmd)
+	rootCmd.AddCommand(projectionsCmd)
+}
+
+func printProjectionProgress(p application.ProjectionProgress) {
+	_, _ = fmt.Fprintf(os.Stderr, "  %s: position %d of %d, %d handled, %d failed, %d drifted\n",
+		p.Task, p.Position, p.Head, p.Handled, p.Failed, p.Drifted)
+}
+
+func runProjectionsRebuild(cmd *cobra.Command, _ []string) error {
+	typeSlug, _ := cmd.Flags().GetString("type")
+	triples, _ := cmd.Flags().GetBool("triples")
+	restart, _ := cmd.Flags().GetBool("restart")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+	deps, err := StartContainer(GetConfig())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = deps.Shutdown() }()
+
+	results, err := deps.Projections.Rebuild(cmd.Context(), application.RebuildOptions{
+		Resources: typeSlug != "" || !triples,
+		TypeSlug:  typeSlug,
+		Triples:   triples || typeSlug == "",
+		Restart:   restart,
+		BatchSize: batchSize,
+		Progress:  printProjectionProgress,
+	})
+	for _, p := range results {
+		resumed := ""
+		if p.Resumed {
+			resumed = " (resumed)"
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "%s%s: replayed %d events, %d failed\n", p.Task, resumed, p.Handled, p.Failed)
+	}
+	if err != nil {
+		return fmt.Errorf("rebuild failed; run it again to resume: %w", err)
+	}
+	return nil
+}
+
+func runProjectionsVerify(cmd *cobra.Command, _ []string) error {
+	typeSlug, _ := cmd.Flags().GetString("type")
+	repair, _ := cmd.Flags().GetBool("repair")
+	restart, _ := cmd.Flags().GetBool("restart")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+	deps, err := StartContainer(GetConfig())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = deps.Shutdown() }()
+
+	p, err := deps.Projections.Verify(cmd.Context(), application.VerifyOptions{
+		TypeSlug:  typeSlug,
+		Repair:    repair,
+		Restart:   restart,
+		BatchSize: batchSize,
+		Progress:  printProjectionProgress,
+		OnDrift: func(d application.ProjectionDrift) {
+			state := "drifted"
+			if d.Repaired {
+				state = "repaired"
+			}
+			_, _ = fmt.Fprintf(os.Stdout, "%s %s: %s\n", state, d.ResourceID, strings.Join(d.Problems, "; "))
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("verify failed; run it again to resume: %w", err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "Verified %d resources, %d drifted\n", p.Handled, p.Drifted)
+	if p.Drifted > 0 && !repair {
+		return fmt.Errorf("%d resources have drifted; run with --repair to fix them", p.Drifted)
+	}
+	return nil
+}
@@ -127,6 +127,31 @@ var resourceDeleteCmd = &cobra.Command{
 	},
 }
 
+var resourceEraseCmd = &cobra.Command{
+	Use:   "erase [id]",
+	Short: "Erase a resource's personal data",
+	Long: `Destroys the keys the resource's x-pii properties are encrypted with, then
+rewrites its read models from the events, where those values now read as null.
+Every other copy of the events, such as BigQuery and exports, becomes unreadable
+too. The resource itself is kept. Erasure cannot be undone.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deps, err := StartContainer(GetConfig())
+		if err != nil {
+			return err
+		}
+		defer func() { _ = deps.Shutdown() }()
+
+		result, err := deps.Eraser.Erase(cmd.Context(), args[0])
+		if err != nil {
+			return fmt.Errorf("failed to erase personal data: %w", err)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "Erased personal data of %s (%d keys destroyed)\n",
+			result.ResourceID, result.KeysDestroyed)
+		return nil
+	},
+}
+
 func init() {
 	resourceCreateCmd.Flags().String("type", "", "Resource type slug")
 	_ = resourceCreateCmd.MarkFlagRequired("type")
@@ -140,7 +165,7 @@ func init() {
 
 	resourceCmd.AddCommand(
 		resourceCreateCmd, resourceGetCmd,
-		resourceListCmd, resourceDeleteCmd,
+		resourceListCmd, resourceDeleteCmd, resourceEraseCmd,
 	)
 	rootCmd.AddCommand(resourceCmd)
 }
@@ -25,6 +25,7 @@ import (
 	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"github.com/wepala/weos/v3/application"
 	"github.com/wepala/weos/v3/application/presets"
 	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
 	gormdb "github.com/wepala/weos/v3/infrastructure/database/gorm"
 	"github.com/wepala/weos/v3/internal/config"
 	mcpserver "github.com/wepala/weos/v3/internal/mcp"
@@ -76,6 +78,7 @@ var serveCmd = &cobra.Command{
 
 func init() {
 	serveCmd.Flags().Bool("mcp", true, "enable MCP server over HTTP at /api/mcp")
+	serveCmd.Flags().Bool("worker", true, "run the background job worker and event relay in-process")
 	serveViper.SetEnvPrefix("MCP")
 	serveViper.AutomaticEnv()
 	if err := serveViper.BindPFlag("enabled", serveCmd.Flags().Lookup("mcp")); err != nil {
@@ -90,12 +93,23 @@ func runServe(cmd *cobra.Command, args []string) error {
 	var resourceTypeService application.ResourceTypeService
 	var resourceService application.ResourceService
 	var resourcePermService application.ResourcePermissionService
+	var editorialService application.EditorialService
+	var jobWorker *application.JobWorker
+	var eventRelay *application.EventRelay
+	var webhookService application.WebhookService
+	var teamService application.TeamService
+	var roleAuthorizer application.RoleAuthorizer
+	var roleAssignmentService application.RoleAssignmentService
+	var authzCheckService application.AuthorizationCheckService
+	var eventStreamService application.EventStreamService
 	var fileService application.FileService
 	var authService authapp.AuthenticationService
 	var sessionManager session.SessionManager
 	var credentialRepo authrepos.CredentialRepository
 	var agentRepo authrepos.AgentRepository
 	var accountRepo authrepos.AccountRepository
+	var auditRepo repositories.AuditRepository
+	var roleAssignmentRepo repositories.RoleAssignmentRepository
 	var sessionStore sessions.Store
 	var logger entities.Logger
 	var sidebarSettingsRepo *gormdb.SidebarSettingsRepository
@@ -117,12 +131,23 @@ func runServe(cmd *cobra.Command, args []string) error {
 		fx.Populate(&resourceTypeService),
 		fx.Populate(&resourceService),
 		fx.Populate(&resourcePermService),
+		fx.Populate(&editorialService),
+		fx.Populate(&jobWorker),
+		fx.Populate(&eventRelay),
+		fx.Populate(&webhookService),
+		fx.Populate(&teamService),
+		fx.Populate(&roleAuthorizer),
+		fx.Populate(&roleAssignmentService),
+		fx.Populate(&authzCheckService),
+		fx.Populate(&eventStreamService),
 		fx.Populate(&fileService),
 		fx.Populate(&authService),
 		fx.Populate(&sessionManager),
 		fx.Populate(&credentialRepo),
 		fx.Populate(&agentRepo),
 		fx.Populate(&accountRepo),
+		fx.Populate(&auditRepo),
+		fx.Populate(&roleAssignmentRepo),
 		fx.Populate(&sessionStore),
 		fx.Populate(&logger),
 		fx.Populate(&sidebarSettingsRepo),
@@ -145,14 +170,10 @@ func runServe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to start application: %w", err)
 	}
 
-	// Sync role-access policies from the config table into Casbin.
-	accessMap, accessMapErr := roleAccessRepo.GetAccessMap(context.Background())
-	if accessMapErr != nil {
-		logger.Warn(context.Background(), "failed to load role access map at startup, RBAC policies may be incomplete", "error", accessMapErr)
-	} else {
-		if syncErr := application.SyncAccessMapToCasbin(authzChecker, accessMap, nil); syncErr != nil {
-			logger.Warn(context.Background(), "casbin policy sync errors at startup", "error", syncErr)
-		}
+	// Sync the role-access policies of the global default and every account
+	// from the config table into Casbin.
+	if syncErr := application.LoadRoleAccessIntoCasbin(context.Background(), authzChecker, roleAccessRepo); syncErr != nil {
+		logger.Warn(context.Background(), "casbin policy sync errors at startup, RBAC policies may be incomplete", "error", syncErr)
 	}
 	if seedErr := application.SeedAdminPolicies(authzChecker); seedErr != nil {
 		logger.Warn(context.Background(), "failed to seed admin policies at startup", "error", seedErr)
@@ -168,6 +189,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	api := e.Group("/api")
 	api.Use(apimw.Messages())
+	api.Use(apimw.RequestID())
 	api.GET("/health", handlers.HealthHandler)
 
 	// Auth routes (pericarp built-in handlers wrapped for Echo)
@@ -288,8 +310,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 	protected := api.Group("")
 	if appCfg.AuthEnabled() {
 		protected.Use(echo.WrapMiddleware(authhttp.RequireAuth(sessionManager, authService)))
+		protected.Use(apimw.AuthMethod(entities.AuthMethodSession))
 		protected.Use(apimw.Impersonation(sessionStore, accountRepo, logger))
-		protected.Use(apimw.AuthorizeResource(authzChecker, accountRepo, logger))
+		protected.Use(apimw.AuthorizeResource(roleAuthorizer, accountRepo, logger))
 	} else {
 		protected.Use(apimw.SoftAuth(credentialRepo, agentRepo, accountRepo, logger))
 	}
@@ -309,7 +332,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	protected.DELETE("/organizations/:id", orgHandler.Delete)
 	protected.GET("/organizations/:id/members", orgHandler.Members)
 
-	rtHandler := handlers.NewResourceTypeHandler(resourceTypeService, authzChecker, accountRepo, logger)
+	rtHandler := handlers.NewResourceTypeHandler(resourceTypeService, roleAuthorizer, accountRepo, logger)
 	protected.POST("/resource-types", rtHandler.Create)
 	protected.GET("/resource-types", rtHandler.List)
 	protected.GET("/resource-types/:id", rtHandler.Get)
@@ -341,6 +364,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 		Repo:        roleAccessRepo,
 		Checker:     authzChecker,
 		AccountRepo: accountRepo,
+		Assignments: roleAssignmentRepo,
 		Logger:      logger,
 	})
 	protected.GET("/settings/role-access", roleAccessHandler.Get)
@@ -380,6 +404,48 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 	acceptGroup.POST("/invites/accept", inviteHandler.Accept)
 
+	webhookHandler := handlers.NewWebhookHandler(handlers.WebhookHandlerConfig{
+		Service:     webhookService,
+		AccountRepo: accountRepo,
+		Logger:      logger,
+	})
+	protected.POST("/webhooks", webhookHandler.Create)
+	protected.GET("/webhooks", webhookHandler.List)
+	protected.GET("/webhooks/:id", webhookHandler.Get)
+	protected.PUT("/webhooks/:id", webhookHandler.Update)
+	protected.DELETE("/webhooks/:id", webhookHandler.Delete)
+	protected.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+	protected.POST("/webhooks/:id/deliveries/:deliveryId/replay", webhookHandler.Replay)
+
+	teamHandler := handlers.NewTeamHandler(teamService, logger)
+	protected.POST("/teams", teamHandler.Create)
+	protected.GET("/teams", teamHandler.List)
+	protected.GET("/teams/:id", teamHandler.Get)
+	protected.PUT("/teams/:id", teamHandler.Update)
+	protected.DELETE("/teams/:id", teamHandler.Delete)
+	protected.GET("/teams/:id/members", teamHandler.ListMembers)
+	protected.POST("/teams/:id/members", teamHandler.AddMember)
+	protected.DELETE("/teams/:id/members/:agentId", teamHandler.RemoveMember)
+
+	// Scoped role assignments
+	roleAssignmentHandler := handlers.NewRoleAssignmentHandler(roleAssignmentService, logger)
+	protected.POST("/role-assignments", roleAssignmentHandler.Create)
+	protected.GET("/role-assignments", roleAssignmentHandler.List)
+	protected.DELETE("/role-assignments/:id", roleAssignmentHandler.Delete)
+
+	authzHandler := handlers.NewAuthzHandler(authzCheckService, logger)
+	protected.POST("/authz/check", authzHandler.Check)
+
+	eventHandlerStatusHandler := handlers.NewEventHandlerStatusHandler(eventRelay, accountRepo, logger)
+	protected.GET("/admin/event-handlers", eventHandlerStatusHandler.List)
+
+	auditHandler := handlers.NewAuditHandler(auditRepo, accountRepo, logger)
+	protected.GET("/audit", auditHandler.List)
+
+	// Server-Sent Events stream of resource changes
+	eventStreamHandler := handlers.NewEventStreamHandler(eventStreamService, logger)
+	protected.GET("/events/stream", eventStreamHandler.Stream)
+
 	protected.POST("/admin/impersonate", impersonationHandler.Start)
 	protected.POST("/admin/stop-impersonation", impersonationHandler.Stop)
 	protected.GET("/admin/impersonation-status", impersonationHandler.Status)
@@ -411,7 +477,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	// MCP routes — registered before dynamic catch-all
 	if serveViper.GetBool("enabled") {
 		mcpHandler, mcpErr := mcpserver.NewHTTPHandler(
-			resourceTypeService, resourceService, slog.Default(),
+			resourceTypeService, resourceService, authzCheckService, slog.Default(),
 		)
 		if mcpErr != nil {
 			return fmt.Errorf("failed to create MCP handler: %w", mcpErr)
@@ -425,7 +491,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 		} else {
 			mcpGroup.Use(apimw.SoftAuth(credentialRepo, agentRepo, accountRepo, logger))
 		}
-		mcpGroup.Use(apimw.AuthorizeResource(authzChecker, accountRepo, logger))
+		mcpGroup.Use(apimw.AuthorizeResource(roleAuthorizer, accountRepo, logger))
 		mcpGroup.Any("/mcp", echo.WrapHandler(mcpHandler))
 		mcpGroup.Any("/mcp/*", echo.WrapHandler(mcpHandler))
 		logger.Info(context.Background(), "MCP server enabled", "path", "/api/mcp")
@@ -438,6 +504,15 @@ func runServe(cmd *cobra.Command, args []string) error {
 	protected.POST("/:typeSlug/:id/permissions", permHandler.Grant)
 	protected.GET("/:typeSlug/:id/permissions", permHandler.List)
 	protected.DELETE("/:typeSlug/:id/permissions/:agentId", permHandler.Revoke)
+	protected.DELETE("/:typeSlug/:id/permissions/teams/:teamId", permHandler.RevokeTeam)
+
+	// Editorial routes — publish/unpublish are registered before the dynamic
+	// catch-all; the public read API needs no session.
+	editorialHandler := handlers.NewEditorialHandler(editorialService, resourceTypeService)
+	protected.POST("/:typeSlug/:id/publish", editorialHandler.Publish)
+	protected.POST("/:typeSlug/:id/unpublish", editorialHandler.Unpublish)
+	api.GET("/public/:accountId/:typeSlug", editorialHandler.ListPublished)
+	api.GET("/public/:accountId/:typeSlug/:id", editorialHandler.GetPublished)
 
 	// Preset-contributed HTTP handlers. Registered before the dynamic /:typeSlug
 	// catch-all so preset routes aren't shadowed by it.
@@ -450,9 +525,27 @@ func runServe(cmd *cobra.Command, args []string) error {
 	protected.GET("/:typeSlug/:id", resourceHandler.Get)
 	protected.PUT("/:typeSlug/:id", resourceHandler.Update)
 	protected.DELETE("/:typeSlug/:id", resourceHandler.Delete)
+	protected.POST("/:typeSlug/:id/transitions/:name", resourceHandler.Transition)
 
 	addr := fmt.Sprintf("%s:%d", appCfg.Server.Host, appCfg.Server.Port)
 
+	// Run background jobs and the event relay in-process unless a separate
+	// `weos worker` does.
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	var workers sync.WaitGroup
+	if runWorker, _ := cmd.Flags().GetBool("worker"); runWorker {
+		workers.Add(2)
+		go func() {
+			defer workers.Done()
+			jobWorker.Run(workerCtx)
+		}()
+		go func() {
+			defer workers.Done()
+			eventRelay.Run(workerCtx)
+		}()
+	}
+
 	go func() {
 		fmt.Printf("Starting server on %s\n", addr)
 		if err := e.Start(addr); err != nil {
@@ -475,6 +568,8 @@ func runServe(cmd *cobra.Command, args []string) error {
 	if err := e.Shutdown(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "Server forced to shutdown: %v\n", err)
 	}
+	stopWorker()
+	workers.Wait()
 
 	stopCtx, stopCancel := context.WithTimeout(context.Background(), fx.DefaultTimeout)
 	defer stopCancel()
@@ -0,0 +1,172 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/wepala/weos/v3/infrastructure/models"
+)
+
+// subjectKeyLine is one line of a subject key file: a key that seals x-pii
+// values in exported events. The key is base64 in JSON.
+type subjectKeyLine struct {
+	ID        string    `json:"id"`
+	SubjectID string    `json:"subject_id"`
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// countSubjectKeys returns how many subject keys the database holds.
+func countSubjectKeys(db *gorm.DB) (int64, error) {
+	if !db.Migrator().HasTable(&models.SubjectKey{}) {
+		return 0, nil
+	}
+	var n int64
+	if err := db.Model(&models.SubjectKey{}).Count(&n).Error; err != nil {
+		return 0, fmt.Errorf("failed to count subject keys: %w", err)
+	}
+	return n, nil
+}
+
+// exportSubjectKeys writes every subject key to path as NDJSON, readable by
+// the owner only. It writes path.part first and renames it once complete.
+func exportSubjectKeys(ctx context.Context, db *gorm.DB, path string) (n int, err error) {
+	part := path + ".part"
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create subject key file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(part)
+		}
+	}()
+	if db.Migrator().HasTable(&models.SubjectKey{}) {
+		w := bufio.NewWriter(f)
+		enc := json.NewEncoder(w)
+		var rows []models.SubjectKey
+		err = db.WithContext(ctx).Order("id").FindInBatches(&rows, 500, func(*gorm.DB, int) error {
+			for _, r := range rows {
+				if err := enc.Encode(subjectKeyLine{
+					ID: r.ID, SubjectID: r.SubjectID, Key: r.Key, CreatedAt: r.CreatedAt,
+				}); err != nil {
+					return err
+				}
+				n++
+			}
+			return nil
+		}).Error
+		if err != nil {
+			return 0, fmt.Errorf("failed to export subject keys: %w", err)
+		}
+		if err = w.Flush(); err != nil {
+			return 0, fmt.Errorf("failed to write subject key file: %w", err)
+		}
+	}
+	if err = f.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write subject key file: %w", err)
+	}
+	if err = os.Rename(part, path); err != nil {
+		return 0, fmt.Errorf("failed to write subject key file: %w", err)
+	}
+	return n, nil
+}
+
+// importSubjectKeys stores the keys in a file written by exportSubjectKeys,
+// skipping keys the database already holds.
+func importSubjectKeys(ctx context.Context, db *gorm.DB, path string) (int, error) {
+	if err := db.AutoMigrate(&models.SubjectKey{}); err != nil {
+		return 0, fmt.Errorf("failed to migrate subject keys: %w", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open subject key file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var n int
+	var batch []models.SubjectKey
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		result := db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&batch)
+		if result.Error != nil {
+			return fmt.Errorf("failed to import subject keys: %w", result.Error)
+		}
+		n += int(result.RowsAffected)
+		batch = batch[:0]
+		return nil
+	}
+	dec := json.NewDecoder(f)
+	for {
+		var line subjectKeyLine
+		if err := dec.Decode(&line); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return n, fmt.Errorf("failed to read subject key file: %w", err)
+		}
+		if line.ID == "" || line.SubjectID == "" || len(line.Key) == 0 {
+			return n, fmt.Errorf("subject key file holds an incomplete key %q", line.ID)
+		}
+		batch = append(batch, models.SubjectKey{
+			ID: line.ID, SubjectID: line.SubjectID, Key: line.Key, CreatedAt: line.CreatedAt,
+		})
+		if len(batch) == 500 {
+			if err := flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, flush()
+}
+
+// missingSubjectKeys returns how many of ids the database holds no key for.
+func missingSubjectKeys(ctx context.Context, db *gorm.DB, ids map[string]struct{}) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if !db.Migrator().HasTable(&models.SubjectKey{}) {
+		return len(ids), nil
+	}
+	list := make([]string, 0, len(ids))
+	for id := range ids {
+		list = append(list, id)
+	}
+	var found int64
+	for start := 0; start < len(list); start += 500 {
+		var n int64
+		chunk := list[start:min(start+500, len(list))]
+		if err := db.WithContext(ctx).Model(&models.SubjectKey{}).Where("id IN ?", chunk).
+			Count(&n).Error; err != nil {
+			return 0, fmt.Errorf("failed to look up subject keys: %w", err)
+		}
+		found += n
+	}
+	return len(list) - int(found), nil
+}
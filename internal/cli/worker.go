@@ -0,0 +1,75 @@
+// Copyright (C) 2026 Wepala, LLC
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Run the background job worker and event relay",
+	Long: `Runs queued background jobs and enqueues recurring ones on their cron
+schedule, and relays committed events to the event handler groups. Start any
+number of workers against the same database; use "weos serve --worker=false"
+when the API server shouldn't do this itself.`,
+	RunE: runWorker,
+}
+
+func init() {
+	workerCmd.Flags().Int("concurrency", 4, "maximum jobs this worker runs at once")
+	workerCmd.Flags().Duration("poll-interval", 0, "how often to look for due jobs (default 1s)")
+	rootCmd.AddCommand(workerCmd)
+}
+
+func runWorker(cmd *cobra.Command, _ []string) error {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+
+	deps, err := StartContainer(GetConfig())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = deps.Shutdown() }()
+
+	deps.JobWorker.Concurrency = concurrency
+	if pollInterval > 0 {
+		deps.JobWorker.PollInterval = pollInterval
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Println("Job worker running; press Ctrl+C to stop")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		deps.EventRelay.Run(ctx)
+	}()
+	deps.JobWorker.Run(ctx)
+	wg.Wait()
+	fmt.Println("Job worker stopped")
+	return nil
+}
@@ -111,6 +111,16 @@ type Config struct {
 	BigQueryDatasetID string
 	BigQueryTableID   string
 
+	// SnapshotEvery is how many events an aggregate accumulates between
+	// snapshots of its replayed state. 0 disables snapshots.
+	// Default: 100
+	SnapshotEvery int
+
+	// FieldEncryptionKeyFile is the path of the keyfile x-encrypted
+	// properties are encrypted with. Without it, resource types with
+	// x-encrypted properties cannot be written.
+	FieldEncryptionKeyFile string
+
 	// Storage holds configuration for file storage backends.
 	Storage StorageConfig
 }
@@ -234,6 +244,7 @@ func Default() Config {
 			Host: "0.0.0.0",
 		},
 		SessionSecret: "change-me-in-production",
+		SnapshotEvery: 100,
 		LLM: LLMConfig{
 			GeminiModel: "gemini-2.0-flash",
 		},
@@ -351,6 +362,16 @@ func (c *Config) LoadFromEnvironment() {
 		c.BigQueryTableID = bqTable
 	}
 
+	if v := os.Getenv("SNAPSHOT_EVERY"); v != "" {
+		if every, err := strconv.Atoi(v); err == nil && every >= 0 {
+			c.SnapshotEvery = every
+		}
+	}
+
+	if keyFile := os.Getenv("FIELD_ENCRYPTION_KEYFILE"); keyFile != "" {
+		c.FieldEncryptionKeyFile = keyFile
+	}
+
 	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
 		c.SMTP.Host = smtpHost
 	}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/wepala/weos/v3/application"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type AuthzCheckInput struct {
+	AgentID    string `json:"agent_id" jsonschema:"agent to check; omit to check the caller"`
+	AccountID  string `json:"account_id,omitempty" jsonschema:"account to check in, for stdio sessions without an active account"`
+	Action     string `json:"action" jsonschema:"read, modify or delete"`
+	TypeSlug   string `json:"type_slug,omitempty" jsonschema:"resource type slug; may be omitted when id is set"`
+	ResourceID string `json:"id,omitempty" jsonschema:"resource ID (URN) to check one resource"`
+}
+
+type AuthzCheckOutput struct {
+	Allowed   bool                            `json:"allowed"`
+	DecidedBy string                          `json:"decided_by,omitempty"`
+	Trace     []application.AuthorizationStep `json:"trace"`
+}
+
+func registerAuthzTools(server *mcp.Server, svc application.AuthorizationCheckService) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "authz_check",
+		Description: "Explain whether an agent may read, modify or delete a resource type, or one resource when id is set, without taking the action. Returns the decision and a trace of every check made: the deciding role and Casbin policy, ownership, explicit resource grants, inherited permissions and the unconfigured-role read fallback.",
+	}, func(
+		ctx context.Context, _ *mcp.CallToolRequest, input AuthzCheckInput,
+	) (*mcp.CallToolResult, AuthzCheckOutput, error) {
+		explanation, err := svc.Check(ctx, application.CheckAuthorizationCommand{
+			AgentID:    input.AgentID,
+			AccountID:  input.AccountID,
+			Action:     input.Action,
+			TypeSlug:   input.TypeSlug,
+			ResourceID: input.ResourceID,
+		})
+		if err != nil {
+			return nil, AuthzCheckOutput{}, err
+		}
+		out := AuthzCheckOutput{
+			Allowed:   explanation.Allowed,
+			DecidedBy: explanation.DecidedBy,
+			Trace:     explanation.Trace,
+		}
+		if out.Trace == nil {
+			out.Trace = []application.AuthorizationStep{}
+		}
+		return nil, out, nil
+	})
+}
@@ -31,9 +31,10 @@ import (
 func NewHTTPHandler(
 	resourceTypeService application.ResourceTypeService,
 	resourceService application.ResourceService,
+	authzService application.AuthorizationCheckService,
 	logger *slog.Logger,
 ) (http.Handler, error) {
-	server, err := NewMCPServer(resourceTypeService, resourceService, nil)
+	server, err := NewMCPServer(resourceTypeService, resourceService, authzService, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create MCP server: %w", err)
 	}
@@ -127,6 +127,18 @@ func (s *stubResourceService) Delete(_ context.Context, _ application.DeleteReso
 	return nil
 }
 
+func (s *stubResourceService) Transition(
+	_ context.Context, _ application.TransitionResourceCommand,
+) (*entities.Resource, error) {
+	return nil, nil
+}
+
+func (s *stubResourceService) AvailableTransitions(
+	_ context.Context, _ string,
+) ([]application.AvailableTransition, error) {
+	return nil, nil
+}
+
 // toolNames connects to an MCP server via in-memory transport and returns the registered tool names.
 func toolNames(t *testing.T, server *gomcp.Server) []string {
 	t.Helper()
@@ -168,7 +180,7 @@ func toolNames(t *testing.T, server *gomcp.Server) []string {
 }
 
 func TestNewMCPServer_AllServices(t *testing.T) {
-	server, err := NewMCPServer(&stubResourceTypeService{}, &stubResourceService{}, nil)
+	server, err := NewMCPServer(&stubResourceTypeService{}, &stubResourceService{}, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -178,7 +190,7 @@ func TestNewMCPServer_AllServices(t *testing.T) {
 
 	names := toolNames(t, server)
 
-	// All 4 service groups should be registered (24 tools total).
+	// All 4 service groups should be registered (25 tools total).
 	expectedPrefixes := []string{"person_", "organization_", "resource_type_", "resource_"}
 	for _, prefix := range expectedPrefixes {
 		found := false
@@ -193,13 +205,13 @@ func TestNewMCPServer_AllServices(t *testing.T) {
 		}
 	}
 
-	if len(names) != 24 {
-		t.Errorf("expected 24 tools, got %d: %v", len(names), names)
+	if len(names) != 25 {
+		t.Errorf("expected 25 tools, got %d: %v", len(names), names)
 	}
 }
 
 func TestNewMCPServer_Subset(t *testing.T) {
-	server, err := NewMCPServer(&stubResourceTypeService{}, &stubResourceService{}, []string{"person"})
+	server, err := NewMCPServer(&stubResourceTypeService{}, &stubResourceService{}, nil, []string{"person"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -218,7 +230,7 @@ func TestNewMCPServer_Subset(t *testing.T) {
 
 func TestNewMCPServer_ResourceTypeIncludesPresets(t *testing.T) {
 	server, err := NewMCPServer(
-		&stubResourceTypeService{}, &stubResourceService{}, []string{"resource-type"},
+		&stubResourceTypeService{}, &stubResourceService{}, nil, []string{"resource-type"},
 	)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -239,21 +251,21 @@ func TestNewMCPServer_ResourceTypeIncludesPresets(t *testing.T) {
 }
 
 func TestNewMCPServer_NilResourceTypeService(t *testing.T) {
-	_, err := NewMCPServer(nil, &stubResourceService{}, nil)
+	_, err := NewMCPServer(nil, &stubResourceService{}, nil, nil)
 	if err == nil {
 		t.Fatal("expected error for nil resourceTypeService")
 	}
 }
 
 func TestNewMCPServer_NilResourceService(t *testing.T) {
-	_, err := NewMCPServer(&stubResourceTypeService{}, nil, nil)
+	_, err := NewMCPServer(&stubResourceTypeService{}, nil, nil, nil)
 	if err == nil {
 		t.Fatal("expected error for nil resourceService")
 	}
 }
 
 func TestNewHTTPHandler_ReturnsHandler(t *testing.T) {
-	handler, err := NewHTTPHandler(&stubResourceTypeService{}, &stubResourceService{}, nil)
+	handler, err := NewHTTPHandler(&stubResourceTypeService{}, &stubResourceService{}, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -263,7 +275,7 @@ func TestNewHTTPHandler_ReturnsHandler(t *testing.T) {
 }
 
 func TestNewHTTPHandler_AcceptsMCPRequest(t *testing.T) {
-	handler, err := NewHTTPHandler(&stubResourceTypeService{}, &stubResourceService{}, nil)
+	handler, err := NewHTTPHandler(&stubResourceTypeService{}, &stubResourceService{}, nil, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -290,8 +302,28 @@ func TestNewHTTPHandler_AcceptsMCPRequest(t *testing.T) {
 }
 
 func TestNewHTTPHandler_NilServices(t *testing.T) {
-	_, err := NewHTTPHandler(nil, nil, nil)
+	_, err := NewHTTPHandler(nil, nil, nil, nil)
 	if err == nil {
 		t.Fatal("expected error for nil services")
 	}
 }
+
+type stubAuthzService struct{}
+
+func (s *stubAuthzService) Check(
+	_ context.Context, _ application.CheckAuthorizationCommand,
+) (*application.AuthorizationExplanation, error) {
+	return &application.AuthorizationExplanation{}, nil
+}
+
+func TestNewMCPServer_AuthzToolsNeedService(t *testing.T) {
+	server, err := NewMCPServer(&stubResourceTypeService{}, &stubResourceService{}, &stubAuthzService{},
+		[]string{"authz"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := toolNames(t, server)
+	if len(names) != 1 || names[0] != "authz_check" {
+		t.Errorf("expected only authz_check, got: %v", names)
+	}
+}
@@ -39,6 +39,14 @@ type ListResourcesInput struct {
 	SortOrder string `json:"sort_order,omitempty" jsonschema:"sort order: asc or desc"`
 }
 
+type ResourceTransitionsInput struct {
+	ID string `json:"id" jsonschema:"resource ID (URN)"`
+}
+
+type ResourceTransitionsOutput struct {
+	Transitions []application.AvailableTransition `json:"transitions"`
+}
+
 type ResourceOutput struct {
 	ID        string    `json:"id"`
 	TypeSlug  string    `json:"type_slug"`
@@ -68,7 +76,7 @@ func toResourceOutput(e *entities.Resource) ResourceOutput {
 func registerResourceTools(server *mcp.Server, svc application.ResourceService) {
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "resource_create",
-		Description: "Create a new resource of a given type. Data is validated against the type's JSON Schema if defined.",
+		Description: "Create a new resource of a given type. Data is validated against the type's JSON Schema and its x-rules if defined; a rejected call lists every violated rule with its field so the data can be corrected and retried.",
 	}, func(
 		ctx context.Context, _ *mcp.CallToolRequest, input CreateResourceInput,
 	) (*mcp.CallToolResult, ResourceOutput, error) {
@@ -126,7 +134,7 @@ func registerResourceTools(server *mcp.Server, svc application.ResourceService)
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "resource_update",
-		Description: "Update an existing resource. Data is re-validated against the type's JSON Schema.",
+		Description: "Update an existing resource. Data is re-validated against the type's JSON Schema and x-rules; a rejected call lists every violated rule with its field.",
 	}, func(
 		ctx context.Context, _ *mcp.CallToolRequest, input UpdateResourceInput,
 	) (*mcp.CallToolResult, ResourceOutput, error) {
@@ -154,4 +162,17 @@ func registerResourceTools(server *mcp.Server, svc application.ResourceService)
 		}
 		return nil, DeletedOutput{Success: true}, nil
 	})
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "resource_transitions",
+		Description: "List the workflow transitions that can be triggered on a resource in its current state, for types that declare an x-workflow. Each entry gives the transition name and the from/to states; trigger one by calling resource_update with the workflow property set to its target state.",
+	}, func(
+		ctx context.Context, _ *mcp.CallToolRequest, input ResourceTransitionsInput,
+	) (*mcp.CallToolResult, ResourceTransitionsOutput, error) {
+		transitions, err := svc.AvailableTransitions(ctx, input.ID)
+		if err != nil {
+			return nil, ResourceTransitionsOutput{}, err
+		}
+		return nil, ResourceTransitionsOutput{Transitions: transitions}, nil
+	})
 }
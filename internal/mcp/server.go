@@ -11,6 +11,7 @@ import (
 
 	"github.com/wepala/weos/v3/application"
 	"github.com/wepala/weos/v3/application/presets"
+	"github.com/wepala/weos/v3/domain/entities"
 	"github.com/wepala/weos/v3/internal/config"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -30,6 +31,7 @@ const (
 	ServiceOrganization ServiceName = "organization"
 	ServiceResourceType ServiceName = "resource-type"
 	ServiceResource     ServiceName = "resource"
+	ServiceAuthz        ServiceName = "authz"
 )
 
 // AllServices is the ordered list of every available service.
@@ -38,6 +40,7 @@ var AllServices = []ServiceName{
 	ServiceOrganization,
 	ServiceResourceType,
 	ServiceResource,
+	ServiceAuthz,
 }
 
 // ValidServiceNames returns the service names as strings (useful for help text).
@@ -87,10 +90,12 @@ func resolveEnabled(services []string) map[ServiceName]bool {
 }
 
 // NewMCPServer creates a configured MCP server with the specified tool groups registered.
-// If enabledServices is nil or empty, all tool groups are registered.
+// If enabledServices is nil or empty, all tool groups are registered. The authz tools
+// are only registered when authzService is non-nil.
 func NewMCPServer(
 	resourceTypeService application.ResourceTypeService,
 	resourceService application.ResourceService,
+	authzService application.AuthorizationCheckService,
 	enabledServices []string,
 ) (*mcp.Server, error) {
 	if isNilInterface(resourceTypeService) {
@@ -126,6 +131,9 @@ func NewMCPServer(
 	if enabled[ServiceResource] {
 		registerResourceTools(server, resourceService)
 	}
+	if enabled[ServiceAuthz] && !isNilInterface(authzService) {
+		registerAuthzTools(server, authzService)
+	}
 
 	return server, nil
 }
@@ -137,12 +145,14 @@ func Run(enabledServices []string) error {
 
 	var resourceTypeService application.ResourceTypeService
 	var resourceService application.ResourceService
+	var authzService application.AuthorizationCheckService
 
 	app := fx.New(
 		fx.NopLogger,
 		application.Module(cfg, presets.NewDefaultRegistry()),
 		fx.Populate(&resourceTypeService),
 		fx.Populate(&resourceService),
+		fx.Populate(&authzService),
 	)
 
 	startCtx, startCancel := context.WithTimeout(context.Background(), fx.DefaultTimeout)
@@ -159,13 +169,16 @@ func Run(enabledServices []string) error {
 		}
 	}()
 
-	server, err := NewMCPServer(resourceTypeService, resourceService, enabledServices)
+	server, err := NewMCPServer(resourceTypeService, resourceService, authzService, enabledServices)
 	if err != nil {
 		return fmt.Errorf("failed to create MCP server: %w", err)
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
+	// Tool calls run on the server's context, so the events they cause are
+	// recorded as made over stdio.
+	ctx = entities.ContextWithAuthMethod(ctx, entities.AuthMethodMCPStdio)
 
 	return server.Run(ctx, &mcp.StdioTransport{})
 }
@@ -58,7 +58,7 @@ func TestValidServiceNames_ReturnsAll(t *testing.T) {
 	}
 	expected := map[string]bool{
 		"person": true, "organization": true,
-		"resource-type": true, "resource": true,
+		"resource-type": true, "resource": true, "authz": true,
 	}
 	for _, n := range names {
 		if !expected[n] {
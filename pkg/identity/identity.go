@@ -100,6 +100,36 @@ func NewResource(typeSlug string) string {
 	return "urn:" + typeSlug + ":" + ksuid.New().String()
 }
 
+// NewJob generates a background job URN.
+// Format: "urn:job:<ksuid>"
+func NewJob() string {
+	return "urn:job:" + ksuid.New().String()
+}
+
+// NewWebhook generates a webhook subscription URN.
+// Format: "urn:webhook:<ksuid>"
+func NewWebhook() string {
+	return "urn:webhook:" + ksuid.New().String()
+}
+
+// NewWebhookDelivery generates a webhook delivery URN.
+// Format: "urn:webhook-delivery:<ksuid>"
+func NewWebhookDelivery() string {
+	return "urn:webhook-delivery:" + ksuid.New().String()
+}
+
+// NewTeam generates a team URN.
+// Format: "urn:team:<ksuid>"
+func NewTeam() string {
+	return "urn:team:" + ksuid.New().String()
+}
+
+// NewRoleAssignment generates a role assignment URN.
+// Format: "urn:role-assignment:<ksuid>"
+func NewRoleAssignment() string {
+	return "urn:role-assignment:" + ksuid.New().String()
+}
+
 // ExtractThemeSlug returns the theme slug from a theme or template URN.
 // Theme URN (urn:theme:<slug>) → parts[2]
 // Template URN (urn:theme:<ts>:template:<ksuid>:<tps>) → parts[2]
@@ -127,6 +127,27 @@ func TestNewPerson_Uniqueness(t *testing.T) {
 	}
 }
 
+func TestNewJob(t *testing.T) {
+	t.Parallel()
+	id := NewJob()
+	if !strings.HasPrefix(id, "urn:job:") {
+		t.Fatalf("NewJob produced %q, want prefix %q", id, "urn:job:")
+	}
+	if id == NewJob() {
+		t.Fatalf("expected unique IDs, got %q twice", id)
+	}
+}
+
+func TestNewWebhook(t *testing.T) {
+	t.Parallel()
+	if id := NewWebhook(); !strings.HasPrefix(id, "urn:webhook:") {
+		t.Fatalf("NewWebhook produced %q, want prefix %q", id, "urn:webhook:")
+	}
+	if id := NewWebhookDelivery(); !strings.HasPrefix(id, "urn:webhook-delivery:") {
+		t.Fatalf("NewWebhookDelivery produced %q, want prefix %q", id, "urn:webhook-delivery:")
+	}
+}
+
 func TestNewOrganization(t *testing.T) {
 	t.Parallel()
 	id := NewOrganization("acme-corp")
@@ -0,0 +1,130 @@
+package e2e
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// auditEntries lists the audit log as the given dev agent.
+func (env *testEnv) auditEntries(t *testing.T, query url.Values, email string) []map[string]any {
+	t.Helper()
+	resp := env.doRequest(t, "GET", "/api/audit?"+query.Encode(), "", email)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list audit log: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	raw, _ := readJSON(t, resp)["data"].([]any)
+	entries := make([]map[string]any, 0, len(raw))
+	for _, item := range raw {
+		entry, _ := item.(map[string]any)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAudit_RecordsWhoCausedEveryEvent(t *testing.T) {
+	env := setupTestEnv(t)
+
+	req, err := http.NewRequest("POST", env.server.URL+"/api/project",
+		strings.NewReader(`{"name":"Audited","description":"d","status":"active"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Dev-Agent", "admin@weos.dev")
+	req.Header.Set("X-Request-ID", "req-audit-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("X-Request-ID") != "req-audit-1" {
+		t.Fatalf("create project: got %d with request ID %q", resp.StatusCode, resp.Header.Get("X-Request-ID"))
+	}
+	projectID, _ := readEnvelopeData(t, resp)["id"].(string)
+
+	resp = env.doRequest(t, "PUT", "/api/project/"+projectID,
+		`{"name":"Audited again","description":"d","status":"archived"}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update project: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	updateRequestID := resp.Header.Get("X-Request-ID")
+	resp.Body.Close()
+	env.seedProjectForUser(t, "Someone else's", "member@weos.dev")
+	env.runJobs(t)
+
+	entries := env.auditEntries(t, url.Values{"resource": {projectID}}, "admin@weos.dev")
+	if len(entries) != 2 || entries[0]["event_type"] != "Resource.Updated" ||
+		entries[1]["event_type"] != "Resource.Created" {
+		t.Fatalf("audit log of the project = %v, want its update then its creation", entries)
+	}
+	for _, e := range entries {
+		if e["agent_id"] != env.adminAgentID || e["account_id"] != env.adminAccountID ||
+			e["auth_method"] != "dev" || e["type_slug"] != "project" {
+			t.Errorf("audit entry = %v, want the admin acting in their account on a project", e)
+		}
+	}
+	if entries[1]["request_id"] != "req-audit-1" || entries[0]["request_id"] != updateRequestID ||
+		updateRequestID == "" {
+		t.Errorf("request IDs = %v and %v, want req-audit-1 and %q",
+			entries[1]["request_id"], entries[0]["request_id"], updateRequestID)
+	}
+
+	if others := env.auditEntries(t, url.Values{"agent": {env.memberAgentID}}, "admin@weos.dev"); len(others) != 0 {
+		t.Errorf("admin sees %d entries of another account's member", len(others))
+	}
+	for _, e := range env.auditEntries(t, url.Values{"type": {"project"}}, "member@weos.dev") {
+		if e["agent_id"] != env.memberAgentID {
+			t.Errorf("member sees entry %v from another account", e)
+		}
+	}
+
+	resp = env.doRequest(t, "GET", "/api/audit?format=csv&resource="+url.QueryEscape(projectID), "", "admin@weos.dev")
+	defer resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+		t.Fatalf("csv export content type = %q", resp.Header.Get("Content-Type"))
+	}
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "occurred_at" || rows[2][2] != "Resource.Created" {
+		t.Errorf("csv export = %v, want a header and the project's two events", rows)
+	}
+}
+
+func TestAudit_ReplacesRequestIDsOutsideTheAllowedCharacters(t *testing.T) {
+	env := setupTestEnv(t)
+	for id, kept := range map[string]bool{
+		"req-1.retry_2":                    true,
+		`=HYPERLINK("https://x.test","a")`: false,
+		"req 1":                            false,
+	} {
+		req, err := http.NewRequest("GET", env.server.URL+"/api/project", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Dev-Agent", "admin@weos.dev")
+		req.Header.Set("X-Request-ID", id)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		got := resp.Header.Get("X-Request-ID")
+		if (got == id) != kept || got == "" {
+			t.Errorf("X-Request-ID %q came back as %q, kept = %v", id, got, kept)
+		}
+	}
+}
+
+func TestAudit_AdminOnlyWithValidTimes(t *testing.T) {
+	env := setupTestEnv(t)
+
+	resp := env.doRequest(t, "GET", "/api/audit?from=yesterday", "", "admin@weos.dev")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("from=yesterday: expected 400, got %d", resp.StatusCode)
+	}
+}
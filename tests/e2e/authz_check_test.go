@@ -0,0 +1,96 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// checkAuthz posts an authorization check and returns the decision and
+// its trace.
+func (env *testEnv) checkAuthz(t *testing.T, body, email string) (bool, string, []map[string]any) {
+	t.Helper()
+	resp := env.doRequest(t, "POST", "/api/authz/check", body, email)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("authz check: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	data := readEnvelopeData(t, resp)
+	allowed, _ := data["allowed"].(bool)
+	decidedBy, _ := data["decided_by"].(string)
+	var trace []map[string]any
+	for _, step := range data["trace"].([]any) {
+		trace = append(trace, step.(map[string]any))
+	}
+	return allowed, decidedBy, trace
+}
+
+func TestAuthzCheck_ExplainsDecisions(t *testing.T) {
+	env := setupTestEnv(t)
+	projectID := env.seedProjectForUser(t, "Explained", "admin@weos.dev")
+	check := func(action string) string {
+		return fmt.Sprintf(`{"agent_id":%q,"action":%q,"resource_id":%q}`, env.memberAgentID, action, projectID)
+	}
+
+	allowed, decidedBy, _ := env.checkAuthz(t, check("read"), "admin@weos.dev")
+	if allowed || decidedBy != "role" {
+		t.Fatalf("without any role: allowed=%v decided_by=%q, want a role deny", allowed, decidedBy)
+	}
+
+	env.assignRole(t, "weos:author", "account", "")
+	allowed, decidedBy, trace := env.checkAuthz(t, check("read"), "admin@weos.dev")
+	if !allowed || decidedBy != "role-scope" {
+		t.Fatalf("author read: allowed=%v decided_by=%q, want allowed by role-scope", allowed, decidedBy)
+	}
+	if policy, _ := trace[0]["policy"].(string); !strings.HasPrefix(policy, "weos:viewer, ") {
+		t.Errorf("the role step should name the inherited viewer policy, got %q", policy)
+	}
+
+	allowed, decidedBy, _ = env.checkAuthz(t, check("modify"), "admin@weos.dev")
+	if allowed || decidedBy != "role-scope" {
+		t.Fatalf("author modifying another's project: allowed=%v decided_by=%q", allowed, decidedBy)
+	}
+
+	env.grantTo(t, "project", projectID, env.memberAgentID, "modify")
+	allowed, decidedBy, _ = env.checkAuthz(t, check("modify"), "admin@weos.dev")
+	if !allowed || decidedBy != "resource-permission" {
+		t.Fatalf("granted modify: allowed=%v decided_by=%q, want allowed by resource-permission", allowed, decidedBy)
+	}
+
+	allowed, decidedBy, _ = env.checkAuthz(t,
+		fmt.Sprintf(`{"action":"delete","resource_id":%q}`, projectID), "admin@weos.dev")
+	if !allowed || decidedBy != "account-admin" {
+		t.Fatalf("admin checking themselves: allowed=%v decided_by=%q", allowed, decidedBy)
+	}
+}
+
+func TestAuthzCheck_Errors(t *testing.T) {
+	env := setupTestEnv(t)
+	projectID := env.seedProjectForUser(t, "Private", "admin@weos.dev")
+	if err := env.accounts.SaveMember(context.Background(), env.memberAccountID, env.memberAgentID, "member"); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name, body, email string
+		want              int
+	}{
+		{"members cannot check other agents",
+			fmt.Sprintf(`{"agent_id":%q,"action":"read","type_slug":"project"}`, env.adminAgentID),
+			"member@weos.dev", http.StatusForbidden},
+		{"unknown action", `{"action":"publish","type_slug":"project"}`, "admin@weos.dev", http.StatusBadRequest},
+		{"type or resource required", `{"action":"read"}`, "admin@weos.dev", http.StatusBadRequest},
+		{"resource of another account",
+			fmt.Sprintf(`{"action":"read","resource_id":%q}`, projectID), "member@weos.dev", http.StatusNotFound},
+		{"members may check themselves", `{"action":"read","type_slug":"project"}`, "member@weos.dev",
+			http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := env.status(t, "POST", "/api/authz/check", tc.body, tc.email); code != tc.want {
+				t.Errorf("expected %d, got %d", tc.want, code)
+			}
+		})
+	}
+}
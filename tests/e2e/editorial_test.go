@@ -0,0 +1,136 @@
+package e2e
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func installWebsite(t *testing.T, env *testEnv) {
+	t.Helper()
+	if _, err := env.typeService.InstallPreset(context.Background(), "website", true); err != nil {
+		t.Fatalf("failed to install website preset: %v", err)
+	}
+}
+
+// publishedHeadline fetches an article of the admin's account through the
+// public API and returns its headline, or "" with the status code when it
+// isn't published.
+func publishedHeadline(t *testing.T, env *testEnv, id string) (string, int) {
+	t.Helper()
+	resp := env.doRequest(t, "GET", "/api/public/"+env.adminAccountID+"/article/"+id, "", "")
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return "", resp.StatusCode
+	}
+	data, _ := readEnvelopeData(t, resp)["data"].(map[string]any)
+	headline, _ := data["headline"].(string)
+	return headline, http.StatusOK
+}
+
+func TestEditorial_PublishedRevisionSeparateFromDraft(t *testing.T) {
+	env := setupTestEnv(t)
+	installWebsite(t, env)
+	id := env.createFor(t, "article", `{"headline":"First draft"}`, "admin@weos.dev")
+
+	if _, status := publishedHeadline(t, env, id); status != http.StatusNotFound {
+		t.Fatalf("draft article: public GET returned %d, want 404", status)
+	}
+
+	resp := env.doRequest(t, "POST", "/api/article/"+id+"/publish", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("publish: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+
+	resp = env.doRequest(t, "PUT", "/api/article/"+id, `{"headline":"Second draft"}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("edit: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+
+	if got, status := publishedHeadline(t, env, id); got != "First draft" {
+		t.Fatalf("after editing the draft: public headline = %q (%d), want First draft", got, status)
+	}
+
+	resp = env.doRequest(t, "GET", "/api/public/"+env.adminAccountID+"/article", "", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("public list: expected 200, got %d", resp.StatusCode)
+	}
+	if items, _ := readJSON(t, resp)["data"].([]any); len(items) != 1 {
+		t.Errorf("public list has %d items, want 1", len(items))
+	}
+
+	// Another account's site doesn't serve it.
+	resp = env.doRequest(t, "GET", "/api/public/"+env.memberAccountID+"/article", "", "")
+	if items, _ := readJSON(t, resp)["data"].([]any); len(items) != 0 {
+		t.Errorf("another account's public list has %d items, want 0", len(items))
+	}
+	resp = env.doRequest(t, "GET", "/api/public/"+env.memberAccountID+"/article/"+id, "", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("another account's public GET returned %d, want 404", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	// Publishing again promotes the current draft.
+	resp = env.doRequest(t, "POST", "/api/article/"+id+"/publish", "", "admin@weos.dev")
+	resp.Body.Close()
+	if got, _ := publishedHeadline(t, env, id); got != "Second draft" {
+		t.Fatalf("after republishing: public headline = %q, want Second draft", got)
+	}
+
+	resp = env.doRequest(t, "POST", "/api/article/"+id+"/unpublish", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unpublish: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	if _, status := publishedHeadline(t, env, id); status != http.StatusNotFound {
+		t.Fatalf("unpublished article: public GET returned %d, want 404", status)
+	}
+}
+
+func TestEditorial_ScheduledPublishing(t *testing.T) {
+	env := setupTestEnv(t)
+	installWebsite(t, env)
+	publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	id := env.createFor(t, "article",
+		`{"headline":"Launch","datePublished":"`+publishAt+`"}`, "admin@weos.dev")
+
+	resp := env.doRequest(t, "POST", "/api/article/"+id+"/publish", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("publish: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	data, _ := readEnvelopeData(t, resp)["data"].(map[string]any)
+	graph, _ := data["@graph"].([]any)
+	if node, _ := graph[0].(map[string]any); node["creativeWorkStatus"] != "scheduled" {
+		t.Fatalf("future datePublished: state = %v, want scheduled", node["creativeWorkStatus"])
+	}
+	if _, status := publishedHeadline(t, env, id); status != http.StatusNotFound {
+		t.Fatalf("scheduled article: public GET returned %d, want 404", status)
+	}
+
+	n, err := env.editorial.PublishDue(context.Background(), time.Now())
+	if err != nil || n != 0 {
+		t.Fatalf("PublishDue before the publish time = %d, %v; want 0", n, err)
+	}
+	n, err = env.editorial.PublishDue(context.Background(), time.Now().Add(2*time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("PublishDue after the publish time = %d, %v; want 1", n, err)
+	}
+	if got, status := publishedHeadline(t, env, id); got != "Launch" {
+		t.Fatalf("after PublishDue: public headline = %q (%d), want Launch", got, status)
+	}
+}
+
+func TestEditorial_PublishRequiresEditorialType(t *testing.T) {
+	env := setupTestEnv(t)
+	installTicketWorkflow(t, env)
+	id := env.createFor(t, "ticket", `{"title":"Fix login"}`, "admin@weos.dev")
+
+	resp := env.doRequest(t, "POST", "/api/ticket/"+id+"/publish", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("publish a ticket: expected 400, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,197 @@
+package e2e
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akeemphilbert/pericarp/pkg/auth"
+	"github.com/wepala/weos/v3/application"
+	"github.com/wepala/weos/v3/internal/config"
+)
+
+// withFieldKey writes a keyfile holding one fresh key and points the config
+// at it.
+func withFieldKey(t *testing.T) func(*config.Config) {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "field.keys")
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return func(cfg *config.Config) { cfg.FieldEncryptionKeyFile = path }
+}
+
+// installEmployee creates an "employee" type whose ssn is encrypted.
+func installEmployee(t *testing.T, env *testEnv) {
+	t.Helper()
+	if _, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Employee", Slug: "employee",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},
+			"ssn":{"type":"string","x-encrypted":true}
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create employee type: %v", err)
+	}
+}
+
+func TestEncrypted_StoredAsCiphertext(t *testing.T) {
+	env := setupTestEnv(t, withFieldKey(t))
+	installEmployee(t, env)
+	id := env.createFor(t, "employee", `{"name":"Grace","ssn":"078-05-1120"}`, "admin@weos.dev")
+
+	var stored []string
+	for _, q := range []struct{ table, column, key string }{
+		{"events", "payload", "aggregate_id"},
+		{"resources", "data", "id"},
+		{"employees", "ssn", "id"},
+	} {
+		var values []string
+		if err := env.db.Table(q.table).Where(q.key+" = ?", id).Pluck(q.column, &values).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(values) == 0 {
+			t.Fatalf("no %s rows for %s", q.table, id)
+		}
+		stored = append(stored, values...)
+	}
+	for _, v := range stored {
+		if strings.Contains(v, "078-05-1120") {
+			t.Fatalf("the ssn is stored in the clear: %s", v)
+		}
+	}
+
+	resp := env.doRequest(t, "GET", "/api/employee/"+id, "", "admin@weos.dev")
+	if got := readEnvelopeData(t, resp); got["ssn"] != "078-05-1120" {
+		t.Errorf("admin read ssn = %v", got["ssn"])
+	}
+
+	// An update that leaves the ssn alone keeps it.
+	resp = env.doRequest(t, "PUT", "/api/employee/"+id, `{"name":"Grace Hopper","ssn":"078-05-1120"}`,
+		"admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	resp = env.doRequest(t, "GET", "/api/employee", "", "admin@weos.dev")
+	items, _ := readJSON(t, resp)["data"].([]any)
+	if len(items) != 1 || items[0].(map[string]any)["ssn"] != "078-05-1120" {
+		t.Errorf("admin list = %v", items)
+	}
+}
+
+func TestEncrypted_HiddenFromOtherRoles(t *testing.T) {
+	env := setupTestEnv(t, withFieldKey(t))
+	installEmployee(t, env)
+	id := env.createFor(t, "employee", `{"name":"Grace","ssn":"078-05-1120"}`, "admin@weos.dev")
+
+	// The member may read the resource, but holds no role in its account.
+	grant := fmt.Sprintf(`{"agent_id":%q,"actions":["read","modify"]}`, env.memberAgentID)
+	resp := env.doRequest(t, "POST", "/api/employee/"+id+"/permissions", grant, "admin@weos.dev")
+	if resp.StatusCode >= 300 {
+		t.Fatalf("grant: got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+
+	resp = env.doRequest(t, "GET", "/api/employee/"+id, "", "member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("member get: expected 200, got %d", resp.StatusCode)
+	}
+	got := readEnvelopeData(t, resp)
+	if _, ok := got["ssn"]; ok || got["name"] != "Grace" {
+		t.Errorf("member read = %v, want the name without the ssn", got)
+	}
+
+	// The member's update cannot see the ssn, so it is kept.
+	resp = env.doRequest(t, "PUT", "/api/employee/"+id, `{"name":"Grace Hopper"}`, "member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("member update: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	resp = env.doRequest(t, "GET", "/api/employee/"+id, "", "admin@weos.dev")
+	if got := readEnvelopeData(t, resp); got["ssn"] != "078-05-1120" || got["name"] != "Grace Hopper" {
+		t.Errorf("after the member's update, admin read = %v", got)
+	}
+}
+
+func TestEncrypted_GuardsSeeHiddenFields(t *testing.T) {
+	env := setupTestEnv(t, withFieldKey(t))
+	if _, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Applicant", Slug: "applicant",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},
+			"ssn":{"type":"string","x-encrypted":true},
+			"status":{"type":"string"}
+		},"x-workflow":{
+			"states":["pending","verified"],
+			"transitions":[{"name":"verify","from":["pending"],"to":"verified","guard":"ssn == '078-05-1120'"}]
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create applicant type: %v", err)
+	}
+	id := env.createFor(t, "applicant", `{"name":"Grace","ssn":"078-05-1120"}`, "admin@weos.dev")
+	grant := fmt.Sprintf(`{"agent_id":%q,"actions":["read","modify"]}`, env.memberAgentID)
+	resp := env.doRequest(t, "POST", "/api/applicant/"+id+"/permissions", grant, "admin@weos.dev")
+	if resp.StatusCode >= 300 {
+		t.Fatalf("grant: got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+
+	// The member can't read the ssn, but the guard is decided on the stored
+	// value, the same as when the member triggers the transition.
+	member := auth.ContextWithAgent(context.Background(), &auth.Identity{
+		AgentID:         env.memberAgentID,
+		AccountIDs:      []string{env.memberAccountID},
+		ActiveAccountID: env.memberAccountID,
+	})
+	transitions, err := env.resourceService.AvailableTransitions(member, id)
+	if err != nil {
+		t.Fatalf("AvailableTransitions: %v", err)
+	}
+	if len(transitions) != 1 || transitions[0].Name != "verify" {
+		t.Errorf("available transitions = %+v, want [verify]", transitions)
+	}
+	resp = env.doRequest(t, "POST", "/api/applicant/"+id+"/transitions/verify", "", "member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("verify: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+}
+
+func TestEncrypted_NotFilterableOrSortable(t *testing.T) {
+	env := setupTestEnv(t, withFieldKey(t))
+	installEmployee(t, env)
+	env.createFor(t, "employee", `{"name":"Grace","ssn":"078-05-1120"}`, "admin@weos.dev")
+
+	for _, query := range []string{"_filter[ssn][eq]=078-05-1120", "sort_by=ssn"} {
+		resp := env.doRequest(t, "GET", "/api/employee?"+query, "", "admin@weos.dev")
+		result := readJSON(t, resp)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+		if msg := fmt.Sprint(result["error"]); !strings.Contains(msg, "encrypted") {
+			t.Errorf("%s: error = %q", query, msg)
+		}
+	}
+}
+
+func TestEncrypted_RequiresKey(t *testing.T) {
+	env := setupTestEnv(t)
+	installEmployee(t, env)
+	resp := env.doRequest(t, "POST", "/api/employee", `{"name":"Grace","ssn":"078-05-1120"}`, "admin@weos.dev")
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusCreated {
+		t.Fatal("an encrypted value was stored without a key")
+	}
+}
@@ -0,0 +1,62 @@
+package e2e
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEventHandlers_InlineGroupsKeepUpAndAsyncGroupsAreRelayed(t *testing.T) {
+	env := setupTestEnv(t)
+	env.seedProjectForUser(t, "Launch", "admin@weos.dev")
+
+	status := func() map[string]map[string]any {
+		t.Helper()
+		resp := env.doRequest(t, "GET", "/api/admin/event-handlers", "", "admin@weos.dev")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("event handler status: expected 200, got %d", resp.StatusCode)
+		}
+		groups := map[string]map[string]any{}
+		list, _ := readJSON(t, resp)["data"].([]any)
+		for _, item := range list {
+			g, _ := item.(map[string]any)
+			name, _ := g["group"].(string)
+			groups[name] = g
+		}
+		return groups
+	}
+
+	// Inline groups' checkpoints are advanced in batches, shortly after the
+	// events are handled.
+	inline := []string{"projections.resources", "projections.resource-types", "projections.triples"}
+	caughtUp := func(groups map[string]map[string]any) bool {
+		for _, name := range inline {
+			if groups[name]["lag"] != float64(0) {
+				return false
+			}
+		}
+		return true
+	}
+	groups := status()
+	for deadline := time.Now().Add(5 * time.Second); !caughtUp(groups) && time.Now().Before(deadline); {
+		time.Sleep(50 * time.Millisecond)
+		groups = status()
+	}
+	for _, name := range inline {
+		g, ok := groups[name]
+		if !ok {
+			t.Fatalf("group %s is missing from %v", name, groups)
+		}
+		if g["lag"] != float64(0) || g["async"] != false {
+			t.Errorf("inline group %s = %v, want no lag: it is checkpointed as it handles events", name, g)
+		}
+	}
+	if hooks := groups["webhooks"]; hooks["async"] != true || hooks["lag"] == float64(0) {
+		t.Errorf("webhooks = %v, want an async group behind the head until the relay runs", hooks)
+	}
+
+	env.runJobs(t)
+	if hooks := status()["webhooks"]; hooks["lag"] != float64(0) {
+		t.Errorf("webhooks after a relay tick = %v, want caught up", hooks)
+	}
+}
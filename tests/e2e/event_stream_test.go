@@ -0,0 +1,198 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseFrame is one event, or heartbeat comment, read from an event stream.
+type sseFrame struct {
+	id, event, comment string
+	data               map[string]any
+}
+
+// openStream connects to the event stream as devAgent and returns its frames.
+// The stream closes when the test ends.
+func (env *testEnv) openStream(t *testing.T, query, lastEventID, devAgent string) <-chan sseFrame {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, "GET", env.server.URL+"/api/events/stream?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Dev-Agent", devAgent)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		resp.Body.Close()
+		t.Fatalf("open stream: got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	frames := make(chan sseFrame)
+	go func() {
+		defer close(frames)
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		var f sseFrame
+		for scanner.Scan() {
+			line := scanner.Text()
+			field, value, _ := strings.Cut(line, ": ")
+			switch field {
+			case "":
+				if line == "" && (f.event != "" || f.comment != "") {
+					select {
+					case frames <- f:
+					case <-ctx.Done():
+						return
+					}
+				}
+				f = sseFrame{}
+				if strings.HasPrefix(line, ":") {
+					f.comment = strings.TrimPrefix(line, ": ")
+				}
+			case "id":
+				f.id = value
+			case "event":
+				f.event = value
+			case "data":
+				_ = json.Unmarshal([]byte(value), &f.data)
+			}
+		}
+	}()
+	return frames
+}
+
+// nextEvent returns the next event frame, skipping heartbeats.
+func nextEvent(t *testing.T, frames <-chan sseFrame) sseFrame {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case f, ok := <-frames:
+			if !ok {
+				t.Fatal("stream closed")
+			}
+			if f.event != "" {
+				return f
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for a stream event")
+		}
+	}
+}
+
+func TestEventStream_OnlyStreamsReadableResources(t *testing.T) {
+	env := setupTestEnv(t)
+	hidden := env.seedProjectForUser(t, "Admin only", "admin@weos.dev")
+	visible := env.seedProjectForUser(t, "Mine", "member@weos.dev")
+
+	// Replay the log from the start: the member sees their own project but
+	// none of the admin's.
+	frames := env.openStream(t, "type=project", "0", "member@weos.dev")
+	var created sseFrame
+	for created.id == "" {
+		f := nextEvent(t, frames)
+		switch f.data["resourceId"] {
+		case hidden:
+			t.Fatalf("member was streamed %s on another account's project", f.event)
+		case visible:
+			if f.event == "Resource.Created" {
+				created = f
+			}
+		}
+		if f.event == "Resource.Published" {
+			t.Errorf("internal event %s was streamed", f.event)
+		}
+		if slug := f.data["typeSlug"]; slug != "project" {
+			t.Errorf("type=project streamed a %v event", slug)
+		}
+	}
+
+	// Reconnecting with Last-Event-ID resumes after the last event seen.
+	resumed := env.openStream(t, "resource="+visible, created.id, "member@weos.dev")
+	resp := env.doRequest(t, "PUT", "/api/project/"+visible,
+		`{"name":"Mine, renamed","description":"test project","status":"active"}`, "member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update project: expected 200, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+	next := nextEvent(t, resumed)
+	after, _ := strconv.Atoi(created.id)
+	got, _ := strconv.Atoi(next.id)
+	if next.event != "Resource.Updated" || next.data["resourceId"] != visible || got <= after {
+		t.Errorf("resumed stream sent %s %v at %s, want the update after %s",
+			next.event, next.data["resourceId"], next.id, created.id)
+	}
+}
+
+func TestEventStream_SendsHeartbeats(t *testing.T) {
+	env := setupTestEnv(t)
+	frames := env.openStream(t, "resource=urn:project:none", "", "member@weos.dev")
+	select {
+	case f := <-frames:
+		if f.comment != "heartbeat" {
+			t.Errorf("first frame = %+v, want a heartbeat", f)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no heartbeat")
+	}
+
+	resp := env.doRequest(t, "GET", "/api/events/stream?lastEventId=soon", "", "member@weos.dev")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad Last-Event-ID: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestEventStream_ShowsResourceDataAsReadsDo(t *testing.T) {
+	env, id := setupStaffFieldRules(t)
+
+	// The member may read name and email of staff records, not the salary.
+	frames := env.openStream(t, "resource="+id, "0", "member@weos.dev")
+	created := nextEvent(t, frames)
+	payload, _ := json.Marshal(created.data["payload"])
+	if created.event != "Resource.Created" || !strings.Contains(string(payload), "ada@example.com") ||
+		strings.Contains(string(payload), "90000") {
+		t.Errorf("member was streamed %s %s, want the record without its salary", created.event, payload)
+	}
+	frames = env.openStream(t, "resource="+id, "0", "admin@weos.dev")
+	if payload, _ := json.Marshal(nextEvent(t, frames).data["payload"]); !strings.Contains(string(payload), "90000") {
+		t.Errorf("admin was streamed %s, want the salary", payload)
+	}
+}
+
+func TestEventStream_LeavesOutPersonalData(t *testing.T) {
+	env := setupTestEnv(t)
+	installPatient(t, env)
+	clinic := env.createFor(t, "clinic", `{"name":"Northside"}`, "admin@weos.dev")
+	id := env.createFor(t, "patient",
+		fmt.Sprintf(`{"name":"Ada Lovelace","ward":"B","clinic":%q}`, clinic), "admin@weos.dev")
+	env.doRequest(t, "PUT", "/api/patient/"+id,
+		fmt.Sprintf(`{"name":"Ada Lovelace","ward":"C","clinic":%q}`, clinic), "admin@weos.dev").Body.Close()
+
+	frames := env.openStream(t, "resource="+id, "0", "admin@weos.dev")
+	for seen := map[string]bool{}; !seen["Resource.Updated"]; {
+		f := nextEvent(t, frames)
+		seen[f.event] = true
+		payload, _ := json.Marshal(f.data["payload"])
+		if strings.Contains(string(payload), "Ada Lovelace") || strings.Contains(string(payload), clinic) {
+			t.Errorf("%s streamed personal data: %s", f.event, payload)
+		}
+		if f.event == "Resource.Updated" && !strings.Contains(string(payload), `"ward":"C"`) {
+			t.Errorf("update streamed %s, want the ward", payload)
+		}
+	}
+}
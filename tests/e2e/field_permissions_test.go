@@ -0,0 +1,137 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+// setupStaffFieldRules creates a "staff" type, limits the "member" role of the
+// member user's account to reading name and email and writing name, makes the
+// member user a plain member of that account, and shares one admin-owned staff
+// record with them.
+func setupStaffFieldRules(t *testing.T) (*testEnv, string) {
+	t.Helper()
+	env := setupTestEnv(t)
+	ctx := context.Background()
+	if _, err := env.typeService.Create(ctx, application.CreateResourceTypeCommand{
+		Name: "Staff", Slug: "staff",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},"email":{"type":"string"},"salary":{"type":"string"}
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create staff type: %v", err)
+	}
+
+	resp := env.doRequest(t, "PUT", "/api/settings/role-access", `{"roles":{},"fields":{
+		"member":{"staff":{"read":["name","email"],"write":["name"]}}}}`, "member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("save role access: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	if err := env.accounts.SaveMember(ctx, env.memberAccountID, env.memberAgentID, "member"); err != nil {
+		t.Fatal(err)
+	}
+
+	id := env.createFor(t, "staff", `{"name":"Ada","email":"ada@example.com","salary":"90000"}`, "admin@weos.dev")
+	grant := fmt.Sprintf(`{"agent_id":%q,"actions":["read","modify"]}`, env.memberAgentID)
+	resp = env.doRequest(t, "POST", "/api/staff/"+id+"/permissions", grant, "admin@weos.dev")
+	if resp.StatusCode >= 300 {
+		t.Fatalf("grant: got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	return env, id
+}
+
+func TestFieldPermissions_RoleAccessSettings(t *testing.T) {
+	env, _ := setupStaffFieldRules(t)
+
+	resp := env.doRequest(t, "PUT", "/api/settings/role-access", `{"roles":{},"fields":{
+		"member":{"staff":{"read":["name","email"],"write":["name"]}}}}`, "admin@weos.dev")
+	resp.Body.Close()
+	// Saving the role map alone keeps the field rules.
+	resp = env.doRequest(t, "PUT", "/api/settings/role-access", `{"roles":{"member":{"staff":["read"]}}}`,
+		"admin@weos.dev")
+	resp.Body.Close()
+	resp = env.doRequest(t, "GET", "/api/settings/role-access", "", "admin@weos.dev")
+	got := readEnvelopeData(t, resp)
+	fields, _ := got["fields"].(map[string]any)
+	staff, _ := fields["member"].(map[string]any)["staff"].(map[string]any)
+	if fmt.Sprint(staff["read"]) != "[name email]" || fmt.Sprint(staff["write"]) != "[name]" {
+		t.Errorf("fields = %v", got["fields"])
+	}
+}
+
+func TestFieldPermissions_ReadsLeaveOutUnreadableProperties(t *testing.T) {
+	env, id := setupStaffFieldRules(t)
+
+	resp := env.doRequest(t, "GET", "/api/staff/"+id, "", "member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("member get: expected 200, got %d", resp.StatusCode)
+	}
+	got := readEnvelopeData(t, resp)
+	if _, ok := got["salary"]; ok || got["name"] != "Ada" || got["email"] != "ada@example.com" {
+		t.Errorf("member get = %v, want name and email without salary", got)
+	}
+
+	resp = env.doRequest(t, "GET", "/api/staff", "", "member@weos.dev")
+	items, _ := readJSON(t, resp)["data"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("member list = %v", items)
+	}
+	if _, ok := items[0].(map[string]any)["salary"]; ok {
+		t.Errorf("member list includes the salary: %v", items[0])
+	}
+
+	for _, query := range []string{"_filter[salary][eq]=90000", "sort_by=salary"} {
+		resp := env.doRequest(t, "GET", "/api/staff?"+query, "", "member@weos.dev")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+
+	resp = env.doRequest(t, "GET", "/api/staff/"+id, "", "admin@weos.dev")
+	if got := readEnvelopeData(t, resp); got["salary"] != "90000" {
+		t.Errorf("admin get salary = %v", got["salary"])
+	}
+}
+
+func TestFieldPermissions_WritesRejectForbiddenProperties(t *testing.T) {
+	env, id := setupStaffFieldRules(t)
+
+	// Unchanged values of read-only properties may be sent back, and the
+	// unreadable salary is kept.
+	resp := env.doRequest(t, "PUT", "/api/staff/"+id, `{"name":"Ada L","email":"ada@example.com"}`,
+		"member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("member update: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	resp = env.doRequest(t, "GET", "/api/staff/"+id, "", "admin@weos.dev")
+	if got := readEnvelopeData(t, resp); got["name"] != "Ada L" || got["salary"] != "90000" {
+		t.Errorf("after the member's update, admin get = %v", got)
+	}
+
+	for _, body := range []string{
+		`{"name":"Ada L","email":"other@example.com"}`,
+		`{"name":"Ada L","email":"ada@example.com","salary":"1"}`,
+	} {
+		resp := env.doRequest(t, "PUT", "/api/staff/"+id, body, "member@weos.dev")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("member update %s: expected 403, got %d", body, resp.StatusCode)
+		}
+	}
+
+	resp = env.doRequest(t, "POST", "/api/staff", `{"name":"Grace","salary":"1"}`, "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("member create with a salary: expected 403, got %d", resp.StatusCode)
+	}
+	env.createFor(t, "staff", `{"name":"Grace"}`, "member@weos.dev")
+}
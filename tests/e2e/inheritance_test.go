@@ -0,0 +1,114 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+// installNotes creates area, folder and note types, where a note inherits
+// the permissions of its folder and a folder those of its area.
+func installNotes(t *testing.T, env *testEnv) {
+	t.Helper()
+	ctx := context.Background()
+	for _, cmd := range []application.CreateResourceTypeCommand{
+		{Name: "Area", Slug: "area", Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"}}}`)},
+		{Name: "Folder", Slug: "folder", Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},
+			"area":{"type":"string","x-resource-type":"area"}
+		},"x-inherit-permissions-from":"area"}`)},
+		{Name: "Note", Slug: "note", Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},
+			"folder":{"type":"string","x-resource-type":"folder"}
+		},"x-inherit-permissions-from":["folder"]}`)},
+	} {
+		if _, err := env.typeService.Create(ctx, cmd); err != nil {
+			t.Fatalf("failed to create %s type: %v", cmd.Slug, err)
+		}
+	}
+}
+
+func (env *testEnv) grantTo(t *testing.T, typeSlug, id, agentID string, actions ...string) {
+	t.Helper()
+	actionsJSON, _ := json.Marshal(actions)
+	body := fmt.Sprintf(`{"agent_id":%q,"actions":%s}`, agentID, actionsJSON)
+	resp := env.doRequest(t, "POST", "/api/"+typeSlug+"/"+id+"/permissions", body, "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("grant: expected 201, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+}
+
+func TestInheritance_ReadFollowsTheReferenceChain(t *testing.T) {
+	env := setupTestEnv(t)
+	installNotes(t, env)
+	areaID := env.createFor(t, "area", `{"name":"Research"}`, "admin@weos.dev")
+	folderID := env.createFor(t, "folder", fmt.Sprintf(`{"name":"Drafts","area":%q}`, areaID), "admin@weos.dev")
+	noteID := env.createFor(t, "note", fmt.Sprintf(`{"name":"Idea","folder":%q}`, folderID), "admin@weos.dev")
+	loose := env.createFor(t, "note", `{"name":"Loose"}`, "admin@weos.dev")
+
+	resp := env.doRequest(t, "GET", "/api/note/"+noteID, "", "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Fatal("member read the note before its area was shared")
+	}
+
+	env.grantTo(t, "area", areaID, env.memberAgentID, "read")
+
+	resp = env.doRequest(t, "GET", "/api/note/"+noteID, "", "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get note through its area: expected 200, got %d", resp.StatusCode)
+	}
+	listed := listedIDs(t, env.doRequest(t, "GET", "/api/note", "", "member@weos.dev"))
+	if !listed[noteID] || listed[loose] {
+		t.Errorf("member note list = %v, want only %s", listed, noteID)
+	}
+	if !listedIDs(t, env.doRequest(t, "GET", "/api/folder", "", "member@weos.dev"))[folderID] {
+		t.Error("member folder list does not include the folder in the shared area")
+	}
+
+	// A read grant on the area does not let the member change the note.
+	resp = env.doRequest(t, "PUT", "/api/note/"+noteID,
+		fmt.Sprintf(`{"name":"Changed","folder":%q}`, folderID), "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Error("member modified the note with only inherited read access")
+	}
+}
+
+func TestInheritance_TasksFollowTheirProject(t *testing.T) {
+	env := setupTestEnv(t)
+	projectID := env.seedProjectForUser(t, "Launch", "admin@weos.dev")
+	taskID := env.seedTaskForUser(t, "Write copy", projectID, "admin@weos.dev")
+
+	env.grantTo(t, "project", projectID, env.memberAgentID, "read", "modify")
+
+	resp := env.doRequest(t, "PUT", "/api/task/"+taskID,
+		fmt.Sprintf(`{"name":"Write better copy","status":"open","priority":"high","project":%q}`, projectID),
+		"member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update task through its project: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	if !listedIDs(t, env.doRequest(t, "GET", "/api/task", "", "member@weos.dev"))[taskID] {
+		t.Error("member task list does not include the task of the shared project")
+	}
+}
+
+func TestInheritance_RejectsNonReferenceProperties(t *testing.T) {
+	env := setupTestEnv(t)
+	_, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Memo", Slug: "memo",
+		Schema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}},
+			"x-inherit-permissions-from":"name"}`),
+	})
+	if err == nil {
+		t.Error("created a type inheriting permissions through a plain property")
+	}
+}
@@ -0,0 +1,125 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+// installPatient creates a "clinic" type and a "patient" type whose name
+// and clinic reference are personal data.
+func installPatient(t *testing.T, env *testEnv) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := env.typeService.Create(ctx, application.CreateResourceTypeCommand{
+		Name: "Clinic", Slug: "clinic",
+		Schema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`),
+	}); err != nil {
+		t.Fatalf("failed to create clinic type: %v", err)
+	}
+	if _, err := env.typeService.Create(ctx, application.CreateResourceTypeCommand{
+		Name: "Patient", Slug: "patient",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string","x-pii":true},
+			"ward":{"type":"string"},
+			"clinic":{"type":"string","x-resource-type":"clinic","x-pii":true}
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create patient type: %v", err)
+	}
+}
+
+func (env *testEnv) patient(t *testing.T, id string) map[string]any {
+	t.Helper()
+	resp := env.doRequest(t, "GET", "/api/patient/"+id, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get patient: expected 200, got %d", resp.StatusCode)
+	}
+	return readEnvelopeData(t, resp)
+}
+
+func (env *testEnv) tripleCount(t *testing.T, subject string) int64 {
+	t.Helper()
+	var n int64
+	if err := env.db.Table("triples").Where("subject = ?", subject).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPII_SealedInEventsAndErased(t *testing.T) {
+	env := setupTestEnv(t)
+	installPatient(t, env)
+	clinic := env.createFor(t, "clinic", `{"name":"Northside"}`, "admin@weos.dev")
+	id := env.createFor(t, "patient",
+		fmt.Sprintf(`{"name":"Ada Lovelace","ward":"B","clinic":%q}`, clinic), "admin@weos.dev")
+
+	var payloads []string
+	if err := env.db.Table("events").Where("aggregate_id = ?", id).Pluck("payload", &payloads).Error; err != nil {
+		t.Fatal(err)
+	}
+	stored := strings.Join(payloads, "\n")
+	if strings.Contains(stored, "Ada Lovelace") || strings.Contains(stored, clinic) {
+		t.Fatalf("stored events hold personal data in the clear:\n%s", stored)
+	}
+	if !strings.Contains(stored, `"B"`) {
+		t.Errorf("stored events lost the plain ward value:\n%s", stored)
+	}
+
+	if got := env.patient(t, id); got["name"] != "Ada Lovelace" {
+		t.Fatalf("before erasure, name = %v", got["name"])
+	}
+	if n := env.tripleCount(t, id); n == 0 {
+		t.Fatal("before erasure, the clinic reference has no triple")
+	}
+
+	result, err := env.eraser.Erase(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Erase: %v", err)
+	}
+	if result.KeysDestroyed != 1 || !result.Repaired {
+		t.Errorf("result = %+v, want one key destroyed and the read models repaired", result)
+	}
+
+	got := env.patient(t, id)
+	if got["name"] != nil || got["ward"] != "B" {
+		t.Errorf("after erasure, name = %v and ward = %v, want null and B", got["name"], got["ward"])
+	}
+	if n := env.tripleCount(t, id); n != 0 {
+		t.Errorf("after erasure, %d triples remain for the patient", n)
+	}
+
+	// Replay keeps working: the read models match the redacted events, and
+	// survive a rebuild from scratch.
+	if _, drifts := env.verify(t, false); len(drifts) != 0 {
+		t.Fatalf("after erasure, drift = %+v", drifts)
+	}
+	if _, err := env.projections.Rebuild(context.Background(), application.RebuildOptions{
+		Resources: true, Triples: true, Restart: true,
+	}); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if got := env.patient(t, id); got["name"] != nil || got["ward"] != "B" {
+		t.Errorf("after rebuild, name = %v and ward = %v", got["name"], got["ward"])
+	}
+
+	if _, err := env.eraser.Erase(context.Background(), "urn:patient:missing"); err == nil {
+		t.Error("erasing an unknown resource succeeded")
+	}
+}
+
+func TestPII_RejectsNonBooleanAnnotation(t *testing.T) {
+	env := setupTestEnv(t)
+	_, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Lead", Slug: "lead",
+		Schema: json.RawMessage(`{"type":"object","properties":{"email":{"type":"string","x-pii":"yes"}}}`),
+	})
+	if err == nil {
+		t.Fatal("x-pii \"yes\" was accepted")
+	}
+}
@@ -0,0 +1,147 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+// seedProjectionFixture creates a project with two tasks, moves one of them to
+// a second project, and deletes a third task, so the read models hold
+// creates, updates, triples, and a delete.
+func seedProjectionFixture(t *testing.T, env *testEnv) (kept, moved string) {
+	t.Helper()
+	project := env.seedProjectForUser(t, "Replay", "admin@weos.dev")
+	other := env.seedProjectForUser(t, "Elsewhere", "admin@weos.dev")
+	kept = env.seedTaskForUser(t, "Kept", project, "admin@weos.dev")
+	moved = env.seedTaskForUser(t, "Moved", project, "admin@weos.dev")
+	deleted := env.seedTaskForUser(t, "Deleted", project, "admin@weos.dev")
+
+	body := fmt.Sprintf(`{"name":"Moved","status":"open","project":%q}`, other)
+	resp := env.doRequest(t, "PUT", "/api/task/"+moved, body, "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update task: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	resp = env.doRequest(t, "DELETE", "/api/task/"+deleted, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete task: expected 204, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+	return kept, moved
+}
+
+func (env *testEnv) verify(t *testing.T, repair bool) (application.ProjectionProgress, []application.ProjectionDrift) {
+	t.Helper()
+	var drifts []application.ProjectionDrift
+	p, err := env.projections.Verify(context.Background(), application.VerifyOptions{
+		Repair:  repair,
+		OnDrift: func(d application.ProjectionDrift) { drifts = append(drifts, d) },
+	})
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	return p, drifts
+}
+
+func (env *testEnv) taskName(t *testing.T, id string) string {
+	t.Helper()
+	resp := env.doRequest(t, "GET", "/api/task/"+id, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get task %s: expected 200, got %d", id, resp.StatusCode)
+	}
+	name, _ := readEnvelopeData(t, resp)["name"].(string)
+	return name
+}
+
+func TestProjections_VerifyReportsAndRepairsDrift(t *testing.T) {
+	env := setupTestEnv(t)
+	kept, moved := seedProjectionFixture(t, env)
+
+	if p, drifts := env.verify(t, false); len(drifts) != 0 || p.Handled < 5 {
+		t.Fatalf("untouched read models: verified %d, drift %v; want none", p.Handled, drifts)
+	}
+
+	for _, stmt := range []string{
+		`UPDATE resources SET data = '{}' WHERE id = ?`,
+		`DELETE FROM triples WHERE subject = ?`,
+	} {
+		if err := env.db.Exec(stmt, kept).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := env.db.Exec(`DELETE FROM tasks WHERE id = ?`, moved).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	_, drifts := env.verify(t, false)
+	found := map[string]application.ProjectionDrift{}
+	for _, d := range drifts {
+		found[d.ResourceID] = d
+	}
+	if len(found) != 2 || len(found[kept].Problems) != 2 || len(found[moved].Problems) != 1 {
+		t.Fatalf("drift = %+v, want %s (data, triples) and %s (projection row)", drifts, kept, moved)
+	}
+	if found[kept].Repaired {
+		t.Error("drift was repaired without Repair")
+	}
+
+	if _, drifts := env.verify(t, true); len(drifts) != 2 || !drifts[0].Repaired || !drifts[1].Repaired {
+		t.Fatalf("repair run drift = %+v, want both repaired", drifts)
+	}
+	if _, drifts := env.verify(t, false); len(drifts) != 0 {
+		t.Fatalf("after repair, drift = %+v", drifts)
+	}
+	if name := env.taskName(t, kept); name != "Kept" {
+		t.Errorf("repaired task name = %q, want Kept", name)
+	}
+}
+
+func TestProjections_RebuildReplaysTruncatedReadModels(t *testing.T) {
+	env := setupTestEnv(t)
+	kept, moved := seedProjectionFixture(t, env)
+
+	// Wipe the read models behind the application's back.
+	for _, table := range []string{"resources", "tasks", "triples"} {
+		if err := env.db.Exec("DELETE FROM " + table).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Interrupt the first run after its first batch; the second resumes it.
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := env.projections.Rebuild(ctx, application.RebuildOptions{
+		Resources: true, Triples: true, BatchSize: 3,
+		Progress: func(application.ProjectionProgress) { cancel() },
+	})
+	if err == nil {
+		t.Fatal("expected the interrupted rebuild to fail")
+	}
+	results, err := env.projections.Rebuild(context.Background(), application.RebuildOptions{
+		Resources: true, Triples: true, BatchSize: 3,
+	})
+	if err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	if len(results) != 2 || !results[0].Resumed || results[1].Resumed {
+		t.Errorf("results = %+v, want resources resumed and triples from the start", results)
+	}
+	for _, p := range results {
+		if p.Failed != 0 {
+			t.Errorf("%s: %d events failed to replay", p.Task, p.Failed)
+		}
+	}
+
+	if _, drifts := env.verify(t, false); len(drifts) != 0 {
+		t.Fatalf("after rebuild, drift = %+v", drifts)
+	}
+	if name := env.taskName(t, kept); name != "Kept" {
+		t.Errorf("rebuilt task name = %q, want Kept", name)
+	}
+	if name := env.taskName(t, moved); name != "Moved" {
+		t.Errorf("rebuilt task name = %q, want Moved", name)
+	}
+}
@@ -0,0 +1,265 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+func TestCreateTask_UnknownProjectRejected(t *testing.T) {
+	env := setupTestEnv(t)
+
+	body := `{"name":"Orphan","status":"open","priority":"low","project":"urn:project:doesnotexist"}`
+	resp := env.doRequest(t, "POST", "/api/task", body, "admin@weos.dev")
+	if resp.StatusCode != http.StatusBadRequest {
+		result := readJSON(t, resp)
+		t.Fatalf("expected 400 for a dangling project reference, got %d: %v", resp.StatusCode, result)
+	}
+	resp.Body.Close()
+}
+
+func TestCreateTask_WrongTypeReferenceRejected(t *testing.T) {
+	env := setupTestEnv(t)
+
+	projectID := env.seedProjectForUser(t, "Real Project", "admin@weos.dev")
+	otherTaskID := env.seedTaskForUser(t, "Some Task", projectID, "admin@weos.dev")
+
+	body := fmt.Sprintf(`{"name":"Bad Ref","status":"open","priority":"low","project":%q}`, otherTaskID)
+	resp := env.doRequest(t, "POST", "/api/task", body, "admin@weos.dev")
+	if resp.StatusCode != http.StatusBadRequest {
+		result := readJSON(t, resp)
+		t.Fatalf("expected 400 when project points at a task, got %d: %v", resp.StatusCode, result)
+	}
+	resp.Body.Close()
+}
+
+func TestCreateTask_InvisibleProjectRejected(t *testing.T) {
+	env := setupTestEnv(t)
+
+	// The admin's project is private to the admin; the member must not be
+	// able to reference it (or learn that it exists).
+	projectID := env.seedProjectForUser(t, "Admin Only", "admin@weos.dev")
+
+	body := fmt.Sprintf(`{"name":"Sneaky","status":"open","priority":"low","project":%q}`, projectID)
+	resp := env.doRequest(t, "POST", "/api/task", body, "member@weos.dev")
+	if resp.StatusCode != http.StatusBadRequest {
+		result := readJSON(t, resp)
+		t.Fatalf("expected 400 for a project the member can't read, got %d: %v", resp.StatusCode, result)
+	}
+	resp.Body.Close()
+}
+
+// installShelfAndBook creates a "shelf" type and a "book" type whose shelf
+// reference uses the given x-on-delete policy.
+func installShelfAndBook(t *testing.T, env *testEnv, policy string) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := env.typeService.Create(ctx, application.CreateResourceTypeCommand{
+		Name: "Shelf", Slug: "shelf",
+		Schema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`),
+	}); err != nil {
+		t.Fatalf("failed to create shelf type: %v", err)
+	}
+	schema := fmt.Sprintf(`{"type":"object","properties":{
+		"name":{"type":"string"},
+		"shelf":{"type":"string","x-resource-type":"shelf","x-on-delete":%q}
+	}}`, policy)
+	if _, err := env.typeService.Create(ctx, application.CreateResourceTypeCommand{
+		Name: "Book", Slug: "book", Schema: json.RawMessage(schema),
+	}); err != nil {
+		t.Fatalf("failed to create book type: %v", err)
+	}
+}
+
+func (env *testEnv) createFor(t *testing.T, typeSlug, body, email string) string {
+	t.Helper()
+	resp := env.doRequest(t, "POST", "/api/"+typeSlug, body, email)
+	if resp.StatusCode != http.StatusCreated {
+		result := readJSON(t, resp)
+		t.Fatalf("create %s: expected 201, got %d: %v", typeSlug, resp.StatusCode, result)
+	}
+	id, _ := readEnvelopeData(t, resp)["id"].(string)
+	return id
+}
+
+func TestDelete_OnDeleteRestrict(t *testing.T) {
+	env := setupTestEnv(t)
+	installShelfAndBook(t, env, "restrict")
+
+	shelfID := env.createFor(t, "shelf", `{"name":"Fiction"}`, "admin@weos.dev")
+	env.createFor(t, "book", fmt.Sprintf(`{"name":"Dune","shelf":%q}`, shelfID), "admin@weos.dev")
+
+	resp := env.doRequest(t, "DELETE", "/api/shelf/"+shelfID, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusConflict {
+		result := readJSON(t, resp)
+		t.Fatalf("expected 409 while a book references the shelf, got %d: %v", resp.StatusCode, result)
+	}
+	resp.Body.Close()
+
+	getResp := env.doRequest(t, "GET", "/api/shelf/"+shelfID, "", "admin@weos.dev")
+	if getResp.StatusCode != http.StatusOK {
+		t.Fatalf("restricted shelf should still exist, got %d", getResp.StatusCode)
+	}
+	getResp.Body.Close()
+}
+
+func TestDelete_OnDeleteCascade(t *testing.T) {
+	env := setupTestEnv(t)
+	installShelfAndBook(t, env, "cascade")
+
+	shelfID := env.createFor(t, "shelf", `{"name":"Fiction"}`, "admin@weos.dev")
+	bookID := env.createFor(t, "book", fmt.Sprintf(`{"name":"Dune","shelf":%q}`, shelfID), "admin@weos.dev")
+
+	resp := env.doRequest(t, "DELETE", "/api/shelf/"+shelfID, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusNoContent {
+		result := readJSON(t, resp)
+		t.Fatalf("delete shelf: expected 204, got %d: %v", resp.StatusCode, result)
+	}
+	resp.Body.Close()
+
+	getResp := env.doRequest(t, "GET", "/api/book/"+bookID, "", "admin@weos.dev")
+	if getResp.StatusCode != http.StatusNotFound {
+		t.Fatalf("book should have been cascade-deleted, got %d", getResp.StatusCode)
+	}
+	getResp.Body.Close()
+}
+
+func TestDelete_OnDeleteSetNull(t *testing.T) {
+	env := setupTestEnv(t)
+	installShelfAndBook(t, env, "set-null")
+
+	shelfID := env.createFor(t, "shelf", `{"name":"Fiction"}`, "admin@weos.dev")
+	bookID := env.createFor(t, "book", fmt.Sprintf(`{"name":"Dune","shelf":%q}`, shelfID), "admin@weos.dev")
+
+	resp := env.doRequest(t, "DELETE", "/api/shelf/"+shelfID, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusNoContent {
+		result := readJSON(t, resp)
+		t.Fatalf("delete shelf: expected 204, got %d: %v", resp.StatusCode, result)
+	}
+	resp.Body.Close()
+
+	getResp := env.doRequest(t, "GET", "/api/book/"+bookID, "", "admin@weos.dev")
+	if getResp.StatusCode != http.StatusOK {
+		t.Fatalf("book should survive set-null, got %d", getResp.StatusCode)
+	}
+	book := readEnvelopeData(t, getResp)
+	if shelf, ok := book["shelf"]; ok && shelf != nil && shelf != "" {
+		t.Errorf("book.shelf = %v, want cleared", shelf)
+	}
+	if display, ok := book["shelfDisplay"]; ok && display != nil && display != "" {
+		t.Errorf("book.shelfDisplay = %v, want cleared", display)
+	}
+}
+
+func TestDelete_OnDeleteCascadeBlockedChangesNothing(t *testing.T) {
+	env := setupTestEnv(t)
+	installShelfAndBook(t, env, "cascade")
+	if _, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Review", Slug: "review",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},
+			"book":{"type":"string","x-resource-type":"book","x-on-delete":"restrict"}
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create review type: %v", err)
+	}
+
+	shelfID := env.createFor(t, "shelf", `{"name":"Fiction"}`, "admin@weos.dev")
+	var bookIDs []string
+	for _, name := range []string{"Dune", "Emma"} {
+		bookIDs = append(bookIDs,
+			env.createFor(t, "book", fmt.Sprintf(`{"name":%q,"shelf":%q}`, name, shelfID), "admin@weos.dev"))
+	}
+	reviewID := env.createFor(t, "review", fmt.Sprintf(`{"name":"Great","book":%q}`, bookIDs[1]), "admin@weos.dev")
+
+	// The review blocks cascading to the second book, so neither book goes.
+	if code := env.status(t, "DELETE", "/api/shelf/"+shelfID, "", "admin@weos.dev"); code != http.StatusConflict {
+		t.Fatalf("delete shelf: expected 409 while a review blocks the cascade, got %d", code)
+	}
+	paths := []string{"/api/shelf/" + shelfID, "/api/book/" + bookIDs[0], "/api/book/" + bookIDs[1]}
+	for _, path := range paths {
+		if code := env.status(t, "GET", path, "", "admin@weos.dev"); code != http.StatusOK {
+			t.Errorf("GET %s after the blocked delete: got %d, want 200", path, code)
+		}
+	}
+
+	// Once unblocked, the cascaded deletes are the caller's in the audit log.
+	if code := env.status(t, "DELETE", "/api/review/"+reviewID, "", "admin@weos.dev"); code != http.StatusNoContent {
+		t.Fatalf("delete review: expected 204, got %d", code)
+	}
+	if code := env.status(t, "DELETE", "/api/shelf/"+shelfID, "", "admin@weos.dev"); code != http.StatusNoContent {
+		t.Fatalf("delete shelf: expected 204, got %d", code)
+	}
+	env.runJobs(t)
+	for _, id := range bookIDs {
+		entries := env.auditEntries(t, url.Values{"resource": {id}}, "admin@weos.dev")
+		if !slices.ContainsFunc(entries, func(e map[string]any) bool {
+			return e["event_type"] == "Resource.Deleted" && e["agent_id"] == env.adminAgentID
+		}) {
+			t.Errorf("audit log of cascaded %s = %v, want its deletion by the admin", id, entries)
+		}
+	}
+}
+
+func TestCreateResourceType_InvalidOnDeleteRejected(t *testing.T) {
+	env := setupTestEnv(t)
+
+	_, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Book", Slug: "book",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"shelf":{"type":"string","x-resource-type":"shelf","x-on-delete":"explode"}
+		}}`),
+	})
+	if err == nil {
+		t.Fatal("expected an unknown x-on-delete policy to be rejected")
+	}
+}
+
+func TestList_MultiValuedReferenceFilterAndDisplay(t *testing.T) {
+	env := setupTestEnv(t)
+	ctx := context.Background()
+	if _, err := env.typeService.Create(ctx, application.CreateResourceTypeCommand{
+		Name: "Ingredient", Slug: "ingredient",
+		Schema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`),
+	}); err != nil {
+		t.Fatalf("failed to create ingredient type: %v", err)
+	}
+	if _, err := env.typeService.Create(ctx, application.CreateResourceTypeCommand{
+		Name: "Dish", Slug: "dish",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},
+			"ingredients":{"type":"array","items":{"type":"string"},"x-resource-type":"ingredient"}
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create dish type: %v", err)
+	}
+
+	egg := env.createFor(t, "ingredient", `{"name":"Egg"}`, "admin@weos.dev")
+	rice := env.createFor(t, "ingredient", `{"name":"Rice"}`, "admin@weos.dev")
+	env.createFor(t, "dish", fmt.Sprintf(`{"name":"Fried Rice","ingredients":[%q,%q]}`, rice, egg), "admin@weos.dev")
+	env.createFor(t, "dish", fmt.Sprintf(`{"name":"Plain Rice","ingredients":[%q]}`, rice), "admin@weos.dev")
+
+	resp := env.doRequest(t, "GET", "/api/dish?_filter[ingredients][contains]="+egg, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list dishes: expected 200, got %d", resp.StatusCode)
+	}
+	rows, _ := readJSON(t, resp)["data"].([]any)
+	if len(rows) != 1 {
+		t.Fatalf("contains egg: expected 1 dish, got %d: %v", len(rows), rows)
+	}
+	dish, _ := rows[0].(map[string]any)
+	if dish["name"] != "Fried Rice" {
+		t.Errorf("contains egg: got %v, want Fried Rice", dish["name"])
+	}
+	got, _ := json.Marshal(dish["ingredients"])
+	want := fmt.Sprintf(`[{"display":"Rice","id":%q},{"display":"Egg","id":%q}]`, rice, egg)
+	if string(got) != want {
+		t.Errorf("ingredients = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/domain/entities"
+)
+
+// roleAccess returns the role-access settings of the user's active account.
+func (env *testEnv) roleAccess(t *testing.T, email string) map[string]any {
+	t.Helper()
+	resp := env.doRequest(t, "GET", "/api/settings/role-access", "", email)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get role access: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	return readEnvelopeData(t, resp)
+}
+
+func TestRoleAccess_PerAccount(t *testing.T) {
+	env := setupTestEnv(t)
+
+	if got := env.roleAccess(t, "admin@weos.dev"); got["default"] != true {
+		t.Fatalf("admin account before saving: default = %v, want true", got["default"])
+	}
+	if code := env.status(t, "PUT", "/api/settings/role-access",
+		`{"roles":{"reviewer":{"project":["read"]}}}`, "admin@weos.dev"); code != http.StatusOK {
+		t.Fatalf("admin save: expected 200, got %d", code)
+	}
+
+	admin := env.roleAccess(t, "admin@weos.dev")
+	if admin["default"] != false || admin["roles"].(map[string]any)["reviewer"] == nil {
+		t.Fatalf("admin account after saving = %v, want its own reviewer rules", admin)
+	}
+	member := env.roleAccess(t, "member@weos.dev")
+	if member["default"] != true || member["roles"].(map[string]any)["reviewer"] != nil {
+		t.Fatalf("member account = %v, want the global default without the admin's rules", member)
+	}
+
+	if code := env.status(t, "PUT", "/api/settings/role-access",
+		`{"roles":{"auditor":{"task":["read"]}}}`, "member@weos.dev"); code != http.StatusOK {
+		t.Fatalf("member save: expected 200, got %d", code)
+	}
+	member = env.roleAccess(t, "member@weos.dev")
+	if roles := member["roles"].(map[string]any); member["default"] != false ||
+		roles["auditor"] == nil || roles["reviewer"] != nil {
+		t.Errorf("member account after saving = %v, want only its own auditor rules", member)
+	}
+	if roles := env.roleAccess(t, "admin@weos.dev")["roles"].(map[string]any); roles["auditor"] != nil {
+		t.Errorf("admin account picked up the member account's rules: %v", roles)
+	}
+
+	// The admin account's rules decide requests in it.
+	env.assignRole(t, "reviewer", "account", "")
+	for _, tc := range []struct {
+		typeSlug string
+		want     bool
+	}{{"project", true}, {"task", false}} {
+		body := fmt.Sprintf(`{"agent_id":%q,"action":"read","type_slug":%q}`, env.memberAgentID, tc.typeSlug)
+		if allowed, _, trace := env.checkAuthz(t, body, "admin@weos.dev"); allowed != tc.want {
+			t.Errorf("reviewer read %s in the admin account: allowed = %v, want %v (%v)", tc.typeSlug, allowed, tc.want, trace)
+		}
+	}
+}
+
+func TestRoleAccess_SuperAdminsEditTheDefault(t *testing.T) {
+	env := setupTestEnv(t)
+	const path = "/api/settings/role-access?scope=default"
+
+	// An account admin edits their own account, not the default.
+	if code := env.status(t, "GET", path, "", "admin@weos.dev"); code != http.StatusForbidden {
+		t.Fatalf("account admin read of the default: expected 403, got %d", code)
+	}
+	if code := env.status(t, "PUT", path, `{"roles":{"reviewer":{"project":["read"]}}}`,
+		"admin@weos.dev"); code != http.StatusForbidden {
+		t.Fatalf("account admin save of the default: expected 403, got %d", code)
+	}
+	if code := env.status(t, "GET", "/api/settings/role-access?scope=other", "", "admin@weos.dev"); code != http.StatusBadRequest {
+		t.Fatalf("unknown scope: expected 400, got %d", code)
+	}
+
+	if err := env.assignments.Save(context.Background(), &entities.RoleAssignment{
+		ID: "ra-super", AgentID: env.memberAgentID, Role: entities.RoleSuperAdmin,
+		Scope: entities.RoleScopeGlobal, GrantedAt: time.Now().UTC(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if code := env.status(t, "PUT", path, `{"roles":{"reviewer":{"project":["read"]}}}`,
+		"member@weos.dev"); code != http.StatusOK {
+		t.Fatalf("super admin save of the default: expected 200, got %d", code)
+	}
+
+	resp := env.doRequest(t, "GET", path, "", "member@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("super admin read of the default: expected 200, got %d", resp.StatusCode)
+	}
+	if got := readEnvelopeData(t, resp); got["default"] != true || got["roles"].(map[string]any)["reviewer"] == nil {
+		t.Fatalf("default = %v, want the saved reviewer rules", got)
+	}
+	// Every account without a configuration of its own uses it.
+	admin := env.roleAccess(t, "admin@weos.dev")
+	if admin["default"] != true || admin["roles"].(map[string]any)["reviewer"] == nil {
+		t.Errorf("admin account = %v, want the edited default", admin)
+	}
+}
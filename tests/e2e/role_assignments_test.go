@@ -0,0 +1,185 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+// assignRole assigns the member user a role in the admin's account and
+// returns the assignment's ID.
+func (env *testEnv) assignRole(t *testing.T, role, scope, extra string) string {
+	t.Helper()
+	body := fmt.Sprintf(`{"agent_id":%q,"role":%q,"scope":%q%s}`, env.memberAgentID, role, scope, extra)
+	resp := env.doRequest(t, "POST", "/api/role-assignments", body, "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("assign %s (%s): expected 201, got %d: %v", role, scope, resp.StatusCode, readJSON(t, resp))
+	}
+	id, _ := readEnvelopeData(t, resp)["id"].(string)
+	return id
+}
+
+func (env *testEnv) status(t *testing.T, method, path, body, email string) int {
+	t.Helper()
+	resp := env.doRequest(t, method, path, body, email)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRoleAssignments_MostSpecificScopeDecides(t *testing.T) {
+	env := setupTestEnv(t)
+	projectID := env.seedProjectForUser(t, "Scoped", "admin@weos.dev")
+	update := `{"name":"Edited","description":"d","status":"active"}`
+
+	if code := env.status(t, "GET", "/api/project/"+projectID, "", "member@weos.dev"); code == http.StatusOK {
+		t.Fatal("SCOPE-03: member read the project without any role assignment")
+	}
+
+	viewerID := env.assignRole(t, "weos:viewer", "type", `,"type_slug":"project"`)
+	if code := env.status(t, "GET", "/api/project/"+projectID, "", "member@weos.dev"); code != http.StatusOK {
+		t.Fatalf("ROLE-05: type-scoped viewer get: expected 200, got %d", code)
+	}
+	if code := env.status(t, "PUT", "/api/project/"+projectID, update, "member@weos.dev"); code != http.StatusForbidden {
+		t.Fatalf("ROLE-05: viewer update: expected 403, got %d", code)
+	}
+
+	env.assignRole(t, "weos:editor", "account", "")
+	if code := env.status(t, "PUT", "/api/project/"+projectID, update, "member@weos.dev"); code != http.StatusForbidden {
+		t.Fatalf("SCOPE-01: the type-scoped viewer should decide over the account-scoped editor, got %d", code)
+	}
+
+	if code := env.status(t, "DELETE", "/api/role-assignments/"+viewerID, "", "admin@weos.dev"); code != http.StatusNoContent {
+		t.Fatalf("revoke: expected 204, got %d", code)
+	}
+	if code := env.status(t, "PUT", "/api/project/"+projectID, update, "member@weos.dev"); code != http.StatusOK {
+		t.Fatalf("ROLE-03: account-scoped editor update: expected 200, got %d", code)
+	}
+}
+
+func TestRoleAssignments_SubtreeCoversInheritingResources(t *testing.T) {
+	env := setupTestEnv(t)
+	projectID := env.seedProjectForUser(t, "Tree", "admin@weos.dev")
+	otherID := env.seedProjectForUser(t, "Elsewhere", "admin@weos.dev")
+	taskID := env.seedTaskForUser(t, "Inside", projectID, "admin@weos.dev")
+	outsideID := env.seedTaskForUser(t, "Outside", otherID, "admin@weos.dev")
+
+	env.assignRole(t, "weos:editor", "subtree", fmt.Sprintf(`,"resource_id":%q`, projectID))
+
+	update := fmt.Sprintf(`{"name":"Edited","status":"open","priority":"high","project":%q}`, projectID)
+	if code := env.status(t, "PUT", "/api/task/"+taskID, update, "member@weos.dev"); code != http.StatusOK {
+		t.Fatalf("ROLE-11: editor on the project's subtree updating its task: expected 200, got %d", code)
+	}
+	if code := env.status(t, "GET", "/api/task/"+outsideID, "", "member@weos.dev"); code == http.StatusOK {
+		t.Error("ROLE-11: the subtree assignment reached a task of another project")
+	}
+}
+
+func TestRoleAssignments_DenyWins(t *testing.T) {
+	env := setupTestEnv(t)
+	projectID := env.seedProjectForUser(t, "Guarded", "admin@weos.dev")
+
+	resp := env.doRequest(t, "PUT", "/api/settings/role-access",
+		`{"roles":{},"inherits":{"reviewer":["weos:editor"]},"deny":{"reviewer":{"project":["modify"]}}}`,
+		"admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("save role access: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+
+	env.assignRole(t, "reviewer", "account", "")
+	env.assignRole(t, "weos:editor", "account", "")
+	if code := env.status(t, "GET", "/api/project/"+projectID, "", "member@weos.dev"); code != http.StatusOK {
+		t.Fatalf("ROLE-07: reviewer inheriting editor get: expected 200, got %d", code)
+	}
+	update := `{"name":"Edited","description":"d","status":"active"}`
+	if code := env.status(t, "PUT", "/api/project/"+projectID, update, "member@weos.dev"); code != http.StatusForbidden {
+		t.Fatalf("SCOPE-02: the reviewer deny should win over the editor grant, got %d", code)
+	}
+}
+
+func TestRoleAssignments_RevokedWithTheirScope(t *testing.T) {
+	env := setupTestEnv(t)
+	installProduct(t, env)
+	projectID := env.seedProjectForUser(t, "Doomed", "admin@weos.dev")
+
+	accountID := env.assignRole(t, "weos:viewer", "account", "")
+	typeID := env.assignRole(t, "weos:editor", "type", `,"type_slug":"product"`)
+	subtreeID := env.assignRole(t, "weos:editor", "subtree", fmt.Sprintf(`,"resource_id":%q`, projectID))
+
+	held := func() map[string]bool {
+		t.Helper()
+		all, err := env.assignments.FindByAgent(context.Background(), env.memberAgentID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := map[string]bool{}
+		for _, a := range all {
+			ids[a.ID] = true
+		}
+		return ids
+	}
+
+	if code := env.status(t, "DELETE", "/api/project/"+projectID, "", "admin@weos.dev"); code >= 300 {
+		t.Fatalf("delete project: status %d", code)
+	}
+	if ids := held(); ids[subtreeID] || !ids[typeID] || !ids[accountID] {
+		t.Fatalf("SCOPE-04: after deleting the subtree root, held = %v, want only the subtree assignment revoked", ids)
+	}
+
+	if err := env.typeService.Delete(context.Background(),
+		application.DeleteResourceTypeCommand{ID: "urn:type:product"}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := held(); ids[typeID] || !ids[accountID] {
+		t.Fatalf("SCOPE-04: after deleting the type, held = %v, want the type assignment revoked", ids)
+	}
+}
+
+func TestRoleAssignments_Validation(t *testing.T) {
+	env := setupTestEnv(t)
+	projectID := env.seedProjectForUser(t, "Mine", "admin@weos.dev")
+
+	cases := []struct {
+		name, body, email string
+		want              int
+	}{
+		{"super admin is global only",
+			`{"agent_id":"a","role":"weos:super-admin","scope":"account"}`, "admin@weos.dev", http.StatusBadRequest},
+		{"site admin is per account only",
+			`{"agent_id":"a","role":"weos:site-admin","scope":"global"}`, "admin@weos.dev", http.StatusBadRequest},
+		{"unknown scope", `{"agent_id":"a","role":"weos:viewer","scope":"planet"}`, "admin@weos.dev", http.StatusBadRequest},
+		{"type scope needs a type", `{"agent_id":"a","role":"weos:viewer","scope":"type"}`, "admin@weos.dev",
+			http.StatusBadRequest},
+		{"subtree root in another account",
+			fmt.Sprintf(`{"agent_id":"a","role":"weos:viewer","scope":"subtree","resource_id":%q}`, projectID),
+			"member@weos.dev", http.StatusBadRequest},
+		{"account admins cannot assign globally",
+			`{"agent_id":"a","role":"weos:viewer","scope":"global"}`, "admin@weos.dev", http.StatusForbidden},
+		{"role names cannot contain #", `{"agent_id":"a","role":"acct#editor","scope":"account"}`, "admin@weos.dev",
+			http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := env.status(t, "POST", "/api/role-assignments", tc.body, tc.email); code != tc.want {
+				t.Errorf("expected %d, got %d", tc.want, code)
+			}
+		})
+	}
+
+	roleAccess := []struct{ name, body string }{
+		{"ROLE-06 built-in roles cannot be changed", `{"roles":{"weos:viewer":{"project":["modify"]}}}`},
+		{"ROLE-08 inheritance cycles are rejected", `{"roles":{},"inherits":{"a":["b"],"b":["a"]}}`},
+		{"role names cannot contain #", `{"roles":{"acct#editor":{"project":["read"]}}}`},
+		{"inherited role names cannot contain #", `{"roles":{},"inherits":{"lead":["acct#editor"]}}`},
+	}
+	for _, tc := range roleAccess {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := env.status(t, "PUT", "/api/settings/role-access", tc.body, "admin@weos.dev"); code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", code)
+			}
+		})
+	}
+}
@@ -10,12 +10,14 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/wepala/weos/v3/api/handlers"
 	apimw "github.com/wepala/weos/v3/api/middleware"
 	"github.com/wepala/weos/v3/application"
 	"github.com/wepala/weos/v3/application/presets"
 	"github.com/wepala/weos/v3/domain/entities"
+	"github.com/wepala/weos/v3/domain/repositories"
 	"github.com/wepala/weos/v3/internal/config"
 
 	authapp "github.com/akeemphilbert/pericarp/pkg/auth/application"
@@ -23,6 +25,7 @@ import (
 	authcasbin "github.com/akeemphilbert/pericarp/pkg/auth/infrastructure/casbin"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
+	"gorm.io/gorm"
 )
 
 // testEnv holds the bootstrapped server and services for E2E tests.
@@ -31,6 +34,15 @@ type testEnv struct {
 	app             *fx.App
 	authService     authapp.AuthenticationService
 	resourceService application.ResourceService
+	typeService     application.ResourceTypeService
+	editorial       application.EditorialService
+	jobs            *application.JobWorker
+	relay           *application.EventRelay
+	projections     *application.ProjectionMaintainer
+	eraser          *application.PersonalDataEraser
+	accounts        authrepos.AccountRepository
+	assignments     repositories.RoleAssignmentRepository
+	db              *gorm.DB
 	adminAgentID    string
 	adminAccountID  string
 	memberAgentID   string
@@ -39,7 +51,8 @@ type testEnv struct {
 
 // setupTestEnv boots the full application with an in-memory SQLite database,
 // seeds test users, installs the tasks preset, and starts an httptest server.
-func setupTestEnv(t *testing.T) *testEnv {
+// Options adjust the config before the application boots.
+func setupTestEnv(t *testing.T, opts ...func(*config.Config)) *testEnv {
 	t.Helper()
 
 	cfg := config.Default()
@@ -48,14 +61,32 @@ func setupTestEnv(t *testing.T) *testEnv {
 	tmpDir := t.TempDir()
 	cfg.DatabaseDSN = filepath.Join(tmpDir, "test.db") + "?_journal_mode=WAL&_busy_timeout=5000"
 	cfg.LogLevel = "error"
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
 	var resourceTypeService application.ResourceTypeService
 	var resourceService application.ResourceService
 	var resourcePermService application.ResourcePermissionService
+	var editorialService application.EditorialService
+	var jobWorker *application.JobWorker
+	var eventRelay *application.EventRelay
+	var projections *application.ProjectionMaintainer
+	var eraser *application.PersonalDataEraser
+	var db *gorm.DB
+	var webhookService application.WebhookService
+	var teamService application.TeamService
+	var roleAssignmentService application.RoleAssignmentService
+	var authzCheckService application.AuthorizationCheckService
+	var eventStreamService application.EventStreamService
 	var authService authapp.AuthenticationService
 	var credentialRepo authrepos.CredentialRepository
 	var agentRepo authrepos.AgentRepository
 	var accountRepo authrepos.AccountRepository
+	var auditRepo repositories.AuditRepository
+	var roleAssignmentRepo repositories.RoleAssignmentRepository
+	var roleAccessRepo repositories.RoleResourceAccessRepository
+	var roleAuthorizer application.RoleAuthorizer
 	var authzChecker *authcasbin.CasbinAuthorizationChecker
 	var logger entities.Logger
 
@@ -65,10 +96,25 @@ func setupTestEnv(t *testing.T) *testEnv {
 		fx.Populate(&resourceTypeService),
 		fx.Populate(&resourceService),
 		fx.Populate(&resourcePermService),
+		fx.Populate(&editorialService),
+		fx.Populate(&jobWorker),
+		fx.Populate(&eventRelay),
+		fx.Populate(&projections),
+		fx.Populate(&eraser),
+		fx.Populate(&db),
+		fx.Populate(&webhookService),
+		fx.Populate(&teamService),
+		fx.Populate(&roleAssignmentService),
+		fx.Populate(&authzCheckService),
+		fx.Populate(&eventStreamService),
 		fx.Populate(&authService),
 		fx.Populate(&credentialRepo),
 		fx.Populate(&agentRepo),
 		fx.Populate(&accountRepo),
+		fx.Populate(&auditRepo),
+		fx.Populate(&roleAssignmentRepo),
+		fx.Populate(&roleAccessRepo),
+		fx.Populate(&roleAuthorizer),
 		fx.Populate(&authzChecker),
 		fx.Populate(&logger),
 	)
@@ -133,21 +179,71 @@ func setupTestEnv(t *testing.T) *testEnv {
 
 	api := e.Group("/api")
 	api.Use(apimw.Messages())
+	api.Use(apimw.RequestID())
 	api.GET("/health", handlers.HealthHandler)
 
 	protected := api.Group("")
 	// OAuth is not enabled in tests, so use SoftAuth
 	protected.Use(apimw.SoftAuth(credentialRepo, agentRepo, accountRepo, logger))
 
-	rtHandler := handlers.NewResourceTypeHandler(resourceTypeService, authzChecker, accountRepo, logger)
+	rtHandler := handlers.NewResourceTypeHandler(resourceTypeService, roleAuthorizer, accountRepo, logger)
 	protected.POST("/resource-types", rtHandler.Create)
 	protected.GET("/resource-types", rtHandler.List)
 	protected.GET("/resource-types/:id", rtHandler.Get)
 
+	webhookHandler := handlers.NewWebhookHandler(handlers.WebhookHandlerConfig{
+		Service:     webhookService,
+		AccountRepo: accountRepo,
+		Logger:      logger,
+	})
+	protected.POST("/webhooks", webhookHandler.Create)
+	protected.GET("/webhooks", webhookHandler.List)
+	protected.GET("/webhooks/:id", webhookHandler.Get)
+	protected.PUT("/webhooks/:id", webhookHandler.Update)
+	protected.DELETE("/webhooks/:id", webhookHandler.Delete)
+	protected.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+	protected.POST("/webhooks/:id/deliveries/:deliveryId/replay", webhookHandler.Replay)
+	teamHandler := handlers.NewTeamHandler(teamService, logger)
+	protected.POST("/teams", teamHandler.Create)
+	protected.GET("/teams", teamHandler.List)
+	protected.GET("/teams/:id", teamHandler.Get)
+	protected.PUT("/teams/:id", teamHandler.Update)
+	protected.DELETE("/teams/:id", teamHandler.Delete)
+	protected.GET("/teams/:id/members", teamHandler.ListMembers)
+	protected.POST("/teams/:id/members", teamHandler.AddMember)
+	protected.DELETE("/teams/:id/members/:agentId", teamHandler.RemoveMember)
+	roleAssignmentHandler := handlers.NewRoleAssignmentHandler(roleAssignmentService, logger)
+	protected.POST("/role-assignments", roleAssignmentHandler.Create)
+	protected.GET("/role-assignments", roleAssignmentHandler.List)
+	protected.DELETE("/role-assignments/:id", roleAssignmentHandler.Delete)
+
+	authzHandler := handlers.NewAuthzHandler(authzCheckService, logger)
+	protected.POST("/authz/check", authzHandler.Check)
+	protected.GET("/admin/event-handlers",
+		handlers.NewEventHandlerStatusHandler(eventRelay, accountRepo, logger).List)
+	protected.GET("/audit", handlers.NewAuditHandler(auditRepo, accountRepo, logger).List)
+	roleAccessHandler := handlers.NewRoleAccessHandler(handlers.RoleAccessHandlerConfig{
+		Repo: roleAccessRepo, Checker: authzChecker, AccountRepo: accountRepo,
+		Assignments: roleAssignmentRepo, Logger: logger,
+	})
+	protected.GET("/settings/role-access", roleAccessHandler.Get)
+	protected.PUT("/settings/role-access", roleAccessHandler.Save)
+	eventStreamHandler := handlers.NewEventStreamHandler(eventStreamService, logger)
+	eventStreamHandler.PollInterval = 20 * time.Millisecond
+	eventStreamHandler.HeartbeatInterval = 100 * time.Millisecond
+	protected.GET("/events/stream", eventStreamHandler.Stream)
+
 	permHandler := handlers.NewResourcePermissionHandler(resourcePermService)
 	protected.POST("/:typeSlug/:id/permissions", permHandler.Grant)
 	protected.GET("/:typeSlug/:id/permissions", permHandler.List)
 	protected.DELETE("/:typeSlug/:id/permissions/:agentId", permHandler.Revoke)
+	protected.DELETE("/:typeSlug/:id/permissions/teams/:teamId", permHandler.RevokeTeam)
+
+	editorialHandler := handlers.NewEditorialHandler(editorialService, resourceTypeService)
+	protected.POST("/:typeSlug/:id/publish", editorialHandler.Publish)
+	protected.POST("/:typeSlug/:id/unpublish", editorialHandler.Unpublish)
+	api.GET("/public/:accountId/:typeSlug", editorialHandler.ListPublished)
+	api.GET("/public/:accountId/:typeSlug/:id", editorialHandler.GetPublished)
 
 	resourceHandler := handlers.NewResourceHandler(resourceService, resourceTypeService, logger)
 	protected.POST("/:typeSlug", resourceHandler.Create)
@@ -155,6 +251,7 @@ func setupTestEnv(t *testing.T) *testEnv {
 	protected.GET("/:typeSlug/:id", resourceHandler.Get)
 	protected.PUT("/:typeSlug/:id", resourceHandler.Update)
 	protected.DELETE("/:typeSlug/:id", resourceHandler.Delete)
+	protected.POST("/:typeSlug/:id/transitions/:name", resourceHandler.Transition)
 
 	server := httptest.NewServer(e)
 	t.Cleanup(server.Close)
@@ -164,6 +261,15 @@ func setupTestEnv(t *testing.T) *testEnv {
 		app:             app,
 		authService:     authService,
 		resourceService: resourceService,
+		typeService:     resourceTypeService,
+		editorial:       editorialService,
+		jobs:            jobWorker,
+		relay:           eventRelay,
+		projections:     projections,
+		eraser:          eraser,
+		accounts:        accountRepo,
+		assignments:     roleAssignmentRepo,
+		db:              db,
 		adminAgentID:    adminAgent.GetID(),
 		adminAccountID:  adminAccountID,
 		memberAgentID:   memberAgent.GetID(),
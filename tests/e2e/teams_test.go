@@ -0,0 +1,154 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// setupTeam makes the member user a plain member of the admin's account and
+// of a new "Reviewers" team there, and returns the team's ID.
+func setupTeam(t *testing.T, env *testEnv) string {
+	t.Helper()
+	ctx := context.Background()
+	for _, accountID := range []string{env.memberAccountID, env.adminAccountID} {
+		if err := env.accounts.SaveMember(ctx, accountID, env.memberAgentID, "member"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp := env.doRequest(t, "POST", "/api/teams", `{"name":"Reviewers"}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create team: expected 201, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	teamID, _ := readEnvelopeData(t, resp)["id"].(string)
+
+	resp = env.doRequest(t, "POST", "/api/teams/"+teamID+"/members",
+		fmt.Sprintf(`{"agent_id":%q}`, env.memberAgentID), "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("add member: expected 201, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+	return teamID
+}
+
+func listedIDs(t *testing.T, resp *http.Response) map[string]bool {
+	t.Helper()
+	items, _ := readJSON(t, resp)["data"].([]any)
+	ids := make(map[string]bool, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			id, _ := m["id"].(string)
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+func TestTeams_GrantReachesMembersUntilRemoved(t *testing.T) {
+	env := setupTestEnv(t)
+	teamID := setupTeam(t, env)
+	projectID := env.seedProjectForUser(t, "Shared Project", "admin@weos.dev")
+
+	resp := env.doRequest(t, "GET", "/api/project/"+projectID, "", "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Fatal("member read the project before it was shared")
+	}
+
+	grant := fmt.Sprintf(`{"team_id":%q,"actions":["read"]}`, teamID)
+	resp = env.doRequest(t, "POST", "/api/project/"+projectID+"/permissions", grant, "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("grant: expected 201, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	resp.Body.Close()
+
+	resp = env.doRequest(t, "GET", "/api/project/"+projectID, "", "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("team member get: expected 200, got %d", resp.StatusCode)
+	}
+	if !listedIDs(t, env.doRequest(t, "GET", "/api/project", "", "member@weos.dev"))[projectID] {
+		t.Error("team member list does not include the shared project")
+	}
+
+	resp = env.doRequest(t, "GET", "/api/project/"+projectID+"/permissions", "", "admin@weos.dev")
+	body := readJSON(t, resp)
+	perms, _ := body["data"].([]any)
+	if len(perms) != 1 || perms[0].(map[string]any)["team_id"] != teamID {
+		t.Errorf("permissions = %v, want the team grant", body)
+	}
+
+	resp = env.doRequest(t, "DELETE", "/api/teams/"+teamID+"/members/"+env.memberAgentID, "", "admin@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("remove member: expected 204, got %d", resp.StatusCode)
+	}
+
+	resp = env.doRequest(t, "GET", "/api/project/"+projectID, "", "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Error("removed member can still read the project")
+	}
+	if listedIDs(t, env.doRequest(t, "GET", "/api/project", "", "member@weos.dev"))[projectID] {
+		t.Error("removed member still lists the project")
+	}
+}
+
+func TestTeams_RevokeTeamGrant(t *testing.T) {
+	env := setupTestEnv(t)
+	teamID := setupTeam(t, env)
+	projectID := env.seedProjectForUser(t, "Shared Project", "admin@weos.dev")
+
+	grant := fmt.Sprintf(`{"team_id":%q,"actions":["read"]}`, teamID)
+	resp := env.doRequest(t, "POST", "/api/project/"+projectID+"/permissions", grant, "admin@weos.dev")
+	resp.Body.Close()
+	resp = env.doRequest(t, "DELETE", "/api/project/"+projectID+"/permissions/teams/"+teamID, "",
+		"admin@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("revoke: expected 204, got %d", resp.StatusCode)
+	}
+
+	resp = env.doRequest(t, "GET", "/api/project/"+projectID, "", "member@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		t.Error("member can still read the project after the team grant was revoked")
+	}
+}
+
+func TestTeams_Validation(t *testing.T) {
+	env := setupTestEnv(t)
+	teamID := setupTeam(t, env)
+	projectID := env.seedProjectForUser(t, "Project", "admin@weos.dev")
+
+	tests := []struct {
+		name, method, path, body, user string
+		want                           int
+	}{
+		{"member cannot create teams", "POST", "/api/teams", `{"name":"Mine"}`, "member@weos.dev",
+			http.StatusForbidden},
+		{"name is required", "POST", "/api/teams", `{"name":" "}`, "admin@weos.dev", http.StatusBadRequest},
+		{"outsiders cannot join", "POST", "/api/teams/" + teamID + "/members", `{"agent_id":"urn:agent:nobody"}`,
+			"admin@weos.dev", http.StatusBadRequest},
+		{"grant needs one grantee", "POST", "/api/project/" + projectID + "/permissions",
+			fmt.Sprintf(`{"agent_id":%q,"team_id":%q,"actions":["read"]}`, env.memberAgentID, teamID),
+			"admin@weos.dev", http.StatusBadRequest},
+		{"grant needs a known team", "POST", "/api/project/" + projectID + "/permissions",
+			`{"team_id":"urn:team:missing","actions":["read"]}`, "admin@weos.dev", http.StatusBadRequest},
+		{"another account's team is hidden", "GET", "/api/teams/" + teamID + "/members", "",
+			"member@weos.dev", http.StatusNotFound},
+		{"admins list members", "GET", "/api/teams/" + teamID + "/members", "", "admin@weos.dev",
+			http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := env.doRequest(t, tt.method, tt.path, tt.body, tt.user)
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
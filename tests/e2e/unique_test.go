@@ -0,0 +1,139 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+// installProduct creates a "product" type whose sku is unique.
+func installProduct(t *testing.T, env *testEnv) {
+	t.Helper()
+	if _, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Product", Slug: "product",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"name":{"type":"string"},
+			"sku":{"type":"string","x-unique":true}
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create product type: %v", err)
+	}
+}
+
+func TestUnique_ConcurrentCreatesCommitOnce(t *testing.T) {
+	env := setupTestEnv(t)
+	installProduct(t, env)
+
+	const writers = 8
+	codes := make([]int, writers)
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("POST", env.server.URL+"/api/product",
+				strings.NewReader(`{"name":"Widget","sku":"ABC-1"}`))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Dev-Agent", "admin@weos.dev")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			resp.Body.Close()
+			codes[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for i, code := range codes {
+		if errs[i] != nil {
+			t.Fatalf("request %d failed: %v", i, errs[i])
+		}
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+		default:
+			t.Errorf("request %d: status %d, want 201 or 409", i, code)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("created %d products with the same sku, want 1", created)
+	}
+	var stored int64
+	if err := env.db.Table("resources").
+		Where("type_slug = ? AND deleted_at IS NULL", "product").Count(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored != 1 {
+		t.Errorf("stored %d products, want 1", stored)
+	}
+}
+
+func TestUnique_HoldsWhileTheProjectionLags(t *testing.T) {
+	env := setupTestEnv(t)
+	installProduct(t, env)
+
+	first := env.createFor(t, "product", `{"name":"Widget","sku":"ABC-1"}`, "admin@weos.dev")
+	// Drop the projected row, as if the projection had not caught up with
+	// the first write yet.
+	if err := env.db.Exec("DELETE FROM products WHERE id = ?", first).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	resp := env.doRequest(t, "POST", "/api/product", `{"name":"Gadget","sku":"ABC-1"}`, "admin@weos.dev")
+	result := readJSON(t, resp)
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("create with a taken sku: status %d, want 409: %v", resp.StatusCode, result)
+	}
+	if msg, _ := result["error"].(string); msg != `sku "ABC-1" is already in use: conflict` {
+		t.Errorf("error = %q, want the sku named", msg)
+	}
+}
+
+func TestUnique_DeleteAndUpdateReleaseValues(t *testing.T) {
+	env := setupTestEnv(t)
+	installProduct(t, env)
+
+	first := env.createFor(t, "product", `{"name":"Widget","sku":"ABC-1"}`, "admin@weos.dev")
+	resp := env.doRequest(t, "DELETE", "/api/product/"+first, "", "admin@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		t.Fatalf("delete: status %d", resp.StatusCode)
+	}
+
+	// The deleted resource gave its sku up.
+	second := env.createFor(t, "product", `{"name":"Widget","sku":"ABC-1"}`, "admin@weos.dev")
+
+	// Saving a resource with its own value is not a conflict, and moving it
+	// to a new value frees the old one.
+	resp = env.doRequest(t, "PUT", "/api/product/"+second, `{"name":"Widget","sku":"ABC-1"}`, "admin@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update with its own sku: status %d, want 200", resp.StatusCode)
+	}
+	resp = env.doRequest(t, "PUT", "/api/product/"+second, `{"name":"Widget","sku":"ABC-2"}`, "admin@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update to a new sku: status %d, want 200", resp.StatusCode)
+	}
+	env.createFor(t, "product", `{"name":"Gadget","sku":"ABC-1"}`, "admin@weos.dev")
+
+	resp = env.doRequest(t, "POST", "/api/product", `{"name":"Gizmo","sku":"ABC-2"}`, "admin@weos.dev")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("create with a taken sku: status %d, want 409", resp.StatusCode)
+	}
+}
@@ -0,0 +1,180 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+// webhookReceiver records the deliveries POSTed to it and answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+	r := &webhookReceiver{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, receivedWebhook{header: req.Header.Clone(), body: body})
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+// runJobs relays committed events to the async event handlers, then runs
+// every due background job to completion.
+func (env *testEnv) runJobs(t *testing.T) {
+	t.Helper()
+	if _, err := env.relay.Tick(context.Background(), time.Now()); err != nil {
+		t.Fatalf("event relay tick: %v", err)
+	}
+	if _, err := env.jobs.Tick(context.Background(), time.Now()); err != nil {
+		t.Fatalf("job worker tick: %v", err)
+	}
+	env.jobs.Wait()
+}
+
+func TestWebhooks_DeliverSignedEventsForTheAccount(t *testing.T) {
+	env := setupTestEnv(t)
+	receiver := newWebhookReceiver(t)
+
+	resp := env.doRequest(t, "POST", "/api/webhooks",
+		`{"url":"`+receiver.URL+`","events":["Resource.Created"],"type_slugs":["task"]}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create webhook: expected 201, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	hook := readEnvelopeData(t, resp)
+	hookID, _ := hook["id"].(string)
+	secret, _ := hook["secret"].(string)
+	if secret == "" {
+		t.Fatal("create webhook: response has no secret")
+	}
+
+	projectID := env.seedProjectForUser(t, "Launch", "admin@weos.dev")
+	taskID := env.seedTaskForUser(t, "Write copy", projectID, "admin@weos.dev")
+	// The member's task is in another account.
+	memberProject := env.seedProjectForUser(t, "Other", "member@weos.dev")
+	env.seedTaskForUser(t, "Not yours", memberProject, "member@weos.dev")
+
+	env.runJobs(t)
+	got := receiver.received()
+	if len(got) != 1 {
+		t.Fatalf("receiver got %d deliveries, want 1 (the admin's task)", len(got))
+	}
+	var body map[string]any
+	if err := json.Unmarshal(got[0].body, &body); err != nil {
+		t.Fatalf("delivery body is not JSON: %v", err)
+	}
+	if body["event"] != "Resource.Created" || body["resourceId"] != taskID || body["typeSlug"] != "task" {
+		t.Errorf("delivery body = %v", body)
+	}
+	ts, _ := strconv.ParseInt(got[0].header.Get(application.WebhookTimestampHeader), 10, 64)
+	if sig := got[0].header.Get(application.WebhookSignatureHeader); sig != application.SignWebhook(secret, ts, got[0].body) {
+		t.Errorf("signature %q does not verify", sig)
+	}
+
+	resp = env.doRequest(t, "GET", "/api/webhooks/"+hookID+"/deliveries", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list deliveries: expected 200, got %d", resp.StatusCode)
+	}
+	deliveries, _ := readJSON(t, resp)["data"].([]any)
+	if len(deliveries) != 1 {
+		t.Fatalf("delivery log has %d entries, want 1", len(deliveries))
+	}
+	first, _ := deliveries[0].(map[string]any)
+	if first["status"] != "succeeded" {
+		t.Errorf("delivery status = %v, want succeeded", first["status"])
+	}
+
+	// The member can't see the admin's webhook.
+	resp = env.doRequest(t, "GET", "/api/webhooks/"+hookID, "", "member@weos.dev")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("another account's webhook: expected 404, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+}
+
+func TestWebhooks_RetryAndReplay(t *testing.T) {
+	env := setupTestEnv(t)
+	receiver := newWebhookReceiver(t)
+	receiver.status = http.StatusServiceUnavailable
+
+	resp := env.doRequest(t, "POST", "/api/webhooks",
+		`{"url":"`+receiver.URL+`","events":["Resource.*"],"type_slugs":["project"]}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create webhook: expected 201, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	hookID, _ := readEnvelopeData(t, resp)["id"].(string)
+	env.seedProjectForUser(t, "Launch", "admin@weos.dev")
+
+	env.runJobs(t)
+	resp = env.doRequest(t, "GET", "/api/webhooks/"+hookID+"/deliveries", "", "admin@weos.dev")
+	deliveries, _ := readJSON(t, resp)["data"].([]any)
+	if len(deliveries) != 1 {
+		t.Fatalf("delivery log has %d entries, want 1", len(deliveries))
+	}
+	failed, _ := deliveries[0].(map[string]any)
+	if failed["status"] != "failed" || failed["response_status"] != float64(http.StatusServiceUnavailable) {
+		t.Fatalf("delivery to a failing receiver = %v, want failed with 503", failed)
+	}
+
+	receiver.mu.Lock()
+	receiver.status = http.StatusOK
+	receiver.mu.Unlock()
+	deliveryID, _ := failed["id"].(string)
+	resp = env.doRequest(t, "POST", "/api/webhooks/"+hookID+"/deliveries/"+deliveryID+"/replay", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("replay: expected 202, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	replay := readEnvelopeData(t, resp)
+	if replay["replay_of"] != deliveryID {
+		t.Errorf("replay_of = %v, want %s", replay["replay_of"], deliveryID)
+	}
+
+	env.runJobs(t)
+	got := receiver.received()
+	if len(got) != 2 || string(got[0].body) != string(got[1].body) {
+		t.Fatalf("receiver got %d deliveries; a replay must resend the original body", len(got))
+	}
+}
+
+func TestWebhooks_RejectInvalidSubscription(t *testing.T) {
+	env := setupTestEnv(t)
+	for _, body := range []string{
+		`{"url":"not a url","events":["*"]}`,
+		`{"url":"https://example.com","events":[]}`,
+		`{"url":"https://example.com","events":["Triple.Created"]}`,
+	} {
+		resp := env.doRequest(t, "POST", "/api/webhooks", body, "admin@weos.dev")
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("POST %s: expected 400, got %d", body, resp.StatusCode)
+		}
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,127 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/wepala/weos/v3/application"
+)
+
+func installTicketWorkflow(t *testing.T, env *testEnv) {
+	t.Helper()
+	if _, err := env.typeService.Create(context.Background(), application.CreateResourceTypeCommand{
+		Name: "Ticket", Slug: "ticket",
+		Schema: json.RawMessage(`{"type":"object","properties":{
+			"title":{"type":"string"},
+			"assignee":{"type":"string"},
+			"status":{"type":"string"}
+		},"x-workflow":{
+			"initial":"open",
+			"states":["open","in-progress","done"],
+			"transitions":[
+				{"name":"start","from":["open"],"to":"in-progress","guard":"assignee != ''"},
+				{"name":"finish","from":["in-progress"],"to":"done","roles":["reviewer"]}
+			]
+		}}`),
+	}); err != nil {
+		t.Fatalf("failed to create ticket type: %v", err)
+	}
+}
+
+// workflowState reads the status property from a ResourceResponse envelope.
+func workflowState(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	data, _ := readEnvelopeData(t, resp)["data"].(map[string]any)
+	graph, _ := data["@graph"].([]any)
+	if len(graph) == 0 {
+		t.Fatalf("response has no @graph: %v", data)
+	}
+	node, _ := graph[0].(map[string]any)
+	state, _ := node["status"].(string)
+	return state
+}
+
+func TestWorkflow_TransitionsEnforced(t *testing.T) {
+	env := setupTestEnv(t)
+	installTicketWorkflow(t, env)
+	id := env.createFor(t, "ticket", `{"title":"Fix login"}`, "admin@weos.dev")
+
+	resp := env.doRequest(t, "GET", "/api/ticket/"+id, "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("get ticket: expected 200, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	// Skipping straight to done is not a declared transition.
+	resp = env.doRequest(t, "PUT", "/api/ticket/"+id, `{"title":"Fix login","status":"done"}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("illegal transition: expected 400, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	// The start guard needs an assignee.
+	resp = env.doRequest(t, "POST", "/api/ticket/"+id+"/transitions/start", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("failed guard: expected 400, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	resp = env.doRequest(t, "PUT", "/api/ticket/"+id, `{"title":"Fix login","assignee":"ada"}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("assign: expected 200, got %d", resp.StatusCode)
+	}
+	if got := workflowState(t, resp); got != "open" {
+		t.Errorf("after an edit without status: state = %q, want open", got)
+	}
+
+	resp = env.doRequest(t, "POST", "/api/ticket/"+id+"/transitions/start", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("start: expected 200, got %d: %v", resp.StatusCode, readJSON(t, resp))
+	}
+	if got := workflowState(t, resp); got != "in-progress" {
+		t.Errorf("after start: state = %q, want in-progress", got)
+	}
+
+	// finish is reserved for reviewers.
+	resp = env.doRequest(t, "POST", "/api/ticket/"+id+"/transitions/finish", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("finish without role: expected 403, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	resp = env.doRequest(t, "POST", "/api/ticket/"+id+"/transitions/publish", "", "admin@weos.dev")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown transition: expected 404, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	// The system context lists finish: roles only apply to callers.
+	transitions, err := env.resourceService.AvailableTransitions(context.Background(), id)
+	if err != nil {
+		t.Fatalf("AvailableTransitions: %v", err)
+	}
+	if len(transitions) != 1 || transitions[0].Name != "finish" || transitions[0].From != "in-progress" {
+		t.Errorf("available transitions = %+v, want [finish from in-progress]", transitions)
+	}
+}
+
+func TestWorkflow_CreateStartsInInitialState(t *testing.T) {
+	env := setupTestEnv(t)
+	installTicketWorkflow(t, env)
+
+	resp := env.doRequest(t, "POST", "/api/ticket", `{"title":"Skip ahead","status":"done"}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("create in done: expected 400, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	resp = env.doRequest(t, "POST", "/api/ticket", `{"title":"Fresh"}`, "admin@weos.dev")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d", resp.StatusCode)
+	}
+	if got := workflowState(t, resp); got != "open" {
+		t.Errorf("new ticket: state = %q, want open", got)
+	}
+}